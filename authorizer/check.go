@@ -0,0 +1,127 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.CheckService = (*CheckService)(nil)
+
+// CheckService wraps a influxdb.CheckService and authorizes actions
+// against it appropriately.
+type CheckService struct {
+	s influxdb.CheckService
+}
+
+// NewCheckService constructs an instance of an authorizing check service.
+func NewCheckService(s influxdb.CheckService) *CheckService {
+	return &CheckService{
+		s: s,
+	}
+}
+
+// authorizeOrgResource checks that the authorizer on context may perform a
+// on the resource of type t with id, owned by orgID.
+func authorizeOrgResource(ctx context.Context, a influxdb.Action, t influxdb.ResourceType, orgID, id influxdb.ID) error {
+	p, err := influxdb.NewPermissionAtID(id, a, t, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// authorizeCreateOrgResource checks that the authorizer on context has write
+// access to resources of type t in orgID.
+func authorizeCreateOrgResource(ctx context.Context, t influxdb.ResourceType, orgID influxdb.ID) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, t, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindCheckByID checks to see if the authorizer on context has read access to the id provided.
+func (s *CheckService) FindCheckByID(ctx context.Context, id influxdb.ID) (*influxdb.Check, error) {
+	c, err := s.s.FindCheckByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.ReadAction, influxdb.ChecksResourceType, c.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// FindChecks retrieves all checks that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *CheckService) FindChecks(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]*influxdb.Check, int, error) {
+	cs, _, err := s.s.FindChecks(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	checks := cs[:0]
+	for _, c := range cs {
+		err := authorizeOrgResource(ctx, influxdb.ReadAction, influxdb.ChecksResourceType, c.OrganizationID, c.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		checks = append(checks, c)
+	}
+
+	return checks, len(checks), nil
+}
+
+// CreateCheck checks to see if the authorizer on context has write access to the checks of the organization.
+func (s *CheckService) CreateCheck(ctx context.Context, c *influxdb.Check) error {
+	if err := authorizeCreateOrgResource(ctx, influxdb.ChecksResourceType, c.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.CreateCheck(ctx, c)
+}
+
+// UpdateCheck checks to see if the authorizer on context has write access to the check provided.
+func (s *CheckService) UpdateCheck(ctx context.Context, id influxdb.ID, upd influxdb.CheckUpdate) (*influxdb.Check, error) {
+	c, err := s.s.FindCheckByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.WriteAction, influxdb.ChecksResourceType, c.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateCheck(ctx, id, upd)
+}
+
+// DeleteCheck checks to see if the authorizer on context has write access to the check provided.
+func (s *CheckService) DeleteCheck(ctx context.Context, id influxdb.ID) error {
+	c, err := s.s.FindCheckByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.WriteAction, influxdb.ChecksResourceType, c.OrganizationID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteCheck(ctx, id)
+}
@@ -0,0 +1,155 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestCheckService_FindChecks(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err    error
+		checks []*influxdb.Check
+	}
+
+	checks := func() []*influxdb.Check {
+		return []*influxdb.Check{
+			{ID: 1, OrganizationID: 10},
+			{ID: 2, OrganizationID: 10},
+			{ID: 3, OrganizationID: 11},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to see all checks of an org",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.ChecksResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					{ID: 1, OrganizationID: 10},
+					{ID: 2, OrganizationID: 10},
+				},
+			},
+		},
+		{
+			name: "authorized to see a single check",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.ChecksResourceType,
+						ID:   influxdbtesting.IDPtr(3),
+					},
+				},
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					{ID: 3, OrganizationID: 11},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewCheckService()
+			m.FindChecksFn = func(context.Context, influxdb.CheckFilter, ...influxdb.FindOptions) ([]*influxdb.Check, int, error) {
+				cs := checks()
+				return cs, len(cs), nil
+			}
+			s := authorizer.NewCheckService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			cs, _, err := s.FindChecks(ctx, influxdb.CheckFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(cs, tt.wants.checks); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckService_CreateCheck(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+		orgID      influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to create check",
+			args: args{
+				orgID: 10,
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.ChecksResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+		},
+		{
+			name: "unauthorized to create check",
+			args: args{
+				orgID: 10,
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.ChecksResourceType,
+						OrgID: influxdbtesting.IDPtr(11),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/checks is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewCheckService(mock.NewCheckService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			err := s.CreateCheck(ctx, &influxdb.Check{OrganizationID: tt.args.orgID})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
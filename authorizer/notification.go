@@ -0,0 +1,186 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.NotificationRuleService = (*NotificationRuleService)(nil)
+var _ influxdb.NotificationEndpointService = (*NotificationEndpointService)(nil)
+
+// NotificationRuleService wraps a influxdb.NotificationRuleService and authorizes actions
+// against it appropriately.
+type NotificationRuleService struct {
+	s influxdb.NotificationRuleService
+}
+
+// NewNotificationRuleService constructs an instance of an authorizing notification rule service.
+func NewNotificationRuleService(s influxdb.NotificationRuleService) *NotificationRuleService {
+	return &NotificationRuleService{
+		s: s,
+	}
+}
+
+// FindNotificationRuleByID checks to see if the authorizer on context has read access to the id provided.
+func (s *NotificationRuleService) FindNotificationRuleByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationRule, error) {
+	r, err := s.s.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.ReadAction, influxdb.NotificationRulesResourceType, r.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindNotificationRules retrieves all notification rules that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *NotificationRuleService) FindNotificationRules(ctx context.Context, filter influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRule, int, error) {
+	rs, _, err := s.s.FindNotificationRules(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rules := rs[:0]
+	for _, r := range rs {
+		err := authorizeOrgResource(ctx, influxdb.ReadAction, influxdb.NotificationRulesResourceType, r.OrganizationID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, len(rules), nil
+}
+
+// CreateNotificationRule checks to see if the authorizer on context has write access to the notification rules of the organization.
+func (s *NotificationRuleService) CreateNotificationRule(ctx context.Context, r *influxdb.NotificationRule) error {
+	if err := authorizeCreateOrgResource(ctx, influxdb.NotificationRulesResourceType, r.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.CreateNotificationRule(ctx, r)
+}
+
+// UpdateNotificationRule checks to see if the authorizer on context has write access to the notification rule provided.
+func (s *NotificationRuleService) UpdateNotificationRule(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRuleUpdate) (*influxdb.NotificationRule, error) {
+	r, err := s.s.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.WriteAction, influxdb.NotificationRulesResourceType, r.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateNotificationRule(ctx, id, upd)
+}
+
+// DeleteNotificationRule checks to see if the authorizer on context has write access to the notification rule provided.
+func (s *NotificationRuleService) DeleteNotificationRule(ctx context.Context, id influxdb.ID) error {
+	r, err := s.s.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.WriteAction, influxdb.NotificationRulesResourceType, r.OrganizationID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteNotificationRule(ctx, id)
+}
+
+// NotificationEndpointService wraps a influxdb.NotificationEndpointService and authorizes actions
+// against it appropriately.
+type NotificationEndpointService struct {
+	s influxdb.NotificationEndpointService
+}
+
+// NewNotificationEndpointService constructs an instance of an authorizing notification endpoint service.
+func NewNotificationEndpointService(s influxdb.NotificationEndpointService) *NotificationEndpointService {
+	return &NotificationEndpointService{
+		s: s,
+	}
+}
+
+// FindNotificationEndpointByID checks to see if the authorizer on context has read access to the id provided.
+func (s *NotificationEndpointService) FindNotificationEndpointByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationEndpoint, error) {
+	e, err := s.s.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.ReadAction, influxdb.NotificationEndpointsResourceType, e.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// FindNotificationEndpoints retrieves all notification endpoints that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *NotificationEndpointService) FindNotificationEndpoints(ctx context.Context, filter influxdb.NotificationEndpointFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationEndpoint, int, error) {
+	es, _, err := s.s.FindNotificationEndpoints(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	endpoints := es[:0]
+	for _, e := range es {
+		err := authorizeOrgResource(ctx, influxdb.ReadAction, influxdb.NotificationEndpointsResourceType, e.OrganizationID, e.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		endpoints = append(endpoints, e)
+	}
+
+	return endpoints, len(endpoints), nil
+}
+
+// CreateNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoints of the organization.
+func (s *NotificationEndpointService) CreateNotificationEndpoint(ctx context.Context, e *influxdb.NotificationEndpoint) error {
+	if err := authorizeCreateOrgResource(ctx, influxdb.NotificationEndpointsResourceType, e.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.CreateNotificationEndpoint(ctx, e)
+}
+
+// UpdateNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
+func (s *NotificationEndpointService) UpdateNotificationEndpoint(ctx context.Context, id influxdb.ID, upd influxdb.NotificationEndpointUpdate) (*influxdb.NotificationEndpoint, error) {
+	e, err := s.s.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.WriteAction, influxdb.NotificationEndpointsResourceType, e.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateNotificationEndpoint(ctx, id, upd)
+}
+
+// DeleteNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
+func (s *NotificationEndpointService) DeleteNotificationEndpoint(ctx context.Context, id influxdb.ID) error {
+	e, err := s.s.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeOrgResource(ctx, influxdb.WriteAction, influxdb.NotificationEndpointsResourceType, e.OrganizationID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteNotificationEndpoint(ctx, id)
+}
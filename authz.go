@@ -111,28 +111,47 @@ const (
 	TelegrafsResourceType = ResourceType("telegrafs") // 6
 	// UsersResourceType gives permissions to one or more users.
 	UsersResourceType = ResourceType("users") // 7
+	// ChecksResourceType gives permissions to one or more checks.
+	ChecksResourceType = ResourceType("checks") // 8
+	// NotificationRulesResourceType gives permissions to one or more notification rules.
+	NotificationRulesResourceType = ResourceType("notificationRules") // 9
+	// NotificationEndpointsResourceType gives permissions to one or more notification endpoints.
+	NotificationEndpointsResourceType = ResourceType("notificationEndpoints") // 10
+	// StorageResourceType gives permissions to observe and control the storage engine.
+	StorageResourceType = ResourceType("storage") // 11
+	// SecretsResourceType gives permissions to one or more secrets.
+	SecretsResourceType = ResourceType("secrets") // 12
 )
 
 // AllResourceTypes is the list of all known resource types.
 var AllResourceTypes = []ResourceType{
-	AuthorizationsResourceType, // 0
-	BucketsResourceType,        // 1
-	DashboardsResourceType,     // 2
-	OrgsResourceType,           // 3
-	SourcesResourceType,        // 4
-	TasksResourceType,          // 5
-	TelegrafsResourceType,      // 6
-	UsersResourceType,          // 7
+	AuthorizationsResourceType,        // 0
+	BucketsResourceType,               // 1
+	DashboardsResourceType,            // 2
+	OrgsResourceType,                  // 3
+	SourcesResourceType,               // 4
+	TasksResourceType,                 // 5
+	TelegrafsResourceType,             // 6
+	UsersResourceType,                 // 7
+	ChecksResourceType,                // 8
+	NotificationRulesResourceType,     // 9
+	NotificationEndpointsResourceType, // 10
+	StorageResourceType,               // 11
+	SecretsResourceType,               // 12
 }
 
 // OrgResourceTypes is the list of all known resource types that belong to an organization.
 var OrgResourceTypes = []ResourceType{
-	BucketsResourceType,    // 1
-	DashboardsResourceType, // 2
-	SourcesResourceType,    // 4
-	TasksResourceType,      // 5
-	TelegrafsResourceType,  // 6
-	UsersResourceType,      // 7
+	BucketsResourceType,               // 1
+	DashboardsResourceType,            // 2
+	SourcesResourceType,               // 4
+	TasksResourceType,                 // 5
+	TelegrafsResourceType,             // 6
+	UsersResourceType,                 // 7
+	ChecksResourceType,                // 8
+	NotificationRulesResourceType,     // 9
+	NotificationEndpointsResourceType, // 10
+	SecretsResourceType,               // 12
 }
 
 // Valid checks if the resource is a member of the Resource enum.
@@ -150,6 +169,11 @@ func (t ResourceType) Valid() (err error) {
 	case TelegrafsResourceType: // 5
 	case SourcesResourceType: // 6
 	case UsersResourceType: //7
+	case ChecksResourceType: // 8
+	case NotificationRulesResourceType: // 9
+	case NotificationEndpointsResourceType: // 10
+	case StorageResourceType: // 11
+	case SecretsResourceType: // 12
 	default:
 		err = ErrInvalidResourceType
 	}
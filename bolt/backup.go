@@ -0,0 +1,98 @@
+package bolt
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+)
+
+// Backup writes a consistent snapshot of the boltDB file to w.
+func (c *Client) Backup(w io.Writer) error {
+	return c.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
+// localBuckets are the buckets Restore keeps.  The sessions are those of the
+// instance, so that users sign in to a replication follower.
+var localBuckets = map[string]bool{
+	"sessionsv1": true,
+}
+
+// Restore replaces all the buckets of the boltDB file, except localBuckets,
+// with those of the snapshot read from r, as written by Backup.  The buckets
+// are replaced in a single transaction, so readers see either the old or the
+// new content.
+func (c *Client) Restore(r io.Reader) error {
+	f, err := ioutil.TempFile(filepath.Dir(c.Path), filepath.Base(c.Path)+".restore")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	snapshot, err := bolt.Open(f.Name(), 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+
+	return snapshot.View(func(src *bolt.Tx) error {
+		return c.db.Update(func(dst *bolt.Tx) error {
+			var names [][]byte
+			if err := dst.ForEach(func(name []byte, _ *bolt.Bucket) error {
+				if !localBuckets[string(name)] {
+					names = append(names, append([]byte(nil), name...))
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			for _, name := range names {
+				if err := dst.DeleteBucket(name); err != nil {
+					return err
+				}
+			}
+
+			return src.ForEach(func(name []byte, b *bolt.Bucket) error {
+				if localBuckets[string(name)] {
+					return nil
+				}
+				nb, err := dst.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(nb, b)
+			})
+		})
+	})
+}
+
+// copyBucket copies the keys and the nested buckets of src to dst.
+func copyBucket(dst, src *bolt.Bucket) error {
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+		nb, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(nb, src.Bucket(k))
+	})
+}
@@ -0,0 +1,69 @@
+package bolt_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestClient_Restore(t *testing.T) {
+	leader, closeLeader, err := NewTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeLeader()
+
+	follower, closeFollower, err := NewTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFollower()
+
+	ctx := context.Background()
+	org := &platform.Organization{Name: "org"}
+	if err := leader.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &platform.Bucket{Name: "bucket", OrganizationID: org.ID}
+	if err := leader.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadata of the follower missing from the leader is removed.
+	if err := follower.CreateOrganization(ctx, &platform.Organization{Name: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	// The sessions of the follower are kept.
+	user := &platform.User{Name: "user"}
+	if err := follower.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	session, err := follower.CreateSession(ctx, user.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := leader.Backup(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := follower.Restore(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := follower.FindBucket(ctx, platform.BucketFilter{Name: &bucket.Name, OrganizationID: &org.ID}); err != nil {
+		t.Fatalf("bucket of the leader not found: %v", err)
+	}
+	orgs, _, err := follower.FindOrganizations(ctx, platform.OrganizationFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orgs) != 1 || orgs[0].ID != org.ID {
+		t.Fatalf("got organizations %v, exp only %v", orgs, org)
+	}
+	if _, err := follower.FindSession(ctx, session.Key); err != nil {
+		t.Fatalf("session of the follower not found: %v", err)
+	}
+}
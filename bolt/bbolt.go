@@ -2,43 +2,37 @@ package bolt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/coreos/bbolt"
-	platform "github.com/influxdata/influxdb"
-	"github.com/influxdata/influxdb/rand"
-	"github.com/influxdata/influxdb/snowflake"
+	"github.com/influxdata/influxdb/kit/check"
+	"github.com/influxdata/influxdb/kv"
 	"go.uber.org/zap"
 )
 
 // OpPrefix is the prefix for bolt ops
 const OpPrefix = "bolt/"
 
-func getOp(op string) string {
-	return OpPrefix + op
-}
-
-// Client is a client for the boltDB data store.
+// Client is a client for the boltDB data store.  The metadata services are
+// those of a kv.Service on the boltDB file.
 type Client struct {
-	Path   string
-	db     *bolt.DB
-	Logger *zap.Logger
+	*kv.Service
 
-	IDGenerator    platform.IDGenerator
-	TokenGenerator platform.TokenGenerator
-	time           func() time.Time
+	Path  string
+	db    *bolt.DB
+	store *KVStore
 }
 
 // NewClient returns an instance of a Client.
 func NewClient() *Client {
+	store := NewKVStore("")
 	return &Client{
-		Logger:         zap.NewNop(),
-		IDGenerator:    snowflake.NewIDGenerator(),
-		TokenGenerator: rand.NewTokenGenerator(64),
-		time:           time.Now,
+		Service: kv.NewService(store),
+		store:   store,
 	}
 }
 
@@ -50,13 +44,8 @@ func (c *Client) DB() *bolt.DB {
 // WithLogger sets the logger an a client. It should not be called after
 // the client has been open.
 func (c *Client) WithLogger(l *zap.Logger) {
-	c.Logger = l
-}
-
-// WithTime sets the function for computing the current time. Used for updating meta data
-// about objects stored. Should only be used in tests for mocking.
-func (c *Client) WithTime(fn func() time.Time) {
-	c.time = fn
+	c.Service.WithLogger(l)
+	c.store.WithLogger(l)
 }
 
 // Open / create boltDB file.
@@ -76,8 +65,10 @@ func (c *Client) Open(ctx context.Context) error {
 		return fmt.Errorf("unable to open boltdb; is there a chronograf already running?  %v", err)
 	}
 	c.db = db
+	c.store.path = c.Path
+	c.store.WithDB(db)
 
-	if err := c.initialize(ctx); err != nil {
+	if err := c.Initialize(ctx); err != nil {
 		return err
 	}
 
@@ -85,95 +76,20 @@ func (c *Client) Open(ctx context.Context) error {
 	return nil
 }
 
-// initialize creates Buckets that are missing
-func (c *Client) initialize(ctx context.Context) error {
-	if err := c.db.Update(func(tx *bolt.Tx) error {
-		// Always create Buckets bucket.
-		if err := c.initializeBuckets(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Organizations bucket.
-		if err := c.initializeOrganizations(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Dashboards bucket.
-		if err := c.initializeDashboards(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create User bucket.
-		if err := c.initializeUsers(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Authorization bucket.
-		if err := c.initializeAuthorizations(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Onboarding bucket.
-		if err := c.initializeOnboarding(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Telegraf Config bucket.
-		if err := c.initializeTelegraf(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Source bucket.
-		if err := c.initializeSources(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Views bucket.
-		if err := c.initializeViews(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Macros bucket.
-		if err := c.initializeMacros(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Scraper bucket.
-		if err := c.initializeScraperTargets(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create UserResourceMapping bucket.
-		if err := c.initializeUserResourceMappings(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create labels bucket.
-		if err := c.initializeLabels(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create Session bucket.
-		if err := c.initializeSessions(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create KeyValueLog bucket.
-		if err := c.initializeKeyValueLog(ctx, tx); err != nil {
-			return err
-		}
-
-		// Always create SecretService bucket.
-		if err := c.initializeSecretService(ctx, tx); err != nil {
-			return err
-		}
-
-		return nil
-	}); err != nil {
-		return err
-	}
+// CheckName returns the name of the client's health check.
+func (c *Client) CheckName() string {
+	return "bolt"
+}
 
-	return nil
+// Check reports whether the bolt database is open and can be read.
+func (c *Client) Check(ctx context.Context) check.Response {
+	if c.db == nil {
+		return check.Error(errors.New("bolt database is not open"))
+	}
+	if err := c.db.View(func(*bolt.Tx) error { return nil }); err != nil {
+		return check.Error(err)
+	}
+	return check.Pass()
 }
 
 // Close the connection to the bolt database
@@ -9,11 +9,12 @@ import (
 	"testing"
 
 	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/kv"
 	"golang.org/x/crypto/bcrypt"
 )
 
 func init() {
-	bolt.HashCost = bcrypt.MinCost
+	kv.HashCost = bcrypt.MinCost
 }
 
 func NewTestClient() (*bolt.Client, func(), error) {
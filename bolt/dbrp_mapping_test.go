@@ -1,4 +1,4 @@
-package inmem
+package bolt_test
 
 import (
 	"context"
@@ -9,35 +9,32 @@ import (
 )
 
 func initDBRPMappingService(f platformtesting.DBRPMappingFields, t *testing.T) (platform.DBRPMappingService, func()) {
-	s := NewService()
-	ctx := context.TODO()
-	if err := f.Populate(ctx, s); err != nil {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	if err := f.Populate(context.TODO(), c); err != nil {
 		t.Fatal(err)
 	}
-	return s, func() {}
+	return c, closeFn
 }
 
 func TestDBRPMappingService_CreateDBRPMapping(t *testing.T) {
-	t.Parallel()
 	platformtesting.CreateDBRPMapping(initDBRPMappingService, t)
 }
 
 func TestDBRPMappingService_FindDBRPMappingByKey(t *testing.T) {
-	t.Parallel()
 	platformtesting.FindDBRPMappingByKey(initDBRPMappingService, t)
 }
 
 func TestDBRPMappingService_FindDBRPMappings(t *testing.T) {
-	t.Parallel()
 	platformtesting.FindDBRPMappings(initDBRPMappingService, t)
 }
 
 func TestDBRPMappingService_DeleteDBRPMapping(t *testing.T) {
-	t.Parallel()
 	platformtesting.DeleteDBRPMapping(initDBRPMappingService, t)
 }
 
 func TestDBRPMappingService_FindDBRPMapping(t *testing.T) {
-	t.Parallel()
 	platformtesting.FindDBRPMapping(initDBRPMappingService, t)
 }
@@ -115,10 +115,14 @@ func (tx *Tx) createBucketIfNotExists(b []byte) (*Bucket, error) {
 	}, nil
 }
 
-// Bucket retrieves the bucket named b.
+// Bucket retrieves the bucket named b.  A missing bucket is created, or
+// kv.ErrTxNotWritable is returned in a read-only transaction.
 func (tx *Tx) Bucket(b []byte) (kv.Bucket, error) {
 	bkt := tx.tx.Bucket(b)
 	if bkt == nil {
+		if !tx.tx.Writable() {
+			return nil, kv.ErrTxNotWritable
+		}
 		return tx.createBucketIfNotExists(b)
 	}
 	return &Bucket{
@@ -133,8 +137,9 @@ type Bucket struct {
 
 // Get retrieves the value at the provided key.
 func (b *Bucket) Get(key []byte) ([]byte, error) {
+	// Get returns nil for a missing key and an empty slice for an empty value.
 	val := b.bucket.Get(key)
-	if len(val) == 0 {
+	if val == nil {
 		return nil, kv.ErrKeyNotFound
 	}
 
@@ -168,7 +173,8 @@ func (b *Bucket) Cursor() (kv.Cursor, error) {
 }
 
 // Cursor is a struct for iterating through the entries
-// in the key value store.
+// in the key value store.  Entries with empty values, such
+// as index entries, are returned like any other.
 type Cursor struct {
 	cursor *bolt.Cursor
 }
@@ -176,7 +182,7 @@ type Cursor struct {
 // Seek seeks for the first key that matches the prefix provided.
 func (c *Cursor) Seek(prefix []byte) ([]byte, []byte) {
 	k, v := c.cursor.Seek(prefix)
-	if len(v) == 0 {
+	if k == nil {
 		return nil, nil
 	}
 	return k, v
@@ -185,7 +191,7 @@ func (c *Cursor) Seek(prefix []byte) ([]byte, []byte) {
 // First retrieves the first key value pair in the bucket.
 func (c *Cursor) First() ([]byte, []byte) {
 	k, v := c.cursor.First()
-	if len(v) == 0 {
+	if k == nil {
 		return nil, nil
 	}
 	return k, v
@@ -194,7 +200,7 @@ func (c *Cursor) First() ([]byte, []byte) {
 // Last retrieves the last key value pair in the bucket.
 func (c *Cursor) Last() ([]byte, []byte) {
 	k, v := c.cursor.Last()
-	if len(v) == 0 {
+	if k == nil {
 		return nil, nil
 	}
 	return k, v
@@ -203,7 +209,7 @@ func (c *Cursor) Last() ([]byte, []byte) {
 // Next retrieves the next key in the bucket.
 func (c *Cursor) Next() ([]byte, []byte) {
 	k, v := c.cursor.Next()
-	if len(v) == 0 {
+	if k == nil {
 		return nil, nil
 	}
 	return k, v
@@ -212,7 +218,7 @@ func (c *Cursor) Next() ([]byte, []byte) {
 // Prev retrieves the previous key in the bucket.
 func (c *Cursor) Prev() ([]byte, []byte) {
 	k, v := c.cursor.Prev()
-	if len(v) == 0 {
+	if k == nil {
 		return nil, nil
 	}
 	return k, v
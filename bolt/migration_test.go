@@ -0,0 +1,62 @@
+package bolt_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestClientOpen_Migrations(t *testing.T) {
+	ctx := context.Background()
+	applied := 0
+	migrations := []kv.Migration{
+		{
+			Name: "test",
+			Up: func(ctx context.Context, tx kv.Tx) error {
+				applied++
+				return nil
+			},
+		},
+	}
+
+	f, err := ioutil.TempFile("", "influxdata-platform-bolt-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	c := bolt.NewClient()
+	c.Path = f.Name()
+	c.Migrations = migrations
+	if err := c.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if applied != 1 {
+		t.Fatalf("migration applied %d times on open, want 1", applied)
+	}
+
+	// Opening the store again does not apply the migration again.
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if applied != 1 {
+		t.Fatalf("migration applied %d times on reopen, want 1", applied)
+	}
+
+	states, err := c.Migrator().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || states[0].Name != "test" || states[0].AppliedAt == nil {
+		t.Errorf("unexpected migration states %+v", states)
+	}
+}
@@ -0,0 +1,415 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+)
+
+// ErrCheckNotFound is the error msg for a missing check.
+const ErrCheckNotFound = "check not found"
+
+// ops for checks error
+const (
+	OpFindCheckByID = "FindCheckByID"
+	OpFindChecks    = "FindChecks"
+	OpCreateCheck   = "CreateCheck"
+	OpUpdateCheck   = "UpdateCheck"
+	OpDeleteCheck   = "DeleteCheck"
+)
+
+const (
+	// MonitoringBucketName is the name of the system bucket that checks write
+	// their statuses to. One is created per organization with the first check.
+	MonitoringBucketName = "_monitoring"
+
+	// StatusMeasurement is the measurement of the points written by checks.
+	StatusMeasurement = "statuses"
+
+	// Columns added by checks to the statuses they write.
+	CheckIDColumn   = "_check_id"
+	CheckNameColumn = "_check_name"
+	LevelColumn     = "_level"
+)
+
+// CheckService represents a service for managing checks.
+type CheckService interface {
+	// FindCheckByID returns a single check by ID.
+	FindCheckByID(ctx context.Context, id ID) (*Check, error)
+
+	// FindChecks returns a list of checks that match filter and the total count of matching checks.
+	// Additional options provide pagination & sorting.
+	FindChecks(ctx context.Context, filter CheckFilter, opt ...FindOptions) ([]*Check, int, error)
+
+	// CreateCheck creates a new check and sets c.ID with the new identifier.
+	CreateCheck(ctx context.Context, c *Check) error
+
+	// UpdateCheck updates a single check with changeset.
+	// Returns the new check state after update.
+	UpdateCheck(ctx context.Context, id ID, upd CheckUpdate) (*Check, error)
+
+	// DeleteCheck removes a check by ID.
+	DeleteCheck(ctx context.Context, id ID) error
+}
+
+// CheckType is the kind of evaluation a check performs.
+type CheckType string
+
+// Check types
+const (
+	// ThresholdCheckType compares the values returned by the query against thresholds.
+	ThresholdCheckType CheckType = "threshold"
+	// DeadmanCheckType reports series that have stopped reporting.
+	DeadmanCheckType CheckType = "deadman"
+)
+
+// CheckLevel is the level of a status written by a check.
+type CheckLevel string
+
+// Check levels, from least to most severe.
+const (
+	LevelOK   CheckLevel = "ok"
+	LevelInfo CheckLevel = "info"
+	LevelWarn CheckLevel = "warn"
+	LevelCrit CheckLevel = "crit"
+)
+
+// Valid returns an error if the level is unknown.
+func (l CheckLevel) Valid() error {
+	switch l {
+	case LevelOK, LevelInfo, LevelWarn, LevelCrit:
+		return nil
+	default:
+		return fmt.Errorf("unknown check level %q", l)
+	}
+}
+
+// Check status values
+const (
+	CheckStatusActive   = "active"
+	CheckStatusInactive = "inactive"
+)
+
+// Check is a query that is run on a schedule and whose results are turned
+// into statuses with a level.
+type Check struct {
+	ID             ID        `json:"id,omitempty"`
+	OrganizationID ID        `json:"orgID,omitempty"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description,omitempty"`
+	Status         string    `json:"status"`
+	Type           CheckType `json:"type"`
+	// Query is the flux query whose results are checked. It must return
+	// _time and _value columns.
+	Query string `json:"query"`
+	// Every is how often the check runs, e.g. "1m".
+	Every string `json:"every"`
+
+	// Thresholds are evaluated by threshold checks. When several match,
+	// the most severe level wins; rows matching none are ok.
+	Thresholds []Threshold `json:"thresholds,omitempty"`
+
+	// TimeSince is how long a series may go without points before a
+	// deadman check reports it at Level.
+	TimeSince string     `json:"timeSince,omitempty"`
+	Level     CheckLevel `json:"level,omitempty"`
+
+	// TaskID is the task that runs the check.
+	TaskID ID `json:"taskID,omitempty"`
+}
+
+// Threshold operators
+const (
+	ThresholdGreater = "greater"
+	ThresholdLesser  = "lesser"
+)
+
+// Threshold assigns Level to values that are greater or lesser than Value.
+type Threshold struct {
+	Level CheckLevel `json:"level"`
+	Op    string     `json:"op"`
+	Value float64    `json:"value"`
+}
+
+// checkQueryExpression parses the query of a check, which must be a single
+// expression so that it can only be assigned to data in the generated script.
+func checkQueryExpression(query string) (ast.Expression, error) {
+	pkg := parser.ParseSource(query)
+	if ast.Check(pkg) > 0 {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  "invalid check query",
+			Err:  ast.GetError(pkg),
+		}
+	}
+	if len(pkg.Files) == 1 {
+		f := pkg.Files[0]
+		if f.Package == nil && len(f.Imports) == 0 && len(f.Body) == 1 {
+			if s, ok := f.Body[0].(*ast.ExpressionStatement); ok {
+				return s.Expression, nil
+			}
+		}
+	}
+	return nil, &Error{
+		Code: EInvalid,
+		Msg:  "check query must be a single expression",
+	}
+}
+
+// Valid returns an error if the check is not valid.
+func (c *Check) Valid() error {
+	if c.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "check name is required",
+		}
+	}
+	if c.Query == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "check query is required",
+		}
+	}
+	if _, err := checkQueryExpression(c.Query); err != nil {
+		return err
+	}
+	if err := validDuration("every", c.Every); err != nil {
+		return err
+	}
+	switch c.Status {
+	case "", CheckStatusActive, CheckStatusInactive:
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid check status %q", c.Status),
+		}
+	}
+
+	switch c.Type {
+	case ThresholdCheckType:
+		if len(c.Thresholds) == 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "threshold check requires at least one threshold",
+			}
+		}
+		for _, t := range c.Thresholds {
+			if err := t.Level.Valid(); err != nil {
+				return &Error{
+					Code: EInvalid,
+					Err:  err,
+				}
+			}
+			if t.Level == LevelOK {
+				return &Error{
+					Code: EInvalid,
+					Msg:  "threshold level must not be ok",
+				}
+			}
+			if t.Op != ThresholdGreater && t.Op != ThresholdLesser {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("invalid threshold op %q", t.Op),
+				}
+			}
+		}
+	case DeadmanCheckType:
+		if err := validDuration("timeSince", c.TimeSince); err != nil {
+			return err
+		}
+		if err := c.Level.Valid(); err != nil || c.Level == LevelOK {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid deadman level %q", c.Level),
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unknown check type %q", c.Type),
+		}
+	}
+	return nil
+}
+
+// durationRegexp matches the flux duration literals that checks accept.
+var durationRegexp = regexp.MustCompile(`^([0-9]+(ns|us|ms|s|m|h|d|w))+$`)
+
+func validDuration(name, s string) error {
+	if !durationRegexp.MatchString(s) || strings.Trim(s, "0nsumhdw") == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("check %s must be a positive duration", name),
+		}
+	}
+	return nil
+}
+
+// GenerateFlux returns the task script that runs the check. The script
+// writes one status per row returned by the query to the monitoring bucket,
+// tagged with the check ID, name and level.
+func (c *Check) GenerateFlux() (string, error) {
+	if err := c.Valid(); err != nil {
+		return "", err
+	}
+	data, err := checkQueryExpression(c.Query)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "option task = {name: %s, every: %s}\n\n", strconv.Quote("check: "+c.Name), c.Every)
+	fmt.Fprintf(&b, "data = %s\n\n", ast.Format(data))
+	b.WriteString("checkTime = now()\n\n")
+
+	var levels []string
+	switch c.Type {
+	case ThresholdCheckType:
+		levels = c.thresholdLevels(&b)
+	case DeadmanCheckType:
+		levels = c.deadmanLevels(&b)
+	}
+
+	// Statuses are stamped with the time of the run, so that a series has
+	// exactly one status per run whatever the times returned by the query.
+	fmt.Fprintf(&b, "union(tables: [%s])\n", strings.Join(levels, ", "))
+	fmt.Fprintf(&b, "\t|> map(fn: (r) => ({_time: checkTime, _value: r._value, %s: r.%s}))\n", LevelColumn, LevelColumn)
+	fmt.Fprintf(&b, "\t|> set(key: %q, value: %q)\n", CheckIDColumn, c.ID.String())
+	fmt.Fprintf(&b, "\t|> set(key: %q, value: %s)\n", CheckNameColumn, strconv.Quote(c.Name))
+	fmt.Fprintf(&b, "\t|> set(key: \"_measurement\", value: %q)\n", StatusMeasurement)
+	fmt.Fprintf(&b, "\t|> to(bucket: %q, orgID: %q)\n", MonitoringBucketName, c.OrganizationID.String())
+	return b.String(), nil
+}
+
+// levelOrder lists levels from most to least severe.
+var levelOrder = []CheckLevel{LevelCrit, LevelWarn, LevelInfo}
+
+// thresholdLevels writes one filtered stream per level. A row is assigned
+// the most severe level whose thresholds it matches.
+func (c *Check) thresholdLevels(b *strings.Builder) []string {
+	var levels, higher []string
+	for _, l := range levelOrder {
+		var conds []string
+		for _, t := range c.Thresholds {
+			if t.Level != l {
+				continue
+			}
+			op := ">"
+			if t.Op == ThresholdLesser {
+				op = "<"
+			}
+			conds = append(conds, fmt.Sprintf("r._value %s %s", op, strconv.FormatFloat(t.Value, 'f', -1, 64)))
+		}
+		if len(conds) == 0 {
+			continue
+		}
+		cond := "(" + strings.Join(conds, " or ") + ")"
+		writeLevel(b, l, append(negate(higher), cond))
+		levels = append(levels, string(l))
+		higher = append(higher, cond)
+	}
+	writeLevel(b, LevelOK, negate(higher))
+	return append(levels, string(LevelOK))
+}
+
+// deadmanLevels writes a stream of the series whose last point is older
+// than TimeSince, and a stream of ok series.
+func (c *Check) deadmanLevels(b *strings.Builder) []string {
+	fmt.Fprintf(b, "%s = data\n\t|> last()\n\t|> range(start: 1970-01-01T00:00:00Z, stop: -%s)\n\t|> set(key: %q, value: %q)\n\n", c.Level, c.TimeSince, LevelColumn, c.Level)
+	fmt.Fprintf(b, "%s = data\n\t|> last()\n\t|> range(start: -%s)\n\t|> set(key: %q, value: %q)\n\n", LevelOK, c.TimeSince, LevelColumn, LevelOK)
+	return []string{string(c.Level), string(LevelOK)}
+}
+
+func writeLevel(b *strings.Builder, l CheckLevel, conds []string) {
+	fmt.Fprintf(b, "%s = data\n\t|> filter(fn: (r) => %s)\n\t|> set(key: %q, value: %q)\n\n", l, strings.Join(conds, " and "), LevelColumn, l)
+}
+
+func negate(conds []string) []string {
+	out := make([]string, len(conds))
+	for i, c := range conds {
+		out[i] = "not " + c
+	}
+	if len(out) == 0 {
+		out = append(out, "true")
+	}
+	return out
+}
+
+// CheckFilter represents a set of filters that restrict the returned checks.
+type CheckFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	Organization   *string
+}
+
+// QueryParams implements PagingFilter.
+//
+// It converts CheckFilter fields to url query params.
+func (f CheckFilter) QueryParams() map[string][]string {
+	return orgFilterQueryParams(f.ID, f.OrganizationID, f.Organization)
+}
+
+// CheckUpdate is the changeset for a check.
+type CheckUpdate struct {
+	Name        *string     `json:"name,omitempty"`
+	Description *string     `json:"description,omitempty"`
+	Status      *string     `json:"status,omitempty"`
+	Query       *string     `json:"query,omitempty"`
+	Every       *string     `json:"every,omitempty"`
+	Thresholds  []Threshold `json:"thresholds,omitempty"`
+	TimeSince   *string     `json:"timeSince,omitempty"`
+	Level       *CheckLevel `json:"level,omitempty"`
+
+	// TaskID is set by the service that schedules the check and cannot
+	// be changed through the API.
+	TaskID *ID `json:"-"`
+}
+
+// Valid returns an error if the update changes nothing.
+func (u CheckUpdate) Valid() error {
+	if u.Name == nil && u.Description == nil && u.Status == nil && u.Query == nil &&
+		u.Every == nil && u.Thresholds == nil && u.TimeSince == nil && u.Level == nil && u.TaskID == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "no fields supplied in update",
+		}
+	}
+	return nil
+}
+
+// Apply applies the non-nil fields of the update to c and validates the result.
+func (u CheckUpdate) Apply(c *Check) error {
+	if u.Name != nil {
+		c.Name = *u.Name
+	}
+	if u.Description != nil {
+		c.Description = *u.Description
+	}
+	if u.Status != nil {
+		c.Status = *u.Status
+	}
+	if u.Query != nil {
+		c.Query = *u.Query
+	}
+	if u.Every != nil {
+		c.Every = *u.Every
+	}
+	if u.Thresholds != nil {
+		c.Thresholds = u.Thresholds
+	}
+	if u.TimeSince != nil {
+		c.TimeSince = *u.TimeSince
+	}
+	if u.Level != nil {
+		c.Level = *u.Level
+	}
+	if u.TaskID != nil {
+		c.TaskID = *u.TaskID
+	}
+	return c.Valid()
+}
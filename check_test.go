@@ -0,0 +1,168 @@
+package influxdb_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	platform "github.com/influxdata/influxdb"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+func TestCheck_Valid(t *testing.T) {
+	tests := []struct {
+		name  string
+		check platform.Check
+		msg   string
+	}{
+		{
+			name: "valid threshold check",
+			check: platform.Check{
+				Name:       "cpu",
+				Type:       platform.ThresholdCheckType,
+				Query:      `from(bucket: "b") |> range(start: -1m)`,
+				Every:      "1m",
+				Thresholds: []platform.Threshold{{Level: platform.LevelWarn, Op: platform.ThresholdLesser, Value: 10}},
+			},
+		},
+		{
+			name: "missing thresholds",
+			check: platform.Check{
+				Name:  "cpu",
+				Type:  platform.ThresholdCheckType,
+				Query: `from(bucket: "b") |> range(start: -1m)`,
+				Every: "1m",
+			},
+			msg: "threshold check requires at least one threshold",
+		},
+		{
+			name: "zero every",
+			check: platform.Check{
+				Name:       "cpu",
+				Type:       platform.ThresholdCheckType,
+				Query:      `from(bucket: "b") |> range(start: -1m)`,
+				Every:      "0s",
+				Thresholds: []platform.Threshold{{Level: platform.LevelWarn, Op: platform.ThresholdLesser, Value: 10}},
+			},
+			msg: "check every must be a positive duration",
+		},
+		{
+			name: "query with several statements",
+			check: platform.Check{
+				Name:       "cpu",
+				Type:       platform.ThresholdCheckType,
+				Query:      "from(bucket: \"b\") |> range(start: -1m)\nfrom(bucket: \"other\") |> range(start: -1h) |> yield(name: \"other\")",
+				Every:      "1m",
+				Thresholds: []platform.Threshold{{Level: platform.LevelWarn, Op: platform.ThresholdLesser, Value: 10}},
+			},
+			msg: "check query must be a single expression",
+		},
+		{
+			name: "query with an option",
+			check: platform.Check{
+				Name:       "cpu",
+				Type:       platform.ThresholdCheckType,
+				Query:      `option now = () => 2019-01-01T00:00:00Z`,
+				Every:      "1m",
+				Thresholds: []platform.Threshold{{Level: platform.LevelWarn, Op: platform.ThresholdLesser, Value: 10}},
+			},
+			msg: "check query must be a single expression",
+		},
+		{
+			name: "query that does not parse",
+			check: platform.Check{
+				Name:       "cpu",
+				Type:       platform.ThresholdCheckType,
+				Query:      `from(bucket: "b"))`,
+				Every:      "1m",
+				Thresholds: []platform.Threshold{{Level: platform.LevelWarn, Op: platform.ThresholdLesser, Value: 10}},
+			},
+			msg: "invalid check query",
+		},
+		{
+			name: "deadman with ok level",
+			check: platform.Check{
+				Name:      "cpu",
+				Type:      platform.DeadmanCheckType,
+				Query:     `from(bucket: "b") |> range(start: -1h)`,
+				Every:     "1m",
+				TimeSince: "5m",
+				Level:     platform.LevelOK,
+			},
+			msg: `invalid deadman level "ok"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check.Valid()
+			if tt.msg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || platform.ErrorMessage(err) != tt.msg {
+				t.Fatalf("expected error %q, got %v", tt.msg, err)
+			}
+		})
+	}
+}
+
+func TestCheck_GenerateFlux(t *testing.T) {
+	checks := []*platform.Check{
+		{
+			ID:             1,
+			OrganizationID: 2,
+			Name:           `cpu "usage"`,
+			Type:           platform.ThresholdCheckType,
+			Query:          `from(bucket: "telegraf") |> range(start: -1m) |> filter(fn: (r) => r._measurement == "cpu")`,
+			Every:          "30s",
+			Thresholds: []platform.Threshold{
+				{Level: platform.LevelCrit, Op: platform.ThresholdGreater, Value: 90},
+				{Level: platform.LevelWarn, Op: platform.ThresholdGreater, Value: 70.5},
+				{Level: platform.LevelInfo, Op: platform.ThresholdLesser, Value: 5},
+			},
+		},
+		{
+			ID:             1,
+			OrganizationID: 2,
+			Name:           "heartbeat",
+			Type:           platform.DeadmanCheckType,
+			Query:          `from(bucket: "telegraf") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "system")`,
+			Every:          "1m",
+			TimeSince:      "5m",
+			Level:          platform.LevelCrit,
+		},
+	}
+
+	for _, c := range checks {
+		t.Run(string(c.Type), func(t *testing.T) {
+			script, err := c.GenerateFlux()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := flux.Compile(context.Background(), script, time.Now()); err != nil {
+				t.Fatalf("generated script does not compile: %v\n%s", err, script)
+			}
+
+			opts, err := options.FromScript(script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts.Name != "check: "+c.Name {
+				t.Errorf("unexpected task name %q", opts.Name)
+			}
+			if every, _ := time.ParseDuration(c.Every); opts.Every != every {
+				t.Errorf("unexpected task every %s", opts.Every)
+			}
+			if !strings.Contains(script, `to(bucket: "_monitoring", orgID: "0000000000000002")`) {
+				t.Errorf("script does not write to the monitoring bucket:\n%s", script)
+			}
+		})
+	}
+}
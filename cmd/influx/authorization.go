@@ -6,7 +6,6 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
-	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/spf13/cobra"
@@ -254,7 +253,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Token",
@@ -354,7 +353,7 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Token",
@@ -425,7 +424,7 @@ func authorizationDeleteF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Token",
@@ -494,7 +493,7 @@ func authorizationActiveF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Token",
@@ -563,7 +562,7 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Token",
@@ -8,7 +8,6 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
-	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/spf13/cobra"
@@ -108,7 +107,7 @@ func bucketCreateF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -197,7 +196,7 @@ func bucketFindF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -268,7 +267,7 @@ func bucketUpdateF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -318,7 +317,7 @@ func bucketDeleteF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
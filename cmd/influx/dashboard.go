@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/dashboards"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Dashboard Command
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Dashboard management commands",
+	Run:   dashboardF,
+}
+
+func dashboardF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+// dashboardServices are the services used by the dashboard commands.
+type dashboardServices struct {
+	dashboards    platform.DashboardService
+	templates     platform.DashboardTemplateService
+	organizations platform.OrganizationService
+}
+
+// newDashboardServices returns the dashboard, dashboard template and
+// organization services. In local mode they all use the same bolt client, as
+// the bolt file can only be opened once.
+func newDashboardServices(f Flags) (*dashboardServices, error) {
+	if flags.local {
+		c, err := newLocalClient()
+		if err != nil {
+			return nil, err
+		}
+
+		return &dashboardServices{
+			dashboards:    c,
+			templates:     dashboards.NewTemplateService(c, c, c, c),
+			organizations: c,
+		}, nil
+	}
+	s := &http.DashboardService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	return &dashboardServices{
+		dashboards: s,
+		templates:  s,
+		organizations: &http.OrganizationService{
+			Addr:  flags.host,
+			Token: flags.token,
+		},
+	}, nil
+}
+
+// DashboardCreateFlags define the Create Command
+type DashboardCreateFlags struct {
+	name        string
+	description string
+	orgID       string
+}
+
+var dashboardCreateFlags DashboardCreateFlags
+
+func init() {
+	dashboardCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create dashboard",
+		RunE:  dashboardCreateF,
+	}
+
+	dashboardCreateCmd.Flags().StringVarP(&dashboardCreateFlags.name, "name", "n", "", "Name of the dashboard")
+	dashboardCreateCmd.Flags().StringVarP(&dashboardCreateFlags.description, "description", "d", "", "Description of the dashboard")
+	dashboardCreateCmd.Flags().StringVarP(&dashboardCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the dashboard")
+	dashboardCreateCmd.MarkFlagRequired("name")
+	dashboardCreateCmd.MarkFlagRequired("org-id")
+
+	dashboardCmd.AddCommand(dashboardCreateCmd)
+}
+
+func dashboardCreateF(cmd *cobra.Command, args []string) error {
+	s, err := newDashboardServices(flags)
+	if err != nil {
+		return err
+	}
+
+	d := &platform.Dashboard{
+		Name:        dashboardCreateFlags.name,
+		Description: dashboardCreateFlags.description,
+	}
+	if err := d.OrganizationID.DecodeFromString(dashboardCreateFlags.orgID); err != nil {
+		return fmt.Errorf("error parsing organization id: %v", err)
+	}
+
+	if err := s.dashboards.CreateDashboard(context.Background(), d); err != nil {
+		return err
+	}
+
+	writeDashboards([]*platform.Dashboard{d}, false)
+	return nil
+}
+
+// DashboardFindFlags define the Find Command
+type DashboardFindFlags struct {
+	ids   []string
+	org   string
+	orgID string
+}
+
+var dashboardFindFlags DashboardFindFlags
+
+func init() {
+	dashboardFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find dashboards",
+		RunE:  dashboardFindF,
+	}
+
+	dashboardFindCmd.Flags().StringSliceVarP(&dashboardFindFlags.ids, "id", "i", nil, "The dashboard IDs")
+	dashboardFindCmd.Flags().StringVarP(&dashboardFindFlags.org, "org", "o", "", "The dashboard organization name")
+	dashboardFindCmd.Flags().StringVarP(&dashboardFindFlags.orgID, "org-id", "", "", "The dashboard organization ID")
+
+	dashboardCmd.AddCommand(dashboardFindCmd)
+}
+
+func dashboardFindF(cmd *cobra.Command, args []string) error {
+	if dashboardFindFlags.org != "" && dashboardFindFlags.orgID != "" {
+		return fmt.Errorf("must specify at most one of org or org-id")
+	}
+
+	s, err := newDashboardServices(flags)
+	if err != nil {
+		return err
+	}
+
+	filter := platform.DashboardFilter{}
+	for _, i := range dashboardFindFlags.ids {
+		id, err := platform.IDFromString(i)
+		if err != nil {
+			return err
+		}
+		filter.IDs = append(filter.IDs, id)
+	}
+	if dashboardFindFlags.orgID != "" {
+		orgID, err := platform.IDFromString(dashboardFindFlags.orgID)
+		if err != nil {
+			return err
+		}
+		filter.OrganizationID = orgID
+	}
+	if dashboardFindFlags.org != "" {
+		filter.Organization = &dashboardFindFlags.org
+	}
+
+	ds, _, err := s.dashboards.FindDashboards(context.Background(), filter, platform.DefaultDashboardFindOptions)
+	if err != nil {
+		return err
+	}
+
+	writeDashboards(ds, false)
+	return nil
+}
+
+// DashboardUpdateFlags define the Update Command
+type DashboardUpdateFlags struct {
+	id          string
+	name        string
+	description string
+}
+
+var dashboardUpdateFlags DashboardUpdateFlags
+
+func init() {
+	dashboardUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update dashboard",
+		RunE:  dashboardUpdateF,
+	}
+
+	dashboardUpdateCmd.Flags().StringVarP(&dashboardUpdateFlags.id, "id", "i", "", "The dashboard ID (required)")
+	dashboardUpdateCmd.Flags().StringVarP(&dashboardUpdateFlags.name, "name", "n", "", "New dashboard name")
+	dashboardUpdateCmd.Flags().StringVarP(&dashboardUpdateFlags.description, "description", "d", "", "New dashboard description")
+	dashboardUpdateCmd.MarkFlagRequired("id")
+
+	dashboardCmd.AddCommand(dashboardUpdateCmd)
+}
+
+func dashboardUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newDashboardServices(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(dashboardUpdateFlags.id); err != nil {
+		return err
+	}
+
+	update := platform.DashboardUpdate{}
+	if cmd.Flags().Changed("name") {
+		update.Name = &dashboardUpdateFlags.name
+	}
+	if cmd.Flags().Changed("description") {
+		update.Description = &dashboardUpdateFlags.description
+	}
+
+	d, err := s.dashboards.UpdateDashboard(context.Background(), id, update)
+	if err != nil {
+		return err
+	}
+
+	writeDashboards([]*platform.Dashboard{d}, false)
+	return nil
+}
+
+// DashboardDeleteFlags define the Delete command
+type DashboardDeleteFlags struct {
+	id string
+}
+
+var dashboardDeleteFlags DashboardDeleteFlags
+
+func init() {
+	dashboardDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete dashboard",
+		RunE:  dashboardDeleteF,
+	}
+
+	dashboardDeleteCmd.Flags().StringVarP(&dashboardDeleteFlags.id, "id", "i", "", "The dashboard ID (required)")
+	dashboardDeleteCmd.MarkFlagRequired("id")
+
+	dashboardCmd.AddCommand(dashboardDeleteCmd)
+}
+
+func dashboardDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newDashboardServices(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(dashboardDeleteFlags.id); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	d, err := s.dashboards.FindDashboardByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.dashboards.DeleteDashboard(ctx, id); err != nil {
+		return err
+	}
+
+	writeDashboards([]*platform.Dashboard{d}, true)
+	return nil
+}
+
+// writeDashboards writes the dashboards, marked as deleted if deleted is set.
+func writeDashboards(ds []*platform.Dashboard, deleted bool) {
+	headers := []string{
+		"ID",
+		"Name",
+		"Description",
+		"OrganizationID",
+		"Cells",
+	}
+	if deleted {
+		headers = append(headers, "Deleted")
+	}
+
+	w := newWriter(os.Stdout)
+	w.WriteHeaders(headers...)
+	for _, d := range ds {
+		w.Write(map[string]interface{}{
+			"ID":             d.ID.String(),
+			"Name":           d.Name,
+			"Description":    d.Description,
+			"OrganizationID": d.OrganizationID.String(),
+			"Cells":          len(d.Cells),
+			"Deleted":        deleted,
+		})
+	}
+	w.Flush()
+}
+
+// DashboardExportFlags define the Export Command
+type DashboardExportFlags struct {
+	id   string
+	file string
+}
+
+var dashboardExportFlags DashboardExportFlags
+
+func init() {
+	dashboardExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export dashboard to a template",
+		RunE:  dashboardExportF,
+	}
+
+	dashboardExportCmd.Flags().StringVarP(&dashboardExportFlags.id, "id", "i", "", "The dashboard ID")
+	dashboardExportCmd.Flags().StringVarP(&dashboardExportFlags.file, "file", "f", "", "File to write the template to; defaults to stdout")
+	dashboardExportCmd.MarkFlagRequired("id")
+
+	dashboardCmd.AddCommand(dashboardExportCmd)
+}
+
+func dashboardExportF(cmd *cobra.Command, args []string) error {
+	s, err := newDashboardServices(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(dashboardExportFlags.id); err != nil {
+		return err
+	}
+
+	t, err := s.templates.ExportDashboard(context.Background(), id)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if dashboardExportFlags.file != "" {
+		f, err := os.Create(dashboardExportFlags.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+// DashboardImportFlags define the Import Command
+type DashboardImportFlags struct {
+	org     string
+	orgID   string
+	file    string
+	buckets []string
+}
+
+var dashboardImportFlags DashboardImportFlags
+
+func init() {
+	dashboardImportCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create dashboard from a template",
+		RunE:  dashboardImportF,
+	}
+
+	dashboardImportCmd.Flags().StringVarP(&dashboardImportFlags.org, "org", "o", "", "Name of the organization to create the dashboard in")
+	dashboardImportCmd.Flags().StringVarP(&dashboardImportFlags.orgID, "org-id", "", "", "The ID of the organization to create the dashboard in")
+	dashboardImportCmd.Flags().StringVarP(&dashboardImportFlags.file, "file", "f", "", "File to read the template from; defaults to stdin")
+	dashboardImportCmd.Flags().StringSliceVarP(&dashboardImportFlags.buckets, "bucket", "b", nil, "Bucket of the template to replace by a bucket of the organization, as template=organization")
+
+	dashboardCmd.AddCommand(dashboardImportCmd)
+}
+
+func dashboardImportF(cmd *cobra.Command, args []string) error {
+	if (dashboardImportFlags.org == "") == (dashboardImportFlags.orgID == "") {
+		return fmt.Errorf("must specify exactly one of org or org-id")
+	}
+
+	opts := platform.ImportDashboardOptions{
+		BucketNames: make(map[string]string, len(dashboardImportFlags.buckets)),
+	}
+	for _, b := range dashboardImportFlags.buckets {
+		parts := strings.SplitN(b, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid bucket %q: must be template=organization", b)
+		}
+		opts.BucketNames[parts[0]] = parts[1]
+	}
+
+	var r io.Reader = os.Stdin
+	if dashboardImportFlags.file != "" {
+		f, err := os.Open(dashboardImportFlags.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var t platform.DashboardTemplate
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return fmt.Errorf("error decoding template: %v", err)
+	}
+
+	s, err := newDashboardServices(flags)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var orgID platform.ID
+	if dashboardImportFlags.orgID != "" {
+		if err := orgID.DecodeFromString(dashboardImportFlags.orgID); err != nil {
+			return fmt.Errorf("error parsing organization id: %v", err)
+		}
+	} else {
+		o, err := s.organizations.FindOrganization(ctx, platform.OrganizationFilter{Name: &dashboardImportFlags.org})
+		if err != nil {
+			return err
+		}
+		orgID = o.ID
+	}
+
+	d, err := s.templates.ImportDashboard(ctx, orgID, &t, opts)
+	if err != nil {
+		return err
+	}
+
+	writeDashboards([]*platform.Dashboard{d}, false)
+	return nil
+}
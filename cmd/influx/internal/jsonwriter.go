@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonWriter struct {
+	writer  io.Writer
+	headers []string
+	rows    []map[string]interface{}
+}
+
+// NewJSONWriter returns a Writer that writes the rows as a JSON array of
+// objects keyed by column.
+func NewJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{
+		writer: w,
+		rows:   []map[string]interface{}{},
+	}
+}
+
+func (w *jsonWriter) WriteHeaders(h ...string) {
+	w.headers = h
+}
+
+func (w *jsonWriter) Write(m map[string]interface{}) {
+	row := make(map[string]interface{}, len(w.headers))
+	for _, h := range w.headers {
+		row[h] = m[h]
+	}
+	w.rows = append(w.rows, row)
+}
+
+func (w *jsonWriter) Flush() {
+	enc := json.NewEncoder(w.writer)
+	enc.SetIndent("", "  ")
+	enc.Encode(w.rows)
+	w.rows = w.rows[:0]
+}
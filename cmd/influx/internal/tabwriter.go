@@ -9,11 +9,22 @@ import (
 	platform "github.com/influxdata/influxdb"
 )
 
+// Writer writes rows of named columns.
+type Writer interface {
+	// WriteHeaders sets the columns of the rows.
+	WriteHeaders(h ...string)
+	// Write writes the row m, keyed by column.
+	Write(m map[string]interface{})
+	// Flush writes the buffered rows.
+	Flush()
+}
+
 type tabWriter struct {
 	writer  *tabwriter.Writer
 	headers []string
 }
 
+// NewTabWriter returns a Writer that writes the rows aligned in columns.
 func NewTabWriter(w io.Writer) *tabWriter {
 	return &tabWriter{
 		writer: tabwriter.NewWriter(w, 0, 8, 1, '\t', 0),
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Label Command
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Label management commands",
+	Run:   labelF,
+}
+
+func labelF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+// labelResourceTypes are the types of the resources that have labels, as
+// named in the API paths.
+var labelResourceTypes = []string{
+	"buckets",
+	"dashboards",
+	"orgs",
+	"tasks",
+	"telegrafs",
+	"views",
+}
+
+func newLabelService(f Flags, resourceType string) (platform.LabelService, error) {
+	valid := false
+	for _, t := range labelResourceTypes {
+		valid = valid || t == resourceType
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid resource type %q: must be one of %s", resourceType, strings.Join(labelResourceTypes, ", "))
+	}
+
+	if flags.local {
+		c, err := newLocalClient()
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return &http.LabelService{
+		Addr:     flags.host,
+		Token:    flags.token,
+		BasePath: "/api/v2/" + resourceType,
+	}, nil
+}
+
+// parseLabelProperties parses the key=value properties of a label.
+func parseLabelProperties(ps []string) (map[string]string, error) {
+	m := make(map[string]string, len(ps))
+	for _, p := range ps {
+		parts := strings.SplitN(p, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid property %q: must be key=value", p)
+		}
+		m[parts[0]] = parts[1]
+	}
+	return m, nil
+}
+
+// LabelFlags define the label and the resource it is attached to, shared by
+// the label commands.
+type LabelFlags struct {
+	resourceType string
+	resourceID   string
+	name         string
+}
+
+func (f *LabelFlags) register(cmd *cobra.Command, nameRequired bool) {
+	cmd.Flags().StringVarP(&f.resourceType, "resource-type", "", "", fmt.Sprintf("Type of the labeled resource: %s (required)", strings.Join(labelResourceTypes, ", ")))
+	cmd.Flags().StringVarP(&f.resourceID, "resource-id", "", "", "The ID of the labeled resource (required)")
+	cmd.Flags().StringVarP(&f.name, "name", "n", "", "Name of the label")
+	cmd.MarkFlagRequired("resource-type")
+	cmd.MarkFlagRequired("resource-id")
+	if nameRequired {
+		cmd.MarkFlagRequired("name")
+	}
+}
+
+// LabelCreateFlags define the Create Command
+type LabelCreateFlags struct {
+	LabelFlags
+	properties []string
+}
+
+var labelCreateFlags LabelCreateFlags
+
+func init() {
+	labelCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Add a label to a resource",
+		RunE:  labelCreateF,
+	}
+
+	labelCreateFlags.register(labelCreateCmd, true)
+	labelCreateCmd.Flags().StringSliceVarP(&labelCreateFlags.properties, "property", "p", nil, "Property of the label, as key=value")
+
+	labelCmd.AddCommand(labelCreateCmd)
+}
+
+func labelCreateF(cmd *cobra.Command, args []string) error {
+	s, err := newLabelService(flags, labelCreateFlags.resourceType)
+	if err != nil {
+		return err
+	}
+
+	l := &platform.Label{
+		Name: labelCreateFlags.name,
+	}
+	if err := l.ResourceID.DecodeFromString(labelCreateFlags.resourceID); err != nil {
+		return fmt.Errorf("error parsing resource id: %v", err)
+	}
+	if l.Properties, err = parseLabelProperties(labelCreateFlags.properties); err != nil {
+		return err
+	}
+
+	if err := s.CreateLabel(context.Background(), l); err != nil {
+		return err
+	}
+
+	writeLabels([]*platform.Label{l}, false)
+	return nil
+}
+
+var labelFindFlags LabelFlags
+
+func init() {
+	labelFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find the labels of a resource",
+		RunE:  labelFindF,
+	}
+
+	labelFindFlags.register(labelFindCmd, false)
+
+	labelCmd.AddCommand(labelFindCmd)
+}
+
+func labelFindF(cmd *cobra.Command, args []string) error {
+	s, err := newLabelService(flags, labelFindFlags.resourceType)
+	if err != nil {
+		return err
+	}
+
+	filter := platform.LabelFilter{
+		Name: labelFindFlags.name,
+	}
+	if err := filter.ResourceID.DecodeFromString(labelFindFlags.resourceID); err != nil {
+		return fmt.Errorf("error parsing resource id: %v", err)
+	}
+
+	ls, err := s.FindLabels(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	writeLabels(ls, false)
+	return nil
+}
+
+// LabelUpdateFlags define the Update Command
+type LabelUpdateFlags struct {
+	LabelFlags
+	properties []string
+}
+
+var labelUpdateFlags LabelUpdateFlags
+
+func init() {
+	labelUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the properties of a label of a resource",
+		RunE:  labelUpdateF,
+	}
+
+	labelUpdateFlags.register(labelUpdateCmd, true)
+	labelUpdateCmd.Flags().StringSliceVarP(&labelUpdateFlags.properties, "property", "p", nil, "New property of the label, as key=value")
+	labelUpdateCmd.MarkFlagRequired("property")
+
+	labelCmd.AddCommand(labelUpdateCmd)
+}
+
+func labelUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newLabelService(flags, labelUpdateFlags.resourceType)
+	if err != nil {
+		return err
+	}
+
+	l := &platform.Label{
+		Name: labelUpdateFlags.name,
+	}
+	if err := l.ResourceID.DecodeFromString(labelUpdateFlags.resourceID); err != nil {
+		return fmt.Errorf("error parsing resource id: %v", err)
+	}
+	update := platform.LabelUpdate{}
+	if update.Properties, err = parseLabelProperties(labelUpdateFlags.properties); err != nil {
+		return err
+	}
+
+	l, err = s.UpdateLabel(context.Background(), l, update)
+	if err != nil {
+		return err
+	}
+
+	writeLabels([]*platform.Label{l}, false)
+	return nil
+}
+
+var labelDeleteFlags LabelFlags
+
+func init() {
+	labelDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Remove a label from a resource",
+		RunE:  labelDeleteF,
+	}
+
+	labelDeleteFlags.register(labelDeleteCmd, true)
+
+	labelCmd.AddCommand(labelDeleteCmd)
+}
+
+func labelDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newLabelService(flags, labelDeleteFlags.resourceType)
+	if err != nil {
+		return err
+	}
+
+	l := platform.Label{
+		Name: labelDeleteFlags.name,
+	}
+	if err := l.ResourceID.DecodeFromString(labelDeleteFlags.resourceID); err != nil {
+		return fmt.Errorf("error parsing resource id: %v", err)
+	}
+
+	if err := s.DeleteLabel(context.Background(), l); err != nil {
+		return err
+	}
+
+	writeLabels([]*platform.Label{&l}, true)
+	return nil
+}
+
+// writeLabels writes the labels, marked as deleted if deleted is set.
+func writeLabels(ls []*platform.Label, deleted bool) {
+	headers := []string{
+		"ResourceID",
+		"Name",
+		"Properties",
+	}
+	if deleted {
+		headers = append(headers, "Deleted")
+	}
+
+	w := newWriter(os.Stdout)
+	w.WriteHeaders(headers...)
+	for _, l := range ls {
+		w.Write(map[string]interface{}{
+			"ResourceID": l.ResourceID.String(),
+			"Name":       l.Name,
+			"Properties": l.Properties,
+			"Deleted":    deleted,
+		})
+	}
+	w.Flush()
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Macro Command
+var macroCmd = &cobra.Command{
+	Use:   "macro",
+	Short: "Macro management commands",
+	Run:   macroF,
+}
+
+func macroF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newMacroService(f Flags) (platform.MacroService, error) {
+	if flags.local {
+		c, err := newLocalClient()
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return &http.MacroService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+// newMacroArguments returns the arguments of a macro of type typ, and its
+// default selected values, the first value.
+//
+// The values of a constant macro are the values, the values of a map macro
+// are key=value pairs and a query macro has a single value, its query.
+func newMacroArguments(typ string, values []string, language string) (*platform.MacroArguments, []string, error) {
+	if len(values) == 0 {
+		return nil, nil, fmt.Errorf("must specify at least one value")
+	}
+
+	switch typ {
+	case "constant":
+		return &platform.MacroArguments{
+			Type:   typ,
+			Values: platform.MacroConstantValues(values),
+		}, values[:1], nil
+	case "map":
+		m := make(platform.MacroMapValues, len(values))
+		keys := make([]string, 0, len(values))
+		for _, v := range values {
+			parts := strings.SplitN(v, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return nil, nil, fmt.Errorf("invalid value %q: must be key=value", v)
+			}
+			m[parts[0]] = parts[1]
+			keys = append(keys, parts[0])
+		}
+		sort.Strings(keys)
+		return &platform.MacroArguments{
+			Type:   typ,
+			Values: m,
+		}, keys[:1], nil
+	case "query":
+		if len(values) != 1 {
+			return nil, nil, fmt.Errorf("a query macro must have exactly one value, its query")
+		}
+		return &platform.MacroArguments{
+			Type: typ,
+			Values: platform.MacroQueryValues{
+				Query:    values[0],
+				Language: language,
+			},
+		}, nil, nil
+	}
+	return nil, nil, fmt.Errorf("invalid macro type %q: must be constant, map or query", typ)
+}
+
+// MacroCreateFlags define the Create Command
+type MacroCreateFlags struct {
+	name     string
+	orgID    string
+	typ      string
+	values   []string
+	language string
+	selected []string
+}
+
+var macroCreateFlags MacroCreateFlags
+
+func init() {
+	macroCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create macro",
+		RunE:  macroCreateF,
+	}
+
+	macroCreateCmd.Flags().StringVarP(&macroCreateFlags.name, "name", "n", "", "Name of the macro")
+	macroCreateCmd.Flags().StringVarP(&macroCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the macro")
+	macroCreateCmd.Flags().StringVarP(&macroCreateFlags.typ, "type", "", "constant", "Type of the macro: constant, map or query")
+	macroCreateCmd.Flags().StringSliceVarP(&macroCreateFlags.values, "value", "v", nil, "Value of the macro; key=value for map macros, the query for query macros")
+	macroCreateCmd.Flags().StringVarP(&macroCreateFlags.language, "language", "l", "flux", "Language of the query of a query macro")
+	macroCreateCmd.Flags().StringSliceVarP(&macroCreateFlags.selected, "selected", "s", nil, "Selected values of the macro; defaults to the first value")
+	macroCreateCmd.MarkFlagRequired("name")
+	macroCreateCmd.MarkFlagRequired("org-id")
+	macroCreateCmd.MarkFlagRequired("value")
+
+	macroCmd.AddCommand(macroCreateCmd)
+}
+
+func macroCreateF(cmd *cobra.Command, args []string) error {
+	arguments, selected, err := newMacroArguments(macroCreateFlags.typ, macroCreateFlags.values, macroCreateFlags.language)
+	if err != nil {
+		return err
+	}
+	if len(macroCreateFlags.selected) > 0 {
+		selected = macroCreateFlags.selected
+	}
+
+	m := &platform.Macro{
+		Name:      macroCreateFlags.name,
+		Selected:  selected,
+		Arguments: arguments,
+	}
+	if err := m.OrganizationID.DecodeFromString(macroCreateFlags.orgID); err != nil {
+		return fmt.Errorf("error parsing organization id: %v", err)
+	}
+
+	s, err := newMacroService(flags)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CreateMacro(context.Background(), m); err != nil {
+		return err
+	}
+
+	writeMacros([]*platform.Macro{m}, false)
+	return nil
+}
+
+// MacroFindFlags define the Find Command
+type MacroFindFlags struct {
+	id    string
+	org   string
+	orgID string
+}
+
+var macroFindFlags MacroFindFlags
+
+func init() {
+	macroFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find macros",
+		RunE:  macroFindF,
+	}
+
+	macroFindCmd.Flags().StringVarP(&macroFindFlags.id, "id", "i", "", "The macro ID")
+	macroFindCmd.Flags().StringVarP(&macroFindFlags.org, "org", "o", "", "The macro organization name")
+	macroFindCmd.Flags().StringVarP(&macroFindFlags.orgID, "org-id", "", "", "The macro organization ID")
+
+	macroCmd.AddCommand(macroFindCmd)
+}
+
+func macroFindF(cmd *cobra.Command, args []string) error {
+	if macroFindFlags.org != "" && macroFindFlags.orgID != "" {
+		return fmt.Errorf("must specify at most one of org or org-id")
+	}
+
+	s, err := newMacroService(flags)
+	if err != nil {
+		return err
+	}
+
+	filter := platform.MacroFilter{}
+	if macroFindFlags.id != "" {
+		id, err := platform.IDFromString(macroFindFlags.id)
+		if err != nil {
+			return err
+		}
+		filter.ID = id
+	}
+	if macroFindFlags.orgID != "" {
+		orgID, err := platform.IDFromString(macroFindFlags.orgID)
+		if err != nil {
+			return err
+		}
+		filter.OrganizationID = orgID
+	}
+	if macroFindFlags.org != "" {
+		filter.Organization = &macroFindFlags.org
+	}
+
+	ms, err := s.FindMacros(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	writeMacros(ms, false)
+	return nil
+}
+
+// MacroUpdateFlags define the Update Command
+type MacroUpdateFlags struct {
+	id       string
+	name     string
+	typ      string
+	values   []string
+	language string
+	selected []string
+}
+
+var macroUpdateFlags MacroUpdateFlags
+
+func init() {
+	macroUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update macro",
+		RunE:  macroUpdateF,
+	}
+
+	macroUpdateCmd.Flags().StringVarP(&macroUpdateFlags.id, "id", "i", "", "The macro ID (required)")
+	macroUpdateCmd.Flags().StringVarP(&macroUpdateFlags.name, "name", "n", "", "New macro name")
+	macroUpdateCmd.Flags().StringVarP(&macroUpdateFlags.typ, "type", "", "constant", "Type of the new values: constant, map or query")
+	macroUpdateCmd.Flags().StringSliceVarP(&macroUpdateFlags.values, "value", "v", nil, "New value of the macro; key=value for map macros, the query for query macros")
+	macroUpdateCmd.Flags().StringVarP(&macroUpdateFlags.language, "language", "l", "flux", "Language of the query of a query macro")
+	macroUpdateCmd.Flags().StringSliceVarP(&macroUpdateFlags.selected, "selected", "s", nil, "New selected values of the macro")
+	macroUpdateCmd.MarkFlagRequired("id")
+
+	macroCmd.AddCommand(macroUpdateCmd)
+}
+
+func macroUpdateF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(macroUpdateFlags.id); err != nil {
+		return err
+	}
+
+	update := &platform.MacroUpdate{
+		Name:     macroUpdateFlags.name,
+		Selected: macroUpdateFlags.selected,
+	}
+	if len(macroUpdateFlags.values) > 0 {
+		arguments, selected, err := newMacroArguments(macroUpdateFlags.typ, macroUpdateFlags.values, macroUpdateFlags.language)
+		if err != nil {
+			return err
+		}
+		update.Arguments = arguments
+		if update.Selected == nil {
+			update.Selected = selected
+		}
+	}
+
+	s, err := newMacroService(flags)
+	if err != nil {
+		return err
+	}
+
+	m, err := s.UpdateMacro(context.Background(), id, update)
+	if err != nil {
+		return err
+	}
+
+	writeMacros([]*platform.Macro{m}, false)
+	return nil
+}
+
+// MacroDeleteFlags define the Delete command
+type MacroDeleteFlags struct {
+	id string
+}
+
+var macroDeleteFlags MacroDeleteFlags
+
+func init() {
+	macroDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete macro",
+		RunE:  macroDeleteF,
+	}
+
+	macroDeleteCmd.Flags().StringVarP(&macroDeleteFlags.id, "id", "i", "", "The macro ID (required)")
+	macroDeleteCmd.MarkFlagRequired("id")
+
+	macroCmd.AddCommand(macroDeleteCmd)
+}
+
+func macroDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newMacroService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(macroDeleteFlags.id); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	m, err := s.FindMacroByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DeleteMacro(ctx, id); err != nil {
+		return err
+	}
+
+	writeMacros([]*platform.Macro{m}, true)
+	return nil
+}
+
+// writeMacros writes the macros, marked as deleted if deleted is set.
+func writeMacros(ms []*platform.Macro, deleted bool) {
+	headers := []string{
+		"ID",
+		"Name",
+		"OrganizationID",
+		"Type",
+		"Selected",
+	}
+	if deleted {
+		headers = append(headers, "Deleted")
+	}
+
+	w := newWriter(os.Stdout)
+	w.WriteHeaders(headers...)
+	for _, m := range ms {
+		var typ string
+		if m.Arguments != nil {
+			typ = m.Arguments.Type
+		}
+		w.Write(map[string]interface{}{
+			"ID":             m.ID.String(),
+			"Name":           m.Name,
+			"OrganizationID": m.OrganizationID.String(),
+			"Type":           typ,
+			"Selected":       m.Selected,
+			"Deleted":        deleted,
+		})
+	}
+	w.Flush()
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -24,11 +28,18 @@ var influxCmd = &cobra.Command{
 func init() {
 	influxCmd.AddCommand(authorizationCmd)
 	influxCmd.AddCommand(bucketCmd)
+	influxCmd.AddCommand(dashboardCmd)
+	influxCmd.AddCommand(labelCmd)
+	influxCmd.AddCommand(macroCmd)
 	influxCmd.AddCommand(organizationCmd)
 	influxCmd.AddCommand(queryCmd)
 	influxCmd.AddCommand(replCmd)
+	influxCmd.AddCommand(scraperCmd)
+	influxCmd.AddCommand(secretCmd)
 	influxCmd.AddCommand(setupCmd)
+	influxCmd.AddCommand(sourceCmd)
 	influxCmd.AddCommand(taskCmd)
+	influxCmd.AddCommand(telegrafCmd)
 	influxCmd.AddCommand(userCmd)
 	influxCmd.AddCommand(writeCmd)
 }
@@ -38,6 +49,7 @@ type Flags struct {
 	token string
 	host  string
 	local bool
+	json  bool
 }
 
 var flags Flags
@@ -80,6 +92,7 @@ func init() {
 	}
 
 	influxCmd.PersistentFlags().BoolVar(&flags.local, "local", false, "Run commands locally against the filesystem")
+	influxCmd.PersistentFlags().BoolVar(&flags.json, "json", false, "Output the results as JSON")
 
 	// Override help on all the commands tree
 	walk(influxCmd, func(c *cobra.Command) {
@@ -87,6 +100,30 @@ func init() {
 	})
 }
 
+// newWriter returns the writer of the results of the commands, a JSON
+// writer with --json and a tab writer otherwise.
+func newWriter(w io.Writer) internal.Writer {
+	if flags.json {
+		return internal.NewJSONWriter(w)
+	}
+	return internal.NewTabWriter(w)
+}
+
+// newLocalClient opens the bolt file, for the commands run with --local.
+// The client implements all the services the commands use.
+func newLocalClient() (*bolt.Client, error) {
+	boltFile, err := fs.BoltFile()
+	if err != nil {
+		return nil, err
+	}
+	c := bolt.NewClient()
+	c.Path = boltFile
+	if err := c.Open(context.Background()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func influxF(cmd *cobra.Command, args []string) {
 	cmd.Usage()
 }
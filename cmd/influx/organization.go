@@ -7,7 +7,6 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
-	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/spf13/cobra"
@@ -82,7 +81,7 @@ func organizationCreateF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -142,7 +141,7 @@ func organizationFindF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -202,7 +201,7 @@ func organizationUpdateF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -246,7 +245,7 @@ func organizationDeleteF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -344,7 +343,7 @@ func organizationMembersListF(cmd *cobra.Command, args []string) {
 	}
 
 	// TODO: look up each user and output their name
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 	)
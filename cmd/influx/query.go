@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/dialect"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -21,7 +28,8 @@ or execute a literal Flux query contained in a file by specifying the file prefi
 }
 
 var queryFlags struct {
-	OrgID string
+	OrgID  string
+	Format string
 }
 
 func init() {
@@ -31,6 +39,7 @@ func init() {
 		queryFlags.OrgID = h
 	}
 	queryCmd.MarkPersistentFlagRequired("org-id")
+	queryCmd.PersistentFlags().StringVar(&queryFlags.Format, "format", "", "Print the raw results as csv, json, arrow or lp instead of tables")
 }
 
 func fluxQueryF(cmd *cobra.Command, args []string) {
@@ -52,6 +61,14 @@ func fluxQueryF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if queryFlags.Format != "" {
+		if err := fluxQueryRaw(q, orgID, queryFlags.Format); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	r, err := getFluxREPL(flags.host, flags.token, orgID)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -63,3 +80,37 @@ func fluxQueryF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 }
+
+// fluxQueryRaw runs the query and copies the response, encoded in the given
+// format, to stdout.
+func fluxQueryRaw(q string, orgID platform.ID, format string) error {
+	var d flux.Dialect
+	switch format {
+	case "csv":
+		d = csv.DefaultDialect()
+	case dialect.JSONDialectType:
+		d = new(dialect.JSONDialect)
+	case dialect.ArrowDialectType:
+		d = new(dialect.ArrowDialect)
+	case dialect.LineProtocolDialectType:
+		d = new(dialect.LineProtocolDialect)
+	default:
+		return fmt.Errorf("unknown format %q: must be one of csv, json, arrow or lp", format)
+	}
+
+	s := &http.FluxService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			OrganizationID: orgID,
+			Compiler: lang.FluxCompiler{
+				Query: q,
+			},
+		},
+		Dialect: d,
+	}
+	_, err := s.Query(context.Background(), os.Stdout, req)
+	return err
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Scraper Command
+var scraperCmd = &cobra.Command{
+	Use:   "scraper",
+	Short: "Scraper target management commands",
+	Run:   scraperF,
+}
+
+func scraperF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newScraperService(f Flags) (platform.ScraperTargetStoreService, error) {
+	if flags.local {
+		c, err := newLocalClient()
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return &http.ScraperService{
+		Addr:     flags.host,
+		Token:    flags.token,
+		OpPrefix: bolt.OpPrefix,
+	}, nil
+}
+
+// ScraperCreateFlags define the Create Command
+type ScraperCreateFlags struct {
+	name     string
+	typ      string
+	url      string
+	orgID    string
+	bucketID string
+}
+
+var scraperCreateFlags ScraperCreateFlags
+
+func init() {
+	scraperCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create scraper target",
+		RunE:  scraperCreateF,
+	}
+
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.name, "name", "n", "", "Name of the scraper target")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.typ, "type", "", platform.PrometheusScraperType, "Type of the scraper target")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.url, "url", "u", "", "URL of the metrics to scrape")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.orgID, "org-id", "", "", "The ID of the organization to write the metrics to")
+	scraperCreateCmd.Flags().StringVarP(&scraperCreateFlags.bucketID, "bucket-id", "", "", "The ID of the bucket to write the metrics to")
+	scraperCreateCmd.MarkFlagRequired("name")
+	scraperCreateCmd.MarkFlagRequired("url")
+	scraperCreateCmd.MarkFlagRequired("org-id")
+	scraperCreateCmd.MarkFlagRequired("bucket-id")
+
+	scraperCmd.AddCommand(scraperCreateCmd)
+}
+
+func scraperCreateF(cmd *cobra.Command, args []string) error {
+	if !platform.ValidScraperType(scraperCreateFlags.typ) {
+		return fmt.Errorf("invalid scraper type %q", scraperCreateFlags.typ)
+	}
+
+	t := &platform.ScraperTarget{
+		Name: scraperCreateFlags.name,
+		Type: platform.ScraperType(scraperCreateFlags.typ),
+		URL:  scraperCreateFlags.url,
+	}
+	if err := t.OrgID.DecodeFromString(scraperCreateFlags.orgID); err != nil {
+		return fmt.Errorf("error parsing organization id: %v", err)
+	}
+	if err := t.BucketID.DecodeFromString(scraperCreateFlags.bucketID); err != nil {
+		return fmt.Errorf("error parsing bucket id: %v", err)
+	}
+
+	s, err := newScraperService(flags)
+	if err != nil {
+		return err
+	}
+
+	if err := s.AddTarget(context.Background(), t); err != nil {
+		return err
+	}
+
+	writeScraperTargets([]platform.ScraperTarget{*t}, false)
+	return nil
+}
+
+// ScraperFindFlags define the Find Command
+type ScraperFindFlags struct {
+	id string
+}
+
+var scraperFindFlags ScraperFindFlags
+
+func init() {
+	scraperFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find scraper targets",
+		RunE:  scraperFindF,
+	}
+
+	scraperFindCmd.Flags().StringVarP(&scraperFindFlags.id, "id", "i", "", "The scraper target ID")
+
+	scraperCmd.AddCommand(scraperFindCmd)
+}
+
+func scraperFindF(cmd *cobra.Command, args []string) error {
+	s, err := newScraperService(flags)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if scraperFindFlags.id == "" {
+		ts, err := s.ListTargets(ctx)
+		if err != nil {
+			return err
+		}
+		writeScraperTargets(ts, false)
+		return nil
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(scraperFindFlags.id); err != nil {
+		return err
+	}
+	t, err := s.GetTargetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	writeScraperTargets([]platform.ScraperTarget{*t}, false)
+	return nil
+}
+
+// ScraperUpdateFlags define the Update Command
+type ScraperUpdateFlags struct {
+	id       string
+	name     string
+	url      string
+	bucketID string
+}
+
+var scraperUpdateFlags ScraperUpdateFlags
+
+func init() {
+	scraperUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update scraper target",
+		RunE:  scraperUpdateF,
+	}
+
+	scraperUpdateCmd.Flags().StringVarP(&scraperUpdateFlags.id, "id", "i", "", "The scraper target ID (required)")
+	scraperUpdateCmd.Flags().StringVarP(&scraperUpdateFlags.name, "name", "n", "", "New scraper target name")
+	scraperUpdateCmd.Flags().StringVarP(&scraperUpdateFlags.url, "url", "u", "", "New URL of the metrics to scrape")
+	scraperUpdateCmd.Flags().StringVarP(&scraperUpdateFlags.bucketID, "bucket-id", "", "", "The ID of the new bucket to write the metrics to")
+	scraperUpdateCmd.MarkFlagRequired("id")
+
+	scraperCmd.AddCommand(scraperUpdateCmd)
+}
+
+func scraperUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newScraperService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(scraperUpdateFlags.id); err != nil {
+		return err
+	}
+
+	// The update replaces the target, so it starts from the current one.
+	ctx := context.Background()
+	t, err := s.GetTargetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if scraperUpdateFlags.name != "" {
+		t.Name = scraperUpdateFlags.name
+	}
+	if scraperUpdateFlags.url != "" {
+		t.URL = scraperUpdateFlags.url
+	}
+	if scraperUpdateFlags.bucketID != "" {
+		if err := t.BucketID.DecodeFromString(scraperUpdateFlags.bucketID); err != nil {
+			return fmt.Errorf("error parsing bucket id: %v", err)
+		}
+	}
+
+	t, err = s.UpdateTarget(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	writeScraperTargets([]platform.ScraperTarget{*t}, false)
+	return nil
+}
+
+// ScraperDeleteFlags define the Delete command
+type ScraperDeleteFlags struct {
+	id string
+}
+
+var scraperDeleteFlags ScraperDeleteFlags
+
+func init() {
+	scraperDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete scraper target",
+		RunE:  scraperDeleteF,
+	}
+
+	scraperDeleteCmd.Flags().StringVarP(&scraperDeleteFlags.id, "id", "i", "", "The scraper target ID (required)")
+	scraperDeleteCmd.MarkFlagRequired("id")
+
+	scraperCmd.AddCommand(scraperDeleteCmd)
+}
+
+func scraperDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newScraperService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(scraperDeleteFlags.id); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	t, err := s.GetTargetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.RemoveTarget(ctx, id); err != nil {
+		return err
+	}
+
+	writeScraperTargets([]platform.ScraperTarget{*t}, true)
+	return nil
+}
+
+// writeScraperTargets writes the scraper targets, marked as deleted if
+// deleted is set.
+func writeScraperTargets(ts []platform.ScraperTarget, deleted bool) {
+	headers := []string{
+		"ID",
+		"Name",
+		"Type",
+		"URL",
+		"OrganizationID",
+		"BucketID",
+	}
+	if deleted {
+		headers = append(headers, "Deleted")
+	}
+
+	w := newWriter(os.Stdout)
+	w.WriteHeaders(headers...)
+	for _, t := range ts {
+		w.Write(map[string]interface{}{
+			"ID":             t.ID.String(),
+			"Name":           t.Name,
+			"Type":           string(t.Type),
+			"URL":            t.URL,
+			"OrganizationID": t.OrgID.String(),
+			"BucketID":       t.BucketID.String(),
+			"Deleted":        deleted,
+		})
+	}
+	w.Flush()
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Secret Command
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Secret management commands",
+	Run:   secretF,
+}
+
+func secretF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newSecretService(f Flags) (platform.SecretService, error) {
+	if flags.local {
+		c, err := newLocalClient()
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return &http.SecretService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+// SecretFindFlags define the Find Command
+type SecretFindFlags struct {
+	orgID string
+}
+
+var secretFindFlags SecretFindFlags
+
+func init() {
+	secretFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find the secret keys of an organization",
+		RunE:  secretFindF,
+	}
+
+	secretFindCmd.Flags().StringVarP(&secretFindFlags.orgID, "org-id", "", "", "The ID of the organization (required)")
+	secretFindCmd.MarkFlagRequired("org-id")
+
+	secretCmd.AddCommand(secretFindCmd)
+}
+
+func secretFindF(cmd *cobra.Command, args []string) error {
+	s, err := newSecretService(flags)
+	if err != nil {
+		return err
+	}
+
+	var orgID platform.ID
+	if err := orgID.DecodeFromString(secretFindFlags.orgID); err != nil {
+		return fmt.Errorf("error parsing organization id: %v", err)
+	}
+
+	ks, err := s.GetSecretKeys(context.Background(), orgID)
+	if err != nil {
+		return err
+	}
+
+	writeSecrets(orgID, ks, false)
+	return nil
+}
+
+// SecretUpdateFlags define the Update Command
+type SecretUpdateFlags struct {
+	orgID string
+	key   string
+	value string
+}
+
+var secretUpdateFlags SecretUpdateFlags
+
+func init() {
+	secretUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Set a secret of an organization",
+		RunE:  secretUpdateF,
+	}
+
+	secretUpdateCmd.Flags().StringVarP(&secretUpdateFlags.orgID, "org-id", "", "", "The ID of the organization (required)")
+	secretUpdateCmd.Flags().StringVarP(&secretUpdateFlags.key, "key", "k", "", "The secret key (required)")
+	secretUpdateCmd.Flags().StringVarP(&secretUpdateFlags.value, "value", "v", "", "The secret value (required)")
+	secretUpdateCmd.MarkFlagRequired("org-id")
+	secretUpdateCmd.MarkFlagRequired("key")
+	secretUpdateCmd.MarkFlagRequired("value")
+
+	secretCmd.AddCommand(secretUpdateCmd)
+}
+
+func secretUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newSecretService(flags)
+	if err != nil {
+		return err
+	}
+
+	var orgID platform.ID
+	if err := orgID.DecodeFromString(secretUpdateFlags.orgID); err != nil {
+		return fmt.Errorf("error parsing organization id: %v", err)
+	}
+
+	if err := s.PutSecret(context.Background(), orgID, secretUpdateFlags.key, secretUpdateFlags.value); err != nil {
+		return err
+	}
+
+	writeSecrets(orgID, []string{secretUpdateFlags.key}, false)
+	return nil
+}
+
+// SecretDeleteFlags define the Delete command
+type SecretDeleteFlags struct {
+	orgID string
+	keys  []string
+}
+
+var secretDeleteFlags SecretDeleteFlags
+
+func init() {
+	secretDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete secrets of an organization",
+		RunE:  secretDeleteF,
+	}
+
+	secretDeleteCmd.Flags().StringVarP(&secretDeleteFlags.orgID, "org-id", "", "", "The ID of the organization (required)")
+	secretDeleteCmd.Flags().StringSliceVarP(&secretDeleteFlags.keys, "key", "k", nil, "The secret keys (required)")
+	secretDeleteCmd.MarkFlagRequired("org-id")
+	secretDeleteCmd.MarkFlagRequired("key")
+
+	secretCmd.AddCommand(secretDeleteCmd)
+}
+
+func secretDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newSecretService(flags)
+	if err != nil {
+		return err
+	}
+
+	var orgID platform.ID
+	if err := orgID.DecodeFromString(secretDeleteFlags.orgID); err != nil {
+		return fmt.Errorf("error parsing organization id: %v", err)
+	}
+
+	if err := s.DeleteSecret(context.Background(), orgID, secretDeleteFlags.keys...); err != nil {
+		return err
+	}
+
+	writeSecrets(orgID, secretDeleteFlags.keys, true)
+	return nil
+}
+
+// writeSecrets writes the secret keys of the organization, marked as
+// deleted if deleted is set. The values of the secrets are never written.
+func writeSecrets(orgID platform.ID, ks []string, deleted bool) {
+	headers := []string{
+		"Key",
+		"OrganizationID",
+	}
+	if deleted {
+		headers = append(headers, "Deleted")
+	}
+
+	w := newWriter(os.Stdout)
+	w.WriteHeaders(headers...)
+	for _, k := range ks {
+		w.Write(map[string]interface{}{
+			"Key":            k,
+			"OrganizationID": orgID.String(),
+			"Deleted":        deleted,
+		})
+	}
+	w.Flush()
+}
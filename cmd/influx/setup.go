@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	platform "github.com/influxdata/influxdb"
-	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
 	"github.com/spf13/cobra"
 	input "github.com/tcnksm/go-input"
@@ -49,7 +48,7 @@ func setupF(cmd *cobra.Command, args []string) {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"UserID",
 		"Username",
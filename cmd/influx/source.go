@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Source Command
+var sourceCmd = &cobra.Command{
+	Use:   "source",
+	Short: "Source management commands",
+	Run:   sourceF,
+}
+
+func sourceF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newSourceService(f Flags) (platform.SourceService, error) {
+	if flags.local {
+		c, err := newLocalClient()
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return &http.SourceService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+// SourceFlags define the fields of a source, shared by the create and
+// update commands.
+type SourceFlags struct {
+	name               string
+	typ                string
+	url                string
+	insecureSkipVerify bool
+	telegraf           string
+	token              string
+	username           string
+	password           string
+	defaultRP          string
+}
+
+func (f *SourceFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.name, "name", "n", "", "Name of the source")
+	cmd.Flags().StringVarP(&f.typ, "type", "", platform.V2SourceType, "Type of the source: v2 or v1")
+	cmd.Flags().StringVarP(&f.url, "url", "u", "", "URL of the source")
+	cmd.Flags().BoolVarP(&f.insecureSkipVerify, "insecure-skip-verify", "", false, "Accept any certificate of the source")
+	cmd.Flags().StringVarP(&f.telegraf, "telegraf", "", "telegraf", "Database telegraf writes to, for v1 sources")
+	cmd.Flags().StringVarP(&f.token, "source-token", "", "", "Token of the source, for v2 sources")
+	cmd.Flags().StringVarP(&f.username, "username", "", "", "Username of the source, for v1 sources")
+	cmd.Flags().StringVarP(&f.password, "password", "", "", "Password of the source, for v1 sources")
+	cmd.Flags().StringVarP(&f.defaultRP, "default-rp", "", "", "Default retention policy of the source, for v1 sources")
+}
+
+// SourceCreateFlags define the Create Command
+type SourceCreateFlags struct {
+	SourceFlags
+	orgID string
+}
+
+var sourceCreateFlags SourceCreateFlags
+
+func init() {
+	sourceCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create source",
+		RunE:  sourceCreateF,
+	}
+
+	sourceCreateFlags.register(sourceCreateCmd)
+	sourceCreateCmd.Flags().StringVarP(&sourceCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the source")
+	sourceCreateCmd.MarkFlagRequired("name")
+	sourceCreateCmd.MarkFlagRequired("url")
+	sourceCreateCmd.MarkFlagRequired("org-id")
+
+	sourceCmd.AddCommand(sourceCreateCmd)
+}
+
+func sourceCreateF(cmd *cobra.Command, args []string) error {
+	f := sourceCreateFlags
+	src := &platform.Source{
+		Name:               f.name,
+		Type:               platform.SourceType(f.typ),
+		URL:                f.url,
+		InsecureSkipVerify: f.insecureSkipVerify,
+		Telegraf:           f.telegraf,
+		SourceFields: platform.SourceFields{
+			Token: f.token,
+		},
+		V1SourceFields: platform.V1SourceFields{
+			Username:  f.username,
+			Password:  f.password,
+			DefaultRP: f.defaultRP,
+		},
+	}
+	if err := src.OrganizationID.DecodeFromString(f.orgID); err != nil {
+		return fmt.Errorf("error parsing organization id: %v", err)
+	}
+
+	s, err := newSourceService(flags)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CreateSource(context.Background(), src); err != nil {
+		return err
+	}
+
+	writeSources([]*platform.Source{src}, false)
+	return nil
+}
+
+// SourceFindFlags define the Find Command
+type SourceFindFlags struct {
+	id string
+}
+
+var sourceFindFlags SourceFindFlags
+
+func init() {
+	sourceFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find sources",
+		RunE:  sourceFindF,
+	}
+
+	sourceFindCmd.Flags().StringVarP(&sourceFindFlags.id, "id", "i", "", "The source ID")
+
+	sourceCmd.AddCommand(sourceFindCmd)
+}
+
+func sourceFindF(cmd *cobra.Command, args []string) error {
+	s, err := newSourceService(flags)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if sourceFindFlags.id == "" {
+		srcs, _, err := s.FindSources(ctx, platform.FindOptions{})
+		if err != nil {
+			return err
+		}
+		writeSources(srcs, false)
+		return nil
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(sourceFindFlags.id); err != nil {
+		return err
+	}
+	src, err := s.FindSourceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	writeSources([]*platform.Source{src}, false)
+	return nil
+}
+
+// SourceUpdateFlags define the Update Command
+type SourceUpdateFlags struct {
+	SourceFlags
+	id string
+}
+
+var sourceUpdateFlags SourceUpdateFlags
+
+func init() {
+	sourceUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update source",
+		RunE:  sourceUpdateF,
+	}
+
+	sourceUpdateCmd.Flags().StringVarP(&sourceUpdateFlags.id, "id", "i", "", "The source ID (required)")
+	sourceUpdateFlags.register(sourceUpdateCmd)
+	sourceUpdateCmd.MarkFlagRequired("id")
+
+	sourceCmd.AddCommand(sourceUpdateCmd)
+}
+
+func sourceUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newSourceService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(sourceUpdateFlags.id); err != nil {
+		return err
+	}
+
+	f := sourceUpdateFlags
+	changed := cmd.Flags().Changed
+	update := platform.SourceUpdate{}
+	if changed("name") {
+		update.Name = &f.name
+	}
+	if changed("type") {
+		typ := platform.SourceType(f.typ)
+		update.Type = &typ
+	}
+	if changed("url") {
+		update.URL = &f.url
+	}
+	if changed("insecure-skip-verify") {
+		update.InsecureSkipVerify = &f.insecureSkipVerify
+	}
+	if changed("telegraf") {
+		update.Telegraf = &f.telegraf
+	}
+	if changed("source-token") {
+		update.Token = &f.token
+	}
+	if changed("username") {
+		update.Username = &f.username
+	}
+	if changed("password") {
+		update.Password = &f.password
+	}
+	if changed("default-rp") {
+		update.DefaultRP = &f.defaultRP
+	}
+
+	src, err := s.UpdateSource(context.Background(), id, update)
+	if err != nil {
+		return err
+	}
+
+	writeSources([]*platform.Source{src}, false)
+	return nil
+}
+
+// SourceDeleteFlags define the Delete command
+type SourceDeleteFlags struct {
+	id string
+}
+
+var sourceDeleteFlags SourceDeleteFlags
+
+func init() {
+	sourceDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete source",
+		RunE:  sourceDeleteF,
+	}
+
+	sourceDeleteCmd.Flags().StringVarP(&sourceDeleteFlags.id, "id", "i", "", "The source ID (required)")
+	sourceDeleteCmd.MarkFlagRequired("id")
+
+	sourceCmd.AddCommand(sourceDeleteCmd)
+}
+
+func sourceDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newSourceService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(sourceDeleteFlags.id); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	src, err := s.FindSourceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DeleteSource(ctx, id); err != nil {
+		return err
+	}
+
+	writeSources([]*platform.Source{src}, true)
+	return nil
+}
+
+// writeSources writes the sources, marked as deleted if deleted is set. The
+// credentials of the sources are never written.
+func writeSources(srcs []*platform.Source, deleted bool) {
+	headers := []string{
+		"ID",
+		"Name",
+		"Type",
+		"URL",
+		"OrganizationID",
+		"Default",
+	}
+	if deleted {
+		headers = append(headers, "Deleted")
+	}
+
+	w := newWriter(os.Stdout)
+	w.WriteHeaders(headers...)
+	for _, src := range srcs {
+		w.Write(map[string]interface{}{
+			"ID":             src.ID.String(),
+			"Name":           src.Name,
+			"Type":           string(src.Type),
+			"URL":            src.URL,
+			"OrganizationID": src.OrganizationID.String(),
+			"Default":        src.Default,
+			"Deleted":        deleted,
+		})
+	}
+	w.Flush()
+}
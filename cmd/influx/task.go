@@ -7,7 +7,6 @@ import (
 
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
-	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
 	"github.com/spf13/cobra"
 )
@@ -116,7 +115,7 @@ func taskCreateF(cmd *cobra.Command, args []string) {
 
 		if len(orgs) != 1 {
 			fmt.Println("unable to find a single org matching that ID")
-			w := internal.NewTabWriter(os.Stdout)
+			w := newWriter(os.Stdout)
 			w.WriteHeaders(
 				"ID",
 				"Name",
@@ -147,7 +146,7 @@ func taskCreateF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -248,7 +247,7 @@ func taskFindF(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -324,7 +323,7 @@ func taskUpdateF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -389,7 +388,7 @@ func taskDeleteF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
@@ -472,7 +471,7 @@ func taskLogFindF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"Log",
 	)
@@ -562,7 +561,7 @@ func taskRunFindF(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
+	w := newWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"TaskID",
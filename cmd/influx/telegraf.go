@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Telegraf Command
+var telegrafCmd = &cobra.Command{
+	Use:   "telegraf",
+	Short: "Telegraf config management commands",
+	Run:   telegrafF,
+}
+
+func telegrafF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newTelegrafService(f Flags) (platform.TelegrafConfigStore, error) {
+	if flags.local {
+		c, err := newLocalClient()
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return http.NewTelegrafService(flags.host, flags.token, false), nil
+}
+
+// readTelegrafTOML decodes the telegraf toml config of the file, or of stdin
+// if file is empty.
+func readTelegrafTOML(file string, tc *platform.TelegrafConfig) error {
+	var r io.Reader = os.Stdin
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	text, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return tc.DecodeTOML(string(text))
+}
+
+// TelegrafCreateFlags define the Create Command
+type TelegrafCreateFlags struct {
+	name    string
+	orgID   string
+	file    string
+	ownerID string
+}
+
+var telegrafCreateFlags TelegrafCreateFlags
+
+func init() {
+	telegrafCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create telegraf config from a telegraf toml config",
+		RunE:  telegrafCreateF,
+	}
+
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.name, "name", "n", "", "Name of the telegraf config")
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the telegraf config")
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.file, "file", "f", "", "File to read the toml config from; defaults to stdin")
+	telegrafCreateCmd.Flags().StringVarP(&telegrafCreateFlags.ownerID, "owner-id", "", "", "The ID of the user that owns the telegraf config, required with --local; defaults to the user of the token")
+	telegrafCreateCmd.MarkFlagRequired("name")
+	telegrafCreateCmd.MarkFlagRequired("org-id")
+
+	telegrafCmd.AddCommand(telegrafCreateCmd)
+}
+
+func telegrafCreateF(cmd *cobra.Command, args []string) error {
+	tc := &platform.TelegrafConfig{
+		Name: telegrafCreateFlags.name,
+	}
+	if err := tc.OrganizationID.DecodeFromString(telegrafCreateFlags.orgID); err != nil {
+		return fmt.Errorf("error parsing organization id: %v", err)
+	}
+
+	// Over HTTP the config is owned by the user of the token.
+	var ownerID platform.ID
+	if flags.local || telegrafCreateFlags.ownerID != "" {
+		if err := ownerID.DecodeFromString(telegrafCreateFlags.ownerID); err != nil {
+			return fmt.Errorf("error parsing owner id: %v", err)
+		}
+	}
+
+	if err := readTelegrafTOML(telegrafCreateFlags.file, tc); err != nil {
+		return err
+	}
+
+	s, err := newTelegrafService(flags)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CreateTelegrafConfig(context.Background(), tc, ownerID); err != nil {
+		return err
+	}
+
+	writeTelegrafConfigs([]*platform.TelegrafConfig{tc}, false)
+	return nil
+}
+
+// TelegrafFindFlags define the Find Command
+type TelegrafFindFlags struct {
+	id    string
+	orgID string
+	toml  bool
+}
+
+var telegrafFindFlags TelegrafFindFlags
+
+func init() {
+	telegrafFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find telegraf configs",
+		RunE:  telegrafFindF,
+	}
+
+	telegrafFindCmd.Flags().StringVarP(&telegrafFindFlags.id, "id", "i", "", "The telegraf config ID")
+	telegrafFindCmd.Flags().StringVarP(&telegrafFindFlags.orgID, "org-id", "", "", "The telegraf config organization ID")
+	telegrafFindCmd.Flags().BoolVarP(&telegrafFindFlags.toml, "toml", "", false, "Write the toml config of the telegraf config found by ID")
+
+	telegrafCmd.AddCommand(telegrafFindCmd)
+}
+
+func telegrafFindF(cmd *cobra.Command, args []string) error {
+	if telegrafFindFlags.toml && telegrafFindFlags.id == "" {
+		return fmt.Errorf("must specify id with toml")
+	}
+
+	s, err := newTelegrafService(flags)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if telegrafFindFlags.id != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(telegrafFindFlags.id); err != nil {
+			return err
+		}
+		tc, err := s.FindTelegrafConfigByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if telegrafFindFlags.toml {
+			_, err := io.WriteString(os.Stdout, tc.TOML())
+			return err
+		}
+		writeTelegrafConfigs([]*platform.TelegrafConfig{tc}, false)
+		return nil
+	}
+
+	filter := platform.TelegrafConfigFilter{}
+	if telegrafFindFlags.orgID != "" {
+		orgID, err := platform.IDFromString(telegrafFindFlags.orgID)
+		if err != nil {
+			return err
+		}
+		filter.OrganizationID = orgID
+	}
+
+	tcs, _, err := s.FindTelegrafConfigs(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	writeTelegrafConfigs(tcs, false)
+	return nil
+}
+
+// TelegrafUpdateFlags define the Update Command
+type TelegrafUpdateFlags struct {
+	id   string
+	name string
+	file string
+}
+
+var telegrafUpdateFlags TelegrafUpdateFlags
+
+func init() {
+	telegrafUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Replace the config of a telegraf config by a telegraf toml config",
+		RunE:  telegrafUpdateF,
+	}
+
+	telegrafUpdateCmd.Flags().StringVarP(&telegrafUpdateFlags.id, "id", "i", "", "The telegraf config ID (required)")
+	telegrafUpdateCmd.Flags().StringVarP(&telegrafUpdateFlags.name, "name", "n", "", "New name of the telegraf config; defaults to the current name")
+	telegrafUpdateCmd.Flags().StringVarP(&telegrafUpdateFlags.file, "file", "f", "", "File to read the toml config from; defaults to stdin")
+	telegrafUpdateCmd.MarkFlagRequired("id")
+
+	telegrafCmd.AddCommand(telegrafUpdateCmd)
+}
+
+func telegrafUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newTelegrafService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(telegrafUpdateFlags.id); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	current, err := s.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tc := &platform.TelegrafConfig{
+		Name:           current.Name,
+		OrganizationID: current.OrganizationID,
+	}
+	if telegrafUpdateFlags.name != "" {
+		tc.Name = telegrafUpdateFlags.name
+	}
+	if err := readTelegrafTOML(telegrafUpdateFlags.file, tc); err != nil {
+		return err
+	}
+
+	tc, err = s.UpdateTelegrafConfig(ctx, id, tc, 0)
+	if err != nil {
+		return err
+	}
+
+	writeTelegrafConfigs([]*platform.TelegrafConfig{tc}, false)
+	return nil
+}
+
+// TelegrafDeleteFlags define the Delete command
+type TelegrafDeleteFlags struct {
+	id string
+}
+
+var telegrafDeleteFlags TelegrafDeleteFlags
+
+func init() {
+	telegrafDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete telegraf config",
+		RunE:  telegrafDeleteF,
+	}
+
+	telegrafDeleteCmd.Flags().StringVarP(&telegrafDeleteFlags.id, "id", "i", "", "The telegraf config ID (required)")
+	telegrafDeleteCmd.MarkFlagRequired("id")
+
+	telegrafCmd.AddCommand(telegrafDeleteCmd)
+}
+
+func telegrafDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newTelegrafService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(telegrafDeleteFlags.id); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	tc, err := s.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DeleteTelegrafConfig(ctx, id); err != nil {
+		return err
+	}
+
+	writeTelegrafConfigs([]*platform.TelegrafConfig{tc}, true)
+	return nil
+}
+
+// writeTelegrafConfigs writes the telegraf configs, marked as deleted if
+// deleted is set.
+func writeTelegrafConfigs(tcs []*platform.TelegrafConfig, deleted bool) {
+	headers := []string{
+		"ID",
+		"Name",
+		"OrganizationID",
+		"Plugins",
+		"Version",
+	}
+	if deleted {
+		headers = append(headers, "Deleted")
+	}
+
+	w := newWriter(os.Stdout)
+	w.WriteHeaders(headers...)
+	for _, tc := range tcs {
+		w.Write(map[string]interface{}{
+			"ID":             tc.ID.String(),
+			"Name":           tc.Name,
+			"OrganizationID": tc.OrganizationID.String(),
+			"Plugins":        len(tc.Plugins),
+			"Version":        tc.Version(),
+			"Deleted":        deleted,
+		})
+	}
+	w.Flush()
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
 
@@ -20,17 +22,33 @@ var writeCmd = &cobra.Command{
 	Use:   "write line protocol or @/path/to/points.txt",
 	Short: "Write points to InfluxDB",
 	Long: `Write a single line of line protocol to InfluxDB,
-or add an entire file specified with an @ prefix.`,
+or add an entire file specified with an @ prefix.
+
+Data in another format is selected with --format:
+  lp             line protocol (default)
+  annotated-csv  flux annotated CSV as returned by influx query
+  csv            plain CSV with a header row, described by --csv-mapping
+  json           a JSON array of {"measurement","tags","fields","time"} objects
+
+A csv mapping is a comma separated list of key=value pairs, for example
+  measurement=cpu,tag=host,field=usage_user:float,time=timestamp:unix
+
+Lines rejected by the server are printed to stderr. With --mode atomic
+(the default) a batch containing a rejected line is not written and the
+write stops; with --mode partial the valid lines of every batch are written.`,
 	Args: cobra.ExactArgs(1),
 	RunE: fluxWriteF,
 }
 
 var writeFlags struct {
-	OrgID     string
-	Org       string
-	BucketID  string
-	Bucket    string
-	Precision string
+	OrgID      string
+	Org        string
+	BucketID   string
+	Bucket     string
+	Precision  string
+	Format     string
+	CSVMapping string
+	Mode       string
 }
 
 func init() {
@@ -63,6 +81,10 @@ func init() {
 	if p := viper.GetString("PRECISION"); p != "" {
 		writeFlags.Precision = p
 	}
+
+	writeCmd.PersistentFlags().StringVar(&writeFlags.Format, "format", string(write.FormatLineProtocol), "Format of the input data; one of lp, annotated-csv, csv or json")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.CSVMapping, "csv-mapping", "", "Column mapping for the csv format, such as measurement=cpu,tag=host,field=usage:float,time=_time")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.Mode, "mode", string(http.WriteModeAtomic), "How batches with rejected lines are handled; atomic or partial")
 }
 
 func fluxWriteF(cmd *cobra.Command, args []string) error {
@@ -83,12 +105,34 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid precision")
 	}
 
+	format, err := write.ParseFormat(writeFlags.Format)
+	if err != nil {
+		cmd.Usage()
+		return err
+	}
+
+	var mapping *write.CSVMapping
+	if writeFlags.CSVMapping != "" {
+		if mapping, err = write.ParseCSVMapping(writeFlags.CSVMapping); err != nil {
+			return err
+		}
+	}
+	if format == write.FormatCSV && mapping == nil {
+		cmd.Usage()
+		return fmt.Errorf("the csv format requires --csv-mapping")
+	}
+
+	mode := http.WriteMode(writeFlags.Mode)
+	if mode != http.WriteModeAtomic && mode != http.WriteModePartial {
+		cmd.Usage()
+		return fmt.Errorf("invalid mode %q; valid modes are atomic and partial", writeFlags.Mode)
+	}
+
 	bs := &http.BucketService{
 		Addr:  flags.host,
 		Token: flags.token,
 	}
 
-	var err error
 	filter := platform.BucketFilter{}
 
 	if writeFlags.BucketID != "" {
@@ -136,17 +180,83 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		r = strings.NewReader(args[0])
 	}
 
-	s := write.Batcher{
+	precision := writeFlags.Precision
+	if format != write.FormatLineProtocol {
+		// Other formats are converted to line protocol with nanosecond timestamps
+		// so they can be batched line by line.
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		lp, err := write.ToLineProtocol(format, data, write.ParseOptions{
+			Precision:  writeFlags.Precision,
+			CSVMapping: mapping,
+		})
+		if err != nil {
+			return err
+		}
+		r, precision = bytes.NewReader(lp), "ns"
+	}
+
+	ws := &rejectedLinePrinter{
 		Service: &http.WriteService{
 			Addr:      flags.host,
 			Token:     flags.token,
-			Precision: writeFlags.Precision,
+			Precision: precision,
+			Mode:      mode,
 		},
+		Out: os.Stderr,
+	}
+	s := write.Batcher{
+		Service: ws,
 	}
 
 	ctx = signals.WithStandardSignals(ctx)
-	if err := s.Write(ctx, orgID, bucketID, r); err != context.Canceled {
+	if err := s.Write(ctx, orgID, bucketID, r); err != context.Canceled && err != nil {
 		return err
 	}
+	if ws.rejected > 0 {
+		return fmt.Errorf("%d lines rejected", ws.rejected)
+	}
 	return nil
 }
+
+// rejectedLinePrinter prints the lines rejected by each batch, numbered
+// relative to the whole input. In partial mode it keeps writing after a
+// batch with rejected lines.
+type rejectedLinePrinter struct {
+	Service *http.WriteService
+	Out     io.Writer
+
+	lines    int // lines sent in earlier batches
+	rejected int
+}
+
+func (p *rejectedLinePrinter) Write(ctx context.Context, orgID, bucketID platform.ID, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	offset := p.lines
+	p.lines += bytes.Count(data, []byte{'\n'})
+
+	err = p.Service.Write(ctx, orgID, bucketID, bytes.NewReader(data))
+	we, ok := err.(*http.WriteError)
+	if !ok {
+		return err
+	}
+
+	for _, l := range we.Errors {
+		fmt.Fprintf(p.Out, "line %d: %s: %s\n", offset+l.Line, l.Reason, l.Text)
+	}
+	if n := we.Rejected - len(we.Errors); n > 0 {
+		fmt.Fprintf(p.Out, "%d more rejected lines not shown\n", n)
+	}
+	p.rejected += we.Rejected
+
+	if p.Service.Mode == http.WriteModePartial {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+// Package generate implements the "influxd generate" command, which creates
+// synthetic data from a schema for benchmarks and capacity planning.
+package generate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/pkg/data/gen"
+	"github.com/spf13/cobra"
+)
+
+// Command is the "influxd generate" command.
+var Command = &cobra.Command{
+	Use:   "generate <schema.toml>",
+	Short: "Generate time series data sets using a schema",
+	Long: `Generate time series data sets using a schema.
+
+The data is written as TSM files to the engine path for the organization
+and bucket given by --org-id and --bucket-id, or streamed to stdout as
+line protocol with --print. influxd must not be running while the engine
+path is written to.
+
+The schema is a TOML file:
+
+	start    = 2019-01-01T00:00:00Z  # optional, data ends now by default
+	span     = "24h"
+	interval = "10s"
+
+	[[measurements]]
+	name   = "cpu"
+	tags   = [
+	  { name = "host", cardinality = 100, format = "host-%s" },
+	  { name = "region", cardinality = 4 },
+	]
+	fields = [
+	  { name = "usage_user", type = "float", scale = 100.0 },
+	  { name = "running", type = "boolean", value = true },
+	]
+
+Field types are float, integer, unsigned, string and boolean.`,
+	Args: cobra.ExactArgs(1),
+	RunE: generateF,
+}
+
+var flags struct {
+	orgID      string
+	bucketID   string
+	enginePath string
+	print      bool
+}
+
+func init() {
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to determine influx directory: %v", err))
+	}
+
+	Command.Flags().StringVar(&flags.orgID, "org-id", "", "The ID of the organization that owns the bucket")
+	Command.Flags().StringVar(&flags.bucketID, "bucket-id", "", "The ID of the bucket to write the data to")
+	Command.Flags().StringVar(&flags.enginePath, "engine-path", filepath.Join(dir, "engine"), "path to persistent engine files")
+	Command.Flags().BoolVar(&flags.print, "print", false, "print the data as line protocol instead of writing it to the engine")
+}
+
+func generateF(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	spec, err := gen.NewSpecFromToml(f)
+	if err != nil {
+		return fmt.Errorf("invalid schema %s: %v", args[0], err)
+	}
+	start := spec.StartTime(time.Now().UTC())
+
+	if flags.print {
+		w := bufio.NewWriterSize(os.Stdout, 1<<20)
+		if err := writeLineProtocol(w, gen.NewSpecSeriesGenerator(spec, start)); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	if flags.orgID == "" || flags.bucketID == "" {
+		return errors.New("--org-id and --bucket-id are required unless --print is set")
+	}
+	orgID, err := platform.IDFromString(flags.orgID)
+	if err != nil {
+		return fmt.Errorf("invalid org ID: %v", err)
+	}
+	bucketID, err := platform.IDFromString(flags.bucketID)
+	if err != nil {
+		return fmt.Errorf("invalid bucket ID: %v", err)
+	}
+
+	w := &engineWriter{
+		Path:     flags.enginePath,
+		OrgID:    *orgID,
+		BucketID: *bucketID,
+	}
+	files, err := w.Write(spec, start)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d series of %d points from %s to %s in %d file(s)\n",
+		spec.SeriesCount(), spec.PointsPerSeries(), start.Format(time.RFC3339), w.Path, len(files))
+	return nil
+}
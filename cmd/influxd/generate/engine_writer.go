@@ -0,0 +1,232 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/data/gen"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+const (
+	// maxTSMFileSize is the size after which a new TSM file is started.
+	maxTSMFileSize = uint32(2048 * 1024 * 1024) // 2GB
+
+	indexBatchSize = 10000
+)
+
+// engineWriter writes generated series as TSM files of a new generation
+// and adds them to the series file and index of an engine.
+type engineWriter struct {
+	Path     string
+	OrgID    platform.ID
+	BucketID platform.ID
+}
+
+type generatedSeries struct {
+	key   []byte // composite TSM key
+	field *gen.FieldSpec
+}
+
+// Write writes the series of spec and returns the paths of the TSM files.
+func (w *engineWriter) Write(spec *gen.Spec, start time.Time) ([]string, error) {
+	config := storage.NewConfig()
+	dataPath := config.GetEnginePath(w.Path)
+	if err := os.MkdirAll(dataPath, 0777); err != nil {
+		return nil, err
+	}
+
+	series, err := w.index(spec, start, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// TSM files require their keys to be sorted.
+	sort.Slice(series, func(i, j int) bool {
+		return bytes.Compare(series[i].key, series[j].key) < 0
+	})
+
+	generation, err := nextGeneration(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	return writeTSMFiles(dataPath, generation, series, spec.PointsPerSeries(), start, time.Duration(spec.Interval))
+}
+
+// index adds the series of spec to the series file and index of the engine
+// and returns them.
+func (w *engineWriter) index(spec *gen.Spec, start time.Time, config storage.Config) ([]generatedSeries, error) {
+	sfile := tsdb.NewSeriesFile(config.GetSeriesFilePath(w.Path))
+	if err := sfile.Open(); err != nil {
+		return nil, err
+	}
+	defer sfile.Close()
+
+	index := tsi1.NewIndex(sfile, config.Index,
+		tsi1.WithPath(config.GetIndexPath(w.Path)),
+		tsi1.DisableMetrics(),
+	)
+	if err := index.Open(); err != nil {
+		return nil, err
+	}
+	defer index.Close()
+
+	name := tsdb.EncodeName(w.OrgID, w.BucketID)
+	collection := newSeriesCollection()
+	series := make([]generatedSeries, 0, spec.SeriesCount())
+
+	g := gen.NewSpecSeriesGenerator(spec, start)
+	for g.Next() {
+		tags := make(models.Tags, 0, len(g.Tags())+2)
+		tags = append(tags,
+			models.NewTag(tsdb.FieldKeyTagKeyBytes, g.Field()),
+			models.NewTag(tsdb.MeasurementTagKeyBytes, g.Name()),
+		)
+		tags = append(tags, g.Tags().Clone()...)
+		sort.Sort(tags)
+
+		seriesKey := models.MakeKey(name[:], tags)
+		field := g.FieldSpec()
+		series = append(series, generatedSeries{
+			key:   tsm1.SeriesFieldKeyBytes(string(seriesKey), field.Name),
+			field: field,
+		})
+
+		collection.Keys = append(collection.Keys, seriesKey)
+		collection.Names = append(collection.Names, name[:])
+		collection.Tags = append(collection.Tags, tags)
+		collection.Types = append(collection.Types, field.FieldType())
+		if collection.Length() == indexBatchSize {
+			if err := index.CreateSeriesListIfNotExists(collection); err != nil {
+				return nil, fmt.Errorf("problem creating series: (%s)", err)
+			}
+			collection = newSeriesCollection()
+		}
+	}
+
+	if collection.Length() > 0 {
+		if err := index.CreateSeriesListIfNotExists(collection); err != nil {
+			return nil, fmt.Errorf("problem creating series: (%s)", err)
+		}
+	}
+	return series, nil
+}
+
+func newSeriesCollection() *tsdb.SeriesCollection {
+	return &tsdb.SeriesCollection{
+		Keys:  make([][]byte, 0, indexBatchSize),
+		Names: make([][]byte, 0, indexBatchSize),
+		Tags:  make([]models.Tags, 0, indexBatchSize),
+		Types: make([]models.FieldType, 0, indexBatchSize),
+	}
+}
+
+// nextGeneration returns a generation greater than the generations of the
+// TSM files in dir.
+func nextGeneration(dir string) (int, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var max int
+	for _, fi := range fis {
+		if filepath.Ext(fi.Name()) != "."+tsm1.TSMFileExtension {
+			continue
+		}
+		generation, _, err := tsm1.DefaultParseFileName(fi.Name())
+		if err != nil {
+			continue
+		}
+		if generation > max {
+			max = generation
+		}
+	}
+	return max + 1, nil
+}
+
+// writeTSMFiles writes the values of series, which must be sorted by key,
+// to TSM files of generation in dir.
+func writeTSMFiles(dir string, generation int, series []generatedSeries, n int, start time.Time, interval time.Duration) ([]string, error) {
+	var (
+		files    []string
+		sequence int
+	)
+	for len(series) > 0 {
+		sequence++
+		path := filepath.Join(dir, tsm1.DefaultFormatFileName(generation, sequence)+"."+tsm1.TSMFileExtension)
+
+		written, err := writeTSMFile(path, series, n, start, interval)
+		if err != nil {
+			for _, f := range files {
+				os.Remove(f)
+				os.Remove(tsm1.StatsFilename(f))
+			}
+			return nil, err
+		}
+		files = append(files, path)
+		series = series[written:]
+	}
+	return files, nil
+}
+
+// writeTSMFile writes series to the file at path until it is full and
+// returns the number of series written. The file is written to a temporary
+// path and renamed when complete.
+func writeTSMFile(path string, series []generatedSeries, n int, start time.Time, interval time.Duration) (written int, err error) {
+	tmpPath := path + "." + tsm1.TmpTSMFileExtension
+	fd, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return 0, err
+	}
+
+	tw, err := tsm1.NewTSMWriterWithDiskBuffer(fd)
+	if err != nil {
+		fd.Close()
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tw.Remove()
+		}
+	}()
+
+	var buf []byte
+	for _, s := range series {
+		vs := s.field.NewValuesSequence(n, start, interval)
+		for vs.Next() {
+			// Encoding a block may modify its timestamps in place.
+			v := vs.Values()
+			minTime, maxTime := v.MinTime(), v.MaxTime()
+			if buf, err = v.Encode(buf[:0]); err != nil {
+				return 0, err
+			}
+			if err := tw.WriteBlock(s.key, minTime, maxTime, buf); err != nil {
+				return 0, err
+			}
+		}
+		written++
+
+		if tw.Size() > maxTSMFileSize {
+			break
+		}
+	}
+
+	if err := tw.WriteIndex(); err != nil {
+		return 0, err
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	return written, os.Rename(tmpPath, path)
+}
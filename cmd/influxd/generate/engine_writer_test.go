@@ -0,0 +1,77 @@
+package generate
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/data/gen"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestEngineWriter_Write(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-generate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spec, err := gen.NewSpecFromToml(strings.NewReader(`
+start    = 2019-01-01T00:00:00Z
+span     = "6h"
+interval = "10s"
+
+[[measurements]]
+name   = "cpu"
+tags   = [ { name = "host", cardinality = 5 } ]
+fields = [
+  { name = "usage", type = "float" },
+  { name = "count", type = "integer" },
+]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &engineWriter{Path: dir, OrgID: 1, BucketID: 2}
+	files, err := w.Write(spec, spec.Start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("unexpected files: %v", files)
+	}
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if got, exp := r.KeyCount(), spec.SeriesCount(); got != exp {
+		t.Errorf("unexpected key count -got/+exp\n%d\n%d", got, exp)
+	}
+
+	// Every series spans more than one block.
+	min, max := r.TimeRange()
+	expMin := spec.Start.UnixNano()
+	expMax := spec.Start.Add(time.Duration(spec.Span) - time.Duration(spec.Interval)).UnixNano()
+	if min != expMin || max != expMax {
+		t.Errorf("unexpected time range -got/+exp\n%d %d\n%d %d", min, max, expMin, expMax)
+	}
+
+	// A second run adds a new generation.
+	files, err = w.Write(spec, spec.Start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generation, _, err := tsm1.DefaultParseFileName(files[0]); err != nil || generation != 2 {
+		t.Errorf("unexpected generation of %s: %d %v", files[0], generation, err)
+	}
+}
@@ -0,0 +1,73 @@
+package generate
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/data/gen"
+	"github.com/influxdata/influxdb/pkg/escape"
+)
+
+// writeLineProtocol writes every point generated by g to w as a line with
+// a single field.
+func writeLineProtocol(w io.Writer, g gen.SeriesGenerator) error {
+	var prefix, line []byte
+	for g.Next() {
+		prefix = models.AppendMakeKey(prefix[:0], g.Name(), g.Tags())
+		prefix = append(prefix, ' ')
+		prefix = append(prefix, escape.Bytes(g.Field())...)
+		prefix = append(prefix, '=')
+
+		vs := g.ValuesGenerator()
+		for vs.Next() {
+			switch a := vs.Values().(type) {
+			case *gen.FloatArray:
+				for i, v := range a.Values {
+					line = append(append(line[:0], prefix...), strconv.FormatFloat(v, 'f', -1, 64)...)
+					if err := writeLine(w, line, a.Timestamps[i]); err != nil {
+						return err
+					}
+				}
+			case *gen.IntegerArray:
+				for i, v := range a.Values {
+					line = strconv.AppendInt(append(line[:0], prefix...), v, 10)
+					if err := writeLine(w, append(line, 'i'), a.Timestamps[i]); err != nil {
+						return err
+					}
+				}
+			case *gen.UnsignedArray:
+				for i, v := range a.Values {
+					line = strconv.AppendUint(append(line[:0], prefix...), v, 10)
+					if err := writeLine(w, append(line, 'u'), a.Timestamps[i]); err != nil {
+						return err
+					}
+				}
+			case *gen.StringArray:
+				for i, v := range a.Values {
+					line = append(append(line[:0], prefix...), '"')
+					line = append(append(line, models.EscapeStringField(v)...), '"')
+					if err := writeLine(w, line, a.Timestamps[i]); err != nil {
+						return err
+					}
+				}
+			case *gen.BooleanArray:
+				for i, v := range a.Values {
+					line = strconv.AppendBool(append(line[:0], prefix...), v)
+					if err := writeLine(w, line, a.Timestamps[i]); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func writeLine(w io.Writer, line []byte, ts int64) error {
+	line = append(line, ' ')
+	line = strconv.AppendInt(line, ts, 10)
+	line = append(line, '\n')
+	_, err := w.Write(line)
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,32 +17,42 @@ import (
 	"github.com/influxdata/flux/execute"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
 	"github.com/influxdata/influxdb/chronograf/server"
+	"github.com/influxdata/influxdb/dashboards"
 	protofs "github.com/influxdata/influxdb/fs"
 	"github.com/influxdata/influxdb/gather"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kit/check"
 	"github.com/influxdata/influxdb/kit/cli"
 	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/leveldb"
 	influxlogger "github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/monitor"
 	"github.com/influxdata/influxdb/nats"
+	"github.com/influxdata/influxdb/oauth"
 	"github.com/influxdata/influxdb/query"
 	pcontrol "github.com/influxdata/influxdb/query/control"
-	"github.com/influxdata/influxdb/snowflake"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/secrets"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/readservice"
+	"github.com/influxdata/influxdb/storage/replication"
 	"github.com/influxdata/influxdb/task"
 	taskbackend "github.com/influxdata/influxdb/task/backend"
 	taskbolt "github.com/influxdata/influxdb/task/backend/bolt"
 	"github.com/influxdata/influxdb/task/backend/coordinator"
 	taskexecutor "github.com/influxdata/influxdb/task/backend/executor"
+	"github.com/influxdata/influxdb/toml"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1"
 	_ "github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/influxdata/influxdb/vault"
-	pzap "github.com/influxdata/influxdb/zap"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -55,6 +66,9 @@ type Launcher struct {
 	logLevel        string
 	httpBindAddress string
 	boltPath        string
+	storeType       string
+	leveldbPath     string
+	checkMigrations bool
 	natsPath        string
 	developerMode   bool
 	enginePath      string
@@ -62,8 +76,26 @@ type Launcher struct {
 
 	secretStore string
 
-	boltClient *bolt.Client
-	engine     *storage.Engine
+	replicationLeader           bool
+	replicationLeaderURL        string
+	replicationToken            string
+	replicationWALRetentionSize int
+
+	monitoringInstance  string
+	monitoringOrg       string
+	monitoringInterval  time.Duration
+	monitoringRetention time.Duration
+
+	oauth         oauth.Config
+	oauthMappings []string
+
+	tracing      tracing.Config
+	tracerCloser io.Closer
+
+	boltClient   *bolt.Client
+	leveldbStore *leveldb.KVStore
+	engine       *storage.Engine
+	follower     *replication.Follower
 
 	queryController *pcontrol.Controller
 
@@ -120,11 +152,25 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 		m.logger.Info("failed closing bolt", zap.Error(err))
 	}
 
+	if m.leveldbStore != nil {
+		m.logger.Info("Stopping", zap.String("service", "leveldb"))
+		if err := m.leveldbStore.Close(); err != nil {
+			m.logger.Info("failed closing leveldb", zap.Error(err))
+		}
+	}
+
 	m.logger.Info("Stopping", zap.String("service", "query"))
 	if err := m.queryController.Shutdown(ctx); err != nil {
 		m.logger.Info("Failed closing query service", zap.Error(err))
 	}
 
+	if m.follower != nil {
+		m.logger.Info("Stopping", zap.String("service", "replication"))
+		if err := m.follower.Close(); err != nil {
+			m.logger.Info("Failed closing replication follower", zap.Error(err))
+		}
+	}
+
 	m.logger.Info("Stopping", zap.String("service", "storage-engine"))
 	if err := m.engine.Close(); err != nil {
 		m.logger.Error("failed to close engine", zap.Error(err))
@@ -132,6 +178,13 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 
 	m.wg.Wait()
 
+	if m.tracerCloser != nil {
+		m.logger.Info("Stopping", zap.String("service", "tracing"))
+		if err := m.tracerCloser.Close(); err != nil {
+			m.logger.Info("failed closing tracer", zap.Error(err))
+		}
+	}
+
 	m.logger.Sync()
 }
 
@@ -140,9 +193,24 @@ func (m *Launcher) Cancel() { m.cancel() }
 
 // Run executes the program with the given CLI arguments.
 func (m *Launcher) Run(ctx context.Context, args ...string) error {
+	cmd, err := m.NewCommand(ctx)
+	if err != nil {
+		return err
+	}
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+// NewCommand returns the command that runs the program, so that
+// subcommands can be added to it.
+func (m *Launcher) NewCommand(ctx context.Context) (*cobra.Command, error) {
 	dir, err := fs.InfluxDir()
 	if err != nil {
-		return fmt.Errorf("failed to determine influx directory: %v", err)
+		return nil, fmt.Errorf("failed to determine influx directory: %v", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "influxd"
 	}
 
 	prog := &cli.Program{
@@ -155,6 +223,36 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 				Default: "info",
 				Desc:    "supported log levels are debug, info, and error",
 			},
+			{
+				DestP:   &m.tracing.Type,
+				Flag:    "tracing-type",
+				Default: tracing.LogTracing,
+				Desc:    fmt.Sprintf("tracing spans are written as logs with %s, or reported to Jaeger with %s", tracing.LogTracing, tracing.JaegerTracing),
+			},
+			{
+				DestP:   &m.tracing.JaegerAgentHostPort,
+				Flag:    "jaeger-agent-host-port",
+				Default: "localhost:6831",
+				Desc:    "UDP address of the Jaeger agent spans are reported to",
+			},
+			{
+				DestP:   &m.tracing.JaegerCollectorEndpoint,
+				Flag:    "jaeger-collector-endpoint",
+				Default: "",
+				Desc:    "HTTP endpoint of the Jaeger collector spans are reported to, such as http://localhost:14268/api/traces; takes precedence over the agent",
+			},
+			{
+				DestP:   &m.tracing.JaegerSamplerType,
+				Flag:    "jaeger-sampler-type",
+				Default: "const",
+				Desc:    "type of the Jaeger sampler: const, probabilistic, ratelimiting or remote",
+			},
+			{
+				DestP:   &m.tracing.JaegerSamplerParam,
+				Flag:    "jaeger-sampler-param",
+				Default: 1.0,
+				Desc:    "parameter of the Jaeger sampler: 1 or 0 for const, the sampled ratio for probabilistic, spans per second for ratelimiting",
+			},
 			{
 				DestP:   &m.httpBindAddress,
 				Flag:    "http-bind-address",
@@ -167,6 +265,24 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 				Default: filepath.Join(dir, "influxd.bolt"),
 				Desc:    "path to boltdb database",
 			},
+			{
+				DestP:   &m.storeType,
+				Flag:    "store",
+				Default: "bolt",
+				Desc:    "data store for the metadata services (bolt or leveldb); tasks are always stored in bolt",
+			},
+			{
+				DestP:   &m.leveldbPath,
+				Flag:    "leveldb-path",
+				Default: filepath.Join(dir, "influxd.leveldb"),
+				Desc:    "path to leveldb database, used with --store leveldb",
+			},
+			{
+				DestP:   &m.checkMigrations,
+				Flag:    "check-migrations",
+				Default: false,
+				Desc:    "refuse to start if the metadata stores have pending migrations instead of applying them; apply them with influxd migrate",
+			},
 			{
 				DestP:   &m.developerMode,
 				Flag:    "developer-mode",
@@ -197,12 +313,220 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 				Default: filepath.Join(dir, "protos"),
 				Desc:    "path to protos on the filesystem",
 			},
+			{
+				DestP:   &m.replicationLeader,
+				Flag:    "replication-leader",
+				Default: false,
+				Desc:    "serve the WAL and the bolt metadata to replication followers; WAL segments written with it enabled cannot be read by influxd versions without replication",
+			},
+			{
+				DestP:   &m.replicationLeaderURL,
+				Flag:    "replication-leader-url",
+				Default: "",
+				Desc:    "URL of an influxd to follow as a read replica; its data and bolt metadata are replicated, and writes and metadata changes are rejected when set",
+			},
+			{
+				DestP:   &m.replicationToken,
+				Flag:    "replication-token",
+				Default: "",
+				Desc:    "token used to read the WAL and the metadata of the replication leader; it needs the read and write permissions on storage",
+			},
+			{
+				DestP:   &m.replicationWALRetentionSize,
+				Flag:    "replication-wal-retention-size",
+				Default: 0,
+				Desc:    "bytes of removed WAL segments kept for replication followers",
+			},
+			{
+				DestP:   &m.monitoringInterval,
+				Flag:    "monitoring-interval",
+				Default: time.Duration(0),
+				Desc:    fmt.Sprintf("interval between writes of the metrics of influxd to the _monitoring_<instance> bucket, such as %s; 0 disables them", monitor.DefaultSelfMonitorInterval),
+			},
+			{
+				DestP:   &m.monitoringInstance,
+				Flag:    "monitoring-instance",
+				Default: hostname,
+				Desc:    "name of this instance of influxd, which names the bucket of its metrics and tags them; defaults to the hostname",
+			},
+			{
+				DestP:   &m.monitoringOrg,
+				Flag:    "monitoring-org",
+				Default: "",
+				Desc:    "organization of the bucket of the metrics of influxd; defaults to the first organization",
+			},
+			{
+				DestP:   &m.monitoringRetention,
+				Flag:    "monitoring-retention",
+				Default: monitor.DefaultSelfMonitorRetention,
+				Desc:    "retention period of the bucket of the metrics of influxd, if it is created",
+			},
+			{
+				DestP:   &m.oauth.TokenSecret,
+				Flag:    "token-secret",
+				Default: "",
+				Desc:    "secret to sign OAuth2 tokens; OAuth2 sign in is disabled without it",
+			},
+			{
+				DestP:   &m.oauth.JwksURL,
+				Flag:    "jwks-url",
+				Default: "",
+				Desc:    "URL that returns the OpenID key discovery JWKS document",
+			},
+			{
+				DestP:   &m.oauth.UseIDToken,
+				Flag:    "use-id-token",
+				Default: false,
+				Desc:    "read users from the id_token of OAuth2 providers",
+			},
+			{
+				DestP:   &m.oauth.PublicURL,
+				Flag:    "public-url",
+				Default: "",
+				Desc:    "full public URL used to access influxd from a web browser, used for OAuth2 callbacks",
+			},
+			{
+				DestP:   &m.oauthMappings,
+				Flag:    "oauth-org-mapping",
+				Default: []string{},
+				Desc:    "grant OAuth2 users of a group or email domain a role in an organization, as group:<group>=<org>[:<role>] or domain:<domain>=<org>[:<role>]",
+			},
+			{
+				DestP:   &m.oauth.GithubClientID,
+				Flag:    "github-client-id",
+				Default: "",
+				Desc:    "GitHub client ID for OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.GithubClientSecret,
+				Flag:    "github-client-secret",
+				Default: "",
+				Desc:    "GitHub client secret for OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.GithubOrgs,
+				Flag:    "github-organization",
+				Default: []string{},
+				Desc:    "GitHub organizations users are required to be members of",
+			},
+			{
+				DestP:   &m.oauth.GoogleClientID,
+				Flag:    "google-client-id",
+				Default: "",
+				Desc:    "Google client ID for OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.GoogleClientSecret,
+				Flag:    "google-client-secret",
+				Default: "",
+				Desc:    "Google client secret for OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.GoogleDomains,
+				Flag:    "google-domains",
+				Default: []string{},
+				Desc:    "Google email domains users are required to have",
+			},
+			{
+				DestP:   &m.oauth.HerokuClientID,
+				Flag:    "heroku-client-id",
+				Default: "",
+				Desc:    "Heroku client ID for OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.HerokuSecret,
+				Flag:    "heroku-secret",
+				Default: "",
+				Desc:    "Heroku secret for OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.HerokuOrganizations,
+				Flag:    "heroku-organization",
+				Default: []string{},
+				Desc:    "Heroku organizations users are required to be members of",
+			},
+			{
+				DestP:   &m.oauth.Auth0Domain,
+				Flag:    "auth0-domain",
+				Default: "",
+				Desc:    "subdomain of auth0.com used for Auth0 OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.Auth0ClientID,
+				Flag:    "auth0-client-id",
+				Default: "",
+				Desc:    "Auth0 client ID for OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.Auth0ClientSecret,
+				Flag:    "auth0-client-secret",
+				Default: "",
+				Desc:    "Auth0 client secret for OAuth2 support",
+			},
+			{
+				DestP:   &m.oauth.Auth0Organizations,
+				Flag:    "auth0-organizations",
+				Default: []string{},
+				Desc:    "Auth0 organizations users are required to be members of",
+			},
+			{
+				DestP:   &m.oauth.GenericName,
+				Flag:    "generic-name",
+				Default: "",
+				Desc:    "name of the generic OAuth2 provider",
+			},
+			{
+				DestP:   &m.oauth.GenericClientID,
+				Flag:    "generic-client-id",
+				Default: "",
+				Desc:    "generic OAuth2 client ID",
+			},
+			{
+				DestP:   &m.oauth.GenericClientSecret,
+				Flag:    "generic-client-secret",
+				Default: "",
+				Desc:    "generic OAuth2 client secret",
+			},
+			{
+				DestP:   &m.oauth.GenericScopes,
+				Flag:    "generic-scopes",
+				Default: []string{"user:email"},
+				Desc:    "scopes requested from the generic OAuth2 provider",
+			},
+			{
+				DestP:   &m.oauth.GenericDomains,
+				Flag:    "generic-domains",
+				Default: []string{},
+				Desc:    "email domains users of the generic OAuth2 provider are required to have",
+			},
+			{
+				DestP:   &m.oauth.GenericAuthURL,
+				Flag:    "generic-auth-url",
+				Default: "",
+				Desc:    "authorization endpoint URL of the generic OAuth2 provider",
+			},
+			{
+				DestP:   &m.oauth.GenericTokenURL,
+				Flag:    "generic-token-url",
+				Default: "",
+				Desc:    "token endpoint URL of the generic OAuth2 provider",
+			},
+			{
+				DestP:   &m.oauth.GenericAPIURL,
+				Flag:    "generic-api-url",
+				Default: "",
+				Desc:    "URL that returns OpenID UserInfo compatible information",
+			},
+			{
+				DestP:   &m.oauth.GenericAPIKey,
+				Flag:    "generic-api-key",
+				Default: "email",
+				Desc:    "JSON key of the email address in the response of the generic API URL",
+			},
 		},
 	}
 
-	cmd := cli.NewCommand(prog)
-	cmd.SetArgs(args)
-	return cmd.Execute()
+	return cli.NewCommand(prog), nil
 }
 
 func (m *Launcher) run(ctx context.Context) (err error) {
@@ -225,15 +549,32 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	// set tracing
-	tracer := new(pzap.Tracer)
-	tracer.Logger = m.logger
-	tracer.IDGenerator = snowflake.NewIDGenerator()
+	m.tracing.ServiceName = "influxd"
+	tracer, closer, err := tracing.NewTracer(m.logger.With(zap.String("service", "tracing")), m.tracing)
+	if err != nil {
+		return err
+	}
+	m.tracerCloser = closer
 	opentracing.SetGlobalTracer(tracer)
 
 	reg := prom.NewRegistry()
 	reg.MustRegister(prometheus.NewGoCollector())
 	reg.WithLogger(m.logger)
 
+	// The metadata replicated to followers is that of the bolt store.
+	if (m.replicationLeader || m.replicationLeaderURL != "") && m.storeType != "bolt" {
+		err := fmt.Errorf("replication requires the bolt store, not %q", m.storeType)
+		m.logger.Error("failed setting store", zap.Error(err))
+		return err
+	}
+
+	if m.checkMigrations {
+		if err := m.checkPendingMigrations(ctx); err != nil {
+			m.logger.Error("failed checking migrations", zap.Error(err))
+			return err
+		}
+	}
+
 	m.boltClient = bolt.NewClient()
 	m.boltClient.Path = m.boltPath
 	m.boltClient.WithLogger(m.logger.With(zap.String("service", "bolt")))
@@ -243,27 +584,54 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	var kvSvc *kv.Service
+	switch m.storeType {
+	case "bolt":
+		kvSvc = m.boltClient.Service
+	case "leveldb":
+		m.leveldbStore = leveldb.NewKVStore(m.leveldbPath)
+		m.leveldbStore.WithLogger(m.logger.With(zap.String("service", "leveldb")))
+		if err := m.leveldbStore.Open(ctx); err != nil {
+			m.logger.Error("failed opening leveldb", zap.Error(err))
+			return err
+		}
+
+		kvSvc = kv.NewService(m.leveldbStore)
+		kvSvc.WithLogger(m.logger.With(zap.String("service", "kv")))
+		if err := kvSvc.Initialize(ctx); err != nil {
+			m.logger.Error("failed initializing leveldb", zap.Error(err))
+			return err
+		}
+	default:
+		err := fmt.Errorf("unknown store %q, expected \"bolt\" or \"leveldb\"", m.storeType)
+		m.logger.Error("failed setting store", zap.Error(err))
+		return err
+	}
+
 	var (
-		orgSvc           platform.OrganizationService             = m.boltClient
-		authSvc          platform.AuthorizationService            = m.boltClient
-		userSvc          platform.UserService                     = m.boltClient
-		macroSvc         platform.MacroService                    = m.boltClient
-		bucketSvc        platform.BucketService                   = m.boltClient
-		sourceSvc        platform.SourceService                   = m.boltClient
-		sessionSvc       platform.SessionService                  = m.boltClient
-		basicAuthSvc     platform.BasicAuthService                = m.boltClient
-		dashboardSvc     platform.DashboardService                = m.boltClient
-		dashboardLogSvc  platform.DashboardOperationLogService    = m.boltClient
-		userLogSvc       platform.UserOperationLogService         = m.boltClient
-		bucketLogSvc     platform.BucketOperationLogService       = m.boltClient
-		orgLogSvc        platform.OrganizationOperationLogService = m.boltClient
-		onboardingSvc    platform.OnboardingService               = m.boltClient
-		scraperTargetSvc platform.ScraperTargetStoreService       = m.boltClient
-		telegrafSvc      platform.TelegrafConfigStore             = m.boltClient
-		userResourceSvc  platform.UserResourceMappingService      = m.boltClient
-		labelSvc         platform.LabelService                    = m.boltClient
-		secretSvc        platform.SecretService                   = m.boltClient
-		lookupSvc        platform.LookupService                   = m.boltClient
+		orgSvc                  platform.OrganizationService             = kvSvc
+		authSvc                 platform.AuthorizationService            = kvSvc
+		userSvc                 platform.UserService                     = kvSvc
+		macroSvc                platform.MacroService                    = kvSvc
+		bucketSvc               platform.BucketService                   = kvSvc
+		sourceSvc               platform.SourceService                   = kvSvc
+		sessionSvc              platform.SessionService                  = kvSvc
+		basicAuthSvc            platform.BasicAuthService                = kvSvc
+		dashboardSvc            platform.DashboardService                = kvSvc
+		dashboardLogSvc         platform.DashboardOperationLogService    = kvSvc
+		userLogSvc              platform.UserOperationLogService         = kvSvc
+		bucketLogSvc            platform.BucketOperationLogService       = kvSvc
+		orgLogSvc               platform.OrganizationOperationLogService = kvSvc
+		onboardingSvc           platform.OnboardingService               = kvSvc
+		scraperTargetSvc        platform.ScraperTargetStoreService       = kvSvc
+		telegrafSvc             platform.TelegrafConfigStore             = kvSvc
+		telegrafAgentSvc        platform.TelegrafAgentService            = kvSvc
+		userResourceSvc         platform.UserResourceMappingService      = kvSvc
+		labelSvc                platform.LabelService                    = kvSvc
+		secretSvc               platform.SecretService                   = kvSvc
+		lookupSvc               platform.LookupService                   = kvSvc
+		notificationRuleSvc     platform.NotificationRuleService         = kvSvc
+		notificationEndpointSvc platform.NotificationEndpointService     = kvSvc
 	)
 
 	switch m.secretStore {
@@ -298,7 +666,11 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	var pointsWriter storage.PointsWriter
 	{
-		m.engine = storage.NewEngine(m.enginePath, storage.NewConfig(), storage.WithRetentionEnforcer(bucketSvc))
+		config := storage.NewConfig()
+		config.WAL.Replication = m.replicationLeader
+		config.WAL.RetentionSize = toml.Size(m.replicationWALRetentionSize)
+
+		m.engine = storage.NewEngine(m.enginePath, config, storage.WithRetentionEnforcer(bucketSvc))
 		m.engine.WithLogger(m.logger)
 
 		if err := m.engine.Open(); err != nil {
@@ -310,6 +682,23 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		pointsWriter = m.engine
 
+		if m.replicationLeaderURL != "" {
+			leader := &http.ReplicationService{
+				Addr:  m.replicationLeaderURL,
+				Token: m.replicationToken,
+			}
+			m.follower = replication.NewFollower(leader, m.engine, filepath.Join(m.enginePath, "replication.json"))
+			m.follower.Metadata = m.boltClient
+			m.follower.WithLogger(m.logger)
+			if err := m.follower.Open(); err != nil {
+				m.logger.Error("failed to open replication follower", zap.Error(err))
+				return err
+			}
+			reg.MustRegister(m.follower.PrometheusCollectors()...)
+
+			pointsWriter = replication.PointsWriter{}
+		}
+
 		const (
 			concurrencyQuota = 10
 			memoryBytesQuota = 1e6
@@ -328,6 +717,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			m.logger.Error("Failed to configure query controller dependencies", zap.Error(err))
 			return err
 		}
+		if err := secrets.InjectDependencies(cc.ExecutorDependencies, secrets.Dependencies{SecretService: secretSvc}); err != nil {
+			m.logger.Error("Failed to configure query controller dependencies", zap.Error(err))
+			return err
+		}
 
 		m.queryController = pcontrol.New(cc)
 		reg.MustRegister(m.queryController.PrometheusCollectors()...)
@@ -335,6 +728,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	var storageQueryService query.ProxyQueryService = readservice.NewProxyQueryService(m.queryController)
 	var taskSvc platform.TaskService
+	var checkSvc platform.CheckService
 	{
 		boltStore, err := taskbolt.New(m.boltClient.DB(), "tasks")
 		if err != nil {
@@ -346,13 +740,21 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		lw := taskbackend.NewPointLogWriter(pointsWriter)
 		m.scheduler = taskbackend.NewScheduler(boltStore, executor, lw, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger))
-		m.scheduler.Start(ctx)
 		reg.MustRegister(m.scheduler.PrometheusCollectors()...)
 
 		queryService := query.QueryServiceBridge{AsyncQueryService: m.queryController}
 		lr := taskbackend.NewQueryLogReader(queryService)
-		taskSvc = task.PlatformAdapter(coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, boltStore), lr, m.scheduler)
+		if m.follower != nil {
+			// The tasks of the leader run on the leader; a follower only serves them.
+			taskSvc = task.PlatformAdapter(boltStore, lr, m.scheduler)
+		} else {
+			m.scheduler.Start(ctx)
+			taskSvc = task.PlatformAdapter(coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, boltStore), lr, m.scheduler)
+		}
 		taskSvc = task.NewValidator(taskSvc, bucketSvc)
+		// The query of a check runs in its task, so the buckets it reads are
+		// authorized by the validator like those of any other task.
+		checkSvc = monitor.NewCheckService(kvSvc, taskSvc, bucketSvc)
 	}
 
 	// NATS streaming server
@@ -387,15 +789,40 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
-	m.wg.Add(1)
-	go func(logger *zap.Logger) {
-		defer m.wg.Done()
-		logger = logger.With(zap.String("service", "scraper"))
-		if err := scraperScheduler.Run(ctx); err != nil {
-			logger.Error("failed scraper service", zap.Error(err))
-		}
-		logger.Info("Stopping")
-	}(m.logger)
+	// Replication followers are read-only, and the leader scrapes the targets
+	// and sends the notifications.
+	if m.follower == nil {
+		m.wg.Add(1)
+		go func(logger *zap.Logger) {
+			defer m.wg.Done()
+			logger = logger.With(zap.String("service", "scraper"))
+			if err := scraperScheduler.Run(ctx); err != nil {
+				logger.Error("failed scraper service", zap.Error(err))
+			}
+			logger.Info("Stopping")
+		}(m.logger)
+
+		notifier := monitor.NewNotifier(notificationRuleSvc, notificationEndpointSvc, query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.logger.With(zap.String("service", "notifier")))
+		m.wg.Add(1)
+		go func(logger *zap.Logger) {
+			defer m.wg.Done()
+			notifier.Run(ctx)
+			logger.Info("Stopping")
+		}(notifier.Logger)
+	}
+
+	// Replication followers are read-only, so only leaders write their metrics.
+	if m.monitoringInterval > 0 && m.follower == nil {
+		selfMonitor := monitor.NewSelfMonitor(reg, gather.PointWriter{Writer: pointsWriter}, orgSvc, bucketSvc, m.monitoringInstance, m.monitoringOrg, m.logger.With(zap.String("service", "self-monitor")))
+		selfMonitor.Interval = m.monitoringInterval
+		selfMonitor.Retention = m.monitoringRetention
+		m.wg.Add(1)
+		go func(logger *zap.Logger) {
+			defer m.wg.Done()
+			selfMonitor.Run(ctx)
+			logger.Info("Stopping")
+		}(selfMonitor.Logger)
+	}
 
 	m.httpServer = &nethttp.Server{
 		Addr: m.httpBindAddress,
@@ -407,6 +834,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
+		CompactionService:    m.engine,
+		ReplicationService:   m.engine,
 		AuthorizationService: authSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
 		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
@@ -417,6 +846,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		LabelService:                    labelSvc,
 		DashboardService:                dashboardSvc,
 		DashboardOperationLogService:    dashboardLogSvc,
+		DashboardTemplateService:        dashboards.NewTemplateService(dashboardSvc, macroSvc, labelSvc, bucketSvc),
 		BucketOperationLogService:       bucketLogSvc,
 		UserOperationLogService:         userLogSvc,
 		OrganizationOperationLogService: orgLogSvc,
@@ -425,13 +855,27 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		BasicAuthService:                basicAuthSvc,
 		OnboardingService:               onboardingSvc,
 		ProxyQueryService:               storageQueryService,
+		QueryService:                    query.QueryServiceBridge{AsyncQueryService: m.queryController},
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
+		TelegrafAgentService:            telegrafAgentSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
 		SecretService:                   secretSvc,
 		LookupService:                   lookupSvc,
 		ProtoService:                    protoSvc,
+		CheckService:                    checkSvc,
+		NotificationRuleService:         notificationRuleSvc,
+		NotificationEndpointService:     notificationEndpointSvc,
+	}
+
+	if m.replicationLeader {
+		handlerConfig.ReplicationMetadataStore = m.boltClient
+	}
+
+	if err := m.configureOAuth(handlerConfig, kvSvc); err != nil {
+		m.logger.Error("failed to configure OAuth2 providers", zap.Error(err))
+		return err
 	}
 
 	// HTTP server
@@ -441,8 +885,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	h := http.NewHandlerFromRegistry("platform", reg)
 	h.Handler = platformHandler
+	if m.follower != nil {
+		h.Handler = http.ReadOnlyHandler{Handler: platformHandler}
+	}
 	h.Logger = httpLogger
 	h.Tracer = opentracing.GlobalTracer()
+	checks := m.checks()
+	h.HealthHandler = checks
+	h.ReadyHandler = checks
 
 	m.httpServer.Handler = h
 
@@ -470,3 +920,101 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	return nil
 }
+
+// checks returns the health and readiness checks of the components.  They
+// fail when a component is closed or has failed, so that influxd is
+// restarted when a component is wedged.
+// checkPendingMigrations returns an error if the metadata stores have
+// pending migrations, without applying them.  New stores have none.
+func (m *Launcher) checkPendingMigrations(ctx context.Context) error {
+	type store interface {
+		kv.Store
+		Open(context.Context) error
+		Close() error
+	}
+	paths := []string{m.boltPath}
+	stores := []store{bolt.NewKVStore(m.boltPath)}
+	if m.storeType == "leveldb" {
+		paths = append(paths, m.leveldbPath)
+		stores = append(stores, leveldb.NewKVStore(m.leveldbPath))
+	}
+
+	for i, s := range stores {
+		if _, err := os.Stat(paths[i]); os.IsNotExist(err) {
+			continue
+		}
+		if err := s.Open(ctx); err != nil {
+			return err
+		}
+		states, err := kv.NewService(s).Migrator().List(ctx)
+		if err := s.Close(); err != nil {
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		var pending []string
+		for _, st := range states {
+			if st.AppliedAt == nil {
+				pending = append(pending, st.Name)
+			}
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%s has %d pending migrations (%s); apply them with influxd migrate", paths[i], len(pending), strings.Join(pending, ", "))
+		}
+	}
+	return nil
+}
+
+func (m *Launcher) checks() *check.Check {
+	checkers := []check.NamedChecker{
+		m.boltClient,
+		m.engine,
+		m.natsServer,
+		m.queryController,
+	}
+	// The scheduler of a replication follower is not started.
+	if m.follower == nil {
+		checkers = append(checkers, m.scheduler)
+	}
+	if m.leveldbStore != nil {
+		checkers = append(checkers, m.leveldbStore)
+	}
+
+	c := check.NewCheck()
+	for _, checker := range checkers {
+		c.AddHealthCheck(checker)
+		c.AddReadyCheck(checker)
+	}
+	return c
+}
+
+// configureOAuth sets the OAuth2 providers users can sign in with.  The
+// identities of the users at the providers are stored in identities.
+func (m *Launcher) configureOAuth(b *http.APIBackend, identities platform.OAuthIdentityService) error {
+	providers, err := m.oauth.Providers(oauth.NewLogger(m.logger.With(zap.String("service", "oauth"))))
+	if err != nil {
+		return err
+	}
+
+	provisioner := &oauth.Provisioner{
+		UserService:                b.UserService,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		OAuthIdentityService:       identities,
+	}
+	for _, s := range m.oauthMappings {
+		mapping, err := oauth.ParseMapping(s)
+		if err != nil {
+			return err
+		}
+		provisioner.Mappings = append(provisioner.Mappings, mapping)
+	}
+
+	b.OAuthProviders = providers
+	b.OAuthTokenizer = oauth2.NewJWT(m.oauth.TokenSecret, m.oauth.JwksURL)
+	b.OAuthUseIDToken = m.oauth.UseIDToken
+	b.OAuthProvisioner = provisioner
+	return nil
+}
@@ -3,19 +3,24 @@ package launcher_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	nethttp "net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kit/check"
 	_ "github.com/influxdata/influxdb/query/builtin"
 )
 
@@ -48,6 +53,63 @@ func TestLauncher_Setup(t *testing.T) {
 	}
 }
 
+func TestLauncher_SetupLevelDB(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx, "--store", "leveldb")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	svc := &http.BucketService{Addr: l.URL(), Token: l.Auth.Token}
+	b, err := svc.FindBucketByID(ctx, l.Bucket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Name != "BUCKET" {
+		t.Fatalf("got bucket %q, want BUCKET", b.Name)
+	}
+	if _, err := os.Stat(filepath.Join(l.Path, "influxd.leveldb")); err != nil {
+		t.Fatalf("leveldb database was not created: %v", err)
+	}
+}
+
+func TestLauncher_Health(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx)
+	defer l.ShutdownOrFail(t, ctx)
+
+	for _, path := range []string{"/health", "/ready"} {
+		resp, err := nethttp.Get(l.URL() + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got check.Response
+		err = json.NewDecoder(resp.Body).Decode(&got)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != nethttp.StatusOK || got.Status != check.StatusPass {
+			t.Fatalf("got status %d and %+v for %s", resp.StatusCode, got, path)
+		}
+		for _, name := range []string{"bolt", "nats", "query-controller", "scheduler", "storage-engine"} {
+			if !got.HasCheck(name) {
+				t.Errorf("%s has no check of %s: %+v", path, name, got)
+			}
+		}
+	}
+
+	// A closed component fails the health check.
+	if err := l.Engine().Close(); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := nethttp.Get(l.URL() + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusServiceUnavailable {
+		t.Errorf("got status %d with a closed engine, want %d", resp.StatusCode, nethttp.StatusServiceUnavailable)
+	}
+}
+
 func TestLauncher_WriteAndQuery(t *testing.T) {
 	l := RunLauncherOrFail(t, ctx)
 	l.SetupOrFail(t)
@@ -88,6 +150,255 @@ func TestLauncher_WriteAndQuery(t *testing.T) {
 	}
 }
 
+func TestLauncher_QuerySchema(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID), "m,k=v1 f=1i 946684800000000000\nm,k=v2 f=2i 946771200000000000\nn,j=w g=3i 946684800000000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	tests := []struct {
+		query string
+		exp   string
+	}{
+		{
+			query: `schema.tagKeys(bucket:"BUCKET") |> range(start:2000-01-01T00:00:00Z, stop:2000-01-03T00:00:00Z)`,
+			exp: `,result,table,_value` + "\r\n" +
+				`,result,table,_field` + "\r\n" +
+				`,result,table,_measurement` + "\r\n" +
+				`,result,table,j` + "\r\n" +
+				`,result,table,k` + "\r\n\r\n",
+		},
+		{
+			query: `schema.tagValues(bucket:"BUCKET", tag:"k") |> range(start:2000-01-01T00:00:00Z, stop:2000-01-01T12:00:00Z)`,
+			exp: `,result,table,_value` + "\r\n" +
+				`,result,table,v1` + "\r\n\r\n",
+		},
+		{
+			query: `schema.measurements(bucket:"BUCKET", predicate:(r) => r.j == "w") |> range(start:2000-01-01T00:00:00Z)`,
+			exp: `,result,table,_value` + "\r\n" +
+				`,result,table,n` + "\r\n\r\n",
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		req := (http.QueryRequest{Query: `import "influxdata/influxdb/schema"` + "\n" + tt.query, Org: l.Org}).WithDefaults()
+		if preq, err := req.ProxyRequest(); err != nil {
+			t.Fatal(err)
+		} else if _, err := l.FluxService().Query(ctx, &buf, preq); err != nil {
+			t.Fatal(err)
+		} else if diff := cmp.Diff(buf.String(), tt.exp); diff != "" {
+			t.Errorf("%s: %s", tt.query, diff)
+		}
+	}
+}
+
+func TestLauncher_QueryValueFilter(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID), "m f=1 946684800000000000\nm f=95 946684801000000000\nn f=100 946684800000000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	tests := []struct {
+		query string
+		exp   string
+	}{
+		{
+			query: `from(bucket:"BUCKET") |> range(start:2000-01-01T00:00:00Z, stop:2000-01-02T00:00:00Z) |> filter(fn: (r) => r._value > 90.0) |> keep(columns: ["_time", "_value", "_measurement"])`,
+			exp: `,result,table,_time,_value,_measurement` + "\r\n" +
+				`,result,table,2000-01-01T00:00:01Z,95,m` + "\r\n" +
+				`,,,2000-01-01T00:00:00Z,100,n` + "\r\n\r\n",
+		},
+		{
+			query: `from(bucket:"BUCKET") |> range(start:2000-01-01T00:00:00Z, stop:2000-01-02T00:00:00Z) |> filter(fn: (r) => r._measurement == "m" and r._value < 90.0) |> keep(columns: ["_time", "_value", "_measurement"])`,
+			exp: `,result,table,_time,_value,_measurement` + "\r\n" +
+				`,result,table,2000-01-01T00:00:00Z,1,m` + "\r\n\r\n",
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		req := (http.QueryRequest{Query: tt.query, Org: l.Org}).WithDefaults()
+		if preq, err := req.ProxyRequest(); err != nil {
+			t.Fatal(err)
+		} else if _, err := l.FluxService().Query(ctx, &buf, preq); err != nil {
+			t.Fatal(err)
+		} else if diff := cmp.Diff(buf.String(), tt.exp); diff != "" {
+			t.Errorf("%s: %s", tt.query, diff)
+		}
+	}
+}
+
+func TestLauncher_QuerySecrets(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	secrets := &http.SecretService{Addr: l.URL(), Token: l.Auth.Token}
+	if err := secrets.PutSecrets(ctx, l.Org.ID, map[string]string{"token": "s3cr3t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID), `m,k=v f=100i 946684800000000000`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	tokens := make(chan string, 1)
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		select {
+		case tokens <- r.URL.Query().Get("token"):
+		default:
+		}
+	}))
+	defer server.Close()
+
+	query := func(q string) (string, error) {
+		var buf bytes.Buffer
+		req := (http.QueryRequest{Query: "import \"http\"\nimport \"influxdata/influxdb/secrets\"\n" + q, Org: l.Org}).WithDefaults()
+		preq, err := req.ProxyRequest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = l.FluxService().Query(ctx, &buf, preq)
+		return buf.String(), err
+	}
+
+	// The url of http.to() is resolved when the query is executed.
+	if _, err := query(fmt.Sprintf(`from(bucket: %q) |> range(start:2000-01-01T00:00:00Z,stop:2000-01-02T00:00:00Z) |> http.to(url: %q + secrets.get(key: "token"))`, l.Bucket.Name, server.URL+"/?token=")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case token := <-tokens:
+		if token != "s3cr3t" {
+			t.Fatalf("expected the secret in the url, got %q", token)
+		}
+	default:
+		t.Fatal("expected a request to the url of http.to()")
+	}
+
+	// Anywhere else, the query only sees the reference to the secret.
+	got, err := query(fmt.Sprintf(`from(bucket: %q) |> range(start:2000-01-01T00:00:00Z,stop:2000-01-02T00:00:00Z) |> set(key: "k", value: secrets.get(key: "token"))`, l.Bucket.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "s3cr3t") || !strings.Contains(got, "${secret:") {
+		t.Fatalf("expected the reference to the secret in the result, got %q", got)
+	}
+
+	// A reference that was not returned by secrets.get is not resolved.
+	got, err = query(fmt.Sprintf(`from(bucket: %q) |> range(start:2000-01-01T00:00:00Z,stop:2000-01-02T00:00:00Z) |> http.to(url: %q + "${" + "secret:dG9rZW4}")`, l.Bucket.Name, server.URL+"/?token="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "invalid reference to a secret") {
+		t.Fatalf("expected error resolving a forged reference, got %q", got)
+	}
+	select {
+	case token := <-tokens:
+		t.Fatalf("expected no request to the url of http.to(), got token %q", token)
+	default:
+	}
+}
+
+func TestLauncher_CreateCheck(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	newCheck := func() *platform.Check {
+		return &platform.Check{
+			OrganizationID: l.Org.ID,
+			Name:           "cpu",
+			Type:           platform.ThresholdCheckType,
+			Query:          fmt.Sprintf(`from(bucket: %q) |> range(start: -1m)`, l.Bucket.Name),
+			Every:          "1m",
+			Thresholds: []platform.Threshold{
+				{Level: platform.LevelCrit, Op: platform.ThresholdGreater, Value: 90},
+			},
+		}
+	}
+
+	// A token that may write checks and tasks but not read the bucket of
+	// the query cannot create the check.
+	auth := &platform.Authorization{
+		OrgID:  l.Org.ID,
+		UserID: l.User.ID,
+		Permissions: []platform.Permission{
+			{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.ChecksResourceType, OrgID: &l.Org.ID}},
+			{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.TasksResourceType, OrgID: &l.Org.ID}},
+		},
+	}
+	if err := (&http.AuthorizationService{Addr: l.URL(), Token: l.Auth.Token}).CreateAuthorization(ctx, auth); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&http.CheckService{Addr: l.URL(), Token: auth.Token}).CreateCheck(ctx, newCheck()); err == nil {
+		t.Fatal("expected an error creating a check without permission to read its bucket")
+	}
+
+	check := newCheck()
+	if err := (&http.CheckService{Addr: l.URL(), Token: l.Auth.Token}).CreateCheck(ctx, check); err != nil {
+		t.Fatal(err)
+	}
+	if !check.TaskID.Valid() {
+		t.Fatalf("expected the check to be scheduled: %+v", check)
+	}
+}
+
+func TestLauncher_SelfMonitoring(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx, "--monitoring-interval", "100ms", "--monitoring-instance", "test")
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	// The metrics of influxd are written to the bucket of the instance in the
+	// organization created by the setup.
+	qs := `from(bucket:"_monitoring_test") |> range(start:-1h) |> filter(fn: (r) => r._measurement == "go_goroutines") |> keep(columns: ["_field", "instance"]) |> limit(n: 1)`
+	exp := `,result,table,_field,instance` + "\r\n" +
+		`,result,table,gauge,test` + "\r\n\r\n"
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var buf bytes.Buffer
+		req := (http.QueryRequest{Query: qs, Org: l.Org}).WithDefaults()
+		preq, err := req.ProxyRequest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = l.FluxService().Query(ctx, &buf, preq)
+		if err == nil && buf.String() == exp {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the metrics of influxd in the _monitoring_test bucket, got %q, %v", buf.String(), err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
 func TestLauncher_BucketDelete(t *testing.T) {
 	l := RunLauncherOrFail(t, ctx)
 	l.SetupOrFail(t)
@@ -176,6 +487,61 @@ type Launcher struct {
 }
 
 // NewLauncher returns a new instance of Launcher.
+func TestLauncher_ReplicationFollower(t *testing.T) {
+	// Followers replicate the metadata of the bolt store.
+	l := NewLauncher()
+	if err := l.Run(ctx, "--replication-leader-url", "http://127.0.0.1:1", "--store", "leveldb"); err == nil {
+		l.Shutdown(ctx)
+		t.Fatal("expected error running a follower with the leveldb store")
+	}
+	os.RemoveAll(l.Path)
+
+	l = RunLauncherOrFail(t, ctx, "--replication-leader-url", "http://127.0.0.1:1")
+	defer l.ShutdownOrFail(t, ctx)
+
+	// The metadata of a follower is that of its leader, so it is not set up.
+	resp, err := nethttp.Post(l.URL()+"/api/v2/setup", "application/json", strings.NewReader(`{"username":"USER","password":"PASSWORD","org":"ORG","bucket":"BUCKET"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusForbidden {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	for _, path := range []string{"/health", "/ready"} {
+		resp, err := nethttp.Get(l.URL() + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != nethttp.StatusOK {
+			t.Fatalf("%s: unexpected status code: %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestLauncher_CheckMigrations(t *testing.T) {
+	// A store of a version without migrations has them all pending.
+	l := NewLauncher()
+	defer os.RemoveAll(l.Path)
+	store := bolt.NewKVStore(filepath.Join(l.Path, "influxd.bolt"))
+	if err := store.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Run(ctx, "--check-migrations"); err == nil {
+		l.Shutdown(ctx)
+		t.Fatal("expected error running with pending migrations")
+	}
+
+	// New stores have no pending migrations.
+	l = RunLauncherOrFail(t, ctx, "--check-migrations")
+	l.ShutdownOrFail(t, ctx)
+}
+
 func NewLauncher() *Launcher {
 	l := &Launcher{Launcher: launcher.NewLauncher()}
 	l.Launcher.Stdin = &l.Stdin
@@ -207,6 +573,7 @@ func RunLauncherOrFail(tb testing.TB, ctx context.Context, args ...string) *Laun
 // Run executes the program with additional arguments to set paths and ports.
 func (l *Launcher) Run(ctx context.Context, args ...string) error {
 	args = append(args, "--bolt-path", filepath.Join(l.Path, "influxd.bolt"))
+	args = append(args, "--leveldb-path", filepath.Join(l.Path, "influxd.leveldb"))
 	args = append(args, "--protos-path", filepath.Join(l.Path, "protos"))
 	args = append(args, "--engine-path", filepath.Join(l.Path, "engine"))
 	args = append(args, "--nats-path", filepath.Join(l.Path, "nats"))
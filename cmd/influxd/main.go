@@ -7,7 +7,9 @@ import (
 	"os"
 	"time"
 
+	"github.com/influxdata/influxdb/cmd/influxd/generate"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/cmd/influxd/migrate"
 	"github.com/influxdata/influxdb/kit/signals"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1"
@@ -20,9 +22,22 @@ func main() {
 	ctx = signals.WithStandardSignals(ctx)
 
 	m := launcher.NewLauncher()
-	if err := m.Run(ctx, os.Args[1:]...); err != nil {
+	rootCmd, err := m.NewCommand(ctx)
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
+	}
+	rootCmd.AddCommand(generate.Command)
+	rootCmd.AddCommand(migrate.Command)
+	rootCmd.AddCommand(migrate.ChronografCommand)
+
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	} else if cmd != rootCmd {
+		// A subcommand ran to completion.
+		return
 	} else if !m.Running() {
 		os.Exit(1)
 	}
@@ -0,0 +1,198 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/leveldb"
+	"github.com/spf13/cobra"
+)
+
+// Command is the "influxd migrate" command.
+var Command = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply the pending migrations of the metadata store",
+	Long: `Apply the pending migrations of the metadata store. influxd applies them
+when it starts, unless started with --check-migrations; this command applies
+them, or reports them with --dry-run, beforehand. influxd must not be running.`,
+	Args: cobra.NoArgs,
+	RunE: migrateUpF,
+}
+
+var listCommand = &cobra.Command{
+	Use:   "list",
+	Short: "List the migrations of the metadata store",
+	Args:  cobra.NoArgs,
+	RunE:  migrateListF,
+}
+
+var downCommand = &cobra.Command{
+	Use:   "down",
+	Short: "Revert the last migrations of the metadata store",
+	Long: `Revert the last migrations of the metadata store before downgrading
+influxd. Starting this version of influxd again applies them again.`,
+	Args: cobra.NoArgs,
+	RunE: migrateDownF,
+}
+
+var migrateFlags struct {
+	boltPath    string
+	leveldbPath string
+	storeType   string
+	dryRun      bool
+	steps       int
+}
+
+func init() {
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to determine influx directory: %v", err))
+	}
+
+	Command.PersistentFlags().StringVar(&migrateFlags.boltPath, "bolt-path", filepath.Join(dir, "influxd.bolt"), "path to boltdb database")
+	Command.PersistentFlags().StringVar(&migrateFlags.leveldbPath, "leveldb-path", filepath.Join(dir, "influxd.leveldb"), "path to leveldb database, used with --store leveldb")
+	Command.PersistentFlags().StringVar(&migrateFlags.storeType, "store", "bolt", "data store for the metadata services (bolt or leveldb)")
+	Command.Flags().BoolVar(&migrateFlags.dryRun, "dry-run", false, "report the pending migrations without applying them")
+	downCommand.Flags().BoolVar(&migrateFlags.dryRun, "dry-run", false, "report the migrations that would be reverted without reverting them")
+	downCommand.Flags().IntVar(&migrateFlags.steps, "steps", 1, "number of migrations to revert")
+
+	Command.AddCommand(listCommand, downCommand)
+}
+
+// openMigrator opens the metadata store and returns its migrator.
+func openMigrator(ctx context.Context) (*kv.Migrator, func() error, error) {
+	var store interface {
+		kv.Store
+		Open(context.Context) error
+		Close() error
+	}
+	switch migrateFlags.storeType {
+	case "bolt":
+		if _, err := os.Stat(migrateFlags.boltPath); err != nil {
+			return nil, nil, err
+		}
+		store = bolt.NewKVStore(migrateFlags.boltPath)
+	case "leveldb":
+		if _, err := os.Stat(migrateFlags.leveldbPath); err != nil {
+			return nil, nil, err
+		}
+		store = leveldb.NewKVStore(migrateFlags.leveldbPath)
+	default:
+		return nil, nil, fmt.Errorf("unknown store %q, expected \"bolt\" or \"leveldb\"", migrateFlags.storeType)
+	}
+
+	if err := store.Open(ctx); err != nil {
+		return nil, nil, err
+	}
+	return kv.NewService(store).Migrator(), store.Close, nil
+}
+
+func migrateUpF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	m, closeFn, err := openMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	w := cmd.OutOrStdout()
+	if migrateFlags.dryRun {
+		states, err := m.List(ctx)
+		if err != nil {
+			return err
+		}
+		pending := 0
+		for _, s := range states {
+			if s.AppliedAt == nil {
+				fmt.Fprintf(w, "Would apply %s\n", s.Name)
+				pending++
+			}
+		}
+		fmt.Fprintf(w, "%d pending migrations.\n", pending)
+		return nil
+	}
+
+	n, err := m.Up(ctx)
+	fmt.Fprintf(w, "Applied %d migrations.\n", n)
+	return err
+}
+
+func migrateListF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	m, closeFn, err := openMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	states, err := m.List(ctx)
+	if err != nil {
+		return err
+	}
+	writeMigrationStates(cmd.OutOrStdout(), states)
+	return nil
+}
+
+func migrateDownF(cmd *cobra.Command, args []string) error {
+	if migrateFlags.steps < 1 {
+		return errors.New("steps must be at least 1")
+	}
+
+	ctx := context.Background()
+	m, closeFn, err := openMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	w := cmd.OutOrStdout()
+	if migrateFlags.dryRun {
+		states, err := m.List(ctx)
+		if err != nil {
+			return err
+		}
+		n := migrateFlags.steps
+		for i := len(states) - 1; i >= 0 && n > 0; i-- {
+			if states[i].AppliedAt == nil {
+				continue
+			}
+			if states[i].Reversible {
+				fmt.Fprintf(w, "Would revert %s\n", states[i].Name)
+			} else {
+				fmt.Fprintf(w, "Cannot revert %s\n", states[i].Name)
+			}
+			n--
+		}
+		return nil
+	}
+
+	n, err := m.Down(ctx, migrateFlags.steps)
+	fmt.Fprintf(w, "Reverted %d migrations.\n", n)
+	return err
+}
+
+func writeMigrationStates(w io.Writer, states []kv.MigrationState) {
+	if len(states) == 0 {
+		fmt.Fprintln(w, "No migrations.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "Name\tApplied")
+	for _, s := range states {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", s.Name, applied)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,397 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/influx"
+	"github.com/influxdata/influxql"
+)
+
+// Report describes the result of a migration.
+type Report struct {
+	Sources    int
+	Dashboards int
+	Cells      int
+	Macros     int
+
+	// Buckets are the names of the buckets referenced by the Flux queries of
+	// the migrated dashboards.  They are not created by the migration.
+	Buckets []string
+
+	// Warnings describe what could not be converted.
+	Warnings []string
+}
+
+func (r *Report) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Migrator creates the sources, dashboards and templates of a Chronograf 1.x
+// database in an organization.
+type Migrator struct {
+	OrganizationID   platform.ID
+	SourceService    platform.SourceService
+	DashboardService platform.DashboardService
+	MacroService     platform.MacroService
+
+	// DryRun converts without creating anything; the services are not used.
+	DryRun bool
+}
+
+// Migrate converts and creates sources and dashboards.  Dashboard templates
+// are created as macros of the organization unless it has macros with the same
+// names.
+func (m *Migrator) Migrate(ctx context.Context, sources []chronograf.Source, dashboards []chronograf.Dashboard) (*Report, error) {
+	r := &Report{}
+	c := &converter{
+		report:    r,
+		sourceIDs: make(map[int]platform.ID, len(sources)),
+		buckets:   make(map[string]bool),
+	}
+
+	sort.Slice(sources, func(i, j int) bool { return sources[i].ID < sources[j].ID })
+	for _, src := range sources {
+		s := convertSource(src)
+		s.OrganizationID = m.OrganizationID
+		if !m.DryRun {
+			if err := m.SourceService.CreateSource(ctx, s); err != nil {
+				return nil, fmt.Errorf("error creating source %q: %v", src.Name, err)
+			}
+		}
+		c.sourceIDs[src.ID] = s.ID
+		if src.Default {
+			c.defaultSourceID = s.ID
+		}
+		r.Sources++
+	}
+
+	macros := make(map[string]bool)
+	if !m.DryRun {
+		existing, err := m.MacroService.FindMacros(ctx, platform.MacroFilter{OrganizationID: &m.OrganizationID})
+		if err != nil {
+			return nil, err
+		}
+		for _, macro := range existing {
+			macros[macro.Name] = true
+		}
+	}
+
+	sort.Slice(dashboards, func(i, j int) bool { return dashboards[i].ID < dashboards[j].ID })
+	for _, cd := range dashboards {
+		for _, t := range cd.Templates {
+			macro, err := convertTemplate(t)
+			if err != nil {
+				r.warnf("dashboard %q: template %q not migrated: %v", cd.Name, t.Var, err)
+				continue
+			}
+			if macros[macro.Name] {
+				r.warnf("dashboard %q: template %q not migrated: macro %q already exists", cd.Name, t.Var, macro.Name)
+				continue
+			}
+			macro.OrganizationID = m.OrganizationID
+			if !m.DryRun {
+				if err := m.MacroService.CreateMacro(ctx, macro); err != nil {
+					return nil, fmt.Errorf("error creating macro %q: %v", macro.Name, err)
+				}
+			}
+			macros[macro.Name] = true
+			r.Macros++
+		}
+
+		d := &platform.Dashboard{
+			OrganizationID: m.OrganizationID,
+			Name:           cd.Name,
+		}
+		if !m.DryRun {
+			if err := m.DashboardService.CreateDashboard(ctx, d); err != nil {
+				return nil, fmt.Errorf("error creating dashboard %q: %v", cd.Name, err)
+			}
+		}
+		for _, cc := range cd.Cells {
+			cell, view, ok := c.convertCell(cd.Name, cc)
+			if !ok {
+				continue
+			}
+			if !m.DryRun {
+				if err := m.DashboardService.AddDashboardCell(ctx, d.ID, cell, platform.AddDashboardCellOptions{View: view}); err != nil {
+					return nil, fmt.Errorf("error creating cell %q of dashboard %q: %v", cc.Name, cd.Name, err)
+				}
+			}
+			r.Cells++
+		}
+		r.Dashboards++
+	}
+
+	for b := range c.buckets {
+		r.Buckets = append(r.Buckets, b)
+	}
+	sort.Strings(r.Buckets)
+	return r, nil
+}
+
+// convertSource converts a Chronograf source to an InfluxDB 1.x source.
+func convertSource(src chronograf.Source) *platform.Source {
+	return &platform.Source{
+		Default:            src.Default,
+		Name:               src.Name,
+		Type:               platform.V1SourceType,
+		URL:                src.URL,
+		InsecureSkipVerify: src.InsecureSkipVerify,
+		Telegraf:           src.Telegraf,
+		V1SourceFields: platform.V1SourceFields{
+			Username:     src.Username,
+			Password:     src.Password,
+			SharedSecret: src.SharedSecret,
+			MetaURL:      src.MetaURL,
+			DefaultRP:    src.DefaultRP,
+		},
+	}
+}
+
+// convertTemplate converts a dashboard template to a macro.  Templates of
+// values become constant or map macros; templates of queries become InfluxQL
+// query macros.
+func convertTemplate(t chronograf.Template) (*platform.Macro, error) {
+	name := strings.Trim(t.Var, ":")
+	if name == "" {
+		return nil, fmt.Errorf("template has no name")
+	}
+
+	m := &platform.Macro{
+		Name:     name,
+		Selected: []string{},
+	}
+
+	switch t.Type {
+	case "csv", "constant", "text":
+		values := platform.MacroConstantValues{}
+		for _, v := range t.Values {
+			values = append(values, v.Value)
+			if v.Selected {
+				m.Selected = append(m.Selected, v.Value)
+			}
+		}
+		m.Arguments = &platform.MacroArguments{Type: "constant", Values: values}
+	case "map":
+		values := platform.MacroMapValues{}
+		for _, v := range t.Values {
+			values[v.Key] = v.Value
+			if v.Selected {
+				m.Selected = append(m.Selected, v.Key)
+			}
+		}
+		m.Arguments = &platform.MacroArguments{Type: "map", Values: values}
+	case "databases", "measurements", "fieldKeys", "tagKeys", "tagValues", "influxql":
+		if t.Query == nil || t.Query.Command == "" {
+			return nil, fmt.Errorf("template of type %q has no query", t.Type)
+		}
+		for _, v := range t.Values {
+			if v.Selected {
+				m.Selected = append(m.Selected, v.Value)
+			}
+		}
+		m.Arguments = &platform.MacroArguments{
+			Type: "query",
+			Values: platform.MacroQueryValues{
+				Query:    renderTemplateQuery(t.Query),
+				Language: "influxql",
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported template type %q", t.Type)
+	}
+	return m, nil
+}
+
+// renderTemplateQuery replaces the placeholders of the query of a template by
+// the database, measurement and keys it was saved with.
+func renderTemplateQuery(q *chronograf.TemplateQuery) string {
+	return strings.NewReplacer(
+		":database:", influxql.QuoteIdent(q.DB),
+		":measurement:", influxql.QuoteIdent(q.Measurement),
+		":tagKey:", influxql.QuoteIdent(q.TagKey),
+		":fieldKey:", influxql.QuoteIdent(q.FieldKey),
+	).Replace(q.Command)
+}
+
+// converter converts dashboard cells, referring to the sources created by the
+// migration.
+type converter struct {
+	report          *Report
+	sourceIDs       map[int]platform.ID
+	defaultSourceID platform.ID
+	buckets         map[string]bool
+}
+
+// convertCell converts a dashboard cell to a cell and its view.  false is
+// returned if the type of the cell is not supported.
+func (c *converter) convertCell(dashboard string, cc chronograf.DashboardCell) (*platform.Cell, *platform.View, bool) {
+	queries := make([]platform.DashboardQuery, 0, len(cc.Queries))
+	for i, q := range cc.Queries {
+		dq, err := c.convertQuery(q)
+		if err != nil {
+			c.report.warnf("dashboard %q: cell %q: query %d kept as InfluxQL: %v", dashboard, cc.Name, i, err)
+		}
+		queries = append(queries, dq)
+	}
+
+	axes := make(map[string]platform.Axis, len(cc.Axes))
+	for k, a := range cc.Axes {
+		axes[k] = platform.Axis{
+			Bounds: a.Bounds,
+			Label:  a.Label,
+			Prefix: a.Prefix,
+			Suffix: a.Suffix,
+			Base:   a.Base,
+			Scale:  a.Scale,
+		}
+	}
+	colors := make([]platform.ViewColor, 0, len(cc.CellColors))
+	for _, cl := range cc.CellColors {
+		// Colors of scales have no value.
+		v, _ := strconv.ParseFloat(cl.Value, 64)
+		colors = append(colors, platform.ViewColor{
+			ID:    cl.ID,
+			Type:  cl.Type,
+			Hex:   cl.Hex,
+			Name:  cl.Name,
+			Value: v,
+		})
+	}
+	legend := platform.Legend{Type: cc.Legend.Type, Orientation: cc.Legend.Orientation}
+	decimalPlaces := platform.DecimalPlaces{IsEnforced: cc.DecimalPlaces.IsEnforced, Digits: cc.DecimalPlaces.Digits}
+	prefix, suffix := cc.Axes["y"].Prefix, cc.Axes["y"].Suffix
+
+	var props platform.ViewProperties
+	switch cc.Type {
+	case "line", "line-stacked", "line-stepplot", "bar":
+		props = platform.XYViewProperties{
+			Type:       "xy",
+			Queries:    queries,
+			Axes:       axes,
+			Legend:     legend,
+			Geom:       geoms[cc.Type],
+			ViewColors: colors,
+		}
+	case "line-plus-single-stat":
+		props = platform.LinePlusSingleStatProperties{
+			Type:          "line-plus-single-stat",
+			Queries:       queries,
+			Axes:          axes,
+			Legend:        legend,
+			ViewColors:    colors,
+			Prefix:        prefix,
+			Suffix:        suffix,
+			DecimalPlaces: decimalPlaces,
+		}
+	case "single-stat":
+		props = platform.SingleStatViewProperties{
+			Type:          "single-stat",
+			Queries:       queries,
+			Prefix:        prefix,
+			Suffix:        suffix,
+			ViewColors:    colors,
+			DecimalPlaces: decimalPlaces,
+		}
+	case "gauge":
+		props = platform.GaugeViewProperties{
+			Type:          "gauge",
+			Queries:       queries,
+			Prefix:        prefix,
+			Suffix:        suffix,
+			ViewColors:    colors,
+			DecimalPlaces: decimalPlaces,
+		}
+	case "table":
+		fields := make([]platform.RenamableField, 0, len(cc.FieldOptions))
+		for _, f := range cc.FieldOptions {
+			fields = append(fields, platform.RenamableField(f))
+		}
+		props = platform.TableViewProperties{
+			Type:       "table",
+			Queries:    queries,
+			ViewColors: colors,
+			TableOptions: platform.TableOptions{
+				VerticalTimeAxis: cc.TableOptions.VerticalTimeAxis,
+				SortBy:           platform.RenamableField(cc.TableOptions.SortBy),
+				Wrapping:         cc.TableOptions.Wrapping,
+				FixFirstColumn:   cc.TableOptions.FixFirstColumn,
+			},
+			FieldOptions:  fields,
+			TimeFormat:    cc.TimeFormat,
+			DecimalPlaces: decimalPlaces,
+		}
+	default:
+		c.report.warnf("dashboard %q: cell %q not migrated: unsupported cell type %q", dashboard, cc.Name, cc.Type)
+		return nil, nil, false
+	}
+
+	cell := &platform.Cell{X: cc.X, Y: cc.Y, W: cc.W, H: cc.H}
+	view := &platform.View{
+		ViewContents: platform.ViewContents{Name: cc.Name},
+		Properties:   props,
+	}
+	return cell, view, true
+}
+
+// geoms maps the types of Chronograf graphs to the geometries of XY views.
+var geoms = map[string]string{
+	"line":          "line",
+	"line-stacked":  "stacked",
+	"line-stepplot": "step",
+	"bar":           "bar",
+}
+
+// convertQuery converts a query to Flux if it can be made with the query
+// builder.  Otherwise the InfluxQL query is kept, along with the reason it was
+// not converted, and refers to the source it was made for.
+func (c *converter) convertQuery(q chronograf.DashboardQuery) (platform.DashboardQuery, error) {
+	// Query configs are not stored; Chronograf makes them from the queries.
+	qc, err := influx.Convert(q.Command)
+	var (
+		text   string
+		config platform.BuilderConfig
+	)
+	if err != nil {
+		// Queries using templates other than the time ones do not parse.
+		err = fmt.Errorf("query could not be parsed: %v", err)
+	} else {
+		text, config, err = fluxQuery(qc)
+	}
+	if err == nil {
+		for _, b := range config.Buckets {
+			c.buckets[b] = true
+		}
+		return platform.DashboardQuery{
+			Text:          text,
+			Type:          "flux",
+			EditMode:      "builder",
+			Name:          q.Label,
+			BuilderConfig: config,
+		}, nil
+	}
+
+	sourceID := c.defaultSourceID
+	if id, convErr := strconv.Atoi(path.Base(q.Source)); convErr == nil {
+		if sid, ok := c.sourceIDs[id]; ok {
+			sourceID = sid
+		}
+	}
+	dq := platform.DashboardQuery{
+		Text:     q.Command,
+		Type:     "influxql",
+		EditMode: "advanced",
+		Name:     q.Label,
+	}
+	if sourceID.Valid() {
+		dq.SourceID = sourceID.String()
+	}
+	return dq, err
+}
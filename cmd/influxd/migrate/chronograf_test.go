@@ -0,0 +1,328 @@
+package migrate
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/chronograf"
+	chronografbolt "github.com/influxdata/influxdb/chronograf/bolt"
+)
+
+func tempPath(t *testing.T) string {
+	f, err := ioutil.TempFile("", "influxd-migrate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return f.Name()
+}
+
+// newChronograf creates a Chronograf database with a source and a dashboard.
+func newChronograf(t *testing.T) string {
+	ctx := context.Background()
+	path := tempPath(t)
+	c := chronografbolt.NewClient()
+	c.Path = path
+	if err := c.Open(ctx, &chronograf.NoopLogger{}, chronograf.BuildInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	src, err := c.SourcesStore.Add(ctx, chronograf.Source{
+		Name:     "influx",
+		URL:      "http://localhost:8086",
+		Username: "admin",
+		Telegraf: "telegraf",
+		Default:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.DashboardsStore.Add(ctx, chronograf.Dashboard{
+		Name: "System",
+		Cells: []chronograf.DashboardCell{
+			{
+				Name: "CPU",
+				Type: "line-stacked",
+				W:    6,
+				H:    4,
+				Queries: []chronograf.DashboardQuery{
+					{
+						Command: `SELECT mean("usage_user"), mean("usage_system") FROM "telegraf"."autogen"."cpu" WHERE time > :dashboardTime: AND "cpu" = 'cpu-total' GROUP BY time(:interval:) FILL(null)`,
+					},
+					{
+						Command: `SELECT mean("usage_user") FROM "telegraf"."autogen"."cpu" WHERE host = :host: GROUP BY time(:interval:)`,
+						Source:  "/chronograf/v1/sources/" + strconv.Itoa(src.ID),
+					},
+				},
+				Axes: map[string]chronograf.Axis{
+					"y": {Bounds: []string{"0", "100"}, Suffix: "%"},
+				},
+			},
+			{Name: "Alerts", Type: "alerts"},
+		},
+		Templates: []chronograf.Template{
+			{
+				TemplateVar: chronograf.TemplateVar{
+					Var: ":host:",
+					Values: []chronograf.TemplateValue{
+						{Value: "server01", Type: "tagValue", Selected: true},
+					},
+				},
+				Type: "tagValues",
+				Query: &chronograf.TemplateQuery{
+					Command:     "SHOW TAG VALUES ON :database: FROM :measurement: WITH KEY=:tagKey:",
+					DB:          "telegraf",
+					Measurement: "cpu",
+					TagKey:      "host",
+				},
+			},
+			{
+				TemplateVar: chronograf.TemplateVar{
+					Var: ":region:",
+					Values: []chronograf.TemplateValue{
+						{Value: "us-west", Type: "csv"},
+						{Value: "us-east", Type: "csv", Selected: true},
+					},
+				},
+				Type: "csv",
+			},
+			{
+				TemplateVar: chronograf.TemplateVar{Var: ":existing:"},
+				Type:        "csv",
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMigrator_Migrate(t *testing.T) {
+	ctx := context.Background()
+	chronografPath := newChronograf(t)
+	defer os.Remove(chronografPath)
+
+	sources, dashboards, err := readChronograf(ctx, chronografPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := bolt.NewClient()
+	c.Path = tempPath(t)
+	if err := c.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(c.Path)
+	defer c.Close()
+
+	org := &platform.Organization{Name: "org"}
+	if err := c.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateMacro(ctx, &platform.Macro{
+		OrganizationID: org.ID,
+		Name:           "existing",
+		Arguments:      &platform.MacroArguments{Type: "constant", Values: platform.MacroConstantValues{"x"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Migrator{
+		OrganizationID:   org.ID,
+		SourceService:    c,
+		DashboardService: c,
+		MacroService:     c,
+	}
+	r, err := m.Migrate(ctx, sources, dashboards)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Sources != 1 || r.Dashboards != 1 || r.Cells != 1 || r.Macros != 2 {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if want := []string{"telegraf/autogen"}; !reflect.DeepEqual(r.Buckets, want) {
+		t.Errorf("got buckets %v, want %v", r.Buckets, want)
+	}
+	wantWarnings := []string{
+		`dashboard "System": template ":existing:" not migrated: macro "existing" already exists`,
+		`dashboard "System": cell "CPU": query 1 kept as InfluxQL: query could not be parsed: found :, expected identifier, string, number, bool at line 1, char 72`,
+		`dashboard "System": cell "Alerts" not migrated: unsupported cell type "alerts"`,
+	}
+	if !reflect.DeepEqual(r.Warnings, wantWarnings) {
+		t.Errorf("got warnings:\n%q\nwant:\n%q", r.Warnings, wantWarnings)
+	}
+
+	srcs, _, err := c.FindSources(ctx, platform.FindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var src *platform.Source
+	for _, s := range srcs {
+		if s.Name == "influx" {
+			src = s
+		}
+	}
+	if src == nil || src.Type != platform.V1SourceType || src.URL != "http://localhost:8086" || src.Username != "admin" || src.OrganizationID != org.ID {
+		t.Fatalf("unexpected source %+v", src)
+	}
+
+	ds, _, err := c.FindDashboards(ctx, platform.DashboardFilter{OrganizationID: &org.ID}, platform.FindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 || ds[0].Name != "System" || len(ds[0].Cells) != 1 {
+		t.Fatalf("unexpected dashboards %+v", ds)
+	}
+	v, err := c.GetDashboardCellView(ctx, ds[0].ID, ds[0].Cells[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	props, ok := v.Properties.(platform.XYViewProperties)
+	if !ok || props.Geom != "stacked" || props.Axes["y"].Suffix != "%" || v.Name != "CPU" {
+		t.Fatalf("unexpected view %+v", v)
+	}
+	if len(props.Queries) != 2 {
+		t.Fatalf("got %d queries, want 2", len(props.Queries))
+	}
+	if q := props.Queries[0]; q.Type != "flux" || q.EditMode != "builder" {
+		t.Errorf("unexpected Flux query %+v", q)
+	}
+	if q := props.Queries[1]; q.Type != "influxql" || q.SourceID != src.ID.String() {
+		t.Errorf("unexpected InfluxQL query %+v", q)
+	}
+
+	macros, err := c.FindMacros(ctx, platform.MacroFilter{OrganizationID: &org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*platform.Macro)
+	for _, m := range macros {
+		byName[m.Name] = m
+	}
+	host := byName["host"]
+	if host == nil || !reflect.DeepEqual(host.Selected, []string{"server01"}) {
+		t.Fatalf("unexpected macro %+v", host)
+	}
+	if args := host.Arguments.Values.(platform.MacroQueryValues); args.Language != "influxql" || args.Query != "SHOW TAG VALUES ON telegraf FROM cpu WITH KEY=host" {
+		t.Errorf("unexpected macro query %+v", args)
+	}
+	region := byName["region"]
+	if region == nil || !reflect.DeepEqual(region.Arguments.Values, platform.MacroConstantValues{"us-west", "us-east"}) || !reflect.DeepEqual(region.Selected, []string{"us-east"}) {
+		t.Errorf("unexpected macro %+v", region)
+	}
+}
+
+func TestFluxQuery(t *testing.T) {
+	raw := "SELECT 1"
+	field := func(name string) chronograf.Field { return chronograf.Field{Type: "field", Value: name} }
+	fn := func(name string, field string) chronograf.Field {
+		return chronograf.Field{Type: "func", Value: name, Args: []chronograf.Field{{Type: "field", Value: field}}}
+	}
+
+	tests := []struct {
+		name    string
+		qc      chronograf.QueryConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "raw fields",
+			qc: chronograf.QueryConfig{
+				Database:    "telegraf",
+				Measurement: "cpu",
+				Fields:      []chronograf.Field{field("usage_user")},
+			},
+			want: `from(bucket: "telegraf")
+  |> range(start: timeRangeStart)
+  |> filter(fn: (r) => r._measurement == "cpu")
+  |> filter(fn: (r) => r._field == "usage_user")`,
+		},
+		{
+			name: "functions and tags",
+			qc: chronograf.QueryConfig{
+				Database:        "telegraf",
+				RetentionPolicy: "autogen",
+				Measurement:     "cpu",
+				Fields:          []chronograf.Field{fn("mean", "usage_user"), fn("count", "usage_user")},
+				Tags:            map[string][]string{"host": {"a", `b"c`}},
+				AreTagsAccepted: true,
+			},
+			want: `from(bucket: "telegraf/autogen")
+  |> range(start: timeRangeStart)
+  |> filter(fn: (r) => r._measurement == "cpu")
+  |> filter(fn: (r) => r._field == "usage_user")
+  |> filter(fn: (r) => r.host == "a" or r.host == "b\"c")
+  |> window(period: windowPeriod)
+  |> mean()
+  |> group(columns: ["_value", "_time", "_start", "_stop"], mode: "except")
+  |> yield(name: "mean")
+
+from(bucket: "telegraf/autogen")
+  |> range(start: timeRangeStart)
+  |> filter(fn: (r) => r._measurement == "cpu")
+  |> filter(fn: (r) => r._field == "usage_user")
+  |> filter(fn: (r) => r.host == "a" or r.host == "b\"c")
+  |> count()
+  |> yield(name: "count")`,
+		},
+		{
+			name:    "raw text",
+			qc:      chronograf.QueryConfig{RawText: &raw},
+			wantErr: true,
+		},
+		{
+			name: "excluded tags",
+			qc: chronograf.QueryConfig{
+				Database:    "telegraf",
+				Measurement: "cpu",
+				Fields:      []chronograf.Field{field("usage_user")},
+				Tags:        map[string][]string{"host": {"a"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "different functions per field",
+			qc: chronograf.QueryConfig{
+				Database:    "telegraf",
+				Measurement: "cpu",
+				Fields:      []chronograf.Field{fn("mean", "usage_user"), fn("max", "usage_system")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported function",
+			qc: chronograf.QueryConfig{
+				Database:    "telegraf",
+				Measurement: "cpu",
+				Fields:      []chronograf.Field{fn("derivative", "usage_user")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := fluxQuery(tt.qc)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got query:\n%s", got)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got query:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package migrate implements the "influxd migrate" command, which migrates the
+// metadata store, and the "influxd migrate-chronograf" command, which
+// converts the sources and dashboards of a Chronograf 1.x database.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/chronograf"
+	chronografbolt "github.com/influxdata/influxdb/chronograf/bolt"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+// ChronografCommand is the "influxd migrate-chronograf" command.
+var ChronografCommand = &cobra.Command{
+	Use:   "migrate-chronograf <chronograf-v1.db>",
+	Short: "Migrate Chronograf 1.x sources and dashboards",
+	Long: `Migrate the sources and dashboards of a Chronograf 1.x database into an
+organization. influxd must not be running. The Chronograf database is not
+modified.
+
+Sources become InfluxDB 1.x sources. Dashboard templates become macros of the
+organization, unless it has macros with the same names. Queries made with the
+Chronograf query builder are converted to the Flux queries of the query
+builder, reading from the bucket named <database>/<retention policy>; the
+buckets are not created. Other queries are kept as InfluxQL queries of the
+migrated sources.
+
+Everything that could not be converted is reported.`,
+	Args: cobra.ExactArgs(1),
+	RunE: migrateChronografF,
+}
+
+var flags struct {
+	boltPath string
+	org      string
+	orgID    string
+	dryRun   bool
+}
+
+func init() {
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to determine influx directory: %v", err))
+	}
+
+	ChronografCommand.Flags().StringVar(&flags.boltPath, "bolt-path", filepath.Join(dir, "influxd.bolt"), "path to boltdb database")
+	ChronografCommand.Flags().StringVarP(&flags.org, "org", "o", "", "Name of the organization to migrate to")
+	ChronografCommand.Flags().StringVar(&flags.orgID, "org-id", "", "The ID of the organization to migrate to")
+	ChronografCommand.Flags().BoolVar(&flags.dryRun, "dry-run", false, "report what would be migrated without migrating it")
+}
+
+func migrateChronografF(cmd *cobra.Command, args []string) error {
+	if !flags.dryRun && (flags.org == "") == (flags.orgID == "") {
+		return errors.New("must specify exactly one of org or org-id")
+	}
+
+	ctx := context.Background()
+	sources, dashboards, err := readChronograf(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	m := &Migrator{DryRun: flags.dryRun}
+	if !flags.dryRun {
+		c := bolt.NewClient()
+		c.Path = flags.boltPath
+		if err := c.Open(ctx); err != nil {
+			return err
+		}
+		defer c.Close()
+
+		orgID, err := findOrganization(ctx, c)
+		if err != nil {
+			return err
+		}
+		m.OrganizationID = orgID
+		m.SourceService = c
+		m.DashboardService = c
+		m.MacroService = c
+	}
+
+	r, err := m.Migrate(ctx, sources, dashboards)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "Migrated %d sources, %d dashboards with %d cells and %d macros.\n", r.Sources, r.Dashboards, r.Cells, r.Macros)
+	if len(r.Buckets) > 0 {
+		fmt.Fprintln(w, "\nBuckets read by the Flux queries:")
+		for _, b := range r.Buckets {
+			fmt.Fprintf(w, "  %s\n", b)
+		}
+	}
+	if len(r.Warnings) > 0 {
+		fmt.Fprintln(w, "\nNot converted:")
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(w, "  %s\n", warning)
+		}
+	}
+	return nil
+}
+
+func findOrganization(ctx context.Context, svc platform.OrganizationService) (platform.ID, error) {
+	if flags.orgID != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(flags.orgID); err != nil {
+			return 0, fmt.Errorf("error parsing organization id: %v", err)
+		}
+		if _, err := svc.FindOrganizationByID(ctx, id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	o, err := svc.FindOrganization(ctx, platform.OrganizationFilter{Name: &flags.org})
+	if err != nil {
+		return 0, err
+	}
+	return o.ID, nil
+}
+
+// readChronograf returns the sources and dashboards of a Chronograf database.
+// Opening the database migrates it to the current Chronograf schema, so a
+// copy is read.
+func readChronograf(ctx context.Context, path string) ([]chronograf.Source, []chronograf.Dashboard, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, err
+	}
+
+	tmp, err := ioutil.TempFile("", "chronograf-v1-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if err := copyFile(tmp, path); err != nil {
+		tmp.Close()
+		return nil, nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	c := chronografbolt.NewClient()
+	c.Path = tmp.Name()
+	if err := c.Open(ctx, &chronograf.NoopLogger{}, chronograf.BuildInfo{}); err != nil {
+		return nil, nil, err
+	}
+	defer c.Close()
+
+	all, err := c.SourcesStore.All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The client adds a placeholder source to databases without sources.
+	sources := all[:0]
+	for _, src := range all {
+		if src.ID != chronografbolt.DefaultSource.ID {
+			sources = append(sources, src)
+		}
+	}
+	dashboards, err := c.DashboardsStore.All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sources, dashboards, nil
+}
+
+func copyFile(dst io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(dst, f)
+	return err
+}
@@ -0,0 +1,200 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf"
+)
+
+// builderTag and builderFunction are the elements of a platform.BuilderConfig.
+type builderTag struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+}
+
+type builderFunction struct {
+	Name string `json:"name"`
+}
+
+// builderFunctions are the functions of the query builder, in Flux.  Aggregates
+// are applied to windows.
+var builderFunctions = map[string]struct {
+	flux      string
+	aggregate bool
+}{
+	"mean":     {flux: "|> mean()", aggregate: true},
+	"median":   {flux: "|> toFloat()\n  |> median()", aggregate: true},
+	"max":      {flux: "|> max()", aggregate: true},
+	"min":      {flux: "|> min()", aggregate: true},
+	"sum":      {flux: "|> sum()", aggregate: true},
+	"distinct": {flux: "|> distinct()"},
+	"count":    {flux: "|> count()"},
+	"spread":   {flux: "|> spread()"},
+	"stddev":   {flux: "|> stddev()", aggregate: true},
+	"first":    {flux: "|> first()", aggregate: true},
+	"last":     {flux: "|> last()", aggregate: true},
+}
+
+// fluxQuery returns the Flux query and the builder config the 2.0 query builder
+// makes for a query of the Chronograf query builder.  The query of each function
+// keeps the series separate, as the 2.0 query builder does.  An error is
+// returned if the query cannot be expressed with the 2.0 query builder.
+func fluxQuery(qc chronograf.QueryConfig) (string, platform.BuilderConfig, error) {
+	var config platform.BuilderConfig
+
+	switch {
+	case qc.RawText != nil:
+		return "", config, errors.New("query was not made with the query builder")
+	case qc.Database == "" || qc.Measurement == "":
+		return "", config, errors.New("query has no database or measurement")
+	case qc.Range != nil:
+		return "", config, errors.New("query has its own time range")
+	case len(qc.Shifts) > 0:
+		return "", config, errors.New("query has time shifts")
+	case qc.Fill != "" && qc.Fill != "null":
+		return "", config, fmt.Errorf("fill %q is not supported", qc.Fill)
+	case qc.GroupBy.Time != "" && qc.GroupBy.Time != "auto":
+		return "", config, fmt.Errorf("grouping by time(%s) is not supported", qc.GroupBy.Time)
+	case len(qc.GroupBy.Tags) > 0:
+		return "", config, errors.New("grouping by tags is not supported")
+	case !qc.AreTagsAccepted && len(qc.Tags) > 0:
+		return "", config, errors.New("excluding tag values is not supported")
+	}
+
+	fields, functions, err := builderFields(qc.Fields)
+	if err != nil {
+		return "", config, err
+	}
+
+	bucket := bucketName(qc.Database, qc.RetentionPolicy)
+	config.Buckets = []string{bucket}
+	config.Tags = append(config.Tags,
+		builderTag{Key: "_measurement", Values: []string{qc.Measurement}},
+		builderTag{Key: "_field", Values: fields},
+	)
+	keys := make([]string, 0, len(qc.Tags))
+	for k, vs := range qc.Tags {
+		if len(vs) == 0 {
+			continue
+		}
+		if !isIdentifier(k) {
+			return "", config, fmt.Errorf("tag key %q is not supported", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		config.Tags = append(config.Tags, builderTag{Key: k, Values: qc.Tags[k]})
+	}
+	for _, fn := range functions {
+		config.Functions = append(config.Functions, builderFunction{Name: fn})
+	}
+
+	var filters strings.Builder
+	for _, t := range config.Tags {
+		conds := make([]string, len(t.Values))
+		for i, v := range t.Values {
+			conds[i] = fmt.Sprintf("r.%s == %s", t.Key, quoteString(v))
+		}
+		fmt.Fprintf(&filters, "\n  |> filter(fn: (r) => %s)", strings.Join(conds, " or "))
+	}
+	base := fmt.Sprintf("from(bucket: %s)\n  |> range(start: timeRangeStart)%s", quoteString(bucket), filters.String())
+
+	if len(functions) == 0 {
+		return base, config, nil
+	}
+	queries := make([]string, len(functions))
+	for i, fn := range functions {
+		spec := builderFunctions[fn]
+		if spec.aggregate {
+			queries[i] = fmt.Sprintf("%s\n  |> window(period: windowPeriod)\n  %s\n  |> group(columns: [\"_value\", \"_time\", \"_start\", \"_stop\"], mode: \"except\")\n  |> yield(name: %q)", base, spec.flux, fn)
+		} else {
+			queries[i] = fmt.Sprintf("%s\n  %s\n  |> yield(name: %q)", base, spec.flux, fn)
+		}
+	}
+	return strings.Join(queries, "\n\n"), config, nil
+}
+
+// builderFields returns the fields and functions selected by the fields of a
+// query config.  Every function must be applied to every field, as the query
+// builder does.
+func builderFields(qfs []chronograf.Field) (fields, functions []string, err error) {
+	type selection struct{ field, function string }
+	selected := make(map[selection]bool)
+	seenField := make(map[string]bool)
+	seenFunction := make(map[string]bool)
+	raw := false
+
+	for _, f := range qfs {
+		var field, function string
+		switch f.Type {
+		case "field":
+			field, _ = f.Value.(string)
+			raw = true
+		case "func":
+			function, _ = f.Value.(string)
+			if _, ok := builderFunctions[function]; !ok {
+				return nil, nil, fmt.Errorf("function %q is not supported", function)
+			}
+			if len(f.Args) != 1 || f.Args[0].Type != "field" {
+				return nil, nil, fmt.Errorf("arguments of function %q are not supported", function)
+			}
+			field, _ = f.Args[0].Value.(string)
+		default:
+			return nil, nil, fmt.Errorf("field of type %q is not supported", f.Type)
+		}
+		if field == "" {
+			return nil, nil, errors.New("query has a field without name")
+		}
+
+		selected[selection{field, function}] = true
+		if !seenField[field] {
+			seenField[field] = true
+			fields = append(fields, field)
+		}
+		if function != "" && !seenFunction[function] {
+			seenFunction[function] = true
+			functions = append(functions, function)
+		}
+	}
+
+	switch {
+	case len(fields) == 0:
+		return nil, nil, errors.New("query has no fields")
+	case raw && len(functions) > 0:
+		return nil, nil, errors.New("query selects both fields and functions")
+	case len(functions) > 0 && len(selected) != len(fields)*len(functions):
+		return nil, nil, errors.New("query applies different functions to different fields")
+	}
+	return fields, functions, nil
+}
+
+// bucketName returns the name of the bucket for a database and retention policy.
+func bucketName(db, rp string) string {
+	if rp == "" {
+		return db
+	}
+	return db + "/" + rp
+}
+
+// quoteString returns s as a Flux string literal.
+func quoteString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// isIdentifier reports whether s can be used as a Flux identifier.
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case i > 0 && '0' <= r && r <= '9':
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
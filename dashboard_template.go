@@ -0,0 +1,45 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ops for dashboard template service.
+const (
+	OpExportDashboard = "ExportDashboard"
+	OpImportDashboard = "ImportDashboard"
+)
+
+// DashboardTemplate is a self-contained export of a dashboard that can be
+// imported in any organization.  IDs of the dashboard and its macros are not
+// kept; the views are indexed by the IDs of the cells of the template.
+type DashboardTemplate struct {
+	ProtoDashboard
+
+	// Macros are the macros referenced by the queries of the dashboard.
+	Macros []*Macro `json:"macros"`
+
+	// Labels are the labels of the dashboard.
+	Labels []*Label `json:"labels"`
+
+	// Buckets are the names of the buckets referenced by the queries of the dashboard.
+	Buckets []string `json:"buckets"`
+}
+
+// ImportDashboardOptions are the options for importing a dashboard template.
+type ImportDashboardOptions struct {
+	// BucketNames maps the names of the buckets of the template to the names of
+	// buckets of the organization.  Buckets not mapped keep their name.
+	BucketNames map[string]string `json:"bucketNames,omitempty"`
+}
+
+// DashboardTemplateService exports dashboards to templates and imports them.
+type DashboardTemplateService interface {
+	// ExportDashboard returns a template of the dashboard with the given ID.
+	ExportDashboard(ctx context.Context, id ID) (*DashboardTemplate, error)
+
+	// ImportDashboard creates a dashboard in an organization from a template.
+	// Macros of the template are created unless the organization has macros with
+	// the same names.
+	ImportDashboard(ctx context.Context, orgID ID, t *DashboardTemplate, opts ImportDashboardOptions) (*Dashboard, error)
+}
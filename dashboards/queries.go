@@ -0,0 +1,172 @@
+package dashboards
+
+import (
+	"sort"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+)
+
+// viewQueries returns pointers to the queries of views, in the order of the
+// IDs of the views.
+func viewQueries(views map[platform.ID]platform.View) []*platform.DashboardQuery {
+	ids := make([]platform.ID, 0, len(views))
+	for id := range views {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var queries []*platform.DashboardQuery
+	for _, id := range ids {
+		qs := propertiesQueries(views[id].Properties)
+		for i := range qs {
+			queries = append(queries, &qs[i])
+		}
+	}
+	return queries
+}
+
+// propertiesQueries returns the queries of the view properties p.
+func propertiesQueries(p platform.ViewProperties) []platform.DashboardQuery {
+	switch p := p.(type) {
+	case platform.XYViewProperties:
+		return p.Queries
+	case platform.LinePlusSingleStatProperties:
+		return p.Queries
+	case platform.SingleStatViewProperties:
+		return p.Queries
+	case platform.GaugeViewProperties:
+		return p.Queries
+	case platform.TableViewProperties:
+		return p.Queries
+	default:
+		return nil
+	}
+}
+
+// copyProperties returns a copy of the view properties p that does not share
+// its queries with p.
+func copyProperties(p platform.ViewProperties) platform.ViewProperties {
+	switch p := p.(type) {
+	case platform.XYViewProperties:
+		p.Queries = copyQueries(p.Queries)
+		return p
+	case platform.LinePlusSingleStatProperties:
+		p.Queries = copyQueries(p.Queries)
+		return p
+	case platform.SingleStatViewProperties:
+		p.Queries = copyQueries(p.Queries)
+		return p
+	case platform.GaugeViewProperties:
+		p.Queries = copyQueries(p.Queries)
+		return p
+	case platform.TableViewProperties:
+		p.Queries = copyQueries(p.Queries)
+		return p
+	default:
+		return p
+	}
+}
+
+func copyQueries(qs []platform.DashboardQuery) []platform.DashboardQuery {
+	if qs == nil {
+		return nil
+	}
+	cp := make([]platform.DashboardQuery, len(qs))
+	for i, q := range qs {
+		cp[i] = q
+		if q.BuilderConfig.Buckets != nil {
+			cp[i].BuilderConfig.Buckets = append([]string(nil), q.BuilderConfig.Buckets...)
+		}
+	}
+	return cp
+}
+
+// isFlux reports whether q is a Flux query.  Queries without a type are Flux.
+func isFlux(q *platform.DashboardQuery) bool {
+	return q.Type != "influxql"
+}
+
+// bucketNames returns the sorted names of the buckets referenced by queries,
+// either by their builder config or by the `from` calls of their Flux text.
+func bucketNames(queries []*platform.DashboardQuery) []string {
+	set := make(map[string]bool)
+	for _, q := range queries {
+		for _, b := range q.BuilderConfig.Buckets {
+			set[b] = true
+		}
+		if isFlux(q) {
+			for _, lit := range fromBuckets(parser.ParseSource(q.Text)) {
+				set[lit.Value] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// renameBuckets renames the buckets referenced by queries from the keys of names
+// to their values.
+func renameBuckets(queries []*platform.DashboardQuery, names map[string]string) {
+	for _, q := range queries {
+		for i, b := range q.BuilderConfig.Buckets {
+			if n, ok := names[b]; ok {
+				q.BuilderConfig.Buckets[i] = n
+			}
+		}
+		if !isFlux(q) {
+			continue
+		}
+
+		pkg := parser.ParseSource(q.Text)
+		renamed := false
+		for _, lit := range fromBuckets(pkg) {
+			if n, ok := names[lit.Value]; ok && n != lit.Value {
+				// The formatter writes the source of literals that have one.
+				lit.Value = n
+				lit.Loc = nil
+				renamed = true
+			}
+		}
+		// Queries are only formatted again when they change.
+		if renamed && len(pkg.Files) > 0 {
+			q.Text = ast.Format(pkg.Files[0])
+		}
+	}
+}
+
+// fromBuckets returns the string literals of the bucket parameters of the `from`
+// calls of pkg.
+func fromBuckets(pkg *ast.Package) []*ast.StringLiteral {
+	var lits []*ast.StringLiteral
+	ast.Walk(ast.CreateVisitor(func(node ast.Node) {
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return
+		}
+		if id, ok := call.Callee.(*ast.Identifier); !ok || id.Name != "from" {
+			return
+		}
+		for _, arg := range call.Arguments {
+			obj, ok := arg.(*ast.ObjectExpression)
+			if !ok {
+				continue
+			}
+			for _, p := range obj.Properties {
+				if p.Key.Key() != "bucket" {
+					continue
+				}
+				if lit, ok := p.Value.(*ast.StringLiteral); ok {
+					lits = append(lits, lit)
+				}
+			}
+		}
+	}), pkg)
+	return lits
+}
@@ -0,0 +1,291 @@
+// Package dashboards exports dashboards to self-contained templates and imports
+// them into organizations.
+package dashboards
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query/macro"
+)
+
+var _ platform.DashboardTemplateService = (*TemplateService)(nil)
+
+// TemplateService implements platform.DashboardTemplateService on top of the
+// services storing dashboards, macros, labels and buckets.
+type TemplateService struct {
+	DashboardService platform.DashboardService
+	MacroService     platform.MacroService
+	LabelService     platform.LabelService
+	BucketService    platform.BucketService
+}
+
+// NewTemplateService creates an instance of a TemplateService.
+func NewTemplateService(dashboardService platform.DashboardService, macroService platform.MacroService, labelService platform.LabelService, bucketService platform.BucketService) *TemplateService {
+	return &TemplateService{
+		DashboardService: dashboardService,
+		MacroService:     macroService,
+		LabelService:     labelService,
+		BucketService:    bucketService,
+	}
+}
+
+// ExportDashboard returns a template of the dashboard with the given ID.
+func (s *TemplateService) ExportDashboard(ctx context.Context, id platform.ID) (*platform.DashboardTemplate, error) {
+	d, err := s.DashboardService.FindDashboardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &platform.DashboardTemplate{
+		ProtoDashboard: platform.ProtoDashboard{
+			Dashboard: platform.Dashboard{
+				Name:        d.Name,
+				Description: d.Description,
+				Cells:       make([]*platform.Cell, 0, len(d.Cells)),
+			},
+			Views: make(map[platform.ID]platform.View, len(d.Cells)),
+		},
+		Macros:  []*platform.Macro{},
+		Labels:  []*platform.Label{},
+		Buckets: []string{},
+	}
+
+	for _, c := range d.Cells {
+		v, err := s.DashboardService.GetDashboardCellView(ctx, d.ID, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		cell := *c
+		t.Dashboard.Cells = append(t.Dashboard.Cells, &cell)
+		t.Views[c.ID] = *v
+	}
+
+	queries := viewQueries(t.Views)
+	if t.Macros, err = s.exportMacros(ctx, d.OrganizationID, queries); err != nil {
+		return nil, err
+	}
+	for _, m := range t.Macros {
+		if args, ok := m.Arguments.Values.(platform.MacroQueryValues); ok {
+			queries = append(queries, &platform.DashboardQuery{Text: args.Query, Type: args.Language})
+		}
+	}
+	t.Buckets = bucketNames(queries)
+
+	labels, err := s.LabelService.FindLabels(ctx, platform.LabelFilter{ResourceID: d.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		t.Labels = append(t.Labels, &platform.Label{
+			Name:       l.Name,
+			Properties: l.Properties,
+		})
+	}
+
+	return t, nil
+}
+
+// exportMacros returns the macros of the organization referenced by queries,
+// directly or through other macros.
+func (s *TemplateService) exportMacros(ctx context.Context, orgID platform.ID, queries []*platform.DashboardQuery) ([]*platform.Macro, error) {
+	macros, err := s.MacroService.FindMacros(ctx, platform.MacroFilter{OrganizationID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*platform.Macro, len(macros))
+	for _, m := range macros {
+		byName[m.Name] = m
+	}
+
+	var names []string
+	for _, q := range queries {
+		if isFlux(q) {
+			names = append(names, macro.References(q.Text)...)
+		}
+	}
+
+	exported := []*platform.Macro{}
+	seen := make(map[string]bool)
+	for len(names) > 0 {
+		name := names[0]
+		names = names[1:]
+
+		m, ok := byName[name]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if args, ok := m.Arguments.Values.(platform.MacroQueryValues); ok && args.Language == "flux" {
+			names = append(names, macro.References(args.Query)...)
+		}
+		exported = append(exported, &platform.Macro{
+			Name:      m.Name,
+			Selected:  m.Selected,
+			Arguments: m.Arguments,
+		})
+	}
+
+	sort.Slice(exported, func(i, j int) bool { return exported[i].Name < exported[j].Name })
+	return exported, nil
+}
+
+// ImportDashboard creates a dashboard in an organization from a template.
+func (s *TemplateService) ImportDashboard(ctx context.Context, orgID platform.ID, t *platform.DashboardTemplate, opts platform.ImportDashboardOptions) (*platform.Dashboard, error) {
+	for _, c := range t.Dashboard.Cells {
+		if _, ok := t.Views[c.ID]; !ok {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   platform.OpImportDashboard,
+				Msg:  fmt.Sprintf("view for cell %q does not exist", c.ID),
+			}
+		}
+	}
+
+	// The template is modified when renaming buckets.
+	views := make(map[platform.ID]platform.View, len(t.Views))
+	for id, v := range t.Views {
+		views[id] = platform.View{
+			ViewContents: v.ViewContents,
+			Properties:   copyProperties(v.Properties),
+		}
+	}
+	macros := make([]*platform.Macro, 0, len(t.Macros))
+	for _, m := range t.Macros {
+		if m.Arguments == nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   platform.OpImportDashboard,
+				Msg:  fmt.Sprintf("macro %q has no arguments", m.Name),
+			}
+		}
+		c := *m
+		args := *m.Arguments
+		c.Arguments = &args
+		macros = append(macros, &c)
+	}
+
+	queries := viewQueries(views)
+	macroQueries := make(map[*platform.Macro]*platform.DashboardQuery)
+	for _, m := range macros {
+		if args, ok := m.Arguments.Values.(platform.MacroQueryValues); ok {
+			q := &platform.DashboardQuery{Text: args.Query, Type: args.Language}
+			macroQueries[m] = q
+			queries = append(queries, q)
+		}
+	}
+	if err := s.resolveBuckets(ctx, orgID, queries, opts.BucketNames); err != nil {
+		return nil, err
+	}
+	for m, q := range macroQueries {
+		args := m.Arguments.Values.(platform.MacroQueryValues)
+		args.Query = q.Text
+		m.Arguments.Values = args
+	}
+
+	if err := s.importMacros(ctx, orgID, macros); err != nil {
+		return nil, err
+	}
+
+	d := &platform.Dashboard{
+		OrganizationID: orgID,
+		Name:           t.Dashboard.Name,
+		Description:    t.Dashboard.Description,
+	}
+	if err := s.DashboardService.CreateDashboard(ctx, d); err != nil {
+		return nil, err
+	}
+
+	cells := make([]*platform.Cell, 0, len(t.Dashboard.Cells))
+	for _, c := range t.Dashboard.Cells {
+		cell := *c
+		view := views[c.ID]
+		if err := s.DashboardService.AddDashboardCell(ctx, d.ID, &cell, platform.AddDashboardCellOptions{View: &view}); err != nil {
+			return nil, err
+		}
+		cells = append(cells, &cell)
+	}
+	d.Cells = cells
+
+	for _, l := range t.Labels {
+		if err := s.LabelService.CreateLabel(ctx, &platform.Label{
+			ResourceID: d.ID,
+			Name:       l.Name,
+			Properties: l.Properties,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// importMacros creates the macros in the organization, unless it has macros with
+// the same names.
+func (s *TemplateService) importMacros(ctx context.Context, orgID platform.ID, macros []*platform.Macro) error {
+	if len(macros) == 0 {
+		return nil
+	}
+
+	existing, err := s.MacroService.FindMacros(ctx, platform.MacroFilter{OrganizationID: &orgID})
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		names[m.Name] = true
+	}
+
+	for _, m := range macros {
+		if names[m.Name] {
+			continue
+		}
+		m.ID = 0
+		m.OrganizationID = orgID
+		if err := s.MacroService.CreateMacro(ctx, m); err != nil {
+			return err
+		}
+		names[m.Name] = true
+	}
+	return nil
+}
+
+// resolveBuckets renames the buckets referenced by queries to the names of the
+// buckets of the organization.  An error is returned if a bucket does not exist.
+func (s *TemplateService) resolveBuckets(ctx context.Context, orgID platform.ID, queries []*platform.DashboardQuery, mapping map[string]string) error {
+	names := make(map[string]string)
+	var missing []string
+	for _, name := range bucketNames(queries) {
+		target := name
+		if n, ok := mapping[name]; ok {
+			target = n
+		}
+		names[name] = target
+
+		if s.BucketService == nil {
+			continue
+		}
+		if _, err := s.BucketService.FindBucket(ctx, platform.BucketFilter{
+			OrganizationID: &orgID,
+			Name:           &target,
+		}); platform.ErrorCode(err) == platform.ENotFound {
+			missing = append(missing, target)
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Op:   platform.OpImportDashboard,
+			Msg:  fmt.Sprintf("buckets not found in organization: %s", strings.Join(missing, ", ")),
+		}
+	}
+
+	renameBuckets(queries, names)
+	return nil
+}
@@ -0,0 +1,201 @@
+package dashboards_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/dashboards"
+)
+
+func newTestClient(t *testing.T) (*bolt.Client, func()) {
+	c := bolt.NewClient()
+	f, err := ioutil.TempFile("", "influxdata-platform-dashboards-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	c.Path = f.Name()
+	if err := c.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return c, func() {
+		c.Close()
+		os.Remove(c.Path)
+	}
+}
+
+func createOrganization(t *testing.T, c *bolt.Client, name string, buckets ...string) *platform.Organization {
+	ctx := context.Background()
+	org := &platform.Organization{Name: name}
+	if err := c.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range buckets {
+		if err := c.CreateBucket(ctx, &platform.Bucket{OrganizationID: org.ID, Name: b}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return org
+}
+
+func createMacro(t *testing.T, c *bolt.Client, orgID platform.ID, name string, args *platform.MacroArguments) {
+	m := &platform.Macro{OrganizationID: orgID, Name: name, Arguments: args}
+	if err := c.CreateMacro(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func xyView(q platform.DashboardQuery) *platform.View {
+	return &platform.View{
+		ViewContents: platform.ViewContents{Name: "cpu"},
+		Properties: platform.XYViewProperties{
+			Type:    "xy",
+			Queries: []platform.DashboardQuery{q},
+		},
+	}
+}
+
+func TestTemplateService(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+	ctx := context.Background()
+
+	src := createOrganization(t, c, "src", "telegraf")
+	createMacro(t, c, src.ID, "host", &platform.MacroArguments{
+		Type:   "query",
+		Values: platform.MacroQueryValues{Query: `from(bucket: "telegraf") |> filter(fn: (r) => r.cpu == v.cpu)`, Language: "flux"},
+	})
+	createMacro(t, c, src.ID, "cpu", &platform.MacroArguments{
+		Type:   "constant",
+		Values: platform.MacroConstantValues{"cpu0", "cpu1"},
+	})
+	createMacro(t, c, src.ID, "unused", &platform.MacroArguments{
+		Type:   "constant",
+		Values: platform.MacroConstantValues{"x"},
+	})
+
+	d := &platform.Dashboard{OrganizationID: src.ID, Name: "system", Description: "hosts"}
+	if err := c.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	q := platform.DashboardQuery{
+		Text: `from(bucket: "telegraf") |> filter(fn: (r) => r.host == v.host)`,
+		Type: "flux",
+		BuilderConfig: platform.BuilderConfig{
+			Buckets: []string{"telegraf"},
+		},
+	}
+	if err := c.AddDashboardCell(ctx, d.ID, &platform.Cell{W: 4, H: 4}, platform.AddDashboardCellOptions{View: xyView(q)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateLabel(ctx, &platform.Label{ResourceID: d.ID, Name: "infra", Properties: map[string]string{"color": "red"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := dashboards.NewTemplateService(c, c, c, c)
+	tmpl, err := s.ExportDashboard(ctx, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Templates are exchanged as JSON.
+	b, err := json.Marshal(tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl = &platform.DashboardTemplate{}
+	if err := json.Unmarshal(b, tmpl); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, m := range tmpl.Macros {
+		if m.ID.Valid() || m.OrganizationID.Valid() {
+			t.Errorf("macro %q was exported with its IDs", m.Name)
+		}
+		names = append(names, m.Name)
+	}
+	if want := []string{"cpu", "host"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got macros %v, want %v", names, want)
+	}
+	if want := []string{"telegraf"}; !reflect.DeepEqual(tmpl.Buckets, want) {
+		t.Errorf("got buckets %v, want %v", tmpl.Buckets, want)
+	}
+	if len(tmpl.Labels) != 1 || tmpl.Labels[0].Name != "infra" {
+		t.Errorf("unexpected labels %v", tmpl.Labels)
+	}
+
+	dst := createOrganization(t, c, "dst", "metrics")
+	createMacro(t, c, dst.ID, "cpu", &platform.MacroArguments{
+		Type:   "constant",
+		Values: platform.MacroConstantValues{"cpu-total"},
+	})
+
+	if _, err := s.ImportDashboard(ctx, dst.ID, tmpl, platform.ImportDashboardOptions{}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected import with missing bucket to fail, got %v", err)
+	}
+
+	imported, err := s.ImportDashboard(ctx, dst.ID, tmpl, platform.ImportDashboardOptions{
+		BucketNames: map[string]string{"telegraf": "metrics"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ID == d.ID || imported.OrganizationID != dst.ID || imported.Name != "system" {
+		t.Fatalf("unexpected dashboard %+v", imported)
+	}
+	if len(imported.Cells) != 1 {
+		t.Fatalf("got %d cells, want 1", len(imported.Cells))
+	}
+
+	v, err := c.GetDashboardCellView(ctx, imported.ID, imported.Cells[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := v.Properties.(platform.XYViewProperties).Queries[0]
+	if want := "from(bucket: \"metrics\")\n\t|> filter(fn: (r) =>\n\t\t(r.host == v.host))"; got.Text != want {
+		t.Errorf("got query:\n%s\nwant:\n%s", got.Text, want)
+	}
+	if want := []string{"metrics"}; !reflect.DeepEqual(got.BuilderConfig.Buckets, want) {
+		t.Errorf("got builder buckets %v, want %v", got.BuilderConfig.Buckets, want)
+	}
+
+	// The template is not modified by imports.
+	for _, v := range tmpl.Views {
+		if b := v.Properties.(platform.XYViewProperties).Queries[0].BuilderConfig.Buckets[0]; b != "telegraf" {
+			t.Errorf("template was modified: bucket %q", b)
+		}
+	}
+
+	macros, err := c.FindMacros(ctx, platform.MacroFilter{OrganizationID: &dst.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*platform.Macro)
+	for _, m := range macros {
+		byName[m.Name] = m
+	}
+	if len(byName) != 2 {
+		t.Fatalf("got macros %v, want cpu and host", byName)
+	}
+	if vs := byName["cpu"].Arguments.Values; !reflect.DeepEqual(vs, platform.MacroConstantValues{"cpu-total"}) {
+		t.Errorf("existing macro was replaced: %v", vs)
+	}
+	if args := byName["host"].Arguments.Values.(platform.MacroQueryValues); args.Query != "from(bucket: \"metrics\")\n\t|> filter(fn: (r) =>\n\t\t(r.cpu == v.cpu))" {
+		t.Errorf("unexpected macro query:\n%s", args.Query)
+	}
+
+	labels, err := c.FindLabels(ctx, platform.LabelFilter{ResourceID: imported.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 1 || labels[0].Name != "infra" || labels[0].Properties["color"] != "red" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+}
@@ -63,43 +63,7 @@ func (p *prometheusScraper) parse(r io.Reader, header http.Header, target influx
 
 	// read metrics
 	for name, family := range metricFamilies {
-		for _, m := range family.Metric {
-			// reading tags
-			tags := makeLabels(m)
-			// reading fields
-			var fields map[string]interface{}
-			switch family.GetType() {
-			case dto.MetricType_SUMMARY:
-				// summary metric
-				fields = makeQuantiles(m)
-				fields["count"] = float64(m.GetSummary().GetSampleCount())
-				fields["sum"] = float64(m.GetSummary().GetSampleSum())
-			case dto.MetricType_HISTOGRAM:
-				// histogram metric
-				fields = makeBuckets(m)
-				fields["count"] = float64(m.GetHistogram().GetSampleCount())
-				fields["sum"] = float64(m.GetHistogram().GetSampleSum())
-			default:
-				// standard metric
-				fields = getNameAndValue(m)
-			}
-			if len(fields) == 0 {
-				continue
-			}
-			tm := now
-			if m.TimestampMs != nil && *m.TimestampMs > 0 {
-				tm = time.Unix(0, *m.TimestampMs*1000000)
-			}
-			me := Metrics{
-				Timestamp: tm,
-				Tags:      tags,
-				Fields:    fields,
-				Name:      name,
-				Type:      MetricType(family.GetType()),
-			}
-			ms = append(ms, me)
-		}
-
+		ms = appendMetrics(ms, name, family, now)
 	}
 
 	collected = MetricsCollection{
@@ -111,6 +75,47 @@ func (p *prometheusScraper) parse(r io.Reader, header http.Header, target influx
 	return collected, nil
 }
 
+// appendMetrics appends the metrics of a metric family to ms. The metrics
+// without a timestamp are timestamped with now.
+func appendMetrics(ms []Metrics, name string, family *dto.MetricFamily, now time.Time) []Metrics {
+	for _, m := range family.Metric {
+		// reading tags
+		tags := makeLabels(m)
+		// reading fields
+		var fields map[string]interface{}
+		switch family.GetType() {
+		case dto.MetricType_SUMMARY:
+			// summary metric
+			fields = makeQuantiles(m)
+			fields["count"] = float64(m.GetSummary().GetSampleCount())
+			fields["sum"] = float64(m.GetSummary().GetSampleSum())
+		case dto.MetricType_HISTOGRAM:
+			// histogram metric
+			fields = makeBuckets(m)
+			fields["count"] = float64(m.GetHistogram().GetSampleCount())
+			fields["sum"] = float64(m.GetHistogram().GetSampleSum())
+		default:
+			// standard metric
+			fields = getNameAndValue(m)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		tm := now
+		if m.TimestampMs != nil && *m.TimestampMs > 0 {
+			tm = time.Unix(0, *m.TimestampMs*1000000)
+		}
+		ms = append(ms, Metrics{
+			Timestamp: tm,
+			Tags:      tags,
+			Fields:    fields,
+			Name:      name,
+			Type:      MetricType(family.GetType()),
+		})
+	}
+	return ms
+}
+
 // Get labels from metric
 func makeLabels(m *dto.Metric) map[string]string {
 	result := map[string]string{}
@@ -0,0 +1,39 @@
+package gather
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegistryScraper gathers the metrics of a prometheus.Gatherer, such as the
+// registry of the process itself, without going through HTTP.
+// The URL of the scraper targets is ignored.
+type RegistryScraper struct {
+	Gatherer prometheus.Gatherer
+}
+
+var _ Scraper = (*RegistryScraper)(nil)
+
+// Gather gathers the metrics of the gatherer for the org and bucket of the target.
+func (s *RegistryScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	families, err := s.Gatherer.Gather()
+	if err != nil {
+		return collected, err
+	}
+
+	now := time.Now()
+	ms := make([]Metrics, 0)
+	for _, family := range families {
+		ms = appendMetrics(ms, family.GetName(), family, now)
+	}
+
+	collected = MetricsCollection{
+		MetricsSlice: ms,
+		OrgID:        target.OrgID,
+		BucketID:     target.BucketID,
+	}
+	return collected, nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
@@ -155,6 +156,62 @@ func TestPrometheusScraper(t *testing.T) {
 	}
 }
 
+func TestRegistryScraper(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	writes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "writes_total",
+		Help: "Number of writes.",
+	}, []string{"status"})
+	writes.WithLabelValues("ok").Add(3)
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "write_duration_seconds",
+		Help:    "Duration of writes.",
+		Buckets: []float64{0.5, 1},
+	})
+	duration.Observe(0.25)
+	duration.Observe(0.75)
+	reg.MustRegister(writes, duration)
+
+	scraper := &RegistryScraper{Gatherer: reg}
+	results, err := scraper.Gather(context.Background(), influxdb.ScraperTarget{
+		OrgID:    *orgID,
+		BucketID: *bucketID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.OrgID != *orgID || results.BucketID != *bucketID {
+		t.Fatalf("expected the metrics of the org and bucket of the target, got %v and %v", results.OrgID, results.BucketID)
+	}
+
+	want := MetricsSlice{
+		{
+			Name: "write_duration_seconds",
+			Type: MetricTypeHistogrm,
+			Tags: map[string]string{},
+			Fields: map[string]interface{}{
+				"0.5":   float64(1),
+				"1":     float64(2),
+				"count": float64(2),
+				"sum":   float64(1),
+			},
+		},
+		{
+			Name: "writes_total",
+			Type: MetricTypeCounter,
+			Tags: map[string]string{
+				"status": "ok",
+			},
+			Fields: map[string]interface{}{
+				"counter": float64(3),
+			},
+		},
+	}
+	if diff := cmp.Diff(results.MetricsSlice, want, metricsCmpOption); diff != "" {
+		t.Fatalf("scraper metrics are different -got/+want\ndiff %s", diff)
+	}
+}
+
 const sampleResp = `
 # 	HELP go_gc_duration_seconds A summary of the GC invocation durations.
 # TYPE go_gc_duration_seconds summary
@@ -6,31 +6,39 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/authorizer"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/replication"
 	"go.uber.org/zap"
 )
 
 // APIHandler is a collection of all the service handlers.
 type APIHandler struct {
-	BucketHandler        *BucketHandler
-	UserHandler          *UserHandler
-	OrgHandler           *OrgHandler
-	AuthorizationHandler *AuthorizationHandler
-	DashboardHandler     *DashboardHandler
-	AssetHandler         *AssetHandler
-	ChronografHandler    *ChronografHandler
-	ScraperHandler       *ScraperHandler
-	SourceHandler        *SourceHandler
-	MacroHandler         *MacroHandler
-	TaskHandler          *TaskHandler
-	TelegrafHandler      *TelegrafHandler
-	QueryHandler         *FluxHandler
-	ProtoHandler         *ProtoHandler
-	WriteHandler         *WriteHandler
-	SetupHandler         *SetupHandler
-	SessionHandler       *SessionHandler
+	BucketHandler               *BucketHandler
+	UserHandler                 *UserHandler
+	OrgHandler                  *OrgHandler
+	AuthorizationHandler        *AuthorizationHandler
+	DashboardHandler            *DashboardHandler
+	AssetHandler                *AssetHandler
+	ChronografHandler           *ChronografHandler
+	ScraperHandler              *ScraperHandler
+	SourceHandler               *SourceHandler
+	MacroHandler                *MacroHandler
+	CheckHandler                *CheckHandler
+	NotificationRuleHandler     *NotificationRuleHandler
+	NotificationEndpointHandler *NotificationEndpointHandler
+	TaskHandler                 *TaskHandler
+	TelegrafHandler             *TelegrafHandler
+	QueryHandler                *FluxHandler
+	ProtoHandler                *ProtoHandler
+	WriteHandler                *WriteHandler
+	SetupHandler                *SetupHandler
+	SessionHandler              *SessionHandler
+	OAuthHandler                *OAuthHandler
+	CompactionHandler           *CompactionHandler
+	ReplicationHandler          *ReplicationHandler
 }
 
 // APIBackend is all services and associated parameters required to construct
@@ -43,6 +51,9 @@ type APIBackend struct {
 	NewQueryService  func(*platform.Source) (query.ProxyQueryService, error)
 
 	PointsWriter                    storage.PointsWriter
+	CompactionService               storage.CompactionService
+	ReplicationService              storage.ReplicationService
+	ReplicationMetadataStore        replication.MetadataStore
 	AuthorizationService            platform.AuthorizationService
 	BucketService                   platform.BucketService
 	SessionService                  platform.SessionService
@@ -52,21 +63,32 @@ type APIBackend struct {
 	LabelService                    platform.LabelService
 	DashboardService                platform.DashboardService
 	DashboardOperationLogService    platform.DashboardOperationLogService
+	DashboardTemplateService        platform.DashboardTemplateService
 	BucketOperationLogService       platform.BucketOperationLogService
 	UserOperationLogService         platform.UserOperationLogService
 	OrganizationOperationLogService platform.OrganizationOperationLogService
 	SourceService                   platform.SourceService
 	MacroService                    platform.MacroService
+	CheckService                    platform.CheckService
+	NotificationRuleService         platform.NotificationRuleService
+	NotificationEndpointService     platform.NotificationEndpointService
 	BasicAuthService                platform.BasicAuthService
 	OnboardingService               platform.OnboardingService
 	ProxyQueryService               query.ProxyQueryService
+	QueryService                    query.QueryService
 	TaskService                     platform.TaskService
 	TelegrafService                 platform.TelegrafConfigStore
+	TelegrafAgentService            platform.TelegrafAgentService
 	ScraperTargetStoreService       platform.ScraperTargetStoreService
 	SecretService                   platform.SecretService
 	LookupService                   platform.LookupService
 	ChronografService               *server.Service
 	ProtoService                    platform.ProtoService
+
+	OAuthProviders   []oauth2.Provider
+	OAuthTokenizer   oauth2.Tokenizer
+	OAuthUseIDToken  bool
+	OAuthProvisioner OAuthProvisioner
 }
 
 // NewAPIHandler constructs all api handlers beneath it and returns an APIHandler
@@ -78,6 +100,9 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	sessionBackend := NewSessionBackend(b)
 	h.SessionHandler = NewSessionHandler(sessionBackend)
 
+	oauthBackend := NewOAuthBackend(b)
+	h.OAuthHandler = NewOAuthHandler(oauthBackend)
+
 	h.BucketHandler = NewBucketHandler(b.UserResourceMappingService, b.LabelService, b.UserService)
 	h.BucketHandler.BucketService = b.BucketService
 	h.BucketHandler.BucketOperationLogService = b.BucketOperationLogService
@@ -96,10 +121,23 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.DashboardHandler = NewDashboardHandler(b.UserResourceMappingService, b.LabelService, b.UserService)
 	h.DashboardHandler.DashboardService = b.DashboardService
 	h.DashboardHandler.DashboardOperationLogService = b.DashboardOperationLogService
+	h.DashboardHandler.DashboardTemplateService = b.DashboardTemplateService
 
 	h.MacroHandler = NewMacroHandler()
 	h.MacroHandler.MacroService = b.MacroService
 
+	h.CheckHandler = NewCheckHandler()
+	h.CheckHandler.CheckService = authorizer.NewCheckService(b.CheckService)
+	h.CheckHandler.Logger = b.Logger.With(zap.String("handler", "check"))
+
+	h.NotificationRuleHandler = NewNotificationRuleHandler()
+	h.NotificationRuleHandler.NotificationRuleService = authorizer.NewNotificationRuleService(b.NotificationRuleService)
+	h.NotificationRuleHandler.Logger = b.Logger.With(zap.String("handler", "notification_rule"))
+
+	h.NotificationEndpointHandler = NewNotificationEndpointHandler()
+	h.NotificationEndpointHandler.NotificationEndpointService = authorizer.NewNotificationEndpointService(b.NotificationEndpointService)
+	h.NotificationEndpointHandler.Logger = b.Logger.With(zap.String("handler", "notification_endpoint"))
+
 	h.AuthorizationHandler = NewAuthorizationHandler(b.UserService)
 	h.AuthorizationHandler.OrganizationService = b.OrganizationService
 	h.AuthorizationHandler.AuthorizationService = b.AuthorizationService
@@ -133,6 +171,7 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 		b.UserService,
 		b.OrganizationService,
 	)
+	h.TelegrafHandler.TelegrafAgentService = b.TelegrafAgentService
 
 	h.WriteHandler = NewWriteHandler(b.PointsWriter)
 	h.WriteHandler.OrganizationService = b.OrganizationService
@@ -143,27 +182,43 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.QueryHandler.OrganizationService = b.OrganizationService
 	h.QueryHandler.Logger = b.Logger.With(zap.String("handler", "query"))
 	h.QueryHandler.ProxyQueryService = b.ProxyQueryService
+	h.QueryHandler.MacroService = b.MacroService
+	h.QueryHandler.QueryService = b.QueryService
 
 	h.ProtoHandler = NewProtoHandler(NewProtoBackend(b))
 
 	h.ChronografHandler = NewChronografHandler(b.ChronografService)
 
+	h.CompactionHandler = NewCompactionHandler()
+	h.CompactionHandler.CompactionService = b.CompactionService
+	h.CompactionHandler.Logger = b.Logger.With(zap.String("handler", "compaction"))
+
+	h.ReplicationHandler = NewReplicationHandler()
+	h.ReplicationHandler.ReplicationService = b.ReplicationService
+	h.ReplicationHandler.MetadataStore = b.ReplicationMetadataStore
+	h.ReplicationHandler.Logger = b.Logger.With(zap.String("handler", "replication"))
+
 	return h
 }
 
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
-	"authorizations": "/api/v2/authorizations",
-	"buckets":        "/api/v2/buckets",
-	"dashboards":     "/api/v2/dashboards",
+	"authorizations":   "/api/v2/authorizations",
+	"buckets":          "/api/v2/buckets",
+	"checks":           "/api/v2/checks",
+	"dashboardImports": "/api/v2/dashboardImports",
+	"dashboards":       "/api/v2/dashboards",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
-	"macros": "/api/v2/macros",
-	"me":     "/api/v2/me",
-	"orgs":   "/api/v2/orgs",
-	"protos": "/api/v2/protos",
+	"macros":                "/api/v2/macros",
+	"me":                    "/api/v2/me",
+	"notificationEndpoints": "/api/v2/notificationEndpoints",
+	"notificationRules":     "/api/v2/notificationRules",
+	"oauth":                 "/api/v2/oauth",
+	"orgs":                  "/api/v2/orgs",
+	"protos":                "/api/v2/protos",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
@@ -171,11 +226,19 @@ var apiLinks = map[string]interface{}{
 		"spec":        "/api/v2/query/spec",
 		"suggestions": "/api/v2/query/suggestions",
 	},
+	"replication": map[string]string{
+		"wal":      "/api/v2/replication/wal",
+		"snapshot": "/api/v2/replication/snapshot",
+		"metadata": "/api/v2/replication/metadata",
+	},
 	"setup":          "/api/v2/setup",
 	"signin":         "/api/v2/signin",
 	"signout":        "/api/v2/signout",
 	"sources":        "/api/v2/sources",
 	"scrapertargets": "/api/v2/scrapertargets",
+	"storage": map[string]string{
+		"compactions": "/api/v2/storage/compactions",
+	},
 	"system": map[string]string{
 		"metrics": "/metrics",
 		"debug":   "/debug/pprof",
@@ -213,6 +276,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/oauth") {
+		h.OAuthHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/setup") {
 		h.SetupHandler.ServeHTTP(w, r)
 		return
@@ -253,7 +321,7 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.HasPrefix(r.URL.Path, "/api/v2/dashboards") {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/dashboards") || strings.HasPrefix(r.URL.Path, "/api/v2/dashboardImports") {
 		h.DashboardHandler.ServeHTTP(w, r)
 		return
 	}
@@ -283,6 +351,31 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/checks") {
+		h.CheckHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/notificationRules") {
+		h.NotificationRuleHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/notificationEndpoints") {
+		h.NotificationEndpointHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/storage/compactions") && h.CompactionHandler.CompactionService != nil {
+		h.CompactionHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/replication") && h.ReplicationHandler.ReplicationService != nil {
+		h.ReplicationHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/protos") {
 		h.ProtoHandler.ServeHTTP(w, r)
 		return
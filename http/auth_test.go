@@ -14,6 +14,7 @@ import (
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/kit/errors"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 
 	platformtesting "github.com/influxdata/influxdb/testing"
@@ -836,7 +837,7 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 	}
 	done := server.Close
 
-	return &client, inmem.OpPrefix, done
+	return &client, kv.OpPrefix, done
 }
 
 func TestAuthorizationService_CreateAuthorization(t *testing.T) {
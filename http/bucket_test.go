@@ -13,6 +13,7 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
 	"github.com/julienschmidt/httprouter"
@@ -1017,11 +1018,11 @@ func initBucketService(f platformtesting.BucketFields, t *testing.T) (platform.B
 	server := httptest.NewServer(handler)
 	client := BucketService{
 		Addr:     server.URL,
-		OpPrefix: inmem.OpPrefix,
+		OpPrefix: kv.OpPrefix,
 	}
 	done := server.Close
 
-	return &client, inmem.OpPrefix, done
+	return &client, kv.OpPrefix, done
 }
 
 func TestBucketService(t *testing.T) {
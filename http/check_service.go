@@ -0,0 +1,405 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	checksPath = "/api/v2/checks"
+)
+
+// CheckHandler is the handler for the check service
+type CheckHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	CheckService platform.CheckService
+}
+
+// NewCheckHandler creates a new CheckHandler
+func NewCheckHandler() *CheckHandler {
+	h := &CheckHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", checksPath)
+
+	h.HandlerFunc("GET", checksPath, h.handleGetChecks)
+	h.HandlerFunc("POST", checksPath, h.handlePostCheck)
+	h.HandlerFunc("GET", entityPath, h.handleGetCheck)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchCheck)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteCheck)
+
+	return h
+}
+
+type checkLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+	Task string `json:"task,omitempty"`
+}
+
+type checkResponse struct {
+	*platform.Check
+	Links checkLinks `json:"links"`
+}
+
+func newCheckResponse(c *platform.Check) checkResponse {
+	res := checkResponse{
+		Check: c,
+		Links: checkLinks{
+			Self: fmt.Sprintf("/api/v2/checks/%s", c.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", c.OrganizationID),
+		},
+	}
+	if c.TaskID.Valid() {
+		res.Links.Task = fmt.Sprintf("/api/v2/tasks/%s", c.TaskID)
+	}
+	return res
+}
+
+type getChecksResponse struct {
+	Checks []checkResponse       `json:"checks"`
+	Links  *platform.PagingLinks `json:"links"`
+}
+
+func (r getChecksResponse) ToPlatform() []*platform.Check {
+	checks := make([]*platform.Check, len(r.Checks))
+	for i := range r.Checks {
+		checks[i] = r.Checks[i].Check
+	}
+	return checks
+}
+
+func newGetChecksResponse(checks []*platform.Check, f platform.CheckFilter, opts platform.FindOptions) getChecksResponse {
+	resp := getChecksResponse{
+		Checks: make([]checkResponse, 0, len(checks)),
+		Links:  newPagingLinks(checksPath, opts, f, len(checks)),
+	}
+	for _, c := range checks {
+		resp.Checks = append(resp.Checks, newCheckResponse(c))
+	}
+	return resp
+}
+
+// decodeOrgResourceFilter decodes the id, orgID and org query parameters
+// shared by the filters of organization resources.
+func decodeOrgResourceFilter(qp url.Values) (id, orgID *platform.ID, org *string, err error) {
+	if s := qp.Get("id"); s != "" {
+		if id, err = platform.IDFromString(s); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if s := qp.Get("orgID"); s != "" {
+		if orgID, err = platform.IDFromString(s); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if s := qp.Get("org"); s != "" {
+		org = &s
+	}
+	return id, orgID, org, nil
+}
+
+type getChecksRequest struct {
+	filter platform.CheckFilter
+	opts   platform.FindOptions
+}
+
+func decodeGetChecksRequest(ctx context.Context, r *http.Request) (*getChecksRequest, error) {
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getChecksRequest{
+		opts: *opts,
+	}
+	req.filter.ID, req.filter.OrganizationID, req.filter.Organization, err = decodeOrgResourceFilter(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (h *CheckHandler) handleGetChecks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetChecksRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	checks, _, err := h.CheckService.FindChecks(ctx, req.filter, req.opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetChecksResponse(checks, req.filter, req.opts)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestResourceID(ctx context.Context) (platform.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return platform.InvalidID(), &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := platform.IDFromString(urlID)
+	if err != nil {
+		return platform.InvalidID(), &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	return *id, nil
+}
+
+func (h *CheckHandler) handleGetCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	c, err := h.CheckService.FindCheckByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newCheckResponse(c)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostCheckRequest(ctx context.Context, r *http.Request) (*platform.Check, error) {
+	c := &platform.Check{}
+	if err := json.NewDecoder(r.Body).Decode(c); err != nil {
+		return nil, &platform.Error{Code: platform.EInvalid, Msg: "malformed json", Err: err}
+	}
+
+	// The task of a check is managed by the server.
+	c.TaskID = 0
+	if err := c.Valid(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (h *CheckHandler) handlePostCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	c, err := decodePostCheckRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.CheckService.CreateCheck(ctx, c); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newCheckResponse(c)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type patchCheckRequest struct {
+	id  platform.ID
+	upd platform.CheckUpdate
+}
+
+func decodePatchCheckRequest(ctx context.Context, r *http.Request) (*patchCheckRequest, error) {
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &patchCheckRequest{
+		id: id,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req.upd); err != nil {
+		return nil, &platform.Error{Code: platform.EInvalid, Msg: "malformed json", Err: err}
+	}
+
+	if err := req.upd.Valid(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (h *CheckHandler) handlePatchCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePatchCheckRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	c, err := h.CheckService.UpdateCheck(ctx, req.id, req.upd)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newCheckResponse(c)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *CheckHandler) handleDeleteCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.CheckService.DeleteCheck(ctx, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CheckService is a check service over HTTP to the influxdb server
+type CheckService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.CheckService = (*CheckService)(nil)
+
+// FindCheckByID returns a single check by ID.
+func (s *CheckService) FindCheckByID(ctx context.Context, id platform.ID) (*platform.Check, error) {
+	var cr checkResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "GET", checkIDPath(id), nil, nil, &cr); err != nil {
+		return nil, err
+	}
+	return cr.Check, nil
+}
+
+// FindChecks returns a list of checks that match filter.
+func (s *CheckService) FindChecks(ctx context.Context, filter platform.CheckFilter, opt ...platform.FindOptions) ([]*platform.Check, int, error) {
+	var cs getChecksResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "GET", checksPath, filter.QueryParams(), nil, &cs); err != nil {
+		return nil, 0, err
+	}
+	checks := cs.ToPlatform()
+	return checks, len(checks), nil
+}
+
+// CreateCheck creates a new check and sets c.ID with the new identifier.
+func (s *CheckService) CreateCheck(ctx context.Context, c *platform.Check) error {
+	var cr checkResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "POST", checksPath, nil, c, &cr); err != nil {
+		return err
+	}
+	*c = *cr.Check
+	return nil
+}
+
+// UpdateCheck updates a single check with changeset.
+func (s *CheckService) UpdateCheck(ctx context.Context, id platform.ID, upd platform.CheckUpdate) (*platform.Check, error) {
+	var cr checkResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "PATCH", checkIDPath(id), nil, upd, &cr); err != nil {
+		return nil, err
+	}
+	return cr.Check, nil
+}
+
+// DeleteCheck removes a check by ID.
+func (s *CheckService) DeleteCheck(ctx context.Context, id platform.ID) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "DELETE", checkIDPath(id), nil, nil, nil)
+}
+
+func checkIDPath(id platform.ID) string {
+	return path.Join(checksPath, id.String())
+}
+
+// doJSONRequest sends body encoded as JSON to the path p of addr and
+// decodes the response into v, if v is not nil.
+func doJSONRequest(ctx context.Context, addr, token string, insecureSkipVerify bool, method, p string, qp url.Values, body, v interface{}) error {
+	u, err := newURL(addr, p)
+	if err != nil {
+		return err
+	}
+	if qp != nil {
+		u.RawQuery = qp.Encode()
+	}
+
+	var r *bytes.Reader
+	if body != nil {
+		octets, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(octets)
+	}
+
+	var req *http.Request
+	if r != nil {
+		req, err = http.NewRequest(method, u.String(), r)
+	} else {
+		req, err = http.NewRequest(method, u.String(), nil)
+	}
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	SetToken(token, req)
+
+	hc := newClient(u.Scheme, insecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestCheckService_handleGetChecks(t *testing.T) {
+	svc := mock.NewCheckService()
+	svc.FindChecksFn = func(ctx context.Context, filter platform.CheckFilter, opts ...platform.FindOptions) ([]*platform.Check, int, error) {
+		if filter.OrganizationID == nil || *filter.OrganizationID != 1 {
+			t.Errorf("unexpected filter %+v", filter)
+		}
+		return []*platform.Check{
+			{
+				ID:             platformtesting.MustIDBase16("020f755c3c082000"),
+				OrganizationID: 1,
+				Name:           "cpu",
+				Status:         platform.CheckStatusActive,
+				Type:           platform.DeadmanCheckType,
+				Query:          `from(bucket: "b") |> range(start: -1h)`,
+				Every:          "1m",
+				TimeSince:      "5m",
+				Level:          platform.LevelCrit,
+				TaskID:         2,
+			},
+		}, 1, nil
+	}
+
+	h := NewCheckHandler()
+	h.CheckService = svc
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/checks?orgID=0000000000000001", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("handleGetChecks() = %v, want %v: %s", res.StatusCode, http.StatusOK, body)
+	}
+
+	want := `{"checks":[{"id":"020f755c3c082000","orgID":"0000000000000001","name":"cpu","status":"active","type":"deadman","query":"from(bucket: \"b\") |> range(start: -1h)","every":"1m","timeSince":"5m","level":"crit","taskID":"0000000000000002","links":{"self":"/api/v2/checks/020f755c3c082000","org":"/api/v2/orgs/0000000000000001","task":"/api/v2/tasks/0000000000000002"}}],"links":{"self":"/api/v2/checks?descending=false&limit=20&offset=0&orgID=0000000000000001"}}`
+	if eq, diff, _ := jsonEqual(string(body), want); !eq {
+		t.Errorf("handleGetChecks() = ***%s***", diff)
+	}
+}
+
+func TestCheckService_handlePostCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+	}{
+		{
+			name:       "create threshold check",
+			body:       `{"orgID":"0000000000000001","name":"cpu","type":"threshold","query":"from(bucket: \"b\") |> range(start: -1m)","every":"1m","thresholds":[{"level":"crit","op":"greater","value":90}]}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "threshold check without thresholds",
+			body:       `{"orgID":"0000000000000001","name":"cpu","type":"threshold","query":"from(bucket: \"b\") |> range(start: -1m)","every":"1m"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed body",
+			body:       `{`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewCheckService()
+			svc.CreateCheckFn = func(ctx context.Context, c *platform.Check) error {
+				c.ID = 3
+				return nil
+			}
+
+			h := NewCheckHandler()
+			h.CheckService = svc
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/checks", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if res := w.Result(); res.StatusCode != tt.statusCode {
+				body, _ := ioutil.ReadAll(res.Body)
+				t.Errorf("handlePostCheck() = %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+		})
+	}
+}
+
+func TestCheckService_Client(t *testing.T) {
+	check := &platform.Check{
+		OrganizationID: 1,
+		Name:           "cpu",
+		Type:           platform.ThresholdCheckType,
+		Query:          `from(bucket: "b") |> range(start: -1m)`,
+		Every:          "1m",
+		Thresholds: []platform.Threshold{
+			{Level: platform.LevelWarn, Op: platform.ThresholdLesser, Value: 1.5},
+		},
+	}
+
+	svc := mock.NewCheckService()
+	svc.CreateCheckFn = func(ctx context.Context, c *platform.Check) error {
+		c.ID = 3
+		c.Status = platform.CheckStatusActive
+		c.TaskID = 4
+		return nil
+	}
+	svc.FindCheckByIDFn = func(ctx context.Context, id platform.ID) (*platform.Check, error) {
+		if id != 3 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrCheckNotFound}
+		}
+		return check, nil
+	}
+
+	h := NewCheckHandler()
+	h.CheckService = svc
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	client := CheckService{Addr: server.URL}
+	ctx := context.Background()
+	if err := client.CreateCheck(ctx, check); err != nil {
+		t.Fatal(err)
+	}
+	if check.ID != 3 || check.TaskID != 4 {
+		t.Errorf("unexpected created check %+v", check)
+	}
+
+	got, err := client.FindCheckByID(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, check); diff != "" {
+		t.Errorf("checks are different -got/+want\ndiff %s", diff)
+	}
+
+	if _, err := client.FindCheckByID(ctx, 5); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
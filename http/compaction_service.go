@@ -0,0 +1,290 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	compactionsPath = "/api/v2/storage/compactions"
+)
+
+// Compaction types that can be scheduled through the API.
+const (
+	CompactionTypeFull     = "full"
+	CompactionTypeOptimize = "optimize"
+)
+
+// CompactionHandler is the handler for observing and controlling the compactions
+// of the storage engine.
+type CompactionHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	CompactionService storage.CompactionService
+}
+
+// NewCompactionHandler creates a new CompactionHandler.
+func NewCompactionHandler() *CompactionHandler {
+	h := &CompactionHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("GET", compactionsPath, h.handleGetCompactions)
+	h.HandlerFunc("POST", compactionsPath, h.handlePostCompaction)
+	h.HandlerFunc("PATCH", compactionsPath, h.handlePatchCompactions)
+
+	return h
+}
+
+// Compaction is a running or queued compaction of the storage engine.
+type Compaction struct {
+	Level        int              `json:"level"`
+	Status       string           `json:"status"`
+	StartedAt    *time.Time       `json:"startedAt,omitempty"`
+	Files        []CompactionFile `json:"files"`
+	Size         int64            `json:"size"`
+	BytesWritten int64            `json:"bytesWritten"`
+	Progress     float64          `json:"progress"`
+}
+
+// CompactionFile is a TSM file that is part of a compaction.
+type CompactionFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Compactions is the state of the compactions of the storage engine.
+type Compactions struct {
+	Enabled     bool         `json:"enabled"`
+	Compactions []Compaction `json:"compactions"`
+}
+
+func newCompactions(enabled bool, infos []tsm1.CompactionInfo) Compactions {
+	res := Compactions{
+		Enabled:     enabled,
+		Compactions: make([]Compaction, 0, len(infos)),
+	}
+	for _, info := range infos {
+		c := Compaction{
+			Level:        info.Level,
+			Status:       "queued",
+			Files:        make([]CompactionFile, 0, len(info.Files)),
+			Size:         info.Size,
+			BytesWritten: info.BytesWritten,
+			Progress:     info.Progress(),
+		}
+		if info.Running {
+			startedAt := info.StartedAt
+			c.Status = "running"
+			c.StartedAt = &startedAt
+		}
+		for _, f := range info.Files {
+			c.Files = append(c.Files, CompactionFile{Path: f.Path, Size: f.Size})
+		}
+		res.Compactions = append(res.Compactions, c)
+	}
+	return res
+}
+
+func (h *CompactionHandler) compactions(ctx context.Context) (Compactions, error) {
+	enabled, err := h.CompactionService.CompactionsEnabled()
+	if err != nil {
+		return Compactions{}, err
+	}
+	infos, err := h.CompactionService.Compactions()
+	if err != nil {
+		return Compactions{}, err
+	}
+	return newCompactions(enabled, infos), nil
+}
+
+func authorizeStorage(ctx context.Context, a platform.Action) error {
+	return authorizer.IsAllowed(ctx, platform.Permission{
+		Action:   a,
+		Resource: platform.Resource{Type: platform.StorageResourceType},
+	})
+}
+
+// handleGetCompactions is the HTTP handler for the GET /api/v2/storage/compactions route.
+func (h *CompactionHandler) handleGetCompactions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeStorage(ctx, platform.ReadAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res, err := h.compactions(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type postCompactionRequest struct {
+	Type string `json:"type"`
+}
+
+func decodePostCompactionRequest(ctx context.Context, r *http.Request) (*postCompactionRequest, error) {
+	req := &postCompactionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	switch req.Type {
+	case CompactionTypeFull, CompactionTypeOptimize:
+	default:
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "compaction type must be full or optimize",
+		}
+	}
+	return req, nil
+}
+
+// handlePostCompaction is the HTTP handler for the POST /api/v2/storage/compactions route.
+func (h *CompactionHandler) handlePostCompaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeStorage(ctx, platform.WriteAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req, err := decodePostCompactionRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if req.Type == CompactionTypeFull {
+		err = h.CompactionService.ScheduleFullCompaction()
+	} else {
+		err = h.CompactionService.ScheduleOptimizeCompaction()
+	}
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res, err := h.compactions(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusAccepted, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type patchCompactionsRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+func decodePatchCompactionsRequest(ctx context.Context, r *http.Request) (*patchCompactionsRequest, error) {
+	req := &patchCompactionsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if req.Enabled == nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "enabled is required",
+		}
+	}
+	return req, nil
+}
+
+// handlePatchCompactions is the HTTP handler for the PATCH /api/v2/storage/compactions route.
+// It pauses or resumes compactions.
+func (h *CompactionHandler) handlePatchCompactions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeStorage(ctx, platform.WriteAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req, err := decodePatchCompactionsRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.CompactionService.SetCompactionsEnabled(*req.Enabled); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res, err := h.compactions(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// CompactionService connects to Influx via HTTP using tokens to manage the
+// compactions of the storage engine.
+type CompactionService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// Compactions returns the state of the compactions.
+func (s *CompactionService) Compactions(ctx context.Context) (*Compactions, error) {
+	var cs Compactions
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "GET", compactionsPath, nil, nil, &cs); err != nil {
+		return nil, err
+	}
+	return &cs, nil
+}
+
+// ScheduleCompaction schedules a compaction of type full or optimize.
+func (s *CompactionService) ScheduleCompaction(ctx context.Context, typ string) (*Compactions, error) {
+	var cs Compactions
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "POST", compactionsPath, nil, postCompactionRequest{Type: typ}, &cs); err != nil {
+		return nil, err
+	}
+	return &cs, nil
+}
+
+// SetCompactionsEnabled pauses or resumes compactions.
+func (s *CompactionService) SetCompactionsEnabled(ctx context.Context, enabled bool) (*Compactions, error) {
+	var cs Compactions
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "PATCH", compactionsPath, nil, patchCompactionsRequest{Enabled: &enabled}, &cs); err != nil {
+		return nil, err
+	}
+	return &cs, nil
+}
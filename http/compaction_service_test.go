@@ -0,0 +1,174 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+type fakeCompactionService struct {
+	enabled   bool
+	scheduled []string
+	infos     []tsm1.CompactionInfo
+}
+
+func (s *fakeCompactionService) Compactions() ([]tsm1.CompactionInfo, error) {
+	return s.infos, nil
+}
+
+func (s *fakeCompactionService) CompactionsEnabled() (bool, error) {
+	return s.enabled, nil
+}
+
+func (s *fakeCompactionService) SetCompactionsEnabled(enabled bool) error {
+	s.enabled = enabled
+	return nil
+}
+
+func (s *fakeCompactionService) ScheduleFullCompaction() error {
+	s.scheduled = append(s.scheduled, CompactionTypeFull)
+	return nil
+}
+
+func (s *fakeCompactionService) ScheduleOptimizeCompaction() error {
+	s.scheduled = append(s.scheduled, CompactionTypeOptimize)
+	return nil
+}
+
+func TestCompactionHandler(t *testing.T) {
+	started := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	infos := []tsm1.CompactionInfo{
+		{
+			Level:        2,
+			Running:      true,
+			StartedAt:    started,
+			Files:        []tsm1.CompactionFileInfo{{Path: "000000001-000000001.tsm", Size: 100}},
+			Size:         100,
+			BytesWritten: 25,
+		},
+		{
+			Level: 5,
+			Files: []tsm1.CompactionFileInfo{{Path: "000000002-000000004.tsm", Size: 50}},
+			Size:  50,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		body          string
+		permissions   []platform.Permission
+		wantStatus    int
+		wantEnabled   bool
+		wantScheduled []string
+	}{
+		{
+			name:        "get compactions",
+			method:      "GET",
+			permissions: []platform.Permission{{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.StorageResourceType}}},
+			wantStatus:  http.StatusOK,
+			wantEnabled: true,
+		},
+		{
+			name:        "get compactions without permission",
+			method:      "GET",
+			wantStatus:  http.StatusForbidden,
+			wantEnabled: true,
+		},
+		{
+			name:          "schedule full compaction",
+			method:        "POST",
+			body:          `{"type":"full"}`,
+			permissions:   []platform.Permission{{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.StorageResourceType}}},
+			wantStatus:    http.StatusAccepted,
+			wantEnabled:   true,
+			wantScheduled: []string{CompactionTypeFull},
+		},
+		{
+			name:        "schedule unknown compaction",
+			method:      "POST",
+			body:        `{"type":"level"}`,
+			permissions: []platform.Permission{{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.StorageResourceType}}},
+			wantStatus:  http.StatusBadRequest,
+			wantEnabled: true,
+		},
+		{
+			name:        "schedule compaction with read permission",
+			method:      "POST",
+			body:        `{"type":"optimize"}`,
+			permissions: []platform.Permission{{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.StorageResourceType}}},
+			wantStatus:  http.StatusForbidden,
+			wantEnabled: true,
+		},
+		{
+			name:        "pause compactions",
+			method:      "PATCH",
+			body:        `{"enabled":false}`,
+			permissions: []platform.Permission{{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.StorageResourceType}}},
+			wantStatus:  http.StatusOK,
+			wantEnabled: false,
+		},
+		{
+			name:        "patch without enabled",
+			method:      "PATCH",
+			body:        `{}`,
+			permissions: []platform.Permission{{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.StorageResourceType}}},
+			wantStatus:  http.StatusBadRequest,
+			wantEnabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeCompactionService{enabled: true, infos: infos}
+			h := NewCompactionHandler()
+			h.CompactionService = svc
+
+			r := httptest.NewRequest(tt.method, compactionsPath, strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", got, tt.wantStatus, w.Body.String())
+			}
+			if got := svc.enabled; got != tt.wantEnabled {
+				t.Errorf("enabled = %v, want %v", got, tt.wantEnabled)
+			}
+			if got, want := strings.Join(svc.scheduled, ","), strings.Join(tt.wantScheduled, ","); got != want {
+				t.Errorf("scheduled = %q, want %q", got, want)
+			}
+			if w.Code >= http.StatusBadRequest {
+				return
+			}
+
+			var res Compactions
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatalf("unexpected error decoding response: %v", err)
+			}
+			if res.Enabled != tt.wantEnabled {
+				t.Errorf("response enabled = %v, want %v", res.Enabled, tt.wantEnabled)
+			}
+			if len(res.Compactions) != 2 {
+				t.Fatalf("got %d compactions, want 2", len(res.Compactions))
+			}
+			running, queued := res.Compactions[0], res.Compactions[1]
+			if running.Status != "running" || running.StartedAt == nil || !running.StartedAt.Equal(started) || running.Progress != 0.25 {
+				t.Errorf("unexpected running compaction: %+v", running)
+			}
+			if queued.Status != "queued" || queued.StartedAt != nil || queued.Level != 5 || len(queued.Files) != 1 {
+				t.Errorf("unexpected queued compaction: %+v", queued)
+			}
+		})
+	}
+}
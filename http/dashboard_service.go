@@ -24,6 +24,7 @@ type DashboardHandler struct {
 
 	DashboardService             platform.DashboardService
 	DashboardOperationLogService platform.DashboardOperationLogService
+	DashboardTemplateService     platform.DashboardTemplateService
 	UserResourceMappingService   platform.UserResourceMappingService
 	LabelService                 platform.LabelService
 	UserService                  platform.UserService
@@ -42,6 +43,8 @@ const (
 	dashboardsIDOwnersIDPath    = "/api/v2/dashboards/:id/owners/:userID"
 	dashboardsIDLabelsPath      = "/api/v2/dashboards/:id/labels"
 	dashboardsIDLabelsNamePath  = "/api/v2/dashboards/:id/labels/:name"
+	dashboardsIDExportPath      = "/api/v2/dashboards/:id/export"
+	dashboardImportsPath        = "/api/v2/dashboardImports"
 )
 
 // NewDashboardHandler returns a new instance of DashboardHandler.
@@ -62,6 +65,9 @@ func NewDashboardHandler(mappingService platform.UserResourceMappingService, lab
 	h.HandlerFunc("DELETE", dashboardsIDPath, h.handleDeleteDashboard)
 	h.HandlerFunc("PATCH", dashboardsIDPath, h.handlePatchDashboard)
 
+	h.HandlerFunc("GET", dashboardsIDExportPath, h.handleGetDashboardExport)
+	h.HandlerFunc("POST", dashboardImportsPath, h.handlePostDashboardImport)
+
 	h.HandlerFunc("PUT", dashboardsIDCellsPath, h.handlePutDashboardCells)
 	h.HandlerFunc("POST", dashboardsIDCellsPath, h.handlePostDashboardCell)
 	h.HandlerFunc("DELETE", dashboardsIDCellsIDPath, h.handleDeleteDashboardCell)
@@ -913,6 +919,88 @@ func (h *DashboardHandler) handlePatchDashboardCell(w http.ResponseWriter, r *ht
 	}
 }
 
+// handleGetDashboardExport exports a dashboard to a template.
+func (h *DashboardHandler) handleGetDashboardExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	t, err := h.DashboardTemplateService.ExportDashboard(ctx, req.DashboardID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, t); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type postDashboardImportRequest struct {
+	OrganizationID platform.ID                 `json:"orgID"`
+	Template       *platform.DashboardTemplate `json:"template"`
+	platform.ImportDashboardOptions
+}
+
+func decodePostDashboardImportRequest(ctx context.Context, r *http.Request) (*postDashboardImportRequest, error) {
+	req := &postDashboardImportRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   platform.OpImportDashboard,
+			Err:  err,
+		}
+	}
+	if !req.OrganizationID.Valid() {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   platform.OpImportDashboard,
+			Msg:  "orgID is required",
+		}
+	}
+	if req.Template == nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   platform.OpImportDashboard,
+			Msg:  "template is required",
+		}
+	}
+	return req, nil
+}
+
+// handlePostDashboardImport creates a dashboard from a template.
+func (h *DashboardHandler) handlePostDashboardImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostDashboardImportRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	d, err := h.DashboardTemplateService.ImportDashboard(ctx, req.OrganizationID, req.Template, req.ImportDashboardOptions)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindLabels(ctx, platform.LabelFilter{ResourceID: d.ID})
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newDashboardResponse(d, labels)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 // DashboardService is a dashboard service over HTTP to the influxdb server.
 type DashboardService struct {
 	Addr               string
@@ -1333,6 +1421,80 @@ func (s *DashboardService) ReplaceDashboardCells(ctx context.Context, id platfor
 	return nil
 }
 
+// ExportDashboard returns a template of the dashboard with the given ID.
+func (s *DashboardService) ExportDashboard(ctx context.Context, id platform.ID) (*platform.DashboardTemplate, error) {
+	url, err := newURL(s.Addr, path.Join(dashboardIDPath(id), "export"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(s.Token, req)
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var t platform.DashboardTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ImportDashboard creates a dashboard in an organization from a template.
+func (s *DashboardService) ImportDashboard(ctx context.Context, orgID platform.ID, t *platform.DashboardTemplate, opts platform.ImportDashboardOptions) (*platform.Dashboard, error) {
+	url, err := newURL(s.Addr, dashboardImportsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(postDashboardImportRequest{
+		OrganizationID:         orgID,
+		Template:               t,
+		ImportDashboardOptions: opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url.String(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var dr dashboardResponse
+	if err := json.NewDecoder(resp.Body).Decode(&dr); err != nil {
+		return nil, err
+	}
+	return dr.toPlatform(), nil
+}
+
 func dashboardIDPath(id platform.ID) string {
 	return path.Join(dashboardsPath, id.String())
 }
@@ -8,11 +8,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
 	"github.com/julienschmidt/httprouter"
@@ -1279,14 +1281,88 @@ func initDashboardService(f platformtesting.DashboardFields, t *testing.T) (plat
 	server := httptest.NewServer(h)
 	client := DashboardService{
 		Addr:     server.URL,
-		OpPrefix: inmem.OpPrefix,
+		OpPrefix: kv.OpPrefix,
 	}
 	done := server.Close
 
-	return &client, inmem.OpPrefix, done
+	return &client, kv.OpPrefix, done
 }
 
 func TestDashboardService(t *testing.T) {
 	t.Parallel()
 	platformtesting.DashboardService(initDashboardService, t)
 }
+
+func TestDashboardService_Templates(t *testing.T) {
+	tmpl := &platform.DashboardTemplate{
+		ProtoDashboard: platform.ProtoDashboard{
+			Dashboard: platform.Dashboard{
+				Name:  "system",
+				Cells: []*platform.Cell{{ID: 2, W: 4, H: 4}},
+			},
+			Views: map[platform.ID]platform.View{
+				2: {
+					ViewContents: platform.ViewContents{Name: "cpu"},
+					Properties: platform.XYViewProperties{
+						Type:    "xy",
+						Queries: []platform.DashboardQuery{{Text: `from(bucket: "telegraf")`, Type: "flux"}},
+					},
+				},
+			},
+		},
+		Macros:  []*platform.Macro{},
+		Labels:  []*platform.Label{{Name: "infra"}},
+		Buckets: []string{"telegraf"},
+	}
+
+	templateService := &mock.DashboardTemplateService{
+		ExportDashboardF: func(ctx context.Context, id platform.ID) (*platform.DashboardTemplate, error) {
+			if id != 1 {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: "dashboard not found"}
+			}
+			return tmpl, nil
+		},
+		ImportDashboardF: func(ctx context.Context, orgID platform.ID, got *platform.DashboardTemplate, opts platform.ImportDashboardOptions) (*platform.Dashboard, error) {
+			if !reflect.DeepEqual(got, tmpl) {
+				t.Errorf("got template %+v, want %+v", got, tmpl)
+			}
+			if want := map[string]string{"telegraf": "metrics"}; !reflect.DeepEqual(opts.BucketNames, want) {
+				t.Errorf("got bucket names %v, want %v", opts.BucketNames, want)
+			}
+			return &platform.Dashboard{
+				ID:             3,
+				OrganizationID: orgID,
+				Name:           got.Dashboard.Name,
+				Cells:          []*platform.Cell{{ID: 4, W: 4, H: 4}},
+			}, nil
+		},
+	}
+
+	h := NewDashboardHandler(mock.NewUserResourceMappingService(), mock.NewLabelService(), mock.NewUserService())
+	h.DashboardTemplateService = templateService
+	server := httptest.NewServer(h)
+	defer server.Close()
+	client := DashboardService{Addr: server.URL}
+
+	ctx := context.Background()
+	got, err := client.ExportDashboard(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, tmpl) {
+		t.Errorf("got template %+v, want %+v", got, tmpl)
+	}
+	if _, err := client.ExportDashboard(ctx, 2); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("unexpected error exporting missing dashboard: %v", err)
+	}
+
+	d, err := client.ImportDashboard(ctx, 5, got, platform.ImportDashboardOptions{
+		BucketNames: map[string]string{"telegraf": "metrics"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ID != 3 || d.OrganizationID != 5 || len(d.Cells) != 1 || d.Cells[0].ID != 4 {
+		t.Errorf("unexpected dashboard %+v", d)
+	}
+}
@@ -13,6 +13,10 @@ import (
 	"github.com/julienschmidt/httprouter"
 )
 
+var _ plat.LabelService = (*LabelService)(nil)
+
+// LabelService connects to Influx via HTTP using tokens to manage the labels
+// of the resources at BasePath, such as "/api/v2/dashboards".
 type LabelService struct {
 	Addr               string
 	Token              string
@@ -297,7 +301,7 @@ func decodeDeleteLabelRequest(ctx context.Context, r *http.Request) (*deleteLabe
 
 // FindLabels returns a slice of labels
 func (s *LabelService) FindLabels(ctx context.Context, filter plat.LabelFilter, opt ...plat.FindOptions) ([]*plat.Label, error) {
-	url, err := newURL(s.Addr, resourceIDPath(s.BasePath, filter.ResourceID))
+	url, err := newURL(s.Addr, labelsPath(s.BasePath, filter.ResourceID))
 	if err != nil {
 		return nil, err
 	}
@@ -333,7 +337,7 @@ func (s *LabelService) CreateLabel(ctx context.Context, l *plat.Label) error {
 		return err
 	}
 
-	url, err := newURL(s.Addr, resourceIDPath(s.BasePath, l.ResourceID))
+	url, err := newURL(s.Addr, labelsPath(s.BasePath, l.ResourceID))
 	if err != nil {
 		return err
 	}
@@ -362,13 +366,54 @@ func (s *LabelService) CreateLabel(ctx context.Context, l *plat.Label) error {
 		return err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(l); err != nil {
+	var r labelResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
 		return err
 	}
+	*l = r.Label
 
 	return nil
 }
 
+// UpdateLabel updates the properties of a label.
+func (s *LabelService) UpdateLabel(ctx context.Context, l *plat.Label, upd plat.LabelUpdate) (*plat.Label, error) {
+	url, err := newURL(s.Addr, labelNamePath(s.BasePath, l.ResourceID, l.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(upd)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", url.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var r labelResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+
+	return &r.Label, nil
+}
+
 func (s *LabelService) DeleteLabel(ctx context.Context, l plat.Label) error {
 	url, err := newURL(s.Addr, labelNamePath(s.BasePath, l.ResourceID, l.Name))
 	if err != nil {
@@ -389,6 +434,10 @@ func (s *LabelService) DeleteLabel(ctx context.Context, l plat.Label) error {
 	return CheckError(resp)
 }
 
+func labelsPath(basePath string, resourceID plat.ID) string {
+	return path.Join(basePath, resourceID.String(), "labels")
+}
+
 func labelNamePath(basePath string, resourceID plat.ID, name string) string {
 	return path.Join(basePath, resourceID.String(), "labels", name)
 }
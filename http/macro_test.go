@@ -11,6 +11,7 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
 	kerrors "github.com/influxdata/influxdb/kit/errors"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
 	"github.com/julienschmidt/httprouter"
@@ -603,7 +604,7 @@ func initMacroService(f platformtesting.MacroFields, t *testing.T) (platform.Mac
 	}
 	done := server.Close
 
-	return &client, inmem.OpPrefix, done
+	return &client, kv.OpPrefix, done
 }
 
 func TestMacroService(t *testing.T) {
@@ -0,0 +1,508 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	notificationRulesPath     = "/api/v2/notificationRules"
+	notificationEndpointsPath = "/api/v2/notificationEndpoints"
+)
+
+// NotificationRuleHandler is the handler for the notification rule service
+type NotificationRuleHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	NotificationRuleService platform.NotificationRuleService
+}
+
+// NewNotificationRuleHandler creates a new NotificationRuleHandler
+func NewNotificationRuleHandler() *NotificationRuleHandler {
+	h := &NotificationRuleHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", notificationRulesPath)
+
+	h.HandlerFunc("GET", notificationRulesPath, h.handleGetNotificationRules)
+	h.HandlerFunc("POST", notificationRulesPath, h.handlePostNotificationRule)
+	h.HandlerFunc("GET", entityPath, h.handleGetNotificationRule)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchNotificationRule)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteNotificationRule)
+
+	return h
+}
+
+type notificationRuleLinks struct {
+	Self     string `json:"self"`
+	Org      string `json:"org"`
+	Endpoint string `json:"endpoint"`
+}
+
+type notificationRuleResponse struct {
+	*platform.NotificationRule
+	Links notificationRuleLinks `json:"links"`
+}
+
+func newNotificationRuleResponse(nr *platform.NotificationRule) notificationRuleResponse {
+	return notificationRuleResponse{
+		NotificationRule: nr,
+		Links: notificationRuleLinks{
+			Self:     fmt.Sprintf("/api/v2/notificationRules/%s", nr.ID),
+			Org:      fmt.Sprintf("/api/v2/orgs/%s", nr.OrganizationID),
+			Endpoint: fmt.Sprintf("/api/v2/notificationEndpoints/%s", nr.EndpointID),
+		},
+	}
+}
+
+type getNotificationRulesResponse struct {
+	NotificationRules []notificationRuleResponse `json:"notificationRules"`
+	Links             *platform.PagingLinks      `json:"links"`
+}
+
+func (r getNotificationRulesResponse) ToPlatform() []*platform.NotificationRule {
+	rules := make([]*platform.NotificationRule, len(r.NotificationRules))
+	for i := range r.NotificationRules {
+		rules[i] = r.NotificationRules[i].NotificationRule
+	}
+	return rules
+}
+
+func newGetNotificationRulesResponse(rules []*platform.NotificationRule, f platform.NotificationRuleFilter, opts platform.FindOptions) getNotificationRulesResponse {
+	resp := getNotificationRulesResponse{
+		NotificationRules: make([]notificationRuleResponse, 0, len(rules)),
+		Links:             newPagingLinks(notificationRulesPath, opts, f, len(rules)),
+	}
+	for _, nr := range rules {
+		resp.NotificationRules = append(resp.NotificationRules, newNotificationRuleResponse(nr))
+	}
+	return resp
+}
+
+func (h *NotificationRuleHandler) handleGetNotificationRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var filter platform.NotificationRuleFilter
+	filter.ID, filter.OrganizationID, filter.Organization, err = decodeOrgResourceFilter(r.URL.Query())
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	rules, _, err := h.NotificationRuleService.FindNotificationRules(ctx, filter, *opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetNotificationRulesResponse(rules, filter, *opts)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationRuleHandler) handleGetNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	nr, err := h.NotificationRuleService.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationRuleResponse(nr)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationRuleHandler) handlePostNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	nr := &platform.NotificationRule{}
+	if err := json.NewDecoder(r.Body).Decode(nr); err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Msg: "malformed json", Err: err}, w)
+		return
+	}
+	if err := nr.Valid(); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotificationRuleService.CreateNotificationRule(ctx, nr); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newNotificationRuleResponse(nr)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationRuleHandler) handlePatchNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var upd platform.NotificationRuleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Msg: "malformed json", Err: err}, w)
+		return
+	}
+	if err := upd.Valid(); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	nr, err := h.NotificationRuleService.UpdateNotificationRule(ctx, id, upd)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationRuleResponse(nr)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationRuleHandler) handleDeleteNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotificationRuleService.DeleteNotificationRule(ctx, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NotificationEndpointHandler is the handler for the notification endpoint service
+type NotificationEndpointHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	NotificationEndpointService platform.NotificationEndpointService
+}
+
+// NewNotificationEndpointHandler creates a new NotificationEndpointHandler
+func NewNotificationEndpointHandler() *NotificationEndpointHandler {
+	h := &NotificationEndpointHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", notificationEndpointsPath)
+
+	h.HandlerFunc("GET", notificationEndpointsPath, h.handleGetNotificationEndpoints)
+	h.HandlerFunc("POST", notificationEndpointsPath, h.handlePostNotificationEndpoint)
+	h.HandlerFunc("GET", entityPath, h.handleGetNotificationEndpoint)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchNotificationEndpoint)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteNotificationEndpoint)
+
+	return h
+}
+
+type notificationEndpointLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+}
+
+type notificationEndpointResponse struct {
+	*platform.NotificationEndpoint
+	Links notificationEndpointLinks `json:"links"`
+}
+
+func newNotificationEndpointResponse(e *platform.NotificationEndpoint) notificationEndpointResponse {
+	return notificationEndpointResponse{
+		NotificationEndpoint: e,
+		Links: notificationEndpointLinks{
+			Self: fmt.Sprintf("/api/v2/notificationEndpoints/%s", e.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", e.OrganizationID),
+		},
+	}
+}
+
+type getNotificationEndpointsResponse struct {
+	NotificationEndpoints []notificationEndpointResponse `json:"notificationEndpoints"`
+	Links                 *platform.PagingLinks          `json:"links"`
+}
+
+func (r getNotificationEndpointsResponse) ToPlatform() []*platform.NotificationEndpoint {
+	endpoints := make([]*platform.NotificationEndpoint, len(r.NotificationEndpoints))
+	for i := range r.NotificationEndpoints {
+		endpoints[i] = r.NotificationEndpoints[i].NotificationEndpoint
+	}
+	return endpoints
+}
+
+func newGetNotificationEndpointsResponse(endpoints []*platform.NotificationEndpoint, f platform.NotificationEndpointFilter, opts platform.FindOptions) getNotificationEndpointsResponse {
+	resp := getNotificationEndpointsResponse{
+		NotificationEndpoints: make([]notificationEndpointResponse, 0, len(endpoints)),
+		Links:                 newPagingLinks(notificationEndpointsPath, opts, f, len(endpoints)),
+	}
+	for _, e := range endpoints {
+		resp.NotificationEndpoints = append(resp.NotificationEndpoints, newNotificationEndpointResponse(e))
+	}
+	return resp
+}
+
+func (h *NotificationEndpointHandler) handleGetNotificationEndpoints(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var filter platform.NotificationEndpointFilter
+	filter.ID, filter.OrganizationID, filter.Organization, err = decodeOrgResourceFilter(r.URL.Query())
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	endpoints, _, err := h.NotificationEndpointService.FindNotificationEndpoints(ctx, filter, *opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetNotificationEndpointsResponse(endpoints, filter, *opts)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationEndpointHandler) handleGetNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	e, err := h.NotificationEndpointService.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationEndpointResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationEndpointHandler) handlePostNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	e := &platform.NotificationEndpoint{}
+	if err := json.NewDecoder(r.Body).Decode(e); err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Msg: "malformed json", Err: err}, w)
+		return
+	}
+	if err := e.Valid(); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotificationEndpointService.CreateNotificationEndpoint(ctx, e); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newNotificationEndpointResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationEndpointHandler) handlePatchNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var upd platform.NotificationEndpointUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Msg: "malformed json", Err: err}, w)
+		return
+	}
+	if err := upd.Valid(); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	e, err := h.NotificationEndpointService.UpdateNotificationEndpoint(ctx, id, upd)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationEndpointResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationEndpointHandler) handleDeleteNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestResourceID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotificationEndpointService.DeleteNotificationEndpoint(ctx, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NotificationRuleService is a notification rule service over HTTP to the influxdb server
+type NotificationRuleService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.NotificationRuleService = (*NotificationRuleService)(nil)
+
+// FindNotificationRuleByID returns a single notification rule by ID.
+func (s *NotificationRuleService) FindNotificationRuleByID(ctx context.Context, id platform.ID) (*platform.NotificationRule, error) {
+	var nr notificationRuleResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "GET", path.Join(notificationRulesPath, id.String()), nil, nil, &nr); err != nil {
+		return nil, err
+	}
+	return nr.NotificationRule, nil
+}
+
+// FindNotificationRules returns a list of notification rules that match filter.
+func (s *NotificationRuleService) FindNotificationRules(ctx context.Context, filter platform.NotificationRuleFilter, opt ...platform.FindOptions) ([]*platform.NotificationRule, int, error) {
+	var rs getNotificationRulesResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "GET", notificationRulesPath, filter.QueryParams(), nil, &rs); err != nil {
+		return nil, 0, err
+	}
+	rules := rs.ToPlatform()
+	return rules, len(rules), nil
+}
+
+// CreateNotificationRule creates a new notification rule and sets r.ID with the new identifier.
+func (s *NotificationRuleService) CreateNotificationRule(ctx context.Context, r *platform.NotificationRule) error {
+	var nr notificationRuleResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "POST", notificationRulesPath, nil, r, &nr); err != nil {
+		return err
+	}
+	*r = *nr.NotificationRule
+	return nil
+}
+
+// UpdateNotificationRule updates a single notification rule with changeset.
+func (s *NotificationRuleService) UpdateNotificationRule(ctx context.Context, id platform.ID, upd platform.NotificationRuleUpdate) (*platform.NotificationRule, error) {
+	var nr notificationRuleResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "PATCH", path.Join(notificationRulesPath, id.String()), nil, upd, &nr); err != nil {
+		return nil, err
+	}
+	return nr.NotificationRule, nil
+}
+
+// DeleteNotificationRule removes a notification rule by ID.
+func (s *NotificationRuleService) DeleteNotificationRule(ctx context.Context, id platform.ID) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "DELETE", path.Join(notificationRulesPath, id.String()), nil, nil, nil)
+}
+
+// NotificationEndpointService is a notification endpoint service over HTTP to the influxdb server
+type NotificationEndpointService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.NotificationEndpointService = (*NotificationEndpointService)(nil)
+
+// FindNotificationEndpointByID returns a single notification endpoint by ID.
+func (s *NotificationEndpointService) FindNotificationEndpointByID(ctx context.Context, id platform.ID) (*platform.NotificationEndpoint, error) {
+	var er notificationEndpointResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "GET", path.Join(notificationEndpointsPath, id.String()), nil, nil, &er); err != nil {
+		return nil, err
+	}
+	return er.NotificationEndpoint, nil
+}
+
+// FindNotificationEndpoints returns a list of notification endpoints that match filter.
+func (s *NotificationEndpointService) FindNotificationEndpoints(ctx context.Context, filter platform.NotificationEndpointFilter, opt ...platform.FindOptions) ([]*platform.NotificationEndpoint, int, error) {
+	var es getNotificationEndpointsResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "GET", notificationEndpointsPath, filter.QueryParams(), nil, &es); err != nil {
+		return nil, 0, err
+	}
+	endpoints := es.ToPlatform()
+	return endpoints, len(endpoints), nil
+}
+
+// CreateNotificationEndpoint creates a new notification endpoint and sets e.ID with the new identifier.
+func (s *NotificationEndpointService) CreateNotificationEndpoint(ctx context.Context, e *platform.NotificationEndpoint) error {
+	var er notificationEndpointResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "POST", notificationEndpointsPath, nil, e, &er); err != nil {
+		return err
+	}
+	*e = *er.NotificationEndpoint
+	return nil
+}
+
+// UpdateNotificationEndpoint updates a single notification endpoint with changeset.
+func (s *NotificationEndpointService) UpdateNotificationEndpoint(ctx context.Context, id platform.ID, upd platform.NotificationEndpointUpdate) (*platform.NotificationEndpoint, error) {
+	var er notificationEndpointResponse
+	if err := doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "PATCH", path.Join(notificationEndpointsPath, id.String()), nil, upd, &er); err != nil {
+		return nil, err
+	}
+	return er.NotificationEndpoint, nil
+}
+
+// DeleteNotificationEndpoint removes a notification endpoint by ID.
+func (s *NotificationEndpointService) DeleteNotificationEndpoint(ctx context.Context, id platform.ID) error {
+	return doJSONRequest(ctx, s.Addr, s.Token, s.InsecureSkipVerify, "DELETE", path.Join(notificationEndpointsPath, id.String()), nil, nil, nil)
+}
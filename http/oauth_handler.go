@@ -0,0 +1,215 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
+	"github.com/influxdata/influxdb/oauth"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	oauthPath         = "/api/v2/oauth"
+	oauthLoginPath    = "/api/v2/oauth/:provider/login"
+	oauthCallbackPath = "/api/v2/oauth/:provider/callback"
+
+	// oauthFailurePath is the page browsers are sent to when signing in fails.
+	oauthFailurePath = "/signin"
+)
+
+// OAuthProvisioner provisions the users that sign in with an OAuth2 provider.
+type OAuthProvisioner interface {
+	// Provision returns the user identified by subject at provider, creating
+	// it if it does not exist.  groups are the comma separated groups of the
+	// user reported by the provider.
+	Provision(ctx context.Context, provider, subject, groups string) (*platform.User, error)
+}
+
+// OAuthBackend is all services and associated parameters required to construct
+// the OAuthHandler.
+type OAuthBackend struct {
+	Logger *zap.Logger
+
+	OAuthProviders   []oauth2.Provider
+	OAuthTokenizer   oauth2.Tokenizer
+	OAuthUseIDToken  bool
+	OAuthProvisioner OAuthProvisioner
+	SessionService   platform.SessionService
+}
+
+// NewOAuthBackend returns a new instance of OAuthBackend.
+func NewOAuthBackend(b *APIBackend) *OAuthBackend {
+	return &OAuthBackend{
+		Logger: b.Logger.With(zap.String("handler", "oauth")),
+
+		OAuthProviders:   b.OAuthProviders,
+		OAuthTokenizer:   b.OAuthTokenizer,
+		OAuthUseIDToken:  b.OAuthUseIDToken,
+		OAuthProvisioner: b.OAuthProvisioner,
+		SessionService:   b.SessionService,
+	}
+}
+
+// OAuthHandler signs users in with OAuth2 providers.  Users that sign in are
+// provisioned and given a session, as with /api/v2/signin.
+type OAuthHandler struct {
+	*httprouter.Router
+	Logger *zap.Logger
+
+	providers []oauth2.Provider
+	muxes     map[string]oauth2.Mux
+}
+
+// NewOAuthHandler returns a new instance of OAuthHandler.
+func NewOAuthHandler(b *OAuthBackend) *OAuthHandler {
+	h := &OAuthHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		providers: b.OAuthProviders,
+		muxes:     make(map[string]oauth2.Mux),
+	}
+
+	auth := &oauthAuthenticator{
+		provisioner: b.OAuthProvisioner,
+		sessions:    b.SessionService,
+	}
+	logger := oauth.NewLogger(b.Logger)
+	for _, p := range b.OAuthProviders {
+		mux := oauth2.NewAuthMux(p, auth, b.OAuthTokenizer, "", logger, b.OAuthUseIDToken)
+		mux.FailureURL = oauthFailurePath
+		h.muxes[p.Name()] = mux
+	}
+
+	h.HandlerFunc("GET", oauthPath, h.handleGetProviders)
+	h.HandlerFunc("GET", oauthLoginPath, h.handleLogin)
+	h.HandlerFunc("GET", oauthCallbackPath, h.handleCallback)
+	return h
+}
+
+type oauthProviderResponse struct {
+	Name  string            `json:"name"`
+	Links map[string]string `json:"links"`
+}
+
+type oauthProvidersResponse struct {
+	Links     map[string]string       `json:"links"`
+	Providers []oauthProviderResponse `json:"providers"`
+}
+
+// handleGetProviders is the HTTP handler for the GET /api/v2/oauth route.
+func (h *OAuthHandler) handleGetProviders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res := oauthProvidersResponse{
+		Links: map[string]string{
+			"self": oauthPath,
+		},
+		Providers: make([]oauthProviderResponse, 0, len(h.providers)),
+	}
+	for _, p := range h.providers {
+		res.Providers = append(res.Providers, oauthProviderResponse{
+			Name: p.Name(),
+			Links: map[string]string{
+				"login": fmt.Sprintf("%s/%s/login", oauthPath, p.Name()),
+			},
+		})
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleLogin is the HTTP handler for the GET /api/v2/oauth/:provider/login route.
+// It redirects to the provider.
+func (h *OAuthHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
+	mux, err := h.mux(r)
+	if err != nil {
+		EncodeError(r.Context(), err, w)
+		return
+	}
+	mux.Login().ServeHTTP(w, r)
+}
+
+// handleCallback is the HTTP handler for the GET /api/v2/oauth/:provider/callback
+// route.  The provider redirects to it once the user signed in.
+func (h *OAuthHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
+	mux, err := h.mux(r)
+	if err != nil {
+		EncodeError(r.Context(), err, w)
+		return
+	}
+	mux.Callback().ServeHTTP(w, r)
+}
+
+func (h *OAuthHandler) mux(r *http.Request) (oauth2.Mux, error) {
+	name := httprouter.ParamsFromContext(r.Context()).ByName("provider")
+	mux, ok := h.muxes[name]
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  fmt.Sprintf("oauth provider %q not found", name),
+		}
+	}
+	return mux, nil
+}
+
+// oauthAuthenticator gives the users that signed in with a provider a session.
+// Sessions are validated by the AuthenticationHandler, so only Authorize is
+// used by the providers.
+type oauthAuthenticator struct {
+	provisioner OAuthProvisioner
+	sessions    platform.SessionService
+}
+
+var _ oauth2.Authenticator = (*oauthAuthenticator)(nil)
+
+// Authorize provisions the user of p and sets the cookie of a new session.
+// The issuer of p is the name of the provider.
+func (a *oauthAuthenticator) Authorize(ctx context.Context, w http.ResponseWriter, p oauth2.Principal) error {
+	u, err := a.provisioner.Provision(ctx, p.Issuer, p.Subject, p.Group)
+	if err != nil {
+		return err
+	}
+
+	s, err := a.sessions.CreateSession(ctx, u.Name)
+	if err != nil {
+		return err
+	}
+
+	// The callback is not beneath the API routes that read the cookie, so
+	// the path of the cookie is set explicitly.
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieSessionName,
+		Value:    s.Key,
+		Path:     "/api/v2",
+		HttpOnly: true,
+	})
+	return nil
+}
+
+// Validate is not supported; sessions are validated by their key.
+func (a *oauthAuthenticator) Validate(context.Context, *http.Request) (oauth2.Principal, error) {
+	return oauth2.Principal{}, oauth2.ErrAuthentication
+}
+
+// Extend is not supported; sessions are renewed by the SessionService.
+func (a *oauthAuthenticator) Extend(context.Context, http.ResponseWriter, oauth2.Principal) (oauth2.Principal, error) {
+	return oauth2.Principal{}, oauth2.ErrAuthentication
+}
+
+// Expire removes the session cookie.  The session itself is expired by
+// /api/v2/signout.
+func (a *oauthAuthenticator) Expire(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   cookieSessionName,
+		Path:   "/api/v2",
+		MaxAge: -1,
+	})
+}
@@ -0,0 +1,171 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
+	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/oauth"
+	"go.uber.org/zap"
+)
+
+// newOIDCStub returns a server that acts as the token and userinfo endpoints
+// of an OpenID Connect provider, signing in the user with the email address.
+func newOIDCStub(email string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "code123" {
+			http.Error(w, "invalid code", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access123",
+			"token_type":   "bearer",
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access123" {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"email": email})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestOAuthHandler(t *testing.T) {
+	ctx := context.Background()
+	stub := newOIDCStub("jane@example.com")
+	defer stub.Close()
+
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &platform.Organization{Name: "ops"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	var sessionUser string
+	sessions := mock.NewSessionService()
+	sessions.CreateSessionFn = func(ctx context.Context, user string) (*platform.Session, error) {
+		sessionUser = user
+		return &platform.Session{Key: "session123"}, nil
+	}
+
+	h := platformhttp.NewOAuthHandler(&platformhttp.OAuthBackend{
+		Logger: zap.NewNop(),
+		OAuthProviders: []oauth2.Provider{
+			&oauth2.Generic{
+				PageName:     "sso",
+				ClientID:     "influxd",
+				ClientSecret: "secret",
+				RedirectURL:  "http://influxd.example.com/api/v2/oauth/sso/callback",
+				AuthURL:      stub.URL + "/authorize",
+				TokenURL:     stub.URL + "/token",
+				APIURL:       stub.URL + "/userinfo",
+				APIKey:       "email",
+				Logger:       oauth.NewLogger(zap.NewNop()),
+			},
+		},
+		OAuthTokenizer: oauth2.NewJWT("token-secret", ""),
+		OAuthProvisioner: &oauth.Provisioner{
+			UserService:                svc,
+			OrganizationService:        svc,
+			UserResourceMappingService: svc,
+			OAuthIdentityService:       svc,
+			Mappings: []oauth.Mapping{
+				{Domain: "example.com", Organization: "ops", Role: platform.Member},
+			},
+		},
+		SessionService: sessions,
+	})
+
+	// The providers are listed.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/oauth", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d listing providers", w.Code)
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal([]byte(`{
+  "links": {"self": "/api/v2/oauth"},
+  "providers": [
+    {"name": "sso", "links": {"login": "/api/v2/oauth/sso/login"}}
+  ]
+}`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got providers %s", w.Body.String())
+	}
+
+	// Login redirects to the provider with a state that the callback accepts.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/oauth/sso/login", nil))
+	if w.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("got status %d for login, want %d", w.Code, http.StatusTemporaryRedirect)
+	}
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loc.Scheme + "://" + loc.Host + loc.Path; got != stub.URL+"/authorize" {
+		t.Fatalf("got redirect to %s, want the provider", got)
+	}
+	state := loc.Query().Get("state")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/oauth/sso/callback?code=code123&state="+url.QueryEscape(state), nil))
+	if w.Code != http.StatusTemporaryRedirect || w.Header().Get("Location") != "/" {
+		t.Fatalf("got status %d and redirect to %q for callback", w.Code, w.Header().Get("Location"))
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session" || cookies[0].Value != "session123" {
+		t.Fatalf("unexpected cookies %v", cookies)
+	}
+	if sessionUser != "sso:jane@example.com" {
+		t.Errorf("got session for %q, want sso:jane@example.com", sessionUser)
+	}
+
+	// The user was provisioned as a member of the mapped organization.
+	u, err := svc.FindUser(ctx, platform.UserFilter{Name: &sessionUser})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{UserID: u.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].ResourceID != org.ID || ms[0].UserType != platform.Member {
+		t.Errorf("unexpected user resource mappings %+v", ms)
+	}
+
+	// A forged state is rejected.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/oauth/sso/callback?code=code123&state=forged", nil))
+	if w.Code != http.StatusTemporaryRedirect || w.Header().Get("Location") != "/signin" {
+		t.Errorf("got status %d and redirect to %q for a forged state", w.Code, w.Header().Get("Location"))
+	}
+
+	// Unknown providers are not found.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/oauth/github/login", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d for an unknown provider, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
 )
@@ -34,11 +35,11 @@ func initOrganizationService(f platformtesting.OrganizationFields, t *testing.T)
 	server := httptest.NewServer(handler)
 	client := OrganizationService{
 		Addr:     server.URL,
-		OpPrefix: inmem.OpPrefix,
+		OpPrefix: kv.OpPrefix,
 	}
 	done := server.Close
 
-	return &client, inmem.OpPrefix, done
+	return &client, kv.OpPrefix, done
 }
 func TestOrganizationService(t *testing.T) {
 	t.Parallel()
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -21,6 +23,8 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/errors"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/dialect"
+	"github.com/influxdata/influxdb/query/macro"
 	"github.com/influxdata/influxql"
 )
 
@@ -32,11 +36,15 @@ type QueryRequest struct {
 	Type    string       `json:"type"`
 	Dialect QueryDialect `json:"dialect"`
 
+	// Macros are injected in the query as properties of the v option.
+	Macros []*platform.Macro `json:"macros,omitempty"`
+
 	Org *platform.Organization `json:"-"`
 }
 
 // QueryDialect is the formatting options for the query response.
 type QueryDialect struct {
+	Type           string   `json:"type,omitempty"`
 	Header         *bool    `json:"header"`
 	Delimiter      string   `json:"delimiter"`
 	CommentPrefix  string   `json:"commentPrefix"`
@@ -72,6 +80,16 @@ func (r QueryRequest) Validate() error {
 		return fmt.Errorf(`unknown query type: %s`, r.Type)
 	}
 
+	if len(r.Macros) > 0 && r.Query == "" && r.AST == nil {
+		return errors.New(`macros require either query or AST`)
+	}
+
+	switch r.Dialect.Type {
+	case "", "csv", dialect.JSONDialectType, dialect.ArrowDialectType, dialect.LineProtocolDialectType:
+	default:
+		return fmt.Errorf(`unknown dialect type: %s`, r.Dialect.Type)
+	}
+
 	if len(r.Dialect.CommentPrefix) > 1 {
 		return fmt.Errorf("invalid dialect comment prefix: must be length 0 or 1")
 	}
@@ -245,6 +263,25 @@ func toSpec(p *ast.Package, now func() time.Time) (*flux.Spec, error) {
 	return flux.ToSpec(sideEffects, nowTime.Time().Time())
 }
 
+// expandMacros injects the values of the macros of the request in its query.
+func (r *QueryRequest) expandMacros(ctx context.Context, e *macro.Expander, auth *platform.Authorization) error {
+	if len(r.Macros) == 0 {
+		return nil
+	}
+
+	values, err := e.Values(ctx, r.Org.ID, auth, r.Macros)
+	if err != nil {
+		return err
+	}
+
+	// Query is preferred over AST
+	if r.Query != "" {
+		r.Query, err = macro.InjectQuery(r.Query, values)
+		return err
+	}
+	return macro.InjectAST(r.AST, values)
+}
+
 // ProxyRequest returns a request to proxy from the flux.
 func (r QueryRequest) ProxyRequest() (*query.ProxyRequest, error) {
 	return r.proxyRequest(time.Now)
@@ -284,18 +321,30 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 
 	// TODO(nathanielc): Use commentPrefix and dateTimeFormat
 	// once they are supported.
-	return &query.ProxyRequest{
-		Request: query.Request{
-			OrganizationID: r.Org.ID,
-			Compiler:       compiler,
-		},
-		Dialect: &csv.Dialect{
+	var d flux.Dialect
+	switch r.Dialect.Type {
+	case dialect.JSONDialectType:
+		d = new(dialect.JSONDialect)
+	case dialect.ArrowDialectType:
+		d = new(dialect.ArrowDialect)
+	case dialect.LineProtocolDialectType:
+		d = new(dialect.LineProtocolDialect)
+	default:
+		d = &csv.Dialect{
 			ResultEncoderConfig: csv.ResultEncoderConfig{
 				NoHeader:    noHeader,
 				Delimiter:   delimiter,
 				Annotations: r.Dialect.Annotations,
 			},
+		}
+	}
+
+	return &query.ProxyRequest{
+		Request: query.Request{
+			OrganizationID: r.Org.ID,
+			Compiler:       compiler,
 		},
+		Dialect: d,
 	}, nil
 }
 
@@ -321,6 +370,8 @@ func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error
 		qr.Dialect.CommentPrefix = "#"
 		qr.Dialect.DateTimeFormat = "RFC3339"
 		qr.Dialect.Annotations = d.ResultEncoderConfig.Annotations
+	case *dialect.JSONDialect, *dialect.ArrowDialect, *dialect.LineProtocolDialect:
+		qr.Dialect.Type = string(d.DialectType())
 	default:
 		return nil, fmt.Errorf("unsupported dialect %T", d)
 	}
@@ -344,6 +395,10 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc platform.Organ
 		}
 	}
 
+	if req.Dialect.Type == "" {
+		req.Dialect.Type = dialectTypeFromAccept(r.Header.Get("Accept"))
+	}
+
 	req = req.WithDefaults()
 	err := req.Validate()
 	if err != nil {
@@ -354,18 +409,45 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc platform.Organ
 	return &req, err
 }
 
-func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth platform.Authorizer, svc platform.OrganizationService) (*query.ProxyRequest, error) {
+// dialectTypeFromAccept returns the dialect type of the first media type in the
+// Accept header that has a dialect. It returns an empty string, meaning CSV,
+// when no media type matches.
+func dialectTypeFromAccept(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mt {
+		case "text/csv", "application/csv":
+			return "csv"
+		case dialect.JSONMediaType:
+			return dialect.JSONDialectType
+		case dialect.ArrowMediaType, "application/vnd.apache.arrow.stream":
+			return dialect.ArrowDialectType
+		case dialect.LineProtocolMediaType:
+			return dialect.LineProtocolDialectType
+		}
+	}
+	return ""
+}
+
+func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth platform.Authorizer, svc platform.OrganizationService, macros *macro.Expander) (*query.ProxyRequest, error) {
 	req, err := decodeQueryRequest(ctx, r, svc)
 	if err != nil {
 		return nil, err
 	}
 
+	a, ok := auth.(*platform.Authorization)
+	if err := req.expandMacros(ctx, macros, a); err != nil {
+		return nil, err
+	}
+
 	pr, err := req.ProxyRequest()
 	if err != nil {
 		return nil, err
 	}
 
-	a, ok := auth.(*platform.Authorization)
 	if !ok {
 		// TODO(desa): this should go away once we're using platform.Authorizers everywhere.
 		return pr, platform.ErrAuthorizerNotSupported
@@ -19,6 +19,7 @@ import (
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/errors"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/macro"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -37,6 +38,10 @@ type FluxHandler struct {
 	Now                 func() time.Time
 	OrganizationService platform.OrganizationService
 	ProxyQueryService   query.ProxyQueryService
+
+	// MacroService and QueryService are used to expand the macros of queries.
+	MacroService platform.MacroService
+	QueryService query.QueryService
 }
 
 // NewFluxHandler returns a new handler at /api/v2/query for flux queries.
@@ -66,7 +71,8 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req, err := decodeProxyQueryRequest(ctx, r, a, h.OrganizationService)
+	macros := macro.NewExpander(h.MacroService, h.QueryService)
+	req, err := decodeProxyQueryRequest(ctx, r, a, h.OrganizationService, macros)
 	if err != nil && err != platform.ErrAuthorizerNotSupported {
 		EncodeError(ctx, err, w)
 		return
@@ -271,6 +277,11 @@ func (s *FluxService) Query(ctx context.Context, w io.Writer, r *query.ProxyRequ
 	if err != nil {
 		return 0, err
 	}
+	if r.Request.OrganizationID.Valid() {
+		params := url.Values{}
+		params.Set(OrgID, r.Request.OrganizationID.String())
+		u.RawQuery = params.Encode()
+	}
 
 	qreq, err := QueryRequestFromProxyRequest(r)
 	if err != nil {
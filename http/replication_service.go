@@ -0,0 +1,319 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/replication"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	replicationWALPath      = "/api/v2/replication/wal"
+	replicationSnapshotPath = "/api/v2/replication/snapshot"
+	replicationMetadataPath = "/api/v2/replication/metadata"
+)
+
+// Headers describing the WAL segments and snapshots sent to followers.
+const (
+	WALSegmentHeader       = "X-Influx-WAL-Segment"
+	WALSegmentClosedHeader = "X-Influx-WAL-Segment-Closed"
+	WALSegmentSizeHeader   = "X-Influx-WAL-Segment-Size"
+	WALLagHeader           = "X-Influx-WAL-Lag"
+)
+
+// ReplicationHandler is the handler serving the WAL and snapshots of the storage
+// engine, and snapshots of the metadata store, to replication followers.
+type ReplicationHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	ReplicationService storage.ReplicationService
+
+	// MetadataStore is the metadata store served to followers.  Snapshots of the
+	// metadata are not served if it is nil.
+	MetadataStore replication.MetadataStore
+}
+
+// NewReplicationHandler creates a new ReplicationHandler.
+func NewReplicationHandler() *ReplicationHandler {
+	h := &ReplicationHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("GET", replicationWALPath, h.handleGetWAL)
+	h.HandlerFunc("GET", replicationSnapshotPath, h.handleGetSnapshot)
+	h.HandlerFunc("GET", replicationMetadataPath, h.handleGetMetadata)
+
+	return h
+}
+
+type getWALRequest struct {
+	pos replication.Position
+}
+
+func decodeGetWALRequest(ctx context.Context, r *http.Request) (*getWALRequest, error) {
+	qp := r.URL.Query()
+	req := &getWALRequest{}
+
+	segment, err := strconv.Atoi(qp.Get("segment"))
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "segment must be an integer",
+			Err:  err,
+		}
+	}
+	req.pos.Segment = segment
+
+	if s := qp.Get("offset"); s != "" {
+		offset, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "offset must be a positive integer",
+			}
+		}
+		req.pos.Offset = offset
+	}
+	return req, nil
+}
+
+func replicationError(err error) error {
+	switch err {
+	case tsm1.ErrWALSegmentNotFound:
+		return &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  err.Error(),
+		}
+	case storage.ErrWALDisabled, storage.ErrReplicationDisabled, storage.ErrEngineClosed:
+		return &platform.Error{
+			Code: platform.EUnavailable,
+			Msg:  err.Error(),
+		}
+	}
+	return err
+}
+
+// handleGetWAL is the HTTP handler for the GET /api/v2/replication/wal route.
+// It returns the content of a WAL segment from an offset.
+func (h *ReplicationHandler) handleGetWAL(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeStorage(ctx, platform.ReadAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeGetWALRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	segment, err := h.ReplicationService.OpenWALSegment(req.pos.Segment)
+	if err != nil {
+		EncodeError(ctx, replicationError(err), w)
+		return
+	}
+	defer segment.Close()
+
+	lag, err := h.ReplicationService.WALSizeFrom(req.pos.Segment, req.pos.Offset)
+	if err != nil {
+		EncodeError(ctx, replicationError(err), w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set(WALSegmentClosedHeader, strconv.FormatBool(segment.Closed))
+	w.Header().Set(WALSegmentSizeHeader, strconv.FormatInt(segment.Size, 10))
+	w.Header().Set(WALLagHeader, strconv.FormatInt(lag, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, segment.NewReader(req.pos.Offset)); err != nil {
+		h.Logger.Info("Error writing WAL segment", zap.Error(err))
+	}
+}
+
+// handleGetSnapshot is the HTTP handler for the GET /api/v2/replication/snapshot route.
+// It returns a snapshot of the TSM files as a tar archive.
+func (h *ReplicationHandler) handleGetSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeStorage(ctx, platform.ReadAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	snapshot, err := h.ReplicationService.CreateReplicationSnapshot()
+	if err != nil {
+		EncodeError(ctx, replicationError(err), w)
+		return
+	}
+	defer snapshot.Close()
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set(WALSegmentHeader, strconv.Itoa(snapshot.Segment))
+	w.WriteHeader(http.StatusOK)
+	if _, err := snapshot.WriteTo(w); err != nil {
+		h.Logger.Info("Error writing snapshot", zap.Error(err))
+	}
+}
+
+// handleGetMetadata is the HTTP handler for the GET /api/v2/replication/metadata route.
+// It returns a snapshot of the metadata store, including the authorizations and
+// the password hashes of the users, so it requires the write permission on
+// storage, which only operators have.
+func (h *ReplicationHandler) handleGetMetadata(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeStorage(ctx, platform.WriteAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if h.MetadataStore == nil {
+		EncodeError(ctx, replicationError(storage.ErrReplicationDisabled), w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	if err := h.MetadataStore.Backup(w); err != nil {
+		h.Logger.Info("Error writing metadata snapshot", zap.Error(err))
+	}
+}
+
+// ReplicationService connects to Influx via HTTP using tokens to follow the storage
+// engine of a leader.
+type ReplicationService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ replication.Leader = (*ReplicationService)(nil)
+
+func (s *ReplicationService) get(ctx context.Context, p string, qp url.Values) (*http.Response, error) {
+	u, err := newURL(s.Addr, p)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = qp.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckError(resp, true); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ReadWAL reads the WAL of the leader from a position.
+func (s *ReplicationService) ReadWAL(ctx context.Context, pos replication.Position) (*replication.WALSegment, error) {
+	qp := url.Values{}
+	qp.Set("segment", strconv.Itoa(pos.Segment))
+	qp.Set("offset", strconv.FormatInt(pos.Offset, 10))
+
+	resp, err := s.get(ctx, replicationWALPath, qp)
+	if platform.ErrorCode(err) == platform.ENotFound {
+		return nil, replication.ErrSnapshotRequired
+	} else if err != nil {
+		return nil, err
+	}
+
+	segment := &replication.WALSegment{ReadCloser: resp.Body}
+	if segment.Closed, err = strconv.ParseBool(resp.Header.Get(WALSegmentClosedHeader)); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("invalid %s header: %v", WALSegmentClosedHeader, err)
+	}
+	if segment.Size, err = strconv.ParseInt(resp.Header.Get(WALSegmentSizeHeader), 10, 64); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("invalid %s header: %v", WALSegmentSizeHeader, err)
+	}
+	if segment.Lag, err = strconv.ParseInt(resp.Header.Get(WALLagHeader), 10, 64); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("invalid %s header: %v", WALLagHeader, err)
+	}
+	return segment, nil
+}
+
+// Snapshot returns a snapshot of the TSM files of the leader.
+func (s *ReplicationService) Snapshot(ctx context.Context) (*replication.Snapshot, error) {
+	resp, err := s.get(ctx, replicationSnapshotPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	segment, err := strconv.Atoi(resp.Header.Get(WALSegmentHeader))
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("invalid %s header: %v", WALSegmentHeader, err)
+	}
+	return &replication.Snapshot{ReadCloser: resp.Body, Segment: segment}, nil
+}
+
+// Metadata returns a snapshot of the metadata store of the leader.
+func (s *ReplicationService) Metadata(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := s.get(ctx, replicationMetadataPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// ReadOnlyHandler serves the requests of a replication follower.  The metadata
+// and the data of a follower are replaced by those of its leader, so requests
+// other than reads, queries and sign in are rejected.
+type ReadOnlyHandler struct {
+	http.Handler
+}
+
+// readOnlyPaths are the paths of the requests that only read, whatever their
+// method.  Signing in and out changes the sessions, which are those of the
+// follower, not of its leader.
+var readOnlyPaths = map[string]bool{
+	"/api/v2/signin":        true,
+	"/api/v2/signout":       true,
+	fluxPath:                true,
+	queryPath:               true,
+	proxyQueryPath:          true,
+	"/api/v2/query/ast":     true,
+	"/api/v2/query/analyze": true,
+	"/api/v2/query/spec":    true,
+}
+
+// ServeHTTP rejects requests that change metadata or write points with a
+// forbidden error.
+func (h ReadOnlyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET", "HEAD", "OPTIONS":
+	default:
+		if !readOnlyPaths[r.URL.Path] {
+			EncodeError(r.Context(), replication.ErrReadOnly, w)
+			return
+		}
+	}
+	h.Handler.ServeHTTP(w, r)
+}
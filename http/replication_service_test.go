@@ -0,0 +1,173 @@
+package http
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/replication"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestReplicationService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication_service_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := storage.NewConfig()
+	config.WAL.Replication = true
+	engine := storage.NewEngine(dir, config)
+	if err := engine.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	pt := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "server"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	points, err := tsdb.ExplodePoints(platform.ID(1), platform.ID(2), []models.Point{pt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewReplicationHandler()
+	h.ReplicationService = engine
+	permissions := []platform.Permission{
+		{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.StorageResourceType}},
+		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.StorageResourceType}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: permissions,
+		}))
+		h.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := &ReplicationService{Addr: server.URL}
+
+	segment, err := client.ReadWAL(ctx, replication.Position{Segment: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer segment.Close()
+	if segment.Closed || segment.Size == 0 || segment.Lag != segment.Size {
+		t.Fatalf("unexpected segment: closed %v, size %d, lag %d", segment.Closed, segment.Size, segment.Lag)
+	}
+
+	r := tsm1.NewWALSegmentReader(segment)
+	if !r.Next() {
+		t.Fatal("expected a WAL entry")
+	}
+	entry, err := r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := len(entry.(*tsm1.WriteWALEntry).Values), 1; got != exp {
+		t.Fatalf("got %d keys, exp %d", got, exp)
+	}
+
+	if _, err := client.ReadWAL(ctx, replication.Position{Segment: 3}); err != replication.ErrSnapshotRequired {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot, err := client.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snapshot.Close()
+	if got, exp := snapshot.Segment, 2; got != exp {
+		t.Fatalf("got segment %d, exp %d", got, exp)
+	}
+	if _, err := tar.NewReader(snapshot).Next(); err != nil {
+		t.Fatalf("expected a TSM file in the snapshot: %v", err)
+	}
+
+	if _, err := client.Metadata(ctx); platform.ErrorCode(err) != platform.EUnavailable {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.MetadataStore = fakeMetadataStore("metadata")
+	metadata, err := client.Metadata(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer metadata.Close()
+	if b, err := ioutil.ReadAll(metadata); err != nil {
+		t.Fatal(err)
+	} else if got, exp := string(b), "metadata"; got != exp {
+		t.Fatalf("got metadata %q, exp %q", got, exp)
+	}
+
+	// The metadata include the tokens, so reading storage is not enough.
+	permissions = permissions[:1]
+	if _, err := client.Metadata(ctx); platform.ErrorCode(err) != platform.EUnauthorized {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	permissions = nil
+	if _, err := client.Snapshot(ctx); platform.ErrorCode(err) != platform.EUnauthorized {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Metadata(ctx); platform.ErrorCode(err) != platform.EUnauthorized {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReadOnlyHandler(t *testing.T) {
+	h := ReadOnlyHandler{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})}
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{method: "GET", path: "/api/v2/buckets", status: http.StatusNoContent},
+		{method: "POST", path: "/api/v2/query", status: http.StatusNoContent},
+		{method: "POST", path: "/api/v2/write", status: http.StatusForbidden},
+		{method: "POST", path: "/api/v2/buckets", status: http.StatusForbidden},
+		{method: "DELETE", path: "/api/v2/buckets/020f755c3c082000", status: http.StatusForbidden},
+		{method: "POST", path: "/api/v2/signin", status: http.StatusNoContent},
+		{method: "POST", path: "/api/v2/signout", status: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if got := w.Code; got != tt.status {
+			t.Errorf("%s %s: got status %d, exp %d", tt.method, tt.path, got, tt.status)
+		}
+	}
+}
+
+type fakeMetadataStore string
+
+func (s fakeMetadataStore) Backup(w io.Writer) error {
+	_, err := io.WriteString(w, string(s))
+	return err
+}
+
+func (s fakeMetadataStore) Restore(r io.Reader) error {
+	return nil
+}
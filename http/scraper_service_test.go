@@ -12,6 +12,7 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
 	"github.com/julienschmidt/httprouter"
@@ -777,11 +778,11 @@ func initScraperService(f platformtesting.TargetFields, t *testing.T) (platform.
 	server := httptest.NewServer(handler)
 	client := ScraperService{
 		Addr:     server.URL,
-		OpPrefix: inmem.OpPrefix,
+		OpPrefix: kv.OpPrefix,
 	}
 	done := server.Close
 
-	return &client, inmem.OpPrefix, done
+	return &client, kv.OpPrefix, done
 }
 
 func TestScraperService(t *testing.T) {
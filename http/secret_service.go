@@ -0,0 +1,129 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.SecretService = (*SecretService)(nil)
+
+// SecretService connects to Influx via HTTP using tokens to manage the
+// secrets of organizations.
+type SecretService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// LoadSecret is not supported over HTTP: the values of the secrets can not
+// be read back.
+func (s *SecretService) LoadSecret(ctx context.Context, orgID platform.ID, k string) (string, error) {
+	return "", &platform.Error{
+		Code: platform.EMethodNotAllowed,
+		Msg:  "secret values can not be read over HTTP",
+	}
+}
+
+// GetSecretKeys returns the keys of the secrets of the organization.
+func (s *SecretService) GetSecretKeys(ctx context.Context, orgID platform.ID) ([]string, error) {
+	url, err := newURL(s.Addr, secretsPath(orgID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var r secretsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	return r.Secrets, nil
+}
+
+// PutSecret stores the secret k of the organization.
+func (s *SecretService) PutSecret(ctx context.Context, orgID platform.ID, k string, v string) error {
+	return s.PatchSecrets(ctx, orgID, map[string]string{k: v})
+}
+
+// PutSecrets sets the secrets of the organization to m, deleting the other
+// secrets. Unlike the other implementations it is not atomic.
+func (s *SecretService) PutSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
+	ks, err := s.GetSecretKeys(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	var deleted []string
+	for _, k := range ks {
+		if _, ok := m[k]; !ok {
+			deleted = append(deleted, k)
+		}
+	}
+	if len(deleted) > 0 {
+		if err := s.DeleteSecret(ctx, orgID, deleted...); err != nil {
+			return err
+		}
+	}
+	return s.PatchSecrets(ctx, orgID, m)
+}
+
+// PatchSecrets sets the secrets m of the organization, keeping its other
+// secrets.
+func (s *SecretService) PatchSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
+	return s.do(ctx, "PATCH", secretsPath(orgID), m)
+}
+
+// DeleteSecret deletes the secrets ks of the organization.
+func (s *SecretService) DeleteSecret(ctx context.Context, orgID platform.ID, ks ...string) error {
+	return s.do(ctx, "POST", path.Join(secretsPath(orgID), "delete"), ks)
+}
+
+// do sends v as the JSON body of a request without response body.
+func (s *SecretService) do(ctx context.Context, method, p string, v interface{}) error {
+	url, err := newURL(s.Addr, p)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return CheckError(resp, true)
+}
+
+func secretsPath(orgID platform.ID) string {
+	return path.Join(organizationIDPath(orgID), "secrets")
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestSecretService(t *testing.T) {
+	svc := kv.NewService(inmem.NewKVStore())
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("failed to initialize kv service: %v", err)
+	}
+
+	handler := NewOrgHandler(mock.NewUserResourceMappingService(), mock.NewLabelService(), mock.NewUserService())
+	handler.SecretService = svc
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := SecretService{
+		Addr: server.URL,
+	}
+
+	orgID := platform.ID(1)
+	wantKeys := func(want ...string) {
+		t.Helper()
+		ks, err := client.GetSecretKeys(ctx, orgID)
+		if err != nil {
+			t.Fatalf("failed to get secret keys: %v", err)
+		}
+		if diff := cmp.Diff(ks, want); diff != "" {
+			t.Fatalf("secret keys are different -got/+want\ndiff %s", diff)
+		}
+	}
+
+	if err := client.PutSecret(ctx, orgID, "k1", "v1"); err != nil {
+		t.Fatalf("failed to put secret: %v", err)
+	}
+	if err := client.PatchSecrets(ctx, orgID, map[string]string{"k2": "v2", "k3": "v3"}); err != nil {
+		t.Fatalf("failed to patch secrets: %v", err)
+	}
+	wantKeys("k1", "k2", "k3")
+
+	if err := client.PutSecrets(ctx, orgID, map[string]string{"k2": "v4", "k4": "v4"}); err != nil {
+		t.Fatalf("failed to put secrets: %v", err)
+	}
+	wantKeys("k2", "k4")
+	if v, err := svc.LoadSecret(ctx, orgID, "k2"); err != nil || v != "v4" {
+		t.Fatalf("expected secret k2 to be v4, got %q, %v", v, err)
+	}
+
+	if err := client.DeleteSecret(ctx, orgID, "k2"); err != nil {
+		t.Fatalf("failed to delete secret: %v", err)
+	}
+	wantKeys("k4")
+
+	if _, err := client.LoadSecret(ctx, orgID, "k4"); platform.ErrorCode(err) != platform.EMethodNotAllowed {
+		t.Fatalf("expected secret values not to be readable, got %v", err)
+	}
+}
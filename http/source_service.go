@@ -497,6 +497,28 @@ type SourceService struct {
 	InsecureSkipVerify bool
 }
 
+var _ platform.SourceService = (*SourceService)(nil)
+
+// DefaultSource returns the default source.
+func (s *SourceService) DefaultSource(ctx context.Context) (*platform.Source, error) {
+	srcs, _, err := s.FindSources(ctx, platform.FindOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, src := range srcs {
+		if src.Default {
+			return src, nil
+		}
+	}
+
+	return nil, &platform.Error{
+		Code: platform.ENotFound,
+		Op:   platform.OpDefaultSource,
+		Msg:  "default source not found",
+	}
+}
+
 // FindSourceByID returns a single source by ID.
 func (s *SourceService) FindSourceByID(ctx context.Context, id platform.ID) (*platform.Source, error) {
 	u, err := newURL(s.Addr, sourceIDPath(id))
@@ -554,13 +576,15 @@ func (s *SourceService) FindSources(ctx context.Context, opt platform.FindOption
 		return nil, 0, err
 	}
 
-	var bs []*platform.Source
-	if err := json.NewDecoder(resp.Body).Decode(&bs); err != nil {
+	var r struct {
+		Sources []*platform.Source `json:"sources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
 		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
-	return bs, len(bs), nil
+	return r.Sources, len(r.Sources), nil
 }
 
 // CreateSource creates a new source and sets b.ID with the new identifier.
@@ -1,11 +1,17 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
 	"net/http"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/golang/gddo/httputil"
 	platform "github.com/influxdata/influxdb"
@@ -21,6 +27,7 @@ type TelegrafHandler struct {
 	Logger *zap.Logger
 
 	TelegrafService            platform.TelegrafConfigStore
+	TelegrafAgentService       platform.TelegrafAgentService
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
@@ -36,6 +43,9 @@ const (
 	telegrafsIDOwnersIDPath   = "/api/v2/telegrafs/:id/owners/:userID"
 	telegrafsIDLabelsPath     = "/api/v2/telegrafs/:id/labels"
 	telegrafsIDLabelsNamePath = "/api/v2/telegrafs/:id/labels/:name"
+	telegrafsIDAgentPath      = "/api/v2/telegrafs/:id/agent"
+	telegrafsIDAgentsPath     = "/api/v2/telegrafs/:id/agents"
+	telegrafsIDTokenPath      = "/api/v2/telegrafs/:id/token"
 )
 
 // NewTelegrafHandler returns a new instance of TelegrafHandler.
@@ -62,6 +72,9 @@ func NewTelegrafHandler(
 	h.HandlerFunc("GET", telegrafsIDPath, h.handleGetTelegraf)
 	h.HandlerFunc("DELETE", telegrafsIDPath, h.handleDeleteTelegraf)
 	h.HandlerFunc("PUT", telegrafsIDPath, h.handlePutTelegraf)
+	h.HandlerFunc("GET", telegrafsIDAgentPath, h.handleGetTelegrafAgent)
+	h.HandlerFunc("GET", telegrafsIDAgentsPath, h.handleGetTelegrafAgents)
+	h.HandlerFunc("POST", telegrafsIDTokenPath, h.handlePostTelegrafToken)
 
 	h.HandlerFunc("POST", telegrafsIDMembersPath, newPostMemberHandler(h.UserResourceMappingService, h.UserService, platform.TelegrafsResourceType, platform.Member))
 	h.HandlerFunc("GET", telegrafsIDMembersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.TelegrafsResourceType, platform.Member))
@@ -194,6 +207,168 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// handleGetTelegrafAgent is the HTTP handler for the GET /api/v2/telegrafs/:id/agent route.
+// It returns the toml of the config for an agent, with the token of the
+// config in the influxdb_v2 outputs without a token, and records the fetch.
+// Only agent tokens may fetch it, see isTelegrafAgent.
+func (h *TelegrafHandler) handleGetTelegrafAgent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetTelegrafRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if !isTelegrafAgent(auth, id) {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "the config may only be fetched with a token of its agents",
+		}, w)
+		return
+	}
+	tc, err := h.TelegrafService.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	version := tc.Version()
+	a, err := h.TelegrafAgentService.FindTelegrafToken(ctx, tc.ID)
+	switch {
+	case err == nil:
+		*tc = tc.WithToken(a.Token)
+	case platform.ErrorCode(err) != platform.ENotFound:
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	hostname := telegrafAgentHostname(r)
+	// The config is sent even if the fetch can not be recorded.
+	if err := h.TelegrafAgentService.PutTelegrafAgent(ctx, &platform.TelegrafAgent{
+		TelegrafID: tc.ID,
+		Hostname:   hostname,
+		Version:    version,
+		FetchedAt:  time.Now().UTC(),
+	}); err != nil {
+		h.Logger.Info("failed to record telegraf agent", zap.String("hostname", hostname), zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/toml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(tc.TOML()))
+}
+
+// telegrafAgentHostname returns the hostname of the agent of a request: the
+// hostname query parameter, which agents set in the url of the config, or
+// the host the request comes from.
+func telegrafAgentHostname(r *http.Request) string {
+	hostname := r.URL.Query().Get("hostname")
+	if hostname == "" {
+		hostname = r.RemoteAddr
+	}
+	if host, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = host
+	}
+	return hostname
+}
+
+// isTelegrafAgent reports whether auth is a token of the agents of the
+// telegraf config: a token with read permission on the config itself.
+// Tokens that read every config of an organization are not agent tokens.
+func isTelegrafAgent(auth platform.Authorizer, telegrafID platform.ID) bool {
+	a, ok := auth.(*platform.Authorization)
+	if !ok || !a.IsActive() {
+		return false
+	}
+	for _, p := range a.Permissions {
+		if p.Action == platform.ReadAction && p.Resource.Type == platform.TelegrafsResourceType &&
+			p.Resource.ID != nil && *p.Resource.ID == telegrafID {
+			return true
+		}
+	}
+	return false
+}
+
+type telegrafAgentsResponse struct {
+	// Version is the current version of the config.
+	Version string                    `json:"version"`
+	Agents  []*platform.TelegrafAgent `json:"agents"`
+}
+
+// handleGetTelegrafAgents is the HTTP handler for the GET /api/v2/telegrafs/:id/agents route.
+func (h *TelegrafHandler) handleGetTelegrafAgents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetTelegrafRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	tc, err := h.TelegrafService.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	as, err := h.TelegrafAgentService.FindTelegrafAgents(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res := telegrafAgentsResponse{
+		Version: tc.Version(),
+		Agents:  as,
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostTelegrafToken is the HTTP handler for the POST /api/v2/telegrafs/:id/token route.
+// It replaces the token of the config; the agents get the new token the
+// next time they fetch the config.  It requires the write permission on the
+// config.
+func (h *TelegrafHandler) handlePostTelegrafToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetTelegrafRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	tc, err := h.TelegrafService.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	p, err := platform.NewPermissionAtID(tc.ID, platform.WriteAction, platform.TelegrafsResourceType, tc.OrganizationID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if !auth.Allowed(*p) {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "the token of the config may only be replaced with write permission on the config",
+		}, w)
+		return
+	}
+
+	if _, err := h.TelegrafAgentService.CreateTelegrafToken(ctx, tc, auth.GetUserID()); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func decodeTelegrafConfigFilter(ctx context.Context, r *http.Request) (*platform.TelegrafConfigFilter, error) {
 	f := &platform.TelegrafConfigFilter{}
 	urm, err := decodeUserResourceMappingFilter(ctx, r)
@@ -241,16 +416,64 @@ func decodeUserResourceMappingFilter(ctx context.Context, r *http.Request) (*pla
 	return f, nil
 }
 
+// isTOMLRequest reports whether the body of r is a telegraf toml config.
+func isTOMLRequest(r *http.Request) bool {
+	ct, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && ct == "application/toml"
+}
+
+// decodeTelegrafTOMLRequest decodes a telegraf toml config from the body of
+// r. The name of the config is taken from the name query parameter.
+func decodeTelegrafTOMLRequest(r *http.Request) (*platform.TelegrafConfig, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	tc := &platform.TelegrafConfig{
+		Name: r.URL.Query().Get("name"),
+	}
+	if err := tc.DecodeTOML(string(body)); err != nil {
+		return nil, err
+	}
+	return tc, nil
+}
+
 func decodePostTelegrafRequest(ctx context.Context, r *http.Request) (*platform.TelegrafConfig, error) {
-	tc := new(platform.TelegrafConfig)
-	err := json.NewDecoder(r.Body).Decode(tc)
-	return tc, err
+	if !isTOMLRequest(r) {
+		tc := new(platform.TelegrafConfig)
+		err := json.NewDecoder(r.Body).Decode(tc)
+		return tc, err
+	}
+
+	tc, err := decodeTelegrafTOMLRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	orgID := r.URL.Query().Get("orgID")
+	if orgID == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "orgID is missing",
+		}
+	}
+	if err := tc.OrganizationID.DecodeFromString(orgID); err != nil {
+		return nil, err
+	}
+	return tc, nil
 }
 
 func decodePutTelegrafRequest(ctx context.Context, r *http.Request) (*platform.TelegrafConfig, error) {
-	tc := new(platform.TelegrafConfig)
-	if err := json.NewDecoder(r.Body).Decode(tc); err != nil {
-		return nil, err
+	var tc *platform.TelegrafConfig
+	if isTOMLRequest(r) {
+		var err error
+		if tc, err = decodeTelegrafTOMLRequest(r); err != nil {
+			return nil, err
+		}
+	} else {
+		tc = new(platform.TelegrafConfig)
+		if err := json.NewDecoder(r.Body).Decode(tc); err != nil {
+			return nil, err
+		}
 	}
 	params := httprouter.ParamsFromContext(ctx)
 	id := params.ByName("id")
@@ -284,6 +507,10 @@ func (h *TelegrafHandler) handlePostTelegraf(w http.ResponseWriter, r *http.Requ
 		EncodeError(ctx, err, w)
 		return
 	}
+	if _, err := h.TelegrafAgentService.CreateTelegrafToken(ctx, tc, auth.GetUserID()); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 
 	if err := encodeResponse(ctx, w, http.StatusCreated, newTelegrafResponse(tc, []*platform.Label{})); err != nil {
 		logEncodingError(h.Logger, r, err)
@@ -306,6 +533,16 @@ func (h *TelegrafHandler) handlePutTelegraf(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// A toml config without a name keeps the name it has.
+	if tc.Name == "" && isTOMLRequest(r) {
+		current, err := h.TelegrafService.FindTelegrafConfigByID(ctx, tc.ID)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		tc.Name = current.Name
+	}
+
 	tc, err = h.TelegrafService.UpdateTelegrafConfig(ctx, tc.ID, tc, auth.GetUserID())
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -336,9 +573,230 @@ func (h *TelegrafHandler) handleDeleteTelegraf(w http.ResponseWriter, r *http.Re
 		EncodeError(ctx, err, w)
 		return
 	}
+	if err = h.TelegrafAgentService.DeleteTelegrafAgents(ctx, i); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 
 	if err := encodeResponse(ctx, w, http.StatusNoContent, nil); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
 }
+
+// TelegrafService connects to Influx via HTTP using tokens to manage telegraf configs.
+type TelegrafService struct {
+	*UserResourceMappingService
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.TelegrafConfigStore = (*TelegrafService)(nil)
+
+// NewTelegrafService returns a new instance of TelegrafService.
+func NewTelegrafService(addr, token string, insecureSkipVerify bool) *TelegrafService {
+	return &TelegrafService{
+		UserResourceMappingService: &UserResourceMappingService{
+			Addr:               addr,
+			Token:              token,
+			InsecureSkipVerify: insecureSkipVerify,
+			BasePath:           telegrafsPath,
+		},
+		Addr:               addr,
+		Token:              token,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+}
+
+// FindTelegrafConfigByID returns a single telegraf config by ID.
+func (s *TelegrafService) FindTelegrafConfigByID(ctx context.Context, id platform.ID) (*platform.TelegrafConfig, error) {
+	url, err := newURL(s.Addr, telegrafIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	tc := new(platform.TelegrafConfig)
+	if err := json.NewDecoder(resp.Body).Decode(tc); err != nil {
+		return nil, err
+	}
+	return tc, nil
+}
+
+// FindTelegrafConfig returns the first telegraf config that matches filter.
+func (s *TelegrafService) FindTelegrafConfig(ctx context.Context, filter platform.TelegrafConfigFilter) (*platform.TelegrafConfig, error) {
+	tcs, n, err := s.FindTelegrafConfigs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Op:   platform.OpFindTelegrafConfig,
+			Msg:  "telegraf config not found",
+		}
+	}
+
+	return tcs[0], nil
+}
+
+// FindTelegrafConfigs returns a list of telegraf configs that match filter and the total count of matching telegraf configs.
+func (s *TelegrafService) FindTelegrafConfigs(ctx context.Context, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) ([]*platform.TelegrafConfig, int, error) {
+	url, err := newURL(s.Addr, telegrafsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := url.Query()
+	if filter.OrganizationID != nil {
+		query.Add("orgID", filter.OrganizationID.String())
+	} else if filter.Organization != nil {
+		query.Add("org", *filter.Organization)
+	}
+	if filter.UserID.Valid() {
+		query.Add("userID", filter.UserID.String())
+	}
+	url.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	var r struct {
+		TelegrafConfigs []*platform.TelegrafConfig `json:"configurations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, 0, err
+	}
+	return r.TelegrafConfigs, len(r.TelegrafConfigs), nil
+}
+
+// CreateTelegrafConfig creates a new telegraf config and sets tc.ID with the new identifier.
+// The config is owned by the user of the token, userID is ignored.
+func (s *TelegrafService) CreateTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) error {
+	url, err := newURL(s.Addr, telegrafsPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(tc)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if err := CheckError(resp, true); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(tc)
+}
+
+// UpdateTelegrafConfig updates a single telegraf config.
+// Returns the new telegraf config after update.
+func (s *TelegrafService) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *platform.TelegrafConfig, userID platform.ID) (*platform.TelegrafConfig, error) {
+	url, err := newURL(s.Addr, telegrafIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", url.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	updated := new(platform.TelegrafConfig)
+	if err := json.NewDecoder(resp.Body).Decode(updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteTelegrafConfig removes a telegraf config by ID.
+func (s *TelegrafService) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error {
+	url, err := newURL(s.Addr, telegrafIDPath(id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", url.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return CheckError(resp, true)
+}
+
+func telegrafIDPath(id platform.ID) string {
+	return path.Join(telegrafsPath, id.String())
+}
@@ -10,7 +10,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
 	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
@@ -156,6 +161,7 @@ func TestTelegrafHandler_handleGetTelegrafs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			h := NewTelegrafHandler(zaptest.NewLogger(t), mock.NewUserResourceMappingService(), mock.NewLabelService(), tt.svc, mock.NewUserService(), &mock.OrganizationService{})
+			h.TelegrafAgentService = mock.NewTelegrafAgentService()
 			h.ServeHTTP(w, tt.r)
 
 			res := w.Result()
@@ -683,6 +689,7 @@ func TestTelegrafHandler_handleGetTelegraf(t *testing.T) {
 			tt.r.Header.Set("Accept", tt.acceptHeader)
 			w := httptest.NewRecorder()
 			h := NewTelegrafHandler(logger, mapping, labels, tt.svc, users, orgs)
+			h.TelegrafAgentService = mock.NewTelegrafAgentService()
 
 			h.ServeHTTP(w, tt.r)
 
@@ -710,6 +717,273 @@ func TestTelegrafHandler_handleGetTelegraf(t *testing.T) {
 	}
 }
 
+func TestTelegrafHandler_handlePostTelegrafTOML(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+	tests := []struct {
+		name  string
+		url   string
+		body  string
+		wants wants
+	}{
+		{
+			name: "create telegraf config from toml",
+			url:  "http://any.url/api/v2/telegrafs?orgID=0000000000000002&name=my%20config",
+			body: `[agent]
+  interval = "10s"
+
+[[inputs.cpu]]
+
+[[outputs.kafka]]
+  brokers = ["localhost:9092"]
+`,
+			wants: wants{
+				statusCode: http.StatusCreated,
+				body: `{
+  "id": "0000000000000001",
+  "organizationID": "0000000000000002",
+  "name": "my config",
+  "agent": {
+    "collectionInterval": 10000
+  },
+  "plugins": [
+    {
+      "name": "cpu",
+      "type": "input",
+      "comment": "",
+      "config": {}
+    },
+    {
+      "name": "kafka",
+      "type": "output",
+      "comment": "",
+      "config": {
+        "toml": "[[outputs.kafka]]\n  brokers = [\"localhost:9092\"]\n"
+      }
+    }
+  ]
+}`,
+			},
+		},
+		{
+			name: "invalid plugin",
+			url:  "http://any.url/api/v2/telegrafs?orgID=0000000000000002&name=my%20config",
+			body: `[agent]
+  interval = "10s"
+
+[[outputs.influxdb_v2]]
+  urls = ["http://127.0.0.1:9999"]
+`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body: `{
+  "code": "invalid",
+  "op": "DecodeTelegrafTOML",
+  "message": "line 4: organization is missing for influxdb_v2 output plugin"
+}`,
+			},
+		},
+		{
+			name: "missing orgID",
+			url:  "http://any.url/api/v2/telegrafs?name=my%20config",
+			body: "[agent]\n  interval = \"10s\"\n",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body: `{
+  "code": "invalid",
+  "message": "orgID is missing"
+}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			mapping := mock.NewUserResourceMappingService()
+			labels := mock.NewLabelService()
+			users := mock.NewUserService()
+			orgs := &mock.OrganizationService{}
+			svc := &mock.TelegrafConfigStore{
+				CreateTelegrafConfigF: func(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) error {
+					tc.ID = platform.ID(1)
+					return nil
+				},
+			}
+
+			r := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/toml")
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				UserID: platform.ID(3),
+			}))
+			w := httptest.NewRecorder()
+			h := NewTelegrafHandler(logger, mapping, labels, svc, users, orgs)
+			h.TelegrafAgentService = mock.NewTelegrafAgentService()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePostTelegraf() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if eq, diff, _ := jsonEqual(string(body), tt.wants.body); !eq {
+				t.Errorf("%q. handlePostTelegraf() = ***%s***", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestTelegrafHandler_agentFetch(t *testing.T) {
+	tc := &platform.TelegrafConfig{
+		ID:             platform.ID(1),
+		OrganizationID: platform.ID(2),
+		Name:           "my config",
+		Agent: platform.TelegrafAgentConfig{
+			Interval: 10000,
+		},
+		Plugins: []platform.TelegrafPlugin{
+			{
+				Config: &outputs.InfluxDBV2{
+					URLs:         []string{"http://127.0.0.1:9999"},
+					Organization: "my_org",
+					Bucket:       "my_bucket",
+				},
+			},
+		},
+	}
+	svc := &mock.TelegrafConfigStore{
+		FindTelegrafConfigByIDF: func(ctx context.Context, id platform.ID) (*platform.TelegrafConfig, error) {
+			// The handler gets a copy, as it would from a store.
+			cp := *tc
+			return &cp, nil
+		},
+	}
+	var agents []*platform.TelegrafAgent
+	agentSvc := mock.NewTelegrafAgentService()
+	agentSvc.FindTelegrafTokenF = func(ctx context.Context, telegrafID platform.ID) (*platform.Authorization, error) {
+		return &platform.Authorization{Token: "agent_token"}, nil
+	}
+	agentSvc.PutTelegrafAgentF = func(ctx context.Context, a *platform.TelegrafAgent) error {
+		agents = append(agents, a)
+		return nil
+	}
+	agentSvc.FindTelegrafAgentsF = func(ctx context.Context, telegrafID platform.ID) ([]*platform.TelegrafAgent, error) {
+		return agents, nil
+	}
+
+	h := NewTelegrafHandler(zaptest.NewLogger(t), mock.NewUserResourceMappingService(), mock.NewLabelService(), svc, mock.NewUserService(), &mock.OrganizationService{})
+	h.TelegrafAgentService = agentSvc
+
+	agentPerm, _ := platform.NewPermissionAtID(tc.ID, platform.ReadAction, platform.TelegrafsResourceType, tc.OrganizationID)
+	orgPerm, _ := platform.NewPermission(platform.ReadAction, platform.TelegrafsResourceType, tc.OrganizationID)
+	fetch := func(path, accept string, perms ...platform.Permission) (int, string) {
+		r := httptest.NewRequest("GET", "http://any.url"+path, nil)
+		r.Header.Set("Accept", accept)
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: perms,
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		res := w.Result()
+		body, _ := ioutil.ReadAll(res.Body)
+		return res.StatusCode, string(body)
+	}
+
+	// The config is fetched without the token of the agents by users.
+	for _, accept := range []string{"application/toml", "application/octet-stream", "application/json"} {
+		code, body := fetch("/api/v2/telegrafs/0000000000000001", accept, *orgPerm)
+		if code != http.StatusOK {
+			t.Fatalf("handleGetTelegraf() = %v, want %v", code, http.StatusOK)
+		}
+		if strings.Contains(body, "agent_token") {
+			t.Fatalf("handleGetTelegraf() %s has the token of the agents: %s", accept, body)
+		}
+	}
+	if len(agents) != 0 {
+		t.Fatalf("expected no recorded agent, got %d", len(agents))
+	}
+
+	// Only agent tokens fetch the token of the agents.
+	if code, _ := fetch("/api/v2/telegrafs/0000000000000001/agent", "application/toml", *orgPerm); code != http.StatusForbidden {
+		t.Fatalf("handleGetTelegrafAgent() = %v, want %v", code, http.StatusForbidden)
+	}
+	code, body := fetch("/api/v2/telegrafs/0000000000000001/agent?hostname=host1", "application/toml", *agentPerm)
+	if code != http.StatusOK {
+		t.Fatalf("handleGetTelegrafAgent() = %v, want %v", code, http.StatusOK)
+	}
+	if !strings.Contains(body, `token = "agent_token"`) {
+		t.Fatalf("handleGetTelegrafAgent() did not set the token of the agents, got %s", body)
+	}
+
+	// The agent is recorded under the hostname parameter, without port, or
+	// else under the host of the request; httptest requests come from
+	// 192.0.2.1:1234.
+	if code, _ := fetch("/api/v2/telegrafs/0000000000000001/agent?hostname=host2:8080", "application/toml", *agentPerm); code != http.StatusOK {
+		t.Fatalf("handleGetTelegrafAgent() = %v, want %v", code, http.StatusOK)
+	}
+	if code, _ := fetch("/api/v2/telegrafs/0000000000000001/agent", "application/toml", *agentPerm); code != http.StatusOK {
+		t.Fatalf("handleGetTelegrafAgent() = %v, want %v", code, http.StatusOK)
+	}
+	if len(agents) != 3 {
+		t.Fatalf("expected 3 recorded fetches, got %d", len(agents))
+	}
+	for i, hostname := range []string{"host1", "host2", "192.0.2.1"} {
+		if agents[i].Hostname != hostname || agents[i].TelegrafID != tc.ID || agents[i].Version != tc.Version() {
+			t.Errorf("unexpected agent %+v, want hostname %q", agents[i], hostname)
+		}
+	}
+	agents = agents[:1]
+	if token := tc.Plugins[0].Config.(*outputs.InfluxDBV2).Token; token != "" {
+		t.Fatalf("the token of the agents was stored in the config: %q", token)
+	}
+
+	// Replacing the token requires the write permission on the config.
+	var tokens int
+	agentSvc.CreateTelegrafTokenF = func(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) (*platform.Authorization, error) {
+		tokens++
+		return &platform.Authorization{Token: "new_token"}, nil
+	}
+	post := func(perms ...platform.Permission) int {
+		r := httptest.NewRequest("POST", "http://any.url/api/v2/telegrafs/0000000000000001/token", nil)
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: perms,
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result().StatusCode
+	}
+	if code := post(*orgPerm, *agentPerm); code != http.StatusForbidden {
+		t.Fatalf("handlePostTelegrafToken() = %v, want %v", code, http.StatusForbidden)
+	}
+	if tokens != 0 {
+		t.Fatalf("expected the token to be kept, got %d new tokens", tokens)
+	}
+	writePerm, _ := platform.NewPermissionAtID(tc.ID, platform.WriteAction, platform.TelegrafsResourceType, tc.OrganizationID)
+	if code := post(*writePerm); code != http.StatusNoContent {
+		t.Fatalf("handlePostTelegrafToken() = %v, want %v", code, http.StatusNoContent)
+	}
+	if tokens != 1 {
+		t.Fatalf("expected a new token, got %d", tokens)
+	}
+
+	code, body = fetch("/api/v2/telegrafs/0000000000000001/agents", "application/json", *orgPerm)
+	if code != http.StatusOK {
+		t.Fatalf("handleGetTelegrafAgents() = %v, want %v", code, http.StatusOK)
+	}
+	var agentsRes telegrafAgentsResponse
+	if err := json.Unmarshal([]byte(body), &agentsRes); err != nil {
+		t.Fatalf("failed to decode agents: %v", err)
+	}
+	if agentsRes.Version != tc.Version() || len(agentsRes.Agents) != 1 {
+		t.Fatalf("unexpected agents response %s", body)
+	}
+}
+
 func Test_newTelegrafResponses(t *testing.T) {
 	type args struct {
 		tcs []*platform.TelegrafConfig
@@ -876,3 +1150,76 @@ func Test_newTelegrafResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestTelegrafService(t *testing.T) {
+	svc := kv.NewService(inmem.NewKVStore())
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("failed to initialize kv service: %v", err)
+	}
+
+	h := NewTelegrafHandler(zaptest.NewLogger(t), svc, mock.NewLabelService(), svc, svc, svc)
+	h.TelegrafAgentService = mock.NewTelegrafAgentService()
+	userID := platform.ID(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{UserID: userID}))
+		h.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := NewTelegrafService(server.URL, "", false)
+
+	cmpOpt := cmpopts.IgnoreUnexported(inputs.CPUStats{}, outputs.InfluxDBV2{})
+	orgID := platform.ID(2)
+	tc := &platform.TelegrafConfig{
+		OrganizationID: orgID,
+		Name:           "tc1",
+		Agent:          platform.TelegrafAgentConfig{Interval: 10000},
+		Plugins: []platform.TelegrafPlugin{
+			{Config: &inputs.CPUStats{}},
+			{Config: &outputs.InfluxDBV2{URLs: []string{"http://127.0.0.1:9999"}, Organization: "org1", Bucket: "bucket1"}},
+		},
+	}
+	if err := client.CreateTelegrafConfig(ctx, tc, 0); err != nil {
+		t.Fatalf("failed to create telegraf config: %v", err)
+	}
+	if !tc.ID.Valid() {
+		t.Fatalf("expected the created config to have an id")
+	}
+	// The config is owned by the user of the token.
+	urms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{ResourceID: tc.ID})
+	if err != nil || len(urms) != 1 || urms[0].UserID != userID {
+		t.Fatalf("expected the config to be owned by %s, got %v, %v", userID, urms, err)
+	}
+
+	got, err := client.FindTelegrafConfigByID(ctx, tc.ID)
+	if err != nil {
+		t.Fatalf("failed to find telegraf config: %v", err)
+	}
+	if diff := cmp.Diff(got, tc, cmpOpt); diff != "" {
+		t.Fatalf("telegraf configs are different -got/+want\ndiff %s", diff)
+	}
+
+	tc.Name = "tc2"
+	tc.Plugins = tc.Plugins[:1]
+	if got, err = client.UpdateTelegrafConfig(ctx, tc.ID, tc, 0); err != nil {
+		t.Fatalf("failed to update telegraf config: %v", err)
+	}
+	if diff := cmp.Diff(got, tc, cmpOpt); diff != "" {
+		t.Fatalf("telegraf configs are different -got/+want\ndiff %s", diff)
+	}
+
+	tcs, n, err := client.FindTelegrafConfigs(ctx, platform.TelegrafConfigFilter{OrganizationID: &orgID})
+	if err != nil || n != 1 {
+		t.Fatalf("expected to find one telegraf config, got %d, %v", n, err)
+	}
+	if diff := cmp.Diff(tcs[0], tc, cmpOpt); diff != "" {
+		t.Fatalf("telegraf configs are different -got/+want\ndiff %s", diff)
+	}
+
+	if err := client.DeleteTelegrafConfig(ctx, tc.ID); err != nil {
+		t.Fatalf("failed to delete telegraf config: %v", err)
+	}
+	if _, err := client.FindTelegrafConfigByID(ctx, tc.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the config to be deleted, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	platformtesting "github.com/influxdata/influxdb/testing"
 )
 
@@ -27,12 +28,12 @@ func initUserService(f platformtesting.UserFields, t *testing.T) (platform.UserS
 	server := httptest.NewServer(handler)
 	client := UserService{
 		Addr:     server.URL,
-		OpPrefix: inmem.OpPrefix,
+		OpPrefix: kv.OpPrefix,
 	}
 
 	done := server.Close
 
-	return &client, inmem.OpPrefix, done
+	return &client, kv.OpPrefix, done
 }
 
 func TestUserService(t *testing.T) {
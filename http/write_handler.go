@@ -1,8 +1,10 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,11 +17,12 @@ import (
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/write"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
 
-// WriteHandler receives line protocol and sends to a publish function.
+// WriteHandler receives line protocol, annotated CSV, CSV or JSON points and sends to a publish function.
 type WriteHandler struct {
 	*httprouter.Router
 
@@ -35,8 +38,81 @@ const (
 	writePath            = "/api/v2/write"
 	errInvalidGzipHeader = "gzipped HTTP body contains an invalid header"
 	errInvalidPrecision  = "invalid precision; valid precision units are ns, us, ms, and s"
+	errMissingCSVMapping = "csv format requires a mapping query parameter"
+	errInvalidWriteMode  = "invalid mode; valid write modes are atomic and partial"
+
+	// maxRejectedLines bounds the number of rejected lines reported in a write response.
+	maxRejectedLines = 100
+	// maxRejectedLineLength bounds the length of the text reported for a rejected line.
+	maxRejectedLineLength = 128
 )
 
+// WriteMode controls what a write does with a batch that contains rejected lines.
+type WriteMode string
+
+const (
+	// WriteModeAtomic rejects the whole batch if any line fails to parse or validate.
+	WriteModeAtomic WriteMode = "atomic"
+	// WriteModePartial writes every valid line and reports the rejected ones.
+	WriteModePartial WriteMode = "partial"
+)
+
+// RejectedLine describes a line of a write that was not written.
+type RejectedLine struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+	Text   string `json:"text,omitempty"`
+}
+
+func newRejectedLine(line int, reason, text string) RejectedLine {
+	if len(text) > maxRejectedLineLength {
+		text = text[:maxRejectedLineLength] + "..."
+	}
+	return RejectedLine{Line: line, Reason: reason, Text: text}
+}
+
+// WriteError is the body of a write response that rejected lines.
+type WriteError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Op      string `json:"op"`
+	// Line is the first rejected line.
+	Line     int `json:"line,omitempty"`
+	Written  int `json:"written"`
+	Rejected int `json:"rejected"`
+	// Errors holds at most maxRejectedLines of the rejected lines.
+	Errors []RejectedLine `json:"errors"`
+}
+
+func (e *WriteError) Error() string {
+	return e.Message
+}
+
+func encodeWriteError(w http.ResponseWriter, written int, rejected []RejectedLine) {
+	msg := fmt.Sprintf("%d lines rejected; %d points written", len(rejected), written)
+	if written == 0 {
+		msg = fmt.Sprintf("%d lines rejected; no points were written", len(rejected))
+	}
+
+	e := &WriteError{
+		Code:     platform.EInvalid,
+		Message:  msg,
+		Op:       "http/handleWrite",
+		Line:     rejected[0].Line,
+		Written:  written,
+		Rejected: len(rejected),
+		Errors:   rejected,
+	}
+	if len(e.Errors) > maxRejectedLines {
+		e.Errors = e.Errors[:maxRejectedLines]
+	}
+
+	w.Header().Set(PlatformErrorCodeHeader, e.Code)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(e)
+}
+
 // NewWriteHandler creates a new handler at /api/v2/write to receive line protocol.
 func NewWriteHandler(writer storage.PointsWriter) *WriteHandler {
 	h := &WriteHandler{
@@ -159,28 +235,182 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	points, err := models.ParsePointsWithPrecision(data, time.Now(), req.Precision)
-	if err != nil {
+	var (
+		points []models.Point
+		lines  []int
+		now    = time.Now()
+	)
+	if req.Format == write.FormatLineProtocol {
+		points, lines, err = models.ParsePointsWithLines(data, now, req.Precision)
+	} else {
+		points, err = write.ParsePoints(req.Format, data, write.ParseOptions{
+			Precision:  req.Precision,
+			CSVMapping: req.CSVMapping,
+			Now:        now,
+		})
+	}
+
+	var rejected []RejectedLine
+	if perr, ok := err.(*models.ParseError); ok {
+		for _, l := range perr.Lines {
+			rejected = append(rejected, newRejectedLine(l.Line, l.Err.Error(), l.Text))
+		}
+	} else if err != nil {
 		logger.Info("Error parsing points", zap.Error(err))
-		EncodeError(ctx, err, w)
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handleWrite",
+			Msg:  err.Error(),
+		}, w)
 		return
 	}
 
-	exploded, err := tsdb.ExplodePoints(org.ID, bucket.ID, points)
-	if err != nil {
-		logger.Info("Error exploding points", zap.Error(err))
-		EncodeError(ctx, err, w)
+	points, lines, conflicts := rejectFieldTypeConflicts(points, lines)
+	rejected = append(rejected, conflicts...)
+
+	// The points are checked against the storage before any is written, so
+	// that a rejected atomic batch writes nothing and a partial batch is
+	// written once.
+	if v, ok := h.PointsWriter.(storage.PointsValidator); ok {
+		var invalid []RejectedLine
+		points, lines, invalid, err = rejectInvalidPoints(v, org.ID, bucket.ID, points, lines)
+		if err != nil {
+			// The batch cannot be written as a whole, such as when it does
+			// not fit in the cache.
+			logger.Info("Error validating points", zap.Error(err))
+			EncodeError(ctx, errors.BadRequestError(err.Error()), w)
+			return
+		}
+		rejected = append(rejected, invalid...)
+	}
+
+	if len(rejected) > 0 && req.Mode == WriteModeAtomic {
+		logger.Info("Rejected write batch", zap.Int("rejected", len(rejected)))
+		encodeWriteError(w, 0, rejected)
 		return
 	}
 
-	if err := h.PointsWriter.WritePoints(exploded); err != nil {
+	if err := h.writePoints(org.ID, bucket.ID, points); err != nil {
+		// The lines were validated, the storage failed for another reason,
+		// such as a full cache.
+		logger.Info("Error writing points", zap.Error(err))
 		EncodeError(ctx, errors.BadRequestError(err.Error()), w)
 		return
 	}
 
+	if len(rejected) > 0 {
+		encodeWriteError(w, len(points), rejected)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *WriteHandler) writePoints(orgID, bucketID platform.ID, points []models.Point) error {
+	exploded, err := tsdb.ExplodePoints(orgID, bucketID, points)
+	if err != nil {
+		return err
+	}
+	return h.PointsWriter.WritePoints(exploded)
+}
+
+// pointLine returns the line number of the i-th point, if line numbers are known.
+func pointLine(lines []int, i int) int {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return 0
+}
+
+// rejectInvalidPoints removes the points that v would not write. The points
+// are exploded one at a time, so that the errors are reported for their lines,
+// and validated together. An error is returned if the batch cannot be written
+// as a whole.
+func rejectInvalidPoints(v storage.PointsValidator, orgID, bucketID platform.ID, points []models.Point, lines []int) ([]models.Point, []int, []RejectedLine, error) {
+	var (
+		exploded []models.Point
+		// owners holds the index of the point each exploded point comes from.
+		owners  []int
+		reasons = make(map[int]string)
+	)
+	for i, pt := range points {
+		pts, err := tsdb.ExplodePoints(orgID, bucketID, []models.Point{pt})
+		if err != nil {
+			reasons[i] = err.Error()
+			continue
+		}
+		for range pts {
+			owners = append(owners, i)
+		}
+		exploded = append(exploded, pts...)
+	}
+
+	err := v.ValidatePoints(exploded)
+	if invalid, ok := err.(tsdb.InvalidPointsError); ok {
+		for j, pt := range exploded {
+			reason, ok := invalid.Reasons[string(pt.Key())]
+			if _, seen := reasons[owners[j]]; ok && !seen {
+				reasons[owners[j]] = reason
+			}
+		}
+	} else if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var (
+		keepPts  = points[:0]
+		keepLns  []int
+		rejected []RejectedLine
+	)
+	for i, pt := range points {
+		if reason, ok := reasons[i]; ok {
+			rejected = append(rejected, newRejectedLine(pointLine(lines, i), reason, pt.String()))
+			continue
+		}
+		keepPts = append(keepPts, pt)
+		if i < len(lines) {
+			keepLns = append(keepLns, lines[i])
+		}
+	}
+	return keepPts, keepLns, rejected, nil
+}
+
+// rejectFieldTypeConflicts removes points that write a field with a different
+// type than an earlier point in the same batch.
+func rejectFieldTypeConflicts(points []models.Point, lines []int) ([]models.Point, []int, []RejectedLine) {
+	var (
+		types    = make(map[string]models.FieldType)
+		keepPts  = points[:0]
+		keepLns  []int
+		rejected []RejectedLine
+	)
+	for i, pt := range points {
+		var reason string
+		key := string(pt.Key())
+		for iter := pt.FieldIterator(); iter.Next(); {
+			fk := key + " " + string(iter.FieldKey())
+			if typ, ok := types[fk]; ok && typ != iter.Type() {
+				reason = fmt.Sprintf("field type conflict: input field %q on measurement %q is type %s, already exists as type %s",
+					iter.FieldKey(), pt.Name(), iter.Type(), typ)
+				break
+			}
+		}
+		if reason != "" {
+			rejected = append(rejected, newRejectedLine(pointLine(lines, i), reason, pt.String()))
+			continue
+		}
+
+		for iter := pt.FieldIterator(); iter.Next(); {
+			types[key+" "+string(iter.FieldKey())] = iter.Type()
+		}
+		keepPts = append(keepPts, pt)
+		if i < len(lines) {
+			keepLns = append(keepLns, lines[i])
+		}
+	}
+	return keepPts, keepLns, rejected
+}
+
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
 	qp := r.URL.Query()
 	p := qp.Get("precision")
@@ -196,17 +426,71 @@ func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest
 		}
 	}
 
+	format := write.FormatFromContentType(r.Header.Get("Content-Type"))
+	var err error
+	if f := qp.Get("format"); f != "" {
+		format, err = write.ParseFormat(f)
+	}
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeWriteRequest",
+			Msg:  err.Error(),
+		}
+	}
+
+	var mapping *write.CSVMapping
+	if spec := qp.Get("mapping"); spec != "" {
+		if format == write.FormatAnnotatedCSV && qp.Get("format") == "" {
+			// A mapping turns text/csv into plain CSV.
+			format = write.FormatCSV
+		}
+		if mapping, err = write.ParseCSVMapping(spec); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeWriteRequest",
+				Msg:  err.Error(),
+			}
+		}
+	}
+	if format == write.FormatCSV && mapping == nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeWriteRequest",
+			Msg:  errMissingCSVMapping,
+		}
+	}
+
+	mode := WriteMode(qp.Get("mode"))
+	switch mode {
+	case "":
+		mode = WriteModeAtomic
+	case WriteModeAtomic, WriteModePartial:
+	default:
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeWriteRequest",
+			Msg:  errInvalidWriteMode,
+		}
+	}
+
 	return &postWriteRequest{
-		Bucket:    qp.Get("bucket"),
-		Org:       qp.Get("org"),
-		Precision: p,
+		Mode:       mode,
+		Bucket:     qp.Get("bucket"),
+		Org:        qp.Get("org"),
+		Precision:  p,
+		Format:     format,
+		CSVMapping: mapping,
 	}, nil
 }
 
 type postWriteRequest struct {
-	Org       string
-	Bucket    string
-	Precision string
+	Mode       WriteMode
+	Org        string
+	Bucket     string
+	Precision  string
+	Format     write.Format
+	CSVMapping *write.CSVMapping
 }
 
 // WriteService sends data over HTTP to influxdb via line protocol.
@@ -214,6 +498,7 @@ type WriteService struct {
 	Addr               string
 	Token              string
 	Precision          string
+	Mode               WriteMode
 	InsecureSkipVerify bool
 }
 
@@ -266,6 +551,9 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 	params.Set("org", string(org))
 	params.Set("bucket", string(bucket))
 	params.Set("precision", string(precision))
+	if s.Mode != "" {
+		params.Set("mode", string(s.Mode))
+	}
 	req.URL.RawQuery = params.Encode()
 
 	hc := newClient(u.Scheme, s.InsecureSkipVerify)
@@ -274,7 +562,29 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	return checkWriteError(resp)
+}
+
+// checkWriteError returns a *WriteError when the response reports rejected
+// lines, and otherwise behaves like CheckError.
+func checkWriteError(resp *http.Response) error {
+	if resp.StatusCode != http.StatusBadRequest {
+		return CheckError(resp, true)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	we := new(WriteError)
+	if err := json.Unmarshal(body, we); err == nil && len(we.Errors) > 0 {
+		return we
+	}
 
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
 	return CheckError(resp, true)
 }
 
@@ -3,14 +3,22 @@ package http
 import (
 	"compress/gzip"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/write"
 )
 
 func TestWriteService_Write(t *testing.T) {
@@ -69,3 +77,243 @@ func TestWriteService_Write(t *testing.T) {
 		})
 	}
 }
+
+func Test_decodeWriteRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		wantFormat  write.Format
+		wantMapping bool
+		wantErr     bool
+	}{
+		{
+			name:       "line protocol by default",
+			url:        "/api/v2/write?org=o&bucket=b",
+			wantFormat: write.FormatLineProtocol,
+		},
+		{
+			name:        "annotated csv from content type",
+			url:         "/api/v2/write?org=o&bucket=b",
+			contentType: "text/csv",
+			wantFormat:  write.FormatAnnotatedCSV,
+		},
+		{
+			name:        "plain csv when a mapping is given",
+			url:         "/api/v2/write?org=o&bucket=b&mapping=" + url.QueryEscape("measurement=cpu,field=usage"),
+			contentType: "text/csv",
+			wantFormat:  write.FormatCSV,
+			wantMapping: true,
+		},
+		{
+			name:        "format parameter overrides content type",
+			url:         "/api/v2/write?org=o&bucket=b&format=json",
+			contentType: "text/plain",
+			wantFormat:  write.FormatJSON,
+		},
+		{
+			name:    "csv format requires a mapping",
+			url:     "/api/v2/write?org=o&bucket=b&format=csv",
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			url:     "/api/v2/write?org=o&bucket=b&format=xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", tt.url, nil)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			got, err := decodeWriteRequest(context.Background(), r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeWriteRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Format != tt.wantFormat {
+				t.Errorf("decodeWriteRequest() format = %v, want %v", got.Format, tt.wantFormat)
+			}
+			if (got.CSVMapping != nil) != tt.wantMapping {
+				t.Errorf("decodeWriteRequest() mapping = %v, want mapping %v", got.CSVMapping, tt.wantMapping)
+			}
+		})
+	}
+}
+
+func TestWriteHandler_handleWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        WriteMode
+		body        string
+		storageErr  func(models.Point) error
+		validateErr error
+		wantStatus  int
+		wantWritten int
+		wantLines   []int
+	}{
+		{
+			name:        "all lines valid",
+			body:        "m f=1 1\nm f=2 2\n",
+			wantStatus:  http.StatusNoContent,
+			wantWritten: 2,
+		},
+		{
+			name:       "atomic rejects batch with a malformed line",
+			body:       "m f=1 1\nm f= 2\nm f=3 3\n",
+			wantStatus: http.StatusBadRequest,
+			wantLines:  []int{2},
+		},
+		{
+			name:        "partial writes valid lines",
+			mode:        WriteModePartial,
+			body:        "m f=1 1\nm f= 2\n\nm f=3i 3\nm f=4 4\n",
+			wantStatus:  http.StatusBadRequest,
+			wantWritten: 2,
+			wantLines:   []int{2, 4},
+		},
+		{
+			name:       "atomic rejects batch with a line rejected by storage",
+			body:       "m f=1 1\nn f=2 2\nm f=3 3\n",
+			storageErr: failMeasurement("n"),
+			wantStatus: http.StatusBadRequest,
+			wantLines:  []int{2},
+		},
+		{
+			name:        "partial reports lines rejected by storage",
+			mode:        WriteModePartial,
+			body:        "m f=1 1\nn f=2 2\nm f=3 3\nn f=4 4\n",
+			storageErr:  failMeasurement("n"),
+			wantStatus:  http.StatusBadRequest,
+			wantWritten: 2,
+			wantLines:   []int{2, 4},
+		},
+		{
+			name:        "partial rejects a batch that cannot be written as a whole",
+			mode:        WriteModePartial,
+			body:        "m f=1 1\nn f=2 2\n",
+			validateErr: fmt.Errorf("cache-max-memory-size exceeded"),
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgID, bucketID := platform.ID(1), platform.ID(2)
+			pw := &failingPointsWriter{fail: tt.storageErr, validateErr: tt.validateErr}
+			h := NewWriteHandler(pw)
+			h.OrganizationService = &mock.OrganizationService{
+				FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id}, nil
+				},
+			}
+			h.BucketService = &mock.BucketService{
+				FindBucketFn: func(ctx context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+					return &platform.Bucket{ID: *f.ID, OrganizationID: *f.OrganizationID}, nil
+				},
+			}
+
+			u := fmt.Sprintf("/api/v2/write?org=%s&bucket=%s&mode=%s", orgID, bucketID, tt.mode)
+			r := httptest.NewRequest("POST", u, strings.NewReader(tt.body))
+			p, _ := platform.NewPermissionAtID(bucketID, platform.WriteAction, platform.BucketsResourceType, orgID)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: []platform.Permission{*p},
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.wantStatus {
+				t.Fatalf("handleWrite() status = %d, want %d: %s", got, tt.wantStatus, w.Body.String())
+			}
+			if got := pw.written(); got != tt.wantWritten {
+				t.Errorf("handleWrite() wrote %d points, want %d", got, tt.wantWritten)
+			}
+			if pw.validations != 1 {
+				t.Errorf("handleWrite() validated the points %d times, want once", pw.validations)
+			}
+			if tt.wantStatus == http.StatusNoContent || tt.validateErr != nil {
+				return
+			}
+
+			resp := w.Result()
+			err := checkWriteError(resp)
+			we, ok := err.(*WriteError)
+			if !ok {
+				t.Fatalf("expected *WriteError, got %T: %v", err, err)
+			}
+			var lines []int
+			for _, l := range we.Errors {
+				lines = append(lines, l.Line)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("handleWrite() rejected lines = %v, want %v", lines, tt.wantLines)
+			}
+			if we.Written != tt.wantWritten {
+				t.Errorf("handleWrite() reported %d written, want %d", we.Written, tt.wantWritten)
+			}
+		})
+	}
+}
+
+// failingPointsWriter fails to write the points fail returns an error for.
+// Like the storage engine, it writes the other points of a failed write.
+type failingPointsWriter struct {
+	fail func(models.Point) error
+	// validateErr is returned when validating any batch.
+	validateErr error
+	// n is the number of points written, including points written twice.
+	n int
+	// validations is the number of batches validated.
+	validations int
+}
+
+func (w *failingPointsWriter) WritePoints(points []models.Point) error {
+	var err error
+	for _, pt := range points {
+		if w.fail != nil {
+			if ferr := w.fail(pt); ferr != nil {
+				err = ferr
+				continue
+			}
+		}
+		w.n++
+	}
+	return err
+}
+
+func (w *failingPointsWriter) ValidatePoints(points []models.Point) error {
+	w.validations++
+	if w.validateErr != nil {
+		return w.validateErr
+	}
+	reasons := make(map[string]string)
+	for _, pt := range points {
+		if w.fail != nil {
+			if err := w.fail(pt); err != nil {
+				reasons[string(pt.Key())] = err.Error()
+			}
+		}
+	}
+	if len(reasons) > 0 {
+		return tsdb.InvalidPointsError{Reasons: reasons}
+	}
+	return nil
+}
+
+// failMeasurement fails the exploded points of the measurement name.
+func failMeasurement(name string) func(models.Point) error {
+	return func(pt models.Point) error {
+		if string(pt.Tags().Get(tsdb.MeasurementTagKeyBytes)) == name {
+			return fmt.Errorf("field type conflict")
+		}
+		return nil
+	}
+}
+
+func (w *failingPointsWriter) written() int {
+	return w.n
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"time"
 )
 
 // Status string to indicate the overall status of the check.
@@ -63,31 +64,26 @@ func (c *Check) AddReadyCheck(check Checker) {
 
 // CheckHealth evaluates c's set of health checks and returns a populated Response.
 func (c *Check) CheckHealth(ctx context.Context) Response {
-	response := Response{
-		Name:   "Health",
-		Status: StatusPass,
-		Checks: make(Responses, len(c.healthChecks)),
-	}
-	for i, ch := range c.healthChecks {
-		resp := ch.Check(ctx)
-		if resp.Status != StatusPass {
-			response.Status = resp.Status
-		}
-		response.Checks[i] = resp
-	}
-	sort.Sort(response.Checks)
-	return response
+	return run(ctx, "Health", c.healthChecks)
 }
 
 // CheckReady evaluates c's set of ready checks and returns a populated Response.
 func (c *Check) CheckReady(ctx context.Context) Response {
+	return run(ctx, "Ready", c.readyChecks)
+}
+
+// run evaluates the checks in order and returns a Response with their
+// results and latencies.  The Response fails if any check fails.
+func run(ctx context.Context, name string, checks []Checker) Response {
 	response := Response{
-		Name:   "Ready",
+		Name:   name,
 		Status: StatusPass,
-		Checks: make(Responses, len(c.readyChecks)),
+		Checks: make(Responses, len(checks)),
 	}
-	for i, c := range c.readyChecks {
-		resp := c.Check(ctx)
+	for i, ch := range checks {
+		start := time.Now()
+		resp := ch.Check(ctx)
+		resp.Latency = time.Since(start).String()
 		if resp.Status != StatusPass {
 			response.Status = resp.Status
 		}
@@ -140,6 +136,7 @@ func (c *Check) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		status = http.StatusInternalServerError
 	}
 	msg = string(b)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
 	fmt.Fprintln(w, msg)
 }
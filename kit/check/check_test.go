@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -100,11 +101,22 @@ func mockFail(name string) Checker {
 	return mockCheck{status: StatusFail, name: name}
 }
 
+// respBuilder decodes a response and clears the latencies of its checks,
+// which every check must have.
 func respBuilder(body io.ReadCloser) (*Response, error) {
 	defer body.Close()
 	d := json.NewDecoder(body)
 	r := &Response{}
-	return r, d.Decode(r)
+	if err := d.Decode(r); err != nil {
+		return nil, err
+	}
+	for i := range r.Checks {
+		if r.Checks[i].Latency == "" {
+			return nil, fmt.Errorf("check %q has no latency", r.Checks[i].Name)
+		}
+		r.Checks[i].Latency = ""
+	}
+	return r, nil
 }
 
 func TestBasicHTTPHandler(t *testing.T) {
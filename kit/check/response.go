@@ -2,9 +2,12 @@ package check
 
 // Response is a result of a collection of health checks.
 type Response struct {
-	Name    string    `json:"name"`
-	Status  Status    `json:"status"`
-	Message string    `json:"message,omitempty"`
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message,omitempty"`
+	// Latency is how long the check took.  It is set on the responses of
+	// the individual checks.
+	Latency string    `json:"latency,omitempty"`
 	Checks  Responses `json:"checks,omitempty"`
 }
 
@@ -73,6 +73,13 @@ func NewCommand(p *Program) *cobra.Command {
 			cmd.Flags().IntVar(o.DestP.(*int), o.Flag, o.Default.(int), o.Desc)
 			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
 			*o.DestP.(*int) = viper.GetInt(o.Flag)
+		case *float64:
+			if o.Default == nil {
+				o.Default = float64(0)
+			}
+			cmd.Flags().Float64Var(o.DestP.(*float64), o.Flag, o.Default.(float64), o.Desc)
+			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
+			*o.DestP.(*float64) = viper.GetFloat64(o.Flag)
 		case *bool:
 			if o.Default == nil {
 				o.Default = false
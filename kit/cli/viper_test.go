@@ -9,6 +9,7 @@ import (
 func ExampleNewCommand() {
 	var monitorHost string
 	var number int
+	var ratio float64
 	var sleep bool
 	var duration time.Duration
 	var stringSlice []string
@@ -18,6 +19,7 @@ func ExampleNewCommand() {
 			for i := 0; i < number; i++ {
 				fmt.Printf("%d\n", i)
 			}
+			fmt.Println(ratio)
 			fmt.Println(sleep)
 			fmt.Println(duration)
 			fmt.Println(stringSlice)
@@ -37,6 +39,12 @@ func ExampleNewCommand() {
 				Default: 2,
 				Desc:    "number of times to loop",
 			},
+			{
+				DestP:   &ratio,
+				Flag:    "ratio",
+				Default: 0.5,
+				Desc:    "ratio of the loops",
+			},
 			{
 				DestP:   &sleep,
 				Flag:    "sleep",
@@ -65,6 +73,7 @@ func ExampleNewCommand() {
 	// http://localhost:8086
 	// 0
 	// 1
+	// 0.5
 	// true
 	// 1m0s
 	// [foo bar]
@@ -0,0 +1,84 @@
+// Package tracing selects and configures the opentracing.Tracer of a service.
+package tracing
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/influxdata/influxdb/snowflake"
+	pzap "github.com/influxdata/influxdb/zap"
+	opentracing "github.com/opentracing/opentracing-go"
+	jaegerconfig "github.com/uber/jaeger-client-go/config"
+	"go.uber.org/zap"
+)
+
+// Tracer types.
+const (
+	// LogTracing writes each span as a log line.
+	LogTracing = "log"
+	// JaegerTracing reports the spans to a Jaeger agent or collector.
+	JaegerTracing = "jaeger"
+)
+
+// Config configures the tracer of a service.
+type Config struct {
+	// Type is the type of the tracer, LogTracing or JaegerTracing.
+	Type string
+	// ServiceName names the service in the spans reported to Jaeger.
+	ServiceName string
+
+	// JaegerAgentHostPort is the UDP address of the Jaeger agent the spans are
+	// reported to, localhost:6831 if empty.
+	JaegerAgentHostPort string
+	// JaegerCollectorEndpoint is the HTTP endpoint of the Jaeger collector the
+	// spans are reported to, such as http://localhost:14268/api/traces.
+	// It takes precedence over JaegerAgentHostPort.
+	JaegerCollectorEndpoint string
+	// JaegerSamplerType is the type of the Jaeger sampler: const,
+	// probabilistic, ratelimiting or remote.
+	JaegerSamplerType string
+	// JaegerSamplerParam is the parameter of the Jaeger sampler; see
+	// https://www.jaegertracing.io/docs/sampling/.
+	JaegerSamplerParam float64
+}
+
+// NewTracer returns the tracer c configures, and the closer that flushes
+// its spans.
+func NewTracer(logger *zap.Logger, c Config) (opentracing.Tracer, io.Closer, error) {
+	switch c.Type {
+	case LogTracing:
+		tracer := &pzap.Tracer{
+			Logger:      logger,
+			IDGenerator: snowflake.NewIDGenerator(),
+		}
+		return tracer, ioutil.NopCloser(nil), nil
+	case JaegerTracing:
+		cfg := jaegerconfig.Configuration{
+			ServiceName: c.ServiceName,
+			Sampler: &jaegerconfig.SamplerConfig{
+				Type:  c.JaegerSamplerType,
+				Param: c.JaegerSamplerParam,
+			},
+			Reporter: &jaegerconfig.ReporterConfig{
+				LocalAgentHostPort: c.JaegerAgentHostPort,
+				CollectorEndpoint:  c.JaegerCollectorEndpoint,
+			},
+		}
+		return cfg.NewTracer(jaegerconfig.Logger(jaegerLogger{logger: logger}))
+	}
+	return nil, nil, fmt.Errorf("unknown tracing type %q; supported types are %s and %s", c.Type, LogTracing, JaegerTracing)
+}
+
+// jaegerLogger writes the logs of the Jaeger client to a zap logger.
+type jaegerLogger struct {
+	logger *zap.Logger
+}
+
+func (l jaegerLogger) Error(msg string) {
+	l.logger.Error(msg)
+}
+
+func (l jaegerLogger) Infof(msg string, args ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(msg, args...))
+}
@@ -0,0 +1,175 @@
+package tracing_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/kit/tracing"
+	pzap "github.com/influxdata/influxdb/zap"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go/thrift"
+	"github.com/uber/jaeger-client-go/thrift-gen/agent"
+	"github.com/uber/jaeger-client-go/thrift-gen/jaeger"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewTracer_Log(t *testing.T) {
+	tracer, closer, err := tracing.NewTracer(zaptest.NewLogger(t), tracing.Config{Type: tracing.LogTracing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+
+	if _, ok := tracer.(*pzap.Tracer); !ok {
+		t.Fatalf("expected a zap tracer, got %T", tracer)
+	}
+}
+
+func TestNewTracer_Unknown(t *testing.T) {
+	if _, _, err := tracing.NewTracer(zaptest.NewLogger(t), tracing.Config{Type: "zipkin"}); err == nil {
+		t.Fatal("expected an error for an unknown tracing type")
+	}
+}
+
+func TestNewTracer_JaegerAgent(t *testing.T) {
+	// The in-process agent receives the batches of spans as UDP packets.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tracer, closer, err := tracing.NewTracer(zaptest.NewLogger(t), tracing.Config{
+		Type:                tracing.JaegerTracing,
+		ServiceName:         "influxd",
+		JaegerAgentHostPort: conn.LocalAddr().String(),
+		JaegerSamplerType:   "const",
+		JaegerSamplerParam:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	startSpans(tracer)
+	// Closing the tracer flushes its spans.
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 65000)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to receive spans: %v", err)
+	}
+
+	mem := thrift.NewTMemoryBufferLen(n)
+	mem.Write(buf[:n])
+	proto := thrift.NewTCompactProtocol(mem)
+	if _, _, _, err := proto.ReadMessageBegin(); err != nil {
+		t.Fatal(err)
+	}
+	args := agent.NewAgentEmitBatchArgs()
+	if err := args.Read(proto); err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, args.Batch)
+}
+
+func TestNewTracer_JaegerCollector(t *testing.T) {
+	// The in-process collector receives the batches of spans as HTTP requests.
+	batches := make(chan *jaeger.Batch, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mem := thrift.NewTMemoryBufferLen(len(body))
+		mem.Write(body)
+		batch := jaeger.NewBatch()
+		if err := batch.Read(thrift.NewTBinaryProtocolTransport(mem)); err != nil {
+			t.Error(err)
+			return
+		}
+		batches <- batch
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer collector.Close()
+
+	tracer, closer, err := tracing.NewTracer(zaptest.NewLogger(t), tracing.Config{
+		Type:                    tracing.JaegerTracing,
+		ServiceName:             "influxd",
+		JaegerCollectorEndpoint: collector.URL + "/api/traces",
+		JaegerSamplerType:       "const",
+		JaegerSamplerParam:      1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	startSpans(tracer)
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The collector transport sends each span in its own batch.
+	var spans []*jaeger.Span
+	for len(spans) < 2 {
+		select {
+		case batch := <-batches:
+			if batch.Process.ServiceName != "influxd" {
+				t.Fatalf("expected service influxd, got %q", batch.Process.ServiceName)
+			}
+			spans = append(spans, batch.Spans...)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("failed to receive spans, got %d", len(spans))
+		}
+	}
+	checkBatch(t, &jaeger.Batch{
+		Process: &jaeger.Process{ServiceName: "influxd"},
+		Spans:   spans,
+	})
+}
+
+// startSpans starts a request span in a client and the span of its handler
+// in a server, propagating the span context through HTTP headers.
+func startSpans(tracer opentracing.Tracer) {
+	client := tracer.StartSpan("request")
+	header := make(http.Header)
+	tracer.Inject(client.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+
+	remote, _ := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+	server := tracer.StartSpan("handler", opentracing.ChildOf(remote))
+	server.Finish()
+	client.Finish()
+}
+
+func checkBatch(t *testing.T, batch *jaeger.Batch) {
+	t.Helper()
+	if batch.Process.ServiceName != "influxd" {
+		t.Fatalf("expected service influxd, got %q", batch.Process.ServiceName)
+	}
+
+	var names []string
+	for _, s := range batch.Spans {
+		names = append(names, s.OperationName)
+	}
+	sort.Strings(names)
+	if diff := cmp.Diff(names, []string{"handler", "request"}); diff != "" {
+		t.Fatalf("spans are different -got/+want\ndiff %s", diff)
+	}
+
+	// The handler span is a child of the request span.
+	sort.Slice(batch.Spans, func(i, j int) bool {
+		return batch.Spans[i].OperationName < batch.Spans[j].OperationName
+	})
+	handler, request := batch.Spans[0], batch.Spans[1]
+	if handler.TraceIdLow != request.TraceIdLow || handler.ParentSpanId != request.SpanId {
+		t.Fatalf("expected the handler span to be a child of the request span, got %+v and %+v", handler, request)
+	}
+}
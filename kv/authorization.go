@@ -1,10 +1,9 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"encoding/json"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -13,23 +12,23 @@ var (
 	authorizationIndex  = []byte("authorizationindexv1")
 )
 
-var _ platform.AuthorizationService = (*Client)(nil)
+var _ platform.AuthorizationService = (*Service)(nil)
 
-func (c *Client) initializeAuthorizations(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(authorizationBucket)); err != nil {
+func (c *Service) initializeAuthorizations(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(authorizationBucket)); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists([]byte(authorizationIndex)); err != nil {
+	if _, err := tx.Bucket([]byte(authorizationIndex)); err != nil {
 		return err
 	}
 	return nil
 }
 
 // FindAuthorizationByID retrieves a authorization by id.
-func (c *Client) FindAuthorizationByID(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
+func (c *Service) FindAuthorizationByID(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
 	var a *platform.Authorization
 	var err error
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		var pe *platform.Error
 		a, pe = c.findAuthorizationByID(ctx, tx, id)
 		if pe != nil {
@@ -42,7 +41,7 @@ func (c *Client) FindAuthorizationByID(ctx context.Context, id platform.ID) (*pl
 	return a, err
 }
 
-func (c *Client) findAuthorizationByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Authorization, *platform.Error) {
+func (c *Service) findAuthorizationByID(ctx context.Context, tx Tx, id platform.ID) (*platform.Authorization, *platform.Error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &platform.Error{
@@ -52,14 +51,26 @@ func (c *Client) findAuthorizationByID(ctx context.Context, tx *bolt.Tx, id plat
 	}
 
 	var a platform.Authorization
-	v := tx.Bucket(authorizationBucket).Get(encodedID)
+	b, err := tx.Bucket(authorizationBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encodedID)
 
-	if len(v) == 0 {
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "authorization not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	if err := decodeAuthorization(v, &a); err != nil {
 		return nil, &platform.Error{
@@ -72,10 +83,10 @@ func (c *Client) findAuthorizationByID(ctx context.Context, tx *bolt.Tx, id plat
 }
 
 // FindAuthorizationByToken returns a authorization by token for a particular authorization.
-func (c *Client) FindAuthorizationByToken(ctx context.Context, n string) (*platform.Authorization, error) {
+func (c *Service) FindAuthorizationByToken(ctx context.Context, n string) (*platform.Authorization, error) {
 	var a *platform.Authorization
 	var err error
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		var pe *platform.Error
 		a, pe = c.findAuthorizationByToken(ctx, tx, n)
 		if pe != nil {
@@ -88,14 +99,26 @@ func (c *Client) FindAuthorizationByToken(ctx context.Context, n string) (*platf
 	return a, err
 }
 
-func (c *Client) findAuthorizationByToken(ctx context.Context, tx *bolt.Tx, n string) (*platform.Authorization, *platform.Error) {
-	a := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(n))
-	if a == nil {
+func (c *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string) (*platform.Authorization, *platform.Error) {
+	idx, err := tx.Bucket(authorizationIndex)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	a, err := idx.Get(authorizationIndexKey(n))
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "authorization not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 	var id platform.ID
 	if err := id.Decode(a); err != nil {
 		return nil, &platform.Error{
@@ -131,7 +154,7 @@ func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platfor
 // FindAuthorizations retrives all authorizations that match an arbitrary authorization filter.
 // Filters using ID, or Token should be efficient.
 // Other filters will do a linear scan across all authorizations searching for a match.
-func (c *Client) FindAuthorizations(ctx context.Context, filter platform.AuthorizationFilter, opt ...platform.FindOptions) ([]*platform.Authorization, int, error) {
+func (c *Service) FindAuthorizations(ctx context.Context, filter platform.AuthorizationFilter, opt ...platform.FindOptions) ([]*platform.Authorization, int, error) {
 	if filter.ID != nil {
 		a, err := c.FindAuthorizationByID(ctx, *filter.ID)
 		if err != nil {
@@ -157,7 +180,7 @@ func (c *Client) FindAuthorizations(ctx context.Context, filter platform.Authori
 	}
 
 	as := []*platform.Authorization{}
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		auths, err := c.findAuthorizations(ctx, tx, filter)
 		if err != nil {
 			return err
@@ -176,7 +199,7 @@ func (c *Client) FindAuthorizations(ctx context.Context, filter platform.Authori
 	return as, len(as), nil
 }
 
-func (c *Client) findAuthorizations(ctx context.Context, tx *bolt.Tx, f platform.AuthorizationFilter) ([]*platform.Authorization, error) {
+func (c *Service) findAuthorizations(ctx context.Context, tx Tx, f platform.AuthorizationFilter) ([]*platform.Authorization, error) {
 	// If the users name was provided, look up user by ID first
 	if f.User != nil {
 		u, err := c.findUserByName(ctx, tx, *f.User)
@@ -202,7 +225,7 @@ func (c *Client) findAuthorizations(ctx context.Context, tx *bolt.Tx, f platform
 }
 
 // CreateAuthorization creates a platform authorization and sets b.ID, and b.UserID if not provided.
-func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorization) error {
+func (c *Service) CreateAuthorization(ctx context.Context, a *platform.Authorization) error {
 	op := getOp(platform.OpCreateAuthorization)
 	if err := a.Valid(); err != nil {
 		return &platform.Error{
@@ -211,7 +234,7 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 		}
 	}
 
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		_, pErr := c.findUserByID(ctx, tx, a.UserID)
 		if pErr != nil {
 			return platform.ErrUnableToCreateToken
@@ -247,8 +270,8 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 }
 
 // PutAuthorization will put a authorization without setting an ID.
-func (c *Client) PutAuthorization(ctx context.Context, a *platform.Authorization) (err error) {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutAuthorization(ctx context.Context, a *platform.Authorization) (err error) {
+	return c.kv.Update(func(tx Tx) error {
 		pe := c.putAuthorization(ctx, tx, a)
 		if pe != nil {
 			err = pe
@@ -272,7 +295,7 @@ func encodeAuthorization(a *platform.Authorization) ([]byte, error) {
 	return json.Marshal(a)
 }
 
-func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.Authorization) *platform.Error {
+func (c *Service) putAuthorization(ctx context.Context, tx Tx, a *platform.Authorization) *platform.Error {
 	v, err := encodeAuthorization(a)
 	if err != nil {
 		return &platform.Error{
@@ -289,14 +312,28 @@ func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.
 		}
 	}
 
-	if err := tx.Bucket(authorizationIndex).Put(authorizationIndexKey(a.Token), encodedID); err != nil {
+	idx, err := tx.Bucket(authorizationIndex)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := idx.Put(authorizationIndexKey(a.Token), encodedID); err != nil {
 		return &platform.Error{
 			Code: platform.EInternal,
 			Err:  err,
 		}
 	}
 
-	if err := tx.Bucket(authorizationBucket).Put(encodedID, v); err != nil {
+	b, err := tx.Bucket(authorizationBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -320,8 +357,16 @@ func decodeAuthorization(b []byte, a *platform.Authorization) error {
 }
 
 // forEachAuthorization will iterate through all authorizations while fn returns true.
-func (c *Client) forEachAuthorization(ctx context.Context, tx *bolt.Tx, fn func(*platform.Authorization) bool) error {
-	cur := tx.Bucket(authorizationBucket).Cursor()
+func (c *Service) forEachAuthorization(ctx context.Context, tx Tx, fn func(*platform.Authorization) bool) error {
+	b, err := tx.Bucket(authorizationBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
 		a := &platform.Authorization{}
 
@@ -336,14 +381,22 @@ func (c *Client) forEachAuthorization(ctx context.Context, tx *bolt.Tx, fn func(
 	return nil
 }
 
-func (c *Client) uniqueAuthorizationToken(ctx context.Context, tx *bolt.Tx, a *platform.Authorization) bool {
-	v := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(a.Token))
+func (c *Service) uniqueAuthorizationToken(ctx context.Context, tx Tx, a *platform.Authorization) bool {
+	idx, err := tx.Bucket(authorizationIndex)
+	if err != nil {
+		return false
+	}
+
+	v, err := idx.Get(authorizationIndexKey(a.Token))
+	if err != nil && err != ErrKeyNotFound {
+		return false
+	}
 	return len(v) == 0
 }
 
 // DeleteAuthorization deletes a authorization and prunes it from the index.
-func (c *Client) DeleteAuthorization(ctx context.Context, id platform.ID) error {
-	err := c.db.Update(func(tx *bolt.Tx) (err error) {
+func (c *Service) DeleteAuthorization(ctx context.Context, id platform.ID) error {
+	err := c.kv.Update(func(tx Tx) (err error) {
 		pe := c.deleteAuthorization(ctx, tx, id)
 		if pe != nil {
 			pe.Op = getOp(platform.OpDeleteAuthorization)
@@ -354,12 +407,19 @@ func (c *Client) DeleteAuthorization(ctx context.Context, id platform.ID) error
 	return err
 }
 
-func (c *Client) deleteAuthorization(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+func (c *Service) deleteAuthorization(ctx context.Context, tx Tx, id platform.ID) *platform.Error {
 	a, pe := c.findAuthorizationByID(ctx, tx, id)
 	if pe != nil {
 		return pe
 	}
-	if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(a.Token)); err != nil {
+	idx, err := tx.Bucket(authorizationIndex)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := idx.Delete(authorizationIndexKey(a.Token)); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -371,7 +431,14 @@ func (c *Client) deleteAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 		}
 	}
 
-	if err := tx.Bucket(authorizationBucket).Delete(encodedID); err != nil {
+	b, err := tx.Bucket(authorizationBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -381,8 +448,8 @@ func (c *Client) deleteAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 
 // SetAuthorizationStatus updates the status of the authorization. Useful
 // for setting an authorization to inactive or active.
-func (c *Client) SetAuthorizationStatus(ctx context.Context, id platform.ID, status platform.Status) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) SetAuthorizationStatus(ctx context.Context, id platform.ID, status platform.Status) error {
+	return c.kv.Update(func(tx Tx) error {
 		if pe := c.updateAuthorization(ctx, tx, id, status); pe != nil {
 			return &platform.Error{
 				Err: pe,
@@ -393,7 +460,7 @@ func (c *Client) SetAuthorizationStatus(ctx context.Context, id platform.ID, sta
 	})
 }
 
-func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platform.ID, status platform.Status) *platform.Error {
+func (c *Service) updateAuthorization(ctx context.Context, tx Tx, id platform.ID, status platform.Status) *platform.Error {
 	a, pe := c.findAuthorizationByID(ctx, tx, id)
 	if pe != nil {
 		return pe
@@ -414,7 +481,14 @@ func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 		}
 	}
 
-	if err = tx.Bucket(authorizationBucket).Put(encodedID, b); err != nil {
+	bkt, err := tx.Bucket(authorizationBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err = bkt.Put(encodedID, b); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -1,15 +1,14 @@
-package bolt
+package kv
 
 import (
 	"context"
 
-	bolt "github.com/coreos/bbolt"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // SetPassword stores the password hash associated with a user.
-func (c *Client) SetPassword(ctx context.Context, name string, password string) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) SetPassword(ctx context.Context, name string, password string) error {
+	return c.kv.Update(func(tx Tx) error {
 		return c.setPassword(ctx, tx, name, password)
 	})
 }
@@ -17,7 +16,7 @@ func (c *Client) SetPassword(ctx context.Context, name string, password string)
 // HashCost currently using the default cost of bcrypt
 var HashCost = bcrypt.DefaultCost
 
-func (c *Client) setPassword(ctx context.Context, tx *bolt.Tx, name string, password string) error {
+func (c *Service) setPassword(ctx context.Context, tx Tx, name string, password string) error {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), HashCost)
 	if err != nil {
 		return err
@@ -33,16 +32,21 @@ func (c *Client) setPassword(ctx context.Context, tx *bolt.Tx, name string, pass
 		return err
 	}
 
-	return tx.Bucket(userpasswordBucket).Put(encodedID, hash)
+	b, err := tx.Bucket(userpasswordBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, hash)
 }
 
 // ComparePassword compares a provided password with the stored password hash.
-func (c *Client) ComparePassword(ctx context.Context, name string, password string) error {
-	return c.db.View(func(tx *bolt.Tx) error {
+func (c *Service) ComparePassword(ctx context.Context, name string, password string) error {
+	return c.kv.View(func(tx Tx) error {
 		return c.comparePassword(ctx, tx, name, password)
 	})
 }
-func (c *Client) comparePassword(ctx context.Context, tx *bolt.Tx, name string, password string) error {
+func (c *Service) comparePassword(ctx context.Context, tx Tx, name string, password string) error {
 	u, pe := c.findUserByName(ctx, tx, name)
 	if pe != nil {
 		return pe
@@ -53,14 +57,22 @@ func (c *Client) comparePassword(ctx context.Context, tx *bolt.Tx, name string,
 		return err
 	}
 
-	hash := tx.Bucket(userpasswordBucket).Get(encodedID)
+	b, err := tx.Bucket(userpasswordBucket)
+	if err != nil {
+		return err
+	}
+
+	hash, err := b.Get(encodedID)
+	if err != nil && err != ErrKeyNotFound {
+		return err
+	}
 
 	return bcrypt.CompareHashAndPassword(hash, []byte(password))
 }
 
 // CompareAndSetPassword replaces the old password with the new password if thee old password is correct.
-func (c *Client) CompareAndSetPassword(ctx context.Context, name string, old string, new string) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) CompareAndSetPassword(ctx context.Context, name string, old string, new string) error {
+	return c.kv.Update(func(tx Tx) error {
 		if err := c.comparePassword(ctx, tx, name, old); err != nil {
 			return err
 		}
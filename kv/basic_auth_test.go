@@ -1,4 +1,4 @@
-package inmem
+package kv_test
 
 import (
 	"context"
@@ -9,15 +9,25 @@ import (
 )
 
 func initBasicAuthService(f platformtesting.UserFields, t *testing.T) (platform.BasicAuthService, func()) {
-	s := NewService()
-	s.IDGenerator = f.IDGenerator
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
 	ctx := context.Background()
 	for _, u := range f.Users {
-		if err := s.PutUser(ctx, u); err != nil {
+		if err := c.PutUser(ctx, u); err != nil {
 			t.Fatalf("failed to populate users")
 		}
 	}
-	return s, func() {}
+	return c, func() {
+		defer closeFn()
+		for _, u := range f.Users {
+			if err := c.DeleteUser(ctx, u.ID); err != nil {
+				t.Logf("failed to remove users: %v", err)
+			}
+		}
+	}
 }
 
 func TestBasicAuth(t *testing.T) {
@@ -1,4 +1,4 @@
-package bolt
+package kv
 
 import (
 	"context"
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 	platformcontext "github.com/influxdata/influxdb/context"
 )
@@ -16,20 +15,20 @@ var (
 	bucketIndex  = []byte("bucketindexv1")
 )
 
-var _ platform.BucketService = (*Client)(nil)
-var _ platform.BucketOperationLogService = (*Client)(nil)
+var _ platform.BucketService = (*Service)(nil)
+var _ platform.BucketOperationLogService = (*Service)(nil)
 
-func (c *Client) initializeBuckets(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(bucketBucket)); err != nil {
+func (c *Service) initializeBuckets(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(bucketBucket)); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists([]byte(bucketIndex)); err != nil {
+	if _, err := tx.Bucket([]byte(bucketIndex)); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *Client) setOrganizationOnBucket(ctx context.Context, tx *bolt.Tx, b *platform.Bucket) *platform.Error {
+func (c *Service) setOrganizationOnBucket(ctx context.Context, tx Tx, b *platform.Bucket) *platform.Error {
 	o, err := c.findOrganizationByID(ctx, tx, b.OrganizationID)
 	if err != nil {
 		return &platform.Error{
@@ -41,11 +40,11 @@ func (c *Client) setOrganizationOnBucket(ctx context.Context, tx *bolt.Tx, b *pl
 }
 
 // FindBucketByID retrieves a bucket by id.
-func (c *Client) FindBucketByID(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+func (c *Service) FindBucketByID(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
 	var b *platform.Bucket
 	var err error
 
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		bkt, pe := c.findBucketByID(ctx, tx, id)
 		if pe != nil {
 			pe.Op = getOp(platform.OpFindBucketByID)
@@ -63,7 +62,7 @@ func (c *Client) FindBucketByID(ctx context.Context, id platform.ID) (*platform.
 	return b, nil
 }
 
-func (c *Client) findBucketByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Bucket, *platform.Error) {
+func (c *Service) findBucketByID(ctx context.Context, tx Tx, id platform.ID) (*platform.Bucket, *platform.Error) {
 	var b platform.Bucket
 
 	encodedID, err := id.Encode()
@@ -74,13 +73,25 @@ func (c *Client) findBucketByID(ctx context.Context, tx *bolt.Tx, id platform.ID
 		}
 	}
 
-	v := tx.Bucket(bucketBucket).Get(encodedID)
-	if len(v) == 0 {
+	bkt, err := tx.Bucket(bucketBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := bkt.Get(encodedID)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "bucket not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	if err := json.Unmarshal(v, &b); err != nil {
 		return nil, &platform.Error{
@@ -99,11 +110,11 @@ func (c *Client) findBucketByID(ctx context.Context, tx *bolt.Tx, id platform.ID
 
 // FindBucketByName returns a bucket by name for a particular organization.
 // TODO: have method for finding bucket using organization name and bucket name.
-func (c *Client) FindBucketByName(ctx context.Context, orgID platform.ID, n string) (*platform.Bucket, error) {
+func (c *Service) FindBucketByName(ctx context.Context, orgID platform.ID, n string) (*platform.Bucket, error) {
 	var b *platform.Bucket
 	var err error
 
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		bkt, pe := c.findBucketByName(ctx, tx, orgID, n)
 		if pe != nil {
 			pe.Op = getOp(platform.OpFindBucket)
@@ -117,26 +128,38 @@ func (c *Client) FindBucketByName(ctx context.Context, orgID platform.ID, n stri
 	return b, err
 }
 
-func (c *Client) findBucketByName(ctx context.Context, tx *bolt.Tx, orgID platform.ID, n string) (*platform.Bucket, *platform.Error) {
+func (c *Service) findBucketByName(ctx context.Context, tx Tx, orgID platform.ID, n string) (*platform.Bucket, *platform.Error) {
 	b := &platform.Bucket{
 		OrganizationID: orgID,
 		Name:           n,
 	}
-	key, err := bucketIndexKey(b)
-	if err != nil {
+	key, pe := bucketIndexKey(b)
+	if pe != nil {
 		return nil, &platform.Error{
 			Code: platform.EInvalid,
-			Err:  err,
+			Err:  pe,
 		}
 	}
 
-	buf := tx.Bucket(bucketIndex).Get(key)
-	if buf == nil {
+	idx, err := tx.Bucket(bucketIndex)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	buf, err := idx.Get(key)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "bucket not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	var id platform.ID
 	if err := id.Decode(buf); err != nil {
@@ -150,7 +173,7 @@ func (c *Client) findBucketByName(ctx context.Context, tx *bolt.Tx, orgID platfo
 // FindBucket retrives a bucket using an arbitrary bucket filter.
 // Filters using ID, or OrganizationID and bucket Name should be efficient.
 // Other filters will do a linear scan across buckets until it finds a match.
-func (c *Client) FindBucket(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+func (c *Service) FindBucket(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
 	var b *platform.Bucket
 	var err error
 
@@ -169,7 +192,7 @@ func (c *Client) FindBucket(ctx context.Context, filter platform.BucketFilter) (
 		return c.FindBucketByName(ctx, *filter.OrganizationID, *filter.Name)
 	}
 
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		if filter.Organization != nil {
 			o, err := c.findOrganizationByName(ctx, tx, *filter.Organization)
 			if err != nil {
@@ -236,7 +259,7 @@ func filterBucketsFn(filter platform.BucketFilter) func(b *platform.Bucket) bool
 // FindBuckets retrives all buckets that match an arbitrary bucket filter.
 // Filters using ID, or OrganizationID and bucket Name should be efficient.
 // Other filters will do a linear scan across all buckets searching for a match.
-func (c *Client) FindBuckets(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+func (c *Service) FindBuckets(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
 	if filter.ID != nil {
 		b, err := c.FindBucketByID(ctx, *filter.ID)
 		if err != nil {
@@ -256,7 +279,7 @@ func (c *Client) FindBuckets(ctx context.Context, filter platform.BucketFilter,
 	}
 
 	bs := []*platform.Bucket{}
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		bkts, err := c.findBuckets(ctx, tx, filter, opts...)
 		if err != nil {
 			return err
@@ -272,7 +295,7 @@ func (c *Client) FindBuckets(ctx context.Context, filter platform.BucketFilter,
 	return bs, len(bs), nil
 }
 
-func (c *Client) findBuckets(ctx context.Context, tx *bolt.Tx, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, *platform.Error) {
+func (c *Service) findBuckets(ctx context.Context, tx Tx, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, *platform.Error) {
 	bs := []*platform.Bucket{}
 	if filter.Organization != nil {
 		o, err := c.findOrganizationByName(ctx, tx, *filter.Organization)
@@ -318,10 +341,10 @@ func (c *Client) findBuckets(ctx context.Context, tx *bolt.Tx, filter platform.B
 }
 
 // CreateBucket creates a platform bucket and sets b.ID.
-func (c *Client) CreateBucket(ctx context.Context, b *platform.Bucket) error {
+func (c *Service) CreateBucket(ctx context.Context, b *platform.Bucket) error {
 	var err error
 	op := getOp(platform.OpCreateBucket)
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		if b.OrganizationID.Valid() {
 			_, pe := c.findOrganizationByID(ctx, tx, b.OrganizationID)
 			if pe != nil {
@@ -375,8 +398,8 @@ func (c *Client) CreateBucket(ctx context.Context, b *platform.Bucket) error {
 }
 
 // PutBucket will put a bucket without setting an ID.
-func (c *Client) PutBucket(ctx context.Context, b *platform.Bucket) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutBucket(ctx context.Context, b *platform.Bucket) error {
+	return c.kv.Update(func(tx Tx) error {
 		var err error
 		pe := c.putBucket(ctx, tx, b)
 		if pe != nil {
@@ -386,7 +409,7 @@ func (c *Client) PutBucket(ctx context.Context, b *platform.Bucket) error {
 	})
 }
 
-func (c *Client) createBucketUserResourceMappings(ctx context.Context, tx *bolt.Tx, b *platform.Bucket) *platform.Error {
+func (c *Service) createBucketUserResourceMappings(ctx context.Context, tx Tx, b *platform.Bucket) *platform.Error {
 	ms, err := c.findUserResourceMappings(ctx, tx, platform.UserResourceMappingFilter{
 		ResourceType: platform.OrgsResourceType,
 		ResourceID:   b.OrganizationID,
@@ -413,7 +436,7 @@ func (c *Client) createBucketUserResourceMappings(ctx context.Context, tx *bolt.
 	return nil
 }
 
-func (c *Client) putBucket(ctx context.Context, tx *bolt.Tx, b *platform.Bucket) *platform.Error {
+func (c *Service) putBucket(ctx context.Context, tx Tx, b *platform.Bucket) *platform.Error {
 	b.Organization = ""
 	v, err := json.Marshal(b)
 	if err != nil {
@@ -433,12 +456,26 @@ func (c *Client) putBucket(ctx context.Context, tx *bolt.Tx, b *platform.Bucket)
 		return pe
 	}
 
-	if err := tx.Bucket(bucketIndex).Put(key, encodedID); err != nil {
+	idx, err := tx.Bucket(bucketIndex)
+	if err != nil {
 		return &platform.Error{
 			Err: err,
 		}
 	}
-	if err := tx.Bucket(bucketBucket).Put(encodedID, v); err != nil {
+
+	if err := idx.Put(key, encodedID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	bkt, err := tx.Bucket(bucketBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := bkt.Put(encodedID, v); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -461,8 +498,16 @@ func bucketIndexKey(b *platform.Bucket) ([]byte, *platform.Error) {
 }
 
 // forEachBucket will iterate through all buckets while fn returns true.
-func (c *Client) forEachBucket(ctx context.Context, tx *bolt.Tx, descending bool, fn func(*platform.Bucket) bool) error {
-	cur := tx.Bucket(bucketBucket).Cursor()
+func (c *Service) forEachBucket(ctx context.Context, tx Tx, descending bool, fn func(*platform.Bucket) bool) error {
+	b, err := tx.Bucket(bucketBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 
 	var k, v []byte
 	if descending {
@@ -493,19 +538,27 @@ func (c *Client) forEachBucket(ctx context.Context, tx *bolt.Tx, descending bool
 	return nil
 }
 
-func (c *Client) uniqueBucketName(ctx context.Context, tx *bolt.Tx, b *platform.Bucket) bool {
-	key, err := bucketIndexKey(b)
+func (c *Service) uniqueBucketName(ctx context.Context, tx Tx, b *platform.Bucket) bool {
+	key, pe := bucketIndexKey(b)
+	if pe != nil {
+		return false
+	}
+	idx, err := tx.Bucket(bucketIndex)
 	if err != nil {
 		return false
 	}
-	v := tx.Bucket(bucketIndex).Get(key)
+
+	v, err := idx.Get(key)
+	if err != nil && err != ErrKeyNotFound {
+		return false
+	}
 	return len(v) == 0
 }
 
 // UpdateBucket updates a bucket according the parameters set on upd.
-func (c *Client) UpdateBucket(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+func (c *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
 	var b *platform.Bucket
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		bkt, err := c.updateBucket(ctx, tx, id, upd)
 		if err != nil {
 			return err
@@ -517,7 +570,7 @@ func (c *Client) UpdateBucket(ctx context.Context, id platform.ID, upd platform.
 	return b, err
 }
 
-func (c *Client) updateBucket(ctx context.Context, tx *bolt.Tx, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+func (c *Service) updateBucket(ctx context.Context, tx Tx, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
 	b, err := c.findBucketByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
@@ -528,12 +581,17 @@ func (c *Client) updateBucket(ctx context.Context, tx *bolt.Tx, id platform.ID,
 	}
 
 	if upd.Name != nil {
-		key, err := bucketIndexKey(b)
+		key, pe := bucketIndexKey(b)
+		if pe != nil {
+			return nil, pe
+		}
+		// Buckets are indexed by name and so the bucket index must be pruned when name is modified.
+		idx, err := tx.Bucket(bucketIndex)
 		if err != nil {
 			return nil, err
 		}
-		// Buckets are indexed by name and so the bucket index must be pruned when name is modified.
-		if err := tx.Bucket(bucketIndex).Delete(key); err != nil {
+
+		if err := idx.Delete(key); err != nil {
 			return nil, err
 		}
 		b.Name = *upd.Name
@@ -555,8 +613,8 @@ func (c *Client) updateBucket(ctx context.Context, tx *bolt.Tx, id platform.ID,
 }
 
 // DeleteBucket deletes a bucket and prunes it from the index.
-func (c *Client) DeleteBucket(ctx context.Context, id platform.ID) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteBucket(ctx context.Context, id platform.ID) error {
+	return c.kv.Update(func(tx Tx) error {
 		var err error
 		if pe := c.deleteBucket(ctx, tx, id); pe != nil {
 			pe.Op = getOp(platform.OpDeleteBucket)
@@ -566,7 +624,7 @@ func (c *Client) DeleteBucket(ctx context.Context, id platform.ID) error {
 	})
 }
 
-func (c *Client) deleteBucket(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+func (c *Service) deleteBucket(ctx context.Context, tx Tx, id platform.ID) *platform.Error {
 	b, pe := c.findBucketByID(ctx, tx, id)
 	if pe != nil {
 		return pe
@@ -576,7 +634,14 @@ func (c *Client) deleteBucket(ctx context.Context, tx *bolt.Tx, id platform.ID)
 		return pe
 	}
 	// make lowercase deleteBucket with tx
-	if err := tx.Bucket(bucketIndex).Delete(key); err != nil {
+	idx, err := tx.Bucket(bucketIndex)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := idx.Delete(key); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -588,7 +653,14 @@ func (c *Client) deleteBucket(ctx context.Context, tx *bolt.Tx, id platform.ID)
 			Err:  err,
 		}
 	}
-	if err := tx.Bucket(bucketBucket).Delete(encodedID); err != nil {
+	bkt, err := tx.Bucket(bucketBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := bkt.Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -621,11 +693,11 @@ func encodeBucketOperationLogKey(id platform.ID) ([]byte, error) {
 }
 
 // GetBucketOperationLog retrieves a buckets operation log.
-func (c *Client) GetBucketOperationLog(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.OperationLogEntry, int, error) {
+func (c *Service) GetBucketOperationLog(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.OperationLogEntry, int, error) {
 	// TODO(desa): might be worthwhile to allocate a slice of size opts.Limit
 	log := []*platform.OperationLogEntry{}
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		key, err := encodeBucketOperationLogKey(id)
 		if err != nil {
 			return err
@@ -657,7 +729,7 @@ const (
 	bucketUpdatedEvent = "Bucket Updated"
 )
 
-func (c *Client) appendBucketEventToLog(ctx context.Context, tx *bolt.Tx, id platform.ID, s string) error {
+func (c *Service) appendBucketEventToLog(ctx context.Context, tx Tx, id platform.ID, s string) error {
 	e := &platform.OperationLogEntry{
 		Description: s,
 	}
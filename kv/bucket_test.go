@@ -0,0 +1,46 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func initBucketService(f platformtesting.BucketFields, t *testing.T) (platform.BucketService, string, func()) {
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
+	ctx := context.TODO()
+	for _, o := range f.Organizations {
+		if err := c.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, b := range f.Buckets {
+		if err := c.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets")
+		}
+	}
+	return c, kv.OpPrefix, func() {
+		defer closeFn()
+		for _, o := range f.Organizations {
+			if err := c.DeleteOrganization(ctx, o.ID); err != nil {
+				t.Logf("failed to remove organization: %v", err)
+			}
+		}
+		for _, b := range f.Buckets {
+			if err := c.DeleteBucket(ctx, b.ID); err != nil {
+				t.Logf("failed to remove bucket: %v", err)
+			}
+		}
+	}
+}
+
+func TestBucketService(t *testing.T) {
+	platformtesting.BucketService(initBucketService, t)
+}
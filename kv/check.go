@@ -0,0 +1,240 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var (
+	checkBucket = []byte("checksv1")
+)
+
+var _ platform.CheckService = (*Service)(nil)
+
+func (c *Service) initializeChecks(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(checkBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindCheckByID returns a single check by ID.
+func (c *Service) FindCheckByID(ctx context.Context, id platform.ID) (*platform.Check, error) {
+	var check *platform.Check
+	err := c.kv.View(func(tx Tx) error {
+		chk, pe := c.findCheckByID(ctx, tx, id)
+		if pe != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpFindCheckByID),
+				Err: pe,
+			}
+		}
+		check = chk
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+func (c *Service) findCheckByID(ctx context.Context, tx Tx, id platform.ID) (*platform.Check, *platform.Error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(checkBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encID)
+	if err == ErrKeyNotFound {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrCheckNotFound,
+		}
+	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	check := new(platform.Check)
+	if err := json.Unmarshal(v, check); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+	return check, nil
+}
+
+// FindChecks returns a list of checks that match filter and the total count of matching checks.
+func (c *Service) FindChecks(ctx context.Context, filter platform.CheckFilter, opt ...platform.FindOptions) ([]*platform.Check, int, error) {
+	op := getOp(platform.OpFindChecks)
+	checks := []*platform.Check{}
+	err := c.kv.View(func(tx Tx) error {
+		if filter.Organization != nil {
+			o, err := c.findOrganizationByName(ctx, tx, *filter.Organization)
+			if err != nil {
+				return err
+			}
+			filter.OrganizationID = &o.ID
+		}
+
+		b, err := tx.Bucket(checkBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			check := new(platform.Check)
+			if err := json.Unmarshal(v, check); err != nil {
+				return err
+			}
+			if filter.ID != nil && check.ID != *filter.ID {
+				continue
+			}
+			if filter.OrganizationID != nil && check.OrganizationID != *filter.OrganizationID {
+				continue
+			}
+			checks = append(checks, check)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return checks, len(checks), nil
+}
+
+// CreateCheck creates a new check and sets check.ID with the new identifier.
+func (c *Service) CreateCheck(ctx context.Context, check *platform.Check) error {
+	op := getOp(platform.OpCreateCheck)
+	if !check.OrganizationID.Valid() {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "org id is invalid",
+			Op:   op,
+		}
+	}
+	if err := check.Valid(); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	if check.Status == "" {
+		check.Status = platform.CheckStatusActive
+	}
+
+	err := c.kv.Update(func(tx Tx) error {
+		check.ID = c.IDGenerator.ID()
+		return c.putCheck(ctx, tx, check)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// PutCheck will put a check without setting an ID.
+func (c *Service) PutCheck(ctx context.Context, check *platform.Check) error {
+	return c.kv.Update(func(tx Tx) error {
+		return c.putCheck(ctx, tx, check)
+	})
+}
+
+func (c *Service) putCheck(ctx context.Context, tx Tx, check *platform.Check) error {
+	v, err := json.Marshal(check)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	encID, err := check.ID.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	b, err := tx.Bucket(checkBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encID, v)
+}
+
+// UpdateCheck updates a single check with changeset.
+func (c *Service) UpdateCheck(ctx context.Context, id platform.ID, upd platform.CheckUpdate) (*platform.Check, error) {
+	op := getOp(platform.OpUpdateCheck)
+	var check *platform.Check
+	err := c.kv.Update(func(tx Tx) error {
+		chk, pe := c.findCheckByID(ctx, tx, id)
+		if pe != nil {
+			return pe
+		}
+		if err := upd.Apply(chk); err != nil {
+			return err
+		}
+		check = chk
+		return c.putCheck(ctx, tx, check)
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return check, nil
+}
+
+// DeleteCheck removes a check by ID.
+func (c *Service) DeleteCheck(ctx context.Context, id platform.ID) error {
+	err := c.kv.Update(func(tx Tx) error {
+		if _, pe := c.findCheckByID(ctx, tx, id); pe != nil {
+			return pe
+		}
+		encID, err := id.Encode()
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Err:  err,
+			}
+		}
+		b, err := tx.Bucket(checkBucket)
+		if err != nil {
+			return err
+		}
+
+		return b.Delete(encID)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  getOp(platform.OpDeleteCheck),
+			Err: err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func initCheckService(f platformtesting.CheckFields, t *testing.T) (platform.CheckService, string, func()) {
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+
+	c.IDGenerator = f.IDGenerator
+	ctx := context.TODO()
+
+	for _, check := range f.Checks {
+		if err := c.PutCheck(ctx, check); err != nil {
+			t.Fatalf("failed to populate test checks: %v", err)
+		}
+	}
+
+	done := func() {
+		defer closeFn()
+
+		for _, check := range f.Checks {
+			if err := c.DeleteCheck(ctx, check.ID); err != nil {
+				t.Logf("failed to clean up checks kv test: %v", err)
+			}
+		}
+	}
+
+	return c, kv.OpPrefix, done
+}
+
+func TestCheckService(t *testing.T) {
+	t.Parallel()
+	platformtesting.CheckService(initCheckService, t)
+}
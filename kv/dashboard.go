@@ -1,4 +1,4 @@
-package bolt
+package kv
 
 import (
 	"bytes"
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 	platformcontext "github.com/influxdata/influxdb/context"
 )
@@ -28,27 +27,27 @@ const (
 	dashboardCellUpdatedEvent   = "Dashboard Cell Updated"
 )
 
-var _ platform.DashboardService = (*Client)(nil)
-var _ platform.DashboardOperationLogService = (*Client)(nil)
+var _ platform.DashboardService = (*Service)(nil)
+var _ platform.DashboardOperationLogService = (*Service)(nil)
 
-func (c *Client) initializeDashboards(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists(dashboardBucket); err != nil {
+func (c *Service) initializeDashboards(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(dashboardBucket); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists(orgDashboardIndex); err != nil {
+	if _, err := tx.Bucket(orgDashboardIndex); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists(dashboardCellViewBucket); err != nil {
+	if _, err := tx.Bucket(dashboardCellViewBucket); err != nil {
 		return err
 	}
 	return nil
 }
 
 // FindDashboardByID retrieves a dashboard by id.
-func (c *Client) FindDashboardByID(ctx context.Context, id platform.ID) (*platform.Dashboard, error) {
+func (c *Service) FindDashboardByID(ctx context.Context, id platform.ID) (*platform.Dashboard, error) {
 	var d *platform.Dashboard
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		dash, err := c.findDashboardByID(ctx, tx, id)
 		if err != nil {
 			return err
@@ -67,7 +66,7 @@ func (c *Client) FindDashboardByID(ctx context.Context, id platform.ID) (*platfo
 	return d, nil
 }
 
-func (c *Client) findDashboardByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Dashboard, error) {
+func (c *Service) findDashboardByID(ctx context.Context, tx Tx, id platform.ID) (*platform.Dashboard, error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &platform.Error{
@@ -75,14 +74,22 @@ func (c *Client) findDashboardByID(ctx context.Context, tx *bolt.Tx, id platform
 		}
 	}
 
-	v := tx.Bucket(dashboardBucket).Get(encodedID)
-	if len(v) == 0 {
+	b, err := tx.Bucket(dashboardBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  platform.ErrDashboardNotFound,
 		}
 
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	var d platform.Dashboard
 	if err := json.Unmarshal(v, &d); err != nil {
@@ -95,13 +102,13 @@ func (c *Client) findDashboardByID(ctx context.Context, tx *bolt.Tx, id platform
 }
 
 // FindDashboard retrieves a dashboard using an arbitrary dashboard filter.
-func (c *Client) FindDashboard(ctx context.Context, filter platform.DashboardFilter, opts ...platform.FindOptions) (*platform.Dashboard, error) {
+func (c *Service) FindDashboard(ctx context.Context, filter platform.DashboardFilter, opts ...platform.FindOptions) (*platform.Dashboard, error) {
 	if len(filter.IDs) == 1 {
 		return c.FindDashboardByID(ctx, *filter.IDs[0])
 	}
 
 	var d *platform.Dashboard
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		filterFn := filterDashboardsFn(filter)
 		return c.forEachDashboard(ctx, tx, opts[0].Descending, func(dash *platform.Dashboard) bool {
 			if filterFn(dash) {
@@ -142,7 +149,7 @@ func filterDashboardsFn(filter platform.DashboardFilter) func(d *platform.Dashbo
 }
 
 // FindDashboards retrives all dashboards that match an arbitrary dashboard filter.
-func (c *Client) FindDashboards(ctx context.Context, filter platform.DashboardFilter, opts platform.FindOptions) ([]*platform.Dashboard, int, error) {
+func (c *Service) FindDashboards(ctx context.Context, filter platform.DashboardFilter, opts platform.FindOptions) ([]*platform.Dashboard, int, error) {
 	ds := []*platform.Dashboard{}
 	if len(filter.IDs) == 1 {
 		d, err := c.FindDashboardByID(ctx, *filter.IDs[0])
@@ -157,7 +164,7 @@ func (c *Client) FindDashboards(ctx context.Context, filter platform.DashboardFi
 		}
 		return []*platform.Dashboard{d}, 1, nil
 	}
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		dashs, err := c.findDashboards(ctx, tx, filter, opts)
 		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
 			return err
@@ -178,9 +185,17 @@ func (c *Client) FindDashboards(ctx context.Context, filter platform.DashboardFi
 	return ds, len(ds), nil
 }
 
-func (c *Client) findOrganizationDashboards(ctx context.Context, tx *bolt.Tx, orgID platform.ID) ([]*platform.Dashboard, error) {
+func (c *Service) findOrganizationDashboards(ctx context.Context, tx Tx, orgID platform.ID) ([]*platform.Dashboard, error) {
 	// TODO(desa): support find options.
-	cur := tx.Bucket(orgDashboardIndex).Cursor()
+	idx, err := tx.Bucket(orgDashboardIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := idx.Cursor()
+	if err != nil {
+		return nil, err
+	}
 	prefix, err := orgID.Encode()
 	if err != nil {
 		return nil, err
@@ -220,7 +235,7 @@ func decodeOrgDashboardIndexKey(indexKey []byte) (orgID platform.ID, dashID plat
 	return orgID, dashID, nil
 }
 
-func (c *Client) findDashboards(ctx context.Context, tx *bolt.Tx, filter platform.DashboardFilter, opts ...platform.FindOptions) ([]*platform.Dashboard, error) {
+func (c *Service) findDashboards(ctx context.Context, tx Tx, filter platform.DashboardFilter, opts ...platform.FindOptions) ([]*platform.Dashboard, error) {
 	if filter.OrganizationID != nil {
 		return c.findOrganizationDashboards(ctx, tx, *filter.OrganizationID)
 	}
@@ -264,8 +279,8 @@ func (c *Client) findDashboards(ctx context.Context, tx *bolt.Tx, filter platfor
 }
 
 // CreateDashboard creates a platform dashboard and sets d.ID.
-func (c *Client) CreateDashboard(ctx context.Context, d *platform.Dashboard) error {
-	err := c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) CreateDashboard(ctx context.Context, d *platform.Dashboard) error {
+	err := c.kv.Update(func(tx Tx) error {
 		d.ID = c.IDGenerator.ID()
 
 		for _, cell := range d.Cells {
@@ -298,7 +313,7 @@ func (c *Client) CreateDashboard(ctx context.Context, d *platform.Dashboard) err
 	return nil
 }
 
-func (c *Client) createCellView(ctx context.Context, tx *bolt.Tx, dashID, cellID platform.ID, view *platform.View) error {
+func (c *Service) createCellView(ctx context.Context, tx Tx, dashID, cellID platform.ID, view *platform.View) error {
 	if view == nil {
 		// If not view exists create the view
 		view = &platform.View{}
@@ -309,8 +324,8 @@ func (c *Client) createCellView(ctx context.Context, tx *bolt.Tx, dashID, cellID
 }
 
 // ReplaceDashboardCells updates the positions of each cell in a dashboard concurrently.
-func (c *Client) ReplaceDashboardCells(ctx context.Context, id platform.ID, cs []*platform.Cell) error {
-	err := c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) ReplaceDashboardCells(ctx context.Context, id platform.ID, cs []*platform.Cell) error {
+	err := c.kv.Update(func(tx Tx) error {
 		d, err := c.findDashboardByID(ctx, tx, id)
 		if err != nil {
 			return err
@@ -353,7 +368,7 @@ func (c *Client) ReplaceDashboardCells(ctx context.Context, id platform.ID, cs [
 	return nil
 }
 
-func (c *Client) addDashboardCell(ctx context.Context, tx *bolt.Tx, id platform.ID, cell *platform.Cell, opts platform.AddDashboardCellOptions) error {
+func (c *Service) addDashboardCell(ctx context.Context, tx Tx, id platform.ID, cell *platform.Cell, opts platform.AddDashboardCellOptions) error {
 	d, err := c.findDashboardByID(ctx, tx, id)
 	if err != nil {
 		return err
@@ -373,8 +388,8 @@ func (c *Client) addDashboardCell(ctx context.Context, tx *bolt.Tx, id platform.
 }
 
 // AddDashboardCell adds a cell to a dashboard and sets the cells ID.
-func (c *Client) AddDashboardCell(ctx context.Context, id platform.ID, cell *platform.Cell, opts platform.AddDashboardCellOptions) error {
-	err := c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) AddDashboardCell(ctx context.Context, id platform.ID, cell *platform.Cell, opts platform.AddDashboardCellOptions) error {
+	err := c.kv.Update(func(tx Tx) error {
 		return c.addDashboardCell(ctx, tx, id, cell, opts)
 	})
 	if err != nil {
@@ -387,9 +402,9 @@ func (c *Client) AddDashboardCell(ctx context.Context, id platform.ID, cell *pla
 }
 
 // RemoveDashboardCell removes a cell from a dashboard.
-func (c *Client) RemoveDashboardCell(ctx context.Context, dashboardID, cellID platform.ID) error {
+func (c *Service) RemoveDashboardCell(ctx context.Context, dashboardID, cellID platform.ID) error {
 	op := getOp(platform.OpRemoveDashboardCell)
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		d, err := c.findDashboardByID(ctx, tx, dashboardID)
 		if err != nil {
 			return &platform.Error{
@@ -440,9 +455,9 @@ func (c *Client) RemoveDashboardCell(ctx context.Context, dashboardID, cellID pl
 }
 
 // GetDashboardCellView retrieves the view for a dashboard cell.
-func (c *Client) GetDashboardCellView(ctx context.Context, dashboardID, cellID platform.ID) (*platform.View, error) {
+func (c *Service) GetDashboardCellView(ctx context.Context, dashboardID, cellID platform.ID) (*platform.View, error) {
 	var v *platform.View
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		view, err := c.findDashboardCellView(ctx, tx, dashboardID, cellID)
 		if err != nil {
 			return err
@@ -462,15 +477,23 @@ func (c *Client) GetDashboardCellView(ctx context.Context, dashboardID, cellID p
 	return v, nil
 }
 
-func (c *Client) findDashboardCellView(ctx context.Context, tx *bolt.Tx, dashboardID, cellID platform.ID) (*platform.View, error) {
+func (c *Service) findDashboardCellView(ctx context.Context, tx Tx, dashboardID, cellID platform.ID) (*platform.View, error) {
 	k, err := encodeDashboardCellViewID(dashboardID, cellID)
 	if err != nil {
 		return nil, platform.NewError(platform.WithErrorErr(err))
 	}
-	v := tx.Bucket(dashboardCellViewBucket).Get(k)
-	if len(v) == 0 {
+	b, err := tx.Bucket(dashboardCellViewBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if err == ErrKeyNotFound {
 		return nil, platform.NewError(platform.WithErrorCode(platform.ENotFound), platform.WithErrorMsg(platform.ErrViewNotFound))
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	view := &platform.View{}
 	if err := json.Unmarshal(v, view); err != nil {
@@ -480,20 +503,25 @@ func (c *Client) findDashboardCellView(ctx context.Context, tx *bolt.Tx, dashboa
 	return view, nil
 }
 
-func (c *Client) deleteDashboardCellView(ctx context.Context, tx *bolt.Tx, dashboardID, cellID platform.ID) error {
+func (c *Service) deleteDashboardCellView(ctx context.Context, tx Tx, dashboardID, cellID platform.ID) error {
 	k, err := encodeDashboardCellViewID(dashboardID, cellID)
 	if err != nil {
 		return platform.NewError(platform.WithErrorErr(err))
 	}
 
-	if err := tx.Bucket(dashboardCellViewBucket).Delete(k); err != nil {
+	b, err := tx.Bucket(dashboardCellViewBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Delete(k); err != nil {
 		return platform.NewError(platform.WithErrorErr(err))
 	}
 
 	return nil
 }
 
-func (c *Client) putDashboardCellView(ctx context.Context, tx *bolt.Tx, dashboardID, cellID platform.ID, view *platform.View) error {
+func (c *Service) putDashboardCellView(ctx context.Context, tx Tx, dashboardID, cellID platform.ID, view *platform.View) error {
 	k, err := encodeDashboardCellViewID(dashboardID, cellID)
 	if err != nil {
 		return platform.NewError(platform.WithErrorErr(err))
@@ -504,7 +532,12 @@ func (c *Client) putDashboardCellView(ctx context.Context, tx *bolt.Tx, dashboar
 		return platform.NewError(platform.WithErrorErr(err))
 	}
 
-	if err := tx.Bucket(dashboardCellViewBucket).Put(k, v); err != nil {
+	b, err := tx.Bucket(dashboardCellViewBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(k, v); err != nil {
 		return platform.NewError(platform.WithErrorErr(err))
 	}
 
@@ -535,10 +568,10 @@ func encodeDashboardCellViewID(dashID, cellID platform.ID) ([]byte, error) {
 }
 
 // UpdateDashboardCellView updates the view for a dashboard cell.
-func (c *Client) UpdateDashboardCellView(ctx context.Context, dashboardID, cellID platform.ID, upd platform.ViewUpdate) (*platform.View, error) {
+func (c *Service) UpdateDashboardCellView(ctx context.Context, dashboardID, cellID platform.ID, upd platform.ViewUpdate) (*platform.View, error) {
 	var v *platform.View
 
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		view, err := c.findDashboardCellView(ctx, tx, dashboardID, cellID)
 		if err != nil {
 			return err
@@ -567,7 +600,7 @@ func (c *Client) UpdateDashboardCellView(ctx context.Context, dashboardID, cellI
 }
 
 // UpdateDashboardCell udpates a cell on a dashboard.
-func (c *Client) UpdateDashboardCell(ctx context.Context, dashboardID, cellID platform.ID, upd platform.CellUpdate) (*platform.Cell, error) {
+func (c *Service) UpdateDashboardCell(ctx context.Context, dashboardID, cellID platform.ID, upd platform.CellUpdate) (*platform.Cell, error) {
 	op := getOp(platform.OpUpdateDashboardCell)
 	if err := upd.Valid(); err != nil {
 		return nil, &platform.Error{
@@ -577,7 +610,7 @@ func (c *Client) UpdateDashboardCell(ctx context.Context, dashboardID, cellID pl
 	}
 
 	var cell *platform.Cell
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		d, err := c.findDashboardByID(ctx, tx, dashboardID)
 		if err != nil {
 			return err
@@ -622,8 +655,8 @@ func (c *Client) UpdateDashboardCell(ctx context.Context, dashboardID, cellID pl
 }
 
 // PutDashboard will put a dashboard without setting an ID.
-func (c *Client) PutDashboard(ctx context.Context, d *platform.Dashboard) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutDashboard(ctx context.Context, d *platform.Dashboard) error {
+	return c.kv.Update(func(tx Tx) error {
 		for _, cell := range d.Cells {
 			if err := c.createCellView(ctx, tx, d.ID, cell.ID, nil); err != nil {
 				return err
@@ -656,31 +689,41 @@ func encodeOrgDashboardIndex(orgID platform.ID, dashID platform.ID) ([]byte, err
 	return key, nil
 }
 
-func (c *Client) putOrganizationDashboardIndex(ctx context.Context, tx *bolt.Tx, d *platform.Dashboard) error {
+func (c *Service) putOrganizationDashboardIndex(ctx context.Context, tx Tx, d *platform.Dashboard) error {
 	k, err := encodeOrgDashboardIndex(d.OrganizationID, d.ID)
 	if err != nil {
 		return err
 	}
-	if err := tx.Bucket(orgDashboardIndex).Put(k, nil); err != nil {
+	idx, err := tx.Bucket(orgDashboardIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Put(k, nil); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Client) removeOrganizationDashboardIndex(ctx context.Context, tx *bolt.Tx, d *platform.Dashboard) error {
+func (c *Service) removeOrganizationDashboardIndex(ctx context.Context, tx Tx, d *platform.Dashboard) error {
 	k, err := encodeOrgDashboardIndex(d.OrganizationID, d.ID)
 	if err != nil {
 		return err
 	}
-	if err := tx.Bucket(orgDashboardIndex).Delete(k); err != nil {
+	idx, err := tx.Bucket(orgDashboardIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Delete(k); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Client) putDashboard(ctx context.Context, tx *bolt.Tx, d *platform.Dashboard) error {
+func (c *Service) putDashboard(ctx context.Context, tx Tx, d *platform.Dashboard) error {
 	v, err := json.Marshal(d)
 	if err != nil {
 		return err
@@ -689,21 +732,34 @@ func (c *Client) putDashboard(ctx context.Context, tx *bolt.Tx, d *platform.Dash
 	if err != nil {
 		return err
 	}
-	if err := tx.Bucket(dashboardBucket).Put(encodedID, v); err != nil {
+	b, err := tx.Bucket(dashboardBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *Client) putDashboardWithMeta(ctx context.Context, tx *bolt.Tx, d *platform.Dashboard) error {
+func (c *Service) putDashboardWithMeta(ctx context.Context, tx Tx, d *platform.Dashboard) error {
 	// TODO(desa): don't populate this here. use the first/last methods of the oplog to get meta fields.
 	d.Meta.UpdatedAt = c.time()
 	return c.putDashboard(ctx, tx, d)
 }
 
 // forEachDashboard will iterate through all dashboards while fn returns true.
-func (c *Client) forEachDashboard(ctx context.Context, tx *bolt.Tx, descending bool, fn func(*platform.Dashboard) bool) error {
-	cur := tx.Bucket(dashboardBucket).Cursor()
+func (c *Service) forEachDashboard(ctx context.Context, tx Tx, descending bool, fn func(*platform.Dashboard) bool) error {
+	b, err := tx.Bucket(dashboardBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 
 	var k, v []byte
 	if descending {
@@ -733,13 +789,13 @@ func (c *Client) forEachDashboard(ctx context.Context, tx *bolt.Tx, descending b
 }
 
 // UpdateDashboard updates a dashboard according the parameters set on upd.
-func (c *Client) UpdateDashboard(ctx context.Context, id platform.ID, upd platform.DashboardUpdate) (*platform.Dashboard, error) {
+func (c *Service) UpdateDashboard(ctx context.Context, id platform.ID, upd platform.DashboardUpdate) (*platform.Dashboard, error) {
 	if err := upd.Valid(); err != nil {
 		return nil, err
 	}
 
 	var d *platform.Dashboard
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		dash, err := c.updateDashboard(ctx, tx, id, upd)
 		if err != nil {
 			return err
@@ -758,7 +814,7 @@ func (c *Client) UpdateDashboard(ctx context.Context, id platform.ID, upd platfo
 	return d, err
 }
 
-func (c *Client) updateDashboard(ctx context.Context, tx *bolt.Tx, id platform.ID, upd platform.DashboardUpdate) (*platform.Dashboard, error) {
+func (c *Service) updateDashboard(ctx context.Context, tx Tx, id platform.ID, upd platform.DashboardUpdate) (*platform.Dashboard, error) {
 	d, err := c.findDashboardByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
@@ -780,8 +836,8 @@ func (c *Client) updateDashboard(ctx context.Context, tx *bolt.Tx, id platform.I
 }
 
 // DeleteDashboard deletes a dashboard and prunes it from the index.
-func (c *Client) DeleteDashboard(ctx context.Context, id platform.ID) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteDashboard(ctx context.Context, id platform.ID) error {
+	return c.kv.Update(func(tx Tx) error {
 		if pe := c.deleteDashboard(ctx, tx, id); pe != nil {
 			return &platform.Error{
 				Err: pe,
@@ -792,7 +848,7 @@ func (c *Client) DeleteDashboard(ctx context.Context, id platform.ID) error {
 	})
 }
 
-func (c *Client) deleteDashboard(ctx context.Context, tx *bolt.Tx, id platform.ID) error {
+func (c *Service) deleteDashboard(ctx context.Context, tx Tx, id platform.ID) error {
 	d, pe := c.findDashboardByID(ctx, tx, id)
 	if pe != nil {
 		return pe
@@ -817,7 +873,12 @@ func (c *Client) deleteDashboard(ctx context.Context, tx *bolt.Tx, id platform.I
 		return platform.NewError(platform.WithErrorErr(err))
 	}
 
-	if err := tx.Bucket(dashboardBucket).Delete(encodedID); err != nil {
+	b, err := tx.Bucket(dashboardBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -854,11 +915,11 @@ func encodeDashboardOperationLogKey(id platform.ID) ([]byte, error) {
 }
 
 // GetDashboardOperationLog retrieves a dashboards operation log.
-func (c *Client) GetDashboardOperationLog(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.OperationLogEntry, int, error) {
+func (c *Service) GetDashboardOperationLog(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.OperationLogEntry, int, error) {
 	// TODO(desa): might be worthwhile to allocate a slice of size opts.Limit
 	log := []*platform.OperationLogEntry{}
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		key, err := encodeDashboardOperationLogKey(id)
 		if err != nil {
 			return err
@@ -884,7 +945,7 @@ func (c *Client) GetDashboardOperationLog(ctx context.Context, id platform.ID, o
 	return log, len(log), nil
 }
 
-func (c *Client) appendDashboardEventToLog(ctx context.Context, tx *bolt.Tx, id platform.ID, s string) error {
+func (c *Service) appendDashboardEventToLog(ctx context.Context, tx Tx, id platform.ID, s string) error {
 	e := &platform.OperationLogEntry{
 		Description: s,
 	}
@@ -0,0 +1,178 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var (
+	dbrpMappingBucket = []byte("dbrpmappingsv1")
+
+	errDBRPMappingNotFound = errors.New("dbrp mapping not found")
+	errDBRPMappingExists   = errors.New("dbrp mapping already exists")
+)
+
+var _ platform.DBRPMappingService = (*Service)(nil)
+
+func (c *Service) initializeDBRPMappings(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(dbrpMappingBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+func encodeDBRPMappingKey(cluster, db, rp string) []byte {
+	return []byte(path.Join(cluster, db, rp))
+}
+
+func (c *Service) findDBRPMapping(ctx context.Context, tx Tx, cluster, db, rp string) (*platform.DBRPMapping, error) {
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodeDBRPMappingKey(cluster, db, rp))
+	if err == ErrKeyNotFound {
+		return nil, errDBRPMappingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &platform.DBRPMapping{}
+	if err := json.Unmarshal(v, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FindBy returns a single dbrp mapping by cluster, db and rp.
+func (c *Service) FindBy(ctx context.Context, cluster, db, rp string) (*platform.DBRPMapping, error) {
+	var m *platform.DBRPMapping
+	err := c.kv.View(func(tx Tx) error {
+		var err error
+		m, err = c.findDBRPMapping(ctx, tx, cluster, db, rp)
+		return err
+	})
+	return m, err
+}
+
+func (c *Service) forEachDBRPMapping(ctx context.Context, tx Tx, fn func(m *platform.DBRPMapping) bool) error {
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		m := &platform.DBRPMapping{}
+		if err := json.Unmarshal(v, m); err != nil {
+			return err
+		}
+		if !fn(m) {
+			break
+		}
+	}
+	return nil
+}
+
+// Find returns the first dbrp mapping that matches filter.
+func (c *Service) Find(ctx context.Context, filter platform.DBRPMappingFilter) (*platform.DBRPMapping, error) {
+	if filter.Cluster == nil && filter.Database == nil && filter.RetentionPolicy == nil {
+		return nil, fmt.Errorf("no filter parameters provided")
+	}
+
+	mappings, n, err := c.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 {
+		return nil, errDBRPMappingNotFound
+	}
+	return mappings[0], nil
+}
+
+// FindMany returns a list of dbrpMappings that match filter and the total count of matching dbrp mappings.
+func (c *Service) FindMany(ctx context.Context, filter platform.DBRPMappingFilter, opt ...platform.FindOptions) ([]*platform.DBRPMapping, int, error) {
+	// filter by dbrpMapping key
+	if filter.Cluster != nil && filter.Database != nil && filter.RetentionPolicy != nil {
+		m, err := c.FindBy(ctx, *filter.Cluster, *filter.Database, *filter.RetentionPolicy)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*platform.DBRPMapping{m}, 1, nil
+	}
+
+	mappings := []*platform.DBRPMapping{}
+	err := c.kv.View(func(tx Tx) error {
+		return c.forEachDBRPMapping(ctx, tx, func(m *platform.DBRPMapping) bool {
+			if (filter.Cluster == nil || *filter.Cluster == m.Cluster) &&
+				(filter.Database == nil || *filter.Database == m.Database) &&
+				(filter.RetentionPolicy == nil || *filter.RetentionPolicy == m.RetentionPolicy) &&
+				(filter.Default == nil || *filter.Default == m.Default) {
+				mappings = append(mappings, m)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return mappings, len(mappings), nil
+}
+
+// Create creates a new dbrp mapping.  Creating a mapping equal to an existing
+// one is not an error.
+func (c *Service) Create(ctx context.Context, m *platform.DBRPMapping) error {
+	if err := m.Validate(); err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	return c.kv.Update(func(tx Tx) error {
+		existing, err := c.findDBRPMapping(ctx, tx, m.Cluster, m.Database, m.RetentionPolicy)
+		if err != nil && err != errDBRPMappingNotFound {
+			return err
+		}
+		if existing != nil && !existing.Equal(m) {
+			return errDBRPMappingExists
+		}
+		return c.putDBRPMapping(ctx, tx, m)
+	})
+}
+
+func (c *Service) putDBRPMapping(ctx context.Context, tx Tx, m *platform.DBRPMapping) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodeDBRPMappingKey(m.Cluster, m.Database, m.RetentionPolicy), v)
+}
+
+// Delete removes a dbrp mapping.  Deleting a mapping that does not exist is
+// not an error.
+func (c *Service) Delete(ctx context.Context, cluster, db, rp string) error {
+	return c.kv.Update(func(tx Tx) error {
+		b, err := tx.Bucket(dbrpMappingBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(encodeDBRPMappingKey(cluster, db, rp))
+	})
+}
@@ -0,0 +1,40 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func initDBRPMappingService(f platformtesting.DBRPMappingFields, t *testing.T) (platform.DBRPMappingService, func()) {
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	if err := f.Populate(context.TODO(), c); err != nil {
+		t.Fatal(err)
+	}
+	return c, closeFn
+}
+
+func TestDBRPMappingService_CreateDBRPMapping(t *testing.T) {
+	platformtesting.CreateDBRPMapping(initDBRPMappingService, t)
+}
+
+func TestDBRPMappingService_FindDBRPMappingByKey(t *testing.T) {
+	platformtesting.FindDBRPMappingByKey(initDBRPMappingService, t)
+}
+
+func TestDBRPMappingService_FindDBRPMappings(t *testing.T) {
+	platformtesting.FindDBRPMappings(initDBRPMappingService, t)
+}
+
+func TestDBRPMappingService_DeleteDBRPMapping(t *testing.T) {
+	platformtesting.DeleteDBRPMapping(initDBRPMappingService, t)
+}
+
+func TestDBRPMappingService_FindDBRPMapping(t *testing.T) {
+	platformtesting.FindDBRPMapping(initDBRPMappingService, t)
+}
@@ -1,4 +1,4 @@
-package bolt
+package kv
 
 import (
 	"bytes"
@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -18,7 +17,7 @@ var (
 	keyValueLogIndex  = []byte("keyvaluelogindex/v1")
 )
 
-var _ platform.KeyValueLog = (*Client)(nil)
+var _ platform.KeyValueLog = (*Service)(nil)
 
 type keyValueLogBounds struct {
 	Start int64 `json:"start"`
@@ -99,11 +98,11 @@ func encodeKeyValueIndexKey(k []byte) []byte {
 	return h.Sum(nil)
 }
 
-func (c *Client) initializeKeyValueLog(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(keyValueLogBucket)); err != nil {
+func (c *Service) initializeKeyValueLog(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(keyValueLogBucket)); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists([]byte(keyValueLogIndex)); err != nil {
+	if _, err := tx.Bucket([]byte(keyValueLogIndex)); err != nil {
 		return err
 	}
 	return nil
@@ -111,14 +110,22 @@ func (c *Client) initializeKeyValueLog(ctx context.Context, tx *bolt.Tx) error {
 
 var errKeyValueLogBoundsNotFound = fmt.Errorf("oplog not found")
 
-func (c *Client) getKeyValueLogBounds(ctx context.Context, tx *bolt.Tx, key []byte) (*keyValueLogBounds, error) {
+func (c *Service) getKeyValueLogBounds(ctx context.Context, tx Tx, key []byte) (*keyValueLogBounds, error) {
 	k := encodeKeyValueIndexKey(key)
 
-	v := tx.Bucket(keyValueLogIndex).Get(k)
+	idx, err := tx.Bucket(keyValueLogIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := idx.Get(k)
 
-	if len(v) == 0 {
+	if err == ErrKeyNotFound {
 		return nil, errKeyValueLogBoundsNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	bounds := &keyValueLogBounds{}
 	if err := json.Unmarshal(v, bounds); err != nil {
@@ -128,7 +135,7 @@ func (c *Client) getKeyValueLogBounds(ctx context.Context, tx *bolt.Tx, key []by
 	return bounds, nil
 }
 
-func (c *Client) putKeyValueLogBounds(ctx context.Context, tx *bolt.Tx, key []byte, bounds *keyValueLogBounds) error {
+func (c *Service) putKeyValueLogBounds(ctx context.Context, tx Tx, key []byte, bounds *keyValueLogBounds) error {
 	k := encodeKeyValueIndexKey(key)
 
 	v, err := json.Marshal(bounds)
@@ -136,14 +143,19 @@ func (c *Client) putKeyValueLogBounds(ctx context.Context, tx *bolt.Tx, key []by
 		return err
 	}
 
-	if err := tx.Bucket(keyValueLogIndex).Put(k, v); err != nil {
+	idx, err := tx.Bucket(keyValueLogIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Put(k, v); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Client) updateKeyValueLogBounds(ctx context.Context, tx *bolt.Tx, k []byte, t time.Time) error {
+func (c *Service) updateKeyValueLogBounds(ctx context.Context, tx Tx, k []byte, t time.Time) error {
 	// retrieve the keyValue log boundaries
 	bounds, err := c.getKeyValueLogBounds(ctx, tx, k)
 	if err != nil && err != errKeyValueLogBoundsNotFound {
@@ -165,19 +177,27 @@ func (c *Client) updateKeyValueLogBounds(ctx context.Context, tx *bolt.Tx, k []b
 }
 
 // ForEachLogEntry retrieves the keyValue log for a resource type ID combination. KeyValues may be returned in ascending and descending order.
-func (c *Client) ForEachLogEntry(ctx context.Context, k []byte, opts platform.FindOptions, fn func([]byte, time.Time) error) error {
-	return c.db.View(func(tx *bolt.Tx) error {
+func (c *Service) ForEachLogEntry(ctx context.Context, k []byte, opts platform.FindOptions, fn func([]byte, time.Time) error) error {
+	return c.kv.View(func(tx Tx) error {
 		return c.forEachLogEntry(ctx, tx, k, opts, fn)
 	})
 }
 
-func (c *Client) forEachLogEntry(ctx context.Context, tx *bolt.Tx, k []byte, opts platform.FindOptions, fn func([]byte, time.Time) error) error {
+func (c *Service) forEachLogEntry(ctx context.Context, tx Tx, k []byte, opts platform.FindOptions, fn func([]byte, time.Time) error) error {
 	b, err := c.getKeyValueLogBounds(ctx, tx, k)
 	if err != nil {
 		return err
 	}
 
-	cur := tx.Bucket(keyValueLogBucket).Cursor()
+	bkt, err := tx.Bucket(keyValueLogBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := bkt.Cursor()
+	if err != nil {
+		return err
+	}
 
 	next := cur.Next
 	startKey, stopKey, err := b.Bounds(k)
@@ -253,13 +273,13 @@ func (c *Client) forEachLogEntry(ctx context.Context, tx *bolt.Tx, k []byte, opt
 }
 
 // LogKeyValue logs an keyValue for a particular resource type ID pairing.
-func (c *Client) AddLogEntry(ctx context.Context, k, v []byte, t time.Time) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) AddLogEntry(ctx context.Context, k, v []byte, t time.Time) error {
+	return c.kv.Update(func(tx Tx) error {
 		return c.addLogEntry(ctx, tx, k, v, t)
 	})
 }
 
-func (c *Client) addLogEntry(ctx context.Context, tx *bolt.Tx, k, v []byte, t time.Time) error {
+func (c *Service) addLogEntry(ctx context.Context, tx Tx, k, v []byte, t time.Time) error {
 	if err := c.updateKeyValueLogBounds(ctx, tx, k, t); err != nil {
 		return err
 	}
@@ -271,40 +291,53 @@ func (c *Client) addLogEntry(ctx context.Context, tx *bolt.Tx, k, v []byte, t ti
 	return nil
 }
 
-func (c *Client) putLogEntry(ctx context.Context, tx *bolt.Tx, k, v []byte, t time.Time) error {
+func (c *Service) putLogEntry(ctx context.Context, tx Tx, k, v []byte, t time.Time) error {
 	key, err := encodeLogEntryKey(k, t.UTC().UnixNano())
 	if err != nil {
 		return err
 	}
 
-	if err := tx.Bucket(keyValueLogBucket).Put(key, v); err != nil {
+	b, err := tx.Bucket(keyValueLogBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, v); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Client) getLogEntry(ctx context.Context, tx *bolt.Tx, k []byte, t time.Time) ([]byte, time.Time, error) {
+func (c *Service) getLogEntry(ctx context.Context, tx Tx, k []byte, t time.Time) ([]byte, time.Time, error) {
 	key, err := encodeLogEntryKey(k, t.UTC().UnixNano())
 	if err != nil {
 		return nil, t, err
 	}
 
-	v := tx.Bucket(keyValueLogBucket).Get(key)
+	b, err := tx.Bucket(keyValueLogBucket)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	v, err := b.Get(key)
 
-	if len(v) == 0 {
+	if err == ErrKeyNotFound {
 		return nil, t, fmt.Errorf("log entry not found")
 	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
 
 	return v, t, nil
 }
 
 // FirstLogEntry retrieves the first log entry for a key value log.
-func (c *Client) FirstLogEntry(ctx context.Context, k []byte) ([]byte, time.Time, error) {
+func (c *Service) FirstLogEntry(ctx context.Context, k []byte) ([]byte, time.Time, error) {
 	var v []byte
 	var t time.Time
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		val, ts, err := c.firstLogEntry(ctx, tx, k)
 		if err != nil {
 			return err
@@ -323,11 +356,11 @@ func (c *Client) FirstLogEntry(ctx context.Context, k []byte) ([]byte, time.Time
 }
 
 // LastLogEntry retrieves the first log entry for a key value log.
-func (c *Client) LastLogEntry(ctx context.Context, k []byte) ([]byte, time.Time, error) {
+func (c *Service) LastLogEntry(ctx context.Context, k []byte) ([]byte, time.Time, error) {
 	var v []byte
 	var t time.Time
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		val, ts, err := c.lastLogEntry(ctx, tx, k)
 		if err != nil {
 			return err
@@ -345,7 +378,7 @@ func (c *Client) LastLogEntry(ctx context.Context, k []byte) ([]byte, time.Time,
 	return v, t, nil
 }
 
-func (c *Client) firstLogEntry(ctx context.Context, tx *bolt.Tx, k []byte) ([]byte, time.Time, error) {
+func (c *Service) firstLogEntry(ctx context.Context, tx Tx, k []byte) ([]byte, time.Time, error) {
 	bounds, err := c.getKeyValueLogBounds(ctx, tx, k)
 	if err != nil {
 		return nil, bounds.StartTime(), err
@@ -354,7 +387,7 @@ func (c *Client) firstLogEntry(ctx context.Context, tx *bolt.Tx, k []byte) ([]by
 	return c.getLogEntry(ctx, tx, k, bounds.StartTime())
 }
 
-func (c *Client) lastLogEntry(ctx context.Context, tx *bolt.Tx, k []byte) ([]byte, time.Time, error) {
+func (c *Service) lastLogEntry(ctx context.Context, tx Tx, k []byte) ([]byte, time.Time, error) {
 	bounds, err := c.getKeyValueLogBounds(ctx, tx, k)
 	if err != nil {
 		return nil, bounds.StopTime(), err
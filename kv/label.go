@@ -1,11 +1,10 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -13,8 +12,8 @@ var (
 	labelBucket = []byte("labelsv1")
 )
 
-func (c *Client) initializeLabels(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(labelBucket)); err != nil {
+func (c *Service) initializeLabels(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(labelBucket)); err != nil {
 		return err
 	}
 	return nil
@@ -27,7 +26,7 @@ func filterLabelsFn(filter platform.LabelFilter) func(l *platform.Label) bool {
 	}
 }
 
-// func (c *Client) findLabel(ctx context.Context, tx *bolt.Tx, resourceID platform.ID, name string) (*platform.Label, error) {
+// func (c *Service) findLabel(ctx context.Context, tx Tx, resourceID platform.ID, name string) (*platform.Label, error) {
 // 	key, err := labelKey(&platform.Label{ResourceID: resourceID, Name: name})
 // 	if err != nil {
 // 		return nil, err
@@ -43,9 +42,9 @@ func filterLabelsFn(filter platform.LabelFilter) func(l *platform.Label) bool {
 // }
 
 // FindLabels returns a list of labels that match a filter.
-func (c *Client) FindLabels(ctx context.Context, filter platform.LabelFilter, opt ...platform.FindOptions) ([]*platform.Label, error) {
+func (c *Service) FindLabels(ctx context.Context, filter platform.LabelFilter, opt ...platform.FindOptions) ([]*platform.Label, error) {
 	ls := []*platform.Label{}
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		labels, err := c.findLabels(ctx, tx, filter)
 		if err != nil {
 			return err
@@ -61,7 +60,7 @@ func (c *Client) FindLabels(ctx context.Context, filter platform.LabelFilter, op
 	return ls, nil
 }
 
-func (c *Client) findLabels(ctx context.Context, tx *bolt.Tx, filter platform.LabelFilter) ([]*platform.Label, error) {
+func (c *Service) findLabels(ctx context.Context, tx Tx, filter platform.LabelFilter) ([]*platform.Label, error) {
 	ls := []*platform.Label{}
 	filterFn := filterLabelsFn(filter)
 	err := c.forEachLabel(ctx, tx, func(l *platform.Label) bool {
@@ -78,13 +77,13 @@ func (c *Client) findLabels(ctx context.Context, tx *bolt.Tx, filter platform.La
 	return ls, nil
 }
 
-func (c *Client) CreateLabel(ctx context.Context, l *platform.Label) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) CreateLabel(ctx context.Context, l *platform.Label) error {
+	return c.kv.Update(func(tx Tx) error {
 		return c.createLabel(ctx, tx, l)
 	})
 }
 
-func (c *Client) createLabel(ctx context.Context, tx *bolt.Tx, l *platform.Label) error {
+func (c *Service) createLabel(ctx context.Context, tx Tx, l *platform.Label) error {
 	unique := c.uniqueLabel(ctx, tx, l)
 
 	if !unique {
@@ -105,7 +104,12 @@ func (c *Client) createLabel(ctx context.Context, tx *bolt.Tx, l *platform.Label
 		return err
 	}
 
-	if err := tx.Bucket(labelBucket).Put(key, v); err != nil {
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, v); err != nil {
 		return err
 	}
 
@@ -128,8 +132,16 @@ func labelKey(l *platform.Label) ([]byte, error) {
 	return key, nil
 }
 
-func (c *Client) forEachLabel(ctx context.Context, tx *bolt.Tx, fn func(*platform.Label) bool) error {
-	cur := tx.Bucket(labelBucket).Cursor()
+func (c *Service) forEachLabel(ctx context.Context, tx Tx, fn func(*platform.Label) bool) error {
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
 		l := &platform.Label{}
 		if err := json.Unmarshal(v, l); err != nil {
@@ -143,20 +155,28 @@ func (c *Client) forEachLabel(ctx context.Context, tx *bolt.Tx, fn func(*platfor
 	return nil
 }
 
-func (c *Client) uniqueLabel(ctx context.Context, tx *bolt.Tx, l *platform.Label) bool {
+func (c *Service) uniqueLabel(ctx context.Context, tx Tx, l *platform.Label) bool {
 	key, err := labelKey(l)
 	if err != nil {
 		return false
 	}
 
-	v := tx.Bucket(labelBucket).Get(key)
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return false
+	}
+
+	v, err := b.Get(key)
+	if err != nil && err != ErrKeyNotFound {
+		return false
+	}
 	return len(v) == 0
 }
 
 // UpdateLabel updates a label.
-func (c *Client) UpdateLabel(ctx context.Context, l *platform.Label, upd platform.LabelUpdate) (*platform.Label, error) {
+func (c *Service) UpdateLabel(ctx context.Context, l *platform.Label, upd platform.LabelUpdate) (*platform.Label, error) {
 	var label *platform.Label
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		labelResponse, pe := c.updateLabel(ctx, tx, l, upd)
 		if pe != nil {
 			return &platform.Error{
@@ -171,7 +191,7 @@ func (c *Client) UpdateLabel(ctx context.Context, l *platform.Label, upd platfor
 	return label, err
 }
 
-func (c *Client) updateLabel(ctx context.Context, tx *bolt.Tx, l *platform.Label, upd platform.LabelUpdate) (*platform.Label, error) {
+func (c *Service) updateLabel(ctx context.Context, tx Tx, l *platform.Label, upd platform.LabelUpdate) (*platform.Label, error) {
 	ls, err := c.findLabels(ctx, tx, platform.LabelFilter{Name: l.Name, ResourceID: l.ResourceID})
 	if err != nil {
 		return nil, err
@@ -214,7 +234,7 @@ func (c *Client) updateLabel(ctx context.Context, tx *bolt.Tx, l *platform.Label
 }
 
 // set a label and overwrite any existing label
-func (c *Client) putLabel(ctx context.Context, tx *bolt.Tx, l *platform.Label) error {
+func (c *Service) putLabel(ctx context.Context, tx Tx, l *platform.Label) error {
 	v, err := json.Marshal(l)
 	if err != nil {
 		return &platform.Error{
@@ -227,7 +247,12 @@ func (c *Client) putLabel(ctx context.Context, tx *bolt.Tx, l *platform.Label) e
 		return pe
 	}
 
-	if err := tx.Bucket(labelBucket).Put(key, v); err != nil {
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, v); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -237,13 +262,13 @@ func (c *Client) putLabel(ctx context.Context, tx *bolt.Tx, l *platform.Label) e
 }
 
 // DeleteLabel deletes a label.
-func (c *Client) DeleteLabel(ctx context.Context, l platform.Label) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteLabel(ctx context.Context, l platform.Label) error {
+	return c.kv.Update(func(tx Tx) error {
 		return c.deleteLabel(ctx, tx, platform.LabelFilter{Name: l.Name, ResourceID: l.ResourceID})
 	})
 }
 
-func (c *Client) deleteLabel(ctx context.Context, tx *bolt.Tx, filter platform.LabelFilter) error {
+func (c *Service) deleteLabel(ctx context.Context, tx Tx, filter platform.LabelFilter) error {
 	ls, err := c.findLabels(ctx, tx, filter)
 	if err != nil {
 		return err
@@ -261,10 +286,15 @@ func (c *Client) deleteLabel(ctx context.Context, tx *bolt.Tx, filter platform.L
 		return err
 	}
 
-	return tx.Bucket(labelBucket).Delete(key)
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(key)
 }
 
-func (c *Client) deleteLabels(ctx context.Context, tx *bolt.Tx, filter platform.LabelFilter) error {
+func (c *Service) deleteLabels(ctx context.Context, tx Tx, filter platform.LabelFilter) error {
 	ls, err := c.findLabels(ctx, tx, filter)
 	if err != nil {
 		return err
@@ -274,7 +304,12 @@ func (c *Client) deleteLabels(ctx context.Context, tx *bolt.Tx, filter platform.
 		if err != nil {
 			return err
 		}
-		if err = tx.Bucket(labelBucket).Delete(key); err != nil {
+		b, err := tx.Bucket(labelBucket)
+		if err != nil {
+			return err
+		}
+
+		if err = b.Delete(key); err != nil {
 			return err
 		}
 	}
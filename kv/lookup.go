@@ -1,4 +1,4 @@
-package bolt
+package kv
 
 import (
 	"context"
@@ -6,10 +6,10 @@ import (
 	platform "github.com/influxdata/influxdb"
 )
 
-var _ platform.LookupService = (*Client)(nil)
+var _ platform.LookupService = (*Service)(nil)
 
 // Name returns the name for the resource and ID.
-func (c *Client) Name(ctx context.Context, resource platform.ResourceType, id platform.ID) (string, error) {
+func (c *Service) Name(ctx context.Context, resource platform.ResourceType, id platform.ID) (string, error) {
 	if err := resource.Valid(); err != nil {
 		return "", err
 	}
@@ -1,10 +1,11 @@
-package inmem
+package kv_test
 
 import (
 	"context"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
 )
@@ -14,8 +15,8 @@ var (
 	testIDStr = testID.String()
 )
 
-func TestService_Name(t *testing.T) {
-	type initFn func(ctx context.Context, s *Service) error
+func TestClient_Name(t *testing.T) {
+	type initFn func(ctx context.Context, c *kv.Service) error
 	type args struct {
 		resource platform.Resource
 		init     initFn
@@ -56,7 +57,7 @@ func TestService_Name(t *testing.T) {
 			want: "",
 		},
 		{
-			name: "task resource without a name returns an empty string",
+			name: "task resource without a name returns empty string",
 			args: args{
 				resource: platform.Resource{
 					Type: platform.TasksResourceType,
@@ -72,7 +73,7 @@ func TestService_Name(t *testing.T) {
 					Type: platform.BucketsResourceType,
 					ID:   platformtesting.IDPtr(testID),
 				},
-				init: func(ctx context.Context, s *Service) error {
+				init: func(ctx context.Context, s *kv.Service) error {
 					_ = s.CreateOrganization(ctx, &platform.Organization{
 						Name: "o1",
 					})
@@ -101,9 +102,10 @@ func TestService_Name(t *testing.T) {
 					Type: platform.DashboardsResourceType,
 					ID:   platformtesting.IDPtr(testID),
 				},
-				init: func(ctx context.Context, s *Service) error {
+				init: func(ctx context.Context, s *kv.Service) error {
 					return s.CreateDashboard(ctx, &platform.Dashboard{
-						Name: "dashboard1",
+						Name:           "dashboard1",
+						OrganizationID: 1,
 					})
 				},
 			},
@@ -126,7 +128,7 @@ func TestService_Name(t *testing.T) {
 					Type: platform.OrgsResourceType,
 					ID:   platformtesting.IDPtr(testID),
 				},
-				init: func(ctx context.Context, s *Service) error {
+				init: func(ctx context.Context, s *kv.Service) error {
 					return s.CreateOrganization(ctx, &platform.Organization{
 						Name: "org1",
 					})
@@ -144,6 +146,31 @@ func TestService_Name(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "source with existing id returns name",
+			args: args{
+				resource: platform.Resource{
+					Type: platform.SourcesResourceType,
+					ID:   platformtesting.IDPtr(testID),
+				},
+				init: func(ctx context.Context, s *kv.Service) error {
+					return s.CreateSource(ctx, &platform.Source{
+						Name: "source1",
+					})
+				},
+			},
+			want: "source1",
+		},
+		{
+			name: "source with non-existent id returns error",
+			args: args{
+				resource: platform.Resource{
+					Type: platform.SourcesResourceType,
+					ID:   platformtesting.IDPtr(testID),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "telegraf with existing id returns name",
 			args: args{
@@ -151,7 +178,7 @@ func TestService_Name(t *testing.T) {
 					Type: platform.TelegrafsResourceType,
 					ID:   platformtesting.IDPtr(testID),
 				},
-				init: func(ctx context.Context, s *Service) error {
+				init: func(ctx context.Context, s *kv.Service) error {
 					return s.CreateTelegrafConfig(ctx, &platform.TelegrafConfig{
 						OrganizationID: platformtesting.MustIDBase16("0000000000000009"),
 						Name:           "telegraf1",
@@ -177,7 +204,7 @@ func TestService_Name(t *testing.T) {
 					Type: platform.UsersResourceType,
 					ID:   platformtesting.IDPtr(testID),
 				},
-				init: func(ctx context.Context, s *Service) error {
+				init: func(ctx context.Context, s *kv.Service) error {
 					return s.CreateUser(ctx, &platform.User{
 						Name: "user1",
 					})
@@ -198,11 +225,16 @@ func TestService_Name(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := NewService()
-			s.IDGenerator = mock.NewIDGenerator(testIDStr, t)
+			c, done, err := NewTestInmemService()
+			if err != nil {
+				t.Fatalf("unable to create kv service: %v", err)
+			}
+			defer done()
+
+			c.IDGenerator = mock.NewIDGenerator(testIDStr, t)
 			ctx := context.Background()
 			if tt.args.init != nil {
-				if err := tt.args.init(ctx, s); err != nil {
+				if err := tt.args.init(ctx, c); err != nil {
 					t.Errorf("Service.Name() unable to initialize service: %v", err)
 				}
 			}
@@ -210,7 +242,7 @@ func TestService_Name(t *testing.T) {
 			if tt.args.resource.ID != nil {
 				id = *tt.args.resource.ID
 			}
-			got, err := s.Name(ctx, tt.args.resource.Type, id)
+			got, err := c.Name(ctx, tt.args.resource.Type, id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.Name() error = %v, wantErr %v", err, tt.wantErr)
 				return
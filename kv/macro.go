@@ -1,11 +1,10 @@
-package bolt
+package kv
 
 import (
 	"bytes"
 	"context"
 	"encoding/json"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -14,11 +13,11 @@ var (
 	macroOrgsIndex = []byte("macroorgsv1")
 )
 
-func (c *Client) initializeMacros(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(macroBucket)); err != nil {
+func (c *Service) initializeMacros(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(macroBucket)); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists(macroOrgsIndex); err != nil {
+	if _, err := tx.Bucket(macroOrgsIndex); err != nil {
 		return err
 	}
 	return nil
@@ -51,9 +50,17 @@ func decodeMacroOrgsIndexKey(indexKey []byte) (orgID platform.ID, macroID platfo
 	return orgID, macroID, nil
 }
 
-func (c *Client) findOrganizationMacros(ctx context.Context, tx *bolt.Tx, orgID platform.ID) ([]*platform.Macro, error) {
+func (c *Service) findOrganizationMacros(ctx context.Context, tx Tx, orgID platform.ID) ([]*platform.Macro, error) {
 	// TODO(leodido): support find options
-	cur := tx.Bucket(macroOrgsIndex).Cursor()
+	idx, err := tx.Bucket(macroOrgsIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := idx.Cursor()
+	if err != nil {
+		return nil, err
+	}
 	prefix, err := orgID.Encode()
 	if err != nil {
 		return nil, err
@@ -77,7 +84,7 @@ func (c *Client) findOrganizationMacros(ctx context.Context, tx *bolt.Tx, orgID
 	return macros, nil
 }
 
-func (c *Client) findMacros(ctx context.Context, tx *bolt.Tx, filter platform.MacroFilter) ([]*platform.Macro, error) {
+func (c *Service) findMacros(ctx context.Context, tx Tx, filter platform.MacroFilter) ([]*platform.Macro, error) {
 	if filter.OrganizationID != nil {
 		return c.findOrganizationMacros(ctx, tx, *filter.OrganizationID)
 	}
@@ -123,8 +130,16 @@ func filterMacrosFn(filter platform.MacroFilter) func(m *platform.Macro) bool {
 }
 
 // forEachMacro will iterate through all macros while fn returns true.
-func (c *Client) forEachMacro(ctx context.Context, tx *bolt.Tx, fn func(*platform.Macro) bool) error {
-	cur := tx.Bucket(macroBucket).Cursor()
+func (c *Service) forEachMacro(ctx context.Context, tx Tx, fn func(*platform.Macro) bool) error {
+	b, err := tx.Bucket(macroBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
 		m := &platform.Macro{}
 		if err := json.Unmarshal(v, m); err != nil {
@@ -139,11 +154,11 @@ func (c *Client) forEachMacro(ctx context.Context, tx *bolt.Tx, fn func(*platfor
 }
 
 // FindMacros returns all macros in the store
-func (c *Client) FindMacros(ctx context.Context, filter platform.MacroFilter, opt ...platform.FindOptions) ([]*platform.Macro, error) {
+func (c *Service) FindMacros(ctx context.Context, filter platform.MacroFilter, opt ...platform.FindOptions) ([]*platform.Macro, error) {
 	// todo(leodido) > handle find options
 	op := getOp(platform.OpFindMacros)
 	res := []*platform.Macro{}
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		macros, err := c.findMacros(ctx, tx, filter)
 		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
 			return err
@@ -163,10 +178,10 @@ func (c *Client) FindMacros(ctx context.Context, filter platform.MacroFilter, op
 }
 
 // FindMacroByID finds a single macro in the store by its ID
-func (c *Client) FindMacroByID(ctx context.Context, id platform.ID) (*platform.Macro, error) {
+func (c *Service) FindMacroByID(ctx context.Context, id platform.ID) (*platform.Macro, error) {
 	op := getOp(platform.OpFindMacroByID)
 	var macro *platform.Macro
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		m, pe := c.findMacroByID(ctx, tx, id)
 		if pe != nil {
 			return &platform.Error{
@@ -184,7 +199,7 @@ func (c *Client) FindMacroByID(ctx context.Context, id platform.ID) (*platform.M
 	return macro, nil
 }
 
-func (c *Client) findMacroByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Macro, error) {
+func (c *Service) findMacroByID(ctx context.Context, tx Tx, id platform.ID) (*platform.Macro, error) {
 	encID, err := id.Encode()
 	if err != nil {
 		return nil, &platform.Error{
@@ -193,13 +208,21 @@ func (c *Client) findMacroByID(ctx context.Context, tx *bolt.Tx, id platform.ID)
 		}
 	}
 
-	d := tx.Bucket(macroBucket).Get(encID)
-	if d == nil {
+	b, err := tx.Bucket(macroBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := b.Get(encID)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  platform.ErrMacroNotFound,
 		}
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	macro := &platform.Macro{}
 	err = json.Unmarshal(d, &macro)
@@ -213,9 +236,9 @@ func (c *Client) findMacroByID(ctx context.Context, tx *bolt.Tx, id platform.ID)
 }
 
 // CreateMacro creates a new macro and assigns it an ID
-func (c *Client) CreateMacro(ctx context.Context, macro *platform.Macro) error {
+func (c *Service) CreateMacro(ctx context.Context, macro *platform.Macro) error {
 	op := getOp(platform.OpCreateMacro)
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		macro.ID = c.IDGenerator.ID()
 
 		if err := c.putMacroOrgsIndex(ctx, tx, macro); err != nil {
@@ -234,9 +257,9 @@ func (c *Client) CreateMacro(ctx context.Context, macro *platform.Macro) error {
 }
 
 // ReplaceMacro puts a macro in the store
-func (c *Client) ReplaceMacro(ctx context.Context, macro *platform.Macro) error {
+func (c *Service) ReplaceMacro(ctx context.Context, macro *platform.Macro) error {
 	op := getOp(platform.OpReplaceMacro)
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		if err := c.putMacroOrgsIndex(ctx, tx, macro); err != nil {
 			return &platform.Error{
 				Op:  op,
@@ -271,13 +294,18 @@ func encodeMacroOrgsIndex(macro *platform.Macro) ([]byte, error) {
 	return key, nil
 }
 
-func (c *Client) putMacroOrgsIndex(ctx context.Context, tx *bolt.Tx, macro *platform.Macro) error {
+func (c *Service) putMacroOrgsIndex(ctx context.Context, tx Tx, macro *platform.Macro) error {
 	key, err := encodeMacroOrgsIndex(macro)
 	if err != nil {
 		return err
 	}
 
-	if err := tx.Bucket(macroOrgsIndex).Put(key, nil); err != nil {
+	idx, err := tx.Bucket(macroOrgsIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Put(key, nil); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -286,20 +314,25 @@ func (c *Client) putMacroOrgsIndex(ctx context.Context, tx *bolt.Tx, macro *plat
 	return nil
 }
 
-func (c *Client) removeMacroOrgsIndex(ctx context.Context, tx *bolt.Tx, macro *platform.Macro) error {
+func (c *Service) removeMacroOrgsIndex(ctx context.Context, tx Tx, macro *platform.Macro) error {
 	key, err := encodeMacroOrgsIndex(macro)
 	if err != nil {
 		return err
 	}
 
-	if err := tx.Bucket(macroOrgsIndex).Delete(key); err != nil {
+	idx, err := tx.Bucket(macroOrgsIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Delete(key); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Client) putMacro(ctx context.Context, tx *bolt.Tx, macro *platform.Macro) error {
+func (c *Service) putMacro(ctx context.Context, tx Tx, macro *platform.Macro) error {
 	m, err := json.Marshal(macro)
 	if err != nil {
 		return &platform.Error{
@@ -315,7 +348,12 @@ func (c *Client) putMacro(ctx context.Context, tx *bolt.Tx, macro *platform.Macr
 		}
 	}
 
-	if err := tx.Bucket(macroBucket).Put(encID, m); err != nil {
+	b, err := tx.Bucket(macroBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encID, m); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -325,10 +363,10 @@ func (c *Client) putMacro(ctx context.Context, tx *bolt.Tx, macro *platform.Macr
 }
 
 // UpdateMacro updates a single macro in the store with a changeset
-func (c *Client) UpdateMacro(ctx context.Context, id platform.ID, update *platform.MacroUpdate) (*platform.Macro, error) {
+func (c *Service) UpdateMacro(ctx context.Context, id platform.ID, update *platform.MacroUpdate) (*platform.Macro, error) {
 	op := getOp(platform.OpUpdateMacro)
 	var macro *platform.Macro
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		m, pe := c.findMacroByID(ctx, tx, id)
 		if pe != nil {
 			return &platform.Error{
@@ -358,9 +396,9 @@ func (c *Client) UpdateMacro(ctx context.Context, id platform.ID, update *platfo
 }
 
 // DeleteMacro removes a single macro from the store by its ID
-func (c *Client) DeleteMacro(ctx context.Context, id platform.ID) error {
+func (c *Service) DeleteMacro(ctx context.Context, id platform.ID) error {
 	op := getOp(platform.OpDeleteMacro)
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		m, pe := c.findMacroByID(ctx, tx, id)
 		if pe != nil {
 			return &platform.Error{
@@ -384,7 +422,12 @@ func (c *Client) DeleteMacro(ctx context.Context, id platform.ID) error {
 			}
 		}
 
-		if err := tx.Bucket(macroBucket).Delete(encID); err != nil {
+		b, err := tx.Bucket(macroBucket)
+		if err != nil {
+			return err
+		}
+
+		if err := b.Delete(encID); err != nil {
 			return &platform.Error{
 				Op:  op,
 				Err: err,
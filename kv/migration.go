@@ -0,0 +1,246 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var migrationBucket = []byte("migrationsv1")
+
+// Migration is a change to the records of a Store, made when the format of
+// the records changes.
+type Migration struct {
+	// Name identifies the migration.  It must not change once the migration
+	// is released.
+	Name string
+	// Up migrates the records to the new format.
+	Up func(ctx context.Context, tx Tx) error
+	// Down reverts Up.  Migrations without Down cannot be reverted.
+	Down func(ctx context.Context, tx Tx) error
+}
+
+// MigrationState is the state of a migration in a Store.
+type MigrationState struct {
+	Name string
+	// AppliedAt is when the migration was applied.  It is nil for pending
+	// migrations.
+	AppliedAt *time.Time
+	// Reversible reports whether the migration has a Down step.
+	Reversible bool
+}
+
+// migrationRecord is the record of an applied migration.
+type migrationRecord struct {
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// Migrator applies an ordered list of migrations to a Store.  The applied
+// migrations are recorded in the store, each by its position in the list, so
+// migrations must only be appended to the list.  Every migration is applied
+// in its own transaction.
+type Migrator struct {
+	Logger *zap.Logger
+
+	store      Store
+	migrations []Migration
+	now        func() time.Time
+}
+
+// NewMigrator returns a Migrator of store with the migrations ms.
+func NewMigrator(store Store, ms ...Migration) *Migrator {
+	return &Migrator{
+		Logger:     zap.NewNop(),
+		store:      store,
+		migrations: ms,
+		now:        time.Now,
+	}
+}
+
+// List returns the state of every migration, in order.
+func (m *Migrator) List(ctx context.Context) ([]MigrationState, error) {
+	var states []MigrationState
+	err := m.store.View(func(tx Tx) error {
+		records, err := m.records(tx)
+		if err != nil {
+			return err
+		}
+
+		states = make([]MigrationState, len(m.migrations))
+		for i, mig := range m.migrations {
+			states[i] = MigrationState{Name: mig.Name, Reversible: mig.Down != nil}
+			if i < len(records) {
+				appliedAt := records[i].AppliedAt
+				states[i].AppliedAt = &appliedAt
+			}
+		}
+		return nil
+	})
+	return states, err
+}
+
+// Up applies the pending migrations and returns the number of migrations it
+// applied.  If a migration fails, the migrations before it stay applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	for {
+		done := false
+		err := m.store.Update(func(tx Tx) error {
+			tx.WithContext(ctx)
+			records, err := m.records(tx)
+			if err != nil {
+				return err
+			}
+			i := len(records)
+			if i == len(m.migrations) {
+				done = true
+				return nil
+			}
+
+			mig := m.migrations[i]
+			m.Logger.Info("Applying migration", zap.String("migration", mig.Name))
+			if err := mig.Up(ctx, tx); err != nil {
+				return fmt.Errorf("migration %q failed: %v", mig.Name, err)
+			}
+			return m.putRecord(tx, i, migrationRecord{Name: mig.Name, AppliedAt: m.now().UTC()})
+		})
+		if err != nil {
+			return applied, err
+		}
+		if done {
+			return applied, nil
+		}
+		applied++
+	}
+}
+
+// Down reverts the last n applied migrations, the most recent first, and
+// returns the number of migrations it reverted.  Nothing is reverted if one
+// of them has no Down step.
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	states, err := m.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	last := 0
+	for _, s := range states {
+		if s.AppliedAt != nil {
+			last++
+		}
+	}
+	if n > last {
+		n = last
+	}
+	for i := last - n; i < last; i++ {
+		if m.migrations[i].Down == nil {
+			return 0, fmt.Errorf("migration %q cannot be reverted", m.migrations[i].Name)
+		}
+	}
+
+	for i := last - 1; i >= last-n; i-- {
+		mig := m.migrations[i]
+		err := m.store.Update(func(tx Tx) error {
+			tx.WithContext(ctx)
+			m.Logger.Info("Reverting migration", zap.String("migration", mig.Name))
+			if err := mig.Down(ctx, tx); err != nil {
+				return fmt.Errorf("reverting migration %q failed: %v", mig.Name, err)
+			}
+			b, err := tx.Bucket(migrationBucket)
+			if err != nil {
+				return err
+			}
+			return b.Delete(migrationKey(i))
+		})
+		if err != nil {
+			return last - 1 - i, err
+		}
+	}
+	return n, nil
+}
+
+// records returns the records of the applied migrations.  They must be the
+// first migrations of the migrator; a store migrated by a newer version has
+// migrations the migrator does not know.
+func (m *Migrator) records(tx Tx) ([]migrationRecord, error) {
+	b, err := tx.Bucket(migrationBucket)
+	if err == ErrTxNotWritable {
+		// The bucket is created with the first migration applied.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []migrationRecord
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		var r migrationRecord
+		if err := json.Unmarshal(v, &r); err != nil {
+			return nil, err
+		}
+
+		i := len(records)
+		if binary.BigEndian.Uint64(k) != uint64(i) {
+			return nil, fmt.Errorf("migration %q is recorded out of order", r.Name)
+		}
+		if i >= len(m.migrations) || m.migrations[i].Name != r.Name {
+			return nil, fmt.Errorf("unknown migration %q was applied; the data was migrated by a newer version", r.Name)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func (m *Migrator) putRecord(tx Tx, i int, r migrationRecord) error {
+	v, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	b, err := tx.Bucket(migrationBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(migrationKey(i), v)
+}
+
+func migrationKey(i int) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(i))
+	return k
+}
+
+// migrations returns the migrations of the records of the services, in the
+// order they are applied.  New migrations must be appended.
+func (c *Service) migrations() []Migration {
+	return []Migration{
+		{
+			Name: "scope telegraf tokens to the output buckets",
+			Up:   c.scopeTelegrafTokens,
+			Down: c.unscopeTelegrafTokens,
+		},
+	}
+}
+
+// Migrator returns the migrator of the Migrations of the store of the service.
+func (c *Service) Migrator() *Migrator {
+	m := NewMigrator(c.kv, c.Migrations...)
+	m.Logger = c.Logger.With(zap.String("component", "migrator"))
+	return m
+}
+
+// migrate applies the pending migrations.
+func (c *Service) migrate(ctx context.Context) error {
+	n, err := c.Migrator().Up(ctx)
+	if n > 0 {
+		c.Logger.Info("Applied migrations", zap.Int("count", n))
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+)
+
+// renameMigration returns a migration that moves the value of key from to key
+// to in the bucket "things".
+func renameMigration(name, from, to string) kv.Migration {
+	move := func(from, to string) func(context.Context, kv.Tx) error {
+		return func(ctx context.Context, tx kv.Tx) error {
+			b, err := tx.Bucket([]byte("things"))
+			if err != nil {
+				return err
+			}
+			v, err := b.Get([]byte(from))
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(to), v); err != nil {
+				return err
+			}
+			return b.Delete([]byte(from))
+		}
+	}
+	return kv.Migration{Name: name, Up: move(from, to), Down: move(to, from)}
+}
+
+func thing(t *testing.T, s kv.Store, key string) string {
+	var v []byte
+	if err := s.View(func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("things"))
+		if err != nil {
+			return err
+		}
+		v, err = b.Get([]byte(key))
+		return err
+	}); err != nil && err != kv.ErrKeyNotFound {
+		t.Fatal(err)
+	}
+	return string(v)
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	s := inmem.NewKVStore()
+	if err := s.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("things"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("a"), []byte("value"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := kv.NewMigrator(s, renameMigration("a to b", "a", "b"))
+	if n, err := first.Up(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d migrations applied and error %v, want 1", n, err)
+	}
+	if n, err := first.Up(ctx); err != nil || n != 0 {
+		t.Fatalf("got %d migrations applied again and error %v, want 0", n, err)
+	}
+
+	// A newer version appends migrations.
+	m := kv.NewMigrator(s, renameMigration("a to b", "a", "b"), renameMigration("b to c", "b", "c"))
+	states, err := m.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 || states[0].AppliedAt == nil || states[1].AppliedAt != nil {
+		t.Fatalf("unexpected states %+v", states)
+	}
+	if n, err := m.Up(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d migrations applied and error %v, want 1", n, err)
+	}
+	if v := thing(t, s, "c"); v != "value" {
+		t.Fatalf("got %q after migrating, want value", v)
+	}
+
+	// The older version refuses data migrated by the newer one.
+	if _, err := first.Up(ctx); err == nil {
+		t.Fatal("expected error for unknown migration")
+	}
+
+	if n, err := m.Down(ctx, 1); err != nil || n != 1 {
+		t.Fatalf("got %d migrations reverted and error %v, want 1", n, err)
+	}
+	if v := thing(t, s, "b"); v != "value" {
+		t.Fatalf("got %q after reverting, want value", v)
+	}
+	if n, err := first.Up(ctx); err != nil || n != 0 {
+		t.Fatalf("got %d migrations applied after reverting and error %v, want 0", n, err)
+	}
+
+	// Migrations without Down are not reverted.
+	irreversible := kv.NewMigrator(s, kv.Migration{Name: "a to b", Up: renameMigration("", "a", "b").Up})
+	if _, err := irreversible.Down(ctx, 1); err == nil {
+		t.Fatal("expected error reverting a migration without Down")
+	}
+
+	// A failed migration is not recorded.
+	failing := kv.NewMigrator(s, renameMigration("a to b", "a", "b"), kv.Migration{
+		Name: "fail",
+		Up:   func(context.Context, kv.Tx) error { return errors.New("oops") },
+	})
+	if n, err := failing.Up(ctx); err == nil || n != 0 {
+		t.Fatalf("got %d migrations applied and error %v, want a failure", n, err)
+	}
+	states, err = failing.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if states[1].AppliedAt != nil {
+		t.Errorf("failed migration was recorded")
+	}
+}
+
+func TestService_Initialize_Migrations(t *testing.T) {
+	ctx := context.Background()
+	s := inmem.NewKVStore()
+	if err := s.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("things"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("a"), []byte("value"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := kv.NewService(s)
+	svc.Migrations = []kv.Migration{renameMigration("a to b", "a", "b")}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if v := thing(t, s, "b"); v != "value" {
+		t.Fatalf("got %q after initializing, want value", v)
+	}
+
+	states, err := svc.Migrator().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || states[0].AppliedAt == nil {
+		t.Fatalf("unexpected states %+v", states)
+	}
+}
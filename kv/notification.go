@@ -0,0 +1,442 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var (
+	notificationRuleBucket     = []byte("notificationrulesv1")
+	notificationEndpointBucket = []byte("notificationendpointsv1")
+)
+
+var _ platform.NotificationRuleService = (*Service)(nil)
+var _ platform.NotificationEndpointService = (*Service)(nil)
+
+func (c *Service) initializeNotifications(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(notificationRuleBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(notificationEndpointBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindNotificationRuleByID returns a single notification rule by ID.
+func (c *Service) FindNotificationRuleByID(ctx context.Context, id platform.ID) (*platform.NotificationRule, error) {
+	var rule *platform.NotificationRule
+	err := c.kv.View(func(tx Tx) error {
+		r, pe := c.findNotificationRuleByID(ctx, tx, id)
+		if pe != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpFindNotificationRuleByID),
+				Err: pe,
+			}
+		}
+		rule = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (c *Service) findNotificationRuleByID(ctx context.Context, tx Tx, id platform.ID) (*platform.NotificationRule, *platform.Error) {
+	rule := new(platform.NotificationRule)
+	if pe := getNotificationResource(tx, notificationRuleBucket, id, platform.ErrNotificationRuleNotFound, rule); pe != nil {
+		return nil, pe
+	}
+	return rule, nil
+}
+
+// FindNotificationRules returns a list of notification rules that match filter and the total count of matching rules.
+func (c *Service) FindNotificationRules(ctx context.Context, filter platform.NotificationRuleFilter, opt ...platform.FindOptions) ([]*platform.NotificationRule, int, error) {
+	rules := []*platform.NotificationRule{}
+	err := c.kv.View(func(tx Tx) error {
+		orgID, err := c.notificationFilterOrgID(ctx, tx, filter.OrganizationID, filter.Organization)
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(notificationRuleBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			rule := new(platform.NotificationRule)
+			if err := json.Unmarshal(v, rule); err != nil {
+				return err
+			}
+			if filter.ID != nil && rule.ID != *filter.ID {
+				continue
+			}
+			if orgID != nil && rule.OrganizationID != *orgID {
+				continue
+			}
+			rules = append(rules, rule)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  getOp(platform.OpFindNotificationRules),
+			Err: err,
+		}
+	}
+	return rules, len(rules), nil
+}
+
+// CreateNotificationRule creates a new notification rule and sets rule.ID with the new identifier.
+func (c *Service) CreateNotificationRule(ctx context.Context, rule *platform.NotificationRule) error {
+	op := getOp(platform.OpCreateNotificationRule)
+	if !rule.OrganizationID.Valid() {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "org id is invalid",
+			Op:   op,
+		}
+	}
+	if err := rule.Valid(); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	if rule.Status == "" {
+		rule.Status = platform.CheckStatusActive
+	}
+
+	err := c.kv.Update(func(tx Tx) error {
+		if _, pe := c.findNotificationEndpointByID(ctx, tx, rule.EndpointID); pe != nil {
+			return pe
+		}
+		rule.ID = c.IDGenerator.ID()
+		return putNotificationResource(tx, notificationRuleBucket, rule.ID, rule)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// PutNotificationRule will put a notification rule without setting an ID.
+func (c *Service) PutNotificationRule(ctx context.Context, rule *platform.NotificationRule) error {
+	return c.kv.Update(func(tx Tx) error {
+		return putNotificationResource(tx, notificationRuleBucket, rule.ID, rule)
+	})
+}
+
+// UpdateNotificationRule updates a single notification rule with changeset.
+func (c *Service) UpdateNotificationRule(ctx context.Context, id platform.ID, upd platform.NotificationRuleUpdate) (*platform.NotificationRule, error) {
+	var rule *platform.NotificationRule
+	err := c.kv.Update(func(tx Tx) error {
+		r, pe := c.findNotificationRuleByID(ctx, tx, id)
+		if pe != nil {
+			return pe
+		}
+		if err := upd.Apply(r); err != nil {
+			return err
+		}
+		if upd.EndpointID != nil {
+			if _, pe := c.findNotificationEndpointByID(ctx, tx, r.EndpointID); pe != nil {
+				return pe
+			}
+		}
+		rule = r
+		return putNotificationResource(tx, notificationRuleBucket, rule.ID, rule)
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  getOp(platform.OpUpdateNotificationRule),
+			Err: err,
+		}
+	}
+	return rule, nil
+}
+
+// DeleteNotificationRule removes a notification rule by ID.
+func (c *Service) DeleteNotificationRule(ctx context.Context, id platform.ID) error {
+	err := c.kv.Update(func(tx Tx) error {
+		if _, pe := c.findNotificationRuleByID(ctx, tx, id); pe != nil {
+			return pe
+		}
+		return deleteNotificationResource(tx, notificationRuleBucket, id)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  getOp(platform.OpDeleteNotificationRule),
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindNotificationEndpointByID returns a single notification endpoint by ID.
+func (c *Service) FindNotificationEndpointByID(ctx context.Context, id platform.ID) (*platform.NotificationEndpoint, error) {
+	var endpoint *platform.NotificationEndpoint
+	err := c.kv.View(func(tx Tx) error {
+		e, pe := c.findNotificationEndpointByID(ctx, tx, id)
+		if pe != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpFindNotificationEndpointByID),
+				Err: pe,
+			}
+		}
+		endpoint = e
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (c *Service) findNotificationEndpointByID(ctx context.Context, tx Tx, id platform.ID) (*platform.NotificationEndpoint, *platform.Error) {
+	endpoint := new(platform.NotificationEndpoint)
+	if pe := getNotificationResource(tx, notificationEndpointBucket, id, platform.ErrNotificationEndpointNotFound, endpoint); pe != nil {
+		return nil, pe
+	}
+	return endpoint, nil
+}
+
+// FindNotificationEndpoints returns a list of notification endpoints that match filter and the total count of matching endpoints.
+func (c *Service) FindNotificationEndpoints(ctx context.Context, filter platform.NotificationEndpointFilter, opt ...platform.FindOptions) ([]*platform.NotificationEndpoint, int, error) {
+	endpoints := []*platform.NotificationEndpoint{}
+	err := c.kv.View(func(tx Tx) error {
+		orgID, err := c.notificationFilterOrgID(ctx, tx, filter.OrganizationID, filter.Organization)
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(notificationEndpointBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			endpoint := new(platform.NotificationEndpoint)
+			if err := json.Unmarshal(v, endpoint); err != nil {
+				return err
+			}
+			if filter.ID != nil && endpoint.ID != *filter.ID {
+				continue
+			}
+			if orgID != nil && endpoint.OrganizationID != *orgID {
+				continue
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  getOp(platform.OpFindNotificationEndpoints),
+			Err: err,
+		}
+	}
+	return endpoints, len(endpoints), nil
+}
+
+// CreateNotificationEndpoint creates a new notification endpoint and sets endpoint.ID with the new identifier.
+func (c *Service) CreateNotificationEndpoint(ctx context.Context, endpoint *platform.NotificationEndpoint) error {
+	op := getOp(platform.OpCreateNotificationEndpoint)
+	if !endpoint.OrganizationID.Valid() {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "org id is invalid",
+			Op:   op,
+		}
+	}
+	if err := endpoint.Valid(); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+
+	err := c.kv.Update(func(tx Tx) error {
+		endpoint.ID = c.IDGenerator.ID()
+		return putNotificationResource(tx, notificationEndpointBucket, endpoint.ID, endpoint)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// PutNotificationEndpoint will put a notification endpoint without setting an ID.
+func (c *Service) PutNotificationEndpoint(ctx context.Context, endpoint *platform.NotificationEndpoint) error {
+	return c.kv.Update(func(tx Tx) error {
+		return putNotificationResource(tx, notificationEndpointBucket, endpoint.ID, endpoint)
+	})
+}
+
+// UpdateNotificationEndpoint updates a single notification endpoint with changeset.
+func (c *Service) UpdateNotificationEndpoint(ctx context.Context, id platform.ID, upd platform.NotificationEndpointUpdate) (*platform.NotificationEndpoint, error) {
+	var endpoint *platform.NotificationEndpoint
+	err := c.kv.Update(func(tx Tx) error {
+		e, pe := c.findNotificationEndpointByID(ctx, tx, id)
+		if pe != nil {
+			return pe
+		}
+		if err := upd.Apply(e); err != nil {
+			return err
+		}
+		endpoint = e
+		return putNotificationResource(tx, notificationEndpointBucket, endpoint.ID, endpoint)
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  getOp(platform.OpUpdateNotificationEndpoint),
+			Err: err,
+		}
+	}
+	return endpoint, nil
+}
+
+// DeleteNotificationEndpoint removes a notification endpoint by ID.
+// Endpoints that are used by notification rules cannot be deleted.
+func (c *Service) DeleteNotificationEndpoint(ctx context.Context, id platform.ID) error {
+	err := c.kv.Update(func(tx Tx) error {
+		if _, pe := c.findNotificationEndpointByID(ctx, tx, id); pe != nil {
+			return pe
+		}
+
+		b, err := tx.Bucket(notificationRuleBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			rule := new(platform.NotificationRule)
+			if err := json.Unmarshal(v, rule); err != nil {
+				return err
+			}
+			if rule.EndpointID == id {
+				return &platform.Error{
+					Code: platform.EConflict,
+					Msg:  "notification endpoint is used by notification rule " + rule.ID.String(),
+				}
+			}
+		}
+
+		return deleteNotificationResource(tx, notificationEndpointBucket, id)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  getOp(platform.OpDeleteNotificationEndpoint),
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// notificationFilterOrgID returns the organization ID a filter restricts results to, if any.
+func (c *Service) notificationFilterOrgID(ctx context.Context, tx Tx, orgID *platform.ID, org *string) (*platform.ID, error) {
+	if org == nil {
+		return orgID, nil
+	}
+	o, err := c.findOrganizationByName(ctx, tx, *org)
+	if err != nil {
+		return nil, err
+	}
+	return &o.ID, nil
+}
+
+func getNotificationResource(tx Tx, bucket []byte, id platform.ID, notFound string, v interface{}) *platform.Error {
+	encID, err := id.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(bucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	data, err := b.Get(encID)
+	if err == ErrKeyNotFound {
+		return &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  notFound,
+		}
+	}
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func putNotificationResource(tx Tx, bucket []byte, id platform.ID, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	encID, err := id.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	b, err := tx.Bucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encID, data)
+}
+
+func deleteNotificationResource(tx Tx, bucket []byte, id platform.ID) error {
+	encID, err := id.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	b, err := tx.Bucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(encID)
+}
@@ -0,0 +1,41 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func initNotificationServices(f platformtesting.NotificationFields, t *testing.T) (platformtesting.NotificationService, string, func()) {
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+
+	c.IDGenerator = f.IDGenerator
+	ctx := context.TODO()
+
+	for _, e := range f.Endpoints {
+		if err := c.PutNotificationEndpoint(ctx, e); err != nil {
+			t.Fatalf("failed to populate test notification endpoints: %v", err)
+		}
+	}
+	for _, r := range f.Rules {
+		if err := c.PutNotificationRule(ctx, r); err != nil {
+			t.Fatalf("failed to populate test notification rules: %v", err)
+		}
+	}
+
+	done := func() {
+		defer closeFn()
+	}
+
+	return c, kv.OpPrefix, done
+}
+
+func TestNotificationServices(t *testing.T) {
+	t.Parallel()
+	platformtesting.NotificationServices(initNotificationServices, t)
+}
@@ -0,0 +1,104 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var (
+	oauthIdentityBucket = []byte("oauthidentitiesv1")
+)
+
+var _ platform.OAuthIdentityService = (*Service)(nil)
+
+func (c *Service) initializeOAuthIdentities(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(oauthIdentityBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindOAuthIdentity returns the identity of subject at provider.
+func (c *Service) FindOAuthIdentity(ctx context.Context, provider, subject string) (*platform.OAuthIdentity, error) {
+	op := OpPrefix + platform.OpFindOAuthIdentity
+	var i *platform.OAuthIdentity
+	err := c.kv.View(func(tx Tx) error {
+		b, err := tx.Bucket(oauthIdentityBucket)
+		if err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		v, err := b.Get(oauthIdentityKey(provider, subject))
+		if err == ErrKeyNotFound {
+			return &platform.Error{
+				Code: platform.ENotFound,
+				Op:   op,
+				Msg:  platform.ErrOAuthIdentityNotFound,
+			}
+		}
+		if err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		i = new(platform.OAuthIdentity)
+		if err := json.Unmarshal(v, i); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return nil
+	})
+	return i, err
+}
+
+// PutOAuthIdentity creates or replaces an identity.
+func (c *Service) PutOAuthIdentity(ctx context.Context, i *platform.OAuthIdentity) error {
+	op := OpPrefix + platform.OpPutOAuthIdentity
+	if i.Provider == "" || i.Subject == "" || !i.UserID.Valid() {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  "oauth identity requires a provider, a subject and a user",
+		}
+	}
+	v, err := json.Marshal(i)
+	if err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return c.kv.Update(func(tx Tx) error {
+		b, err := tx.Bucket(oauthIdentityBucket)
+		if err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		if err := b.Put(oauthIdentityKey(i.Provider, i.Subject), v); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return nil
+	})
+}
+
+// oauthIdentityKey is the key of an identity, the provider and the subject
+// separated by a zero byte, which provider names do not contain.
+func oauthIdentityKey(provider, subject string) []byte {
+	key := make([]byte, 0, len(provider)+1+len(subject))
+	key = append(key, provider...)
+	key = append(key, 0)
+	key = append(key, subject...)
+	return key
+}
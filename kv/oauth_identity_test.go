@@ -0,0 +1,46 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestOAuthIdentityService(t *testing.T) {
+	s, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	defer closeFn()
+	ctx := context.Background()
+
+	if _, err := s.FindOAuthIdentity(ctx, "google", "jane"); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected identity not to be found, got %v", err)
+	}
+	if err := s.PutOAuthIdentity(ctx, &platform.OAuthIdentity{Provider: "google", Subject: "jane"}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected identity without user to be invalid, got %v", err)
+	}
+
+	identities := []*platform.OAuthIdentity{
+		{Provider: "google", Subject: "jane", UserID: 1},
+		{Provider: "github", Subject: "jane", UserID: 2},
+		{Provider: "google", Subject: "jane", UserID: 3},
+	}
+	for _, i := range identities {
+		if err := s.PutOAuthIdentity(ctx, i); err != nil {
+			t.Fatalf("failed to put identity: %v", err)
+		}
+	}
+
+	// Identities are keyed by provider and subject.
+	for provider, userID := range map[string]platform.ID{"google": 3, "github": 2} {
+		i, err := s.FindOAuthIdentity(ctx, provider, "jane")
+		if err != nil {
+			t.Fatalf("failed to find identity: %v", err)
+		}
+		if i.UserID != userID {
+			t.Errorf("expected identity of %s to be user %v, got %v", provider, userID, i.UserID)
+		}
+	}
+}
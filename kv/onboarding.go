@@ -1,21 +1,20 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"fmt"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
 var onboardingBucket = []byte("onboardingv1")
 var onboardingKey = []byte("onboarding_key")
 
-var _ platform.OnboardingService = (*Client)(nil)
+var _ platform.OnboardingService = (*Service)(nil)
 
-func (c *Client) initializeOnboarding(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(onboardingBucket)); err != nil {
+func (c *Service) initializeOnboarding(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(onboardingBucket)); err != nil {
 		return err
 	}
 	return nil
@@ -23,9 +22,17 @@ func (c *Client) initializeOnboarding(ctx context.Context, tx *bolt.Tx) error {
 
 // IsOnboarding checks onboardingBucket
 // to see if the onboarding key is true.
-func (c *Client) IsOnboarding(ctx context.Context) (isOnboarding bool, err error) {
-	err = c.db.View(func(tx *bolt.Tx) error {
-		result := tx.Bucket(onboardingBucket).Get(onboardingKey)
+func (c *Service) IsOnboarding(ctx context.Context) (isOnboarding bool, err error) {
+	err = c.kv.View(func(tx Tx) error {
+		b, err := tx.Bucket(onboardingBucket)
+		if err != nil {
+			return err
+		}
+
+		result, err := b.Get(onboardingKey)
+		if err != nil && err != ErrKeyNotFound {
+			return err
+		}
 		isOnboarding = len(result) == 0
 		return nil
 	})
@@ -34,10 +41,15 @@ func (c *Client) IsOnboarding(ctx context.Context) (isOnboarding bool, err error
 
 // PutOnboardingStatus will update the flag,
 // so future onboarding request will be denied.
-func (c *Client) PutOnboardingStatus(ctx context.Context, v bool) error {
+func (c *Service) PutOnboardingStatus(ctx context.Context, v bool) error {
 	if v {
-		return c.db.Update(func(tx *bolt.Tx) error {
-			return tx.Bucket(onboardingBucket).Put(onboardingKey, []byte{0x1})
+		return c.kv.Update(func(tx Tx) error {
+			b, err := tx.Bucket(onboardingBucket)
+			if err != nil {
+				return err
+			}
+
+			return b.Put(onboardingKey, []byte{0x1})
 		})
 	}
 	return nil
@@ -45,7 +57,7 @@ func (c *Client) PutOnboardingStatus(ctx context.Context, v bool) error {
 
 // Generate OnboardingResults from onboarding request,
 // update db so this request will be disabled for the second run.
-func (c *Client) Generate(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+func (c *Service) Generate(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
 	isOnboarding, err := c.IsOnboarding(ctx)
 	if err != nil {
 		return nil, err
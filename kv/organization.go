@@ -1,4 +1,4 @@
-package bolt
+package kv
 
 import (
 	"context"
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 	platformcontext "github.com/influxdata/influxdb/context"
 )
@@ -16,23 +15,23 @@ var (
 	organizationIndex  = []byte("organizationindexv1")
 )
 
-var _ platform.OrganizationService = (*Client)(nil)
-var _ platform.OrganizationOperationLogService = (*Client)(nil)
+var _ platform.OrganizationService = (*Service)(nil)
+var _ platform.OrganizationOperationLogService = (*Service)(nil)
 
-func (c *Client) initializeOrganizations(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(organizationBucket)); err != nil {
+func (c *Service) initializeOrganizations(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(organizationBucket)); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists([]byte(organizationIndex)); err != nil {
+	if _, err := tx.Bucket([]byte(organizationIndex)); err != nil {
 		return err
 	}
 	return nil
 }
 
 // FindOrganizationByID retrieves a organization by id.
-func (c *Client) FindOrganizationByID(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+func (c *Service) FindOrganizationByID(ctx context.Context, id platform.ID) (*platform.Organization, error) {
 	var o *platform.Organization
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		org, pe := c.findOrganizationByID(ctx, tx, id)
 		if pe != nil {
 			return &platform.Error{
@@ -51,7 +50,7 @@ func (c *Client) FindOrganizationByID(ctx context.Context, id platform.ID) (*pla
 	return o, nil
 }
 
-func (c *Client) findOrganizationByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Organization, *platform.Error) {
+func (c *Service) findOrganizationByID(ctx context.Context, tx Tx, id platform.ID) (*platform.Organization, *platform.Error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &platform.Error{
@@ -60,13 +59,25 @@ func (c *Client) findOrganizationByID(ctx context.Context, tx *bolt.Tx, id platf
 		}
 	}
 
-	v := tx.Bucket(organizationBucket).Get(encodedID)
-	if len(v) == 0 {
+	b, err := tx.Bucket(organizationBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encodedID)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "organization not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	var o platform.Organization
 	if err := json.Unmarshal(v, &o); err != nil {
@@ -79,10 +90,10 @@ func (c *Client) findOrganizationByID(ctx context.Context, tx *bolt.Tx, id platf
 }
 
 // FindOrganizationByName returns a organization by name for a particular organization.
-func (c *Client) FindOrganizationByName(ctx context.Context, n string) (*platform.Organization, error) {
+func (c *Service) FindOrganizationByName(ctx context.Context, n string) (*platform.Organization, error) {
 	var o *platform.Organization
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		org, pe := c.findOrganizationByName(ctx, tx, n)
 		if pe != nil {
 			return pe
@@ -94,14 +105,26 @@ func (c *Client) FindOrganizationByName(ctx context.Context, n string) (*platfor
 	return o, err
 }
 
-func (c *Client) findOrganizationByName(ctx context.Context, tx *bolt.Tx, n string) (*platform.Organization, *platform.Error) {
-	o := tx.Bucket(organizationIndex).Get(organizationIndexKey(n))
-	if o == nil {
+func (c *Service) findOrganizationByName(ctx context.Context, tx Tx, n string) (*platform.Organization, *platform.Error) {
+	idx, err := tx.Bucket(organizationIndex)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	o, err := idx.Get(organizationIndexKey(n))
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "organization not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	var id platform.ID
 	if err := id.Decode(o); err != nil {
@@ -116,7 +139,7 @@ func (c *Client) findOrganizationByName(ctx context.Context, tx *bolt.Tx, n stri
 // FindOrganization retrives a organization using an arbitrary organization filter.
 // Filters using ID, or Name should be efficient.
 // Other filters will do a linear scan across organizations until it finds a match.
-func (c *Client) FindOrganization(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+func (c *Service) FindOrganization(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
 	op := getOp(platform.OpFindOrganization)
 	if filter.ID != nil {
 		o, err := c.FindOrganizationByID(ctx, *filter.ID)
@@ -143,7 +166,7 @@ func (c *Client) FindOrganization(ctx context.Context, filter platform.Organizat
 	filterFn := filterOrganizationsFn(filter)
 
 	var o *platform.Organization
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		return forEachOrganization(ctx, tx, func(org *platform.Organization) bool {
 			if filterFn(org) {
 				o = org
@@ -190,7 +213,7 @@ func filterOrganizationsFn(filter platform.OrganizationFilter) func(o *platform.
 // FindOrganizations retrives all organizations that match an arbitrary organization filter.
 // Filters using ID, or Name should be efficient.
 // Other filters will do a linear scan across all organizations searching for a match.
-func (c *Client) FindOrganizations(ctx context.Context, filter platform.OrganizationFilter, opt ...platform.FindOptions) ([]*platform.Organization, int, error) {
+func (c *Service) FindOrganizations(ctx context.Context, filter platform.OrganizationFilter, opt ...platform.FindOptions) ([]*platform.Organization, int, error) {
 	op := getOp(platform.OpFindOrganizations)
 	if filter.ID != nil {
 		o, err := c.FindOrganizationByID(ctx, *filter.ID)
@@ -218,7 +241,7 @@ func (c *Client) FindOrganizations(ctx context.Context, filter platform.Organiza
 
 	os := []*platform.Organization{}
 	filterFn := filterOrganizationsFn(filter)
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		return forEachOrganization(ctx, tx, func(o *platform.Organization) bool {
 			if filterFn(o) {
 				os = append(os, o)
@@ -238,9 +261,9 @@ func (c *Client) FindOrganizations(ctx context.Context, filter platform.Organiza
 }
 
 // CreateOrganization creates a platform organization and sets b.ID.
-func (c *Client) CreateOrganization(ctx context.Context, o *platform.Organization) error {
+func (c *Service) CreateOrganization(ctx context.Context, o *platform.Organization) error {
 	op := getOp(platform.OpCreateOrganization)
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		unique := c.uniqueOrganizationName(ctx, tx, o)
 		if !unique {
 			return &platform.Error{
@@ -269,9 +292,9 @@ func (c *Client) CreateOrganization(ctx context.Context, o *platform.Organizatio
 }
 
 // PutOrganization will put a organization without setting an ID.
-func (c *Client) PutOrganization(ctx context.Context, o *platform.Organization) error {
+func (c *Service) PutOrganization(ctx context.Context, o *platform.Organization) error {
 	var err error
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		if pe := c.putOrganization(ctx, tx, o); pe != nil {
 			err = pe
 		}
@@ -279,7 +302,7 @@ func (c *Client) PutOrganization(ctx context.Context, o *platform.Organization)
 	})
 }
 
-func (c *Client) putOrganization(ctx context.Context, tx *bolt.Tx, o *platform.Organization) *platform.Error {
+func (c *Service) putOrganization(ctx context.Context, tx Tx, o *platform.Organization) *platform.Error {
 	v, err := json.Marshal(o)
 	if err != nil {
 		return &platform.Error{
@@ -293,12 +316,26 @@ func (c *Client) putOrganization(ctx context.Context, tx *bolt.Tx, o *platform.O
 			Err:  err,
 		}
 	}
-	if err := tx.Bucket(organizationIndex).Put(organizationIndexKey(o.Name), encodedID); err != nil {
+	idx, err := tx.Bucket(organizationIndex)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := idx.Put(organizationIndexKey(o.Name), encodedID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	b, err := tx.Bucket(organizationBucket)
+	if err != nil {
 		return &platform.Error{
 			Err: err,
 		}
 	}
-	if err = tx.Bucket(organizationBucket).Put(encodedID, v); err != nil {
+
+	if err = b.Put(encodedID, v); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -311,8 +348,16 @@ func organizationIndexKey(n string) []byte {
 }
 
 // forEachOrganization will iterate through all organizations while fn returns true.
-func forEachOrganization(ctx context.Context, tx *bolt.Tx, fn func(*platform.Organization) bool) error {
-	cur := tx.Bucket(organizationBucket).Cursor()
+func forEachOrganization(ctx context.Context, tx Tx, fn func(*platform.Organization) bool) error {
+	b, err := tx.Bucket(organizationBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
 		o := &platform.Organization{}
 		if err := json.Unmarshal(v, o); err != nil {
@@ -326,15 +371,23 @@ func forEachOrganization(ctx context.Context, tx *bolt.Tx, fn func(*platform.Org
 	return nil
 }
 
-func (c *Client) uniqueOrganizationName(ctx context.Context, tx *bolt.Tx, o *platform.Organization) bool {
-	v := tx.Bucket(organizationIndex).Get(organizationIndexKey(o.Name))
+func (c *Service) uniqueOrganizationName(ctx context.Context, tx Tx, o *platform.Organization) bool {
+	idx, err := tx.Bucket(organizationIndex)
+	if err != nil {
+		return false
+	}
+
+	v, err := idx.Get(organizationIndexKey(o.Name))
+	if err != nil && err != ErrKeyNotFound {
+		return false
+	}
 	return len(v) == 0
 }
 
 // UpdateOrganization updates a organization according the parameters set on upd.
-func (c *Client) UpdateOrganization(ctx context.Context, id platform.ID, upd platform.OrganizationUpdate) (*platform.Organization, error) {
+func (c *Service) UpdateOrganization(ctx context.Context, id platform.ID, upd platform.OrganizationUpdate) (*platform.Organization, error) {
 	var o *platform.Organization
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		org, pe := c.updateOrganization(ctx, tx, id, upd)
 		if pe != nil {
 			return &platform.Error{
@@ -349,7 +402,7 @@ func (c *Client) UpdateOrganization(ctx context.Context, id platform.ID, upd pla
 	return o, err
 }
 
-func (c *Client) updateOrganization(ctx context.Context, tx *bolt.Tx, id platform.ID, upd platform.OrganizationUpdate) (*platform.Organization, *platform.Error) {
+func (c *Service) updateOrganization(ctx context.Context, tx Tx, id platform.ID, upd platform.OrganizationUpdate) (*platform.Organization, *platform.Error) {
 	o, pe := c.findOrganizationByID(ctx, tx, id)
 	if pe != nil {
 		return nil, pe
@@ -358,7 +411,14 @@ func (c *Client) updateOrganization(ctx context.Context, tx *bolt.Tx, id platfor
 	if upd.Name != nil {
 		// Organizations are indexed by name and so the organization index must be pruned
 		// when name is modified.
-		if err := tx.Bucket(organizationIndex).Delete(organizationIndexKey(o.Name)); err != nil {
+		idx, err := tx.Bucket(organizationIndex)
+		if err != nil {
+			return nil, &platform.Error{
+				Err: err,
+			}
+		}
+
+		if err := idx.Delete(organizationIndexKey(o.Name)); err != nil {
 			return nil, &platform.Error{
 				Err: err,
 			}
@@ -380,8 +440,8 @@ func (c *Client) updateOrganization(ctx context.Context, tx *bolt.Tx, id platfor
 }
 
 // DeleteOrganization deletes a organization and prunes it from the index.
-func (c *Client) DeleteOrganization(ctx context.Context, id platform.ID) error {
-	err := c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteOrganization(ctx context.Context, id platform.ID) error {
+	err := c.kv.Update(func(tx Tx) error {
 		if pe := c.deleteOrganizationsBuckets(ctx, tx, id); pe != nil {
 			return pe
 		}
@@ -399,12 +459,19 @@ func (c *Client) DeleteOrganization(ctx context.Context, id platform.ID) error {
 	return nil
 }
 
-func (c *Client) deleteOrganization(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+func (c *Service) deleteOrganization(ctx context.Context, tx Tx, id platform.ID) *platform.Error {
 	o, pe := c.findOrganizationByID(ctx, tx, id)
 	if pe != nil {
 		return pe
 	}
-	if err := tx.Bucket(organizationIndex).Delete(organizationIndexKey(o.Name)); err != nil {
+	idx, err := tx.Bucket(organizationIndex)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := idx.Delete(organizationIndexKey(o.Name)); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -416,7 +483,14 @@ func (c *Client) deleteOrganization(ctx context.Context, tx *bolt.Tx, id platfor
 			Err:  err,
 		}
 	}
-	if err = tx.Bucket(organizationBucket).Delete(encodedID); err != nil {
+	b, err := tx.Bucket(organizationBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err = b.Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -424,7 +498,7 @@ func (c *Client) deleteOrganization(ctx context.Context, tx *bolt.Tx, id platfor
 	return nil
 }
 
-func (c *Client) deleteOrganizationsBuckets(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+func (c *Service) deleteOrganizationsBuckets(ctx context.Context, tx Tx, id platform.ID) *platform.Error {
 	filter := platform.BucketFilter{
 		OrganizationID: &id,
 	}
@@ -441,11 +515,11 @@ func (c *Client) deleteOrganizationsBuckets(ctx context.Context, tx *bolt.Tx, id
 }
 
 // GeOrganizationOperationLog retrieves a organization operation log.
-func (c *Client) GetOrganizationOperationLog(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.OperationLogEntry, int, error) {
+func (c *Service) GetOrganizationOperationLog(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.OperationLogEntry, int, error) {
 	// TODO(desa): might be worthwhile to allocate a slice of size opts.Limit
 	log := []*platform.OperationLogEntry{}
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		key, err := encodeBucketOperationLogKey(id)
 		if err != nil {
 			return err
@@ -485,7 +559,7 @@ func encodeOrganizationOperationLogKey(id platform.ID) ([]byte, error) {
 	return append([]byte(bucketOperationLogKeyPrefix), buf...), nil
 }
 
-func (c *Client) appendOrganizationEventToLog(ctx context.Context, tx *bolt.Tx, id platform.ID, s string) error {
+func (c *Service) appendOrganizationEventToLog(ctx context.Context, tx Tx, id platform.ID, s string) error {
 	e := &platform.OperationLogEntry{
 		Description: s,
 	}
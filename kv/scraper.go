@@ -1,10 +1,9 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"encoding/json"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -12,20 +11,28 @@ var (
 	scraperBucket = []byte("scraperv2")
 )
 
-var _ platform.ScraperTargetStoreService = (*Client)(nil)
+var _ platform.ScraperTargetStoreService = (*Service)(nil)
 
-func (c *Client) initializeScraperTargets(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(scraperBucket)); err != nil {
+func (c *Service) initializeScraperTargets(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(scraperBucket)); err != nil {
 		return err
 	}
 	return nil
 }
 
 // ListTargets will list all scrape targets.
-func (c *Client) ListTargets(ctx context.Context) (list []platform.ScraperTarget, err error) {
+func (c *Service) ListTargets(ctx context.Context) (list []platform.ScraperTarget, err error) {
 	list = make([]platform.ScraperTarget, 0)
-	err = c.db.View(func(tx *bolt.Tx) (err error) {
-		cur := tx.Bucket(scraperBucket).Cursor()
+	err = c.kv.View(func(tx Tx) (err error) {
+		b, err := tx.Bucket(scraperBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
 		for k, v := cur.First(); k != nil; k, v = cur.Next() {
 			target := new(platform.ScraperTarget)
 			if err = json.Unmarshal(v, target); err != nil {
@@ -45,7 +52,7 @@ func (c *Client) ListTargets(ctx context.Context) (list []platform.ScraperTarget
 }
 
 // AddTarget add a new scraper target into storage.
-func (c *Client) AddTarget(ctx context.Context, target *platform.ScraperTarget) (err error) {
+func (c *Service) AddTarget(ctx context.Context, target *platform.ScraperTarget) (err error) {
 	if !target.OrgID.Valid() {
 		return &platform.Error{
 			Code: platform.EInvalid,
@@ -60,7 +67,7 @@ func (c *Client) AddTarget(ctx context.Context, target *platform.ScraperTarget)
 			Op:   OpPrefix + platform.OpAddTarget,
 		}
 	}
-	err = c.db.Update(func(tx *bolt.Tx) error {
+	err = c.kv.Update(func(tx Tx) error {
 		target.ID = c.IDGenerator.ID()
 		return c.putTarget(ctx, tx, target)
 	})
@@ -74,8 +81,8 @@ func (c *Client) AddTarget(ctx context.Context, target *platform.ScraperTarget)
 }
 
 // RemoveTarget removes a scraper target from the bucket.
-func (c *Client) RemoveTarget(ctx context.Context, id platform.ID) error {
-	err := c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) RemoveTarget(ctx context.Context, id platform.ID) error {
+	err := c.kv.Update(func(tx Tx) error {
 		_, pe := c.findTargetByID(ctx, tx, id)
 		if pe != nil {
 			return pe
@@ -87,7 +94,12 @@ func (c *Client) RemoveTarget(ctx context.Context, id platform.ID) error {
 				Err:  err,
 			}
 		}
-		return tx.Bucket(scraperBucket).Delete(encID)
+		b, err := tx.Bucket(scraperBucket)
+		if err != nil {
+			return err
+		}
+
+		return b.Delete(encID)
 	})
 	if err != nil {
 		return &platform.Error{
@@ -99,7 +111,7 @@ func (c *Client) RemoveTarget(ctx context.Context, id platform.ID) error {
 }
 
 // UpdateTarget updates a scraper target.
-func (c *Client) UpdateTarget(ctx context.Context, update *platform.ScraperTarget) (target *platform.ScraperTarget, err error) {
+func (c *Service) UpdateTarget(ctx context.Context, update *platform.ScraperTarget) (target *platform.ScraperTarget, err error) {
 	op := getOp(platform.OpUpdateTarget)
 	var pe *platform.Error
 	if !update.ID.Valid() {
@@ -109,7 +121,7 @@ func (c *Client) UpdateTarget(ctx context.Context, update *platform.ScraperTarge
 			Msg:  "id is invalid",
 		}
 	}
-	err = c.db.Update(func(tx *bolt.Tx) error {
+	err = c.kv.Update(func(tx Tx) error {
 		target, pe = c.findTargetByID(ctx, tx, update.ID)
 		if pe != nil {
 			return pe
@@ -135,9 +147,9 @@ func (c *Client) UpdateTarget(ctx context.Context, update *platform.ScraperTarge
 }
 
 // GetTargetByID retrieves a scraper target by id.
-func (c *Client) GetTargetByID(ctx context.Context, id platform.ID) (target *platform.ScraperTarget, err error) {
+func (c *Service) GetTargetByID(ctx context.Context, id platform.ID) (target *platform.ScraperTarget, err error) {
 	var pe *platform.Error
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		target, pe = c.findTargetByID(ctx, tx, id)
 		if pe != nil {
 			return &platform.Error{
@@ -153,7 +165,7 @@ func (c *Client) GetTargetByID(ctx context.Context, id platform.ID) (target *pla
 	return target, nil
 }
 
-func (c *Client) findTargetByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (target *platform.ScraperTarget, pe *platform.Error) {
+func (c *Service) findTargetByID(ctx context.Context, tx Tx, id platform.ID) (target *platform.ScraperTarget, pe *platform.Error) {
 	target = new(platform.ScraperTarget)
 	encID, err := id.Encode()
 	if err != nil {
@@ -161,13 +173,25 @@ func (c *Client) findTargetByID(ctx context.Context, tx *bolt.Tx, id platform.ID
 			Err: err,
 		}
 	}
-	v := tx.Bucket(scraperBucket).Get(encID)
-	if len(v) == 0 {
+	b, err := tx.Bucket(scraperBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encID)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "scraper target is not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	if err := json.Unmarshal(v, target); err != nil {
 		return nil, &platform.Error{
@@ -177,7 +201,7 @@ func (c *Client) findTargetByID(ctx context.Context, tx *bolt.Tx, id platform.ID
 	return target, nil
 }
 
-func (c *Client) putTarget(ctx context.Context, tx *bolt.Tx, target *platform.ScraperTarget) (err error) {
+func (c *Service) putTarget(ctx context.Context, tx Tx, target *platform.ScraperTarget) (err error) {
 	v, err := json.Marshal(target)
 	if err != nil {
 		return err
@@ -186,12 +210,17 @@ func (c *Client) putTarget(ctx context.Context, tx *bolt.Tx, target *platform.Sc
 	if err != nil {
 		return err
 	}
-	return tx.Bucket(scraperBucket).Put(encID, v)
+	b, err := tx.Bucket(scraperBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encID, v)
 }
 
 // PutTarget will put a scraper target without setting an ID.
-func (c *Client) PutTarget(ctx context.Context, target *platform.ScraperTarget) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutTarget(ctx context.Context, target *platform.ScraperTarget) error {
+	return c.kv.Update(func(tx Tx) error {
 		return c.putTarget(ctx, tx, target)
 	})
 }
@@ -1,23 +1,34 @@
-package inmem
+package kv_test
 
 import (
 	"context"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
 	platformtesting "github.com/influxdata/influxdb/testing"
 )
 
 func initScraperTargetStoreService(f platformtesting.TargetFields, t *testing.T) (platform.ScraperTargetStoreService, string, func()) {
-	s := NewService()
-	s.IDGenerator = f.IDGenerator
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
 	ctx := context.Background()
 	for _, target := range f.Targets {
-		if err := s.PutTarget(ctx, target); err != nil {
-			t.Fatalf("failed to populate scraper targets")
+		if err := c.PutTarget(ctx, target); err != nil {
+			t.Fatalf("failed to populate targets: %v", err)
+		}
+	}
+	return c, kv.OpPrefix, func() {
+		defer closeFn()
+		for _, target := range f.Targets {
+			if err := c.RemoveTarget(ctx, target.ID); err != nil {
+				t.Logf("failed to remove targets: %v", err)
+			}
 		}
 	}
-	return s, OpPrefix, func() {}
 }
 
 func TestScraperTargetStoreService_AddTarget(t *testing.T) {
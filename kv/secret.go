@@ -1,4 +1,4 @@
-package bolt
+package kv
 
 import (
 	"context"
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -14,19 +13,19 @@ var (
 	secretBucket = []byte("secretsv1")
 )
 
-var _ platform.SecretService = (*Client)(nil)
+var _ platform.SecretService = (*Service)(nil)
 
-func (c *Client) initializeSecretService(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(secretBucket)); err != nil {
+func (c *Service) initializeSecretService(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(secretBucket)); err != nil {
 		return err
 	}
 	return nil
 }
 
 // LoadSecret retrieves the secret value v found at key k for organization orgID.
-func (c *Client) LoadSecret(ctx context.Context, orgID platform.ID, k string) (string, error) {
+func (c *Service) LoadSecret(ctx context.Context, orgID platform.ID, k string) (string, error) {
 	var v string
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		val, err := c.loadSecret(ctx, tx, orgID, k)
 		if err != nil {
 			return err
@@ -43,16 +42,24 @@ func (c *Client) LoadSecret(ctx context.Context, orgID platform.ID, k string) (s
 	return v, nil
 }
 
-func (c *Client) loadSecret(ctx context.Context, tx *bolt.Tx, orgID platform.ID, k string) (string, error) {
+func (c *Service) loadSecret(ctx context.Context, tx Tx, orgID platform.ID, k string) (string, error) {
 	key, err := encodeSecretKey(orgID, k)
 	if err != nil {
 		return "", err
 	}
 
-	val := tx.Bucket(secretBucket).Get(key)
-	if len(val) == 0 {
+	b, err := tx.Bucket(secretBucket)
+	if err != nil {
+		return "", err
+	}
+
+	val, err := b.Get(key)
+	if err == ErrKeyNotFound {
 		return "", fmt.Errorf("secret not found")
 	}
+	if err != nil {
+		return "", err
+	}
 
 	v, err := decodeSecretValue(val)
 	if err != nil {
@@ -63,9 +70,9 @@ func (c *Client) loadSecret(ctx context.Context, tx *bolt.Tx, orgID platform.ID,
 }
 
 // GetSecretKeys retrieves all secret keys that are stored for the organization orgID.
-func (c *Client) GetSecretKeys(ctx context.Context, orgID platform.ID) ([]string, error) {
+func (c *Service) GetSecretKeys(ctx context.Context, orgID platform.ID) ([]string, error) {
 	var vs []string
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		vals, err := c.getSecretKeys(ctx, tx, orgID)
 		if err != nil {
 			return err
@@ -82,8 +89,16 @@ func (c *Client) GetSecretKeys(ctx context.Context, orgID platform.ID) ([]string
 	return vs, nil
 }
 
-func (c *Client) getSecretKeys(ctx context.Context, tx *bolt.Tx, orgID platform.ID) ([]string, error) {
-	cur := tx.Bucket(secretBucket).Cursor()
+func (c *Service) getSecretKeys(ctx context.Context, tx Tx, orgID platform.ID) ([]string, error) {
+	b, err := tx.Bucket(secretBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
 	prefix, err := orgID.Encode()
 	if err != nil {
 		return nil, err
@@ -130,13 +145,13 @@ func (c *Client) getSecretKeys(ctx context.Context, tx *bolt.Tx, orgID platform.
 }
 
 // PutSecret stores the secret pair (k,v) for the organization orgID.
-func (c *Client) PutSecret(ctx context.Context, orgID platform.ID, k, v string) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutSecret(ctx context.Context, orgID platform.ID, k, v string) error {
+	return c.kv.Update(func(tx Tx) error {
 		return c.putSecret(ctx, tx, orgID, k, v)
 	})
 }
 
-func (c *Client) putSecret(ctx context.Context, tx *bolt.Tx, orgID platform.ID, k, v string) error {
+func (c *Service) putSecret(ctx context.Context, tx Tx, orgID platform.ID, k, v string) error {
 	key, err := encodeSecretKey(orgID, k)
 	if err != nil {
 		return err
@@ -144,7 +159,12 @@ func (c *Client) putSecret(ctx context.Context, tx *bolt.Tx, orgID platform.ID,
 
 	val := encodeSecretValue(v)
 
-	if err := tx.Bucket(secretBucket).Put(key, val); err != nil {
+	b, err := tx.Bucket(secretBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, val); err != nil {
 		return err
 	}
 	return nil
@@ -182,11 +202,12 @@ func decodeSecretKey(key []byte) (platform.ID, string, error) {
 func decodeSecretValue(val []byte) (string, error) {
 	// store the secret value base64 encoded so that it's marginally better than plaintext
 	v := make([]byte, base64.StdEncoding.DecodedLen(len(val)))
-	if _, err := base64.StdEncoding.Decode(v, val); err != nil {
+	n, err := base64.StdEncoding.Decode(v, val)
+	if err != nil {
 		return "", err
 	}
 
-	return string(v), nil
+	return string(v[:n]), nil
 }
 
 func encodeSecretValue(v string) []byte {
@@ -196,8 +217,8 @@ func encodeSecretValue(v string) []byte {
 }
 
 // PutSecrets puts all provided secrets and overwrites any previous values.
-func (c *Client) PutSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
+	return c.kv.Update(func(tx Tx) error {
 		keys, err := c.getSecretKeys(ctx, tx, orgID)
 		if err != nil {
 			return err
@@ -219,8 +240,8 @@ func (c *Client) PutSecrets(ctx context.Context, orgID platform.ID, m map[string
 }
 
 // PatchSecrets patches all provided secrets and updates any previous values.
-func (c *Client) PatchSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PatchSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
+	return c.kv.Update(func(tx Tx) error {
 		for k, v := range m {
 			if err := c.putSecret(ctx, tx, orgID, k, v); err != nil {
 				return err
@@ -231,8 +252,8 @@ func (c *Client) PatchSecrets(ctx context.Context, orgID platform.ID, m map[stri
 }
 
 // DeleteSecret removes secrets from the secret store.
-func (c *Client) DeleteSecret(ctx context.Context, orgID platform.ID, ks ...string) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteSecret(ctx context.Context, orgID platform.ID, ks ...string) error {
+	return c.kv.Update(func(tx Tx) error {
 		for _, k := range ks {
 			if err := c.deleteSecret(ctx, tx, orgID, k); err != nil {
 				return err
@@ -242,10 +263,15 @@ func (c *Client) DeleteSecret(ctx context.Context, orgID platform.ID, ks ...stri
 	})
 }
 
-func (c *Client) deleteSecret(ctx context.Context, tx *bolt.Tx, orgID platform.ID, k string) error {
+func (c *Service) deleteSecret(ctx context.Context, tx Tx, orgID platform.ID, k string) error {
 	key, err := encodeSecretKey(orgID, k)
 	if err != nil {
 		return err
 	}
-	return tx.Bucket(secretBucket).Delete(key)
+	b, err := tx.Bucket(secretBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(key)
 }
@@ -0,0 +1,31 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func initSecretService(f platformtesting.SecretServiceFields, t *testing.T) (platform.SecretService, func()) {
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	ctx := context.TODO()
+	for _, s := range f.Secrets {
+		for k, v := range s.Env {
+			if err := c.PutSecret(ctx, s.OrganizationID, k, v); err != nil {
+				t.Fatalf("failed to populate secrets")
+			}
+		}
+	}
+	return c, func() {
+		defer closeFn()
+	}
+}
+
+func TestSecretService(t *testing.T) {
+	platformtesting.SecretService(initSecretService, t)
+}
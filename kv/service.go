@@ -0,0 +1,171 @@
+package kv
+
+import (
+	"context"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/rand"
+	"github.com/influxdata/influxdb/snowflake"
+	"go.uber.org/zap"
+)
+
+// OpPrefix is the prefix for kv errors.
+const OpPrefix = "kv/"
+
+func getOp(op string) string {
+	return OpPrefix + op
+}
+
+// Service is the metadata services of the platform, implemented on a Store.
+type Service struct {
+	kv     Store
+	Logger *zap.Logger
+
+	IDGenerator    platform.IDGenerator
+	TokenGenerator platform.TokenGenerator
+	// Migrations are the migrations of the records of the services, applied
+	// in order by Initialize.
+	Migrations []Migration
+	time       func() time.Time
+}
+
+// NewService returns an instance of a Service backed by kv.
+func NewService(kv Store) *Service {
+	s := &Service{
+		kv:             kv,
+		Logger:         zap.NewNop(),
+		IDGenerator:    snowflake.NewIDGenerator(),
+		TokenGenerator: rand.NewTokenGenerator(64),
+		time:           time.Now,
+	}
+	s.Migrations = s.migrations()
+	return s
+}
+
+// WithLogger sets the logger of the service.
+func (c *Service) WithLogger(l *zap.Logger) {
+	c.Logger = l
+}
+
+// WithTime sets the function for computing the current time. Used for updating meta data
+// about objects stored. Should only be used in tests for mocking.
+func (c *Service) WithTime(fn func() time.Time) {
+	c.time = fn
+}
+
+// Initialize creates the buckets of the services that are missing, and applies
+// the pending migrations.
+func (c *Service) Initialize(ctx context.Context) error {
+	if err := c.initialize(ctx); err != nil {
+		return err
+	}
+	return c.migrate(ctx)
+}
+
+func (c *Service) initialize(ctx context.Context) error {
+	return c.kv.Update(func(tx Tx) error {
+		// Always create Buckets bucket.
+		if err := c.initializeBuckets(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Organizations bucket.
+		if err := c.initializeOrganizations(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Dashboards bucket.
+		if err := c.initializeDashboards(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create User bucket.
+		if err := c.initializeUsers(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Authorization bucket.
+		if err := c.initializeAuthorizations(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Onboarding bucket.
+		if err := c.initializeOnboarding(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Telegraf Config bucket.
+		if err := c.initializeTelegraf(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Telegraf Agent buckets.
+		if err := c.initializeTelegrafAgents(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Source bucket.
+		if err := c.initializeSources(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Views bucket.
+		if err := c.initializeViews(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Macros bucket.
+		if err := c.initializeMacros(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Scraper bucket.
+		if err := c.initializeScraperTargets(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create UserResourceMapping bucket.
+		if err := c.initializeUserResourceMappings(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create labels bucket.
+		if err := c.initializeLabels(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Session bucket.
+		if err := c.initializeSessions(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create KeyValueLog bucket.
+		if err := c.initializeKeyValueLog(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create SecretService bucket.
+		if err := c.initializeSecretService(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Checks bucket.
+		if err := c.initializeChecks(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create notification rules and endpoints buckets.
+		if err := c.initializeNotifications(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create OAuth identities bucket.
+		if err := c.initializeOAuthIdentities(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create DBRP mappings bucket.
+		return c.initializeDBRPMappings(ctx, tx)
+	})
+}
@@ -0,0 +1,22 @@
+package kv_test
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	kv.HashCost = bcrypt.MinCost
+}
+
+// NewTestInmemService returns a Service on an in-memory store.
+func NewTestInmemService() (*kv.Service, func(), error) {
+	s := kv.NewService(inmem.NewKVStore())
+	if err := s.Initialize(context.Background()); err != nil {
+		return nil, nil, err
+	}
+	return s, func() {}, nil
+}
@@ -1,11 +1,10 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"encoding/json"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -13,17 +12,17 @@ var (
 	sessionBucket = []byte("sessionsv1")
 )
 
-var _ platform.SessionService = (*Client)(nil)
+var _ platform.SessionService = (*Service)(nil)
 
-func (c *Client) initializeSessions(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(sessionBucket)); err != nil {
+func (c *Service) initializeSessions(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(sessionBucket)); err != nil {
 		return err
 	}
 	return nil
 }
 
 // RenewSession extends the expire time to newExpiration.
-func (c *Client) RenewSession(ctx context.Context, session *platform.Session, newExpiration time.Time) error {
+func (c *Service) RenewSession(ctx context.Context, session *platform.Session, newExpiration time.Time) error {
 	op := getOp(platform.OpRenewSession)
 	if session == nil {
 		return &platform.Error{
@@ -31,7 +30,7 @@ func (c *Client) RenewSession(ctx context.Context, session *platform.Session, ne
 			Msg: "session is nil",
 		}
 	}
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		session.ExpiresAt = newExpiration
 		if err := c.putSession(ctx, tx, session); err != nil {
 			return &platform.Error{
@@ -44,10 +43,10 @@ func (c *Client) RenewSession(ctx context.Context, session *platform.Session, ne
 }
 
 // FindSession retrieves the session found at the provided key.
-func (c *Client) FindSession(ctx context.Context, key string) (*platform.Session, error) {
+func (c *Service) FindSession(ctx context.Context, key string) (*platform.Session, error) {
 	op := getOp(platform.OpFindSession)
 	var sess *platform.Session
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		s, err := c.findSession(ctx, tx, key)
 		if err != nil {
 			return err
@@ -74,14 +73,26 @@ func (c *Client) FindSession(ctx context.Context, key string) (*platform.Session
 	return sess, nil
 }
 
-func (c *Client) findSession(ctx context.Context, tx *bolt.Tx, key string) (*platform.Session, *platform.Error) {
-	v := tx.Bucket(sessionBucket).Get([]byte(key))
-	if len(v) == 0 {
+func (c *Service) findSession(ctx context.Context, tx Tx, key string) (*platform.Session, *platform.Error) {
+	b, err := tx.Bucket(sessionBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get([]byte(key))
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  platform.ErrSessionNotFound,
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	s := &platform.Session{}
 	if err := json.Unmarshal(v, s); err != nil {
@@ -115,8 +126,8 @@ func (c *Client) findSession(ctx context.Context, tx *bolt.Tx, key string) (*pla
 }
 
 // PutSession puts the session at key.
-func (c *Client) PutSession(ctx context.Context, s *platform.Session) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutSession(ctx context.Context, s *platform.Session) error {
+	return c.kv.Update(func(tx Tx) error {
 		if err := c.putSession(ctx, tx, s); err != nil {
 			return err
 		}
@@ -124,14 +135,21 @@ func (c *Client) PutSession(ctx context.Context, s *platform.Session) error {
 	})
 }
 
-func (c *Client) putSession(ctx context.Context, tx *bolt.Tx, s *platform.Session) *platform.Error {
+func (c *Service) putSession(ctx context.Context, tx Tx, s *platform.Session) *platform.Error {
 	v, err := json.Marshal(s)
 	if err != nil {
 		return &platform.Error{
 			Err: err,
 		}
 	}
-	if err := tx.Bucket(sessionBucket).Put([]byte(s.Key), v); err != nil {
+	b, err := tx.Bucket(sessionBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Put([]byte(s.Key), v); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -140,8 +158,8 @@ func (c *Client) putSession(ctx context.Context, tx *bolt.Tx, s *platform.Sessio
 }
 
 // ExpireSession expires the session at the provided key.
-func (c *Client) ExpireSession(ctx context.Context, key string) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) ExpireSession(ctx context.Context, key string) error {
+	return c.kv.Update(func(tx Tx) error {
 		s, err := c.findSession(ctx, tx, key)
 		if err != nil {
 			return err
@@ -157,9 +175,9 @@ func (c *Client) ExpireSession(ctx context.Context, key string) error {
 }
 
 // CreateSession creates a session for a user with the users maximal privileges.
-func (c *Client) CreateSession(ctx context.Context, user string) (*platform.Session, error) {
+func (c *Service) CreateSession(ctx context.Context, user string) (*platform.Session, error) {
 	var sess *platform.Session
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		s, err := c.createSession(ctx, tx, user)
 		if err != nil {
 			return err
@@ -177,7 +195,7 @@ func (c *Client) CreateSession(ctx context.Context, user string) (*platform.Sess
 	return sess, nil
 }
 
-func (c *Client) createSession(ctx context.Context, tx *bolt.Tx, user string) (*platform.Session, *platform.Error) {
+func (c *Service) createSession(ctx context.Context, tx Tx, user string) (*platform.Session, *platform.Error) {
 	u, pe := c.findUserByName(ctx, tx, user)
 	if pe != nil {
 		return nil, pe
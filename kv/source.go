@@ -1,11 +1,10 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -37,8 +36,8 @@ func init() {
 	}
 }
 
-func (c *Client) initializeSources(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists(sourceBucket); err != nil {
+func (c *Service) initializeSources(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(sourceBucket); err != nil {
 		return err
 	}
 
@@ -57,10 +56,10 @@ func (c *Client) initializeSources(ctx context.Context, tx *bolt.Tx) error {
 }
 
 // DefaultSource retrieves the default source.
-func (c *Client) DefaultSource(ctx context.Context) (*platform.Source, error) {
+func (c *Service) DefaultSource(ctx context.Context) (*platform.Source, error) {
 	var s *platform.Source
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		// TODO(desa): make this faster by putting the default source in an index.
 		srcs, err := c.findSources(ctx, tx, platform.FindOptions{})
 		if err != nil {
@@ -89,10 +88,10 @@ func (c *Client) DefaultSource(ctx context.Context) (*platform.Source, error) {
 }
 
 // FindSourceByID retrieves a source by id.
-func (c *Client) FindSourceByID(ctx context.Context, id platform.ID) (*platform.Source, error) {
+func (c *Service) FindSourceByID(ctx context.Context, id platform.ID) (*platform.Source, error) {
 	var s *platform.Source
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		src, pe := c.findSourceByID(ctx, tx, id)
 		if pe != nil {
 			return &platform.Error{
@@ -106,7 +105,7 @@ func (c *Client) FindSourceByID(ctx context.Context, id platform.ID) (*platform.
 	return s, err
 }
 
-func (c *Client) findSourceByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Source, *platform.Error) {
+func (c *Service) findSourceByID(ctx context.Context, tx Tx, id platform.ID) (*platform.Source, *platform.Error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &platform.Error{
@@ -114,13 +113,25 @@ func (c *Client) findSourceByID(ctx context.Context, tx *bolt.Tx, id platform.ID
 		}
 	}
 
-	v := tx.Bucket(sourceBucket).Get(encodedID)
-	if len(v) == 0 {
+	b, err := tx.Bucket(sourceBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encodedID)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  platform.ErrSourceNotFound,
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	var s platform.Source
 	if err := json.Unmarshal(v, &s); err != nil {
@@ -135,9 +146,9 @@ func (c *Client) findSourceByID(ctx context.Context, tx *bolt.Tx, id platform.ID
 // FindSources retrives all sources that match an arbitrary source filter.
 // Filters using ID, or OrganizationID and source Name should be efficient.
 // Other filters will do a linear scan across all sources searching for a match.
-func (c *Client) FindSources(ctx context.Context, opt platform.FindOptions) ([]*platform.Source, int, error) {
+func (c *Service) FindSources(ctx context.Context, opt platform.FindOptions) ([]*platform.Source, int, error) {
 	ss := []*platform.Source{}
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		srcs, err := c.findSources(ctx, tx, opt)
 		if err != nil {
 			return err
@@ -156,7 +167,7 @@ func (c *Client) FindSources(ctx context.Context, opt platform.FindOptions) ([]*
 	return ss, len(ss), nil
 }
 
-func (c *Client) findSources(ctx context.Context, tx *bolt.Tx, opt platform.FindOptions) ([]*platform.Source, error) {
+func (c *Service) findSources(ctx context.Context, tx Tx, opt platform.FindOptions) ([]*platform.Source, error) {
 	ss := []*platform.Source{}
 
 	err := c.forEachSource(ctx, tx, func(s *platform.Source) bool {
@@ -172,8 +183,8 @@ func (c *Client) findSources(ctx context.Context, tx *bolt.Tx, opt platform.Find
 }
 
 // CreateSource creates a platform source and sets s.ID.
-func (c *Client) CreateSource(ctx context.Context, s *platform.Source) error {
-	err := c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) CreateSource(ctx context.Context, s *platform.Source) error {
+	err := c.kv.Update(func(tx Tx) error {
 		s.ID = c.IDGenerator.ID()
 
 		// Generating an organization id if it missing or invalid
@@ -193,13 +204,13 @@ func (c *Client) CreateSource(ctx context.Context, s *platform.Source) error {
 }
 
 // PutSource will put a source without setting an ID.
-func (c *Client) PutSource(ctx context.Context, s *platform.Source) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutSource(ctx context.Context, s *platform.Source) error {
+	return c.kv.Update(func(tx Tx) error {
 		return c.putSource(ctx, tx, s)
 	})
 }
 
-func (c *Client) putSource(ctx context.Context, tx *bolt.Tx, s *platform.Source) error {
+func (c *Service) putSource(ctx context.Context, tx Tx, s *platform.Source) error {
 	v, err := json.Marshal(s)
 	if err != nil {
 		return err
@@ -210,7 +221,12 @@ func (c *Client) putSource(ctx context.Context, tx *bolt.Tx, s *platform.Source)
 		return err
 	}
 
-	if err := tx.Bucket(sourceBucket).Put(encodedID, v); err != nil {
+	b, err := tx.Bucket(sourceBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
 		return err
 	}
 
@@ -218,8 +234,16 @@ func (c *Client) putSource(ctx context.Context, tx *bolt.Tx, s *platform.Source)
 }
 
 // forEachSource will iterate through all sources while fn returns true.
-func (c *Client) forEachSource(ctx context.Context, tx *bolt.Tx, fn func(*platform.Source) bool) error {
-	cur := tx.Bucket(sourceBucket).Cursor()
+func (c *Service) forEachSource(ctx context.Context, tx Tx, fn func(*platform.Source) bool) error {
+	b, err := tx.Bucket(sourceBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
 		s := &platform.Source{}
 		if err := json.Unmarshal(v, s); err != nil {
@@ -234,9 +258,9 @@ func (c *Client) forEachSource(ctx context.Context, tx *bolt.Tx, fn func(*platfo
 }
 
 // UpdateSource updates a source according the parameters set on upd.
-func (c *Client) UpdateSource(ctx context.Context, id platform.ID, upd platform.SourceUpdate) (*platform.Source, error) {
+func (c *Service) UpdateSource(ctx context.Context, id platform.ID, upd platform.SourceUpdate) (*platform.Source, error) {
 	var s *platform.Source
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		src, err := c.updateSource(ctx, tx, id, upd)
 		if err != nil {
 			return &platform.Error{
@@ -251,7 +275,7 @@ func (c *Client) UpdateSource(ctx context.Context, id platform.ID, upd platform.
 	return s, err
 }
 
-func (c *Client) updateSource(ctx context.Context, tx *bolt.Tx, id platform.ID, upd platform.SourceUpdate) (*platform.Source, error) {
+func (c *Service) updateSource(ctx context.Context, tx Tx, id platform.ID, upd platform.SourceUpdate) (*platform.Source, error) {
 	s, pe := c.findSourceByID(ctx, tx, id)
 	if pe != nil {
 		return nil, pe
@@ -269,8 +293,8 @@ func (c *Client) updateSource(ctx context.Context, tx *bolt.Tx, id platform.ID,
 }
 
 // DeleteSource deletes a source and prunes it from the index.
-func (c *Client) DeleteSource(ctx context.Context, id platform.ID) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteSource(ctx context.Context, id platform.ID) error {
+	return c.kv.Update(func(tx Tx) error {
 		pe := c.deleteSource(ctx, tx, id)
 		if pe != nil {
 			return &platform.Error{
@@ -282,7 +306,7 @@ func (c *Client) DeleteSource(ctx context.Context, id platform.ID) error {
 	})
 }
 
-func (c *Client) deleteSource(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+func (c *Service) deleteSource(ctx context.Context, tx Tx, id platform.ID) *platform.Error {
 	if id == DefaultSource.ID {
 		return &platform.Error{
 			Code: platform.EForbidden,
@@ -301,7 +325,14 @@ func (c *Client) deleteSource(ctx context.Context, tx *bolt.Tx, id platform.ID)
 		}
 	}
 
-	if err = tx.Bucket(sourceBucket).Delete(encodedID); err != nil {
+	b, err := tx.Bucket(sourceBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err = b.Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
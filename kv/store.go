@@ -43,6 +43,8 @@ type Bucket interface {
 
 // Cursor is an abstraction for iterating/ranging through data. A concrete implementation
 // of a cursor can be found in cursor.go.
+// Entries with empty values, such as the entries of indexes, are returned like
+// any other; a nil key marks the end of the bucket.
 type Cursor interface {
 	Seek(prefix []byte) (k []byte, v []byte)
 	First() (k []byte, v []byte)
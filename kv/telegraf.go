@@ -1,10 +1,9 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"encoding/json"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -12,19 +11,19 @@ var (
 	telegrafBucket = []byte("telegrafv1")
 )
 
-var _ platform.TelegrafConfigStore = new(Client)
+var _ platform.TelegrafConfigStore = new(Service)
 
-func (c *Client) initializeTelegraf(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists(telegrafBucket); err != nil {
+func (c *Service) initializeTelegraf(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(telegrafBucket); err != nil {
 		return err
 	}
 	return nil
 }
 
 // FindTelegrafConfigByID returns a single telegraf config by ID.
-func (c *Client) FindTelegrafConfigByID(ctx context.Context, id platform.ID) (tc *platform.TelegrafConfig, err error) {
+func (c *Service) FindTelegrafConfigByID(ctx context.Context, id platform.ID) (tc *platform.TelegrafConfig, err error) {
 	op := OpPrefix + platform.OpFindTelegrafConfigByID
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		var pErr *platform.Error
 		tc, pErr = c.findTelegrafConfigByID(ctx, tx, id)
 		if pErr != nil {
@@ -36,7 +35,7 @@ func (c *Client) FindTelegrafConfigByID(ctx context.Context, id platform.ID) (tc
 	return tc, err
 }
 
-func (c *Client) findTelegrafConfigByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.TelegrafConfig, *platform.Error) {
+func (c *Service) findTelegrafConfigByID(ctx context.Context, tx Tx, id platform.ID) (*platform.TelegrafConfig, *platform.Error) {
 	encID, err := id.Encode()
 	if err != nil {
 		return nil, &platform.Error{
@@ -44,13 +43,25 @@ func (c *Client) findTelegrafConfigByID(ctx context.Context, tx *bolt.Tx, id pla
 			Err:  err,
 		}
 	}
-	d := tx.Bucket(telegrafBucket).Get(encID)
-	if d == nil {
+	b, err := tx.Bucket(telegrafBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	d, err := b.Get(encID)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  platform.ErrTelegrafConfigNotFound,
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 	tc := new(platform.TelegrafConfig)
 	err = json.Unmarshal(d, tc)
 	if err != nil {
@@ -62,7 +73,7 @@ func (c *Client) findTelegrafConfigByID(ctx context.Context, tx *bolt.Tx, id pla
 }
 
 // FindTelegrafConfig returns the first telegraf config that matches filter.
-func (c *Client) FindTelegrafConfig(ctx context.Context, filter platform.TelegrafConfigFilter) (*platform.TelegrafConfig, error) {
+func (c *Service) FindTelegrafConfig(ctx context.Context, filter platform.TelegrafConfigFilter) (*platform.TelegrafConfig, error) {
 	op := OpPrefix + platform.OpFindTelegrafConfig
 	tcs, n, err := c.FindTelegrafConfigs(ctx, filter, platform.FindOptions{Limit: 1})
 	if err != nil {
@@ -77,7 +88,7 @@ func (c *Client) FindTelegrafConfig(ctx context.Context, filter platform.Telegra
 	}
 }
 
-func (c *Client) findTelegrafConfigs(ctx context.Context, tx *bolt.Tx, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) ([]*platform.TelegrafConfig, int, *platform.Error) {
+func (c *Service) findTelegrafConfigs(ctx context.Context, tx Tx, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) ([]*platform.TelegrafConfig, int, *platform.Error) {
 	tcs := make([]*platform.TelegrafConfig, 0)
 	m, err := c.findUserResourceMappings(ctx, tx, filter.UserResourceMappingFilter)
 	if err != nil {
@@ -109,9 +120,9 @@ func (c *Client) findTelegrafConfigs(ctx context.Context, tx *bolt.Tx, filter pl
 
 // FindTelegrafConfigs returns a list of telegraf configs that match filter and the total count of matching telegraf configs.
 // Additional options provide pagination & sorting.
-func (c *Client) FindTelegrafConfigs(ctx context.Context, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) (tcs []*platform.TelegrafConfig, n int, err error) {
+func (c *Service) FindTelegrafConfigs(ctx context.Context, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) (tcs []*platform.TelegrafConfig, n int, err error) {
 	op := OpPrefix + platform.OpFindTelegrafConfigs
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		var pErr *platform.Error
 		tcs, n, pErr = c.findTelegrafConfigs(ctx, tx, filter)
 		if pErr != nil {
@@ -123,7 +134,7 @@ func (c *Client) FindTelegrafConfigs(ctx context.Context, filter platform.Telegr
 	return tcs, len(tcs), err
 }
 
-func (c *Client) putTelegrafConfig(ctx context.Context, tx *bolt.Tx, tc *platform.TelegrafConfig) *platform.Error {
+func (c *Service) putTelegrafConfig(ctx context.Context, tx Tx, tc *platform.TelegrafConfig) *platform.Error {
 	v, err := json.Marshal(tc)
 	if err != nil {
 		return &platform.Error{
@@ -143,7 +154,14 @@ func (c *Client) putTelegrafConfig(ctx context.Context, tx *bolt.Tx, tc *platfor
 			Msg:  platform.ErrTelegrafConfigInvalidOrganizationID,
 		}
 	}
-	err = tx.Bucket(telegrafBucket).Put(encodedID, v)
+	b, err := tx.Bucket(telegrafBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	err = b.Put(encodedID, v)
 	if err != nil {
 		return &platform.Error{
 			Err: err,
@@ -153,9 +171,9 @@ func (c *Client) putTelegrafConfig(ctx context.Context, tx *bolt.Tx, tc *platfor
 }
 
 // CreateTelegrafConfig creates a new telegraf config and sets b.ID with the new identifier.
-func (c *Client) CreateTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) error {
+func (c *Service) CreateTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) error {
 	op := OpPrefix + platform.OpCreateTelegrafConfig
-	return c.db.Update(func(tx *bolt.Tx) error {
+	return c.kv.Update(func(tx Tx) error {
 		tc.ID = c.IDGenerator.ID()
 
 		pErr := c.putTelegrafConfig(ctx, tx, tc)
@@ -180,9 +198,9 @@ func (c *Client) CreateTelegrafConfig(ctx context.Context, tc *platform.Telegraf
 
 // UpdateTelegrafConfig updates a single telegraf config.
 // Returns the new telegraf config after update.
-func (c *Client) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *platform.TelegrafConfig, userID platform.ID) (*platform.TelegrafConfig, error) {
+func (c *Service) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *platform.TelegrafConfig, userID platform.ID) (*platform.TelegrafConfig, error) {
 	op := OpPrefix + platform.OpUpdateTelegrafConfig
-	err := c.db.Update(func(tx *bolt.Tx) (err error) {
+	err := c.kv.Update(func(tx Tx) (err error) {
 		current, pErr := c.findTelegrafConfigByID(ctx, tx, id)
 		if pErr != nil {
 			pErr.Op = op
@@ -204,9 +222,9 @@ func (c *Client) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *p
 }
 
 // DeleteTelegrafConfig removes a telegraf config by ID.
-func (c *Client) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error {
+func (c *Service) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error {
 	op := OpPrefix + platform.OpDeleteTelegrafConfig
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		encodedID, err := id.Encode()
 		if err != nil {
 			return &platform.Error{
@@ -214,7 +232,12 @@ func (c *Client) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error
 				Err:  err,
 			}
 		}
-		err = tx.Bucket(telegrafBucket).Delete(encodedID)
+		b, err := tx.Bucket(telegrafBucket)
+		if err != nil {
+			return err
+		}
+
+		err = b.Delete(encodedID)
 		if err != nil {
 			return err
 		}
@@ -234,8 +257,8 @@ func (c *Client) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error
 }
 
 // PutTelegrafConfig put a telegraf config to storage.
-func (c *Client) PutTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig) error {
-	return c.db.Update(func(tx *bolt.Tx) (err error) {
+func (c *Service) PutTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig) error {
+	return c.kv.Update(func(tx Tx) (err error) {
 		pErr := c.putTelegrafConfig(ctx, tx, tc)
 		if pErr != nil {
 			err = pErr
@@ -0,0 +1,459 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+)
+
+var (
+	telegrafTokenBucket = []byte("telegraftokensv1")
+	telegrafAgentBucket = []byte("telegrafagentsv1")
+)
+
+var _ platform.TelegrafAgentService = new(Service)
+
+func (c *Service) initializeTelegrafAgents(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(telegrafTokenBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(telegrafAgentBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateTelegrafToken creates the token of the telegraf config, owned by
+// userID, and deletes its previous token. The token may only write to the
+// buckets of the influxdb_v2 outputs of the config.
+func (c *Service) CreateTelegrafToken(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) (*platform.Authorization, error) {
+	op := OpPrefix + platform.OpCreateTelegrafToken
+	a := &platform.Authorization{
+		Description: fmt.Sprintf("token of the agents of telegraf config %s", tc.ID),
+		OrgID:       tc.OrganizationID,
+		UserID:      userID,
+	}
+
+	err := c.kv.Update(func(tx Tx) error {
+		// The buckets are those of the stored config.
+		stored, pErr := c.findTelegrafConfigByID(ctx, tx, tc.ID)
+		if pErr != nil {
+			pErr.Op = op
+			return pErr
+		}
+
+		ps, pErr := c.telegrafTokenPermissions(ctx, tx, stored)
+		if pErr != nil {
+			pErr.Op = op
+			return pErr
+		}
+		a.Permissions = ps
+
+		if pErr := c.deleteTelegrafToken(ctx, tx, tc.ID); pErr != nil && pErr.Code != platform.ENotFound {
+			pErr.Op = op
+			return pErr
+		}
+
+		token, err := c.TokenGenerator.Token()
+		if err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		a.Token = token
+		a.ID = c.IDGenerator.ID()
+		if pErr := c.putAuthorization(ctx, tx, a); pErr != nil {
+			pErr.Op = op
+			return pErr
+		}
+		if pErr := c.putTelegrafTokenID(ctx, tx, tc.ID, a.ID); pErr != nil {
+			pErr.Op = op
+			return pErr
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// telegrafTokenPermissions returns the write permissions on the buckets of
+// the influxdb_v2 outputs of tc.
+func (c *Service) telegrafTokenPermissions(ctx context.Context, tx Tx, tc *platform.TelegrafConfig) ([]platform.Permission, *platform.Error) {
+	var ps []platform.Permission
+	seen := make(map[platform.ID]bool)
+	for _, p := range tc.Plugins {
+		o, ok := p.Config.(*outputs.InfluxDBV2)
+		if !ok {
+			continue
+		}
+		b, pErr := c.findBucketByName(ctx, tx, tc.OrganizationID, o.Bucket)
+		if pErr != nil {
+			return nil, pErr
+		}
+		if seen[b.ID] {
+			continue
+		}
+		seen[b.ID] = true
+		perm, err := platform.NewPermissionAtID(b.ID, platform.WriteAction, platform.BucketsResourceType, tc.OrganizationID)
+		if err != nil {
+			return nil, &platform.Error{
+				Err: err,
+			}
+		}
+		ps = append(ps, *perm)
+	}
+	if len(ps) == 0 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "telegraf config has no influxdb_v2 output",
+		}
+	}
+	return ps, nil
+}
+
+// FindTelegrafToken returns the token of the telegraf config.
+func (c *Service) FindTelegrafToken(ctx context.Context, telegrafID platform.ID) (a *platform.Authorization, err error) {
+	op := OpPrefix + platform.OpFindTelegrafToken
+	err = c.kv.View(func(tx Tx) error {
+		id, pErr := c.findTelegrafTokenID(ctx, tx, telegrafID)
+		if pErr != nil {
+			pErr.Op = op
+			return pErr
+		}
+		a, pErr = c.findAuthorizationByID(ctx, tx, id)
+		if pErr != nil {
+			pErr.Op = op
+			return pErr
+		}
+		return nil
+	})
+	return a, err
+}
+
+func (c *Service) findTelegrafTokenID(ctx context.Context, tx Tx, telegrafID platform.ID) (platform.ID, *platform.Error) {
+	var id platform.ID
+	encodedID, err := telegrafID.Encode()
+	if err != nil {
+		return id, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	b, err := tx.Bucket(telegrafTokenBucket)
+	if err != nil {
+		return id, &platform.Error{
+			Err: err,
+		}
+	}
+	v, err := b.Get(encodedID)
+	if err == ErrKeyNotFound {
+		return id, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrTelegrafTokenNotFound,
+		}
+	}
+	if err != nil {
+		return id, &platform.Error{
+			Err: err,
+		}
+	}
+	if err := id.Decode(v); err != nil {
+		return id, &platform.Error{
+			Err: err,
+		}
+	}
+	return id, nil
+}
+
+func (c *Service) putTelegrafTokenID(ctx context.Context, tx Tx, telegrafID, id platform.ID) *platform.Error {
+	encodedTelegrafID, err := telegrafID.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	b, err := tx.Bucket(telegrafTokenBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	if err := b.Put(encodedTelegrafID, encodedID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// deleteTelegrafToken deletes the token of the telegraf config and its
+// authorization.
+func (c *Service) deleteTelegrafToken(ctx context.Context, tx Tx, telegrafID platform.ID) *platform.Error {
+	id, pErr := c.findTelegrafTokenID(ctx, tx, telegrafID)
+	if pErr != nil {
+		return pErr
+	}
+	// The authorization may have been deleted with the authorization API.
+	if pErr := c.deleteAuthorization(ctx, tx, id); pErr != nil && pErr.Code != platform.ENotFound {
+		return pErr
+	}
+
+	encodedID, err := telegrafID.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	b, err := tx.Bucket(telegrafTokenBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	if err := b.Delete(encodedID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// PutTelegrafAgent records the last fetch of a telegraf config by an agent.
+func (c *Service) PutTelegrafAgent(ctx context.Context, a *platform.TelegrafAgent) error {
+	op := OpPrefix + platform.OpPutTelegrafAgent
+	if a.Hostname == "" {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  "hostname is missing",
+		}
+	}
+	key, err := telegrafAgentKey(a.TelegrafID, a.Hostname)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Err:  err,
+		}
+	}
+	v, err := json.Marshal(a)
+	if err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return c.kv.Update(func(tx Tx) error {
+		b, err := tx.Bucket(telegrafAgentBucket)
+		if err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		if err := b.Put(key, v); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return nil
+	})
+}
+
+// FindTelegrafAgents returns the agents that fetched the telegraf config,
+// sorted by hostname.
+func (c *Service) FindTelegrafAgents(ctx context.Context, telegrafID platform.ID) (as []*platform.TelegrafAgent, err error) {
+	op := OpPrefix + platform.OpFindTelegrafAgents
+	err = c.kv.View(func(tx Tx) error {
+		var pErr *platform.Error
+		as, pErr = c.findTelegrafAgents(ctx, tx, telegrafID)
+		if pErr != nil {
+			pErr.Op = op
+			return pErr
+		}
+		return nil
+	})
+	return as, err
+}
+
+func (c *Service) findTelegrafAgents(ctx context.Context, tx Tx, telegrafID platform.ID) ([]*platform.TelegrafAgent, *platform.Error) {
+	prefix, err := telegrafID.Encode()
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	b, err := tx.Bucket(telegrafAgentBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	as := []*platform.TelegrafAgent{}
+	for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		a := new(platform.TelegrafAgent)
+		if err := json.Unmarshal(v, a); err != nil {
+			return nil, &platform.Error{
+				Err: err,
+			}
+		}
+		as = append(as, a)
+	}
+	return as, nil
+}
+
+// DeleteTelegrafAgents removes the token and the agents of the telegraf config.
+func (c *Service) DeleteTelegrafAgents(ctx context.Context, telegrafID platform.ID) error {
+	op := OpPrefix + platform.OpDeleteTelegrafAgents
+	return c.kv.Update(func(tx Tx) error {
+		if pErr := c.deleteTelegrafToken(ctx, tx, telegrafID); pErr != nil && pErr.Code != platform.ENotFound {
+			pErr.Op = op
+			return pErr
+		}
+
+		as, pErr := c.findTelegrafAgents(ctx, tx, telegrafID)
+		if pErr != nil {
+			pErr.Op = op
+			return pErr
+		}
+		b, err := tx.Bucket(telegrafAgentBucket)
+		if err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		for _, a := range as {
+			key, err := telegrafAgentKey(a.TelegrafID, a.Hostname)
+			if err != nil {
+				return &platform.Error{
+					Op:  op,
+					Err: err,
+				}
+			}
+			if err := b.Delete(key); err != nil {
+				return &platform.Error{
+					Op:  op,
+					Err: err,
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// telegrafAgentKey is the key of an agent, the id of its config followed by
+// its hostname.
+func telegrafAgentKey(telegrafID platform.ID, hostname string) ([]byte, error) {
+	encodedID, err := telegrafID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, 0, platform.IDLength+len(hostname))
+	key = append(key, encodedID...)
+	key = append(key, hostname...)
+	return key, nil
+}
+
+// scopeTelegrafTokens restricts the tokens created before the tokens were
+// scoped to the buckets of the influxdb_v2 outputs of their config.  The
+// tokens of configs without such outputs lose all their permissions.
+func (c *Service) scopeTelegrafTokens(ctx context.Context, tx Tx) error {
+	return c.forEachTelegrafToken(ctx, tx, func(tc *platform.TelegrafConfig, a *platform.Authorization) *platform.Error {
+		ps, pErr := c.telegrafTokenPermissions(ctx, tx, tc)
+		if pErr != nil {
+			ps = []platform.Permission{}
+		}
+		a.Permissions = ps
+		return c.putAuthorization(ctx, tx, a)
+	})
+}
+
+// unscopeTelegrafTokens reverts scopeTelegrafTokens; the tokens may write to
+// every bucket of the organization again.
+func (c *Service) unscopeTelegrafTokens(ctx context.Context, tx Tx) error {
+	return c.forEachTelegrafToken(ctx, tx, func(tc *platform.TelegrafConfig, a *platform.Authorization) *platform.Error {
+		p, err := platform.NewPermission(platform.WriteAction, platform.BucketsResourceType, tc.OrganizationID)
+		if err != nil {
+			return &platform.Error{
+				Err: err,
+			}
+		}
+		a.Permissions = []platform.Permission{*p}
+		return c.putAuthorization(ctx, tx, a)
+	})
+}
+
+// forEachTelegrafToken calls fn with every telegraf config that has a token
+// and the authorization of the token.
+func (c *Service) forEachTelegrafToken(ctx context.Context, tx Tx, fn func(*platform.TelegrafConfig, *platform.Authorization) *platform.Error) error {
+	b, err := tx.Bucket(telegrafTokenBucket)
+	if err != nil {
+		return err
+	}
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	// The ids are collected first as fn writes to the store.
+	var telegrafIDs, ids []platform.ID
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		var telegrafID, id platform.ID
+		if err := telegrafID.Decode(k); err != nil {
+			return err
+		}
+		if err := id.Decode(v); err != nil {
+			return err
+		}
+		telegrafIDs = append(telegrafIDs, telegrafID)
+		ids = append(ids, id)
+	}
+
+	for i := range ids {
+		tc, pErr := c.findTelegrafConfigByID(ctx, tx, telegrafIDs[i])
+		if pErr != nil && pErr.Code == platform.ENotFound {
+			continue
+		}
+		if pErr != nil {
+			return pErr
+		}
+		// The authorization may have been deleted with the authorization API.
+		a, pErr := c.findAuthorizationByID(ctx, tx, ids[i])
+		if pErr != nil && pErr.Code == platform.ENotFound {
+			continue
+		}
+		if pErr != nil {
+			return pErr
+		}
+		if pErr := fn(tc, a); pErr != nil {
+			return pErr
+		}
+	}
+	return nil
+}
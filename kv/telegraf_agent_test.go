@@ -0,0 +1,195 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+)
+
+func TestTelegrafAgentService(t *testing.T) {
+	s, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	defer closeFn()
+	ctx := context.Background()
+
+	user := &platform.User{Name: "user1"}
+	if err := s.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	org := &platform.Organization{Name: "org1"}
+	if err := s.CreateOrganization(ctx, org); err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	bucket := &platform.Bucket{OrganizationID: org.ID, Name: "bucket1"}
+	if err := s.CreateBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+	tc := &platform.TelegrafConfig{
+		OrganizationID: org.ID,
+		Name:           "tc1",
+		Agent:          platform.TelegrafAgentConfig{Interval: 10000},
+	}
+	if err := s.CreateTelegrafConfig(ctx, tc, user.ID); err != nil {
+		t.Fatalf("failed to create telegraf config: %v", err)
+	}
+	if _, err := s.CreateTelegrafToken(ctx, tc, user.ID); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected a config without output to have no token, got %v", err)
+	}
+
+	tc.Plugins = []platform.TelegrafPlugin{
+		{Config: &outputs.InfluxDBV2{URLs: []string{"http://127.0.0.1:9999"}, Organization: "org1", Bucket: "bucket1"}},
+		{Config: &outputs.InfluxDBV2{URLs: []string{"http://127.0.0.2:9999"}, Organization: "org1", Bucket: "bucket1"}},
+	}
+	if _, err := s.UpdateTelegrafConfig(ctx, tc.ID, tc, user.ID); err != nil {
+		t.Fatalf("failed to update telegraf config: %v", err)
+	}
+
+	if _, err := s.FindTelegrafToken(ctx, tc.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the config to have no token, got %v", err)
+	}
+
+	a1, err := s.CreateTelegrafToken(ctx, tc, user.ID)
+	if err != nil {
+		t.Fatalf("failed to create telegraf token: %v", err)
+	}
+	// The token may only write to the bucket of the outputs.
+	p, _ := platform.NewPermissionAtID(bucket.ID, platform.WriteAction, platform.BucketsResourceType, org.ID)
+	if diff := cmp.Diff(a1.Permissions, []platform.Permission{*p}); diff != "" {
+		t.Fatalf("token permissions are different -got/+want\ndiff %s", diff)
+	}
+	found, err := s.FindTelegrafToken(ctx, tc.ID)
+	if err != nil {
+		t.Fatalf("failed to find telegraf token: %v", err)
+	}
+	if found.Token != a1.Token {
+		t.Fatalf("expected token %q, got %q", a1.Token, found.Token)
+	}
+
+	// A new token replaces the previous one.
+	a2, err := s.CreateTelegrafToken(ctx, tc, user.ID)
+	if err != nil {
+		t.Fatalf("failed to create telegraf token: %v", err)
+	}
+	if _, err := s.FindAuthorizationByID(ctx, a1.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the previous token to be deleted, got %v", err)
+	}
+	if found, err = s.FindTelegrafToken(ctx, tc.ID); err != nil || found.Token != a2.Token {
+		t.Fatalf("expected token %q, got %v, %v", a2.Token, found, err)
+	}
+
+	now := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+	agents := []*platform.TelegrafAgent{
+		{TelegrafID: tc.ID, Hostname: "host2", Version: "v1", FetchedAt: now},
+		{TelegrafID: tc.ID, Hostname: "host1", Version: "v1", FetchedAt: now},
+		{TelegrafID: tc.ID, Hostname: "host2", Version: "v2", FetchedAt: now.Add(time.Minute)},
+		{TelegrafID: tc.ID + 1, Hostname: "host3", Version: "v1", FetchedAt: now},
+	}
+	for _, a := range agents {
+		if err := s.PutTelegrafAgent(ctx, a); err != nil {
+			t.Fatalf("failed to put telegraf agent: %v", err)
+		}
+	}
+	got, err := s.FindTelegrafAgents(ctx, tc.ID)
+	if err != nil {
+		t.Fatalf("failed to find telegraf agents: %v", err)
+	}
+	if diff := cmp.Diff(got, []*platform.TelegrafAgent{agents[1], agents[2]}); diff != "" {
+		t.Fatalf("telegraf agents are different -got/+want\ndiff %s", diff)
+	}
+
+	if err := s.DeleteTelegrafAgents(ctx, tc.ID); err != nil {
+		t.Fatalf("failed to delete telegraf agents: %v", err)
+	}
+	if _, err := s.FindTelegrafToken(ctx, tc.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the token to be deleted, got %v", err)
+	}
+	if _, err := s.FindAuthorizationByID(ctx, a2.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the authorization to be deleted, got %v", err)
+	}
+	if got, err = s.FindTelegrafAgents(ctx, tc.ID); err != nil || len(got) != 0 {
+		t.Fatalf("expected no agents, got %v, %v", got, err)
+	}
+	if got, err = s.FindTelegrafAgents(ctx, tc.ID+1); err != nil || len(got) != 1 {
+		t.Fatalf("expected the agents of other configs to be kept, got %v, %v", got, err)
+	}
+}
+
+func TestTelegrafAgentService_ScopeTokensMigration(t *testing.T) {
+	s, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	defer closeFn()
+	ctx := context.Background()
+
+	user := &platform.User{Name: "user1"}
+	if err := s.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	org := &platform.Organization{Name: "org1"}
+	if err := s.CreateOrganization(ctx, org); err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	bucket := &platform.Bucket{OrganizationID: org.ID, Name: "bucket1"}
+	if err := s.CreateBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+	tc := &platform.TelegrafConfig{
+		OrganizationID: org.ID,
+		Name:           "tc1",
+		Agent:          platform.TelegrafAgentConfig{Interval: 10000},
+		Plugins: []platform.TelegrafPlugin{
+			{Config: &outputs.InfluxDBV2{URLs: []string{"http://127.0.0.1:9999"}, Organization: "org1", Bucket: "bucket1"}},
+		},
+	}
+	if err := s.CreateTelegrafConfig(ctx, tc, user.ID); err != nil {
+		t.Fatalf("failed to create telegraf config: %v", err)
+	}
+	a, err := s.CreateTelegrafToken(ctx, tc, user.ID)
+	if err != nil {
+		t.Fatalf("failed to create telegraf token: %v", err)
+	}
+
+	m := s.Migrator()
+	states, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	if len(states) != 1 || states[0].AppliedAt == nil {
+		t.Fatalf("expected the migration to be applied on initialize, got %+v", states)
+	}
+
+	// Reverting gives the token the permissions of the tokens created
+	// before the migration.
+	if n, err := m.Down(ctx, 1); err != nil || n != 1 {
+		t.Fatalf("got %d migrations reverted and error %v, want 1", n, err)
+	}
+	orgWrite, _ := platform.NewPermission(platform.WriteAction, platform.BucketsResourceType, org.ID)
+	found, err := s.FindAuthorizationByID(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("failed to find authorization: %v", err)
+	}
+	if diff := cmp.Diff(found.Permissions, []platform.Permission{*orgWrite}); diff != "" {
+		t.Fatalf("reverted token permissions are different -got/+want\ndiff %s", diff)
+	}
+
+	if n, err := m.Up(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d migrations applied and error %v, want 1", n, err)
+	}
+	bucketWrite, _ := platform.NewPermissionAtID(bucket.ID, platform.WriteAction, platform.BucketsResourceType, org.ID)
+	if found, err = s.FindAuthorizationByID(ctx, a.ID); err != nil {
+		t.Fatalf("failed to find authorization: %v", err)
+	}
+	if diff := cmp.Diff(found.Permissions, []platform.Permission{*bucketWrite}); diff != "" {
+		t.Fatalf("migrated token permissions are different -got/+want\ndiff %s", diff)
+	}
+	if found.Token != a.Token {
+		t.Fatalf("expected the token %q to be kept, got %q", a.Token, found.Token)
+	}
+}
@@ -1,4 +1,4 @@
-package bolt
+package kv
 
 import (
 	"context"
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 	platformcontext "github.com/influxdata/influxdb/context"
 )
@@ -17,28 +16,28 @@ var (
 	userpasswordBucket = []byte("userspasswordv1")
 )
 
-var _ platform.UserService = (*Client)(nil)
-var _ platform.UserOperationLogService = (*Client)(nil)
-var _ platform.BasicAuthService = (*Client)(nil)
+var _ platform.UserService = (*Service)(nil)
+var _ platform.UserOperationLogService = (*Service)(nil)
+var _ platform.BasicAuthService = (*Service)(nil)
 
-func (c *Client) initializeUsers(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(userUser)); err != nil {
+func (c *Service) initializeUsers(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(userUser)); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists([]byte(userIndex)); err != nil {
+	if _, err := tx.Bucket([]byte(userIndex)); err != nil {
 		return err
 	}
-	if _, err := tx.CreateBucketIfNotExists([]byte(userpasswordBucket)); err != nil {
+	if _, err := tx.Bucket([]byte(userpasswordBucket)); err != nil {
 		return err
 	}
 	return nil
 }
 
 // FindUserByID retrieves a user by id.
-func (c *Client) FindUserByID(ctx context.Context, id platform.ID) (*platform.User, error) {
+func (c *Service) FindUserByID(ctx context.Context, id platform.ID) (*platform.User, error) {
 	var u *platform.User
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		usr, pe := c.findUserByID(ctx, tx, id)
 		if pe != nil {
 			return pe
@@ -57,7 +56,7 @@ func (c *Client) FindUserByID(ctx context.Context, id platform.ID) (*platform.Us
 	return u, nil
 }
 
-func (c *Client) findUserByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.User, *platform.Error) {
+func (c *Service) findUserByID(ctx context.Context, tx Tx, id platform.ID) (*platform.User, *platform.Error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &platform.Error{
@@ -66,14 +65,26 @@ func (c *Client) findUserByID(ctx context.Context, tx *bolt.Tx, id platform.ID)
 	}
 
 	var u platform.User
-	v := tx.Bucket(userUser).Get(encodedID)
+	b, err := tx.Bucket(userUser)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encodedID)
 
-	if len(v) == 0 {
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "user not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	if err := json.Unmarshal(v, &u); err != nil {
 		return nil, &platform.Error{
@@ -85,10 +96,10 @@ func (c *Client) findUserByID(ctx context.Context, tx *bolt.Tx, id platform.ID)
 }
 
 // FindUserByName returns a user by name for a particular user.
-func (c *Client) FindUserByName(ctx context.Context, n string) (*platform.User, error) {
+func (c *Service) FindUserByName(ctx context.Context, n string) (*platform.User, error) {
 	var u *platform.User
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		usr, pe := c.findUserByName(ctx, tx, n)
 		if pe != nil {
 			return pe
@@ -100,14 +111,26 @@ func (c *Client) FindUserByName(ctx context.Context, n string) (*platform.User,
 	return u, err
 }
 
-func (c *Client) findUserByName(ctx context.Context, tx *bolt.Tx, n string) (*platform.User, *platform.Error) {
-	u := tx.Bucket(userIndex).Get(userIndexKey(n))
-	if u == nil {
+func (c *Service) findUserByName(ctx context.Context, tx Tx, n string) (*platform.User, *platform.Error) {
+	idx, err := tx.Bucket(userIndex)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	u, err := idx.Get(userIndexKey(n))
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "user not found",
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	var id platform.ID
 	if err := id.Decode(u); err != nil {
@@ -121,7 +144,7 @@ func (c *Client) findUserByName(ctx context.Context, tx *bolt.Tx, n string) (*pl
 // FindUser retrives a user using an arbitrary user filter.
 // Filters using ID, or Name should be efficient.
 // Other filters will do a linear scan across users until it finds a match.
-func (c *Client) FindUser(ctx context.Context, filter platform.UserFilter) (*platform.User, error) {
+func (c *Service) FindUser(ctx context.Context, filter platform.UserFilter) (*platform.User, error) {
 	var u *platform.User
 	var err error
 	op := getOp(platform.OpFindUser)
@@ -149,7 +172,7 @@ func (c *Client) FindUser(ctx context.Context, filter platform.UserFilter) (*pla
 
 	filterFn := filterUsersFn(filter)
 
-	err = c.db.View(func(tx *bolt.Tx) error {
+	err = c.kv.View(func(tx Tx) error {
 		return forEachUser(ctx, tx, func(usr *platform.User) bool {
 			if filterFn(usr) {
 				u = usr
@@ -195,7 +218,7 @@ func filterUsersFn(filter platform.UserFilter) func(u *platform.User) bool {
 // FindUsers retrives all users that match an arbitrary user filter.
 // Filters using ID, or Name should be efficient.
 // Other filters will do a linear scan across all users searching for a match.
-func (c *Client) FindUsers(ctx context.Context, filter platform.UserFilter, opt ...platform.FindOptions) ([]*platform.User, int, error) {
+func (c *Service) FindUsers(ctx context.Context, filter platform.UserFilter, opt ...platform.FindOptions) ([]*platform.User, int, error) {
 	op := getOp(platform.OpFindUsers)
 	if filter.ID != nil {
 		u, err := c.FindUserByID(ctx, *filter.ID)
@@ -223,7 +246,7 @@ func (c *Client) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 
 	us := []*platform.User{}
 	filterFn := filterUsersFn(filter)
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		return forEachUser(ctx, tx, func(u *platform.User) bool {
 			if filterFn(u) {
 				us = append(us, u)
@@ -240,8 +263,8 @@ func (c *Client) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 }
 
 // CreateUser creates a platform user and sets b.ID.
-func (c *Client) CreateUser(ctx context.Context, u *platform.User) error {
-	err := c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) CreateUser(ctx context.Context, u *platform.User) error {
+	err := c.kv.Update(func(tx Tx) error {
 		unique := c.uniqueUserName(ctx, tx, u)
 
 		if !unique {
@@ -269,13 +292,13 @@ func (c *Client) CreateUser(ctx context.Context, u *platform.User) error {
 }
 
 // PutUser will put a user without setting an ID.
-func (c *Client) PutUser(ctx context.Context, u *platform.User) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutUser(ctx context.Context, u *platform.User) error {
+	return c.kv.Update(func(tx Tx) error {
 		return c.putUser(ctx, tx, u)
 	})
 }
 
-func (c *Client) putUser(ctx context.Context, tx *bolt.Tx, u *platform.User) error {
+func (c *Service) putUser(ctx context.Context, tx Tx, u *platform.User) error {
 	v, err := json.Marshal(u)
 	if err != nil {
 		return err
@@ -284,10 +307,20 @@ func (c *Client) putUser(ctx context.Context, tx *bolt.Tx, u *platform.User) err
 	if err != nil {
 		return err
 	}
-	if err := tx.Bucket(userIndex).Put(userIndexKey(u.Name), encodedID); err != nil {
+	idx, err := tx.Bucket(userIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Put(userIndexKey(u.Name), encodedID); err != nil {
+		return err
+	}
+	b, err := tx.Bucket(userUser)
+	if err != nil {
 		return err
 	}
-	return tx.Bucket(userUser).Put(encodedID, v)
+
+	return b.Put(encodedID, v)
 }
 
 func userIndexKey(n string) []byte {
@@ -295,8 +328,16 @@ func userIndexKey(n string) []byte {
 }
 
 // forEachUser will iterate through all users while fn returns true.
-func forEachUser(ctx context.Context, tx *bolt.Tx, fn func(*platform.User) bool) error {
-	cur := tx.Bucket(userUser).Cursor()
+func forEachUser(ctx context.Context, tx Tx, fn func(*platform.User) bool) error {
+	b, err := tx.Bucket(userUser)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
 		u := &platform.User{}
 		if err := json.Unmarshal(v, u); err != nil {
@@ -310,15 +351,23 @@ func forEachUser(ctx context.Context, tx *bolt.Tx, fn func(*platform.User) bool)
 	return nil
 }
 
-func (c *Client) uniqueUserName(ctx context.Context, tx *bolt.Tx, u *platform.User) bool {
-	v := tx.Bucket(userIndex).Get(userIndexKey(u.Name))
+func (c *Service) uniqueUserName(ctx context.Context, tx Tx, u *platform.User) bool {
+	idx, err := tx.Bucket(userIndex)
+	if err != nil {
+		return false
+	}
+
+	v, err := idx.Get(userIndexKey(u.Name))
+	if err != nil && err != ErrKeyNotFound {
+		return false
+	}
 	return len(v) == 0
 }
 
 // UpdateUser updates a user according the parameters set on upd.
-func (c *Client) UpdateUser(ctx context.Context, id platform.ID, upd platform.UserUpdate) (*platform.User, error) {
+func (c *Service) UpdateUser(ctx context.Context, id platform.ID, upd platform.UserUpdate) (*platform.User, error) {
 	var u *platform.User
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		usr, pe := c.updateUser(ctx, tx, id, upd)
 		if pe != nil {
 			return &platform.Error{
@@ -333,7 +382,7 @@ func (c *Client) UpdateUser(ctx context.Context, id platform.ID, upd platform.Us
 	return u, err
 }
 
-func (c *Client) updateUser(ctx context.Context, tx *bolt.Tx, id platform.ID, upd platform.UserUpdate) (*platform.User, *platform.Error) {
+func (c *Service) updateUser(ctx context.Context, tx Tx, id platform.ID, upd platform.UserUpdate) (*platform.User, *platform.Error) {
 	u, err := c.findUserByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
@@ -342,7 +391,14 @@ func (c *Client) updateUser(ctx context.Context, tx *bolt.Tx, id platform.ID, up
 	if upd.Name != nil {
 		// Users are indexed by name and so the user index must be pruned
 		// when name is modified.
-		if err := tx.Bucket(userIndex).Delete(userIndexKey(u.Name)); err != nil {
+		idx, err := tx.Bucket(userIndex)
+		if err != nil {
+			return nil, &platform.Error{
+				Err: err,
+			}
+		}
+
+		if err := idx.Delete(userIndexKey(u.Name)); err != nil {
 			return nil, &platform.Error{
 				Err: err,
 			}
@@ -366,8 +422,8 @@ func (c *Client) updateUser(ctx context.Context, tx *bolt.Tx, id platform.ID, up
 }
 
 // DeleteUser deletes a user and prunes it from the index.
-func (c *Client) DeleteUser(ctx context.Context, id platform.ID) error {
-	err := c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteUser(ctx context.Context, id platform.ID) error {
+	err := c.kv.Update(func(tx Tx) error {
 		if pe := c.deleteUsersAuthorizations(ctx, tx, id); pe != nil {
 			return pe
 		}
@@ -385,7 +441,7 @@ func (c *Client) DeleteUser(ctx context.Context, id platform.ID) error {
 	return nil
 }
 
-func (c *Client) deleteUser(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+func (c *Service) deleteUser(ctx context.Context, tx Tx, id platform.ID) *platform.Error {
 	u, pe := c.findUserByID(ctx, tx, id)
 	if pe != nil {
 		return pe
@@ -396,12 +452,26 @@ func (c *Client) deleteUser(ctx context.Context, tx *bolt.Tx, id platform.ID) *p
 			Err: err,
 		}
 	}
-	if err := tx.Bucket(userIndex).Delete(userIndexKey(u.Name)); err != nil {
+	idx, err := tx.Bucket(userIndex)
+	if err != nil {
 		return &platform.Error{
 			Err: err,
 		}
 	}
-	if err := tx.Bucket(userUser).Delete(encodedID); err != nil {
+
+	if err := idx.Delete(userIndexKey(u.Name)); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	b, err := tx.Bucket(userUser)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -416,7 +486,7 @@ func (c *Client) deleteUser(ctx context.Context, tx *bolt.Tx, id platform.ID) *p
 	return nil
 }
 
-func (c *Client) deleteUsersAuthorizations(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+func (c *Service) deleteUsersAuthorizations(ctx context.Context, tx Tx, id platform.ID) *platform.Error {
 	authFilter := platform.AuthorizationFilter{
 		UserID: &id,
 	}
@@ -435,11 +505,11 @@ func (c *Client) deleteUsersAuthorizations(ctx context.Context, tx *bolt.Tx, id
 }
 
 // GetUserOperationLog retrieves a user operation log.
-func (c *Client) GetUserOperationLog(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.OperationLogEntry, int, error) {
+func (c *Service) GetUserOperationLog(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.OperationLogEntry, int, error) {
 	// TODO(desa): might be worthwhile to allocate a slice of size opts.Limit
 	log := []*platform.OperationLogEntry{}
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		key, err := encodeBucketOperationLogKey(id)
 		if err != nil {
 			return err
@@ -479,7 +549,7 @@ func encodeUserOperationLogKey(id platform.ID) ([]byte, error) {
 	return append([]byte(bucketOperationLogKeyPrefix), buf...), nil
 }
 
-func (c *Client) appendUserEventToLog(ctx context.Context, tx *bolt.Tx, id platform.ID, s string) error {
+func (c *Service) appendUserEventToLog(ctx context.Context, tx Tx, id platform.ID, s string) error {
 	e := &platform.OperationLogEntry{
 		Description: s,
 	}
@@ -1,11 +1,10 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -13,8 +12,8 @@ var (
 	userResourceMappingBucket = []byte("userresourcemappingsv1")
 )
 
-func (c *Client) initializeUserResourceMappings(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(userResourceMappingBucket)); err != nil {
+func (c *Service) initializeUserResourceMappings(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(userResourceMappingBucket)); err != nil {
 		return err
 	}
 	return nil
@@ -30,9 +29,9 @@ func filterMappingsFn(filter platform.UserResourceMappingFilter) func(m *platfor
 }
 
 // FindUserResourceMappings returns a list of UserResourceMappings that match filter and the total count of matching mappings.
-func (c *Client) FindUserResourceMappings(ctx context.Context, filter platform.UserResourceMappingFilter, opt ...platform.FindOptions) ([]*platform.UserResourceMapping, int, error) {
+func (c *Service) FindUserResourceMappings(ctx context.Context, filter platform.UserResourceMappingFilter, opt ...platform.FindOptions) ([]*platform.UserResourceMapping, int, error) {
 	ms := []*platform.UserResourceMapping{}
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		mappings, err := c.findUserResourceMappings(ctx, tx, filter)
 		if err != nil {
 			return err
@@ -48,7 +47,7 @@ func (c *Client) FindUserResourceMappings(ctx context.Context, filter platform.U
 	return ms, len(ms), nil
 }
 
-func (c *Client) findUserResourceMappings(ctx context.Context, tx *bolt.Tx, filter platform.UserResourceMappingFilter) ([]*platform.UserResourceMapping, error) {
+func (c *Service) findUserResourceMappings(ctx context.Context, tx Tx, filter platform.UserResourceMappingFilter) ([]*platform.UserResourceMapping, error) {
 	ms := []*platform.UserResourceMapping{}
 	filterFn := filterMappingsFn(filter)
 	err := c.forEachUserResourceMapping(ctx, tx, func(m *platform.UserResourceMapping) bool {
@@ -65,7 +64,7 @@ func (c *Client) findUserResourceMappings(ctx context.Context, tx *bolt.Tx, filt
 	return ms, nil
 }
 
-func (c *Client) findUserResourceMapping(ctx context.Context, tx *bolt.Tx, filter platform.UserResourceMappingFilter) (*platform.UserResourceMapping, error) {
+func (c *Service) findUserResourceMapping(ctx context.Context, tx Tx, filter platform.UserResourceMappingFilter) (*platform.UserResourceMapping, error) {
 	ms, err := c.findUserResourceMappings(ctx, tx, filter)
 	if err != nil {
 		return nil, err
@@ -78,8 +77,8 @@ func (c *Client) findUserResourceMapping(ctx context.Context, tx *bolt.Tx, filte
 	return ms[0], nil
 }
 
-func (c *Client) CreateUserResourceMapping(ctx context.Context, m *platform.UserResourceMapping) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) CreateUserResourceMapping(ctx context.Context, m *platform.UserResourceMapping) error {
+	return c.kv.Update(func(tx Tx) error {
 		if err := c.createUserResourceMapping(ctx, tx, m); err != nil {
 			return err
 		}
@@ -92,7 +91,7 @@ func (c *Client) CreateUserResourceMapping(ctx context.Context, m *platform.User
 	})
 }
 
-func (c *Client) createUserResourceMapping(ctx context.Context, tx *bolt.Tx, m *platform.UserResourceMapping) error {
+func (c *Service) createUserResourceMapping(ctx context.Context, tx Tx, m *platform.UserResourceMapping) error {
 	unique := c.uniqueUserResourceMapping(ctx, tx, m)
 
 	if !unique {
@@ -109,7 +108,12 @@ func (c *Client) createUserResourceMapping(ctx context.Context, tx *bolt.Tx, m *
 		return err
 	}
 
-	if err := tx.Bucket(userResourceMappingBucket).Put(key, v); err != nil {
+	b, err := tx.Bucket(userResourceMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, v); err != nil {
 		return err
 	}
 
@@ -117,7 +121,7 @@ func (c *Client) createUserResourceMapping(ctx context.Context, tx *bolt.Tx, m *
 }
 
 // This method creates the user/resource mappings for resources that belong to an organization.
-func (c *Client) createOrgDependentMappings(ctx context.Context, tx *bolt.Tx, m *platform.UserResourceMapping) error {
+func (c *Service) createOrgDependentMappings(ctx context.Context, tx Tx, m *platform.UserResourceMapping) error {
 	bf := platform.BucketFilter{OrganizationID: &m.ResourceID}
 	bs, err := c.findBuckets(ctx, tx, bf)
 	if err != nil {
@@ -157,8 +161,16 @@ func userResourceKey(m *platform.UserResourceMapping) ([]byte, error) {
 	return key, nil
 }
 
-func (c *Client) forEachUserResourceMapping(ctx context.Context, tx *bolt.Tx, fn func(*platform.UserResourceMapping) bool) error {
-	cur := tx.Bucket(userResourceMappingBucket).Cursor()
+func (c *Service) forEachUserResourceMapping(ctx context.Context, tx Tx, fn func(*platform.UserResourceMapping) bool) error {
+	b, err := tx.Bucket(userResourceMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
 		m := &platform.UserResourceMapping{}
 		if err := json.Unmarshal(v, m); err != nil {
@@ -172,19 +184,27 @@ func (c *Client) forEachUserResourceMapping(ctx context.Context, tx *bolt.Tx, fn
 	return nil
 }
 
-func (c *Client) uniqueUserResourceMapping(ctx context.Context, tx *bolt.Tx, m *platform.UserResourceMapping) bool {
+func (c *Service) uniqueUserResourceMapping(ctx context.Context, tx Tx, m *platform.UserResourceMapping) bool {
 	key, err := userResourceKey(m)
 	if err != nil {
 		return false
 	}
 
-	v := tx.Bucket(userResourceMappingBucket).Get(key)
+	b, err := tx.Bucket(userResourceMappingBucket)
+	if err != nil {
+		return false
+	}
+
+	v, err := b.Get(key)
+	if err != nil && err != ErrKeyNotFound {
+		return false
+	}
 	return len(v) == 0
 }
 
 // DeleteUserResourceMapping deletes a user resource mapping.
-func (c *Client) DeleteUserResourceMapping(ctx context.Context, resourceID platform.ID, userID platform.ID) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteUserResourceMapping(ctx context.Context, resourceID platform.ID, userID platform.ID) error {
+	return c.kv.Update(func(tx Tx) error {
 		m, err := c.findUserResourceMapping(ctx, tx, platform.UserResourceMappingFilter{
 			ResourceID: resourceID,
 			UserID:     userID,
@@ -208,7 +228,7 @@ func (c *Client) DeleteUserResourceMapping(ctx context.Context, resourceID platf
 	})
 }
 
-func (c *Client) deleteUserResourceMapping(ctx context.Context, tx *bolt.Tx, filter platform.UserResourceMappingFilter) error {
+func (c *Service) deleteUserResourceMapping(ctx context.Context, tx Tx, filter platform.UserResourceMappingFilter) error {
 	ms, err := c.findUserResourceMappings(ctx, tx, filter)
 	if err != nil {
 		return err
@@ -222,10 +242,15 @@ func (c *Client) deleteUserResourceMapping(ctx context.Context, tx *bolt.Tx, fil
 		return err
 	}
 
-	return tx.Bucket(userResourceMappingBucket).Delete(key)
+	b, err := tx.Bucket(userResourceMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(key)
 }
 
-func (c *Client) deleteUserResourceMappings(ctx context.Context, tx *bolt.Tx, filter platform.UserResourceMappingFilter) error {
+func (c *Service) deleteUserResourceMappings(ctx context.Context, tx Tx, filter platform.UserResourceMappingFilter) error {
 	ms, err := c.findUserResourceMappings(ctx, tx, filter)
 	if err != nil {
 		return err
@@ -236,7 +261,12 @@ func (c *Client) deleteUserResourceMappings(ctx context.Context, tx *bolt.Tx, fi
 			return err
 		}
 
-		if err = tx.Bucket(userResourceMappingBucket).Delete(key); err != nil {
+		b, err := tx.Bucket(userResourceMappingBucket)
+		if err != nil {
+			return err
+		}
+
+		if err = b.Delete(key); err != nil {
 			return err
 		}
 	}
@@ -244,7 +274,7 @@ func (c *Client) deleteUserResourceMappings(ctx context.Context, tx *bolt.Tx, fi
 }
 
 // This method deletes the user/resource mappings for resources that belong to an organization.
-func (c *Client) deleteOrgDependentMappings(ctx context.Context, tx *bolt.Tx, m *platform.UserResourceMapping) error {
+func (c *Service) deleteOrgDependentMappings(ctx context.Context, tx Tx, m *platform.UserResourceMapping) error {
 	bf := platform.BucketFilter{OrganizationID: &m.ResourceID}
 	bs, err := c.findBuckets(ctx, tx, bf)
 	if err != nil {
@@ -1,4 +1,4 @@
-package inmem
+package kv_test
 
 import (
 	"context"
@@ -9,15 +9,25 @@ import (
 )
 
 func initUserResourceMappingService(f platformtesting.UserResourceFields, t *testing.T) (platform.UserResourceMappingService, func()) {
-	s := NewService()
-	ctx := context.TODO()
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	ctx := context.Background()
 	for _, m := range f.UserResourceMappings {
-		if err := s.CreateUserResourceMapping(ctx, m); err != nil {
+		if err := c.CreateUserResourceMapping(ctx, m); err != nil {
 			t.Fatalf("failed to populate mappings")
 		}
 	}
 
-	return s, func() {}
+	return c, func() {
+		defer closeFn()
+		for _, m := range f.UserResourceMappings {
+			if err := c.DeleteUserResourceMapping(ctx, m.ResourceID, m.UserID); err != nil {
+				t.Logf("failed to remove user resource mapping: %v", err)
+			}
+		}
+	}
 }
 
 func TestUserResourceMappingService_FindUserResourceMappings(t *testing.T) {
@@ -1,26 +1,37 @@
-package inmem
+package kv_test
 
 import (
 	"context"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
 	platformtesting "github.com/influxdata/influxdb/testing"
 )
 
 func initUserService(f platformtesting.UserFields, t *testing.T) (platform.UserService, string, func()) {
-	s := NewService()
-	s.IDGenerator = f.IDGenerator
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
+
 	ctx := context.Background()
 	for _, u := range f.Users {
-		if err := s.PutUser(ctx, u); err != nil {
+		if err := c.PutUser(ctx, u); err != nil {
 			t.Fatalf("failed to populate users")
 		}
 	}
-	return s, OpPrefix, func() {}
+	return c, kv.OpPrefix, func() {
+		defer closeFn()
+		for _, u := range f.Users {
+			if err := c.DeleteUser(ctx, u.ID); err != nil {
+				t.Logf("failed to remove users: %v", err)
+			}
+		}
+	}
 }
 
 func TestUserService(t *testing.T) {
-	t.Parallel()
 	platformtesting.UserService(initUserService, t)
 }
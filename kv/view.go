@@ -1,11 +1,10 @@
-package bolt
+package kv
 
 import (
 	"context"
 	"encoding/json"
 	"sync"
 
-	bolt "github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -13,18 +12,18 @@ var (
 	viewBucket = []byte("viewsv2")
 )
 
-func (c *Client) initializeViews(ctx context.Context, tx *bolt.Tx) error {
-	if _, err := tx.CreateBucketIfNotExists([]byte(viewBucket)); err != nil {
+func (c *Service) initializeViews(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket([]byte(viewBucket)); err != nil {
 		return err
 	}
 	return nil
 }
 
 // FindViewByID retrieves a view by id.
-func (c *Client) FindViewByID(ctx context.Context, id platform.ID) (*platform.View, error) {
+func (c *Service) FindViewByID(ctx context.Context, id platform.ID) (*platform.View, error) {
 	var d *platform.View
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		dash, err := c.findViewByID(ctx, tx, id)
 		if err != nil {
 			return &platform.Error{
@@ -39,7 +38,7 @@ func (c *Client) FindViewByID(ctx context.Context, id platform.ID) (*platform.Vi
 	return d, err
 }
 
-func (c *Client) findViewByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.View, *platform.Error) {
+func (c *Service) findViewByID(ctx context.Context, tx Tx, id platform.ID) (*platform.View, *platform.Error) {
 	var d platform.View
 
 	encodedID, err := id.Encode()
@@ -49,13 +48,25 @@ func (c *Client) findViewByID(ctx context.Context, tx *bolt.Tx, id platform.ID)
 		}
 	}
 
-	v := tx.Bucket(viewBucket).Get(encodedID)
-	if len(v) == 0 {
+	b, err := tx.Bucket(viewBucket)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encodedID)
+	if err == ErrKeyNotFound {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  platform.ErrViewNotFound,
 		}
 	}
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 
 	if err := json.Unmarshal(v, &d); err != nil {
 		return nil, &platform.Error{
@@ -67,13 +78,13 @@ func (c *Client) findViewByID(ctx context.Context, tx *bolt.Tx, id platform.ID)
 }
 
 // FindView retrieves a view using an arbitrary view filter.
-func (c *Client) FindView(ctx context.Context, filter platform.ViewFilter) (*platform.View, error) {
+func (c *Service) FindView(ctx context.Context, filter platform.ViewFilter) (*platform.View, error) {
 	if filter.ID != nil {
 		return c.FindViewByID(ctx, *filter.ID)
 	}
 
 	var d *platform.View
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		filterFn := filterViewsFn(filter)
 		return c.forEachView(ctx, tx, func(dash *platform.View) bool {
 			if filterFn(dash) {
@@ -122,7 +133,7 @@ func filterViewsFn(filter platform.ViewFilter) func(v *platform.View) bool {
 }
 
 // FindViews retrives all views that match an arbitrary view filter.
-func (c *Client) FindViews(ctx context.Context, filter platform.ViewFilter) ([]*platform.View, int, error) {
+func (c *Service) FindViews(ctx context.Context, filter platform.ViewFilter) ([]*platform.View, int, error) {
 	ds := []*platform.View{}
 	op := getOp(platform.OpFindViews)
 	if filter.ID != nil {
@@ -140,7 +151,7 @@ func (c *Client) FindViews(ctx context.Context, filter platform.ViewFilter) ([]*
 		return ds, 1, nil
 	}
 
-	err := c.db.View(func(tx *bolt.Tx) error {
+	err := c.kv.View(func(tx Tx) error {
 		dashs, err := c.findViews(ctx, tx, filter)
 		if err != nil {
 			return &platform.Error{
@@ -155,7 +166,7 @@ func (c *Client) FindViews(ctx context.Context, filter platform.ViewFilter) ([]*
 	return ds, len(ds), err
 }
 
-func (c *Client) findViews(ctx context.Context, tx *bolt.Tx, filter platform.ViewFilter) ([]*platform.View, error) {
+func (c *Service) findViews(ctx context.Context, tx Tx, filter platform.ViewFilter) ([]*platform.View, error) {
 	ds := []*platform.View{}
 
 	filterFn := filterViewsFn(filter)
@@ -174,8 +185,8 @@ func (c *Client) findViews(ctx context.Context, tx *bolt.Tx, filter platform.Vie
 }
 
 // CreateView creates a platform view and sets d.ID.
-func (c *Client) CreateView(ctx context.Context, d *platform.View) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) CreateView(ctx context.Context, d *platform.View) error {
+	return c.kv.Update(func(tx Tx) error {
 		if pe := c.createView(ctx, tx, d); pe != nil {
 			return &platform.Error{
 				Op:  getOp(platform.OpCreateView),
@@ -186,14 +197,14 @@ func (c *Client) CreateView(ctx context.Context, d *platform.View) error {
 	})
 }
 
-func (c *Client) createView(ctx context.Context, tx *bolt.Tx, d *platform.View) *platform.Error {
+func (c *Service) createView(ctx context.Context, tx Tx, d *platform.View) *platform.Error {
 	d.ID = c.IDGenerator.ID()
 	return c.putView(ctx, tx, d)
 }
 
 // PutView will put a view without setting an ID.
-func (c *Client) PutView(ctx context.Context, d *platform.View) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) PutView(ctx context.Context, d *platform.View) error {
+	return c.kv.Update(func(tx Tx) error {
 		if pe := c.putView(ctx, tx, d); pe != nil {
 			return pe
 		}
@@ -201,7 +212,7 @@ func (c *Client) PutView(ctx context.Context, d *platform.View) error {
 	})
 }
 
-func (c *Client) putView(ctx context.Context, tx *bolt.Tx, d *platform.View) *platform.Error {
+func (c *Service) putView(ctx context.Context, tx Tx, d *platform.View) *platform.Error {
 	v, err := json.Marshal(d)
 	if err != nil {
 		return &platform.Error{
@@ -214,7 +225,14 @@ func (c *Client) putView(ctx context.Context, tx *bolt.Tx, d *platform.View) *pl
 			Err: err,
 		}
 	}
-	if err := tx.Bucket(viewBucket).Put(encodedID, v); err != nil {
+	b, err := tx.Bucket(viewBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
@@ -223,8 +241,16 @@ func (c *Client) putView(ctx context.Context, tx *bolt.Tx, d *platform.View) *pl
 }
 
 // forEachView will iterate through all views while fn returns true.
-func (c *Client) forEachView(ctx context.Context, tx *bolt.Tx, fn func(*platform.View) bool) error {
-	cur := tx.Bucket(viewBucket).Cursor()
+func (c *Service) forEachView(ctx context.Context, tx Tx, fn func(*platform.View) bool) error {
+	b, err := tx.Bucket(viewBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
 		d := &platform.View{}
 		if err := json.Unmarshal(v, d); err != nil {
@@ -239,9 +265,9 @@ func (c *Client) forEachView(ctx context.Context, tx *bolt.Tx, fn func(*platform
 }
 
 // UpdateView updates a view according the parameters set on upd.
-func (c *Client) UpdateView(ctx context.Context, id platform.ID, upd platform.ViewUpdate) (*platform.View, error) {
+func (c *Service) UpdateView(ctx context.Context, id platform.ID, upd platform.ViewUpdate) (*platform.View, error) {
 	var d *platform.View
-	err := c.db.Update(func(tx *bolt.Tx) error {
+	err := c.kv.Update(func(tx Tx) error {
 		dash, pe := c.updateView(ctx, tx, id, upd)
 		if pe != nil {
 			return &platform.Error{
@@ -256,7 +282,7 @@ func (c *Client) UpdateView(ctx context.Context, id platform.ID, upd platform.Vi
 	return d, err
 }
 
-func (c *Client) updateView(ctx context.Context, tx *bolt.Tx, id platform.ID, upd platform.ViewUpdate) (*platform.View, error) {
+func (c *Service) updateView(ctx context.Context, tx Tx, id platform.ID, upd platform.ViewUpdate) (*platform.View, error) {
 	d, err := c.findViewByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
@@ -278,8 +304,8 @@ func (c *Client) updateView(ctx context.Context, tx *bolt.Tx, id platform.ID, up
 }
 
 // DeleteView deletes a view and prunes it from the index.
-func (c *Client) DeleteView(ctx context.Context, id platform.ID) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *Service) DeleteView(ctx context.Context, id platform.ID) error {
+	return c.kv.Update(func(tx Tx) error {
 		if pe := c.deleteView(ctx, tx, id); pe != nil {
 			return &platform.Error{
 				Err: pe,
@@ -290,7 +316,7 @@ func (c *Client) DeleteView(ctx context.Context, id platform.ID) error {
 	})
 }
 
-func (c *Client) deleteView(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+func (c *Service) deleteView(ctx context.Context, tx Tx, id platform.ID) *platform.Error {
 	_, pe := c.findViewByID(ctx, tx, id)
 	if pe != nil {
 		return pe
@@ -301,7 +327,14 @@ func (c *Client) deleteView(ctx context.Context, tx *bolt.Tx, id platform.ID) *p
 			Err: err,
 		}
 	}
-	if err := tx.Bucket(viewBucket).Delete(encodedID); err != nil {
+	b, err := tx.Bucket(viewBucket)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
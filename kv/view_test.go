@@ -1,23 +1,34 @@
-package inmem
+package kv_test
 
 import (
 	"context"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
 	platformtesting "github.com/influxdata/influxdb/testing"
 )
 
 func initViewService(f platformtesting.ViewFields, t *testing.T) (platform.ViewService, string, func()) {
-	s := NewService()
-	s.IDGenerator = f.IDGenerator
+	c, closeFn, err := NewTestInmemService()
+	if err != nil {
+		t.Fatalf("failed to create new kv service: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
 	ctx := context.TODO()
 	for _, b := range f.Views {
-		if err := s.PutView(ctx, b); err != nil {
-			t.Fatalf("failed to populate Views")
+		if err := c.PutView(ctx, b); err != nil {
+			t.Fatalf("failed to populate cells")
+		}
+	}
+	return c, kv.OpPrefix, func() {
+		defer closeFn()
+		for _, b := range f.Views {
+			if err := c.DeleteView(ctx, b.ID); err != nil {
+				t.Logf("failed to remove cell: %v", err)
+			}
 		}
 	}
-	return s, OpPrefix, func() {}
 }
 
 func TestViewService_CreateView(t *testing.T) {
@@ -27,6 +38,7 @@ func TestViewService_CreateView(t *testing.T) {
 func TestViewService_FindViewByID(t *testing.T) {
 	platformtesting.FindViewByID(initViewService, t)
 }
+
 func TestViewService_FindViews(t *testing.T) {
 	platformtesting.FindViews(initViewService, t)
 }
@@ -29,7 +29,7 @@ type LabelService interface {
 }
 
 type Label struct {
-	ResourceID ID                `json:"resourceID"`
+	ResourceID ID                `json:"resourceID,omitempty"`
 	Name       string            `json:"name"`
 	Properties map[string]string `json:"properties"`
 }
@@ -0,0 +1,256 @@
+// Package leveldb implements a kv.Store on goleveldb, a log-structured merge
+// tree.  It is an alternative to the boltdb store for the metadata services.
+package leveldb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/influxdb/kit/check"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"go.uber.org/zap"
+)
+
+// KVStore is a kv.Store backed by leveldb.  LevelDB has a single keyspace, so
+// the keys of a bucket are prefixed by the name of the bucket.
+type KVStore struct {
+	path   string
+	db     *leveldb.DB
+	logger *zap.Logger
+}
+
+// NewKVStore returns an instance of KVStore with the database in the
+// directory at the provided path.
+func NewKVStore(path string) *KVStore {
+	return &KVStore{
+		path:   path,
+		logger: zap.NewNop(),
+	}
+}
+
+// Open creates the leveldb database if it doesn't exist and opens it.
+func (s *KVStore) Open(ctx context.Context) error {
+	db, err := leveldb.OpenFile(s.path, nil)
+	if err != nil {
+		return fmt.Errorf("unable to open leveldb database %s: %v", s.path, err)
+	}
+	s.db = db
+
+	s.logger.Info("Resources opened", zap.String("path", s.path))
+	return nil
+}
+
+// Close the leveldb database.
+func (s *KVStore) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// CheckName returns the name of the store's health check.
+func (s *KVStore) CheckName() string {
+	return "leveldb"
+}
+
+// Check reports whether the leveldb database is open and can be read.
+func (s *KVStore) Check(ctx context.Context) check.Response {
+	if s.db == nil {
+		return check.Error(errors.New("leveldb database is not open"))
+	}
+	snap, err := s.db.GetSnapshot()
+	if err != nil {
+		return check.Error(err)
+	}
+	snap.Release()
+	return check.Pass()
+}
+
+// WithLogger sets the logger on the store.
+func (s *KVStore) WithLogger(l *zap.Logger) {
+	s.logger = l
+}
+
+// View opens up a view transaction against a snapshot of the store.
+func (s *KVStore) View(fn func(tx kv.Tx) error) error {
+	snap, err := s.db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+
+	tx := &Tx{
+		r:   snap,
+		ctx: context.Background(),
+	}
+	defer tx.release()
+	return fn(tx)
+}
+
+// Update opens up an update transaction against the store.  Only one update
+// transaction is open at a time.
+func (s *KVStore) Update(fn func(tx kv.Tx) error) error {
+	tr, err := s.db.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	// Discard is a no-op once the transaction is committed.
+	defer tr.Discard()
+
+	tx := &Tx{
+		r:   tr,
+		tr:  tr,
+		ctx: context.Background(),
+	}
+	defer tx.release()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+// reader reads a snapshot or a transaction.
+type reader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+// Tx is a snapshot of the store, or a leveldb transaction when it is
+// writable. It implements kv.Tx.
+type Tx struct {
+	r   reader
+	tr  *leveldb.Transaction
+	ctx context.Context
+
+	iterators []iterator.Iterator
+}
+
+// Context returns the context for the transaction.
+func (tx *Tx) Context() context.Context {
+	return tx.ctx
+}
+
+// WithContext sets the context for the transaction.
+func (tx *Tx) WithContext(ctx context.Context) {
+	tx.ctx = ctx
+}
+
+// Bucket retrieves the bucket named b.  Buckets exist once they have keys.
+func (tx *Tx) Bucket(b []byte) (kv.Bucket, error) {
+	prefix := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(b))
+	n := binary.PutUvarint(prefix, uint64(len(b)))
+	return &Bucket{
+		tx:     tx,
+		prefix: append(prefix[:n], b...),
+	}, nil
+}
+
+// release releases the iterators of the cursors of the transaction.
+func (tx *Tx) release() {
+	for _, it := range tx.iterators {
+		it.Release()
+	}
+	tx.iterators = nil
+}
+
+// Bucket implements kv.Bucket.
+type Bucket struct {
+	tx     *Tx
+	prefix []byte
+}
+
+func (b *Bucket) key(key []byte) []byte {
+	k := make([]byte, 0, len(b.prefix)+len(key))
+	return append(append(k, b.prefix...), key...)
+}
+
+// Get retrieves the value at the provided key.
+func (b *Bucket) Get(key []byte) ([]byte, error) {
+	val, err := b.tx.r.Get(b.key(key), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, kv.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// Put sets the value at the provided key.
+func (b *Bucket) Put(key []byte, value []byte) error {
+	if b.tx.tr == nil {
+		return kv.ErrTxNotWritable
+	}
+	return b.tx.tr.Put(b.key(key), value, nil)
+}
+
+// Delete removes the provided key.
+func (b *Bucket) Delete(key []byte) error {
+	if b.tx.tr == nil {
+		return kv.ErrTxNotWritable
+	}
+	return b.tx.tr.Delete(b.key(key), nil)
+}
+
+// Cursor retrieves a cursor for iterating through the entries
+// in the key value store.
+func (b *Bucket) Cursor() (kv.Cursor, error) {
+	it := b.tx.r.NewIterator(util.BytesPrefix(b.prefix), nil)
+	b.tx.iterators = append(b.tx.iterators, it)
+	return &Cursor{
+		iterator: it,
+		prefix:   b.prefix,
+	}, nil
+}
+
+// Cursor is a struct for iterating through the entries
+// in a bucket of the key value store.
+type Cursor struct {
+	iterator iterator.Iterator
+	prefix   []byte
+}
+
+// Seek seeks for the first key that is greater than or equal to the
+// prefix provided.
+func (c *Cursor) Seek(prefix []byte) ([]byte, []byte) {
+	k := make([]byte, 0, len(c.prefix)+len(prefix))
+	return c.pair(c.iterator.Seek(append(append(k, c.prefix...), prefix...)))
+}
+
+// First retrieves the first key value pair in the bucket.
+func (c *Cursor) First() ([]byte, []byte) {
+	return c.pair(c.iterator.First())
+}
+
+// Last retrieves the last key value pair in the bucket.
+func (c *Cursor) Last() ([]byte, []byte) {
+	return c.pair(c.iterator.Last())
+}
+
+// Next retrieves the next key in the bucket.
+func (c *Cursor) Next() ([]byte, []byte) {
+	return c.pair(c.iterator.Next())
+}
+
+// Prev retrieves the previous key in the bucket.
+func (c *Cursor) Prev() ([]byte, []byte) {
+	return c.pair(c.iterator.Prev())
+}
+
+// pair returns copies of the key, without the bucket prefix, and the value
+// at the iterator; the iterator reuses its buffers when it moves.
+func (c *Cursor) pair(ok bool) ([]byte, []byte) {
+	if !ok {
+		return nil, nil
+	}
+	k := append([]byte(nil), c.iterator.Key()[len(c.prefix):]...)
+	v := append([]byte{}, c.iterator.Value()...)
+	return k, v
+}
@@ -0,0 +1,83 @@
+package leveldb_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/leveldb"
+	platformtesting "github.com/influxdata/influxdb/testing"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	kv.HashCost = bcrypt.MinCost
+}
+
+func NewTestKVStore() (*leveldb.KVStore, func(), error) {
+	dir, err := ioutil.TempDir("", "influxdata-platform-leveldb-")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := leveldb.NewKVStore(dir)
+	if err := s.Open(context.TODO()); err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+
+	close := func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+
+	return s, close, nil
+}
+
+func initKVStore(f platformtesting.KVStoreFields, t *testing.T) (kv.Store, func()) {
+	s, closeFn, err := NewTestKVStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	err = s.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket(f.Bucket)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range f.Pairs {
+			if err := b.Put(p.Key, p.Value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to put keys: %v", err)
+	}
+	return s, func() {
+		closeFn()
+	}
+}
+
+func TestKVStore(t *testing.T) {
+	platformtesting.KVStore(initKVStore, t)
+}
+
+func NewTestService() (*kv.Service, func(), error) {
+	s, closeFn, err := NewTestKVStore()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	svc := kv.NewService(s)
+	if err := svc.Initialize(context.TODO()); err != nil {
+		closeFn()
+		return nil, nil, err
+	}
+	return svc, closeFn, nil
+}
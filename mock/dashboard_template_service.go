@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.DashboardTemplateService = &DashboardTemplateService{}
+
+type DashboardTemplateService struct {
+	ExportDashboardF func(context.Context, platform.ID) (*platform.DashboardTemplate, error)
+	ImportDashboardF func(context.Context, platform.ID, *platform.DashboardTemplate, platform.ImportDashboardOptions) (*platform.Dashboard, error)
+}
+
+func (s *DashboardTemplateService) ExportDashboard(ctx context.Context, id platform.ID) (*platform.DashboardTemplate, error) {
+	return s.ExportDashboardF(ctx, id)
+}
+
+func (s *DashboardTemplateService) ImportDashboard(ctx context.Context, orgID platform.ID, t *platform.DashboardTemplate, opts platform.ImportDashboardOptions) (*platform.Dashboard, error) {
+	return s.ImportDashboardF(ctx, orgID, t, opts)
+}
@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.NotificationRuleService = &NotificationRuleService{}
+var _ platform.NotificationEndpointService = &NotificationEndpointService{}
+
+// NotificationRuleService is a mock implementation of platform.NotificationRuleService.
+type NotificationRuleService struct {
+	FindNotificationRuleByIDFn func(context.Context, platform.ID) (*platform.NotificationRule, error)
+	FindNotificationRulesFn    func(context.Context, platform.NotificationRuleFilter, ...platform.FindOptions) ([]*platform.NotificationRule, int, error)
+	CreateNotificationRuleFn   func(context.Context, *platform.NotificationRule) error
+	UpdateNotificationRuleFn   func(context.Context, platform.ID, platform.NotificationRuleUpdate) (*platform.NotificationRule, error)
+	DeleteNotificationRuleFn   func(context.Context, platform.ID) error
+}
+
+// FindNotificationRuleByID returns a single notification rule by ID.
+func (s *NotificationRuleService) FindNotificationRuleByID(ctx context.Context, id platform.ID) (*platform.NotificationRule, error) {
+	return s.FindNotificationRuleByIDFn(ctx, id)
+}
+
+// FindNotificationRules returns a list of notification rules that match filter.
+func (s *NotificationRuleService) FindNotificationRules(ctx context.Context, filter platform.NotificationRuleFilter, opts ...platform.FindOptions) ([]*platform.NotificationRule, int, error) {
+	return s.FindNotificationRulesFn(ctx, filter, opts...)
+}
+
+// CreateNotificationRule creates a new notification rule.
+func (s *NotificationRuleService) CreateNotificationRule(ctx context.Context, r *platform.NotificationRule) error {
+	return s.CreateNotificationRuleFn(ctx, r)
+}
+
+// UpdateNotificationRule updates a single notification rule with changeset.
+func (s *NotificationRuleService) UpdateNotificationRule(ctx context.Context, id platform.ID, upd platform.NotificationRuleUpdate) (*platform.NotificationRule, error) {
+	return s.UpdateNotificationRuleFn(ctx, id, upd)
+}
+
+// DeleteNotificationRule removes a notification rule by ID.
+func (s *NotificationRuleService) DeleteNotificationRule(ctx context.Context, id platform.ID) error {
+	return s.DeleteNotificationRuleFn(ctx, id)
+}
+
+// NotificationEndpointService is a mock implementation of platform.NotificationEndpointService.
+type NotificationEndpointService struct {
+	FindNotificationEndpointByIDFn func(context.Context, platform.ID) (*platform.NotificationEndpoint, error)
+	FindNotificationEndpointsFn    func(context.Context, platform.NotificationEndpointFilter, ...platform.FindOptions) ([]*platform.NotificationEndpoint, int, error)
+	CreateNotificationEndpointFn   func(context.Context, *platform.NotificationEndpoint) error
+	UpdateNotificationEndpointFn   func(context.Context, platform.ID, platform.NotificationEndpointUpdate) (*platform.NotificationEndpoint, error)
+	DeleteNotificationEndpointFn   func(context.Context, platform.ID) error
+}
+
+// FindNotificationEndpointByID returns a single notification endpoint by ID.
+func (s *NotificationEndpointService) FindNotificationEndpointByID(ctx context.Context, id platform.ID) (*platform.NotificationEndpoint, error) {
+	return s.FindNotificationEndpointByIDFn(ctx, id)
+}
+
+// FindNotificationEndpoints returns a list of notification endpoints that match filter.
+func (s *NotificationEndpointService) FindNotificationEndpoints(ctx context.Context, filter platform.NotificationEndpointFilter, opts ...platform.FindOptions) ([]*platform.NotificationEndpoint, int, error) {
+	return s.FindNotificationEndpointsFn(ctx, filter, opts...)
+}
+
+// CreateNotificationEndpoint creates a new notification endpoint.
+func (s *NotificationEndpointService) CreateNotificationEndpoint(ctx context.Context, e *platform.NotificationEndpoint) error {
+	return s.CreateNotificationEndpointFn(ctx, e)
+}
+
+// UpdateNotificationEndpoint updates a single notification endpoint with changeset.
+func (s *NotificationEndpointService) UpdateNotificationEndpoint(ctx context.Context, id platform.ID, upd platform.NotificationEndpointUpdate) (*platform.NotificationEndpoint, error) {
+	return s.UpdateNotificationEndpointFn(ctx, id, upd)
+}
+
+// DeleteNotificationEndpoint removes a notification endpoint by ID.
+func (s *NotificationEndpointService) DeleteNotificationEndpoint(ctx context.Context, id platform.ID) error {
+	return s.DeleteNotificationEndpointFn(ctx, id)
+}
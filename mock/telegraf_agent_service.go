@@ -0,0 +1,68 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.TelegrafAgentService = &TelegrafAgentService{}
+
+// TelegrafAgentService is a mock implementation of platform.TelegrafAgentService.
+type TelegrafAgentService struct {
+	CreateTelegrafTokenF  func(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) (*platform.Authorization, error)
+	FindTelegrafTokenF    func(ctx context.Context, telegrafID platform.ID) (*platform.Authorization, error)
+	PutTelegrafAgentF     func(ctx context.Context, a *platform.TelegrafAgent) error
+	FindTelegrafAgentsF   func(ctx context.Context, telegrafID platform.ID) ([]*platform.TelegrafAgent, error)
+	DeleteTelegrafAgentsF func(ctx context.Context, telegrafID platform.ID) error
+}
+
+// NewTelegrafAgentService returns a mock TelegrafAgentService where the
+// configs have no token and no agents.
+func NewTelegrafAgentService() *TelegrafAgentService {
+	return &TelegrafAgentService{
+		CreateTelegrafTokenF: func(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) (*platform.Authorization, error) {
+			return &platform.Authorization{}, nil
+		},
+		FindTelegrafTokenF: func(ctx context.Context, telegrafID platform.ID) (*platform.Authorization, error) {
+			return nil, &platform.Error{
+				Code: platform.ENotFound,
+				Msg:  platform.ErrTelegrafTokenNotFound,
+			}
+		},
+		PutTelegrafAgentF: func(ctx context.Context, a *platform.TelegrafAgent) error {
+			return nil
+		},
+		FindTelegrafAgentsF: func(ctx context.Context, telegrafID platform.ID) ([]*platform.TelegrafAgent, error) {
+			return []*platform.TelegrafAgent{}, nil
+		},
+		DeleteTelegrafAgentsF: func(ctx context.Context, telegrafID platform.ID) error {
+			return nil
+		},
+	}
+}
+
+// CreateTelegrafToken creates the token of the telegraf config.
+func (s *TelegrafAgentService) CreateTelegrafToken(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) (*platform.Authorization, error) {
+	return s.CreateTelegrafTokenF(ctx, tc, userID)
+}
+
+// FindTelegrafToken returns the token of the telegraf config.
+func (s *TelegrafAgentService) FindTelegrafToken(ctx context.Context, telegrafID platform.ID) (*platform.Authorization, error) {
+	return s.FindTelegrafTokenF(ctx, telegrafID)
+}
+
+// PutTelegrafAgent records that an agent fetched a telegraf config.
+func (s *TelegrafAgentService) PutTelegrafAgent(ctx context.Context, a *platform.TelegrafAgent) error {
+	return s.PutTelegrafAgentF(ctx, a)
+}
+
+// FindTelegrafAgents returns the agents that fetched the telegraf config.
+func (s *TelegrafAgentService) FindTelegrafAgents(ctx context.Context, telegrafID platform.ID) ([]*platform.TelegrafAgent, error) {
+	return s.FindTelegrafAgentsF(ctx, telegrafID)
+}
+
+// DeleteTelegrafAgents removes the token and the agents of the telegraf config.
+func (s *TelegrafAgentService) DeleteTelegrafAgents(ctx context.Context, telegrafID platform.ID) error {
+	return s.DeleteTelegrafAgentsF(ctx, telegrafID)
+}
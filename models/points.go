@@ -348,16 +348,55 @@ func ValidPrecision(precision string) bool {
 // NOTE: to minimize heap allocations, the returned Points will refer to subslices of buf.
 // This can have the unintended effect preventing buf from being garbage collected.
 func ParsePointsWithPrecision(buf []byte, defaultTime time.Time, precision string) ([]Point, error) {
-	points := make([]Point, 0, bytes.Count(buf, []byte{'\n'})+1)
+	points, _, err := ParsePointsWithLines(buf, defaultTime, precision)
+	return points, err
+}
+
+// LineError describes a line of line protocol that could not be parsed.
+type LineError struct {
+	Line int    // Line is the 1-based line number within the parsed buffer.
+	Text string // Text is the line that failed to parse.
+	Err  error  // Err is the reason the line failed to parse.
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("unable to parse '%s': %v", e.Text, e.Err)
+}
+
+// ParseError is returned when one or more lines of line protocol could not be parsed.
+type ParseError struct {
+	Lines []LineError
+}
+
+func (e *ParseError) Error() string {
+	failed := make([]string, len(e.Lines))
+	for i, l := range e.Lines {
+		failed[i] = l.Error()
+	}
+	return strings.Join(failed, "\n")
+}
+
+// ParsePointsWithLines is similar to ParsePointsWithPrecision, but also returns
+// the 1-based line number of each parsed point. If any lines fail to parse,
+// the points that did parse are returned along with a *ParseError.
+func ParsePointsWithLines(buf []byte, defaultTime time.Time, precision string) ([]Point, []int, error) {
+	n := bytes.Count(buf, []byte{'\n'}) + 1
+	points := make([]Point, 0, n)
+	lines := make([]int, 0, n)
 	var (
 		pos    int
+		line   = 1
 		block  []byte
-		failed []string
+		failed []LineError
 	)
 	for pos < len(buf) {
 		pos, block = scanLine(buf, pos)
 		pos++
 
+		// quoted string fields may contain newlines, so count them all.
+		blockLine := line
+		line += bytes.Count(block, []byte{'\n'}) + 1
+
 		if len(block) == 0 {
 			continue
 		}
@@ -381,16 +420,17 @@ func ParsePointsWithPrecision(buf []byte, defaultTime time.Time, precision strin
 
 		pt, err := parsePoint(block[start:], defaultTime, precision)
 		if err != nil {
-			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(block[start:]), err))
+			failed = append(failed, LineError{Line: blockLine, Text: string(block[start:]), Err: err})
 		} else {
 			points = append(points, pt)
+			lines = append(lines, blockLine)
 		}
 
 	}
 	if len(failed) > 0 {
-		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
+		return points, lines, &ParseError{Lines: failed}
 	}
-	return points, nil
+	return points, lines, nil
 
 }
 
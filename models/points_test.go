@@ -618,6 +618,35 @@ func TestParsePointBadNumber(t *testing.T) {
 	}
 }
 
+func TestParsePointsWithLines(t *testing.T) {
+	buf := []byte("cpu value=1 1\n\n# comment\ncpu value= 2\nlog msg=\"a\nb\" 3\ncpu value=1ii 4\ncpu value=5 5")
+	points, lines, err := models.ParsePointsWithLines(buf, time.Unix(0, 0), "n")
+
+	if exp, got := []int{1, 5, 8}, lines; !reflect.DeepEqual(exp, got) {
+		t.Fatalf("lines mismatch:\nexp: %v\ngot: %v", exp, got)
+	}
+	if exp, got := 3, len(points); exp != got {
+		t.Fatalf("points length mismatch: exp %d, got %d", exp, got)
+	}
+
+	perr, ok := err.(*models.ParseError)
+	if !ok {
+		t.Fatalf("expected *models.ParseError, got %T: %v", err, err)
+	}
+	if exp, got := 2, len(perr.Lines); exp != got {
+		t.Fatalf("line errors length mismatch: exp %d, got %d", exp, got)
+	}
+	if exp, got := 4, perr.Lines[0].Line; exp != got {
+		t.Fatalf("first line error mismatch: exp %d, got %d", exp, got)
+	}
+	if exp, got := "cpu value=1ii 4", perr.Lines[1].Text; exp != got {
+		t.Fatalf("second line error text mismatch: exp %q, got %q", exp, got)
+	}
+	if exp, got := 7, perr.Lines[1].Line; exp != got {
+		t.Fatalf("second line error mismatch: exp %d, got %d", exp, got)
+	}
+}
+
 func TestParsePointMaxInt64(t *testing.T) {
 	// out of range
 	_, err := models.ParsePointsString(`cpu,host=serverA,region=us-west value=9223372036854775808i`)
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// Notification is sent to a notification endpoint when a status change
+// matches a notification rule.
+type Notification struct {
+	RuleID        platform.ID         `json:"ruleID"`
+	RuleName      string              `json:"ruleName"`
+	CheckID       platform.ID         `json:"checkID"`
+	CheckName     string              `json:"checkName"`
+	Level         platform.CheckLevel `json:"level"`
+	PreviousLevel platform.CheckLevel `json:"previousLevel,omitempty"`
+	Time          time.Time           `json:"time"`
+	Value         float64             `json:"value"`
+	Tags          map[string]string   `json:"tags,omitempty"`
+	Message       string              `json:"message"`
+}
+
+// Sender sends notifications to notification endpoints.
+type Sender interface {
+	Send(ctx context.Context, e *platform.NotificationEndpoint, n *Notification) error
+}
+
+// HTTPSender sends notifications as JSON to HTTP notification endpoints.
+type HTTPSender struct {
+	Client *http.Client
+}
+
+// NewHTTPSender returns a sender whose requests time out after timeout.
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return &HTTPSender{
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send sends n to the URL of e. Any status other than 2xx is an error.
+func (s *HTTPSender) Send(ctx context.Context, e *platform.NotificationEndpoint, n *Notification) error {
+	if e.Type != platform.HTTPNotificationEndpointType {
+		return fmt.Errorf("unsupported notification endpoint type %q", e.Type)
+	}
+
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	method := e.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequest(method, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("notification endpoint %s returned %s", e.ID, resp.Status)
+	}
+	return nil
+}
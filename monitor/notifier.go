@@ -0,0 +1,330 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultNotifierInterval is how often the notifier reads statuses.
+	DefaultNotifierInterval = 10 * time.Second
+
+	// DefaultNotifierLookback is how far back the notifier reads statuses.
+	// Series that have not reported a status within it are forgotten.
+	DefaultNotifierLookback = time.Hour
+)
+
+// Notifier reads the statuses written by checks and sends a notification
+// for every status change that matches a notification rule.
+//
+// The first status of a series seen by a notifier is compared against an
+// empty previous level, so that rules matching the current level of a
+// series fire once after a restart.
+type Notifier struct {
+	Rules        platform.NotificationRuleService
+	Endpoints    platform.NotificationEndpointService
+	QueryService query.QueryService
+	Sender       Sender
+
+	Interval time.Duration
+	Lookback time.Duration
+	Logger   *zap.Logger
+
+	mu     sync.Mutex
+	series map[string]seriesState
+}
+
+type seriesState struct {
+	level platform.CheckLevel
+	time  time.Time
+}
+
+// status is a single point written by a check.
+type status struct {
+	checkID   platform.ID
+	checkName string
+	level     platform.CheckLevel
+	time      time.Time
+	value     float64
+	tags      map[string]string
+	key       string
+}
+
+// NewNotifier creates a notifier that sends notifications with an HTTP sender.
+func NewNotifier(rs platform.NotificationRuleService, es platform.NotificationEndpointService, qs query.QueryService, logger *zap.Logger) *Notifier {
+	return &Notifier{
+		Rules:        rs,
+		Endpoints:    es,
+		QueryService: qs,
+		Sender:       NewHTTPSender(10 * time.Second),
+		Interval:     DefaultNotifierInterval,
+		Lookback:     DefaultNotifierLookback,
+		Logger:       logger,
+		series:       make(map[string]seriesState),
+	}
+}
+
+// Run polls statuses every Interval until ctx is done.
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(n.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := n.Poll(ctx); err != nil {
+				n.Logger.Info("Failed to send notifications", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Poll reads the statuses of every organization that has active
+// notification rules and sends the notifications for new status changes.
+func (n *Notifier) Poll(ctx context.Context) error {
+	rules, _, err := n.Rules.FindNotificationRules(ctx, platform.NotificationRuleFilter{})
+	if err != nil {
+		return err
+	}
+
+	byOrg := make(map[platform.ID][]*platform.NotificationRule)
+	for _, r := range rules {
+		if r.Status == platform.CheckStatusInactive {
+			continue
+		}
+		byOrg[r.OrganizationID] = append(byOrg[r.OrganizationID], r)
+	}
+
+	for orgID, rules := range byOrg {
+		statuses, err := n.readStatuses(ctx, orgID)
+		if err != nil {
+			// The monitoring bucket does not exist until the first check of the organization is created.
+			n.Logger.Debug("Failed to read statuses", zap.Stringer("org_id", orgID), zap.Error(err))
+			continue
+		}
+		n.notify(ctx, rules, n.changes(statuses))
+	}
+	return nil
+}
+
+// change is a status whose level differs from the previous status of its series.
+type change struct {
+	status
+	previous platform.CheckLevel
+}
+
+// changes returns the level changes in statuses since the previous poll
+// and records the latest status of every series.  Series whose latest status
+// is older than Lookback are no longer read, so they are forgotten.
+func (n *Notifier) changes(statuses []status) []change {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].time.Before(statuses[j].time)
+	})
+
+	bySeries := make(map[string][]status)
+	var keys []string
+	for _, s := range statuses {
+		if _, ok := bySeries[s.key]; !ok {
+			keys = append(keys, s.key)
+		}
+		bySeries[s.key] = append(bySeries[s.key], s)
+	}
+
+	var changes []change
+	for _, k := range keys {
+		ss := bySeries[k]
+		prev, seen := n.series[k]
+		if !seen {
+			// Only the current level of a series that is new to the notifier matters.
+			ss = ss[len(ss)-1:]
+		}
+		for _, s := range ss {
+			if seen && !s.time.After(prev.time) {
+				continue
+			}
+			if s.level != prev.level {
+				changes = append(changes, change{status: s, previous: prev.level})
+			}
+			prev = seriesState{level: s.level, time: s.time}
+			seen = true
+		}
+		n.series[k] = prev
+	}
+
+	cutoff := time.Now().Add(-n.Lookback)
+	for k, s := range n.series {
+		if s.time.Before(cutoff) {
+			delete(n.series, k)
+		}
+	}
+	return changes
+}
+
+func (n *Notifier) notify(ctx context.Context, rules []*platform.NotificationRule, changes []change) {
+	endpoints := make(map[platform.ID]*platform.NotificationEndpoint)
+	for _, c := range changes {
+		for _, r := range rules {
+			if !r.Matches(c.checkID, c.previous, c.level) {
+				continue
+			}
+
+			e, ok := endpoints[r.EndpointID]
+			if !ok {
+				var err error
+				e, err = n.Endpoints.FindNotificationEndpointByID(ctx, r.EndpointID)
+				if err != nil {
+					n.Logger.Info("Failed to find notification endpoint", zap.Stringer("rule_id", r.ID), zap.Error(err))
+					continue
+				}
+				endpoints[r.EndpointID] = e
+			}
+
+			nt, err := newNotification(r, c)
+			if err != nil {
+				n.Logger.Info("Failed to render notification", zap.Stringer("rule_id", r.ID), zap.Error(err))
+				continue
+			}
+			if err := n.Sender.Send(ctx, e, nt); err != nil {
+				n.Logger.Info("Failed to send notification", zap.Stringer("rule_id", r.ID), zap.Stringer("endpoint_id", e.ID), zap.Error(err))
+			}
+		}
+	}
+}
+
+func newNotification(r *platform.NotificationRule, c change) (*Notification, error) {
+	nt := &Notification{
+		RuleID:        r.ID,
+		RuleName:      r.Name,
+		CheckID:       c.checkID,
+		CheckName:     c.checkName,
+		Level:         c.level,
+		PreviousLevel: c.previous,
+		Time:          c.time,
+		Value:         c.value,
+		Tags:          c.tags,
+	}
+
+	text := r.MessageTemplate
+	if text == "" {
+		text = platform.DefaultNotificationMessageTemplate
+	}
+	t, err := template.New("message").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, nt); err != nil {
+		return nil, err
+	}
+	nt.Message = b.String()
+	return nt, nil
+}
+
+func (n *Notifier) readStatuses(ctx context.Context, orgID platform.ID) ([]status, error) {
+	script := fmt.Sprintf(`from(bucket: %q)
+	|> range(start: -%s)
+	|> filter(fn: (r) => r._measurement == %q)`,
+		platform.MonitoringBucketName, n.Lookback, platform.StatusMeasurement)
+
+	it, err := n.QueryService.Query(ctx, &query.Request{
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	var statuses []status
+	for it.More() {
+		err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				ss, err := readStatuses(orgID, cr)
+				statuses = append(statuses, ss...)
+				return err
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// readStatuses converts the rows of cr to statuses. Every string column
+// other than the ones added by checks identifies the series of a status.
+func readStatuses(orgID platform.ID, cr flux.ColReader) ([]status, error) {
+	cols := cr.Cols()
+	statuses := make([]status, 0, cr.Len())
+	for i := 0; i < cr.Len(); i++ {
+		s := status{tags: make(map[string]string)}
+		for j, c := range cols {
+			switch {
+			case c.Label == "_time" && c.Type == flux.TTime:
+				s.time = time.Unix(0, cr.Times(j).Value(i)).UTC()
+			case c.Label == "_value":
+				switch c.Type {
+				case flux.TFloat:
+					s.value = cr.Floats(j).Value(i)
+				case flux.TInt:
+					s.value = float64(cr.Ints(j).Value(i))
+				case flux.TUInt:
+					s.value = float64(cr.UInts(j).Value(i))
+				}
+			case c.Type != flux.TString:
+			case c.Label == platform.CheckIDColumn:
+				id, err := platform.IDFromString(cr.Strings(j).ValueString(i))
+				if err != nil {
+					return nil, err
+				}
+				s.checkID = *id
+			case c.Label == platform.CheckNameColumn:
+				s.checkName = cr.Strings(j).ValueString(i)
+			case c.Label == platform.LevelColumn:
+				s.level = platform.CheckLevel(cr.Strings(j).ValueString(i))
+			case c.Label == "_measurement":
+			default:
+				s.tags[c.Label] = cr.Strings(j).ValueString(i)
+			}
+		}
+		s.key = seriesKey(orgID, s.checkID, s.tags)
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+func seriesKey(orgID, checkID platform.ID, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(orgID.String())
+	b.WriteByte(',')
+	b.WriteString(checkID.String())
+	for _, k := range keys {
+		fmt.Fprintf(&b, ",%s=%s", k, tags[k])
+	}
+	return b.String()
+}
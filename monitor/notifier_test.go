@@ -0,0 +1,162 @@
+package monitor_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/monitor"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap"
+)
+
+func statusTable(rows ...[]interface{}) *executetest.Table {
+	return &executetest.Table{
+		KeyCols: []string{"_measurement", "_field", "host", "_check_id", "_check_name", "_level"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_start", Type: flux.TTime},
+			{Label: "_stop", Type: flux.TTime},
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TFloat},
+			{Label: "_measurement", Type: flux.TString},
+			{Label: "_field", Type: flux.TString},
+			{Label: "host", Type: flux.TString},
+			{Label: "_check_id", Type: flux.TString},
+			{Label: "_check_name", Type: flux.TString},
+			{Label: "_level", Type: flux.TString},
+		},
+		Data: rows,
+	}
+}
+
+// statusEpoch is the time of the statuses of the tests, within the lookback
+// of the notifier.
+var statusEpoch = time.Now().Add(-time.Minute).UnixNano()
+
+func statusRow(sec int, host, level string, v float64) []interface{} {
+	return []interface{}{
+		execute.Time(0), execute.Time(0), execute.Time(statusEpoch + int64(sec)*int64(time.Second)), v, "statuses", "usage", host, "0000000000000001", "cpu", level,
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	received := make(chan monitor.Notification, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n monitor.Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Error(err)
+		}
+		if got := r.Header.Get("X-Token"); got != "secret" {
+			t.Errorf("unexpected header %q", got)
+		}
+		received <- n
+	}))
+	defer srv.Close()
+
+	endpoint := &platform.NotificationEndpoint{
+		ID:             10,
+		OrganizationID: 1,
+		Name:           "hook",
+		Type:           platform.HTTPNotificationEndpointType,
+		URL:            srv.URL,
+		Headers:        map[string]string{"X-Token": "secret"},
+	}
+	rule := &platform.NotificationRule{
+		ID:              20,
+		OrganizationID:  1,
+		Name:            "crit",
+		Status:          platform.CheckStatusActive,
+		EndpointID:      endpoint.ID,
+		StatusRules:     []platform.StatusRule{{CurrentLevel: platform.LevelCrit}},
+		MessageTemplate: `{{.CheckName}} on {{index .Tags "host"}} is {{.Level}}`,
+	}
+
+	var tables []*executetest.Table
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != 1 {
+				t.Errorf("unexpected organization %s", req.OrganizationID)
+			}
+			return flux.NewSliceResultIterator([]flux.Result{executetest.NewResult(tables)}), nil
+		},
+	}
+	n := monitor.NewNotifier(
+		&mock.NotificationRuleService{
+			FindNotificationRulesFn: func(context.Context, platform.NotificationRuleFilter, ...platform.FindOptions) ([]*platform.NotificationRule, int, error) {
+				return []*platform.NotificationRule{rule}, 1, nil
+			},
+		},
+		&mock.NotificationEndpointService{
+			FindNotificationEndpointByIDFn: func(context.Context, platform.ID) (*platform.NotificationEndpoint, error) {
+				return endpoint, nil
+			},
+		},
+		qs,
+		zap.NewNop(),
+	)
+
+	// host a starts ok, host b is already crit.
+	tables = []*executetest.Table{
+		statusTable(statusRow(1, "a", "ok", 10), statusRow(2, "a", "ok", 20)),
+		statusTable(statusRow(1, "b", "crit", 95)),
+	}
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got := <-received
+	if got.Message != "cpu on b is crit" || got.PreviousLevel != "" {
+		t.Errorf("unexpected notification %+v", got)
+	}
+
+	// host a becomes crit, host b stays crit.
+	tables = []*executetest.Table{
+		statusTable(statusRow(1, "a", "ok", 10), statusRow(2, "a", "ok", 20)),
+		statusTable(statusRow(3, "a", "crit", 99)),
+		statusTable(statusRow(1, "b", "crit", 95), statusRow(3, "b", "crit", 96)),
+	}
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got = <-received
+	if got.Message != "cpu on a is crit" || got.PreviousLevel != platform.LevelOK || got.Value != 99 {
+		t.Errorf("unexpected notification %+v", got)
+	}
+
+	// Nothing changed.
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-received:
+		t.Errorf("unexpected notification %+v", got)
+	default:
+	}
+
+	// Series without a status within the lookback are forgotten, so their
+	// current level is notified again when they report.
+	n.Lookback = time.Nanosecond
+	for i := 0; i < 2; i++ {
+		if err := n.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case got := <-received:
+			if got.Level != platform.LevelCrit || got.PreviousLevel != "" {
+				t.Errorf("unexpected notification %+v", got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the notifications of the forgotten series")
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/gather"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSelfMonitorInterval is how often the self monitor writes the
+	// metrics of influxd.
+	DefaultSelfMonitorInterval = 10 * time.Second
+
+	// DefaultSelfMonitorRetention is the retention period of the bucket of
+	// the instance when the self monitor creates it.
+	DefaultSelfMonitorRetention = MonitoringBucketRetention
+
+	// InstanceTag is the tag of the metrics naming the instance of influxd
+	// they were gathered from.
+	InstanceTag = "instance"
+)
+
+// SelfMonitorBucketName returns the name of the bucket the metrics of the
+// instance of influxd are written to.  It is not the monitoring bucket of
+// checks, so the metrics are never read as statuses.
+func SelfMonitorBucketName(instance string) string {
+	return platform.MonitoringBucketName + "_" + instance
+}
+
+// SelfMonitor periodically gathers the prometheus metrics of influxd itself
+// and writes them to the bucket of the instance in an organization, so that
+// dashboards of the instance can be built without scraping it.
+type SelfMonitor struct {
+	Gatherer      prometheus.Gatherer
+	Recorder      gather.Recorder
+	Organizations platform.OrganizationService
+	Buckets       platform.BucketService
+
+	// Instance names the instance of influxd.  It names the bucket of the
+	// metrics and tags every metric.
+	Instance string
+	// OrgName names the organization the metrics are written to. If empty,
+	// they are written to the first organization, the one of onboarding.
+	OrgName string
+	// Retention is the retention period of the bucket of the instance, if
+	// the self monitor creates it.
+	Retention time.Duration
+	Interval  time.Duration
+	Logger    *zap.Logger
+}
+
+// NewSelfMonitor creates a self monitor that writes the metrics of g with r
+// to the bucket of instance in the organization named orgName, or in the
+// first organization if orgName is empty.
+func NewSelfMonitor(g prometheus.Gatherer, r gather.Recorder, orgs platform.OrganizationService, bs platform.BucketService, instance, orgName string, logger *zap.Logger) *SelfMonitor {
+	return &SelfMonitor{
+		Gatherer:      g,
+		Recorder:      r,
+		Organizations: orgs,
+		Buckets:       bs,
+		Instance:      instance,
+		OrgName:       orgName,
+		Retention:     DefaultSelfMonitorRetention,
+		Interval:      DefaultSelfMonitorInterval,
+		Logger:        logger,
+	}
+}
+
+// Run writes the metrics every Interval until ctx is done.
+func (m *SelfMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Monitor(ctx); err != nil {
+				m.Logger.Info("Failed to write metrics", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Monitor gathers the metrics, tags them with the instance and writes them to
+// the bucket of the instance, creating it if needed. It writes nothing while the organization does
+// not exist, such as before onboarding.
+func (m *SelfMonitor) Monitor(ctx context.Context) error {
+	org, err := m.findOrganization(ctx)
+	if err != nil {
+		return err
+	}
+	if org == nil {
+		return nil
+	}
+
+	b, err := ensureBucket(ctx, m.Buckets, org.ID, SelfMonitorBucketName(m.Instance), m.Retention)
+	if err != nil {
+		return err
+	}
+
+	scraper := &gather.RegistryScraper{Gatherer: m.Gatherer}
+	collected, err := scraper.Gather(ctx, platform.ScraperTarget{
+		OrgID:    org.ID,
+		BucketID: b.ID,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range collected.MetricsSlice {
+		ms := &collected.MetricsSlice[i]
+		if ms.Tags == nil {
+			ms.Tags = make(map[string]string)
+		}
+		ms.Tags[InstanceTag] = m.Instance
+	}
+	return m.Recorder.Record(collected)
+}
+
+// findOrganization returns the organization the metrics are written to,
+// or nil if it does not exist.
+func (m *SelfMonitor) findOrganization(ctx context.Context) (*platform.Organization, error) {
+	if m.OrgName != "" {
+		org, err := m.Organizations.FindOrganization(ctx, platform.OrganizationFilter{Name: &m.OrgName})
+		if platform.ErrorCode(err) == platform.ENotFound {
+			return nil, nil
+		}
+		return org, err
+	}
+
+	orgs, _, err := m.Organizations.FindOrganizations(ctx, platform.OrganizationFilter{})
+	if err != nil || len(orgs) == 0 {
+		return nil, err
+	}
+	return orgs[0], nil
+}
@@ -0,0 +1,106 @@
+package monitor_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/gather"
+	"github.com/influxdata/influxdb/monitor"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zaptest"
+)
+
+type recorder []gather.MetricsCollection
+
+func (r *recorder) Record(collected gather.MetricsCollection) error {
+	*r = append(*r, collected)
+	return nil
+}
+
+func TestSelfMonitor(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	reg := prometheus.NewRegistry()
+	writes := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "writes_total",
+		Help: "Number of writes.",
+	})
+	writes.Add(3)
+	reg.MustRegister(writes)
+
+	var rec recorder
+	m := monitor.NewSelfMonitor(reg, &rec, c, c, "host1", "", zaptest.NewLogger(t))
+
+	// Nothing is written before onboarding creates the first organization.
+	ctx := context.Background()
+	if err := m.Monitor(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec) != 0 {
+		t.Fatalf("expected no metrics without an organization, got %d collections", len(rec))
+	}
+
+	org := &platform.Organization{Name: "org"}
+	if err := c.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	other := &platform.Organization{Name: "other"}
+	if err := c.CreateOrganization(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := m.Monitor(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	name := "_monitoring_host1"
+	b, err := c.FindBucket(ctx, platform.BucketFilter{OrganizationID: &org.ID, Name: &name})
+	if err != nil {
+		t.Fatalf("expected the bucket of the instance in the first organization: %v", err)
+	}
+	// The metrics are kept apart from the statuses of checks.
+	monitoring := platform.MonitoringBucketName
+	if _, err := c.FindBucket(ctx, platform.BucketFilter{OrganizationID: &org.ID, Name: &monitoring}); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected no monitoring bucket of checks, got %v", err)
+	}
+	if b.RetentionPeriod != monitor.DefaultSelfMonitorRetention {
+		t.Fatalf("expected retention %v, got %v", monitor.DefaultSelfMonitorRetention, b.RetentionPeriod)
+	}
+
+	if len(rec) != 2 {
+		t.Fatalf("expected 2 collections, got %d", len(rec))
+	}
+	for _, collected := range rec {
+		if collected.OrgID != org.ID || collected.BucketID != b.ID {
+			t.Fatalf("expected metrics of the bucket of the instance %v, got org %v and bucket %v", b.ID, collected.OrgID, collected.BucketID)
+		}
+		if len(collected.MetricsSlice) != 1 || collected.MetricsSlice[0].Name != "writes_total" {
+			t.Fatalf("expected the writes_total metric, got %v", collected.MetricsSlice)
+		}
+		if instance := collected.MetricsSlice[0].Tags[monitor.InstanceTag]; instance != "host1" {
+			t.Fatalf("expected the metric of instance host1, got %q", instance)
+		}
+	}
+
+	// The metrics go to the named organization, once it exists.
+	rec = nil
+	m.OrgName = "missing"
+	if err := m.Monitor(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec) != 0 {
+		t.Fatalf("expected no metrics without the named organization, got %d collections", len(rec))
+	}
+
+	m.OrgName = "other"
+	if err := m.Monitor(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec) != 1 || rec[0].OrgID != other.ID {
+		t.Fatalf("expected metrics of the named organization, got %v", rec)
+	}
+}
@@ -0,0 +1,184 @@
+// Package monitor runs checks as tasks and sends notifications for the
+// status changes that they report.
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+)
+
+// MonitoringBucketRetention is the retention period of the monitoring
+// bucket created for an organization with its first check.
+const MonitoringBucketRetention = 7 * 24 * time.Hour
+
+var _ platform.CheckService = (*CheckService)(nil)
+
+// CheckService wraps a platform.CheckService so that every check is run
+// by a task that writes its statuses to the monitoring bucket of the
+// check's organization. The task service should authorize the tasks like
+// those created through the API.
+type CheckService struct {
+	platform.CheckService
+
+	TaskService   platform.TaskService
+	BucketService platform.BucketService
+}
+
+// NewCheckService creates a check service that stores checks in s and
+// schedules them with ts. Monitoring buckets are created with bs.
+func NewCheckService(s platform.CheckService, ts platform.TaskService, bs platform.BucketService) *CheckService {
+	return &CheckService{
+		CheckService:  s,
+		TaskService:   ts,
+		BucketService: bs,
+	}
+}
+
+// CreateCheck creates the check and the task that runs it. The task is
+// owned by the user of the authorizer on ctx.
+func (s *CheckService) CreateCheck(ctx context.Context, c *platform.Check) error {
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authorizeQuery(ctx, auth, c.OrganizationID, c.Query); err != nil {
+		return err
+	}
+
+	if err := s.CheckService.CreateCheck(ctx, c); err != nil {
+		return err
+	}
+
+	if err := s.createTask(ctx, c, auth.GetUserID()); err != nil {
+		// The check is useless without its task.
+		_ = s.CheckService.DeleteCheck(ctx, c.ID)
+		return err
+	}
+	return nil
+}
+
+func (s *CheckService) createTask(ctx context.Context, c *platform.Check, owner platform.ID) error {
+	if _, err := ensureBucket(ctx, s.BucketService, c.OrganizationID, platform.MonitoringBucketName, MonitoringBucketRetention); err != nil {
+		return err
+	}
+
+	flux, err := c.GenerateFlux()
+	if err != nil {
+		return err
+	}
+
+	t := &platform.Task{
+		Organization: c.OrganizationID,
+		Owner:        platform.User{ID: owner},
+		Flux:         flux,
+		Status:       c.Status,
+	}
+	if err := s.TaskService.CreateTask(ctx, t); err != nil {
+		return err
+	}
+
+	updated, err := s.CheckService.UpdateCheck(ctx, c.ID, platform.CheckUpdate{TaskID: &t.ID})
+	if err != nil {
+		_ = s.TaskService.DeleteTask(ctx, t.ID)
+		return err
+	}
+	*c = *updated
+	return nil
+}
+
+// ensureBucket returns the bucket of the organization named name, creating
+// it with the retention period if it does not exist yet.
+func ensureBucket(ctx context.Context, bs platform.BucketService, orgID platform.ID, name string, retention time.Duration) (*platform.Bucket, error) {
+	b, err := bs.FindBucket(ctx, platform.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &name,
+	})
+	if err == nil {
+		return b, nil
+	}
+	if platform.ErrorCode(err) != platform.ENotFound {
+		return nil, err
+	}
+
+	b = &platform.Bucket{
+		OrganizationID:  orgID,
+		Name:            name,
+		RetentionPeriod: retention,
+	}
+	if err := bs.CreateBucket(ctx, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// authorizeQuery ensures that auth can read the buckets read by the query
+// of a check, and write the buckets it writes. The task of the check
+// accesses them without an authorization.
+func (s *CheckService) authorizeQuery(ctx context.Context, auth platform.Authorizer, orgID platform.ID, q string) error {
+	spec, err := flux.Compile(ctx, q, time.Now())
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid check query",
+			Err:  err,
+		}
+	}
+
+	return query.NewPreAuthorizer(s.BucketService).PreAuthorize(ctx, spec, auth, orgID)
+}
+
+// UpdateCheck updates the check and regenerates the script of its task.
+func (s *CheckService) UpdateCheck(ctx context.Context, id platform.ID, upd platform.CheckUpdate) (*platform.Check, error) {
+	if upd.Query != nil {
+		auth, err := pcontext.GetAuthorizer(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c, err := s.CheckService.FindCheckByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authorizeQuery(ctx, auth, c.OrganizationID, *upd.Query); err != nil {
+			return nil, err
+		}
+	}
+
+	c, err := s.CheckService.UpdateCheck(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	flux, err := c.GenerateFlux()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.TaskService.UpdateTask(ctx, c.TaskID, platform.TaskUpdate{
+		Flux:   &flux,
+		Status: &c.Status,
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteCheck deletes the check and its task.
+func (s *CheckService) DeleteCheck(ctx context.Context, id platform.ID) error {
+	c, err := s.CheckService.FindCheckByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if c.TaskID.Valid() {
+		if err := s.TaskService.DeleteTask(ctx, c.TaskID); err != nil {
+			return err
+		}
+	}
+	return s.CheckService.DeleteCheck(ctx, id)
+}
@@ -0,0 +1,145 @@
+package monitor_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/monitor"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+func newTestClient(t *testing.T) (*bolt.Client, func()) {
+	c := bolt.NewClient()
+	f, err := ioutil.TempFile("", "influxdata-platform-monitor-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	c.Path = f.Name()
+	if err := c.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return c, func() {
+		c.Close()
+		os.Remove(c.Path)
+	}
+}
+
+func TestCheckService(t *testing.T) {
+	c, done := newTestClient(t)
+	defer done()
+
+	ctx := context.Background()
+	org := &platform.Organization{Name: "org"}
+	if err := c.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := make(map[platform.ID]*platform.Task)
+	ts := &mock.TaskService{
+		CreateTaskFn: func(_ context.Context, task *platform.Task) error {
+			task.ID = platform.ID(len(tasks) + 100)
+			tasks[task.ID] = task
+			return nil
+		},
+		UpdateTaskFn: func(_ context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
+			task := tasks[id]
+			task.Flux = *upd.Flux
+			task.Status = *upd.Status
+			return task, nil
+		},
+		DeleteTaskFn: func(_ context.Context, id platform.ID) error {
+			delete(tasks, id)
+			return nil
+		},
+	}
+	s := monitor.NewCheckService(c, ts, c)
+
+	bucket := &platform.Bucket{OrganizationID: org.ID, Name: "telegraf"}
+	if err := c.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	check := &platform.Check{
+		OrganizationID: org.ID,
+		Name:           "cpu",
+		Type:           platform.ThresholdCheckType,
+		Query:          `from(bucket: "telegraf") |> range(start: -1m)`,
+		Every:          "1m",
+		Thresholds: []platform.Threshold{
+			{Level: platform.LevelCrit, Op: platform.ThresholdGreater, Value: 90},
+		},
+	}
+
+	// The query of the check may only read buckets readable by the authorizer.
+	denied := pcontext.SetAuthorizer(ctx, &platform.Authorization{UserID: 7, Status: platform.Active})
+	if err := s.CreateCheck(denied, check); platform.ErrorCode(err) != platform.EUnauthorized {
+		t.Fatalf("expected an unauthorized error, got %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("check was scheduled without permission")
+	}
+
+	ctx = pcontext.SetAuthorizer(ctx, &platform.Authorization{
+		UserID: 7,
+		Status: platform.Active,
+		Permissions: []platform.Permission{
+			{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &org.ID, ID: &bucket.ID}},
+		},
+	})
+
+	// Nor may it write buckets that are not writable by the authorizer.
+	writer := *check
+	writer.Query = `from(bucket: "telegraf") |> range(start: -1m) |> to(bucket: "telegraf", orgID: "` + org.ID.String() + `")`
+	if err := s.CreateCheck(ctx, &writer); platform.ErrorCode(err) != platform.EUnauthorized {
+		t.Fatalf("expected an unauthorized error, got %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("check was scheduled without permission")
+	}
+
+	if err := s.CreateCheck(ctx, check); err != nil {
+		t.Fatal(err)
+	}
+
+	task, ok := tasks[check.TaskID]
+	if !ok {
+		t.Fatalf("check was not scheduled: %+v", check)
+	}
+	if task.Owner.ID != 7 || task.Organization != org.ID || task.Status != platform.CheckStatusActive {
+		t.Errorf("unexpected task %+v", task)
+	}
+	if !strings.Contains(task.Flux, check.ID.String()) {
+		t.Errorf("task script does not reference the check:\n%s", task.Flux)
+	}
+
+	name := platform.MonitoringBucketName
+	if _, err := c.FindBucket(ctx, platform.BucketFilter{OrganizationID: &org.ID, Name: &name}); err != nil {
+		t.Errorf("monitoring bucket was not created: %v", err)
+	}
+
+	inactive := platform.CheckStatusInactive
+	if _, err := s.UpdateCheck(ctx, check.ID, platform.CheckUpdate{Status: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != platform.CheckStatusInactive {
+		t.Errorf("task status was not updated: %q", task.Status)
+	}
+
+	if err := s.DeleteCheck(ctx, check.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("task was not deleted")
+	}
+	if _, err := c.FindCheckByID(ctx, check.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("check was not deleted: %v", err)
+	}
+}
@@ -1,8 +1,10 @@
 package nats
 
 import (
+	"context"
 	"errors"
 
+	"github.com/influxdata/influxdb/kit/check"
 	stand "github.com/nats-io/nats-streaming-server/server"
 	"github.com/nats-io/nats-streaming-server/stores"
 )
@@ -38,6 +40,29 @@ func (s *Server) Close() {
 	s.Server.Shutdown()
 }
 
+// CheckName returns the name of the server's health check.
+func (s *Server) CheckName() string {
+	return "nats"
+}
+
+// Check reports whether the embedded NATS streaming server is running.
+func (s *Server) Check(ctx context.Context) check.Response {
+	if s.Server == nil {
+		return check.Error(ErrNoNatsConnection)
+	}
+	switch state := s.Server.State(); state {
+	case stand.Failed:
+		if err := s.Server.LastError(); err != nil {
+			return check.Error(err)
+		}
+		return check.Error(errors.New("nats streaming server failed"))
+	case stand.Shutdown:
+		return check.Error(errors.New("nats streaming server is shut down"))
+	default:
+		return check.Info("nats streaming server is %s", state)
+	}
+}
+
 // Config is the configuration for the NATS streaming server
 type Config struct {
 	// The directory where nats persists message information
@@ -0,0 +1,362 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"text/template"
+)
+
+// Errors for missing notification resources.
+const (
+	ErrNotificationRuleNotFound     = "notification rule not found"
+	ErrNotificationEndpointNotFound = "notification endpoint not found"
+)
+
+// ops for notification rules and endpoints error
+const (
+	OpFindNotificationRuleByID     = "FindNotificationRuleByID"
+	OpFindNotificationRules        = "FindNotificationRules"
+	OpCreateNotificationRule       = "CreateNotificationRule"
+	OpUpdateNotificationRule       = "UpdateNotificationRule"
+	OpDeleteNotificationRule       = "DeleteNotificationRule"
+	OpFindNotificationEndpointByID = "FindNotificationEndpointByID"
+	OpFindNotificationEndpoints    = "FindNotificationEndpoints"
+	OpCreateNotificationEndpoint   = "CreateNotificationEndpoint"
+	OpUpdateNotificationEndpoint   = "UpdateNotificationEndpoint"
+	OpDeleteNotificationEndpoint   = "DeleteNotificationEndpoint"
+)
+
+// NotificationRuleService represents a service for managing notification rules.
+type NotificationRuleService interface {
+	// FindNotificationRuleByID returns a single notification rule by ID.
+	FindNotificationRuleByID(ctx context.Context, id ID) (*NotificationRule, error)
+
+	// FindNotificationRules returns a list of notification rules that match filter and the total count of matching rules.
+	// Additional options provide pagination & sorting.
+	FindNotificationRules(ctx context.Context, filter NotificationRuleFilter, opt ...FindOptions) ([]*NotificationRule, int, error)
+
+	// CreateNotificationRule creates a new notification rule and sets r.ID with the new identifier.
+	CreateNotificationRule(ctx context.Context, r *NotificationRule) error
+
+	// UpdateNotificationRule updates a single notification rule with changeset.
+	// Returns the new rule state after update.
+	UpdateNotificationRule(ctx context.Context, id ID, upd NotificationRuleUpdate) (*NotificationRule, error)
+
+	// DeleteNotificationRule removes a notification rule by ID.
+	DeleteNotificationRule(ctx context.Context, id ID) error
+}
+
+// NotificationEndpointService represents a service for managing notification endpoints.
+type NotificationEndpointService interface {
+	// FindNotificationEndpointByID returns a single notification endpoint by ID.
+	FindNotificationEndpointByID(ctx context.Context, id ID) (*NotificationEndpoint, error)
+
+	// FindNotificationEndpoints returns a list of notification endpoints that match filter and the total count of matching endpoints.
+	// Additional options provide pagination & sorting.
+	FindNotificationEndpoints(ctx context.Context, filter NotificationEndpointFilter, opt ...FindOptions) ([]*NotificationEndpoint, int, error)
+
+	// CreateNotificationEndpoint creates a new notification endpoint and sets e.ID with the new identifier.
+	CreateNotificationEndpoint(ctx context.Context, e *NotificationEndpoint) error
+
+	// UpdateNotificationEndpoint updates a single notification endpoint with changeset.
+	// Returns the new endpoint state after update.
+	UpdateNotificationEndpoint(ctx context.Context, id ID, upd NotificationEndpointUpdate) (*NotificationEndpoint, error)
+
+	// DeleteNotificationEndpoint removes a notification endpoint by ID.
+	DeleteNotificationEndpoint(ctx context.Context, id ID) error
+}
+
+// NotificationRule sends a notification to an endpoint when the status of
+// a check changes in a way that matches one of its status rules.
+type NotificationRule struct {
+	ID             ID     `json:"id,omitempty"`
+	OrganizationID ID     `json:"orgID,omitempty"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Status         string `json:"status"`
+	EndpointID     ID     `json:"endpointID"`
+	// CheckIDs restricts the rule to statuses of these checks.
+	// An empty list matches every check of the organization.
+	CheckIDs    []ID         `json:"checkIDs,omitempty"`
+	StatusRules []StatusRule `json:"statusRules"`
+	// MessageTemplate is a text/template executed with the status change
+	// to produce the notification message.
+	MessageTemplate string `json:"messageTemplate,omitempty"`
+}
+
+// StatusRule matches a change to CurrentLevel, optionally only when the
+// previous level was PreviousLevel.
+type StatusRule struct {
+	CurrentLevel  CheckLevel  `json:"currentLevel"`
+	PreviousLevel *CheckLevel `json:"previousLevel,omitempty"`
+}
+
+// DefaultNotificationMessageTemplate is used by rules without a message template.
+const DefaultNotificationMessageTemplate = `{{.CheckName}} is {{.Level}}{{if .PreviousLevel}} (was {{.PreviousLevel}}){{end}}`
+
+// Valid returns an error if the notification rule is not valid.
+func (r *NotificationRule) Valid() error {
+	if r.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification rule name is required",
+		}
+	}
+	if !r.EndpointID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification rule endpoint id is invalid",
+		}
+	}
+	switch r.Status {
+	case "", CheckStatusActive, CheckStatusInactive:
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid notification rule status %q", r.Status),
+		}
+	}
+	if len(r.StatusRules) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification rule requires at least one status rule",
+		}
+	}
+	for _, sr := range r.StatusRules {
+		if err := sr.CurrentLevel.Valid(); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Err:  err,
+			}
+		}
+		if sr.PreviousLevel != nil {
+			if err := sr.PreviousLevel.Valid(); err != nil {
+				return &Error{
+					Code: EInvalid,
+					Err:  err,
+				}
+			}
+		}
+	}
+	if r.MessageTemplate != "" {
+		if _, err := template.New("message").Parse(r.MessageTemplate); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "invalid notification rule message template",
+				Err:  err,
+			}
+		}
+	}
+	return nil
+}
+
+// Matches returns true if a status of check changing from previous to
+// current should be sent by the rule. Previous is empty for the first
+// status of a series.
+func (r *NotificationRule) Matches(checkID ID, previous, current CheckLevel) bool {
+	if r.Status == CheckStatusInactive || previous == current {
+		return false
+	}
+	if len(r.CheckIDs) > 0 {
+		found := false
+		for _, id := range r.CheckIDs {
+			if id == checkID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, sr := range r.StatusRules {
+		if sr.CurrentLevel != current {
+			continue
+		}
+		if sr.PreviousLevel == nil || *sr.PreviousLevel == previous {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationRuleFilter represents a set of filters that restrict the returned notification rules.
+type NotificationRuleFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	Organization   *string
+}
+
+// QueryParams implements PagingFilter.
+//
+// It converts NotificationRuleFilter fields to url query params.
+func (f NotificationRuleFilter) QueryParams() map[string][]string {
+	return orgFilterQueryParams(f.ID, f.OrganizationID, f.Organization)
+}
+
+// NotificationRuleUpdate is the changeset for a notification rule.
+type NotificationRuleUpdate struct {
+	Name            *string      `json:"name,omitempty"`
+	Description     *string      `json:"description,omitempty"`
+	Status          *string      `json:"status,omitempty"`
+	EndpointID      *ID          `json:"endpointID,omitempty"`
+	CheckIDs        []ID         `json:"checkIDs,omitempty"`
+	StatusRules     []StatusRule `json:"statusRules,omitempty"`
+	MessageTemplate *string      `json:"messageTemplate,omitempty"`
+}
+
+// Valid returns an error if the update changes nothing.
+func (u NotificationRuleUpdate) Valid() error {
+	if u.Name == nil && u.Description == nil && u.Status == nil && u.EndpointID == nil &&
+		u.CheckIDs == nil && u.StatusRules == nil && u.MessageTemplate == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "no fields supplied in update",
+		}
+	}
+	return nil
+}
+
+// Apply applies the non-nil fields of the update to r and validates the result.
+func (u NotificationRuleUpdate) Apply(r *NotificationRule) error {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Status != nil {
+		r.Status = *u.Status
+	}
+	if u.EndpointID != nil {
+		r.EndpointID = *u.EndpointID
+	}
+	if u.CheckIDs != nil {
+		r.CheckIDs = u.CheckIDs
+	}
+	if u.StatusRules != nil {
+		r.StatusRules = u.StatusRules
+	}
+	if u.MessageTemplate != nil {
+		r.MessageTemplate = *u.MessageTemplate
+	}
+	return r.Valid()
+}
+
+// Notification endpoint types
+const (
+	// HTTPNotificationEndpointType posts notifications as JSON to a URL.
+	HTTPNotificationEndpointType = "http"
+)
+
+// NotificationEndpoint is a destination for notifications.
+type NotificationEndpoint struct {
+	ID             ID     `json:"id,omitempty"`
+	OrganizationID ID     `json:"orgID,omitempty"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	URL            string `json:"url"`
+	// Method is the HTTP method used to send notifications; it defaults to POST.
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Valid returns an error if the notification endpoint is not valid.
+func (e *NotificationEndpoint) Valid() error {
+	if e.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification endpoint name is required",
+		}
+	}
+	if e.Type != HTTPNotificationEndpointType {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unknown notification endpoint type %q", e.Type),
+		}
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid notification endpoint url %q", e.URL),
+		}
+	}
+	switch e.Method {
+	case "", "POST", "PUT":
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid notification endpoint method %q", e.Method),
+		}
+	}
+	return nil
+}
+
+// NotificationEndpointFilter represents a set of filters that restrict the returned notification endpoints.
+type NotificationEndpointFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	Organization   *string
+}
+
+// QueryParams implements PagingFilter.
+//
+// It converts NotificationEndpointFilter fields to url query params.
+func (f NotificationEndpointFilter) QueryParams() map[string][]string {
+	return orgFilterQueryParams(f.ID, f.OrganizationID, f.Organization)
+}
+
+// NotificationEndpointUpdate is the changeset for a notification endpoint.
+type NotificationEndpointUpdate struct {
+	Name    *string           `json:"name,omitempty"`
+	URL     *string           `json:"url,omitempty"`
+	Method  *string           `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Valid returns an error if the update changes nothing.
+func (u NotificationEndpointUpdate) Valid() error {
+	if u.Name == nil && u.URL == nil && u.Method == nil && u.Headers == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "no fields supplied in update",
+		}
+	}
+	return nil
+}
+
+// Apply applies the non-nil fields of the update to e and validates the result.
+func (u NotificationEndpointUpdate) Apply(e *NotificationEndpoint) error {
+	if u.Name != nil {
+		e.Name = *u.Name
+	}
+	if u.URL != nil {
+		e.URL = *u.URL
+	}
+	if u.Method != nil {
+		e.Method = *u.Method
+	}
+	if u.Headers != nil {
+		e.Headers = u.Headers
+	}
+	return e.Valid()
+}
+
+func orgFilterQueryParams(id, orgID *ID, org *string) map[string][]string {
+	qp := url.Values{}
+	if id != nil {
+		qp.Add("id", id.String())
+	}
+
+	if orgID != nil {
+		qp.Add("orgID", orgID.String())
+	}
+
+	if org != nil {
+		qp.Add("org", *org)
+	}
+
+	return qp
+}
@@ -0,0 +1,140 @@
+package oauth
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
+)
+
+// Config configures the OAuth2 providers.  A provider is enabled when its
+// client ID and secret are set.  The fields match the options of Chronograf.
+type Config struct {
+	// TokenSecret signs the state of the OAuth2 flows.  No provider is
+	// enabled without it.
+	TokenSecret string
+	// JwksURL returns the keys used to validate RS256 id_tokens.
+	JwksURL string
+	// UseIDToken reads the user from the id_token of the providers that
+	// return one.
+	UseIDToken bool
+	// PublicURL is the URL influxd is accessed with by browsers.  The
+	// callback URLs of the providers are relative to it.
+	PublicURL string
+
+	GithubClientID     string
+	GithubClientSecret string
+	GithubOrgs         []string
+
+	GoogleClientID     string
+	GoogleClientSecret string
+	GoogleDomains      []string
+
+	HerokuClientID      string
+	HerokuSecret        string
+	HerokuOrganizations []string
+
+	Auth0Domain        string
+	Auth0ClientID      string
+	Auth0ClientSecret  string
+	Auth0Organizations []string
+
+	GenericName         string
+	GenericClientID     string
+	GenericClientSecret string
+	GenericScopes       []string
+	GenericDomains      []string
+	GenericAuthURL      string
+	GenericTokenURL     string
+	GenericAPIURL       string
+	GenericAPIKey       string
+}
+
+// Providers returns the enabled providers.
+func (c *Config) Providers(logger chronograf.Logger) ([]oauth2.Provider, error) {
+	if c.TokenSecret == "" {
+		return nil, nil
+	}
+
+	var ps []oauth2.Provider
+	if c.GithubClientID != "" && c.GithubClientSecret != "" {
+		ps = append(ps, &oauth2.Github{
+			ClientID:     c.GithubClientID,
+			ClientSecret: c.GithubClientSecret,
+			Orgs:         c.GithubOrgs,
+			Logger:       logger,
+		})
+	}
+	if c.GoogleClientID != "" && c.GoogleClientSecret != "" {
+		redirectURL, err := c.CallbackURL("google")
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, &oauth2.Google{
+			ClientID:     c.GoogleClientID,
+			ClientSecret: c.GoogleClientSecret,
+			Domains:      c.GoogleDomains,
+			RedirectURL:  redirectURL,
+			Logger:       logger,
+		})
+	}
+	if c.HerokuClientID != "" && c.HerokuSecret != "" {
+		ps = append(ps, &oauth2.Heroku{
+			ClientID:      c.HerokuClientID,
+			ClientSecret:  c.HerokuSecret,
+			Organizations: c.HerokuOrganizations,
+			Logger:        logger,
+		})
+	}
+	if c.Auth0ClientID != "" && c.Auth0ClientSecret != "" {
+		redirectURL, err := c.CallbackURL("auth0")
+		if err != nil {
+			return nil, err
+		}
+		auth0, err := oauth2.NewAuth0(c.Auth0Domain, c.Auth0ClientID, c.Auth0ClientSecret, redirectURL, c.Auth0Organizations, logger)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Auth0 domain: %v", err)
+		}
+		ps = append(ps, &auth0)
+	}
+	if c.GenericClientID != "" && c.GenericClientSecret != "" {
+		if c.GenericAuthURL == "" || c.GenericTokenURL == "" {
+			return nil, fmt.Errorf("the generic OAuth2 provider requires an auth URL and a token URL")
+		}
+		gen := &oauth2.Generic{
+			PageName:       c.GenericName,
+			ClientID:       c.GenericClientID,
+			ClientSecret:   c.GenericClientSecret,
+			RequiredScopes: c.GenericScopes,
+			Domains:        c.GenericDomains,
+			AuthURL:        c.GenericAuthURL,
+			TokenURL:       c.GenericTokenURL,
+			APIURL:         c.GenericAPIURL,
+			APIKey:         c.GenericAPIKey,
+			Logger:         logger,
+		}
+		redirectURL, err := c.CallbackURL(gen.Name())
+		if err != nil {
+			return nil, err
+		}
+		gen.RedirectURL = redirectURL
+		ps = append(ps, gen)
+	}
+	return ps, nil
+}
+
+// CallbackURL returns the URL the provider named name redirects browsers to
+// after they signed in.  It is empty when there is no public URL.
+func (c *Config) CallbackURL(name string) (string, error) {
+	if c.PublicURL == "" {
+		return "", nil
+	}
+	u, err := url.Parse(c.PublicURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid public URL: %v", err)
+	}
+	u.Path = path.Join(u.Path, "/api/v2/oauth", name, "callback")
+	return u.String(), nil
+}
@@ -0,0 +1,44 @@
+package oauth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/influxdata/influxdb/chronograf"
+	"go.uber.org/zap"
+)
+
+// NewLogger returns a chronograf.Logger that writes to l.  The providers of
+// chronograf/oauth2 log with it.
+func NewLogger(l *zap.Logger) chronograf.Logger {
+	return &logger{l: l.Sugar()}
+}
+
+type logger struct {
+	l *zap.SugaredLogger
+}
+
+func (l *logger) Debug(args ...interface{}) { l.l.Debug(args...) }
+func (l *logger) Info(args ...interface{})  { l.l.Info(args...) }
+func (l *logger) Error(args ...interface{}) { l.l.Error(args...) }
+
+func (l *logger) WithField(key string, value interface{}) chronograf.Logger {
+	if s, ok := value.(fmt.Stringer); ok {
+		value = s.String()
+	}
+	return &logger{l: l.l.With(key, value)}
+}
+
+// Writer returns a writer whose lines are logged at the info level.
+func (l *logger) Writer() *io.PipeWriter {
+	r, w := io.Pipe()
+	go func() {
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			l.l.Info(s.Text())
+		}
+		r.CloseWithError(s.Err())
+	}()
+	return w
+}
@@ -0,0 +1,172 @@
+// Package oauth signs users in with the OAuth2 providers of chronograf/oauth2.
+// Users are provisioned on their first sign in, and are granted roles in
+// organizations according to their groups and email domains.
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// Mapping grants the users of a provider group, or of an email domain, a role
+// in an organization.
+type Mapping struct {
+	Group        string
+	Domain       string
+	Organization string
+	Role         platform.UserType
+}
+
+// ParseMapping parses a mapping of the form group:<group>=<org>[:<role>] or
+// domain:<domain>=<org>[:<role>].  The role is member when it is omitted.
+func ParseMapping(s string) (Mapping, error) {
+	var m Mapping
+
+	i := strings.Index(s, "=")
+	if i < 0 {
+		return m, fmt.Errorf("invalid mapping %q: expected <kind>:<value>=<org>[:<role>]", s)
+	}
+	from, to := s[:i], s[i+1:]
+
+	kind, value := from, ""
+	if j := strings.Index(from, ":"); j >= 0 {
+		kind, value = from[:j], from[j+1:]
+	}
+	if value == "" {
+		return m, fmt.Errorf("invalid mapping %q: missing group or domain", s)
+	}
+	switch kind {
+	case "group":
+		m.Group = value
+	case "domain":
+		m.Domain = strings.ToLower(value)
+	default:
+		return m, fmt.Errorf("invalid mapping %q: unknown kind %q, expected group or domain", s, kind)
+	}
+
+	m.Organization, m.Role = to, platform.Member
+	if j := strings.LastIndex(to, ":"); j >= 0 {
+		m.Organization, m.Role = to[:j], platform.UserType(to[j+1:])
+	}
+	if m.Organization == "" {
+		return m, fmt.Errorf("invalid mapping %q: missing organization", s)
+	}
+	if err := m.Role.Valid(); err != nil {
+		return m, fmt.Errorf("invalid mapping %q: unknown role %q, expected owner or member", s, m.Role)
+	}
+	return m, nil
+}
+
+// Matches reports whether the mapping applies to the user with the email
+// address subject and the comma separated groups.
+func (m Mapping) Matches(subject, groups string) bool {
+	if m.Domain != "" {
+		i := strings.LastIndex(subject, "@")
+		return i >= 0 && strings.ToLower(subject[i+1:]) == m.Domain
+	}
+	for _, g := range strings.Split(groups, ",") {
+		if strings.TrimSpace(g) == m.Group {
+			return true
+		}
+	}
+	return false
+}
+
+// Provisioner creates the users that sign in with an OAuth2 provider.
+type Provisioner struct {
+	UserService                platform.UserService
+	OrganizationService        platform.OrganizationService
+	UserResourceMappingService platform.UserResourceMappingService
+	OAuthIdentityService       platform.OAuthIdentityService
+
+	Mappings []Mapping
+}
+
+// Provision returns the user of subject at provider, creating it if it does
+// not exist, and grants it the roles of the mappings that match it.  Roles the
+// user already has are left as they are.
+//
+// Users are found by their identity at the provider, never by name, so that a
+// subject does not sign in as an existing user of the same name or as the
+// user of the same subject at another provider.  New users are named
+// <provider>:<subject>; provisioning fails if that name is already taken.
+func (p *Provisioner) Provision(ctx context.Context, provider, subject, groups string) (*platform.User, error) {
+	if provider == "" || subject == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "provider did not identify the user",
+		}
+	}
+
+	u, err := p.user(ctx, provider, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range p.Mappings {
+		if !m.Matches(subject, groups) {
+			continue
+		}
+		if err := p.grant(ctx, u, m); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// user returns the user of the identity, creating both if they do not exist.
+// An identity whose user was deleted gets a new user.
+func (p *Provisioner) user(ctx context.Context, provider, subject string) (*platform.User, error) {
+	i, err := p.OAuthIdentityService.FindOAuthIdentity(ctx, provider, subject)
+	if err == nil {
+		u, err := p.UserService.FindUserByID(ctx, i.UserID)
+		if platform.ErrorCode(err) != platform.ENotFound {
+			return u, err
+		}
+	} else if platform.ErrorCode(err) != platform.ENotFound {
+		return nil, err
+	}
+
+	u := &platform.User{Name: provider + ":" + subject}
+	if err := p.UserService.CreateUser(ctx, u); err != nil {
+		return nil, err
+	}
+	if err := p.OAuthIdentityService.PutOAuthIdentity(ctx, &platform.OAuthIdentity{
+		Provider: provider,
+		Subject:  subject,
+		UserID:   u.ID,
+	}); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (p *Provisioner) grant(ctx context.Context, u *platform.User, m Mapping) error {
+	o, err := p.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &m.Organization})
+	if err != nil {
+		return err
+	}
+
+	ms, _, err := p.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		UserID:       u.ID,
+		ResourceType: platform.OrgsResourceType,
+	})
+	if err != nil {
+		return err
+	}
+	for _, urm := range ms {
+		if urm.ResourceID == o.ID {
+			return nil
+		}
+	}
+
+	return p.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+		UserID:       u.ID,
+		UserType:     m.Role,
+		ResourceType: platform.OrgsResourceType,
+		ResourceID:   o.ID,
+	})
+}
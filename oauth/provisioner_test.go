@@ -0,0 +1,143 @@
+package oauth_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/oauth"
+)
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    oauth.Mapping
+		wantErr bool
+	}{
+		{
+			in:   "group:admins=ops:owner",
+			want: oauth.Mapping{Group: "admins", Organization: "ops", Role: platform.Owner},
+		},
+		{
+			in:   "domain:Example.com=ops",
+			want: oauth.Mapping{Domain: "example.com", Organization: "ops", Role: platform.Member},
+		},
+		{in: "admins=ops", wantErr: true},
+		{in: "group:=ops", wantErr: true},
+		{in: "group:admins", wantErr: true},
+		{in: "group:admins=:owner", wantErr: true},
+		{in: "group:admins=ops:admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := oauth.ParseMapping(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvisioner_Provision(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	ops := &platform.Organization{Name: "ops"}
+	dev := &platform.Organization{Name: "dev"}
+	for _, o := range []*platform.Organization{ops, dev} {
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A local user with the name of the subject.
+	local := &platform.User{Name: "jane@example.com"}
+	if err := svc.CreateUser(ctx, local); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &oauth.Provisioner{
+		UserService:                svc,
+		OrganizationService:        svc,
+		UserResourceMappingService: svc,
+		OAuthIdentityService:       svc,
+		Mappings: []oauth.Mapping{
+			{Group: "admins", Organization: "ops", Role: platform.Owner},
+			{Domain: "example.com", Organization: "ops", Role: platform.Member},
+			{Domain: "example.com", Organization: "dev", Role: platform.Member},
+		},
+	}
+
+	u, err := p.Provision(ctx, "google", "jane@example.com", "users, admins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "google:jane@example.com" || !u.ID.Valid() || u.ID == local.ID {
+		t.Fatalf("unexpected user %+v", u)
+	}
+
+	// Signing in again returns the same user, and keeps its roles.
+	again, err := p.Provision(ctx, "google", "jane@example.com", "admins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != u.ID {
+		t.Fatalf("got user %v, want %v", again.ID, u.ID)
+	}
+
+	ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{UserID: u.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[platform.ID]platform.UserType)
+	for _, m := range ms {
+		got[m.ResourceID] = m.UserType
+	}
+	want := map[platform.ID]platform.UserType{ops.ID: platform.Owner, dev.ID: platform.Member}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got roles %v, want %v", got, want)
+	}
+
+	// The same subject at another provider is another user.
+	other, err := p.Provision(ctx, "github", "jane@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID == u.ID || other.ID == local.ID {
+		t.Fatalf("subject of another provider signed in as user %v", other.ID)
+	}
+
+	// A deleted user is provisioned again.
+	if err := svc.DeleteUser(ctx, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	recreated, err := p.Provision(ctx, "github", "jane@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recreated.ID == other.ID || recreated.Name != "github:jane@example.com" {
+		t.Fatalf("unexpected user %+v", recreated)
+	}
+
+	// A local user with the name of a new identity is not linked to it.
+	taken := &platform.User{Name: "gitlab:jane@example.com"}
+	if err := svc.CreateUser(ctx, taken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Provision(ctx, "gitlab", "jane@example.com", ""); platform.ErrorCode(err) != platform.EConflict {
+		t.Errorf("got error %v for a taken name, want %s", err, platform.EConflict)
+	}
+
+	if _, err := p.Provision(ctx, "google", "", ""); platform.ErrorCode(err) != platform.EInvalid {
+		t.Errorf("got error %v for a user without subject, want %s", err, platform.EInvalid)
+	}
+}
@@ -0,0 +1,33 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ErrOAuthIdentityNotFound is the error message for a missing OAuth identity.
+const ErrOAuthIdentityNotFound = "oauth identity not found"
+
+// ops for oauth identities errors and op logs.
+var (
+	OpFindOAuthIdentity = "FindOAuthIdentity"
+	OpPutOAuthIdentity  = "PutOAuthIdentity"
+)
+
+// OAuthIdentity links the subject an OAuth2 provider identifies a user by to
+// the user it signs in as.
+type OAuthIdentity struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	UserID   ID     `json:"userID"`
+}
+
+// OAuthIdentityService stores the identities users sign in with OAuth2
+// providers. Identities are keyed by provider and subject, so that the same
+// subject of two providers are two identities.
+type OAuthIdentityService interface {
+	// FindOAuthIdentity returns the identity of subject at provider.
+	FindOAuthIdentity(ctx context.Context, provider, subject string) (*OAuthIdentity, error)
+
+	// PutOAuthIdentity creates or replaces an identity.
+	PutOAuthIdentity(ctx context.Context, i *OAuthIdentity) error
+}
@@ -0,0 +1,153 @@
+package gen
+
+import (
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+type measurementGenerator struct {
+	name   []byte
+	tags   TagsSequence
+	fields []FieldSpec
+}
+
+var _ SeriesGenerator = (*SpecSeriesGenerator)(nil)
+
+// SpecSeriesGenerator generates the series described by a Spec. Series are
+// ordered by measurement, then by tag set and then by field.
+type SpecSeriesGenerator struct {
+	measurements []measurementGenerator
+	n            int
+	start        time.Time
+	interval     time.Duration
+
+	m     int
+	f     int
+	field []byte
+}
+
+// NewSpecSeriesGenerator returns a generator for the series of s whose
+// first point is at start.
+func NewSpecSeriesGenerator(s *Spec, start time.Time) *SpecSeriesGenerator {
+	g := &SpecSeriesGenerator{
+		measurements: make([]measurementGenerator, 0, len(s.Measurements)),
+		n:            s.PointsPerSeries(),
+		start:        start,
+		interval:     time.Duration(s.Interval),
+	}
+
+	for _, m := range s.Measurements {
+		keys := make([]string, len(m.Tags))
+		vals := make([]CountableSequence, len(m.Tags))
+		for i, t := range m.Tags {
+			format := t.Format
+			if format == "" {
+				format = defaultTagFormat
+			}
+			keys[i] = t.Name
+			vals[i] = NewCounterByteSequence(format, 0, t.Cardinality)
+		}
+		g.measurements = append(g.measurements, measurementGenerator{
+			name:   []byte(m.Name),
+			tags:   NewTagsValuesSequenceKeysValues(keys, vals),
+			fields: m.Fields,
+		})
+	}
+
+	// Position before the first field of the first measurement.
+	if len(g.measurements) > 0 {
+		g.f = len(g.measurements[0].fields)
+	}
+	return g
+}
+
+func (g *SpecSeriesGenerator) Next() bool {
+	for g.m < len(g.measurements) {
+		m := &g.measurements[g.m]
+		if g.f+1 < len(m.fields) {
+			g.f++
+			g.field = []byte(m.fields[g.f].Name)
+			return true
+		}
+		if m.tags.Next() {
+			g.f = -1
+			continue
+		}
+
+		g.m++
+		if g.m < len(g.measurements) {
+			g.f = len(g.measurements[g.m].fields)
+		}
+	}
+	return false
+}
+
+func (g *SpecSeriesGenerator) Name() []byte      { return g.measurements[g.m].name }
+func (g *SpecSeriesGenerator) Tags() models.Tags { return g.measurements[g.m].tags.Value() }
+func (g *SpecSeriesGenerator) Field() []byte     { return g.field }
+
+// FieldSpec returns the spec of the current field.
+func (g *SpecSeriesGenerator) FieldSpec() *FieldSpec { return &g.measurements[g.m].fields[g.f] }
+
+func (g *SpecSeriesGenerator) ValuesGenerator() ValuesSequence {
+	return g.FieldSpec().NewValuesSequence(g.n, g.start, g.interval)
+}
+
+// NewValuesSequence returns a sequence of n values of the field, starting
+// at start and spaced by interval.
+func (f *FieldSpec) NewValuesSequence(n int, start time.Time, interval time.Duration) ValuesSequence {
+	switch f.Type {
+	case FloatFieldType:
+		switch v := f.Value.(type) {
+		case float64:
+			return NewFloatConstantValuesSequence(n, start, interval, v)
+		case int64:
+			return NewFloatConstantValuesSequence(n, start, interval, float64(v))
+		}
+		return NewFloatRandomValuesSequence(n, start, interval, scale(f.Scale))
+	case IntegerFieldType:
+		if v, ok := f.Value.(int64); ok {
+			return NewIntegerConstantValuesSequence(n, start, interval, v)
+		}
+		max := int64(scale(f.Scale))
+		if max < 1 {
+			max = 1
+		}
+		return NewIntegerRandomValuesSequence(n, start, interval, max)
+	case UnsignedFieldType:
+		v, _ := f.Value.(int64)
+		return NewUnsignedConstantValuesSequence(n, start, interval, uint64(v))
+	case StringFieldType:
+		v, _ := f.Value.(string)
+		return NewStringConstantValuesSequence(n, start, interval, v)
+	default:
+		v, _ := f.Value.(bool)
+		return NewBooleanConstantValuesSequence(n, start, interval, v)
+	}
+}
+
+// FieldType returns the type of the values of the field.
+func (f *FieldSpec) FieldType() models.FieldType {
+	switch f.Type {
+	case FloatFieldType:
+		return models.Float
+	case IntegerFieldType:
+		return models.Integer
+	case UnsignedFieldType:
+		return models.Unsigned
+	case StringFieldType:
+		return models.String
+	default:
+		return models.Boolean
+	}
+}
+
+const defaultScale = 100
+
+func scale(s float64) float64 {
+	if s == 0 {
+		return defaultScale
+	}
+	return s
+}
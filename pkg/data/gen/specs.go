@@ -0,0 +1,213 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	itoml "github.com/influxdata/influxdb/toml"
+)
+
+// Spec describes the shape of generated data. It is usually decoded from
+// TOML, for example:
+//
+//	start    = 2019-01-01T00:00:00Z
+//	span     = "24h"
+//	interval = "10s"
+//
+//	[[measurements]]
+//	name   = "cpu"
+//	tags   = [
+//	  { name = "host", cardinality = 100, format = "host-%s" },
+//	  { name = "region", cardinality = 4 },
+//	]
+//	fields = [
+//	  { name = "usage_user", type = "float", scale = 100.0 },
+//	  { name = "running", type = "boolean", value = true },
+//	]
+type Spec struct {
+	// Start is the time of the first point. When zero, the data ends at
+	// the current time.
+	Start time.Time `toml:"start"`
+
+	// Span is the duration covered by every series.
+	Span itoml.Duration `toml:"span"`
+
+	// Interval is the duration between two points of a series.
+	Interval itoml.Duration `toml:"interval"`
+
+	Measurements []MeasurementSpec `toml:"measurements"`
+}
+
+// MeasurementSpec describes a measurement. The series of a measurement are
+// the cartesian product of the values of its tags.
+type MeasurementSpec struct {
+	Name   string      `toml:"name"`
+	Tags   []TagSpec   `toml:"tags"`
+	Fields []FieldSpec `toml:"fields"`
+}
+
+// TagSpec describes a tag key and the number of its distinct values.
+type TagSpec struct {
+	Name        string `toml:"name"`
+	Cardinality int    `toml:"cardinality"`
+
+	// Format formats the zero-padded number of a value. It defaults to "value%s".
+	Format string `toml:"format"`
+}
+
+// FieldSpec describes a field. A field with a Value has that value at
+// every point. Otherwise float and integer fields have random values in
+// [0, Scale), where Scale defaults to 100, and fields of other types have
+// the zero value of their type.
+type FieldSpec struct {
+	Name  string      `toml:"name"`
+	Type  string      `toml:"type"`
+	Value interface{} `toml:"value"`
+	Scale float64     `toml:"scale"`
+}
+
+// Field types of a FieldSpec.
+const (
+	FloatFieldType    = "float"
+	IntegerFieldType  = "integer"
+	UnsignedFieldType = "unsigned"
+	StringFieldType   = "string"
+	BooleanFieldType  = "boolean"
+)
+
+const defaultTagFormat = "value%s"
+
+// NewSpecFromToml decodes and validates a TOML spec.
+func NewSpecFromToml(r io.Reader) (*Spec, error) {
+	s := &Spec{}
+	md, err := toml.DecodeReader(r, s)
+	if err != nil {
+		return nil, err
+	}
+	if keys := md.Undecoded(); len(keys) > 0 {
+		return nil, fmt.Errorf("unknown key %q", keys[0].String())
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate returns an error if s cannot generate data.
+func (s *Spec) Validate() error {
+	if s.Interval <= 0 {
+		return errors.New("interval must be greater than zero")
+	}
+	if s.Span < s.Interval {
+		return errors.New("span must not be less than interval")
+	}
+	if len(s.Measurements) == 0 {
+		return errors.New("at least one measurement is required")
+	}
+	for _, m := range s.Measurements {
+		if err := m.validate(); err != nil {
+			return fmt.Errorf("measurement %q: %v", m.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *MeasurementSpec) validate() error {
+	if m.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(m.Fields) == 0 {
+		return errors.New("at least one field is required")
+	}
+	seen := make(map[string]bool)
+	for _, t := range m.Tags {
+		if t.Name == "" {
+			return errors.New("tag name is required")
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("duplicate tag %q", t.Name)
+		}
+		seen[t.Name] = true
+		if t.Cardinality <= 0 {
+			return fmt.Errorf("tag %q: cardinality must be greater than zero", t.Name)
+		}
+	}
+	seen = make(map[string]bool)
+	for _, f := range m.Fields {
+		if f.Name == "" {
+			return errors.New("field name is required")
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("duplicate field %q", f.Name)
+		}
+		seen[f.Name] = true
+		if err := f.validate(); err != nil {
+			return fmt.Errorf("field %q: %v", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func (f *FieldSpec) validate() error {
+	var ok bool
+	switch f.Type {
+	case FloatFieldType:
+		switch f.Value.(type) {
+		case nil, float64, int64:
+			ok = true
+		}
+	case IntegerFieldType:
+		_, ok = f.Value.(int64)
+		ok = ok || f.Value == nil
+	case UnsignedFieldType:
+		v, isInt := f.Value.(int64)
+		ok = (isInt && v >= 0) || f.Value == nil
+	case StringFieldType:
+		_, ok = f.Value.(string)
+		ok = ok || f.Value == nil
+	case BooleanFieldType:
+		_, ok = f.Value.(bool)
+		ok = ok || f.Value == nil
+	default:
+		return fmt.Errorf("unknown type %q", f.Type)
+	}
+	if !ok {
+		return fmt.Errorf("invalid %s value %v", f.Type, f.Value)
+	}
+	if f.Scale < 0 {
+		return errors.New("scale must not be negative")
+	}
+	return nil
+}
+
+// PointsPerSeries returns the number of points of every series.
+func (s *Spec) PointsPerSeries() int {
+	return int(time.Duration(s.Span) / time.Duration(s.Interval))
+}
+
+// StartTime returns the time of the first point, relative to now when
+// Start is zero.
+func (s *Spec) StartTime(now time.Time) time.Time {
+	if !s.Start.IsZero() {
+		return s.Start
+	}
+	interval := time.Duration(s.Interval)
+	return now.Truncate(interval).Add(-time.Duration(s.PointsPerSeries()) * interval)
+}
+
+// SeriesCount returns the number of series generated by s, counting
+// every field as a series.
+func (s *Spec) SeriesCount() int {
+	var n int
+	for _, m := range s.Measurements {
+		c := len(m.Fields)
+		for _, t := range m.Tags {
+			c *= t.Cardinality
+		}
+		n += c
+	}
+	return n
+}
@@ -0,0 +1,146 @@
+package gen_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/data/gen"
+)
+
+const testSpec = `
+start    = 2019-01-01T00:00:00Z
+span     = "1h"
+interval = "10s"
+
+[[measurements]]
+name   = "cpu"
+tags   = [
+  { name = "region", cardinality = 2 },
+  { name = "host", cardinality = 3, format = "host-%s" },
+]
+fields = [
+  { name = "usage", type = "float" },
+  { name = "ok", type = "boolean", value = true },
+]
+
+[[measurements]]
+name   = "mem"
+fields = [ { name = "free", type = "integer", value = 5 } ]
+`
+
+func TestNewSpecFromToml(t *testing.T) {
+	s, err := gen.NewSpecFromToml(strings.NewReader(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := s.SeriesCount(), 2*3*2+1; got != exp {
+		t.Errorf("unexpected series count -got/+exp\n%d\n%d", got, exp)
+	}
+	if got, exp := s.PointsPerSeries(), 360; got != exp {
+		t.Errorf("unexpected points per series -got/+exp\n%d\n%d", got, exp)
+	}
+}
+
+func TestNewSpecFromToml_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "missing interval",
+			spec: `span = "1h"`,
+			err:  "interval must be greater than zero",
+		},
+		{
+			name: "unknown key",
+			spec: `interval = "1s"
+span = "1h"
+foo = 1`,
+			err: `unknown key "foo"`,
+		},
+		{
+			name: "unknown field type",
+			spec: `interval = "1s"
+span = "1h"
+[[measurements]]
+name = "m"
+fields = [ { name = "f", type = "decimal" } ]`,
+			err: `measurement "m": field "f": unknown type "decimal"`,
+		},
+		{
+			name: "invalid value",
+			spec: `interval = "1s"
+span = "1h"
+[[measurements]]
+name = "m"
+fields = [ { name = "f", type = "integer", value = "x" } ]`,
+			err: `measurement "m": field "f": invalid integer value x`,
+		},
+		{
+			name: "zero cardinality",
+			spec: `interval = "1s"
+span = "1h"
+[[measurements]]
+name = "m"
+tags = [ { name = "t" } ]
+fields = [ { name = "f", type = "float" } ]`,
+			err: `measurement "m": tag "t": cardinality must be greater than zero`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := gen.NewSpecFromToml(strings.NewReader(tt.spec))
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("unexpected error -got/+exp\n%v\n%s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSpecSeriesGenerator(t *testing.T) {
+	s, err := gen.NewSpecFromToml(strings.NewReader(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	g := gen.NewSpecSeriesGenerator(s, s.StartTime(time.Now()))
+	for g.Next() {
+		keys = append(keys, string(models.MakeKey(g.Name(), g.Tags()))+" "+string(g.Field()))
+
+		var n int
+		vs := g.ValuesGenerator()
+		for vs.Next() {
+			v := vs.Values()
+			if n == 0 && v.MinTime() != s.Start.UnixNano() {
+				t.Errorf("unexpected min time for %s: %d", keys[len(keys)-1], v.MinTime())
+			}
+			n++
+		}
+		if n == 0 {
+			t.Errorf("no values for %s", keys[len(keys)-1])
+		}
+	}
+
+	exp := []string{
+		"cpu,host=host-0,region=value0 usage",
+		"cpu,host=host-0,region=value0 ok",
+		"cpu,host=host-0,region=value1 usage",
+		"cpu,host=host-0,region=value1 ok",
+	}
+	if len(keys) != s.SeriesCount() {
+		t.Fatalf("unexpected number of series -got/+exp\n%d\n%d", len(keys), s.SeriesCount())
+	}
+	for i := range exp {
+		if keys[i] != exp[i] {
+			t.Errorf("unexpected series %d -got/+exp\n%s\n%s", i, keys[i], exp[i])
+		}
+	}
+	if got, exp := keys[len(keys)-1], "mem free"; got != exp {
+		t.Errorf("unexpected last series -got/+exp\n%s\n%s", got, exp)
+	}
+}
@@ -58,3 +58,55 @@ func (g *FloatRandomValuesSequence) Next() bool {
 func (g *FloatRandomValuesSequence) Values() Values {
 	return &g.vals
 }
+
+type IntegerRandomValuesSequence struct {
+	buf   IntegerArray
+	vals  IntegerArray
+	n     int
+	t     int64
+	state struct {
+		n     int
+		t     int64
+		d     int64
+		scale int64
+	}
+}
+
+func NewIntegerRandomValuesSequence(n int, start time.Time, delta time.Duration, scale int64) *IntegerRandomValuesSequence {
+	g := &IntegerRandomValuesSequence{
+		buf: *NewIntegerArrayLen(cursors.DefaultMaxPointsPerBlock),
+	}
+	g.state.n = n
+	g.state.t = start.UnixNano()
+	g.state.d = int64(delta)
+	g.state.scale = scale
+	g.Reset()
+	return g
+}
+
+func (g *IntegerRandomValuesSequence) Reset() {
+	g.n = g.state.n
+	g.t = g.state.t
+}
+
+func (g *IntegerRandomValuesSequence) Next() bool {
+	if g.n == 0 {
+		return false
+	}
+
+	c := min(g.n, cursors.DefaultMaxPointsPerBlock)
+	g.n -= c
+	g.vals.Timestamps = g.buf.Timestamps[:0]
+	g.vals.Values = g.buf.Values[:0]
+
+	for i := 0; i < c; i++ {
+		g.vals.Timestamps = append(g.vals.Timestamps, g.t)
+		g.vals.Values = append(g.vals.Values, rand.Int63n(g.state.scale))
+		g.t += g.state.d
+	}
+	return true
+}
+
+func (g *IntegerRandomValuesSequence) Values() Values {
+	return &g.vals
+}
@@ -3,10 +3,13 @@ package control
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/control"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/check"
 	"github.com/influxdata/influxdb/query"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -17,6 +20,9 @@ const orgLabel = "org"
 // Controller implements AsyncQueryService by consuming a control.Controller.
 type Controller struct {
 	c *control.Controller
+
+	// shutdown is set once the controller is shut down.
+	shutdown int32
 }
 
 // NewController creates a new Controller specific to platform.
@@ -52,5 +58,20 @@ func (c *Controller) PrometheusCollectors() []prometheus.Collector {
 
 // Shutdown shuts down the underlying Controller.
 func (c *Controller) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&c.shutdown, 1)
 	return c.c.Shutdown(ctx)
 }
+
+// CheckName returns the name of the controller's health check.
+func (c *Controller) CheckName() string {
+	return "query-controller"
+}
+
+// Check reports whether the controller accepts queries, with the number of
+// queries it is running.
+func (c *Controller) Check(ctx context.Context) check.Response {
+	if atomic.LoadInt32(&c.shutdown) != 0 {
+		return check.Error(errors.New("query controller is shut down"))
+	}
+	return check.Info("%d queries", len(c.c.Queries()))
+}
@@ -0,0 +1,349 @@
+package dialect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/google/flatbuffers/go"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+)
+
+// ArrowDialect encodes query results in the Apache Arrow IPC streaming format.
+//
+// Each table is written as its own Arrow stream: a schema message followed by
+// one record batch per buffer of rows and an end-of-stream marker. Clients read
+// the response by opening stream readers one after another until the body is
+// exhausted. The schema metadata holds the result name under "flux.result" and
+// the comma separated group key columns under "flux.groupKey". A query error
+// is sent as a stream whose schema has no fields and a "flux.error" metadata entry.
+//
+// Columns are encoded as bool, int64, uint64, float64, utf8 and
+// timestamp[ns, UTC] arrays, and are always nullable.
+type ArrowDialect struct{}
+
+// SetHeaders sets the content type of the response.
+func (d *ArrowDialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ArrowMediaType)
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+// Encoder returns an Arrow IPC multi result encoder.
+func (d *ArrowDialect) Encoder() flux.MultiResultEncoder {
+	return &flux.DelimitedMultiResultEncoder{
+		Delimiter: []byte{},
+		Encoder:   new(ArrowResultEncoder),
+	}
+}
+
+// DialectType returns the arrow dialect type.
+func (d *ArrowDialect) DialectType() flux.DialectType {
+	return ArrowDialectType
+}
+
+// Schema metadata keys written by ArrowResultEncoder.
+const (
+	ArrowResultMetadataKey   = "flux.result"
+	ArrowGroupKeyMetadataKey = "flux.groupKey"
+	ArrowErrorMetadataKey    = "flux.error"
+)
+
+// ArrowResultEncoder encodes the tables of a single result as Arrow IPC streams.
+type ArrowResultEncoder struct{}
+
+// Encode writes one Arrow stream per table of result to w.
+func (e *ArrowResultEncoder) Encode(w io.Writer, result flux.Result) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	err := result.Tables().Do(func(tbl flux.Table) error {
+		keyCols := make([]string, len(tbl.Key().Cols()))
+		for j, c := range tbl.Key().Cols() {
+			keyCols[j] = c.Label
+		}
+
+		aw := &arrowStreamWriter{w: wc}
+		if err := aw.writeSchema(tbl.Cols(), map[string]string{
+			ArrowResultMetadataKey:   result.Name(),
+			ArrowGroupKeyMetadataKey: strings.Join(keyCols, ","),
+		}); err != nil {
+			return err
+		}
+		if err := tbl.Do(aw.writeRecordBatch); err != nil {
+			return err
+		}
+		return aw.writeEOS()
+	})
+	return wc.Count(), err
+}
+
+// EncodeError writes err as an Arrow stream with an empty schema.
+func (e *ArrowResultEncoder) EncodeError(w io.Writer, err error) error {
+	aw := &arrowStreamWriter{w: w}
+	if err := aw.writeSchema(nil, map[string]string{
+		ArrowErrorMetadataKey: err.Error(),
+	}); err != nil {
+		return err
+	}
+	return aw.writeEOS()
+}
+
+// Flatbuffer enumerations from the Arrow format definitions in Schema.fbs and Message.fbs.
+const (
+	arrowMetadataV4 = 3
+
+	arrowHeaderSchema      = 1
+	arrowHeaderRecordBatch = 3
+
+	arrowTypeInt           = 2
+	arrowTypeFloatingPoint = 3
+	arrowTypeUtf8          = 5
+	arrowTypeBool          = 6
+	arrowTypeTimestamp     = 10
+
+	arrowPrecisionDouble = 2
+	arrowTimeUnitNano    = 3
+)
+
+// arrowStreamWriter writes the encapsulated messages of an Arrow IPC stream.
+type arrowStreamWriter struct {
+	w io.Writer
+}
+
+func (aw *arrowStreamWriter) writeSchema(cols []flux.ColMeta, metadata map[string]string) error {
+	b := flatbuffers.NewBuilder(1024)
+
+	fields := make([]flatbuffers.UOffsetT, len(cols))
+	for j, c := range cols {
+		fields[j] = arrowField(b, c)
+	}
+	b.StartVector(4, len(fields), 4)
+	for j := len(fields) - 1; j >= 0; j-- {
+		b.PrependUOffsetT(fields[j])
+	}
+	fieldsVec := b.EndVector(len(fields))
+	metadataVec := arrowKeyValues(b, metadata)
+
+	b.StartObject(3)
+	b.PrependUOffsetTSlot(1, fieldsVec, 0)
+	b.PrependUOffsetTSlot(2, metadataVec, 0)
+	schema := b.EndObject()
+
+	return aw.writeMessage(b, arrowHeaderSchema, schema, nil)
+}
+
+func (aw *arrowStreamWriter) writeRecordBatch(cr flux.ColReader) error {
+	var (
+		body    bytes.Buffer
+		nodes   [][2]int64 // length, null count
+		buffers [][2]int64 // offset, length
+	)
+	addBuffer := func(buf []byte) {
+		buffers = append(buffers, [2]int64{int64(body.Len()), int64(len(buf))})
+		body.Write(buf)
+		if pad := body.Len() % 8; pad != 0 {
+			body.Write(make([]byte, 8-pad))
+		}
+	}
+
+	n := cr.Len()
+	for j, c := range cr.Cols() {
+		validity := make([]byte, (n+7)/8)
+		nulls := 0
+		setValid := func(i int, valid bool) {
+			if valid {
+				validity[i/8] |= 1 << uint(i%8)
+			} else {
+				nulls++
+			}
+		}
+
+		switch c.Type {
+		case flux.TBool:
+			a := cr.Bools(j)
+			values := make([]byte, (n+7)/8)
+			for i := 0; i < n; i++ {
+				setValid(i, !a.IsNull(i))
+				if !a.IsNull(i) && a.Value(i) {
+					values[i/8] |= 1 << uint(i%8)
+				}
+			}
+			addBuffer(validity)
+			addBuffer(values)
+		case flux.TInt, flux.TTime:
+			var a *array.Int64
+			if c.Type == flux.TTime {
+				a = cr.Times(j)
+			} else {
+				a = cr.Ints(j)
+			}
+			values := make([]byte, 8*n)
+			for i := 0; i < n; i++ {
+				setValid(i, !a.IsNull(i))
+				binary.LittleEndian.PutUint64(values[8*i:], uint64(a.Value(i)))
+			}
+			addBuffer(validity)
+			addBuffer(values)
+		case flux.TUInt:
+			a := cr.UInts(j)
+			values := make([]byte, 8*n)
+			for i := 0; i < n; i++ {
+				setValid(i, !a.IsNull(i))
+				binary.LittleEndian.PutUint64(values[8*i:], a.Value(i))
+			}
+			addBuffer(validity)
+			addBuffer(values)
+		case flux.TFloat:
+			a := cr.Floats(j)
+			values := make([]byte, 8*n)
+			for i := 0; i < n; i++ {
+				setValid(i, !a.IsNull(i))
+				binary.LittleEndian.PutUint64(values[8*i:], math.Float64bits(a.Value(i)))
+			}
+			addBuffer(validity)
+			addBuffer(values)
+		case flux.TString:
+			a := cr.Strings(j)
+			offsets := make([]byte, 4*(n+1))
+			var data []byte
+			for i := 0; i < n; i++ {
+				setValid(i, !a.IsNull(i))
+				if !a.IsNull(i) {
+					data = append(data, a.Value(i)...)
+				}
+				binary.LittleEndian.PutUint32(offsets[4*(i+1):], uint32(len(data)))
+			}
+			addBuffer(validity)
+			addBuffer(offsets)
+			addBuffer(data)
+		}
+		nodes = append(nodes, [2]int64{int64(n), int64(nulls)})
+	}
+
+	b := flatbuffers.NewBuilder(1024)
+	nodesVec := arrowStructVector(b, nodes)
+	buffersVec := arrowStructVector(b, buffers)
+	b.StartObject(3)
+	b.PrependInt64Slot(0, int64(n), 0)
+	b.PrependUOffsetTSlot(1, nodesVec, 0)
+	b.PrependUOffsetTSlot(2, buffersVec, 0)
+	batch := b.EndObject()
+
+	return aw.writeMessage(b, arrowHeaderRecordBatch, batch, body.Bytes())
+}
+
+// writeMessage finishes a Message flatbuffer with the given header and writes
+// it to the stream with its length prefix, padding and body.
+func (aw *arrowStreamWriter) writeMessage(b *flatbuffers.Builder, headerType byte, header flatbuffers.UOffsetT, body []byte) error {
+	b.StartObject(5)
+	b.PrependInt16Slot(0, arrowMetadataV4, 0)
+	b.PrependByteSlot(1, headerType, 0)
+	b.PrependUOffsetTSlot(2, header, 0)
+	b.PrependInt64Slot(3, int64(len(body)), 0)
+	b.Finish(b.EndObject())
+	meta := b.FinishedBytes()
+
+	// The length prefix and metadata together are padded to a multiple of 8 bytes.
+	size := len(meta)
+	if pad := (4 + size) % 8; pad != 0 {
+		size += 8 - pad
+	}
+	buf := make([]byte, 4+size)
+	binary.LittleEndian.PutUint32(buf, uint32(size))
+	copy(buf[4:], meta)
+	if _, err := aw.w.Write(buf); err != nil {
+		return err
+	}
+	_, err := aw.w.Write(body)
+	return err
+}
+
+// writeEOS writes the end-of-stream marker.
+func (aw *arrowStreamWriter) writeEOS() error {
+	_, err := aw.w.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+func arrowField(b *flatbuffers.Builder, c flux.ColMeta) flatbuffers.UOffsetT {
+	name := b.CreateString(c.Label)
+
+	var typeType byte
+	var typ flatbuffers.UOffsetT
+	switch c.Type {
+	case flux.TBool:
+		typeType = arrowTypeBool
+		b.StartObject(0)
+		typ = b.EndObject()
+	case flux.TInt, flux.TUInt:
+		typeType = arrowTypeInt
+		b.StartObject(2)
+		b.PrependInt32Slot(0, 64, 0)
+		b.PrependBoolSlot(1, c.Type == flux.TInt, false)
+		typ = b.EndObject()
+	case flux.TFloat:
+		typeType = arrowTypeFloatingPoint
+		b.StartObject(1)
+		b.PrependInt16Slot(0, arrowPrecisionDouble, 0)
+		typ = b.EndObject()
+	case flux.TTime:
+		typeType = arrowTypeTimestamp
+		tz := b.CreateString("UTC")
+		b.StartObject(2)
+		b.PrependInt16Slot(0, arrowTimeUnitNano, 0)
+		b.PrependUOffsetTSlot(1, tz, 0)
+		typ = b.EndObject()
+	default:
+		typeType = arrowTypeUtf8
+		b.StartObject(0)
+		typ = b.EndObject()
+	}
+
+	// Readers require the children vector even when it is empty.
+	b.StartVector(4, 0, 4)
+	children := b.EndVector(0)
+
+	b.StartObject(7)
+	b.PrependUOffsetTSlot(0, name, 0)
+	b.PrependBoolSlot(1, true, false)
+	b.PrependByteSlot(2, typeType, 0)
+	b.PrependUOffsetTSlot(3, typ, 0)
+	b.PrependUOffsetTSlot(5, children, 0)
+	return b.EndObject()
+}
+
+func arrowKeyValues(b *flatbuffers.Builder, m map[string]string) flatbuffers.UOffsetT {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]flatbuffers.UOffsetT, len(keys))
+	for i, k := range keys {
+		key, value := b.CreateString(k), b.CreateString(m[k])
+		b.StartObject(2)
+		b.PrependUOffsetTSlot(0, key, 0)
+		b.PrependUOffsetTSlot(1, value, 0)
+		kvs[i] = b.EndObject()
+	}
+	b.StartVector(4, len(kvs), 4)
+	for i := len(kvs) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(kvs[i])
+	}
+	return b.EndVector(len(kvs))
+}
+
+// arrowStructVector builds a vector of structs made of two int64s, such as FieldNode and Buffer.
+func arrowStructVector(b *flatbuffers.Builder, structs [][2]int64) flatbuffers.UOffsetT {
+	b.StartVector(16, len(structs), 8)
+	for i := len(structs) - 1; i >= 0; i-- {
+		b.Prep(8, 16)
+		b.PrependInt64(structs[i][1])
+		b.PrependInt64(structs[i][0])
+	}
+	return b.EndVector(len(structs))
+}
@@ -0,0 +1,43 @@
+// Package dialect contains the query result encodings offered by /api/v2/query
+// in addition to flux annotated CSV.
+package dialect
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/values"
+)
+
+// Dialect types and the media types they are served as.
+const (
+	JSONDialectType         = "json"
+	JSONMediaType           = "application/json"
+	ArrowDialectType        = "arrow"
+	ArrowMediaType          = "application/vnd.influx.arrow"
+	LineProtocolDialectType = "lp"
+	LineProtocolMediaType   = "text/vnd.influx.lp"
+)
+
+// keyValue converts a group key value into a JSON compatible value.
+func keyValue(v values.Value) (interface{}, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	switch typ := flux.ColumnType(v.Type()); typ {
+	case flux.TBool:
+		return v.Bool(), nil
+	case flux.TInt:
+		return v.Int(), nil
+	case flux.TUInt:
+		return v.UInt(), nil
+	case flux.TFloat:
+		return jsonFloat(v.Float()), nil
+	case flux.TString:
+		return v.Str(), nil
+	case flux.TTime:
+		return v.Time().Time().UTC(), nil
+	default:
+		return nil, fmt.Errorf("unsupported group key type %v", v.Type())
+	}
+}
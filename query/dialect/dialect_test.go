@@ -0,0 +1,170 @@
+package dialect_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/flatbuffers/go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/query/dialect"
+)
+
+func testResults() flux.ResultIterator {
+	return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+		Nm: "_result",
+		Tbls: []*executetest.Table{
+			{
+				KeyCols: []string{"_measurement", "_field", "host"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{values.Time(1000000000), "cpu", "usage", "a", 1.5},
+					{values.Time(2000000000), "cpu", "usage", "a", nil},
+				},
+			},
+			{
+				KeyCols: []string{"_measurement", "host"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "up", Type: flux.TBool},
+					{Label: "count", Type: flux.TInt},
+				},
+				Data: [][]interface{}{
+					{values.Time(1000000000), "mem", "b", true, int64(3)},
+				},
+			},
+		},
+	}})
+}
+
+func TestJSONDialect(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := new(dialect.JSONDialect).Encoder().Encode(&buf, testResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"results":[{"name":"_result","tables":[` +
+		`{"groupKey":{"_field":"usage","_measurement":"cpu","host":"a"},` +
+		`"columns":[{"label":"_time","type":"time","group":false},{"label":"_measurement","type":"string","group":true},{"label":"_field","type":"string","group":true},{"label":"host","type":"string","group":true},{"label":"_value","type":"float","group":false}],` +
+		`"data":[["1970-01-01T00:00:01Z","cpu","usage","a",1.5],["1970-01-01T00:00:02Z","cpu","usage","a",null]]}` + "\n" +
+		`,{"groupKey":{"_measurement":"mem","host":"b"},` +
+		`"columns":[{"label":"_time","type":"time","group":false},{"label":"_measurement","type":"string","group":true},{"label":"host","type":"string","group":true},{"label":"up","type":"bool","group":false},{"label":"count","type":"int","group":false}],` +
+		`"data":[["1970-01-01T00:00:01Z","mem","b",true,3]]}` + "\n" +
+		`]}]}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected json -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestJSONDialect_Error(t *testing.T) {
+	in := flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+		Nm:  "_result",
+		Err: errors.New("query failed"),
+	}})
+
+	var buf bytes.Buffer
+	if _, err := new(dialect.JSONDialect).Encoder().Encode(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `{"results":[{"name":"_result","tables":[],"error":"query failed"}]}`+"\n"; got != want {
+		t.Errorf("unexpected json: got %s, want %s", got, want)
+	}
+}
+
+func TestLineProtocolDialect(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := new(dialect.LineProtocolDialect).Encoder().Encode(&buf, testResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "cpu,host=a usage=1.5 1000000000\n" +
+		"mem,host=b count=3i,up=true 1000000000\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected line protocol -want/+got\n%s", cmp.Diff(want, got))
+	}
+
+	w := httptest.NewRecorder()
+	new(dialect.LineProtocolDialect).SetHeaders(w)
+	if got := w.Header().Get("Content-Type"); got != dialect.LineProtocolMediaType {
+		t.Errorf("unexpected content type %q", got)
+	}
+}
+
+func TestLineProtocolDialect_MissingMeasurement(t *testing.T) {
+	in := flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+		Nm: "_result",
+		Tbls: []*executetest.Table{{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{values.Time(1), 1.0},
+			},
+		}},
+	}})
+
+	var buf bytes.Buffer
+	if _, err := new(dialect.LineProtocolDialect).Encoder().Encode(&buf, in); err == nil {
+		t.Fatal("expected an encoding error for a table without _measurement")
+	}
+}
+
+func TestArrowDialect(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := new(dialect.ArrowDialect).Encoder().Encode(&buf, testResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Walk the encapsulated messages and record the header type of each,
+	// using 0 for the end-of-stream marker.
+	var got []byte
+	data := buf.Bytes()
+	for len(data) > 0 {
+		size := int(binary.LittleEndian.Uint32(data))
+		if size == 0 {
+			got = append(got, 0)
+			data = data[4:]
+			continue
+		}
+		if (4+size)%8 != 0 {
+			t.Fatalf("message metadata is not 8 byte aligned: %d", size)
+		}
+
+		meta := data[4 : 4+size]
+		msg := &flatbuffers.Table{Bytes: meta, Pos: flatbuffers.GetUOffsetT(meta)}
+		version := msg.GetInt16(msg.Pos + flatbuffers.UOffsetT(msg.Offset(4)))
+		if version != 3 {
+			t.Fatalf("unexpected metadata version %d", version)
+		}
+		got = append(got, msg.GetByte(msg.Pos+flatbuffers.UOffsetT(msg.Offset(6))))
+
+		var bodyLen int64
+		if o := msg.Offset(10); o != 0 {
+			bodyLen = msg.GetInt64(msg.Pos + flatbuffers.UOffsetT(o))
+		}
+		if bodyLen%8 != 0 {
+			t.Fatalf("message body is not 8 byte aligned: %d", bodyLen)
+		}
+		data = data[4+size+int(bodyLen):]
+	}
+
+	// Each table is a stream with a schema, one record batch and an end-of-stream marker.
+	want := []byte{1, 3, 0, 1, 3, 0}
+	if !cmp.Equal(got, want) {
+		t.Errorf("unexpected arrow messages -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
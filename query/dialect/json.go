@@ -0,0 +1,197 @@
+package dialect
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+)
+
+// JSONDialect encodes query results as a JSON document of tables with their group keys.
+//
+//	{"results":[{"name":"_result","tables":[{
+//	  "groupKey":{"_measurement":"cpu"},
+//	  "columns":[{"label":"_time","type":"time","group":false}, ...],
+//	  "data":[["2019-01-02T03:04:05Z", ...], ...]
+//	}]}]}
+//
+// Query errors are reported in an "error" property of the result or document.
+type JSONDialect struct{}
+
+// SetHeaders sets the content type of the response.
+func (d *JSONDialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+}
+
+// Encoder returns a JSON multi result encoder.
+func (d *JSONDialect) Encoder() flux.MultiResultEncoder {
+	return new(JSONMultiResultEncoder)
+}
+
+// DialectType returns the json dialect type.
+func (d *JSONDialect) DialectType() flux.DialectType {
+	return JSONDialectType
+}
+
+type jsonColumn struct {
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Group bool   `json:"group"`
+}
+
+type jsonTable struct {
+	GroupKey map[string]interface{} `json:"groupKey"`
+	Columns  []jsonColumn           `json:"columns"`
+	Data     [][]interface{}        `json:"data"`
+}
+
+// JSONMultiResultEncoder encodes results as a single JSON document.
+// Each table is written as soon as it is complete, so memory use is bounded
+// by the size of the largest table.
+type JSONMultiResultEncoder struct{}
+
+// Encode writes the results to w.
+func (e *JSONMultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	enc := json.NewEncoder(wc)
+
+	if _, err := io.WriteString(wc, `{"results":[`); err != nil {
+		return wc.Count(), err
+	}
+
+	for i := 0; results.More(); i++ {
+		res := results.Next()
+		if i > 0 {
+			if _, err := io.WriteString(wc, ","); err != nil {
+				return wc.Count(), err
+			}
+		}
+
+		name, _ := json.Marshal(res.Name())
+		if _, err := io.WriteString(wc, `{"name":`+string(name)+`,"tables":[`); err != nil {
+			return wc.Count(), err
+		}
+
+		n := 0
+		err := res.Tables().Do(func(tbl flux.Table) error {
+			t, err := newJSONTable(tbl)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				if _, err := io.WriteString(wc, ","); err != nil {
+					return err
+				}
+			}
+			n++
+			return enc.Encode(t)
+		})
+		if _, werr := io.WriteString(wc, "]"); werr != nil {
+			return wc.Count(), werr
+		}
+		if err != nil {
+			if err := writeJSONError(wc, err); err != nil {
+				return wc.Count(), err
+			}
+		}
+		if _, err := io.WriteString(wc, "}"); err != nil {
+			return wc.Count(), err
+		}
+	}
+
+	if _, err := io.WriteString(wc, "]"); err != nil {
+		return wc.Count(), err
+	}
+	if err := results.Err(); err != nil {
+		if err := writeJSONError(wc, err); err != nil {
+			return wc.Count(), err
+		}
+	}
+	_, err := io.WriteString(wc, "}\n")
+	return wc.Count(), err
+}
+
+func writeJSONError(w io.Writer, err error) error {
+	msg, _ := json.Marshal(err.Error())
+	_, werr := io.WriteString(w, `,"error":`+string(msg))
+	return werr
+}
+
+func newJSONTable(tbl flux.Table) (*jsonTable, error) {
+	key := tbl.Key()
+	t := &jsonTable{
+		GroupKey: make(map[string]interface{}, len(key.Cols())),
+		Columns:  make([]jsonColumn, len(tbl.Cols())),
+		Data:     [][]interface{}{},
+	}
+	for j, c := range key.Cols() {
+		v, err := keyValue(key.Value(j))
+		if err != nil {
+			return nil, err
+		}
+		t.GroupKey[c.Label] = v
+	}
+	for j, c := range tbl.Cols() {
+		t.Columns[j] = jsonColumn{
+			Label: c.Label,
+			Type:  c.Type.String(),
+			Group: key.HasCol(c.Label),
+		}
+	}
+
+	err := tbl.Do(func(cr flux.ColReader) error {
+		cols := cr.Cols()
+		for i := 0; i < cr.Len(); i++ {
+			row := make([]interface{}, len(cols))
+			for j, c := range cols {
+				row[j] = jsonValue(cr, c.Type, j, i)
+			}
+			t.Data = append(t.Data, row)
+		}
+		return nil
+	})
+	return t, err
+}
+
+// jsonValue returns the value at row i of column j, or nil when it is null.
+func jsonValue(cr flux.ColReader, typ flux.ColType, j, i int) interface{} {
+	switch typ {
+	case flux.TBool:
+		if a := cr.Bools(j); !a.IsNull(i) {
+			return a.Value(i)
+		}
+	case flux.TInt:
+		if a := cr.Ints(j); !a.IsNull(i) {
+			return a.Value(i)
+		}
+	case flux.TUInt:
+		if a := cr.UInts(j); !a.IsNull(i) {
+			return a.Value(i)
+		}
+	case flux.TFloat:
+		if a := cr.Floats(j); !a.IsNull(i) {
+			return jsonFloat(a.Value(i))
+		}
+	case flux.TString:
+		if a := cr.Strings(j); !a.IsNull(i) {
+			return a.ValueString(i)
+		}
+	case flux.TTime:
+		if a := cr.Times(j); !a.IsNull(i) {
+			return time.Unix(0, a.Value(i)).UTC()
+		}
+	}
+	return nil
+}
+
+// jsonFloat returns nil for floats that JSON cannot represent.
+func jsonFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return f
+}
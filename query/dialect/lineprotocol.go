@@ -0,0 +1,94 @@
+package dialect
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/influxdb/write"
+)
+
+// LineProtocolDialect encodes query results as line protocol so they can be
+// written back with /api/v2/write.
+//
+// Every table must have _measurement and _time columns. Rows of tables with
+// _field and _value columns become one field each, and the other string columns
+// become tags. Tables without a _field column are treated as pivoted: string
+// group key columns become tags and the remaining columns become fields.
+type LineProtocolDialect struct{}
+
+// SetHeaders sets the content type of the response.
+func (d *LineProtocolDialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", LineProtocolMediaType)
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+// Encoder returns a line protocol multi result encoder.
+func (d *LineProtocolDialect) Encoder() flux.MultiResultEncoder {
+	return &flux.DelimitedMultiResultEncoder{
+		Delimiter: []byte{},
+		Encoder:   new(LineProtocolResultEncoder),
+	}
+}
+
+// DialectType returns the line protocol dialect type.
+func (d *LineProtocolDialect) DialectType() flux.DialectType {
+	return LineProtocolDialectType
+}
+
+// LineProtocolResultEncoder encodes a single result as line protocol.
+type LineProtocolResultEncoder struct{}
+
+// Encode writes the points of every table in the result to w.
+func (e *LineProtocolResultEncoder) Encode(w io.Writer, result flux.Result) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	bw := bufio.NewWriter(wc)
+
+	err := result.Tables().Do(func(tbl flux.Table) error {
+		return tbl.Do(func(cr flux.ColReader) error {
+			points, err := write.ColReaderPoints(cr)
+			if err != nil {
+				return &encoderError{msg: fmt.Sprintf("result %q: %v", result.Name(), err)}
+			}
+			for _, p := range points {
+				if _, err := bw.WriteString(p.String()); err != nil {
+					return err
+				}
+				if err := bw.WriteByte('\n'); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if ferr := bw.Flush(); err == nil {
+		err = ferr
+	}
+	return wc.Count(), err
+}
+
+// EncodeError writes err as a line protocol comment, which writes ignore.
+func (e *LineProtocolResultEncoder) EncodeError(w io.Writer, err error) error {
+	msg := strings.Replace(err.Error(), "\n", " ", -1)
+	_, werr := fmt.Fprintf(w, "# error: %s\n", msg)
+	return werr
+}
+
+// encoderError is an error caused by results that cannot be encoded, as
+// opposed to an error from executing the query.
+type encoderError struct {
+	msg string
+}
+
+func (e *encoderError) Error() string {
+	return e.msg
+}
+
+// IsEncoderError reports that the error occurred while encoding.
+func (e *encoderError) IsEncoderError() bool {
+	return true
+}
@@ -0,0 +1,74 @@
+package macro
+
+import (
+	"sort"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+)
+
+// Option returns the statement setting the `v` option to the values of macros.
+func Option(values map[string]string) *ast.OptionStatement {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	obj := &ast.ObjectExpression{
+		Properties: make([]*ast.Property, 0, len(names)),
+	}
+	for _, name := range names {
+		obj.Properties = append(obj.Properties, &ast.Property{
+			Key:   &ast.Identifier{Name: name},
+			Value: &ast.StringLiteral{Value: values[name]},
+		})
+	}
+
+	return &ast.OptionStatement{
+		Assignment: &ast.VariableAssignment{
+			ID:   &ast.Identifier{Name: OptionName},
+			Init: obj,
+		},
+	}
+}
+
+// InjectAST adds the `v` option with the values of macros to the first file of
+// pkg, after its imports.
+func InjectAST(pkg *ast.Package, values map[string]string) error {
+	if len(pkg.Files) == 0 {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "query has no files",
+		}
+	}
+
+	file := pkg.Files[0]
+	body := make([]ast.Statement, 0, len(file.Body)+1)
+	body = append(body, Option(values))
+	file.Body = append(body, file.Body...)
+	return nil
+}
+
+// InjectQuery returns the Flux query q with the `v` option set to the values of
+// macros.  q is returned unchanged if there are no values.
+func InjectQuery(q string, values map[string]string) (string, error) {
+	if len(values) == 0 {
+		return q, nil
+	}
+
+	pkg := parser.ParseSource(q)
+	if ast.Check(pkg) > 0 {
+		return "", &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid query",
+			Err:  ast.GetError(pkg),
+		}
+	}
+	if err := InjectAST(pkg, values); err != nil {
+		return "", err
+	}
+	// Sources parse to a single file.
+	return ast.Format(pkg.Files[0]), nil
+}
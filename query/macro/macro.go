@@ -0,0 +1,329 @@
+// Package macro expands the macros of a query.  Macros are resolved to a single
+// value, which is injected in Flux queries as a property of the `v` record option.
+// Query macros are evaluated through a query service and can reference other
+// macros through the option.
+package macro
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+// OptionName is the name of the Flux option containing the values of the macros.
+const OptionName = "v"
+
+// Expander resolves the values of macros.
+type Expander struct {
+	MacroService platform.MacroService
+	QueryService query.QueryService
+}
+
+// NewExpander returns an Expander finding stored macros with macroService and
+// evaluating query macros with queryService.
+func NewExpander(macroService platform.MacroService, queryService query.QueryService) *Expander {
+	return &Expander{
+		MacroService: macroService,
+		QueryService: queryService,
+	}
+}
+
+// Values returns the values of macros indexed by name.  Macros with an ID and
+// without arguments are found in the macro service; their selected values are
+// replaced by the ones given if any.  Query macros are evaluated in the
+// organization with the authorization given, after the macros they reference.
+func (e *Expander) Values(ctx context.Context, orgID platform.ID, auth *platform.Authorization, macros []*platform.Macro) (map[string]string, error) {
+	byName := make(map[string]*platform.Macro, len(macros))
+	for _, m := range macros {
+		m, err := e.resolve(ctx, orgID, m)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byName[m.Name]; ok {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("macro %q is defined more than once", m.Name),
+			}
+		}
+		byName[m.Name] = m
+	}
+
+	order, err := sortMacros(byName)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(order))
+	for _, m := range order {
+		choices, err := e.choices(ctx, orgID, auth, m, values)
+		if err != nil {
+			return nil, err
+		}
+		values[m.Name] = selectValue(m.Selected, choices)
+	}
+	return values, nil
+}
+
+// resolve returns the stored macro m refers to, or m if it is defined inline.
+func (e *Expander) resolve(ctx context.Context, orgID platform.ID, m *platform.Macro) (*platform.Macro, error) {
+	if m.Arguments != nil {
+		if m.Name == "" {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "missing macro name",
+			}
+		}
+		return m, nil
+	}
+
+	if !m.ID.Valid() {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("macro %q requires either an id or arguments", m.Name),
+		}
+	}
+	if e.MacroService == nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "stored macros are not supported",
+		}
+	}
+
+	stored, err := e.MacroService.FindMacroByID(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Macros of other organizations are not disclosed.
+	if stored.OrganizationID != orgID {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrMacroNotFound,
+		}
+	}
+
+	resolved := *stored
+	if len(m.Selected) > 0 {
+		resolved.Selected = m.Selected
+	}
+	return &resolved, nil
+}
+
+// choices returns the values a macro can take.  values contains the values of
+// the macros evaluated before m.
+func (e *Expander) choices(ctx context.Context, orgID platform.ID, auth *platform.Authorization, m *platform.Macro, values map[string]string) ([]string, error) {
+	switch args := m.Arguments.Values.(type) {
+	case platform.MacroConstantValues:
+		return args, nil
+	case platform.MacroMapValues:
+		keys := make([]string, 0, len(args))
+		for k := range args {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		// The keys are selected but the values are injected.
+		if key := selectValue(m.Selected, keys); key != "" {
+			return []string{args[key]}, nil
+		}
+		return nil, nil
+	case platform.MacroQueryValues:
+		return e.queryChoices(ctx, orgID, auth, m.Name, args, values)
+	default:
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("macro %q has invalid arguments of type %q", m.Name, m.Arguments.Type),
+		}
+	}
+}
+
+// queryChoices evaluates a query macro and returns the values of its _value column.
+func (e *Expander) queryChoices(ctx context.Context, orgID platform.ID, auth *platform.Authorization, name string, args platform.MacroQueryValues, values map[string]string) ([]string, error) {
+	if args.Language != "flux" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("macro %q has unsupported query language %q", name, args.Language),
+		}
+	}
+	if e.QueryService == nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "query macros are not supported",
+		}
+	}
+
+	script, err := InjectQuery(args.Query, values)
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := e.QueryService.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("error evaluating macro %q", name),
+			Err:  err,
+		}
+	}
+	defer it.Release()
+
+	var choices []string
+	seen := make(map[string]bool)
+	for it.More() {
+		err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				for _, v := range readValues(cr) {
+					if !seen[v] {
+						seen[v] = true
+						choices = append(choices, v)
+					}
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("error evaluating macro %q", name),
+			Err:  err,
+		}
+	}
+	return choices, nil
+}
+
+// readValues returns the values of the _value column of cr as strings.
+func readValues(cr flux.ColReader) []string {
+	var vs []string
+	for j, c := range cr.Cols() {
+		if c.Label != "_value" {
+			continue
+		}
+		for i := 0; i < cr.Len(); i++ {
+			switch c.Type {
+			case flux.TString:
+				vs = append(vs, cr.Strings(j).ValueString(i))
+			case flux.TInt:
+				vs = append(vs, fmt.Sprint(cr.Ints(j).Value(i)))
+			case flux.TUInt:
+				vs = append(vs, fmt.Sprint(cr.UInts(j).Value(i)))
+			case flux.TFloat:
+				vs = append(vs, fmt.Sprint(cr.Floats(j).Value(i)))
+			case flux.TBool:
+				vs = append(vs, fmt.Sprint(cr.Bools(j).Value(i)))
+			}
+		}
+	}
+	return vs
+}
+
+// selectValue returns the first selected value that is a choice, or the first
+// choice if none is.
+func selectValue(selected, choices []string) string {
+	for _, s := range selected {
+		for _, c := range choices {
+			if s == c {
+				return s
+			}
+		}
+	}
+	if len(choices) == 0 {
+		return ""
+	}
+	return choices[0]
+}
+
+// sortMacros returns the macros ordered so that query macros come after the
+// macros they reference.  An error is returned if references form a cycle.
+func sortMacros(macros map[string]*platform.Macro) ([]*platform.Macro, error) {
+	names := make([]string, 0, len(macros))
+	for name := range macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(macros))
+	order := make([]*platform.Macro, 0, len(macros))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("macro cycle: %s", strings.Join(append(path, name), " -> ")),
+			}
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		m := macros[name]
+		for _, dep := range dependencies(m) {
+			if _, ok := macros[dep]; !ok {
+				continue
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, m)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// dependencies returns the sorted names of the macros referenced by the query of m.
+func dependencies(m *platform.Macro) []string {
+	args, ok := m.Arguments.Values.(platform.MacroQueryValues)
+	if !ok || args.Language != "flux" {
+		return nil
+	}
+	return References(args.Query)
+}
+
+// References returns the sorted names of the macros referenced by the Flux query q.
+func References(q string) []string {
+	set := make(map[string]bool)
+	ast.Walk(ast.CreateVisitor(func(node ast.Node) {
+		expr, ok := node.(*ast.MemberExpression)
+		if !ok {
+			return
+		}
+		if id, ok := expr.Object.(*ast.Identifier); ok && id.Name == OptionName {
+			set[expr.Property.Key()] = true
+		}
+	}), parser.ParseSource(q))
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,236 @@
+package macro_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/macro"
+	querymock "github.com/influxdata/influxdb/query/mock"
+)
+
+func valuesTable(values ...string) *executetest.Table {
+	rows := make([][]interface{}, len(values))
+	for i, v := range values {
+		rows[i] = []interface{}{v}
+	}
+	return &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_value", Type: flux.TString},
+		},
+		Data: rows,
+	}
+}
+
+func queryMacro(name, q string, selected ...string) *platform.Macro {
+	return &platform.Macro{
+		Name:     name,
+		Selected: selected,
+		Arguments: &platform.MacroArguments{
+			Type:   "query",
+			Values: platform.MacroQueryValues{Query: q, Language: "flux"},
+		},
+	}
+}
+
+func TestExpander_Values(t *testing.T) {
+	stored := &platform.Macro{
+		ID:             1,
+		OrganizationID: 10,
+		Name:           "region",
+		Selected:       []string{"us"},
+		Arguments: &platform.MacroArguments{
+			Type:   "map",
+			Values: platform.MacroMapValues{"eu": "europe", "us": "america"},
+		},
+	}
+	macroService := &mock.MacroService{
+		FindMacroByIDF: func(ctx context.Context, id platform.ID) (*platform.Macro, error) {
+			if id != stored.ID {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrMacroNotFound}
+			}
+			return stored, nil
+		},
+	}
+
+	// The query service returns the buckets and the hosts of the bucket set in
+	// the v option.
+	var queries []string
+	queryService := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != 10 {
+				t.Errorf("unexpected organization %s", req.OrganizationID)
+			}
+			q := req.Compiler.(lang.FluxCompiler).Query
+			queries = append(queries, q)
+
+			var table *executetest.Table
+			switch {
+			case strings.Contains(q, "buckets()"):
+				table = valuesTable("a", "b")
+			case strings.Contains(q, `bucket: "b"`):
+				table = valuesTable("b1", "b2")
+			default:
+				table = valuesTable()
+			}
+			return flux.NewSliceResultIterator([]flux.Result{executetest.NewResult([]*executetest.Table{table})}), nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		macros      []*platform.Macro
+		want        map[string]string
+		wantQueries int
+		wantCode    string
+	}{
+		{
+			name: "constant",
+			macros: []*platform.Macro{
+				{
+					Name:     "a",
+					Selected: []string{"y"},
+					Arguments: &platform.MacroArguments{
+						Type:   "constant",
+						Values: platform.MacroConstantValues{"x", "y"},
+					},
+				},
+				{
+					Name:     "b",
+					Selected: []string{"z"},
+					Arguments: &platform.MacroArguments{
+						Type:   "constant",
+						Values: platform.MacroConstantValues{"x", "y"},
+					},
+				},
+			},
+			want: map[string]string{"a": "y", "b": "x"},
+		},
+		{
+			name:   "stored",
+			macros: []*platform.Macro{{ID: 1}},
+			want:   map[string]string{"region": "america"},
+		},
+		{
+			name:   "stored with selected",
+			macros: []*platform.Macro{{ID: 1, Selected: []string{"eu"}}},
+			want:   map[string]string{"region": "europe"},
+		},
+		{
+			name:     "stored not found",
+			macros:   []*platform.Macro{{ID: 2}},
+			wantCode: platform.ENotFound,
+		},
+		{
+			name: "query dependencies",
+			macros: []*platform.Macro{
+				queryMacro("host", `from(bucket: v.bucket) |> range(start: -1h)`),
+				queryMacro("bucket", `buckets()`, "b"),
+			},
+			want:        map[string]string{"bucket": "b", "host": "b1"},
+			wantQueries: 2,
+		},
+		{
+			name: "query cycle",
+			macros: []*platform.Macro{
+				queryMacro("a", `from(bucket: v.b)`),
+				queryMacro("b", `from(bucket: v.a)`),
+			},
+			wantCode: platform.EInvalid,
+		},
+		{
+			name: "duplicate names",
+			macros: []*platform.Macro{
+				queryMacro("a", `buckets()`),
+				queryMacro("a", `buckets()`),
+			},
+			wantCode: platform.EInvalid,
+		},
+		{
+			name: "influxql query",
+			macros: []*platform.Macro{
+				{
+					Name: "a",
+					Arguments: &platform.MacroArguments{
+						Type:   "query",
+						Values: platform.MacroQueryValues{Query: "SHOW DATABASES", Language: "influxql"},
+					},
+				},
+			},
+			wantCode: platform.EInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries = nil
+			e := macro.NewExpander(macroService, queryService)
+			got, err := e.Values(context.Background(), 10, nil, tt.macros)
+			if tt.wantCode != "" {
+				if code := platform.ErrorCode(err); code != tt.wantCode {
+					t.Fatalf("got error %v, want code %q", err, tt.wantCode)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got values %v, want %v", got, tt.want)
+			}
+			if len(queries) != tt.wantQueries {
+				t.Errorf("got %d queries, want %d: %v", len(queries), tt.wantQueries, queries)
+			}
+		})
+	}
+}
+
+func TestExpander_Values_OtherOrganization(t *testing.T) {
+	macroService := &mock.MacroService{
+		FindMacroByIDF: func(ctx context.Context, id platform.ID) (*platform.Macro, error) {
+			return &platform.Macro{
+				ID:             id,
+				OrganizationID: 20,
+				Name:           "a",
+				Arguments: &platform.MacroArguments{
+					Type:   "constant",
+					Values: platform.MacroConstantValues{"x"},
+				},
+			}, nil
+		},
+	}
+
+	e := macro.NewExpander(macroService, nil)
+	if _, err := e.Values(context.Background(), 10, nil, []*platform.Macro{{ID: 1}}); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInjectQuery(t *testing.T) {
+	got, err := macro.InjectQuery(`import "strings"
+from(bucket: v.bucket) |> range(start: -1h)`, map[string]string{"bucket": `my"bucket`, "a": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `import "strings"
+
+option v = {a: "1", bucket: "my\"bucket"}
+
+from(bucket: v.bucket)
+	|> range(start: -1h)`
+	if got != want {
+		t.Fatalf("got query:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := macro.InjectQuery(`@`, map[string]string{"a": "1"}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
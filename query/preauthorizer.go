@@ -13,7 +13,9 @@ import (
 // callers to fail early for operations that are not allowed.  However, it's still possible
 // for authorization to be denied at runtime even if this check passes.
 type PreAuthorizer interface {
-	PreAuthorize(ctx context.Context, spec *flux.Spec, auth platform.Authorizer) error
+	// PreAuthorize checks the buckets accessed by the spec when it runs in
+	// the organization orgID.
+	PreAuthorize(ctx context.Context, spec *flux.Spec, auth platform.Authorizer, orgID platform.ID) error
 }
 
 // NewPreAuthorizer creates a new PreAuthorizer
@@ -27,12 +29,18 @@ type preAuthorizer struct {
 
 // PreAuthorize finds all the buckets read and written by the given spec, and ensures that execution is allowed
 // given the Authorizer.  Returns nil on success, and an error with an appropriate message otherwise.
-func (a *preAuthorizer) PreAuthorize(ctx context.Context, spec *flux.Spec, auth platform.Authorizer) error {
+func (a *preAuthorizer) PreAuthorize(ctx context.Context, spec *flux.Spec, auth platform.Authorizer, orgID platform.ID) error {
 	readBuckets, writeBuckets, err := BucketsAccessed(spec)
 
 	if err != nil {
 		return errors.Wrap(err, "could not retrieve buckets for query.Spec")
 	}
+	for i := range readBuckets {
+		readBuckets[i] = inOrganization(readBuckets[i], orgID)
+	}
+	for i := range writeBuckets {
+		writeBuckets[i] = inOrganization(writeBuckets[i], orgID)
+	}
 
 	for _, readBucketItem := range readBuckets {
 		bucket, err := a.bucketService.FindBucket(ctx, readBucketItem)
@@ -50,7 +58,10 @@ func (a *preAuthorizer) PreAuthorize(ctx context.Context, spec *flux.Spec, auth
 		}
 
 		if !auth.Allowed(*reqPerm) {
-			return errors.New("no read permission for bucket: \"" + bucket.Name + "\"")
+			return &platform.Error{
+				Code: platform.EUnauthorized,
+				Msg:  "no read permission for bucket: \"" + bucket.Name + "\"",
+			}
 		}
 	}
 
@@ -65,9 +76,21 @@ func (a *preAuthorizer) PreAuthorize(ctx context.Context, spec *flux.Spec, auth
 			return errors.Wrapf(err, "could not create write bucket permission")
 		}
 		if !auth.Allowed(*reqPerm) {
-			return errors.New("no write permission for bucket: \"" + bucket.Name + "\"")
+			return &platform.Error{
+				Code: platform.EUnauthorized,
+				Msg:  "no write permission for bucket: \"" + bucket.Name + "\"",
+			}
 		}
 	}
 
 	return nil
 }
+
+// inOrganization scopes a bucket filter without an organization to orgID,
+// which is where the query looks the bucket up when it runs.
+func inOrganization(bf platform.BucketFilter, orgID platform.ID) platform.BucketFilter {
+	if bf.OrganizationID == nil && (bf.Organization == nil || *bf.Organization == "") {
+		bf.OrganizationID, bf.Organization = &orgID, nil
+	}
+	return bf
+}
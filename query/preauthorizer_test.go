@@ -17,7 +17,8 @@ import (
 func newBucketServiceWithOneBucket(bucket platform.Bucket) platform.BucketService {
 	bs := mock.NewBucketService()
 	bs.FindBucketFn = func(ctx context.Context, bucketFilter platform.BucketFilter) (*platform.Bucket, error) {
-		if *bucketFilter.Name == bucket.Name {
+		if bucketFilter.OrganizationID != nil && *bucketFilter.OrganizationID == bucket.OrganizationID &&
+			bucketFilter.Name != nil && *bucketFilter.Name == bucket.Name {
 			return &bucket, nil
 		}
 
@@ -28,9 +29,6 @@ func newBucketServiceWithOneBucket(bucket platform.Bucket) platform.BucketServic
 }
 
 func TestPreAuthorizer_PreAuthorize(t *testing.T) {
-	// TODO(adam) add this test back when BucketsAccessed is restored for the from function
-	// https://github.com/influxdata/flux/issues/114
-	t.Skip("https://github.com/influxdata/flux/issues/114")
 	ctx := context.Background()
 	now := time.Now().UTC()
 
@@ -42,12 +40,13 @@ func TestPreAuthorizer_PreAuthorize(t *testing.T) {
 
 	// Try to pre-authorize with bucket service with no buckets
 	// and no authorization
+	orgID := platform.ID(1)
 	auth := &platform.Authorization{Status: platform.Active}
 	emptyBucketService := mock.NewBucketService()
 	preAuthorizer := query.NewPreAuthorizer(emptyBucketService)
 
-	err = preAuthorizer.PreAuthorize(ctx, spec, auth)
-	if diagnostic := cmp.Diff("Bucket service returned nil bucket", err.Error()); diagnostic != "" {
+	err = preAuthorizer.PreAuthorize(ctx, spec, auth, orgID)
+	if diagnostic := cmp.Diff("bucket service returned nil bucket", err.Error()); diagnostic != "" {
 		t.Errorf("Authorize message mismatch: -want/+got:\n%v", diagnostic)
 	}
 
@@ -55,17 +54,25 @@ func TestPreAuthorizer_PreAuthorize(t *testing.T) {
 	// (still no authorization)
 	id, _ := platform.IDFromString("deadbeefdeadbeef")
 	bucketService := newBucketServiceWithOneBucket(platform.Bucket{
-		Name: "my_bucket",
-		ID:   *id,
+		Name:           "my_bucket",
+		ID:             *id,
+		OrganizationID: orgID,
 	})
 
 	preAuthorizer = query.NewPreAuthorizer(bucketService)
-	err = preAuthorizer.PreAuthorize(ctx, spec, auth)
-	if diagnostic := cmp.Diff(`No read permission for bucket: "my_bucket"`, err.Error()); diagnostic != "" {
+	err = preAuthorizer.PreAuthorize(ctx, spec, auth, orgID)
+	if diagnostic := cmp.Diff(`no read permission for bucket: "my_bucket"`, platform.ErrorMessage(err)); diagnostic != "" {
 		t.Errorf("Authorize message mismatch: -want/+got:\n%v", diagnostic)
 	}
+	if code := platform.ErrorCode(err); code != platform.EUnauthorized {
+		t.Errorf("expected an unauthorized error, got %q", code)
+	}
+
+	// The bucket is looked up in the organization running the query.
+	if err := preAuthorizer.PreAuthorize(ctx, spec, auth, platform.ID(2)); err == nil {
+		t.Error("expected the bucket of another organization not to be found")
+	}
 
-	orgID := platform.ID(1)
 	p, err := platform.NewPermissionAtID(*id, platform.ReadAction, platform.BucketsResourceType, orgID)
 	if err != nil {
 		t.Fatalf("Error creating read bucket permission query: %v", err)
@@ -76,7 +83,7 @@ func TestPreAuthorizer_PreAuthorize(t *testing.T) {
 		Permissions: []platform.Permission{*p},
 	}
 
-	err = preAuthorizer.PreAuthorize(ctx, spec, auth)
+	err = preAuthorizer.PreAuthorize(ctx, spec, auth, orgID)
 	if err != nil {
 		t.Errorf("Expected successful authorization, but got error: \"%v\"", err.Error())
 	}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/stdlib/influxdata/influxdb"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -13,11 +14,14 @@ type BucketAwareOperationSpec interface {
 // BucketsAccessed returns the set of buckets read and written by a query spec
 func BucketsAccessed(q *flux.Spec) (readBuckets, writeBuckets []platform.BucketFilter, err error) {
 	err = q.Walk(func(o *flux.Operation) error {
-		bucketAwareOpSpec, ok := o.Spec.(BucketAwareOperationSpec)
-		if ok {
-			opBucketsRead, opBucketsWritten := bucketAwareOpSpec.BucketsAccessed()
+		switch spec := o.Spec.(type) {
+		case BucketAwareOperationSpec:
+			opBucketsRead, opBucketsWritten := spec.BucketsAccessed()
 			readBuckets = append(readBuckets, opBucketsRead...)
 			writeBuckets = append(writeBuckets, opBucketsWritten...)
+		case *influxdb.FromOpSpec:
+			// from() is defined by flux, which cannot depend on the platform.
+			readBuckets = append(readBuckets, fromBucketAccessed(spec))
 		}
 		return nil
 	})
@@ -28,3 +32,17 @@ func BucketsAccessed(q *flux.Spec) (readBuckets, writeBuckets []platform.BucketF
 
 	return readBuckets, writeBuckets, nil
 }
+
+// fromBucketAccessed returns the bucket read by from(), which always reads
+// a bucket of the organization running the query.
+func fromBucketAccessed(spec *influxdb.FromOpSpec) platform.BucketFilter {
+	bf := platform.BucketFilter{Name: &spec.Bucket}
+	// An invalid ID is looked up as a name, so that the filter is never empty.
+	if spec.BucketID != "" {
+		bf.Name = &spec.BucketID
+		if id, err := platform.IDFromString(spec.BucketID); err == nil {
+			bf.ID, bf.Name = id, nil
+		}
+	}
+	return bf
+}
@@ -15,9 +15,6 @@ func init() {
 	execute.RegisterSource(influxdb.FromKind, createFromSource)
 }
 
-// TODO(adam): implement a BucketsAccessed that doesn't depend on flux.
-// https://github.com/influxdata/flux/issues/114
-
 func createFromSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec := prSpec.(*influxdb.FromProcedureSpec)
 	var w execute.Window
@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/stdlib/universe"
+)
+
+func init() {
+	plan.RegisterPhysicalRules(
+		MergeRangeRule{Kind: TagKeysKind},
+		MergeRangeRule{Kind: TagValuesKind},
+	)
+}
+
+// boundedProcedureSpec is a procedure spec whose time range is set by a
+// following range.
+type boundedProcedureSpec interface {
+	plan.PhysicalProcedureSpec
+	SetBounds(bounds flux.Bounds)
+}
+
+// MergeRangeRule pushes a `range` into a schema function of Kind.
+type MergeRangeRule struct {
+	Kind plan.ProcedureKind
+}
+
+// Name returns the name of the rule
+func (rule MergeRangeRule) Name() string {
+	return "Merge" + string(rule.Kind) + "RangeRule"
+}
+
+// Pattern returns the pattern that matches `<kind> -> range`
+func (rule MergeRangeRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.RangeKind, plan.Pat(rule.Kind))
+}
+
+// Rewrite sets the bounds of the schema function to those of the range and
+// merges the nodes.
+func (rule MergeRangeRule) Rewrite(node plan.PlanNode) (plan.PlanNode, bool, error) {
+	pred := node.Predecessors()[0]
+	rangeSpec := node.ProcedureSpec().(*universe.RangeProcedureSpec)
+
+	spec := pred.ProcedureSpec().Copy().(boundedProcedureSpec)
+	spec.SetBounds(rangeSpec.Bounds)
+
+	merged, err := plan.MergePhysicalPlanNodes(node, pred, spec)
+	if err != nil {
+		return nil, false, err
+	}
+	return merged, true, nil
+}
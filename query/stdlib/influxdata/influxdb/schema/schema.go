@@ -0,0 +1,238 @@
+// Package schema implements the Flux functions that explore the schema of a
+// bucket, such as its measurements and its tag keys and values.  They are
+// answered from the storage index instead of reading series data.
+//
+// The time range of the series is given by a range that directly follows the
+// function, which is merged into it:
+//
+//	import "influxdata/influxdb/schema"
+//	schema.tagValues(bucket: "telegraf", tag: "host", predicate: (r) => r._measurement == "cpu")
+//	    |> range(start: -1h)
+package schema
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/pkg/errors"
+)
+
+// PackagePath is the import path of the package in Flux.
+const PackagePath = "influxdata/influxdb/schema"
+
+const pkgSource = `package schema
+
+builtin tagKeys
+builtin tagValues
+builtin measurements
+`
+
+// DefaultStart is the start of the time range of a schema function that is
+// not followed by a range.
+var DefaultStart = flux.Time{
+	IsRelative: true,
+	Relative:   -30 * 24 * time.Hour,
+}
+
+func init() {
+	pkg := parser.ParseSource(pkgSource)
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+}
+
+// signature returns the signature of a schema function with the additional
+// parameters.
+func signature(params map[string]semantic.PolyType, required ...string) semantic.FunctionPolySignature {
+	parameters := map[string]semantic.PolyType{
+		"bucket":   semantic.String,
+		"bucketID": semantic.String,
+		"predicate": semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+			Parameters: map[string]semantic.PolyType{
+				"r": semantic.NewEmptyObjectPolyType(),
+			},
+			Required: semantic.LabelSet{"r"},
+			Return:   semantic.Bool,
+		}),
+	}
+	for k, v := range params {
+		parameters[k] = v
+	}
+	return semantic.FunctionPolySignature{
+		Parameters: parameters,
+		Required:   semantic.LabelSet(required),
+		Return:     flux.TableObjectType,
+	}
+}
+
+// SeriesSpec specifies the series of a bucket that a schema function reads.
+type SeriesSpec struct {
+	Bucket    string                       `json:"bucket,omitempty"`
+	BucketID  string                       `json:"bucketID,omitempty"`
+	Predicate *semantic.FunctionExpression `json:"predicate,omitempty"`
+}
+
+// ReadArgs loads the series of the spec from the arguments of a function.
+func (s *SeriesSpec) ReadArgs(args flux.Arguments) error {
+	if bucket, ok, err := args.GetString("bucket"); err != nil {
+		return err
+	} else if ok {
+		s.Bucket = bucket
+	}
+
+	if bucketID, ok, err := args.GetString("bucketID"); err != nil {
+		return err
+	} else if ok {
+		s.BucketID = bucketID
+	}
+
+	if s.Bucket == "" && s.BucketID == "" {
+		return errors.New("must specify one of bucket or bucketID")
+	}
+	if s.Bucket != "" && s.BucketID != "" {
+		return errors.New("must specify only one of bucket or bucketID")
+	}
+
+	if f, ok, err := args.GetFunction("predicate"); err != nil {
+		return err
+	} else if ok {
+		fn, err := interpreter.ResolveFunction(f)
+		if err != nil {
+			return err
+		}
+		s.Predicate = fn
+	}
+	return nil
+}
+
+// BucketsAccessed returns the bucket read by the spec.
+func (s *SeriesSpec) BucketsAccessed() (readBuckets, writeBuckets []platform.BucketFilter) {
+	bf := platform.BucketFilter{}
+	if s.Bucket != "" {
+		bf.Name = &s.Bucket
+	}
+	if s.BucketID != "" {
+		id, err := platform.IDFromString(s.BucketID)
+		if err == nil {
+			bf.ID = id
+		}
+	}
+	readBuckets = append(readBuckets, bf)
+	return readBuckets, writeBuckets
+}
+
+// Copy returns a deep copy of the spec.
+func (s SeriesSpec) Copy() SeriesSpec {
+	if s.Predicate != nil {
+		s.Predicate = s.Predicate.Copy().(*semantic.FunctionExpression)
+	}
+	return s
+}
+
+// defaultBounds returns the time range of a schema function that is not
+// followed by a range.
+func defaultBounds(now time.Time) flux.Bounds {
+	return flux.Bounds{
+		Start: DefaultStart,
+		Stop:  flux.Now,
+		Now:   now,
+	}
+}
+
+// Dependencies are the services used by the schema functions.
+type Dependencies struct {
+	Reader       influxdb.Reader
+	BucketLookup influxdb.BucketLookup
+}
+
+func (d Dependencies) Validate() error {
+	if d.Reader == nil {
+		return errors.New("missing reader dependency")
+	}
+	if d.BucketLookup == nil {
+		return errors.New("missing bucket lookup dependency")
+	}
+	return nil
+}
+
+// InjectDependencies adds the dependencies of the schema functions to
+// depsMap.
+func InjectDependencies(depsMap execute.Dependencies, deps Dependencies) error {
+	if err := deps.Validate(); err != nil {
+		return err
+	}
+	depsMap[TagKeysKind] = deps
+	depsMap[TagValuesKind] = deps
+	return nil
+}
+
+// lookupBucket returns the organization and the ID of the bucket of spec.
+func lookupBucket(ctx context.Context, deps Dependencies, spec SeriesSpec) (orgID, bucketID platform.ID, err error) {
+	req := query.RequestFromContext(ctx)
+	if req == nil {
+		return 0, 0, errors.New("missing request on context")
+	}
+	orgID = req.OrganizationID
+
+	switch {
+	case spec.Bucket != "":
+		b, ok := deps.BucketLookup.Lookup(orgID, spec.Bucket)
+		if !ok {
+			return 0, 0, fmt.Errorf("could not find bucket %q", spec.Bucket)
+		}
+		bucketID = b
+	case len(spec.BucketID) != 0:
+		if err := bucketID.DecodeFromString(spec.BucketID); err != nil {
+			return 0, 0, err
+		}
+	}
+	return orgID, bucketID, nil
+}
+
+// stringsDecoder decodes the strings read by a schema function into a table
+// with a single _value column.
+type stringsDecoder struct {
+	read   func() (cursors.StringIterator, error)
+	values cursors.StringIterator
+	alloc  *memory.Allocator
+}
+
+func (d *stringsDecoder) Connect() error {
+	return nil
+}
+
+func (d *stringsDecoder) Fetch() (bool, error) {
+	itr, err := d.read()
+	if err != nil {
+		return false, err
+	}
+	d.values = itr
+	return false, nil
+}
+
+func (d *stringsDecoder) Decode() (flux.Table, error) {
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), d.alloc)
+	if _, err := b.AddCol(flux.ColMeta{
+		Label: execute.DefaultValueColLabel,
+		Type:  flux.TString,
+	}); err != nil {
+		return nil, err
+	}
+
+	for d.values.Next() {
+		if err := b.AppendString(0, d.values.Value()); err != nil {
+			return nil, err
+		}
+	}
+	return b.Table()
+}
@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/stdlib/inputs"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+const TagKeysKind = "tagKeys"
+
+type TagKeysOpSpec struct {
+	SeriesSpec
+}
+
+func init() {
+	flux.RegisterPackageValue(PackagePath, TagKeysKind, flux.FunctionValue(TagKeysKind, createTagKeysOpSpec, signature(nil)))
+	flux.RegisterOpSpec(TagKeysKind, newTagKeysOp)
+	plan.RegisterProcedureSpec(TagKeysKind, newTagKeysProcedure, TagKeysKind)
+	execute.RegisterSource(TagKeysKind, createTagKeysSource)
+}
+
+func createTagKeysOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(TagKeysOpSpec)
+	if err := spec.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func newTagKeysOp() flux.OperationSpec {
+	return new(TagKeysOpSpec)
+}
+
+func (s *TagKeysOpSpec) Kind() flux.OperationKind {
+	return TagKeysKind
+}
+
+type TagKeysProcedureSpec struct {
+	plan.DefaultCost
+	Series SeriesSpec
+
+	// Bounds is the time range of the series; it is set by a following
+	// range.
+	Bounds flux.Bounds
+}
+
+func newTagKeysProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*TagKeysOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+
+	return &TagKeysProcedureSpec{
+		Series: spec.SeriesSpec.Copy(),
+		Bounds: defaultBounds(pa.Now()),
+	}, nil
+}
+
+func (s *TagKeysProcedureSpec) Kind() plan.ProcedureKind {
+	return TagKeysKind
+}
+
+func (s *TagKeysProcedureSpec) SetBounds(bounds flux.Bounds) {
+	s.Bounds = bounds
+}
+
+func (s *TagKeysProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	ns.Series = s.Series.Copy()
+	return &ns
+}
+
+func createTagKeysSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := prSpec.(*TagKeysProcedureSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", prSpec)
+	}
+
+	deps := a.Dependencies()[TagKeysKind].(Dependencies)
+	orgID, bucketID, err := lookupBucket(a.Context(), deps, spec.Series)
+	if err != nil {
+		return nil, err
+	}
+
+	start := execute.Time(spec.Bounds.Start.Time(spec.Bounds.Now).UnixNano())
+	stop := execute.Time(spec.Bounds.Stop.Time(spec.Bounds.Now).UnixNano())
+	d := &stringsDecoder{
+		read: func() (cursors.StringIterator, error) {
+			return deps.Reader.ReadTagKeys(a.Context(), influxdb.TagKeysSpec{
+				OrganizationID: orgID,
+				BucketID:       bucketID,
+				Predicate:      spec.Series.Predicate,
+			}, start, stop)
+		},
+		alloc: a.Allocator(),
+	}
+	return inputs.CreateSourceFromDecoder(d, dsid, a)
+}
@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/inputs"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+const (
+	TagValuesKind    = "tagValues"
+	MeasurementsKind = "measurements"
+)
+
+// measurementKey is the tag of the measurement of a series.
+const measurementKey = "_measurement"
+
+type TagValuesOpSpec struct {
+	SeriesSpec
+	Tag string `json:"tag"`
+}
+
+func init() {
+	tagValuesSignature := signature(map[string]semantic.PolyType{"tag": semantic.String}, "tag")
+	flux.RegisterPackageValue(PackagePath, TagValuesKind, flux.FunctionValue(TagValuesKind, createTagValuesOpSpec, tagValuesSignature))
+	flux.RegisterPackageValue(PackagePath, MeasurementsKind, flux.FunctionValue(TagValuesKind, createMeasurementsOpSpec, signature(nil)))
+	flux.RegisterOpSpec(TagValuesKind, newTagValuesOp)
+	plan.RegisterProcedureSpec(TagValuesKind, newTagValuesProcedure, TagValuesKind)
+	execute.RegisterSource(TagValuesKind, createTagValuesSource)
+}
+
+func createTagValuesOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(TagValuesOpSpec)
+	if err := spec.ReadArgs(args); err != nil {
+		return nil, err
+	}
+
+	tag, err := args.GetRequiredString("tag")
+	if err != nil {
+		return nil, err
+	}
+	if tag == "" {
+		return nil, errors.New("tag must not be empty")
+	}
+	spec.Tag = tag
+	return spec, nil
+}
+
+// createMeasurementsOpSpec creates the spec of measurements(), which reads
+// the values of the measurement tag.
+func createMeasurementsOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := &TagValuesOpSpec{Tag: measurementKey}
+	if err := spec.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func newTagValuesOp() flux.OperationSpec {
+	return new(TagValuesOpSpec)
+}
+
+func (s *TagValuesOpSpec) Kind() flux.OperationKind {
+	return TagValuesKind
+}
+
+type TagValuesProcedureSpec struct {
+	plan.DefaultCost
+	Series SeriesSpec
+
+	// Bounds is the time range of the series; it is set by a following
+	// range.
+	Bounds flux.Bounds
+	Tag    string
+}
+
+func newTagValuesProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*TagValuesOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+
+	return &TagValuesProcedureSpec{
+		Series: spec.SeriesSpec.Copy(),
+		Bounds: defaultBounds(pa.Now()),
+		Tag:    spec.Tag,
+	}, nil
+}
+
+func (s *TagValuesProcedureSpec) Kind() plan.ProcedureKind {
+	return TagValuesKind
+}
+
+func (s *TagValuesProcedureSpec) SetBounds(bounds flux.Bounds) {
+	s.Bounds = bounds
+}
+
+func (s *TagValuesProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	ns.Series = s.Series.Copy()
+	return &ns
+}
+
+func createTagValuesSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := prSpec.(*TagValuesProcedureSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", prSpec)
+	}
+
+	deps := a.Dependencies()[TagValuesKind].(Dependencies)
+	orgID, bucketID, err := lookupBucket(a.Context(), deps, spec.Series)
+	if err != nil {
+		return nil, err
+	}
+
+	start := execute.Time(spec.Bounds.Start.Time(spec.Bounds.Now).UnixNano())
+	stop := execute.Time(spec.Bounds.Stop.Time(spec.Bounds.Now).UnixNano())
+	d := &stringsDecoder{
+		read: func() (cursors.StringIterator, error) {
+			return deps.Reader.ReadTagValues(a.Context(), influxdb.TagValuesSpec{
+				OrganizationID: orgID,
+				BucketID:       bucketID,
+				Predicate:      spec.Series.Predicate,
+				TagKey:         spec.Tag,
+			}, start, stop)
+		},
+		alloc: a.Allocator(),
+	}
+	return inputs.CreateSourceFromDecoder(d, dsid, a)
+}
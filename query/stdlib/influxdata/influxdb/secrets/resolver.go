@@ -0,0 +1,147 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/execute"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+// Redacted replaces the values of secrets in the errors of a sink.
+const Redacted = "<redacted>"
+
+// Dependencies contains the dependencies for resolving secrets.
+type Dependencies struct {
+	SecretService platform.SecretService
+}
+
+// Validate returns an error if any required field is unset.
+func (d Dependencies) Validate() error {
+	if d.SecretService == nil {
+		return errors.New("missing secret service dependency")
+	}
+	return nil
+}
+
+// InjectDependencies adds the dependencies for resolving secrets to the engine.
+func InjectDependencies(depsMap execute.Dependencies, deps Dependencies) error {
+	if err := deps.Validate(); err != nil {
+		return err
+	}
+	depsMap[PackagePath] = deps
+	return nil
+}
+
+// Resolver replaces the references to secrets in the parameters of a sink
+// by the values of the secrets of the organization of the query. Sinks
+// create a Resolver when they are executed, and only resolve the
+// parameters that are sent to the external system they write to, so that
+// the values never flow into the tables of a query.
+type Resolver struct {
+	ctx    context.Context
+	deps   Dependencies
+	values []string
+}
+
+// NewResolver returns a resolver for the query executed by a.
+func NewResolver(a execute.Administration) *Resolver {
+	deps, _ := a.Dependencies()[PackagePath].(Dependencies)
+	return &Resolver{ctx: a.Context(), deps: deps}
+}
+
+type taskOwnerKey struct{}
+
+// ContextWithTaskOwner returns a context of the run of a task owned by owner,
+// whose script imports the secrets package.  Such scripts are validated to
+// read the secrets of the organization when the task is created or updated.
+func ContextWithTaskOwner(ctx context.Context, owner platform.ID) context.Context {
+	return context.WithValue(ctx, taskOwnerKey{}, owner)
+}
+
+func taskOwnerFromContext(ctx context.Context) platform.ID {
+	owner, _ := ctx.Value(taskOwnerKey{}).(platform.ID)
+	return owner
+}
+
+// Resolve returns s where the references to secrets are replaced by their
+// values. The authorization of the query must allow reading the secrets of
+// its organization. Queries without an authorization may only resolve them
+// in the runs of a task, whose script was validated.
+func (r *Resolver) Resolve(s string) (string, error) {
+	if !referencePattern.MatchString(s) {
+		return s, nil
+	}
+	if r.deps.SecretService == nil {
+		return "", errors.New("secrets are not available")
+	}
+	req := query.RequestFromContext(r.ctx)
+	if req == nil {
+		return "", errors.New("missing request on context")
+	}
+	if req.Authorization != nil {
+		p, err := platform.NewPermission(platform.ReadAction, platform.SecretsResourceType, req.OrganizationID)
+		if err != nil {
+			return "", err
+		}
+		if !req.Authorization.Allowed(*p) {
+			return "", &platform.Error{
+				Code: platform.EUnauthorized,
+				Msg:  "no read permission for the secrets of the organization",
+			}
+		}
+	} else if !taskOwnerFromContext(r.ctx).Valid() {
+		return "", &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "secrets are only available to queries authorized by a token and to tasks",
+		}
+	}
+
+	var err error
+	resolved := referencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if err != nil {
+			return ref
+		}
+		key, ok := decodeReference(ref)
+		if !ok {
+			err = &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid reference to a secret; references are returned by secrets.get",
+			}
+			return ref
+		}
+		var value string
+		if value, err = r.deps.SecretService.LoadSecret(r.ctx, req.OrganizationID, key); err != nil {
+			err = fmt.Errorf("cannot read secret %q: %v", key, err)
+			return ref
+		}
+		if value != "" {
+			r.values = append(r.values, value)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+// Redact replaces the values of the secrets resolved by r in the message
+// of err.
+func (r *Resolver) Redact(err error) error {
+	if err == nil || len(r.values) == 0 {
+		return err
+	}
+	msg := err.Error()
+	redacted := msg
+	for _, value := range r.values {
+		redacted = strings.Replace(redacted, value, Redacted, -1)
+	}
+	if redacted == msg {
+		return err
+	}
+	return errors.New(redacted)
+}
@@ -0,0 +1,178 @@
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/execute"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/secrets"
+)
+
+func newSecretService(orgID platform.ID, m map[string]string) *mock.SecretService {
+	svc := mock.NewSecretService()
+	svc.LoadSecretFn = func(ctx context.Context, id platform.ID, k string) (string, error) {
+		if v, ok := m[k]; ok && id == orgID {
+			return v, nil
+		}
+		return "", &platform.Error{Code: platform.ENotFound, Msg: "secret not found"}
+	}
+	return svc
+}
+
+// administration is the execute.Administration of a query of req.
+type administration struct {
+	execute.Administration
+	ctx  context.Context
+	deps execute.Dependencies
+}
+
+func newAdministration(ctx context.Context, req *query.Request, svc platform.SecretService) *administration {
+	deps := make(execute.Dependencies)
+	if svc != nil {
+		if err := secrets.InjectDependencies(deps, secrets.Dependencies{SecretService: svc}); err != nil {
+			panic(err)
+		}
+	}
+	return &administration{
+		ctx:  query.ContextWithRequest(ctx, req),
+		deps: deps,
+	}
+}
+
+func (a *administration) Context() context.Context           { return a.ctx }
+func (a *administration) Dependencies() execute.Dependencies { return a.deps }
+
+func TestResolver_Resolve(t *testing.T) {
+	orgID := platform.ID(1)
+	svc := newSecretService(orgID, map[string]string{"token": "s3cr3t"})
+	readSecrets := &platform.Authorization{
+		Status: platform.Active,
+		Permissions: []platform.Permission{
+			{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.SecretsResourceType, OrgID: &orgID}},
+		},
+	}
+	readBuckets := &platform.Authorization{
+		Status: platform.Active,
+		Permissions: []platform.Permission{
+			{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID}},
+		},
+	}
+
+	task := secrets.ContextWithTaskOwner(context.Background(), platform.ID(3))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		req     *query.Request
+		svc     platform.SecretService
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no reference",
+			req:  &query.Request{OrganizationID: orgID, Authorization: readBuckets},
+			s:    "https://example.com",
+			want: "https://example.com",
+		},
+		{
+			name: "authorized",
+			req:  &query.Request{OrganizationID: orgID, Authorization: readSecrets},
+			svc:  svc,
+			s:    "Token " + secrets.Reference("token"),
+			want: "Token s3cr3t",
+		},
+		{
+			name:    "unauthorized",
+			req:     &query.Request{OrganizationID: orgID, Authorization: readBuckets},
+			svc:     svc,
+			s:       secrets.Reference("token"),
+			wantErr: true,
+		},
+		{
+			name: "task run",
+			ctx:  task,
+			req:  &query.Request{OrganizationID: orgID},
+			svc:  svc,
+			s:    secrets.Reference("token"),
+			want: "s3cr3t",
+		},
+		{
+			name:    "no authorization",
+			req:     &query.Request{OrganizationID: orgID},
+			svc:     svc,
+			s:       secrets.Reference("token"),
+			wantErr: true,
+		},
+		{
+			name:    "unsigned reference",
+			ctx:     task,
+			req:     &query.Request{OrganizationID: orgID},
+			svc:     svc,
+			s:       "${secret:dG9rZW4}",
+			wantErr: true,
+		},
+		{
+			name:    "forged reference",
+			req:     &query.Request{OrganizationID: orgID, Authorization: readSecrets},
+			svc:     svc,
+			s:       strings.Replace(secrets.Reference("host"), "aG9zdA", "dG9rZW4", 1),
+			wantErr: true,
+		},
+		{
+			name:    "other organization",
+			ctx:     task,
+			req:     &query.Request{OrganizationID: platform.ID(2)},
+			svc:     svc,
+			s:       secrets.Reference("token"),
+			wantErr: true,
+		},
+		{
+			name:    "missing secret service",
+			ctx:     task,
+			req:     &query.Request{OrganizationID: orgID},
+			s:       secrets.Reference("token"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			r := secrets.NewResolver(newAdministration(ctx, tt.req, tt.svc))
+			got, err := r.Resolve(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_Redact(t *testing.T) {
+	orgID := platform.ID(1)
+	svc := newSecretService(orgID, map[string]string{
+		"host":  "example.com:9999",
+		"token": "s3cr3t",
+	})
+	ctx := secrets.ContextWithTaskOwner(context.Background(), platform.ID(3))
+	r := secrets.NewResolver(newAdministration(ctx, &query.Request{OrganizationID: orgID}, svc))
+	if _, err := r.Resolve("http://" + secrets.Reference("host") + "/?token=" + secrets.Reference("token")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.Redact(errors.New("invalid token s3cr3t for http://example.com:9999"))
+	want := "invalid token <redacted> for http://<redacted>"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,128 @@
+// Package secrets implements the Flux function that reads the secrets of the
+// organization of a query, so that scripts such as tasks do not embed
+// credentials:
+//
+//	import "influxdata/influxdb/secrets"
+//	from(bucket: "telegraf")
+//	    |> range(start: -1h)
+//	    |> to(bucket: "telegraf", host: "https://example.com", token: secrets.get(key: "token"))
+//
+// Evaluating the script only returns a reference to the secret, so that the
+// value of a secret is never part of the data of a query. The references are
+// signed, so that a script cannot build one without calling get. The sinks resolve
+// the references in an allow-list of their parameters when they are
+// executed: the host and the token of to(), and the url, headers and url
+// parameters of http.to().
+package secrets
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// PackagePath is the import path of the package in Flux.
+const PackagePath = "influxdata/influxdb/secrets"
+
+const pkgSource = `package secrets
+
+builtin get
+`
+
+func init() {
+	pkg := parser.ParseSource(pkgSource)
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+	flux.RegisterPackageValue(PackagePath, "get", getFunc())
+}
+
+// getFunc returns the get function, which returns the reference to the
+// secret of its key.
+func getFunc() values.Value {
+	ftype := semantic.NewFunctionType(semantic.FunctionSignature{
+		Parameters: map[string]semantic.Type{
+			"key": semantic.String,
+		},
+		Required: []string{"key"},
+		Return:   semantic.String,
+	})
+	call := func(args values.Object) (values.Value, error) {
+		key, err := interpreter.NewArguments(args).GetRequiredString("key")
+		if err != nil {
+			return nil, err
+		}
+		return values.NewString(Reference(key)), nil
+	}
+	sideEffect := false
+	return values.NewFunction("get", ftype, call, sideEffect)
+}
+
+// referencePattern matches the references to secrets, whose keys are
+// encoded so that a reference never contains its delimiters.
+var referencePattern = regexp.MustCompile(`\$\{secret:([^}]*)\}`)
+
+// referenceKey signs the references minted by get, so that a string built by
+// a script cannot reference a secret.  It changes when influxd restarts,
+// which invalidates the references of the queries compiled before.
+var referenceKey = func() []byte {
+	k := make([]byte, 32)
+	if _, err := rand.Read(k); err != nil {
+		panic(err)
+	}
+	return k
+}()
+
+// Reference returns the reference to the secret of the key.
+func Reference(key string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(key))
+	return "${secret:" + encoded + "." + base64.RawURLEncoding.EncodeToString(referenceMAC(encoded)) + "}"
+}
+
+func referenceMAC(encodedKey string) []byte {
+	h := hmac.New(sha256.New, referenceKey)
+	h.Write([]byte(encodedKey))
+	return h.Sum(nil)
+}
+
+// decodeReference returns the key of the secret of a reference matched by
+// referencePattern.  It fails for references not returned by Reference.
+func decodeReference(ref string) (string, bool) {
+	m := referencePattern.FindStringSubmatch(ref)
+	if m == nil {
+		return "", false
+	}
+	parts := strings.Split(m[1], ".")
+	if len(parts) != 2 {
+		return "", false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(mac, referenceMAC(parts[0])) {
+		return "", false
+	}
+	key, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+// Imported reports whether the script imports the secrets package.
+func Imported(script string) bool {
+	for _, f := range parser.ParseSource(script).Files {
+		for _, imp := range f.Imports {
+			if imp.Path != nil && imp.Path.Value == PackagePath {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/semantic"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
 	"github.com/pkg/errors"
 )
 
@@ -227,7 +228,32 @@ type ReadSpec struct {
 	RetentionPolicy string // required by InfluxDB OSS
 }
 
+// TagKeysSpec specifies the series of a bucket whose tag keys are read.
+type TagKeysSpec struct {
+	OrganizationID platform.ID
+	BucketID       platform.ID
+	Predicate      *semantic.FunctionExpression
+}
+
+// TagValuesSpec specifies the tag and the series of a bucket whose tag values
+// are read.
+type TagValuesSpec struct {
+	OrganizationID platform.ID
+	BucketID       platform.ID
+	Predicate      *semantic.FunctionExpression
+	TagKey         string
+}
+
 type Reader interface {
 	Read(ctx context.Context, rs ReadSpec, start, stop execute.Time) (flux.TableIterator, error)
+
+	// ReadTagKeys returns the sorted tag keys of the series that match
+	// spec and have data between start and stop.
+	ReadTagKeys(ctx context.Context, spec TagKeysSpec, start, stop execute.Time) (cursors.StringIterator, error)
+
+	// ReadTagValues returns the sorted tag values of the series that match
+	// spec and have data between start and stop.
+	ReadTagValues(ctx context.Context, spec TagValuesSpec, start, stop execute.Time) (cursors.StringIterator, error)
+
 	Close()
 }
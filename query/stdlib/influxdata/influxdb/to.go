@@ -18,6 +18,7 @@ import (
 	"github.com/influxdata/flux/values"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/secrets"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
 )
@@ -70,6 +71,7 @@ func init() {
 	flux.RegisterOpSpec(ToKind, func() flux.OperationSpec { return &ToOpSpec{} })
 	plan.RegisterProcedureSpecWithSideEffect(ToKind, newToProcedure, ToKind)
 	execute.RegisterTransformation(ToKind, createToTransformation)
+	execute.ReplaceTransformation(http.ToHTTPKind, createToHTTPTransformation)
 }
 
 // argsReader is an interface for OperationSpec that have the same method to read args.
@@ -173,6 +175,19 @@ func (ToOpSpec) Kind() flux.OperationKind {
 // BucketsAccessed returns the buckets accessed by the spec.
 func (o *ToOpSpec) BucketsAccessed() (readBuckets, writeBuckets []platform.BucketFilter) {
 	bf := platform.BucketFilter{Name: &o.Bucket, Organization: &o.Org}
+	// An invalid ID is looked up as a name, so that the filter is never empty.
+	if o.BucketID != "" {
+		bf.Name = &o.BucketID
+		if id, err := platform.IDFromString(o.BucketID); err == nil {
+			bf.ID, bf.Name = id, nil
+		}
+	}
+	if o.OrgID != "" {
+		bf.Organization = &o.OrgID
+		if id, err := platform.IDFromString(o.OrgID); err == nil {
+			bf.OrganizationID, bf.Organization = id, nil
+		}
+	}
 	writeBuckets = append(writeBuckets, bf)
 	return readBuckets, writeBuckets
 }
@@ -225,17 +240,82 @@ func createToTransformation(id execute.DatasetID, mode execute.AccumulationMode,
 	if !ok {
 		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
 	}
+
+	// The host and the token are the only parameters that may reference secrets.
+	r := secrets.NewResolver(a)
+	s = s.Copy().(*ToProcedureSpec)
+	var err error
+	if s.Spec.Host, err = r.Resolve(s.Spec.Host); err != nil {
+		return nil, nil, err
+	}
+	if s.Spec.Token, err = r.Resolve(s.Spec.Token); err != nil {
+		return nil, nil, err
+	}
+
 	cache := execute.NewTableBuilderCache(a.Allocator())
 	d := execute.NewDataset(id, mode, cache)
 	deps := a.Dependencies()[ToKind].(ToDependencies)
 
 	t, err := NewToTransformation(d, cache, s, deps)
 	if err != nil {
+		return nil, nil, r.Redact(err)
+	}
+	return &toTransformation{ToTransformation: t, secrets: r}, d, nil
+}
+
+// toTransformation redacts the values of the secrets it resolved from the
+// errors of the transformation of to().
+type toTransformation struct {
+	*ToTransformation
+	secrets *secrets.Resolver
+}
+
+func (t *toTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	return t.secrets.Redact(t.ToTransformation.Process(id, tbl))
+}
+
+// createToHTTPTransformation replaces the transformation of http.to(), so that
+// its url, headers and url parameters may reference secrets.
+func createToHTTPTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*http.ToHTTPProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+
+	r := secrets.NewResolver(a)
+	s = s.Copy().(*http.ToHTTPProcedureSpec)
+	var err error
+	if s.Spec.URL, err = r.Resolve(s.Spec.URL); err != nil {
 		return nil, nil, err
 	}
+	for _, m := range []map[string]string{s.Spec.Headers, s.Spec.URLParams} {
+		for k, v := range m {
+			if m[k], err = r.Resolve(v); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := &toHTTPTransformation{
+		ToHTTPTransformation: http.NewToHTTPTransformation(d, cache, s),
+		secrets:              r,
+	}
 	return t, d, nil
 }
 
+// toHTTPTransformation redacts the values of the secrets it resolved from
+// the errors of the transformation of http.to().
+type toHTTPTransformation struct {
+	*http.ToHTTPTransformation
+	secrets *secrets.Resolver
+}
+
+func (t *toHTTPTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	return t.secrets.Redact(t.ToHTTPTransformation.Process(id, tbl))
+}
+
 // ToTransformation is the transformation for the `to` flux function.
 type ToTransformation struct {
 	d     execute.Dataset
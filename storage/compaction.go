@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// CompactionService describes the ability to observe and control the compactions
+// of a storage engine.
+type CompactionService interface {
+	// Compactions returns the running and queued compactions.
+	Compactions() ([]tsm1.CompactionInfo, error)
+
+	// CompactionsEnabled returns true if compactions are running.
+	CompactionsEnabled() (bool, error)
+
+	// SetCompactionsEnabled pauses or resumes compactions.
+	SetCompactionsEnabled(enabled bool) error
+
+	// ScheduleFullCompaction forces a full compaction of all the data stored.
+	ScheduleFullCompaction() error
+
+	// ScheduleOptimizeCompaction starts compactions optimizing the fully
+	// compacted data stored.
+	ScheduleOptimizeCompaction() error
+}
+
+var _ CompactionService = (*Engine)(nil)
+
+// Compactions returns the running and queued compactions of the engine.
+func (e *Engine) Compactions() ([]tsm1.CompactionInfo, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+	return e.engine.Compactions(), nil
+}
+
+// CompactionsEnabled returns true if compactions of the engine are running.
+func (e *Engine) CompactionsEnabled() (bool, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return false, ErrEngineClosed
+	}
+	return e.engine.CompactionsEnabled(), nil
+}
+
+// SetCompactionsEnabled pauses or resumes the compactions of the engine.  Pausing
+// compactions aborts the running compactions and also stops the cache from being
+// snapshotted, so writes fail once the cache is full.
+func (e *Engine) SetCompactionsEnabled(enabled bool) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+	e.engine.SetCompactionsEnabled(enabled)
+	return nil
+}
+
+// ScheduleFullCompaction forces a full compaction of all the data stored in the
+// engine.
+func (e *Engine) ScheduleFullCompaction() error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+	return e.engine.ScheduleFullCompaction()
+}
+
+// ScheduleOptimizeCompaction starts compactions optimizing the fully compacted
+// data stored in the engine.
+func (e *Engine) ScheduleOptimizeCompaction() error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+	return e.engine.ScheduleOptimizeCompaction()
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/check"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/tsdb"
@@ -94,6 +95,14 @@ func WithFileStoreObserver(obs tsm1.FileStoreObserver) Option {
 	}
 }
 
+// WithColdStore makes the engine move TSM files to the provided cold store when
+// the cold tier is enabled.
+func WithColdStore(store tsm1.ColdStore) Option {
+	return func(e *Engine) {
+		e.engine.WithColdStore(store)
+	}
+}
+
 // WithCompactionPlanner makes the engine have the provided compaction planner.
 func WithCompactionPlanner(planner tsm1.CompactionPlanner) Option {
 	return func(e *Engine) {
@@ -123,6 +132,8 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	if c.WAL.Enabled {
 		e.wal = tsm1.NewWAL(c.GetWALPath(path))
 		e.wal.WithFsyncDelay(time.Duration(c.WAL.FsyncDelay))
+		e.wal.WithRetentionSize(int64(c.WAL.RetentionSize))
+		e.wal.WithReplication(c.WAL.Replication)
 		e.wal.EnableTraceLogging(c.TraceLoggingEnabled)
 		wal = e.wal
 	}
@@ -249,6 +260,22 @@ func (e *Engine) runRetentionEnforcer() {
 	}()
 }
 
+// CheckName returns the name of the engine's health check.
+func (e *Engine) CheckName() string {
+	return "storage-engine"
+}
+
+// Check reports whether the engine is open.
+func (e *Engine) Check(ctx context.Context) check.Response {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closing == nil {
+		return check.Error(ErrEngineClosed)
+	}
+	return check.Pass()
+}
+
 // Close closes the store and all underlying resources. It returns an error if
 // any of the underlying systems fail to close.
 func (e *Engine) Close() error {
@@ -306,32 +333,9 @@ func (e *Engine) WritePoints(points []models.Point) error {
 
 	j := 0
 	for iter := collection.Iterator(); iter.Next(); {
-		tags := iter.Tags()
-
-		if tags.Len() > 0 && bytes.Equal(tags[0].Key, tsdb.FieldKeyTagKeyBytes) && bytes.Equal(tags[0].Value, timeBytes) {
-			// Field key "time" is invalid
+		if reason := e.invalidSeries(iter.Name(), iter.Tags(), iter.Key()); reason != "" {
 			if collection.Reason == "" {
-				collection.Reason = fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes)
-			}
-			collection.Dropped++
-			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
-			continue
-		}
-
-		// Filter out any tags with key equal to "time": they are invalid.
-		if tags.Get(timeBytes) != nil {
-			if collection.Reason == "" {
-				collection.Reason = fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name())
-			}
-			collection.Dropped++
-			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
-			continue
-		}
-
-		// Drop any series with invalid unicode characters in the key.
-		if e.config.ValidateKeys && !models.ValidKeyTokens(string(iter.Name()), tags) {
-			if collection.Reason == "" {
-				collection.Reason = fmt.Sprintf("key contains invalid unicode: %q", iter.Key())
+				collection.Reason = reason
 			}
 			collection.Dropped++
 			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
@@ -366,6 +370,79 @@ func (e *Engine) WritePoints(points []models.Point) error {
 	return collection.PartialWriteError()
 }
 
+// ValidatePoints returns the error WritePoints would return for points,
+// without writing them. Points that WritePoints would drop, because of their
+// keys or because their series or their field already has another type, are
+// reported by series key in a tsdb.InvalidPointsError. The points are checked
+// together, so that a batch that does not fit in the cache is rejected whole.
+func (e *Engine) ValidatePoints(points []models.Point) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	reasons := make(map[string]string)
+	collection := tsdb.NewSeriesCollection(points)
+	for iter := collection.Iterator(); iter.Next(); {
+		reason := e.invalidSeries(iter.Name(), iter.Tags(), iter.Key())
+		if reason == "" {
+			id := e.sfile.SeriesIDTyped(iter.Name(), iter.Tags(), nil)
+			if !id.IsZero() && id.HasType() && id.Type() != iter.Type() {
+				reason = fmt.Sprintf("series type mismatch: already %s but got %s", id.Type(), iter.Type())
+			}
+		}
+		if reason != "" {
+			reasons[string(iter.Key())] = reason
+		}
+	}
+
+	valid := points
+	if len(reasons) > 0 {
+		valid = make([]models.Point, 0, len(points))
+		for _, p := range points {
+			if _, ok := reasons[string(p.Key())]; !ok {
+				valid = append(valid, p)
+			}
+		}
+	}
+
+	err := e.engine.ValidatePoints(valid)
+	if invalid, ok := err.(tsdb.InvalidPointsError); ok {
+		for key, reason := range invalid.Reasons {
+			reasons[key] = reason
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if len(reasons) > 0 {
+		return tsdb.InvalidPointsError{Reasons: reasons}
+	}
+	return nil
+}
+
+// invalidSeries returns why WritePoints drops the points of a series, or an
+// empty string if they are written.
+func (e *Engine) invalidSeries(name []byte, tags models.Tags, key []byte) string {
+	if tags.Len() > 0 && bytes.Equal(tags[0].Key, tsdb.FieldKeyTagKeyBytes) && bytes.Equal(tags[0].Value, timeBytes) {
+		// Field key "time" is invalid
+		return fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes)
+	}
+
+	// Filter out any tags with key equal to "time": they are invalid.
+	if tags.Get(timeBytes) != nil {
+		return fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, name)
+	}
+
+	// Drop any series with invalid unicode characters in the key.
+	if e.config.ValidateKeys && !models.ValidKeyTokens(string(name), tags) {
+		return fmt.Sprintf("key contains invalid unicode: %q", key)
+	}
+	return ""
+}
+
 // DeleteBucket deletes an entire bucket from the storage engine.
 func (e *Engine) DeleteBucket(orgID, bucketID platform.ID) error {
 	e.mu.RLock()
@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+// TagKeys returns an iterator over the sorted tag keys of the series in the
+// bucket that match predicate and have data between start and end, inclusive.
+// A nil predicate matches every series.
+//
+// The keys are read from the index; the series of each key are only read from
+// TSM until one of them is found to have data in the time range.
+func (e *Engine) TagKeys(ctx context.Context, orgID, bucketID platform.ID, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := encoded[:]
+
+	s, err := e.newSchemaReader(ctx, name, start, end, predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	kitr, err := e.index.TagKeyIterator(name)
+	if err != nil {
+		return nil, err
+	} else if kitr == nil {
+		return cursors.EmptyStringIterator, nil
+	}
+	defer kitr.Close()
+
+	var keys []string
+	for {
+		key, err := kitr.Next()
+		if err != nil {
+			return nil, err
+		} else if key == nil {
+			break
+		}
+
+		sitr, err := e.index.TagKeySeriesIDIterator(name, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := s.hasData(sitr); err != nil {
+			return nil, err
+		} else if ok {
+			keys = append(keys, string(key))
+		}
+	}
+
+	sort.Strings(keys)
+	return cursors.NewStringSliceIterator(keys), nil
+}
+
+// TagValues returns an iterator over the sorted values of tagKey in the
+// series of the bucket that match predicate and have data between start and
+// end, inclusive. A nil predicate matches every series.
+//
+// The values are read from the index; the series of each value are only read
+// from TSM until one of them is found to have data in the time range.
+func (e *Engine) TagValues(ctx context.Context, orgID, bucketID platform.ID, tagKey string, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := encoded[:]
+	key := []byte(tagKey)
+
+	s, err := e.newSchemaReader(ctx, name, start, end, predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	vitr, err := e.index.TagValueIterator(name, key)
+	if err != nil {
+		return nil, err
+	} else if vitr == nil {
+		return cursors.EmptyStringIterator, nil
+	}
+	defer vitr.Close()
+
+	var values []string
+	for {
+		value, err := vitr.Next()
+		if err != nil {
+			return nil, err
+		} else if value == nil {
+			break
+		}
+
+		sitr, err := e.index.TagValueSeriesIDIterator(name, key, value)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := s.hasData(sitr); err != nil {
+			return nil, err
+		} else if ok {
+			values = append(values, string(value))
+		}
+	}
+
+	sort.Strings(values)
+	return cursors.NewStringSliceIterator(values), nil
+}
+
+// schemaReader determines whether any of a set of series match a predicate
+// and have data in a time range.
+type schemaReader struct {
+	ctx    context.Context
+	sfile  *tsdb.SeriesFile
+	cur    tsdb.CursorIterator
+	series *tsdb.SeriesIDSet // series matching the predicate; nil matches all.
+	req    cursors.CursorRequest
+}
+
+func (e *Engine) newSchemaReader(ctx context.Context, name []byte, start, end int64, predicate influxql.Expr) (*schemaReader, error) {
+	cur, err := e.engine.CreateCursorIterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schemaReader{
+		ctx:   ctx,
+		sfile: e.sfile,
+		cur:   cur,
+		req: cursors.CursorRequest{
+			Ascending: true,
+			StartTime: start,
+			EndTime:   end,
+		},
+	}
+
+	if predicate != nil {
+		sitr, err := e.index.MeasurementSeriesByExprIterator(name, predicate)
+		if err != nil {
+			return nil, err
+		}
+		s.series = tsdb.NewSeriesIDSet()
+		if sitr != nil {
+			defer sitr.Close()
+			for {
+				elem, err := sitr.Next()
+				if err != nil {
+					return nil, err
+				} else if elem.SeriesID.IsZero() {
+					break
+				}
+				s.series.Add(elem.SeriesID)
+			}
+		}
+	}
+	return s, nil
+}
+
+// hasData reports whether any series of itr matches the predicate and has
+// data in the time range. It closes itr.
+func (s *schemaReader) hasData(itr tsdb.SeriesIDIterator) (bool, error) {
+	if itr == nil {
+		return false, nil
+	}
+	defer itr.Close()
+
+	for {
+		elem, err := itr.Next()
+		if err != nil {
+			return false, err
+		} else if elem.SeriesID.IsZero() {
+			return false, nil
+		}
+
+		if s.series != nil && !s.series.Contains(elem.SeriesID) {
+			continue
+		}
+
+		key := s.sfile.SeriesKey(elem.SeriesID)
+		if len(key) == 0 {
+			continue
+		}
+
+		if ok, err := s.seriesHasData(key); err != nil {
+			return false, err
+		} else if ok {
+			return true, nil
+		}
+	}
+}
+
+// seriesHasData reports whether the series with the provided series key has
+// any values in the time range.
+func (s *schemaReader) seriesHasData(key []byte) (bool, error) {
+	name, tags := tsdb.ParseSeriesKey(key)
+	s.req.Name = name
+	s.req.Tags = tags
+	s.req.Field = string(tags.Get(tsdb.FieldKeyTagKeyBytes))
+
+	cur, err := s.cur.Next(s.ctx, &s.req)
+	if err != nil {
+		return false, err
+	} else if cur == nil {
+		return false, nil
+	}
+	defer cur.Close()
+
+	var n int
+	switch c := cur.(type) {
+	case cursors.IntegerArrayCursor:
+		n = c.Next().Len()
+	case cursors.FloatArrayCursor:
+		n = c.Next().Len()
+	case cursors.UnsignedArrayCursor:
+		n = c.Next().Len()
+	case cursors.StringArrayCursor:
+		n = c.Next().Len()
+	case cursors.BooleanArrayCursor:
+		n = c.Next().Len()
+	default:
+		return false, fmt.Errorf("unexpected cursor type %T", cur)
+	}
+	return n > 0, cur.Err()
+}
@@ -1,17 +1,23 @@
 package storage_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"math"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/check"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
 )
 
 func TestEngine_WriteAndIndex(t *testing.T) {
@@ -151,6 +157,120 @@ func TestEngine_WriteAddNewField(t *testing.T) {
 	}
 }
 
+func TestEngine_ValidatePoints(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pt := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "server"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	if err := engine.Write1xPoints([]models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "server"}),
+		map[string]interface{}{"value": 2.0},
+		time.Unix(2, 2),
+	)
+	conflict := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "server"}),
+		map[string]interface{}{"value": int64(3)},
+		time.Unix(3, 2),
+	)
+	timeField := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{}),
+		map[string]interface{}{"time": 1.0},
+		time.Unix(1, 2),
+	)
+
+	for _, tt := range []struct {
+		name     string
+		points   []models.Point
+		rejected []models.Point
+	}{
+		{name: "valid", points: []models.Point{valid}},
+		{name: "field type conflict", points: []models.Point{valid, conflict}, rejected: []models.Point{conflict}},
+		{name: "time field", points: []models.Point{valid, timeField}, rejected: []models.Point{timeField}},
+		{name: "both", points: []models.Point{conflict, valid, timeField}, rejected: []models.Point{conflict, timeField}},
+	} {
+		points, err := tsdb.ExplodePoints(engine.org, engine.bucket, tt.points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = engine.ValidatePoints(points)
+		if len(tt.rejected) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+
+		// Every rejected point is reported by key, with its own reason.
+		invalid, ok := err.(tsdb.InvalidPointsError)
+		if !ok {
+			t.Errorf("%s: expected tsdb.InvalidPointsError, got %v", tt.name, err)
+			continue
+		}
+		rejected, err := tsdb.ExplodePoints(engine.org, engine.bucket, tt.rejected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, exp := len(invalid.Reasons), len(rejected); got != exp {
+			t.Errorf("%s: got %d rejected keys, exp %d: %v", tt.name, got, exp, invalid.Reasons)
+		}
+		for _, pt := range rejected {
+			if invalid.Reasons[string(pt.Key())] == "" {
+				t.Errorf("%s: key %q was not rejected", tt.name, pt.Key())
+			}
+		}
+	}
+
+	// Validating does not write.
+	if got, exp := engine.SeriesCardinality(), int64(1); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
+func TestEngine_ValidatePoints_CacheSize(t *testing.T) {
+	config := storage.NewConfig()
+	config.Engine.Cache.MaxMemorySize = 1024
+	engine := NewEngine(config)
+	defer engine.Close()
+	engine.MustOpen()
+
+	var points []models.Point
+	for i := 0; i < 100; i++ {
+		pt := models.MustNewPoint(
+			"cpu",
+			models.NewTags(map[string]string{"host": fmt.Sprintf("server%d", i)}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+		exploded, err := tsdb.ExplodePoints(engine.org, engine.bucket, []models.Point{pt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Every point fits in the cache on its own.
+		if err := engine.ValidatePoints(exploded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		points = append(points, exploded...)
+	}
+
+	// The batch does not fit in the cache as a whole.
+	if err := engine.ValidatePoints(points); err == nil || !strings.Contains(err.Error(), "cache-max-memory-size") {
+		t.Fatalf("expected cache size limit error, got %v", err)
+	}
+}
+
 func TestEngine_DeleteBucket(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -213,6 +333,87 @@ func TestEngine_OpenClose(t *testing.T) {
 	}
 }
 
+func TestEngine_Check(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+
+	ctx := context.Background()
+	if resp := engine.Check(ctx); resp.Status != check.StatusFail {
+		t.Fatalf("got status %q before opening, want fail", resp.Status)
+	}
+
+	engine.MustOpen()
+	if resp := engine.Check(ctx); resp.Status != check.StatusPass {
+		t.Fatalf("got status %q (%s) after opening, want pass", resp.Status, resp.Message)
+	}
+
+	engine.Engine.Close() // Don't destroy temporary data.
+	if resp := engine.Check(ctx); resp.Status != check.StatusFail {
+		t.Fatalf("got status %q after closing, want fail", resp.Status)
+	}
+}
+
+func TestEngine_TagKeysAndValues(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pts, err := models.ParsePointsString(`
+cpu,host=a,region=west value=1 10000000000
+cpu,host=b,region=east value=2 20000000000
+mem,host=c free=3 30000000000
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Write1xPoints(pts); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	all := [2]int64{math.MinInt64, math.MaxInt64}
+	tests := []struct {
+		name      string
+		tagKey    string // tag values are read unless empty
+		timeRange [2]int64
+		predicate string
+		exp       []string
+	}{
+		{name: "keys", timeRange: all, exp: []string{"_f", "_m", "host", "region"}},
+		{name: "keys in range", timeRange: [2]int64{25e9, 35e9}, exp: []string{"_f", "_m", "host"}},
+		{name: "keys with predicate", timeRange: all, predicate: `_m = 'mem'`, exp: []string{"_f", "_m", "host"}},
+		{name: "values", tagKey: "host", timeRange: all, exp: []string{"a", "b", "c"}},
+		{name: "values in range", tagKey: "host", timeRange: [2]int64{15e9, 25e9}, exp: []string{"b"}},
+		{name: "values with predicate", tagKey: "host", timeRange: all, predicate: `region = 'west'`, exp: []string{"a"}},
+		{name: "measurements", tagKey: "_m", timeRange: all, exp: []string{"cpu", "mem"}},
+		{name: "fields with predicate", tagKey: "_f", timeRange: all, predicate: `_m = 'cpu'`, exp: []string{"value"}},
+		{name: "no values", tagKey: "host", timeRange: [2]int64{40e9, 50e9}, exp: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var predicate influxql.Expr
+			if tt.predicate != "" {
+				predicate = influxql.MustParseExpr(tt.predicate)
+			}
+
+			var itr cursors.StringIterator
+			var err error
+			if tt.tagKey == "" {
+				itr, err = engine.TagKeys(ctx, engine.org, engine.bucket, tt.timeRange[0], tt.timeRange[1], predicate)
+			} else {
+				itr, err = engine.TagValues(ctx, engine.org, engine.bucket, tt.tagKey, tt.timeRange[0], tt.timeRange[1], predicate)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := cursors.StringIteratorToSlice(itr); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("got %v, expected %v", got, tt.exp)
+			}
+		})
+	}
+}
+
 // Ensures that when a shard is closed, it removes any series meta-data
 // from the index.
 func TestEngineClose_RemoveIndex(t *testing.T) {
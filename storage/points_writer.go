@@ -8,3 +8,9 @@ import (
 type PointsWriter interface {
 	WritePoints([]models.Point) error
 }
+
+// PointsValidator describes the ability to check that points can be written
+// into a storage engine without writing them.
+type PointsValidator interface {
+	ValidatePoints([]models.Point) error
+}
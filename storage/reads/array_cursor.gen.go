@@ -55,8 +55,6 @@ func (c *floatArrayFilterCursor) Next() *cursors.FloatArray {
 
 	if c.tmp.Len() > 0 {
 		a = c.tmp
-		c.tmp.Timestamps = nil
-		c.tmp.Values = nil
 	} else {
 		a = c.FloatArrayCursor.Next()
 	}
@@ -76,6 +74,11 @@ LOOP:
 				}
 			}
 		}
+
+		// The values of a have all been read; clear any remaining from a
+		// previous call, as a may be c.tmp.
+		c.tmp.Timestamps = nil
+		c.tmp.Values = nil
 		a = c.FloatArrayCursor.Next()
 	}
 
@@ -284,8 +287,6 @@ func (c *integerArrayFilterCursor) Next() *cursors.IntegerArray {
 
 	if c.tmp.Len() > 0 {
 		a = c.tmp
-		c.tmp.Timestamps = nil
-		c.tmp.Values = nil
 	} else {
 		a = c.IntegerArrayCursor.Next()
 	}
@@ -305,6 +306,11 @@ LOOP:
 				}
 			}
 		}
+
+		// The values of a have all been read; clear any remaining from a
+		// previous call, as a may be c.tmp.
+		c.tmp.Timestamps = nil
+		c.tmp.Values = nil
 		a = c.IntegerArrayCursor.Next()
 	}
 
@@ -513,8 +519,6 @@ func (c *unsignedArrayFilterCursor) Next() *cursors.UnsignedArray {
 
 	if c.tmp.Len() > 0 {
 		a = c.tmp
-		c.tmp.Timestamps = nil
-		c.tmp.Values = nil
 	} else {
 		a = c.UnsignedArrayCursor.Next()
 	}
@@ -534,6 +538,11 @@ LOOP:
 				}
 			}
 		}
+
+		// The values of a have all been read; clear any remaining from a
+		// previous call, as a may be c.tmp.
+		c.tmp.Timestamps = nil
+		c.tmp.Values = nil
 		a = c.UnsignedArrayCursor.Next()
 	}
 
@@ -742,8 +751,6 @@ func (c *stringArrayFilterCursor) Next() *cursors.StringArray {
 
 	if c.tmp.Len() > 0 {
 		a = c.tmp
-		c.tmp.Timestamps = nil
-		c.tmp.Values = nil
 	} else {
 		a = c.StringArrayCursor.Next()
 	}
@@ -763,6 +770,11 @@ LOOP:
 				}
 			}
 		}
+
+		// The values of a have all been read; clear any remaining from a
+		// previous call, as a may be c.tmp.
+		c.tmp.Timestamps = nil
+		c.tmp.Values = nil
 		a = c.StringArrayCursor.Next()
 	}
 
@@ -931,8 +943,6 @@ func (c *booleanArrayFilterCursor) Next() *cursors.BooleanArray {
 
 	if c.tmp.Len() > 0 {
 		a = c.tmp
-		c.tmp.Timestamps = nil
-		c.tmp.Values = nil
 	} else {
 		a = c.BooleanArrayCursor.Next()
 	}
@@ -952,6 +962,11 @@ LOOP:
 				}
 			}
 		}
+
+		// The values of a have all been read; clear any remaining from a
+		// previous call, as a may be c.tmp.
+		c.tmp.Timestamps = nil
+		c.tmp.Values = nil
 		a = c.BooleanArrayCursor.Next()
 	}
 
@@ -0,0 +1,74 @@
+package reads
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+type mockFloatArrayCursor struct {
+	arrays []*cursors.FloatArray
+}
+
+func (c *mockFloatArrayCursor) Close()                     {}
+func (c *mockFloatArrayCursor) Err() error                 { return nil }
+func (c *mockFloatArrayCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func (c *mockFloatArrayCursor) Next() *cursors.FloatArray {
+	if len(c.arrays) == 0 {
+		return &cursors.FloatArray{}
+	}
+	a := c.arrays[0]
+	c.arrays = c.arrays[1:]
+	return a
+}
+
+// newFloatArray returns an array of n values, starting at v, with timestamps
+// equal to the values.
+func newFloatArray(v float64, n int) *cursors.FloatArray {
+	a := cursors.NewFloatArrayLen(n)
+	for i := range a.Values {
+		a.Timestamps[i] = int64(v) + int64(i)
+		a.Values[i] = v + float64(i)
+	}
+	return a
+}
+
+func TestFloatArrayFilterCursor(t *testing.T) {
+	// The first block has a few values that do not match, so the values of
+	// the second block are split across two calls to Next.
+	cur := &mockFloatArrayCursor{arrays: []*cursors.FloatArray{
+		newFloatArray(995, MaxPointsPerBlock),
+		newFloatArray(995+MaxPointsPerBlock, MaxPointsPerBlock),
+		newFloatArray(0, 10),
+	}}
+
+	c := newFloatFilterArrayCursor(&astExpr{&influxql.BinaryExpr{
+		Op:  influxql.GTE,
+		LHS: &influxql.VarRef{Val: fieldRef},
+		RHS: &influxql.NumberLiteral{Val: 1000},
+	}})
+	c.reset(cur)
+
+	var got []float64
+	for {
+		a := c.Next()
+		if a.Len() == 0 {
+			break
+		}
+		if a.Len() > MaxPointsPerBlock {
+			t.Fatalf("unexpected number of values: got %d, exp <= %d", a.Len(), MaxPointsPerBlock)
+		}
+		got = append(got, a.Values...)
+	}
+
+	if exp := 2*MaxPointsPerBlock - 5; len(got) != exp {
+		t.Fatalf("unexpected number of values: got %d, exp %d", len(got), exp)
+	}
+	for i, v := range got {
+		if exp := float64(1000 + i); v != exp {
+			t.Fatalf("unexpected value at %d: got %v, exp %v", i, v, exp)
+		}
+	}
+}
@@ -18,6 +18,10 @@ const (
 	fieldKey       = "_field"
 	measurementKey = "_measurement"
 	valueKey       = "_value"
+
+	// fieldRef is the name of the VarRef that refers to the value of a
+	// field in an expression.
+	fieldRef = "$"
 )
 
 // NodeVisitor can be called by Walk to traverse the Node hierarchy.
@@ -454,7 +458,7 @@ func (v *nodeToExprVisitor) Visit(n *datatypes.Node) NodeVisitor {
 		return nil
 
 	case datatypes.NodeTypeFieldRef:
-		v.exprs = append(v.exprs, &influxql.VarRef{Val: "$"})
+		v.exprs = append(v.exprs, &influxql.VarRef{Val: fieldRef})
 		return nil
 
 	case datatypes.NodeTypeLiteral:
@@ -533,7 +537,7 @@ func RewriteExprRemoveFieldValue(expr influxql.Expr) influxql.Expr {
 	return influxql.RewriteExpr(expr, func(expr influxql.Expr) influxql.Expr {
 		if be, ok := expr.(*influxql.BinaryExpr); ok {
 			if ref, ok := be.LHS.(*influxql.VarRef); ok {
-				if ref.Val == "$" {
+				if ref.Val == fieldRef {
 					return &influxql.BooleanLiteral{Val: true}
 				}
 			}
@@ -575,8 +579,9 @@ func (v *hasRefs) Visit(node influxql.Node) influxql.Visitor {
 	return v
 }
 
+// HasFieldValueKey reports whether expr compares the value of a field.
 func HasFieldValueKey(expr influxql.Expr) bool {
-	refs := hasRefs{refs: []string{valueKey}, found: make([]bool, 1)}
+	refs := hasRefs{refs: []string{fieldRef}, found: make([]bool, 1)}
 	influxql.Walk(&refs, expr)
 	return refs.found[0]
 }
@@ -9,11 +9,14 @@ import (
 	"github.com/gogo/protobuf/types"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
+	opentracing "github.com/opentracing/opentracing-go"
 )
 
 type storageTable interface {
@@ -49,6 +52,57 @@ func (r *storeReader) Read(ctx context.Context, rs influxdb.ReadSpec, start, sto
 	}, nil
 }
 
+func (r *storeReader) ReadTagKeys(ctx context.Context, spec influxdb.TagKeysSpec, start, stop execute.Time) (cursors.StringIterator, error) {
+	src, predicate, err := r.schemaSource(spec.OrganizationID, spec.BucketID, spec.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.s.TagKeys(ctx, &TagKeysRequest{
+		ReadSource: src,
+		Range:      datatypes.TimestampRange{Start: int64(start), End: int64(stop)},
+		Predicate:  predicate,
+	})
+}
+
+func (r *storeReader) ReadTagValues(ctx context.Context, spec influxdb.TagValuesSpec, start, stop execute.Time) (cursors.StringIterator, error) {
+	src, predicate, err := r.schemaSource(spec.OrganizationID, spec.BucketID, spec.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.s.TagValues(ctx, &TagValuesRequest{
+		ReadSource: src,
+		Range:      datatypes.TimestampRange{Start: int64(start), End: int64(stop)},
+		Predicate:  predicate,
+		TagKey:     spec.TagKey,
+	})
+}
+
+// schemaSource returns the read source of the bucket and the storage
+// predicate of fn for a tag keys or tag values request.
+func (r *storeReader) schemaSource(orgID, bucketID platform.ID, fn *semantic.FunctionExpression) (*types.Any, *datatypes.Predicate, error) {
+	src, err := r.s.GetSource(influxdb.ReadSpec{
+		OrganizationID: orgID,
+		BucketID:       bucketID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	any, err := types.MarshalAny(src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var predicate *datatypes.Predicate
+	if fn != nil {
+		if predicate, err = toStoragePredicate(fn); err != nil {
+			return nil, nil, err
+		}
+	}
+	return any, predicate, nil
+}
+
 func (r *storeReader) Close() {}
 
 type tableIterator struct {
@@ -63,6 +117,18 @@ type tableIterator struct {
 func (bi *tableIterator) Statistics() flux.Statistics { return bi.stats }
 
 func (bi *tableIterator) Do(f func(flux.Table) error) error {
+	ctx := bi.ctx
+	span := opentracing.SpanFromContext(ctx)
+	if span != nil {
+		span = opentracing.StartSpan(
+			"storage.read",
+			opentracing.ChildOf(span.Context()),
+			opentracing.Tag{Key: "org_id", Value: bi.readSpec.OrganizationID.String()},
+			opentracing.Tag{Key: "bucket_id", Value: bi.readSpec.BucketID.String()})
+		defer span.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
+
 	src, err := bi.s.GetSource(bi.readSpec)
 	if err != nil {
 		return err
@@ -97,7 +163,7 @@ func (bi *tableIterator) Do(f func(flux.Table) error) error {
 
 	switch {
 	case req.Group != datatypes.GroupAll:
-		rs, err := bi.s.GroupRead(bi.ctx, &req)
+		rs, err := bi.s.GroupRead(ctx, &req)
 		if err != nil {
 			return err
 		}
@@ -112,7 +178,7 @@ func (bi *tableIterator) Do(f func(flux.Table) error) error {
 		return bi.handleGroupRead(f, rs)
 
 	default:
-		rs, err := bi.s.Read(bi.ctx, &req)
+		rs, err := bi.s.Read(ctx, &req)
 		if err != nil {
 			return err
 		}
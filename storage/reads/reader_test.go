@@ -0,0 +1,181 @@
+package reads
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	fluxinfluxdb "github.com/influxdata/flux/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+// valueFilterStore reads a single float series. Like the storage engine, it
+// evaluates the comparisons of the predicate with the field value in the
+// cursors of the series.
+type valueFilterStore struct {
+	arrays []*cursors.FloatArray
+	req    *datatypes.ReadRequest
+}
+
+func (s *valueFilterStore) Read(ctx context.Context, req *datatypes.ReadRequest) (ResultSet, error) {
+	s.req = req
+	if req.PointsLimit == 0 {
+		req.PointsLimit = math.MaxInt64
+	}
+	row := SeriesRow{
+		Name:  []byte("m"),
+		Tags:  models.NewTags(map[string]string{measurementKey: "m", fieldKey: "f"}),
+		Field: "f",
+		Query: cursors.CursorIterators{&floatCursorIterator{arrays: s.arrays}},
+	}
+	if req.Predicate != nil {
+		cond, err := NodeToExpr(req.Predicate.Root, nil)
+		if err != nil {
+			return nil, err
+		}
+		if HasFieldValueKey(cond) {
+			row.ValueCond = cond
+		}
+	}
+	return NewResultSet(ctx, req, &sliceSeriesCursor{rows: []SeriesRow{row}}), nil
+}
+
+func (s *valueFilterStore) GroupRead(ctx context.Context, req *datatypes.ReadRequest) (GroupResultSet, error) {
+	return nil, nil
+}
+
+func (s *valueFilterStore) GetSource(rs influxdb.ReadSpec) (proto.Message, error) {
+	return &types.Empty{}, nil
+}
+
+func (s *valueFilterStore) TagKeys(ctx context.Context, req *TagKeysRequest) (cursors.StringIterator, error) {
+	return nil, nil
+}
+
+func (s *valueFilterStore) TagValues(ctx context.Context, req *TagValuesRequest) (cursors.StringIterator, error) {
+	return nil, nil
+}
+
+type floatCursorIterator struct {
+	arrays []*cursors.FloatArray
+}
+
+func (i *floatCursorIterator) Next(ctx context.Context, r *cursors.CursorRequest) (cursors.Cursor, error) {
+	return &mockFloatArrayCursor{arrays: i.arrays}, nil
+}
+
+func (i *floatCursorIterator) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+type sliceSeriesCursor struct {
+	rows []SeriesRow
+}
+
+func (c *sliceSeriesCursor) Close()     {}
+func (c *sliceSeriesCursor) Err() error { return nil }
+
+func (c *sliceSeriesCursor) Next() *SeriesRow {
+	if len(c.rows) == 0 {
+		return nil
+	}
+	row := &c.rows[0]
+	c.rows = c.rows[1:]
+	return row
+}
+
+// fromPredicate plans query and returns the predicate merged into from().
+func fromPredicate(t *testing.T, query string) *semantic.FunctionExpression {
+	t.Helper()
+
+	spec, err := flux.Compile(context.Background(), query, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lp, err := plan.NewLogicalPlanner().Plan(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp, err := plan.NewPhysicalPlanner().Plan(lp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fn *semantic.FunctionExpression
+	err = pp.BottomUpWalk(func(n plan.PlanNode) error {
+		if s, ok := n.ProcedureSpec().(*fluxinfluxdb.FromProcedureSpec); ok && s.FilterSet {
+			fn = s.Filter
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fn == nil {
+		t.Fatalf("the filter of %q was not merged into from()", query)
+	}
+	return fn
+}
+
+func TestStoreReader_ValueFilter(t *testing.T) {
+	// The storage source reads with the predicate merged into from(), see
+	// createFromSource.
+	spec := influxdb.ReadSpec{
+		Predicate: fromPredicate(t, `from(bucket: "b") |> range(start: 0) |> filter(fn: (r) => r._value >= 1000.0)`),
+	}
+
+	s := &valueFilterStore{arrays: []*cursors.FloatArray{
+		newFloatArray(995, MaxPointsPerBlock),
+		newFloatArray(995+MaxPointsPerBlock, 10),
+	}}
+	ti, err := NewReader(s).Read(context.Background(), spec, 0, execute.Time(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []float64
+	err = ti.Do(func(tbl flux.Table) error {
+		return tbl.Do(func(cr flux.ColReader) error {
+			vs := cr.Floats(execute.ColIdx(valueKey, cr.Cols()))
+			for i := 0; i < vs.Len(); i++ {
+				got = append(got, vs.Value(i))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The comparison with the field value reaches the storage request.
+	if s.req == nil || s.req.Predicate == nil {
+		t.Fatal("the read request has no predicate")
+	}
+	cond, err := NodeToExpr(s.req.Predicate.Root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !HasFieldValueKey(cond) {
+		t.Fatalf("the predicate of the read request does not compare the field value: %s", cond)
+	}
+
+	// The values that do not match are dropped by the storage cursors.
+	if exp := MaxPointsPerBlock + 5; len(got) != exp {
+		t.Fatalf("unexpected number of values: got %d, exp %d", len(got), exp)
+	}
+	for i, v := range got {
+		if exp := float64(1000 + i); v != exp {
+			t.Fatalf("unexpected value at %d: got %v, exp %v", i, v, exp)
+		}
+	}
+}
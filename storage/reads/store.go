@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
@@ -74,8 +75,31 @@ type GroupCursor interface {
 	Stats() cursors.CursorStats
 }
 
+// TagKeysRequest is a request for the tag keys of the series of ReadSource
+// that match Predicate and have data in Range.
+type TagKeysRequest struct {
+	ReadSource *types.Any
+	Range      datatypes.TimestampRange
+	Predicate  *datatypes.Predicate
+}
+
+// TagValuesRequest is a request for the values of TagKey in the series of
+// ReadSource that match Predicate and have data in Range.
+type TagValuesRequest struct {
+	ReadSource *types.Any
+	Range      datatypes.TimestampRange
+	Predicate  *datatypes.Predicate
+	TagKey     string
+}
+
 type Store interface {
 	Read(ctx context.Context, req *datatypes.ReadRequest) (ResultSet, error)
 	GroupRead(ctx context.Context, req *datatypes.ReadRequest) (GroupResultSet, error)
 	GetSource(rs influxdb.ReadSpec) (proto.Message, error)
+
+	// TagKeys returns the sorted tag keys that match req.
+	TagKeys(ctx context.Context, req *TagKeysRequest) (cursors.StringIterator, error)
+
+	// TagValues returns the sorted tag values that match req.
+	TagValues(ctx context.Context, req *TagValuesRequest) (cursors.StringIterator, error)
 }
@@ -1,6 +1,9 @@
 package readservice
 
-import "github.com/influxdata/influxdb/models"
+import (
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
 
 const (
 	fieldKey       = "_field"
@@ -24,3 +27,27 @@ func normalizeTags(tags models.Tags) {
 		}
 	}
 }
+
+// normalizeTagKey returns the name of key as it appears in the results of
+// a read.
+func normalizeTagKey(key string) string {
+	switch key {
+	case tsdb.FieldKeyTagKey:
+		return fieldKey
+	case tsdb.MeasurementTagKey:
+		return measurementKey
+	}
+	return key
+}
+
+// denormalizeTagKey is the inverse of normalizeTagKey; it returns the name of
+// key in the index.
+func denormalizeTagKey(key string) string {
+	switch key {
+	case fieldKey:
+		return tsdb.FieldKeyTagKey
+	case measurementKey:
+		return tsdb.MeasurementTagKey
+	}
+	return key
+}
@@ -131,7 +131,9 @@ func (c *indexSeriesCursor) Next() *reads.SeriesRow {
 }
 
 func (c *indexSeriesCursor) Value(key string) (interface{}, bool) {
-	res := c.row.Tags.Get([]byte(key))
+	// The condition refers to tags by their keys in the index, such as _m
+	// and _f, so look them up in the tags of the series before normalization.
+	res := c.row.SeriesTags.Get([]byte(key))
 	// Return res as a string so it compares correctly with the string literals
 	return string(res), res != nil
 }
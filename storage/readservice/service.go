@@ -6,6 +6,7 @@ import (
 	"github.com/influxdata/influxdb/query"
 	pcontrol "github.com/influxdata/influxdb/query/control"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/schema"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/reads"
 )
@@ -21,7 +22,7 @@ func NewProxyQueryService(queryController *pcontrol.Controller) query.ProxyQuery
 }
 
 // AddControllerConfigDependencies sets up the dependencies on cc
-// such that "from" and "to" flux functions will work correctly.
+// such that "from", "to" and the schema flux functions will work correctly.
 func AddControllerConfigDependencies(
 	cc *control.Config,
 	engine *storage.Engine,
@@ -30,8 +31,9 @@ func AddControllerConfigDependencies(
 ) error {
 	bucketLookupSvc := query.FromBucketService(bucketSvc)
 	orgLookupSvc := query.FromOrganizationService(orgSvc)
+	reader := reads.NewReader(newStore(engine))
 	err := influxdb.InjectFromDependencies(cc.ExecutorDependencies, influxdb.Dependencies{
-		Reader:             reads.NewReader(newStore(engine)),
+		Reader:             reader,
 		BucketLookup:       bucketLookupSvc,
 		OrganizationLookup: orgLookupSvc,
 	})
@@ -39,6 +41,14 @@ func AddControllerConfigDependencies(
 		return err
 	}
 
+	err = schema.InjectDependencies(cc.ExecutorDependencies, schema.Dependencies{
+		Reader:       reader,
+		BucketLookup: bucketLookupSvc,
+	})
+	if err != nil {
+		return err
+	}
+
 	if err := influxdb.InjectBucketDependencies(cc.ExecutorDependencies, bucketLookupSvc); err != nil {
 		return err
 	}
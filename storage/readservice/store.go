@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"math"
+	"sort"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
 )
 
 type store struct {
@@ -35,7 +39,7 @@ func (s *store) Read(ctx context.Context, req *datatypes.ReadRequest) (reads.Res
 		req.PointsLimit = math.MaxInt64
 	}
 
-	source, err := getReadSource(req)
+	source, err := getReadSource(req.ReadSource)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +81,7 @@ func (s *store) GroupRead(ctx context.Context, req *datatypes.ReadRequest) (read
 		req.PointsLimit = math.MaxInt64
 	}
 
-	source, err := getReadSource(req)
+	source, err := getReadSource(req.ReadSource)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +105,84 @@ func (s *store) GroupRead(ctx context.Context, req *datatypes.ReadRequest) (read
 	return reads.NewGroupResultSet(ctx, req, newCursor), nil
 }
 
+func (s *store) TagKeys(ctx context.Context, req *reads.TagKeysRequest) (cursors.StringIterator, error) {
+	source, err := getReadSource(req.ReadSource)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := timeRange(req.Range)
+	predicate, err := schemaPredicate(req.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	itr, err := s.engine.TagKeys(ctx, platform.ID(source.OrganizationID), platform.ID(source.BucketID), start, end, predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := cursors.StringIteratorToSlice(itr)
+	for i, key := range keys {
+		keys[i] = normalizeTagKey(key)
+	}
+	// the measurement and field keys may sort differently once renamed.
+	sort.Strings(keys)
+	return cursors.NewStringSliceIterator(keys), nil
+}
+
+func (s *store) TagValues(ctx context.Context, req *reads.TagValuesRequest) (cursors.StringIterator, error) {
+	source, err := getReadSource(req.ReadSource)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := timeRange(req.Range)
+	predicate, err := schemaPredicate(req.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.engine.TagValues(ctx, platform.ID(source.OrganizationID), platform.ID(source.BucketID), denormalizeTagKey(req.TagKey), start, end, predicate)
+}
+
+// timeRange returns the start and end of r, which default to the bounds
+// of the time range of the engine.
+func timeRange(r datatypes.TimestampRange) (start, end int64) {
+	start, end = r.Start, r.End
+	if start <= 0 {
+		start = models.MinNanoTime
+	}
+	if end <= 0 {
+		end = models.MaxNanoTime
+	}
+	return start, end
+}
+
+// schemaPredicate converts p to an expression over the tags of the index.
+// Comparisons of the field values cannot be answered from the index and
+// are ignored.
+func schemaPredicate(p *datatypes.Predicate) (influxql.Expr, error) {
+	root := p.GetRoot()
+	if root == nil {
+		return nil, nil
+	}
+
+	expr, err := reads.NodeToExpr(root, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if reads.HasFieldValueKey(expr) {
+		expr = reads.RewriteExprRemoveFieldValue(expr)
+	}
+	expr = influxql.Reduce(expr, nil)
+	if reads.IsTrueBooleanLiteral(expr) {
+		return nil, nil
+	}
+	return expr, nil
+}
+
 // this is easier than fooling around with .proto files.
 
 type readSource struct {
@@ -120,13 +202,13 @@ func (s *store) GetSource(rs influxdb.ReadSpec) (proto.Message, error) {
 	}, nil
 }
 
-func getReadSource(req *datatypes.ReadRequest) (*readSource, error) {
-	if req.ReadSource == nil {
+func getReadSource(any *types.Any) (*readSource, error) {
+	if any == nil {
 		return nil, errors.New("missing read source")
 	}
 
 	var source readSource
-	if err := types.UnmarshalAny(req.ReadSource, &source); err != nil {
+	if err := types.UnmarshalAny(any, &source); err != nil {
 		return nil, err
 	}
 	return &source, nil
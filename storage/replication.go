@@ -0,0 +1,398 @@
+package storage
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/influxdata/influxql"
+)
+
+var (
+	// ErrWALDisabled is returned when replicating an engine without a WAL.
+	ErrWALDisabled = errors.New("WAL is disabled")
+
+	// ErrReplicationDisabled is returned when replicating an engine whose WAL
+	// does not have the entries replicas need.
+	ErrReplicationDisabled = errors.New("replication is disabled")
+)
+
+// replicationPointsBatchSize is the number of points written at once when restoring
+// a replication snapshot.
+const replicationPointsBatchSize = 10000
+
+// ReplicationService describes the ability of a storage engine to be replicated
+// to followers.
+type ReplicationService interface {
+	// OpenWALSegment opens the WAL segment with the given ID.
+	OpenWALSegment(id int) (*tsm1.WALSegment, error)
+
+	// WALSizeFrom returns the number of bytes written to the WAL after offset in
+	// the WAL segment with the given ID.
+	WALSizeFrom(id int, offset int64) (int64, error)
+
+	// CreateReplicationSnapshot creates a snapshot of the TSM files.
+	CreateReplicationSnapshot() (*ReplicationSnapshot, error)
+}
+
+var _ ReplicationService = (*Engine)(nil)
+
+// OpenWALSegment opens the WAL segment with the given ID for reading by a follower.
+func (e *Engine) OpenWALSegment(id int) (*tsm1.WALSegment, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	} else if e.wal == nil {
+		return nil, ErrWALDisabled
+	} else if !e.wal.Replication() {
+		return nil, ErrReplicationDisabled
+	}
+	return e.wal.OpenSegment(id)
+}
+
+// WALSizeFrom returns the number of bytes written to the WAL after offset in the
+// WAL segment with the given ID.
+func (e *Engine) WALSizeFrom(id int, offset int64) (int64, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return 0, ErrEngineClosed
+	} else if e.wal == nil {
+		return 0, ErrWALDisabled
+	} else if !e.wal.Replication() {
+		return 0, ErrReplicationDisabled
+	}
+	return e.wal.SizeFrom(id, offset)
+}
+
+// ReplicationSnapshot is a snapshot of the TSM files of an engine.  The data of
+// the WAL segments older than Segment is included in the snapshot.
+type ReplicationSnapshot struct {
+	Segment int
+
+	dir string
+}
+
+// WriteTo writes the files of the snapshot to w as a tar archive.
+func (s *ReplicationSnapshot) WriteTo(w io.Writer) (int64, error) {
+	fis, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	cw := &countingWriter{w: w}
+	tw := tar.NewWriter(cw)
+	for _, fi := range fis {
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    fi.Name(),
+			Mode:    0666,
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+		}); err != nil {
+			return cw.n, err
+		}
+
+		if err := copyFile(tw, filepath.Join(s.dir, fi.Name())); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, tw.Close()
+}
+
+// Close removes the files of the snapshot.
+func (s *ReplicationSnapshot) Close() error {
+	return os.RemoveAll(s.dir)
+}
+
+// CreateReplicationSnapshot writes the cache to TSM files and creates a snapshot of
+// the TSM files.  The snapshot must be closed by the caller.
+func (e *Engine) CreateReplicationSnapshot() (*ReplicationSnapshot, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	} else if e.wal == nil {
+		return nil, ErrWALDisabled
+	} else if !e.wal.Replication() {
+		return nil, ErrReplicationDisabled
+	}
+
+	// Writing the cache first reduces the number of segments followers replay.
+	if err := e.engine.WriteSnapshot(); err != nil {
+		return nil, err
+	}
+
+	// Segments are only removed once their data is in TSM files, so the data of
+	// the segments older than the first one is in the files linked afterwards.
+	segment, err := e.wal.FirstSegmentID()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := e.engine.FileStore.CreateSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &ReplicationSnapshot{Segment: segment, dir: dir}, nil
+}
+
+// ApplyWALEntry applies an entry of the WAL of another engine.  Entries can be
+// applied more than once.
+func (e *Engine) ApplyWALEntry(entry tsm1.WALEntry) error {
+	switch entry := entry.(type) {
+	case *tsm1.WriteWALEntry:
+		var points []models.Point
+		for key, values := range entry.Values {
+			var err error
+			if points, err = appendValuePoints(points, []byte(key), values); err != nil {
+				return err
+			}
+		}
+		return e.WritePoints(points)
+
+	case *tsm1.DeleteRangeWALEntry:
+		return e.deleteSeriesKeysRange(entry.Keys, entry.Min, entry.Max)
+
+	case *tsm1.DeleteWALEntry:
+		return e.deleteSeriesKeysRange(entry.Keys, math.MinInt64, math.MaxInt64)
+
+	case *tsm1.DeleteBucketRangeWALEntry:
+		e.mu.RLock()
+		defer e.mu.RUnlock()
+		if e.closing == nil {
+			return ErrEngineClosed
+		}
+		return e.engine.DeleteBucket(entry.Name, entry.Min, entry.Max)
+
+	default:
+		return fmt.Errorf("unknown WAL entry type: %v", entry.Type())
+	}
+}
+
+// deleteSeriesKeysRange deletes the data between min and max of the series of the
+// given keys, with or without a field.
+func (e *Engine) deleteSeriesKeysRange(keys [][]byte, min, max int64) error {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+		set[string(seriesKey)] = struct{}{}
+	}
+
+	seriesKeys := make([][]byte, 0, len(set))
+	for key := range set {
+		seriesKeys = append(seriesKeys, []byte(key))
+	}
+	sort.Slice(seriesKeys, func(i, j int) bool { return bytes.Compare(seriesKeys[i], seriesKeys[j]) < 0 })
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+	return e.engine.DeleteSeriesRange(&seriesKeysIterator{keys: seriesKeys}, min, max)
+}
+
+// RestoreReplicationSnapshot replaces the data of the engine with the data of the
+// TSM files of a snapshot read from r as a tar archive.  Queries might return
+// partial results while the snapshot is restored.
+func (e *Engine) RestoreReplicationSnapshot(r io.Reader) error {
+	dir := filepath.Join(e.path, "replication.tmp")
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	} else if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := writeFile(filepath.Join(dir, filepath.Base(hdr.Name)), tr); err != nil {
+			return err
+		}
+	}
+
+	if err := e.deleteAll(); err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	// Newer generations are written last so that their values take precedence.
+	sort.Strings(files)
+	for _, path := range files {
+		if err := e.restoreTSMFile(path); err != nil {
+			return fmt.Errorf("error restoring %s: %v", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
+
+// deleteAll deletes all the data of the engine.
+func (e *Engine) deleteAll() error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	var names [][]byte
+	if err := e.engine.ForEachMeasurementName(func(name []byte) error {
+		names = append(names, append([]byte(nil), name...))
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := e.engine.DeleteBucket(name, math.MinInt64, math.MaxInt64); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreTSMFile writes the values of the TSM file at path to the engine.
+func (e *Engine) restoreTSMFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	defer r.Close()
+
+	points := make([]models.Point, 0, replicationPointsBatchSize)
+	iter := r.Iterator(nil)
+	for iter.Next() {
+		values, err := r.ReadAll(iter.Key())
+		if err != nil {
+			return err
+		}
+
+		if points, err = appendValuePoints(points, iter.Key(), values); err != nil {
+			return err
+		}
+		if len(points) >= replicationPointsBatchSize {
+			if err := e.WritePoints(points); err != nil {
+				return err
+			}
+			points = points[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(points) > 0 {
+		return e.WritePoints(points)
+	}
+	return nil
+}
+
+// appendValuePoints appends a point to points for each value of the composite key.
+func appendValuePoints(points []models.Point, key []byte, values []tsm1.Value) ([]models.Point, error) {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	name, tags := models.ParseKeyBytes(seriesKey)
+	for _, v := range values {
+		p, err := models.NewPoint(string(name), tags, models.Fields{string(field): v.Value()}, time.Unix(0, v.UnixNano()))
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// seriesKeysIterator iterates over a sorted list of series keys.
+type seriesKeysIterator struct {
+	keys [][]byte
+}
+
+func (itr *seriesKeysIterator) Close() error { return nil }
+
+func (itr *seriesKeysIterator) Next() (tsdb.SeriesElem, error) {
+	if len(itr.keys) == 0 {
+		return nil, nil
+	}
+
+	name, tags := models.ParseKeyBytes(itr.keys[0])
+	itr.keys = itr.keys[1:]
+	return seriesKeyElem{name: name, tags: tags}, nil
+}
+
+type seriesKeyElem struct {
+	name []byte
+	tags models.Tags
+}
+
+func (e seriesKeyElem) Name() []byte        { return e.name }
+func (e seriesKeyElem) Tags() models.Tags   { return e.tags }
+func (e seriesKeyElem) Deleted() bool       { return false }
+func (e seriesKeyElem) Expr() influxql.Expr { return nil }
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
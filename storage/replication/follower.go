@@ -0,0 +1,384 @@
+// Package replication implements followers of the storage engine of another influxd,
+// the leader.  Followers apply the entries of the WAL of the leader to their own
+// storage engine in order to serve reads.  A follower that falls behind further
+// than the WAL retained by the leader catches up by restoring a snapshot of the
+// TSM files of the leader.
+//
+// The metadata of the leader (organizations, buckets, users, authorizations,
+// tasks and dashboards) is restored from a snapshot at an interval, so that a
+// follower resolves the buckets and the tokens of the leader.  It is replaced
+// as a whole, so metadata must be changed on the leader.
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// DefaultPollInterval is the default interval at which a follower that applied
+// all the WAL of the leader checks for new entries.
+const DefaultPollInterval = time.Second
+
+// DefaultMetadataInterval is the default interval at which a follower restores
+// the metadata of the leader.
+const DefaultMetadataInterval = 10 * time.Second
+
+var (
+	// ErrSnapshotRequired is returned by a Leader when the WAL at a position is no
+	// longer available.
+	ErrSnapshotRequired = errors.New("replication snapshot required")
+
+	// ErrReadOnly is returned when writing points to a follower.
+	ErrReadOnly = &platform.Error{
+		Code: platform.EForbidden,
+		Msg:  "writes are not accepted by a replication follower",
+	}
+)
+
+// Position is a position in the WAL of the leader.
+type Position struct {
+	Segment int   `json:"segment"`
+	Offset  int64 `json:"offset"`
+}
+
+// WALSegment is a WAL segment of the leader read from a position.
+type WALSegment struct {
+	io.ReadCloser
+
+	// Closed is true if no more entries will be written to the segment.
+	Closed bool
+
+	// Size is the size of the segment.
+	Size int64
+
+	// Lag is the number of bytes written to the WAL after the position read from.
+	Lag int64
+}
+
+// Snapshot is a snapshot of the TSM files of the leader as a tar archive.
+type Snapshot struct {
+	io.ReadCloser
+
+	// Segment is the ID of the first WAL segment whose data is not in the snapshot.
+	Segment int
+}
+
+// Leader is the influxd followed.
+type Leader interface {
+	// ReadWAL reads the WAL from a position.  ErrSnapshotRequired is returned if
+	// the segment of the position has been removed.
+	ReadWAL(ctx context.Context, pos Position) (*WALSegment, error)
+
+	// Snapshot returns a snapshot of the TSM files.
+	Snapshot(ctx context.Context) (*Snapshot, error)
+
+	// Metadata returns a snapshot of the metadata store.
+	Metadata(ctx context.Context) (io.ReadCloser, error)
+}
+
+// Engine is the storage engine of a follower.
+type Engine interface {
+	ApplyWALEntry(entry tsm1.WALEntry) error
+	RestoreReplicationSnapshot(r io.Reader) error
+}
+
+// Follower applies the WAL of a leader to an engine, and restores the metadata
+// of the leader to a metadata store.
+type Follower struct {
+	Leader Leader
+	Engine Engine
+
+	// Metadata is the metadata store of the follower.  The metadata of the
+	// leader is not restored if it is nil.
+	Metadata MetadataStore
+
+	// PollInterval is the interval at which the WAL of the leader is checked for
+	// new entries once all of it has been applied.
+	PollInterval time.Duration
+
+	// MetadataInterval is the interval at which the metadata of the leader is
+	// restored.
+	MetadataInterval time.Duration
+
+	// path is the file the position of the follower is stored in.
+	path string
+
+	mu           sync.Mutex
+	pos          *Position
+	caughtUp     time.Time
+	metadataHash []byte
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	logger       *zap.Logger
+	metrics      *followerMetrics
+}
+
+// NewFollower returns a Follower storing its position in the file at path.
+func NewFollower(leader Leader, engine Engine, path string) *Follower {
+	return &Follower{
+		Leader:           leader,
+		Engine:           engine,
+		PollInterval:     DefaultPollInterval,
+		MetadataInterval: DefaultMetadataInterval,
+		path:             path,
+		logger:           zap.NewNop(),
+		metrics:          newFollowerMetrics(),
+	}
+}
+
+// WithLogger sets the logger l on the follower. It must be called before Open.
+func (f *Follower) WithLogger(l *zap.Logger) {
+	f.logger = l.With(zap.String("component", "replication_follower"))
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (f *Follower) PrometheusCollectors() []prometheus.Collector {
+	return f.metrics.PrometheusCollectors()
+}
+
+// Open loads the position of the follower and starts following the leader.
+func (f *Follower) Open() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pos, err := readPosition(f.path)
+	if err != nil {
+		return err
+	}
+	f.pos = pos
+	f.caughtUp = time.Now()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.run(ctx)
+	}()
+	if f.Metadata != nil {
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			f.runMetadata(ctx)
+		}()
+	}
+	return nil
+}
+
+// Close stops following the leader.
+func (f *Follower) Close() error {
+	f.mu.Lock()
+	cancel := f.cancel
+	f.cancel = nil
+	f.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		f.wg.Wait()
+	}
+	return nil
+}
+
+// Position returns the position in the WAL of the leader up to which entries have
+// been applied, and false if the follower has not restored a snapshot yet.
+func (f *Follower) Position() (Position, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pos == nil {
+		return Position{}, false
+	}
+	return *f.pos, true
+}
+
+func (f *Follower) run(ctx context.Context) {
+	for {
+		caughtUp, err := f.follow(ctx)
+		if err == ErrSnapshotRequired {
+			err = f.restore(ctx)
+		}
+
+		var wait time.Duration
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			f.metrics.Errors.Inc()
+			f.logger.Info("Error following leader", zap.Error(err))
+			wait = f.PollInterval
+		} else if caughtUp {
+			wait = f.PollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// follow applies the entries of the WAL segment of the leader at the position of
+// the follower and returns true if it should wait for new entries.
+func (f *Follower) follow(ctx context.Context) (bool, error) {
+	pos, ok := f.Position()
+	if !ok {
+		return false, ErrSnapshotRequired
+	}
+
+	segment, err := f.Leader.ReadWAL(ctx, pos)
+	if err != nil {
+		return false, err
+	}
+	defer segment.Close()
+
+	var (
+		n       int64
+		readErr error
+	)
+	r := tsm1.NewWALSegmentReader(segment)
+	for r.Next() {
+		entry, err := r.Read()
+		if err != nil {
+			// The last entry of a segment being written might be incomplete.
+			readErr = err
+			break
+		}
+
+		if err := f.Engine.ApplyWALEntry(entry); err != nil {
+			if _, ok := err.(tsdb.PartialWriteError); !ok {
+				if perr := f.setPosition(Position{Segment: pos.Segment, Offset: pos.Offset + n}); perr != nil {
+					f.logger.Info("Error storing position", zap.Error(perr))
+				}
+				return false, err
+			}
+			f.logger.Info("Points of WAL entry dropped", zap.Error(err))
+		}
+		n = r.Count()
+		f.metrics.AppliedEntries.Inc()
+	}
+
+	next := Position{Segment: pos.Segment, Offset: pos.Offset + n}
+	if segment.Closed && next.Offset >= segment.Size {
+		next = Position{Segment: pos.Segment + 1}
+	}
+	if err := f.setPosition(next); err != nil {
+		return false, err
+	}
+	if readErr != nil && segment.Closed && next.Segment == pos.Segment {
+		return false, fmt.Errorf("error reading WAL segment %d at offset %d: %v", pos.Segment, next.Offset, readErr)
+	}
+
+	lag := segment.Lag - n
+	if lag < 0 {
+		lag = 0
+	}
+	f.updateLag(lag)
+
+	// Wait for new entries if none could be applied.
+	return lag == 0 || next == pos, nil
+}
+
+// restore replaces the data of the engine with a snapshot of the leader.
+func (f *Follower) restore(ctx context.Context) error {
+	f.logger.Info("Restoring snapshot of leader")
+
+	snapshot, err := f.Leader.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+
+	// Forget the position first, the data of the engine is partial until the
+	// snapshot is restored.
+	f.mu.Lock()
+	f.pos = nil
+	f.mu.Unlock()
+	if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := f.Engine.RestoreReplicationSnapshot(snapshot); err != nil {
+		return err
+	}
+	f.metrics.Snapshots.Inc()
+
+	f.logger.Info("Restored snapshot of leader", zap.Int("segment", snapshot.Segment))
+	return f.setPosition(Position{Segment: snapshot.Segment})
+}
+
+func (f *Follower) setPosition(pos Position) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pos != nil && *f.pos == pos {
+		return nil
+	}
+	f.pos = &pos
+	return writePosition(f.path, pos)
+}
+
+func (f *Follower) updateLag(lag int64) {
+	now := time.Now()
+
+	f.mu.Lock()
+	if lag == 0 {
+		f.caughtUp = now
+	}
+	caughtUp := f.caughtUp
+	f.mu.Unlock()
+
+	f.metrics.LagBytes.Set(float64(lag))
+	f.metrics.LagSeconds.Set(now.Sub(caughtUp).Seconds())
+}
+
+// readPosition reads the position stored in the file at path, or returns nil if
+// the file does not exist.
+func readPosition(path string) (*Position, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var pos Position
+	if err := json.Unmarshal(b, &pos); err != nil {
+		return nil, err
+	}
+	return &pos, nil
+}
+
+// writePosition atomically stores pos in the file at path.
+func writePosition(path string, pos Position) error {
+	b, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// PointsWriter rejects the points written to a follower.
+type PointsWriter struct{}
+
+// WritePoints returns ErrReadOnly.
+func (PointsWriter) WritePoints(points []models.Point) error {
+	return ErrReadOnly
+}
@@ -0,0 +1,283 @@
+package replication_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/storage/replication"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestFollower(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication_follower_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	leader := &fakeLeader{
+		snapshot: 2,
+		segments: map[int][]byte{
+			2: mustEncodeSegment(&tsm1.DeleteBucketRangeWALEntry{Name: []byte("a"), Max: 1}),
+			3: mustEncodeSegment(&tsm1.DeleteBucketRangeWALEntry{Name: []byte("b"), Max: 1}),
+		},
+		writing: 3,
+	}
+	engine := &fakeEngine{}
+
+	path := filepath.Join(dir, "replication.json")
+	f := replication.NewFollower(leader, engine, path)
+	f.PollInterval = time.Millisecond
+	if err := f.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := replication.Position{Segment: 3, Offset: int64(len(leader.segments[3]))}
+	waitPosition(t, f, exp)
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := engine.Restores(), 1; got != exp {
+		t.Fatalf("got %d restores, exp %d", got, exp)
+	}
+	if got, exp := engine.Names(), []string{"a", "b"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got entries %v, exp %v", got, exp)
+	}
+
+	// The position is kept when the follower is reopened.
+	leader.mu.Lock()
+	leader.segments[3] = append(leader.segments[3], mustEncodeSegment(&tsm1.DeleteBucketRangeWALEntry{Name: []byte("c"), Max: 1})...)
+	leader.mu.Unlock()
+
+	f = replication.NewFollower(leader, engine, path)
+	f.PollInterval = time.Millisecond
+	if err := f.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	exp = replication.Position{Segment: 3, Offset: int64(len(leader.segments[3]))}
+	waitPosition(t, f, exp)
+
+	if got, exp := engine.Restores(), 1; got != exp {
+		t.Fatalf("got %d restores, exp %d", got, exp)
+	}
+	if got, exp := engine.Names(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got entries %v, exp %v", got, exp)
+	}
+}
+
+func TestFollower_Metadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication_follower_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	leader := &fakeLeader{
+		segments: map[int][]byte{},
+		metadata: []byte("a"),
+	}
+	metadata := &fakeMetadataStore{}
+
+	f := replication.NewFollower(leader, &fakeEngine{}, filepath.Join(dir, "replication.json"))
+	f.Metadata = metadata
+	f.PollInterval = time.Millisecond
+	f.MetadataInterval = time.Millisecond
+	if err := f.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	waitMetadata(t, metadata, []string{"a"})
+
+	// Metadata is only restored when it changes.
+	leader.mu.Lock()
+	leader.metadata = []byte("b")
+	leader.mu.Unlock()
+	waitMetadata(t, metadata, []string{"a", "b"})
+	time.Sleep(10 * time.Millisecond)
+	if got, exp := metadata.Restored(), []string{"a", "b"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got restores %v, exp %v", got, exp)
+	}
+}
+
+func TestPointsWriter(t *testing.T) {
+	if err := (replication.PointsWriter{}).WritePoints(nil); err != replication.ErrReadOnly {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func waitPosition(t *testing.T, f *replication.Follower, exp replication.Position) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		if pos, ok := f.Position(); ok && pos == exp {
+			return
+		}
+
+		select {
+		case <-timeout:
+			pos, _ := f.Position()
+			t.Fatalf("timed out waiting for position: got %v, exp %v", pos, exp)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func waitMetadata(t *testing.T, s *fakeMetadataStore, exp []string) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		if got := s.Restored(); reflect.DeepEqual(got, exp) {
+			return
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("timed out waiting for metadata: got %v, exp %v", s.Restored(), exp)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+// fakeLeader is a leader whose WAL segments older than snapshot have been removed.
+type fakeLeader struct {
+	mu       sync.Mutex
+	snapshot int
+	segments map[int][]byte
+	writing  int
+	metadata []byte
+}
+
+func (l *fakeLeader) ReadWAL(ctx context.Context, pos replication.Position) (*replication.WALSegment, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if pos.Segment < l.snapshot || pos.Segment > l.writing {
+		return nil, replication.ErrSnapshotRequired
+	}
+
+	b := l.segments[pos.Segment]
+	var lag int64
+	for id, segment := range l.segments {
+		if id >= pos.Segment {
+			lag += int64(len(segment))
+		}
+	}
+	return &replication.WALSegment{
+		ReadCloser: ioutil.NopCloser(bytes.NewReader(b[pos.Offset:])),
+		Closed:     pos.Segment < l.writing,
+		Size:       int64(len(b)),
+		Lag:        lag - pos.Offset,
+	}, nil
+}
+
+func (l *fakeLeader) Snapshot(ctx context.Context) (*replication.Snapshot, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return &replication.Snapshot{
+		ReadCloser: ioutil.NopCloser(bytes.NewReader(nil)),
+		Segment:    l.snapshot,
+	}, nil
+}
+
+func (l *fakeLeader) Metadata(ctx context.Context) (io.ReadCloser, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ioutil.NopCloser(bytes.NewReader(l.metadata)), nil
+}
+
+type fakeEngine struct {
+	mu       sync.Mutex
+	names    []string
+	restores int
+}
+
+func (e *fakeEngine) ApplyWALEntry(entry tsm1.WALEntry) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.names = append(e.names, string(entry.(*tsm1.DeleteBucketRangeWALEntry).Name))
+	return nil
+}
+
+func (e *fakeEngine) RestoreReplicationSnapshot(r io.Reader) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.restores++
+	return nil
+}
+
+func (e *fakeEngine) Names() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.names...)
+}
+
+func (e *fakeEngine) Restores() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.restores
+}
+
+type fakeMetadataStore struct {
+	mu       sync.Mutex
+	restored []string
+}
+
+func (s *fakeMetadataStore) Backup(w io.Writer) error {
+	return nil
+}
+
+func (s *fakeMetadataStore) Restore(r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restored = append(s.restored, string(b))
+	return nil
+}
+
+func (s *fakeMetadataStore) Restored() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.restored...)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func mustEncodeSegment(entries ...tsm1.WALEntry) []byte {
+	var buf bytes.Buffer
+	w := tsm1.NewWALSegmentWriter(nopWriteCloser{&buf})
+	for _, entry := range entries {
+		b, err := entry.Encode(nil)
+		if err != nil {
+			panic(err)
+		}
+		if err := w.Write(entry.Type(), snappy.Encode(nil, b)); err != nil {
+			panic(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
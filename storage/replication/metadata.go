@@ -0,0 +1,66 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MetadataStore is the metadata store of an influxd: its organizations,
+// buckets, users, authorizations, tasks and dashboards.
+type MetadataStore interface {
+	// Backup writes a snapshot of the metadata to w.
+	Backup(w io.Writer) error
+
+	// Restore replaces the metadata with a snapshot written by Backup.
+	Restore(r io.Reader) error
+}
+
+func (f *Follower) runMetadata(ctx context.Context) {
+	for {
+		if err := f.restoreMetadata(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			f.metrics.Errors.Inc()
+			f.logger.Info("Error restoring metadata of leader", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.MetadataInterval):
+		}
+	}
+}
+
+// restoreMetadata restores the metadata of the leader, unless it has not changed
+// since it was last restored.
+func (f *Follower) restoreMetadata(ctx context.Context) error {
+	rc, err := f.Leader.Metadata(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+
+	h := sha256.Sum256(b)
+	if bytes.Equal(h[:], f.metadataHash) {
+		return nil
+	}
+	if err := f.Metadata.Restore(bytes.NewReader(b)); err != nil {
+		return err
+	}
+	f.metadataHash = h[:]
+	f.metrics.MetadataRestores.Inc()
+	return nil
+}
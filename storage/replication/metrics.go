@@ -0,0 +1,79 @@
+package replication
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace is the leading part of all published metrics for the Storage service.
+const namespace = "storage"
+
+const replicationSubsystem = "replication" // sub-system associated with metrics for following a leader.
+
+// followerMetrics is a set of metrics concerned with tracking how far a follower is
+// behind its leader.
+type followerMetrics struct {
+	LagBytes         prometheus.Gauge
+	LagSeconds       prometheus.Gauge
+	AppliedEntries   prometheus.Counter
+	Snapshots        prometheus.Counter
+	MetadataRestores prometheus.Counter
+	Errors           prometheus.Counter
+}
+
+func newFollowerMetrics() *followerMetrics {
+	return &followerMetrics{
+		LagBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: replicationSubsystem,
+			Name:      "lag_bytes",
+			Help:      "Number of bytes of the WAL of the leader not applied yet.",
+		}),
+
+		LagSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: replicationSubsystem,
+			Name:      "lag_seconds",
+			Help:      "Time since the follower last applied all the WAL of the leader.",
+		}),
+
+		AppliedEntries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: replicationSubsystem,
+			Name:      "applied_entries_total",
+			Help:      "Number of WAL entries of the leader applied.",
+		}),
+
+		Snapshots: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: replicationSubsystem,
+			Name:      "snapshots_total",
+			Help:      "Number of snapshots of the leader restored to catch up.",
+		}),
+
+		MetadataRestores: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: replicationSubsystem,
+			Name:      "metadata_restores_total",
+			Help:      "Number of snapshots of the metadata of the leader restored.",
+		}),
+
+		Errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: replicationSubsystem,
+			Name:      "errors_total",
+			Help:      "Number of errors following the leader.",
+		}),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *followerMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.LagBytes,
+		m.LagSeconds,
+		m.AppliedEntries,
+		m.Snapshots,
+		m.MetadataRestores,
+		m.Errors,
+	}
+}
@@ -0,0 +1,129 @@
+package storage_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestEngine_ApplyWALEntry(t *testing.T) {
+	leader := NewReplicationLeaderEngine()
+	defer leader.Close()
+	leader.MustOpen()
+
+	follower := NewDefaultEngine()
+	defer follower.Close()
+	follower.MustOpen()
+
+	pts := []models.Point{
+		models.MustNewPoint(
+			"cpu",
+			models.NewTags(map[string]string{"host": "server"}),
+			map[string]interface{}{"value": 1.0, "value2": 2.0},
+			time.Unix(1, 2),
+		),
+	}
+	if err := leader.Write1xPoints(pts); err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.Write1xPointsWithOrgBucket(pts, "3131313131313131", "8888888888888888"); err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.DeleteBucket(leader.org, leader.bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries can be applied more than once.
+	for i := 0; i < 2; i++ {
+		applyWAL(t, leader, follower)
+		if got, exp := follower.SeriesCardinality(), leader.SeriesCardinality(); got != exp {
+			t.Fatalf("got %d series, exp %d series in index", got, exp)
+		}
+	}
+	if got, exp := follower.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
+func TestEngine_RestoreReplicationSnapshot(t *testing.T) {
+	leader := NewReplicationLeaderEngine()
+	defer leader.Close()
+	leader.MustOpen()
+
+	follower := NewDefaultEngine()
+	defer follower.Close()
+	follower.MustOpen()
+
+	pt := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "server"}),
+		map[string]interface{}{"value": 1.0, "value2": 2.0},
+		time.Unix(1, 2),
+	)
+	if err := leader.Write1xPoints([]models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Data of the follower missing from the leader is removed.
+	if err := follower.Write1xPointsWithOrgBucket([]models.Point{pt}, "3131313131313131", "8888888888888888"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := leader.CreateReplicationSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snapshot.Close()
+
+	var buf bytes.Buffer
+	if _, err := snapshot.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := follower.RestoreReplicationSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := follower.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
+// applyWAL applies all the WAL segments of leader to follower.
+func applyWAL(t *testing.T, leader, follower *Engine) {
+	t.Helper()
+
+	// The first segment of a new WAL has ID 1.
+	for id := 1; ; id++ {
+		segment, err := leader.OpenWALSegment(id)
+		if err == tsm1.ErrWALSegmentNotFound {
+			return
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		r := tsm1.NewWALSegmentReader(ioutil.NopCloser(segment.NewReader(0)))
+		for r.Next() {
+			entry, err := r.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := follower.ApplyWALEntry(entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+		segment.Close()
+	}
+}
+
+// NewReplicationLeaderEngine returns a new engine that serves its WAL to
+// followers.
+func NewReplicationLeaderEngine() *Engine {
+	c := storage.NewConfig()
+	c.WAL.Replication = true
+	return NewEngine(c)
+}
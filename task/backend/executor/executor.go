@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/secrets"
 	"github.com/influxdata/influxdb/task/backend"
 	"go.uber.org/zap"
 )
@@ -140,7 +141,7 @@ func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 			Spec: spec,
 		},
 	}
-	it, err := p.svc.Query(p.ctx, req)
+	it, err := p.svc.Query(taskContext(p.ctx, p.t), req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
 		p.finish(nil, err)
@@ -207,7 +208,7 @@ func (e *asyncQueryServiceExecutor) Execute(ctx context.Context, run backend.Que
 			Spec: spec,
 		},
 	}
-	q, err := e.svc.Query(ctx, req)
+	q, err := e.svc.Query(taskContext(ctx, t), req)
 	if err != nil {
 		return nil, err
 	}
@@ -347,3 +348,13 @@ func exhaustResultIterators(res flux.Result) error {
 		})
 	})
 }
+
+// taskContext returns the context of the query of a run of t.  The runs of
+// tasks that import the secrets package may resolve secrets; the task
+// validator checked the permission when the script was set.
+func taskContext(ctx context.Context, t *backend.StoreTask) context.Context {
+	if secrets.Imported(t.Script) {
+		return secrets.ContextWithTaskOwner(ctx, t.User)
+	}
+	return ctx
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/check"
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -282,6 +283,27 @@ func (s *TickScheduler) Stop() {
 	s.executor.Wait()
 }
 
+// CheckName returns the name of the scheduler's health check.
+func (s *TickScheduler) CheckName() string {
+	return "scheduler"
+}
+
+// Check reports whether the scheduler is started and not stopped, with the
+// number of claimed tasks and the time of the last tick.
+func (s *TickScheduler) Check(ctx context.Context) check.Response {
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
+	if s.ctx == nil {
+		return check.Error(errors.New("scheduler is not started"))
+	}
+	if s.ctx.Err() != nil {
+		return check.Error(errors.New("scheduler is stopped"))
+	}
+	now := time.Unix(atomic.LoadInt64(&s.now), 0).UTC()
+	return check.Info("%d tasks claimed, last tick at %s", len(s.taskSchedulers), now.Format(time.RFC3339))
+}
+
 func (s *TickScheduler) ClaimTask(task *StoreTask, meta *StoreTaskMeta) (err error) {
 	s.schedulerMu.Lock()
 	defer s.schedulerMu.Unlock()
@@ -631,6 +653,8 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 	defer r.wg.Done()
 
 	sp, spCtx := opentracing.StartSpanFromContext(ctx, "task.run.execution")
+	sp.SetTag("task_id", qr.TaskID.String())
+	sp.SetTag("run_id", qr.RunID.String())
 	defer sp.Finish()
 
 	rp, err := r.executor.Execute(spCtx, qr)
@@ -10,6 +10,7 @@ import (
 	platform "github.com/influxdata/influxdb"
 	platcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/secrets"
 )
 
 type authError struct {
@@ -80,7 +81,11 @@ func (ts *taskServiceValidator) CreateTask(ctx context.Context, t *platform.Task
 		return err
 	}
 
-	if err := validateBucket(ctx, t.Flux, ts.preAuth); err != nil {
+	if err := validateBucket(ctx, t.Flux, t.Organization, ts.preAuth); err != nil {
+		return err
+	}
+
+	if err := validateSecrets(ctx, t.Flux, t.Organization); err != nil {
 		return err
 	}
 
@@ -102,7 +107,15 @@ func (ts *taskServiceValidator) UpdateTask(ctx context.Context, id platform.ID,
 		return nil, err
 	}
 
-	if err := validateBucket(ctx, task.Flux, ts.preAuth); err != nil {
+	script := task.Flux
+	if upd.Flux != nil {
+		script = *upd.Flux
+	}
+	if err := validateBucket(ctx, script, task.Organization, ts.preAuth); err != nil {
+		return nil, err
+	}
+
+	if err := validateSecrets(ctx, script, task.Organization); err != nil {
 		return nil, err
 	}
 
@@ -246,7 +259,7 @@ func validatePermission(ctx context.Context, perm platform.Permission) error {
 	return nil
 }
 
-func validateBucket(ctx context.Context, script string, preAuth query.PreAuthorizer) error {
+func validateBucket(ctx context.Context, script string, orgID platform.ID, preAuth query.PreAuthorizer) error {
 	auth, err := platcontext.GetAuthorizer(ctx)
 	if err != nil {
 		return err
@@ -260,7 +273,7 @@ func validateBucket(ctx context.Context, script string, preAuth query.PreAuthori
 			platform.WithErrorCode(platform.EInvalid))
 	}
 
-	if err := preAuth.PreAuthorize(ctx, spec, auth); err != nil {
+	if err := preAuth.PreAuthorize(ctx, spec, auth, orgID); err != nil {
 		return platform.NewError(
 			platform.WithErrorErr(err),
 			platform.WithErrorMsg("Failed to authorize."),
@@ -269,3 +282,17 @@ func validateBucket(ctx context.Context, script string, preAuth query.PreAuthori
 
 	return nil
 }
+
+// validateSecrets ensures that the authorizer can read the secrets of the
+// organization when the script imports the secrets package, since the runs
+// of the task resolve them without an authorization.
+func validateSecrets(ctx context.Context, script string, orgID platform.ID) error {
+	if !secrets.Imported(script) {
+		return nil
+	}
+	p, err := platform.NewPermission(platform.ReadAction, platform.SecretsResourceType, orgID)
+	if err != nil {
+		return err
+	}
+	return validatePermission(ctx, *p)
+}
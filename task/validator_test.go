@@ -15,7 +15,7 @@ import (
 
 func TestOnboardingValidation(t *testing.T) {
 	svc := inmem.NewService()
-	validator := task.NewValidator(mockTaskService(), svc)
+	validator := task.NewValidator(mockTaskService(influxdb.ID(1)), svc)
 
 	r, err := svc.Generate(context.Background(), &influxdb.OnboardingRequest{
 		User:            "dude",
@@ -44,10 +44,10 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 	}
 }
 
-func mockTaskService() influxdb.TaskService {
+func mockTaskService(orgID influxdb.ID) influxdb.TaskService {
 	task := influxdb.Task{
 		ID:           influxdb.ID(2),
-		Organization: influxdb.ID(1),
+		Organization: orgID,
 		Name:         "cows",
 		Owner:        influxdb.User{ID: influxdb.ID(3), Name: "farmer"},
 		Flux: `option task = {
@@ -110,7 +110,6 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 
 func TestValidations(t *testing.T) {
 	inmem := inmem.NewService()
-	validTaskService := task.NewValidator(mockTaskService(), inmem)
 
 	r, err := inmem.Generate(context.Background(), &influxdb.OnboardingRequest{
 		User:            "dude",
@@ -119,14 +118,15 @@ func TestValidations(t *testing.T) {
 		Bucket:          "holder",
 		RetentionPeriod: 1,
 	})
-
-	orgID := influxdb.ID(1)
-	taskID := influxdb.ID(2)
-
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	// The task reads the bucket of the onboarded organization.
+	orgID := r.Org.ID
+	taskID := influxdb.ID(2)
+	validTaskService := task.NewValidator(mockTaskService(orgID), inmem)
+
 	tests := []struct {
 		name  string
 		check func(context.Context, influxdb.TaskService) error
@@ -192,6 +192,50 @@ from(bucket:"bad") |> range(start:-5m) |> to(bucket:"bad", org:"thing")`,
 				return nil
 			},
 		},
+		{
+			name: "create secrets without permission",
+			auth: &influxdb.Authorization{Status: "active", Permissions: []influxdb.Permission{
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &r.Org.ID}},
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &r.Org.ID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &r.Org.ID}},
+			}},
+			check: func(ctx context.Context, svc influxdb.TaskService) error {
+				err := svc.CreateTask(ctx, &influxdb.Task{
+					Organization: r.Org.ID,
+					Name:         "cows",
+					Owner:        *r.User,
+					Flux: `import "influxdata/influxdb/secrets"
+option task = {
+ name: "my_task",
+ every: 1s,
+}
+from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing", host: "https://example.com", token: secrets.get(key: "token"))`,
+					Every: "1s",
+				})
+				if err == nil {
+					return errors.New("created task without secrets permission")
+				}
+				return nil
+			},
+		},
+		{
+			name: "create secrets success",
+			auth: r.Auth,
+			check: func(ctx context.Context, svc influxdb.TaskService) error {
+				return svc.CreateTask(ctx, &influxdb.Task{
+					Organization: r.Org.ID,
+					Name:         "cows",
+					Owner:        *r.User,
+					Flux: `import "influxdata/influxdb/secrets"
+option task = {
+ name: "my_task",
+ every: 1s,
+}
+from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing", host: "https://example.com", token: secrets.get(key: "token"))`,
+					Every: "1s",
+				})
+			},
+		},
 		{
 			name: "FindTaskByID missing auth",
 			auth: &influxdb.Authorization{Permissions: []influxdb.Permission{}},
@@ -3,13 +3,16 @@ package influxdb
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb/telegraf/plugins"
+	"github.com/influxdata/influxdb/telegraf/plugins/aggregators"
 	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
 	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+	"github.com/influxdata/influxdb/telegraf/plugins/processors"
 )
 
 // ErrTelegrafConfigInvalidOrganizationID is the error message for a missing or invalid organization ID.
@@ -68,8 +71,9 @@ type TelegrafConfig struct {
 	OrganizationID ID
 	Name           string
 
-	Agent   TelegrafAgentConfig
-	Plugins []TelegrafPlugin
+	Agent      TelegrafAgentConfig
+	GlobalTags map[string]string
+	Plugins    []TelegrafPlugin
 }
 
 // TOML returns the telegraf toml config string.
@@ -78,71 +82,39 @@ func (tc TelegrafConfig) TOML() string {
 	for _, p := range tc.Plugins {
 		plugins += p.Config.TOML()
 	}
-	interval := time.Duration(tc.Agent.Interval * 1000000)
-	return fmt.Sprintf(`# Configuration for telegraf agent
-[agent]
-  ## Default data collection interval for all inputs
-  interval = "%s"
-  ## Rounds collection interval to 'interval'
-  ## ie, if interval="10s" then always collect on :00, :10, :20, etc.
-  round_interval = true
-
-  ## Telegraf will send metrics to outputs in batches of at most
-  ## metric_batch_size metrics.
-  ## This controls the size of writes that Telegraf sends to output plugins.
-  metric_batch_size = 1000
-
-  ## For failed writes, telegraf will cache metric_buffer_limit metrics for each
-  ## output, and will flush this buffer on a successful write. Oldest metrics
-  ## are dropped first when this buffer fills.
-  ## This buffer only fills when writes fail to output plugin(s).
-  metric_buffer_limit = 10000
-
-  ## Collection jitter is used to jitter the collection by a random amount.
-  ## Each plugin will sleep for a random time within jitter before collecting.
-  ## This can be used to avoid many plugins querying things like sysfs at the
-  ## same time, which can have a measurable effect on the system.
-  collection_jitter = "0s"
-
-  ## Default flushing interval for all outputs. Maximum flush_interval will be
-  ## flush_interval + flush_jitter
-  flush_interval = "10s"
-  ## Jitter the flush interval by a random amount. This is primarily to avoid
-  ## large write spikes for users running a large number of telegraf instances.
-  ## ie, a jitter of 5s and interval 10s means flushes will happen every 10-15s
-  flush_jitter = "0s"
-
-  ## By default or when set to "0s", precision will be set to the same
-  ## timestamp order as the collection interval, with the maximum being 1s.
-  ##   ie, when interval = "10s", precision will be "1s"
-  ##       when interval = "250ms", precision will be "1ms"
-  ## Precision will NOT be used for service inputs. It is up to each individual
-  ## service input to set the timestamp at the appropriate precision.
-  ## Valid time units are "ns", "us" (or "µs"), "ms", "s".
-  precision = ""
-
-  ## Logging configuration:
-  ## Run telegraf with debug log messages.
-  debug = false
-  ## Run telegraf in quiet mode (error log messages only).
-  quiet = false
-  ## Specify the log file name. The empty string means to log to stderr.
-  logfile = ""
+	return tc.globalTagsTOML() + tc.Agent.TOML() + plugins
+}
 
-  ## Override default hostname, if empty use os.Hostname()
-  hostname = ""
-  ## If set to true, do no set the "host" tag in the telegraf agent.
-  omit_hostname = false
-%s`, interval.String(), plugins)
+// globalTagsTOML returns the global_tags table of the config, or nothing if
+// it has no global tags.
+func (tc TelegrafConfig) globalTagsTOML() string {
+	if len(tc.GlobalTags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(tc.GlobalTags))
+	for k := range tc.GlobalTags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(`# Global tags added to all metrics
+[global_tags]
+`)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "  %q = %q\n", k, tc.GlobalTags[k])
+	}
+	return b.String()
 }
 
 // telegrafConfigEncode is the helper struct for json encoding.
 type telegrafConfigEncode struct {
-	ID             ID     `json:"id"`
+	ID             ID     `json:"id,omitempty"`
 	OrganizationID ID     `json:"organizationID,omitempty"`
 	Name           string `json:"name"`
 
-	Agent TelegrafAgentConfig `json:"agent"`
+	Agent      TelegrafAgentConfig `json:"agent"`
+	GlobalTags map[string]string   `json:"globalTags,omitempty"`
 
 	Plugins []telegrafPluginEncode `json:"plugins"`
 }
@@ -158,11 +130,12 @@ type telegrafPluginEncode struct {
 
 // telegrafConfigDecode is the helper struct for json decoding.
 type telegrafConfigDecode struct {
-	ID             ID     `json:"id"`
+	ID             ID     `json:"id,omitempty"`
 	OrganizationID ID     `json:"organizationID,omitempty"`
 	Name           string `json:"name"`
 
-	Agent TelegrafAgentConfig `json:"agent"`
+	Agent      TelegrafAgentConfig `json:"agent"`
+	GlobalTags map[string]string   `json:"globalTags,omitempty"`
 
 	Plugins []telegrafPluginDecode `json:"plugins"`
 }
@@ -182,10 +155,114 @@ type TelegrafPlugin struct {
 	Config  plugins.Config `json:"config"`
 }
 
-// TelegrafAgentConfig is based telegraf/internal/config AgentConfig.
+// TelegrafAgentConfig is based telegraf/internal/config AgentConfig. The
+// options that are zero have the telegraf default value.
 type TelegrafAgentConfig struct {
 	// Interval at which to gather information in miliseconds.
 	Interval int64 `json:"collectionInterval"`
+	// RoundInterval rounds collection times to Interval; true if nil.
+	RoundInterval *bool `json:"roundInterval,omitempty"`
+	// MetricBatchSize is the maximum number of metrics in a write to an
+	// output; 1000 if zero.
+	MetricBatchSize int64 `json:"metricBatchSize,omitempty"`
+	// MetricBufferLimit is the number of metrics cached for each output
+	// while writes fail; 10000 if zero.
+	MetricBufferLimit int64 `json:"metricBufferLimit,omitempty"`
+	// CollectionJitter is the maximum random delay of collections, as a
+	// duration such as "5s"; "0s" if empty.
+	CollectionJitter string `json:"collectionJitter,omitempty"`
+	// FlushInterval is the interval of the writes to outputs; "10s" if empty.
+	FlushInterval string `json:"flushInterval,omitempty"`
+	// FlushJitter is the maximum random delay of writes; "0s" if empty.
+	FlushJitter string `json:"flushJitter,omitempty"`
+	// Precision of the timestamps of the collected metrics; the precision of
+	// Interval if empty.
+	Precision    string `json:"precision,omitempty"`
+	Debug        bool   `json:"debug,omitempty"`
+	Quiet        bool   `json:"quiet,omitempty"`
+	Logfile      string `json:"logfile,omitempty"`
+	Hostname     string `json:"hostname,omitempty"`
+	OmitHostname bool   `json:"omitHostname,omitempty"`
+}
+
+// TOML returns the agent table of the telegraf toml config.
+func (a TelegrafAgentConfig) TOML() string {
+	interval := time.Duration(a.Interval * 1000000)
+	roundInterval := true
+	if a.RoundInterval != nil {
+		roundInterval = *a.RoundInterval
+	}
+	metricBatchSize := a.MetricBatchSize
+	if metricBatchSize == 0 {
+		metricBatchSize = 1000
+	}
+	metricBufferLimit := a.MetricBufferLimit
+	if metricBufferLimit == 0 {
+		metricBufferLimit = 10000
+	}
+	orDefault := func(s, def string) string {
+		if s == "" {
+			return def
+		}
+		return s
+	}
+	return fmt.Sprintf(`# Configuration for telegraf agent
+[agent]
+  ## Default data collection interval for all inputs
+  interval = "%s"
+  ## Rounds collection interval to 'interval'
+  ## ie, if interval="10s" then always collect on :00, :10, :20, etc.
+  round_interval = %t
+
+  ## Telegraf will send metrics to outputs in batches of at most
+  ## metric_batch_size metrics.
+  ## This controls the size of writes that Telegraf sends to output plugins.
+  metric_batch_size = %d
+
+  ## For failed writes, telegraf will cache metric_buffer_limit metrics for each
+  ## output, and will flush this buffer on a successful write. Oldest metrics
+  ## are dropped first when this buffer fills.
+  ## This buffer only fills when writes fail to output plugin(s).
+  metric_buffer_limit = %d
+
+  ## Collection jitter is used to jitter the collection by a random amount.
+  ## Each plugin will sleep for a random time within jitter before collecting.
+  ## This can be used to avoid many plugins querying things like sysfs at the
+  ## same time, which can have a measurable effect on the system.
+  collection_jitter = %q
+
+  ## Default flushing interval for all outputs. Maximum flush_interval will be
+  ## flush_interval + flush_jitter
+  flush_interval = %q
+  ## Jitter the flush interval by a random amount. This is primarily to avoid
+  ## large write spikes for users running a large number of telegraf instances.
+  ## ie, a jitter of 5s and interval 10s means flushes will happen every 10-15s
+  flush_jitter = %q
+
+  ## By default or when set to "0s", precision will be set to the same
+  ## timestamp order as the collection interval, with the maximum being 1s.
+  ##   ie, when interval = "10s", precision will be "1s"
+  ##       when interval = "250ms", precision will be "1ms"
+  ## Precision will NOT be used for service inputs. It is up to each individual
+  ## service input to set the timestamp at the appropriate precision.
+  ## Valid time units are "ns", "us" (or "µs"), "ms", "s".
+  precision = %q
+
+  ## Logging configuration:
+  ## Run telegraf with debug log messages.
+  debug = %t
+  ## Run telegraf in quiet mode (error log messages only).
+  quiet = %t
+  ## Specify the log file name. The empty string means to log to stderr.
+  logfile = %q
+
+  ## Override default hostname, if empty use os.Hostname()
+  hostname = %q
+  ## If set to true, do no set the "host" tag in the telegraf agent.
+  omit_hostname = %t
+`, interval.String(), roundInterval, metricBatchSize, metricBufferLimit,
+		orDefault(a.CollectionJitter, "0s"), orDefault(a.FlushInterval, "10s"), orDefault(a.FlushJitter, "0s"),
+		a.Precision, a.Debug, a.Quiet, a.Logfile, a.Hostname, a.OmitHostname)
 }
 
 // errors
@@ -204,6 +281,7 @@ func (tc *TelegrafConfig) MarshalJSON() ([]byte, error) {
 		OrganizationID: tc.OrganizationID,
 		Name:           tc.Name,
 		Agent:          tc.Agent,
+		GlobalTags:     tc.GlobalTags,
 		Plugins:        make([]telegrafPluginEncode, len(tc.Plugins)),
 	}
 	for k, p := range tc.Plugins {
@@ -217,88 +295,6 @@ func (tc *TelegrafConfig) MarshalJSON() ([]byte, error) {
 	return json.Marshal(tce)
 }
 
-// UnmarshalTOML implements toml.Unmarshaler interface.
-func (tc *TelegrafConfig) UnmarshalTOML(data interface{}) error {
-	dataOk, ok := data.(map[string]interface{})
-	if !ok {
-		return errors.New("blank string")
-	}
-	agent, ok := dataOk["agent"].(map[string]interface{})
-	if !ok {
-		return errors.New("agent is missing")
-	}
-
-	intervalStr, ok := agent["interval"].(string)
-	if !ok {
-		return errors.New("agent interval is not string")
-	}
-
-	interval, err := time.ParseDuration(intervalStr)
-	if err != nil {
-		return err
-	}
-	tc.Agent = TelegrafAgentConfig{
-		Interval: interval.Nanoseconds() / 1000000,
-	}
-
-	for tp, ps := range dataOk {
-		if tp == "agent" {
-			continue
-		}
-		plugins, ok := ps.(map[string]interface{})
-		if !ok {
-			return &Error{
-				Msg: "bad plugin type",
-			}
-		}
-		for name, configDataArray := range plugins {
-			if configDataArray == nil {
-				if err := tc.parseTOMLPluginConfig(tp, name, configDataArray); err != nil {
-					return err
-				}
-				continue
-			}
-			for _, configData := range configDataArray.([]map[string]interface{}) {
-				if err := tc.parseTOMLPluginConfig(tp, name, configData); err != nil {
-					return err
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
-func (tc *TelegrafConfig) parseTOMLPluginConfig(typ, name string, configData interface{}) error {
-	var ok bool
-	var tpFn func() plugins.Config
-	switch typ {
-	case "inputs":
-		tpFn, ok = availableInputPlugins[name]
-	case "outputs":
-		tpFn, ok = availableOutputPlugins[name]
-	default:
-		return &Error{
-			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginType, typ),
-		}
-	}
-
-	if !ok {
-		return &Error{
-			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginName, name, typ),
-		}
-	}
-	p := tpFn()
-
-	if err := p.UnmarshalTOML(configData); err != nil {
-		return err
-	}
-	tc.Plugins = append(tc.Plugins, TelegrafPlugin{
-		Config: p,
-	})
-	return nil
-}
-
 // UnmarshalJSON implement the json.Unmarshaler interface.
 func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 	tcd := new(telegrafConfigDecode)
@@ -310,6 +306,7 @@ func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 		OrganizationID: tcd.OrganizationID,
 		Name:           tcd.Name,
 		Agent:          tcd.Agent,
+		GlobalTags:     tcd.GlobalTags,
 		Plugins:        make([]TelegrafPlugin, len(tcd.Plugins)),
 	}
 	return decodePluginRaw(tcd, tc)
@@ -318,46 +315,52 @@ func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 func decodePluginRaw(tcd *telegrafConfigDecode, tc *TelegrafConfig) (err error) {
 	op := "unmarshal telegraf config raw plugin"
 	for k, pr := range tcd.Plugins {
-		var tpFn func() plugins.Config
-		var config plugins.Config
-		var ok bool
-		switch pr.Type {
-		case plugins.Input:
-			tpFn, ok = availableInputPlugins[pr.Name]
-		case plugins.Output:
-			tpFn, ok = availableOutputPlugins[pr.Name]
-		default:
+		available, ok := availablePlugins[pr.Type]
+		if !ok {
 			return &Error{
 				Code: EInvalid,
 				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, pr.Type),
 				Op:   op,
 			}
 		}
-		if ok {
+
+		var config plugins.Config
+		if tpFn, ok := available[pr.Name]; ok {
 			config = tpFn()
-			if err = json.Unmarshal(pr.Config, config); err != nil {
-				return &Error{
-					Code: EInvalid,
-					Err:  err,
-					Op:   op,
-				}
+		} else {
+			// A plugin without a typed config must have its toml.
+			config = &plugins.Raw{PluginType: pr.Type, Name: pr.Name}
+		}
+		if err = json.Unmarshal(pr.Config, config); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Err:  err,
+				Op:   op,
 			}
-			tc.Plugins[k] = TelegrafPlugin{
-				Comment: pr.Comment,
-				Config:  config,
+		}
+		if raw, ok := config.(*plugins.Raw); ok && raw.Text == "" {
+			return &Error{
+				Code: EInvalid,
+				Op:   op,
+				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginName, pr.Name, pr.Type),
 			}
-			continue
 		}
-		return &Error{
-			Code: EInvalid,
-			Op:   op,
-			Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginName, pr.Name, pr.Type),
+		tc.Plugins[k] = TelegrafPlugin{
+			Comment: pr.Comment,
+			Config:  config,
 		}
-
 	}
 	return nil
 }
 
+// availablePlugins are the plugins with a typed config by type.
+var availablePlugins = map[plugins.Type]map[string](func() plugins.Config){
+	plugins.Input:      availableInputPlugins,
+	plugins.Output:     availableOutputPlugins,
+	plugins.Processor:  availableProcessorPlugins,
+	plugins.Aggregator: availableAggregatorPlugins,
+}
+
 var availableInputPlugins = map[string](func() plugins.Config){
 	"cpu":          func() plugins.Config { return &inputs.CPUStats{} },
 	"disk":         func() plugins.Config { return &inputs.DiskStats{} },
@@ -385,3 +388,17 @@ var availableOutputPlugins = map[string](func() plugins.Config){
 	"file":        func() plugins.Config { return &outputs.File{} },
 	"influxdb_v2": func() plugins.Config { return &outputs.InfluxDBV2{} },
 }
+
+var availableProcessorPlugins = map[string](func() plugins.Config){
+	"converter": func() plugins.Config { return &processors.Converter{} },
+	"enum":      func() plugins.Config { return &processors.Enum{} },
+	"regex":     func() plugins.Config { return &processors.Regex{} },
+	"rename":    func() plugins.Config { return &processors.Rename{} },
+	"strings":   func() plugins.Config { return &processors.Strings{} },
+}
+
+var availableAggregatorPlugins = map[string](func() plugins.Config){
+	"basicstats": func() plugins.Config { return &aggregators.BasicStats{} },
+	"histogram":  func() plugins.Config { return &aggregators.Histogram{} },
+	"minmax":     func() plugins.Config { return &aggregators.MinMax{} },
+}
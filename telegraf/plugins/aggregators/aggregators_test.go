@@ -0,0 +1,258 @@
+package aggregators
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/influxdata/influxdb/telegraf/plugins"
+)
+
+// local plugin
+type telegrafPluginConfig interface {
+	TOML() string
+	Type() plugins.Type
+	PluginName() string
+	UnmarshalTOML(data interface{}) error
+}
+
+func TestType(t *testing.T) {
+	b := baseAggregator(0)
+	if b.Type() != plugins.Aggregator {
+		t.Fatalf("aggregator plugins type should be aggregator, got %s", b.Type())
+	}
+}
+
+func TestTOML(t *testing.T) {
+	cases := []struct {
+		name    string
+		plugins map[telegrafPluginConfig]string
+	}{
+		{
+			name: "test empty plugins",
+			plugins: map[telegrafPluginConfig]string{
+				&BasicStats{}: `[[aggregators.basicstats]]
+  ## The period on which to flush & clear the aggregator.
+  period = ""
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## Configures which basic stats to push as fields
+  # stats = ["count", "min", "max", "mean", "stdev", "s2", "sum"]
+`,
+				&MinMax{}: `[[aggregators.minmax]]
+  ## The period on which to flush & clear the aggregator.
+  period = ""
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+`,
+				&Histogram{}: `[[aggregators.histogram]]
+  ## The period on which to flush & clear the aggregator.
+  period = ""
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## If true, the histogram will be reset on flush instead
+  ## of accumulating the results.
+  reset = false
+`,
+			},
+		},
+		{
+			name: "standard testing",
+			plugins: map[telegrafPluginConfig]string{
+				&BasicStats{
+					Period: "30s",
+					Stats:  []string{"count", "mean"},
+				}: `[[aggregators.basicstats]]
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## Configures which basic stats to push as fields
+  stats = ["count", "mean"]
+`,
+				&MinMax{
+					Period:       "1m",
+					DropOriginal: true,
+				}: `[[aggregators.minmax]]
+  ## The period on which to flush & clear the aggregator.
+  period = "1m"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = true
+`,
+				&Histogram{
+					Period: "30s",
+					Reset:  true,
+					Configs: []HistogramConfig{
+						{Measurement: "cpu", Fields: []string{"usage_idle"}, Buckets: []float64{0, 10, 50.5, 100}},
+						{Measurement: "diskio", Buckets: []float64{1e9}},
+					},
+				}: `[[aggregators.histogram]]
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## If true, the histogram will be reset on flush instead
+  ## of accumulating the results.
+  reset = true
+  [[aggregators.histogram.config]]
+    ## The set of buckets.
+    buckets = [0.0, 10.0, 50.5, 100.0]
+    ## The name of metric.
+    measurement_name = "cpu"
+    ## The concrete fields of metric
+    fields = ["usage_idle"]
+  [[aggregators.histogram.config]]
+    ## The set of buckets.
+    buckets = [1e+09]
+    ## The name of metric.
+    measurement_name = "diskio"
+`,
+			},
+		},
+	}
+	for _, c := range cases {
+		for aggregator, toml := range c.plugins {
+			if toml != aggregator.TOML() {
+				t.Fatalf("%s failed want %s, got %v", c.name, toml, aggregator.TOML())
+			}
+		}
+	}
+}
+
+func TestDecodeTOML(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    telegrafPluginConfig
+		wantErr error
+		output  telegrafPluginConfig
+		data    interface{}
+	}{
+		{
+			name:    "basicstats empty",
+			want:    &BasicStats{},
+			wantErr: errors.New("bad period for basicstats aggregator plugin"),
+			output:  &BasicStats{},
+		},
+		{
+			name:    "basicstats missing period",
+			want:    &BasicStats{},
+			wantErr: errors.New("period is missing for basicstats aggregator plugin"),
+			output:  &BasicStats{},
+			data:    map[string]interface{}{},
+		},
+		{
+			name: "basicstats",
+			want: &BasicStats{
+				Period:       "30s",
+				DropOriginal: true,
+				Stats:        []string{"sum"},
+			},
+			output: &BasicStats{},
+			data: map[string]interface{}{
+				"period":        "30s",
+				"drop_original": true,
+				"stats":         []interface{}{"sum"},
+			},
+		},
+		{
+			name: "minmax",
+			want: &MinMax{
+				Period: "10s",
+			},
+			output: &MinMax{},
+			data: map[string]interface{}{
+				"period": "10s",
+			},
+		},
+		{
+			name: "histogram missing measurement",
+			want: &Histogram{
+				Period: "10s",
+			},
+			wantErr: errors.New("measurement_name is missing for histogram aggregator plugin"),
+			output:  &Histogram{},
+			data: map[string]interface{}{
+				"period": "10s",
+				"config": []map[string]interface{}{
+					{"buckets": []interface{}{1.0}},
+				},
+			},
+		},
+		{
+			name: "histogram",
+			want: &Histogram{
+				Period: "10s",
+				Reset:  true,
+				Configs: []HistogramConfig{
+					{Measurement: "cpu", Buckets: []float64{1, 2.5}},
+				},
+			},
+			output: &Histogram{},
+			data: map[string]interface{}{
+				"period": "10s",
+				"reset":  true,
+				"config": []map[string]interface{}{
+					{"measurement_name": "cpu", "buckets": []interface{}{int64(1), 2.5}},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		err := c.output.UnmarshalTOML(c.data)
+		if c.wantErr != nil && (err == nil || err.Error() != c.wantErr.Error()) {
+			t.Fatalf("%s failed want err %s, got %v", c.name, c.wantErr.Error(), err)
+		}
+		if c.wantErr == nil && err != nil {
+			t.Fatalf("%s failed want err nil, got %v", c.name, err)
+		}
+		if !reflect.DeepEqual(c.output, c.want) {
+			t.Fatalf("%s failed want %v, got %v", c.name, c.want, c.output)
+		}
+	}
+}
+
+func TestTOMLRoundTrip(t *testing.T) {
+	cases := []struct {
+		plugin telegrafPluginConfig
+		output telegrafPluginConfig
+	}{
+		{
+			plugin: &BasicStats{Period: "30s", Stats: []string{"s2"}},
+			output: &BasicStats{},
+		},
+		{
+			plugin: &MinMax{Period: "30s", DropOriginal: true},
+			output: &MinMax{},
+		},
+		{
+			plugin: &Histogram{
+				Period:  "30s",
+				Configs: []HistogramConfig{{Measurement: "cpu", Fields: []string{"a"}, Buckets: []float64{0, 0.5, 1e9}}},
+			},
+			output: &Histogram{},
+		},
+	}
+	for _, c := range cases {
+		var data map[string]map[string][]map[string]interface{}
+		if _, err := toml.Decode(c.plugin.TOML(), &data); err != nil {
+			t.Fatalf("%s failed to decode toml: %v", c.plugin.PluginName(), err)
+		}
+		if err := c.output.UnmarshalTOML(data["aggregators"][c.plugin.PluginName()][0]); err != nil {
+			t.Fatalf("%s failed want err nil, got %v", c.plugin.PluginName(), err)
+		}
+		if !reflect.DeepEqual(c.output, c.plugin) {
+			t.Fatalf("%s failed want %v, got %v", c.plugin.PluginName(), c.plugin, c.output)
+		}
+	}
+}
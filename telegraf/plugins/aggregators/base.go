@@ -0,0 +1,44 @@
+package aggregators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/influxdb/telegraf/plugins"
+)
+
+type baseAggregator int
+
+func (b baseAggregator) Type() plugins.Type {
+	return plugins.Aggregator
+}
+
+// periodTOML encodes the settings common to all aggregators.
+func periodTOML(period string, dropOriginal bool) string {
+	return fmt.Sprintf(`  ## The period on which to flush & clear the aggregator.
+  period = %q
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = %t
+`, period, dropOriginal)
+}
+
+// decodePeriod decodes the settings common to all aggregators.
+func decodePeriod(data map[string]interface{}, plugin string) (period string, dropOriginal bool, err error) {
+	period, ok := data["period"].(string)
+	if !ok {
+		return "", false, fmt.Errorf("period is missing for %s aggregator plugin", plugin)
+	}
+	dropOriginal, _ = data["drop_original"].(bool)
+	return period, dropOriginal, nil
+}
+
+// quoteStrings encodes ss as the elements of a toml array.
+func quoteStrings(ss []string) string {
+	s := make([]string, len(ss))
+	for k, v := range ss {
+		s[k] = strconv.Quote(v)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,56 @@
+package aggregators
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BasicStats is based on telegraf basicstats aggregator plugin.
+type BasicStats struct {
+	baseAggregator
+	Period       string `json:"period"`
+	DropOriginal bool   `json:"dropOriginal"`
+	// Stats are the statistics to compute; telegraf computes count, min,
+	// max, mean, s2 and stdev if it is empty.
+	Stats []string `json:"stats,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (b *BasicStats) PluginName() string {
+	return "basicstats"
+}
+
+// TOML encodes to toml string.
+func (b *BasicStats) TOML() string {
+	stats := `  ## Configures which basic stats to push as fields
+  # stats = ["count", "min", "max", "mean", "stdev", "s2", "sum"]
+`
+	if len(b.Stats) > 0 {
+		stats = fmt.Sprintf(`  ## Configures which basic stats to push as fields
+  stats = [%s]
+`, quoteStrings(b.Stats))
+	}
+	return fmt.Sprintf("[[aggregators.%s]]\n%s\n%s", b.PluginName(), periodTOML(b.Period, b.DropOriginal), stats)
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (b *BasicStats) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad period for basicstats aggregator plugin")
+	}
+	var err error
+	if b.Period, b.DropOriginal, err = decodePeriod(dataOK, b.PluginName()); err != nil {
+		return err
+	}
+	if v, ok := dataOK["stats"]; ok {
+		stats, ok := v.([]interface{})
+		if !ok {
+			return errors.New("stats is not an array for basicstats aggregator plugin")
+		}
+		for _, s := range stats {
+			b.Stats = append(b.Stats, s.(string))
+		}
+	}
+	return nil
+}
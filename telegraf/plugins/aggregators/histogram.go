@@ -0,0 +1,122 @@
+package aggregators
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Histogram is based on telegraf histogram aggregator plugin.
+type Histogram struct {
+	baseAggregator
+	Period       string `json:"period"`
+	DropOriginal bool   `json:"dropOriginal"`
+	// Reset resets the buckets on every period instead of accumulating
+	// them.
+	Reset   bool              `json:"reset"`
+	Configs []HistogramConfig `json:"configs"`
+}
+
+// HistogramConfig configures the buckets of the fields of a measurement.
+type HistogramConfig struct {
+	Measurement string `json:"measurement"`
+	// Fields are the fields of the measurement to aggregate; all fields are
+	// aggregated if it is empty.
+	Fields  []string  `json:"fields,omitempty"`
+	Buckets []float64 `json:"buckets"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (h *Histogram) PluginName() string {
+	return "histogram"
+}
+
+// TOML encodes to toml string.
+func (h *Histogram) TOML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `[[aggregators.%s]]
+%s
+  ## If true, the histogram will be reset on flush instead
+  ## of accumulating the results.
+  reset = %t
+`, h.PluginName(), periodTOML(h.Period, h.DropOriginal), h.Reset)
+
+	for _, c := range h.Configs {
+		buckets := make([]string, len(c.Buckets))
+		for k, v := range c.Buckets {
+			buckets[k] = formatFloat(v)
+		}
+		fmt.Fprintf(&b, `  [[aggregators.%s.config]]
+    ## The set of buckets.
+    buckets = [%s]
+    ## The name of metric.
+    measurement_name = %q
+`, h.PluginName(), strings.Join(buckets, ", "), c.Measurement)
+		if len(c.Fields) > 0 {
+			fmt.Fprintf(&b, `    ## The concrete fields of metric
+    fields = [%s]
+`, quoteStrings(c.Fields))
+		}
+	}
+	return b.String()
+}
+
+// formatFloat formats v as a toml float, which always has a fractional part
+// or an exponent.
+func formatFloat(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (h *Histogram) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad period for histogram aggregator plugin")
+	}
+	var err error
+	if h.Period, h.DropOriginal, err = decodePeriod(dataOK, h.PluginName()); err != nil {
+		return err
+	}
+	h.Reset, _ = dataOK["reset"].(bool)
+
+	v, ok := dataOK["config"]
+	if !ok {
+		return nil
+	}
+	tables, ok := v.([]map[string]interface{})
+	if !ok {
+		return errors.New("config is not an array of tables for histogram aggregator plugin")
+	}
+	for _, t := range tables {
+		c := HistogramConfig{}
+		if c.Measurement, ok = t["measurement_name"].(string); !ok {
+			return errors.New("measurement_name is missing for histogram aggregator plugin")
+		}
+		buckets, ok := t["buckets"].([]interface{})
+		if !ok {
+			return errors.New("buckets is not an array for histogram aggregator plugin")
+		}
+		for _, b := range buckets {
+			switch b := b.(type) {
+			case float64:
+				c.Buckets = append(c.Buckets, b)
+			case int64:
+				c.Buckets = append(c.Buckets, float64(b))
+			default:
+				return errors.New("buckets is not an array of numbers for histogram aggregator plugin")
+			}
+		}
+		if fields, ok := t["fields"].([]interface{}); ok {
+			for _, f := range fields {
+				c.Fields = append(c.Fields, f.(string))
+			}
+		}
+		h.Configs = append(h.Configs, c)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package aggregators
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MinMax is based on telegraf minmax aggregator plugin.
+type MinMax struct {
+	baseAggregator
+	Period       string `json:"period"`
+	DropOriginal bool   `json:"dropOriginal"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (m *MinMax) PluginName() string {
+	return "minmax"
+}
+
+// TOML encodes to toml string.
+func (m *MinMax) TOML() string {
+	return fmt.Sprintf("[[aggregators.%s]]\n%s", m.PluginName(), periodTOML(m.Period, m.DropOriginal))
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (m *MinMax) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad period for minmax aggregator plugin")
+	}
+	var err error
+	m.Period, m.DropOriginal, err = decodePeriod(dataOK, m.PluginName())
+	return err
+}
@@ -60,10 +60,9 @@ func (i *InfluxDBV2) UnmarshalTOML(data interface{}) error {
 		i.URLs = append(i.URLs, url.(string))
 	}
 
-	i.Token, ok = dataOK["token"].(string)
-	if !ok {
-		return errors.New("token is missing for influxdb_v2 output plugin")
-	}
+	// The token is optional: the agents that fetch the config get the
+	// token of the config when it has none.
+	i.Token, _ = dataOK["token"].(string)
 
 	i.Organization, ok = dataOK["organization"].(string)
 	if !ok {
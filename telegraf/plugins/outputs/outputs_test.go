@@ -158,20 +158,23 @@ func TestDecodeTOML(t *testing.T) {
 			},
 		},
 		{
-			name: "influxdb_v2 missing token",
+			name: "influxdb_v2 without token",
 			want: &InfluxDBV2{
 				URLs: []string{
 					"http://localhost:9999",
 					"http://192.168.0.1:9999",
 				},
+				Organization: "org1",
+				Bucket:       "bucket1",
 			},
-			wantErr: errors.New("token is missing for influxdb_v2 output plugin"),
-			output:  &InfluxDBV2{},
+			output: &InfluxDBV2{},
 			data: map[string]interface{}{
 				"urls": []interface{}{
 					"http://localhost:9999",
 					"http://192.168.0.1:9999",
 				},
+				"organization": "org1",
+				"bucket":       "bucket1",
 			},
 		},
 		{
@@ -0,0 +1,59 @@
+package processors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/influxdb/telegraf/plugins"
+)
+
+type baseProcessor int
+
+func (b baseProcessor) Type() plugins.Type {
+	return plugins.Processor
+}
+
+// quoteStrings encodes ss as the elements of a toml array.
+func quoteStrings(ss []string) string {
+	s := make([]string, len(ss))
+	for k, v := range ss {
+		s[k] = strconv.Quote(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+// decodeTables returns the array of tables under key of data, or nil if
+// there is none.
+func decodeTables(data map[string]interface{}, key, plugin string) ([]map[string]interface{}, error) {
+	v, ok := data[key]
+	if !ok {
+		return nil, nil
+	}
+	tables, ok := v.([]map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not an array of tables for %s processor plugin", key, plugin)
+	}
+	return tables, nil
+}
+
+// decodeStrings returns the strings of the array under key of data.
+func decodeStrings(data map[string]interface{}, key, plugin string) ([]string, error) {
+	v, ok := data[key]
+	if !ok {
+		return nil, nil
+	}
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not an array for %s processor plugin", key, plugin)
+	}
+	ss := make([]string, 0, len(arr))
+	for _, s := range arr {
+		str, ok := s.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an array of strings for %s processor plugin", key, plugin)
+		}
+		ss = append(ss, str)
+	}
+	return ss, nil
+}
@@ -0,0 +1,114 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Converter is based on telegraf converter processor plugin.
+type Converter struct {
+	baseProcessor
+	Tags   ConverterTypes `json:"tags"`
+	Fields ConverterTypes `json:"fields"`
+}
+
+// ConverterTypes lists the glob patterns of the keys of the tags or fields to
+// convert to each type.
+type ConverterTypes struct {
+	// Tag converts fields to tags; it is ignored for tags.
+	Tag      []string `json:"tag,omitempty"`
+	String   []string `json:"string,omitempty"`
+	Integer  []string `json:"integer,omitempty"`
+	Unsigned []string `json:"unsigned,omitempty"`
+	Boolean  []string `json:"boolean,omitempty"`
+	Float    []string `json:"float,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (c *Converter) PluginName() string {
+	return "converter"
+}
+
+// TOML encodes to toml string.
+func (c *Converter) TOML() string {
+	return fmt.Sprintf(`[[processors.%s]]
+  ## Tags to convert
+  ##
+  ## The table key determines the target type, and the array of key-values
+  ## select the keys to convert.  The array may contain globs.
+  [processors.%s.tags]
+    string = [%s]
+    integer = [%s]
+    unsigned = [%s]
+    boolean = [%s]
+    float = [%s]
+
+  ## Fields to convert
+  [processors.%s.fields]
+    tag = [%s]
+    string = [%s]
+    integer = [%s]
+    unsigned = [%s]
+    boolean = [%s]
+    float = [%s]
+`, c.PluginName(), c.PluginName(),
+		quoteStrings(c.Tags.String),
+		quoteStrings(c.Tags.Integer),
+		quoteStrings(c.Tags.Unsigned),
+		quoteStrings(c.Tags.Boolean),
+		quoteStrings(c.Tags.Float),
+		c.PluginName(),
+		quoteStrings(c.Fields.Tag),
+		quoteStrings(c.Fields.String),
+		quoteStrings(c.Fields.Integer),
+		quoteStrings(c.Fields.Unsigned),
+		quoteStrings(c.Fields.Boolean),
+		quoteStrings(c.Fields.Float))
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (c *Converter) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad tags or fields for converter processor plugin")
+	}
+	var err error
+	if c.Tags, err = c.decodeTypes(dataOK, "tags"); err != nil {
+		return err
+	}
+	c.Tags.Tag = nil
+	if c.Fields, err = c.decodeTypes(dataOK, "fields"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Converter) decodeTypes(data map[string]interface{}, key string) (ConverterTypes, error) {
+	var types ConverterTypes
+	v, ok := data[key]
+	if !ok {
+		return types, nil
+	}
+	table, ok := v.(map[string]interface{})
+	if !ok {
+		return types, fmt.Errorf("%s is not a table for converter processor plugin", key)
+	}
+
+	for k, ss := range map[string]*[]string{
+		"tag":      &types.Tag,
+		"string":   &types.String,
+		"integer":  &types.Integer,
+		"unsigned": &types.Unsigned,
+		"boolean":  &types.Boolean,
+		"float":    &types.Float,
+	} {
+		s, err := decodeStrings(table, k, c.PluginName())
+		if err != nil {
+			return types, err
+		}
+		if len(s) > 0 {
+			*ss = s
+		}
+	}
+	return types, nil
+}
@@ -0,0 +1,91 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Enum is based on telegraf enum processor plugin.
+type Enum struct {
+	baseProcessor
+	Mappings []EnumMapping `json:"mappings"`
+}
+
+// EnumMapping maps the string values of a field to integers.
+type EnumMapping struct {
+	Field string `json:"field"`
+	// Dest is the field of the mapped value; the value of Field is
+	// replaced if it is empty.
+	Dest          string           `json:"dest,omitempty"`
+	ValueMappings map[string]int64 `json:"valueMappings"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (e *Enum) PluginName() string {
+	return "enum"
+}
+
+// TOML encodes to toml string.
+func (e *Enum) TOML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[[processors.%s]]\n", e.PluginName())
+	for _, m := range e.Mappings {
+		fmt.Fprintf(&b, `  [[processors.%s.mapping]]
+    ## Name of the field to map
+    field = %q
+
+    ## Destination field to be used for the mapped value.  By default the
+    ## source field is used, overwriting the original value.
+    dest = %q
+
+    ## Table of mappings
+    [processors.%s.mapping.value_mappings]
+`, e.PluginName(), m.Field, m.Dest, e.PluginName())
+
+		keys := make([]string, 0, len(m.ValueMappings))
+		for k := range m.ValueMappings {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "      %q = %d\n", k, m.ValueMappings[k])
+		}
+	}
+	return b.String()
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (e *Enum) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad mapping for enum processor plugin")
+	}
+	tables, err := decodeTables(dataOK, "mapping", e.PluginName())
+	if err != nil {
+		return err
+	}
+	for _, t := range tables {
+		m := EnumMapping{}
+		if m.Field, ok = t["field"].(string); !ok {
+			return errors.New("field is missing for enum processor plugin")
+		}
+		m.Dest, _ = t["dest"].(string)
+
+		values, ok := t["value_mappings"].(map[string]interface{})
+		if !ok {
+			return errors.New("value_mappings is missing for enum processor plugin")
+		}
+		m.ValueMappings = make(map[string]int64, len(values))
+		for k, v := range values {
+			i, ok := v.(int64)
+			if !ok {
+				return fmt.Errorf("value of %s is not an integer for enum processor plugin", k)
+			}
+			m.ValueMappings[k] = i
+		}
+		e.Mappings = append(e.Mappings, m)
+	}
+	return nil
+}
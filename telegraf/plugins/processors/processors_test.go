@@ -0,0 +1,391 @@
+package processors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/influxdata/influxdb/telegraf/plugins"
+)
+
+// local plugin
+type telegrafPluginConfig interface {
+	TOML() string
+	Type() plugins.Type
+	PluginName() string
+	UnmarshalTOML(data interface{}) error
+}
+
+func TestType(t *testing.T) {
+	b := baseProcessor(0)
+	if b.Type() != plugins.Processor {
+		t.Fatalf("processor plugins type should be processor, got %s", b.Type())
+	}
+}
+
+func TestTOML(t *testing.T) {
+	cases := []struct {
+		name    string
+		plugins map[telegrafPluginConfig]string
+	}{
+		{
+			name: "test empty plugins",
+			plugins: map[telegrafPluginConfig]string{
+				&Rename{}: `[[processors.rename]]
+  ## Each replace renames one measurement, tag or field to dest.
+`,
+				&Regex{}: `[[processors.regex]]
+  ## Tag and field conversions are defined in separate sub-tables.
+`,
+				&Converter{}: `[[processors.converter]]
+  ## Tags to convert
+  ##
+  ## The table key determines the target type, and the array of key-values
+  ## select the keys to convert.  The array may contain globs.
+  [processors.converter.tags]
+    string = []
+    integer = []
+    unsigned = []
+    boolean = []
+    float = []
+
+  ## Fields to convert
+  [processors.converter.fields]
+    tag = []
+    string = []
+    integer = []
+    unsigned = []
+    boolean = []
+    float = []
+`,
+				&Enum{}: "[[processors.enum]]\n",
+				&Strings{}: `[[processors.strings]]
+  ## Each function applies to the measurement, a tag or a field, and writes
+  ## the result to dest, or in place if dest is not set.
+`,
+			},
+		},
+		{
+			name: "standard testing",
+			plugins: map[telegrafPluginConfig]string{
+				&Rename{
+					Replaces: []RenameReplace{
+						{Measurement: "network_interface_throughput", Dest: "throughput"},
+						{Tag: "hostname", Dest: "host"},
+					},
+				}: `[[processors.rename]]
+  ## Each replace renames one measurement, tag or field to dest.
+  [[processors.rename.replace]]
+    measurement = "network_interface_throughput"
+    dest = "throughput"
+  [[processors.rename.replace]]
+    tag = "hostname"
+    dest = "host"
+`,
+				&Regex{
+					Tags: []RegexConverter{
+						{Key: "resp_code", Pattern: `^(\d)\d\d$`, Replacement: "${1}xx"},
+					},
+					Fields: []RegexConverter{
+						{Key: "request", Pattern: `^/api(?P<method>/[\w/]+)\S*`, Replacement: "${method}", ResultKey: "method"},
+					},
+				}: `[[processors.regex]]
+  ## Tag and field conversions are defined in separate sub-tables.
+  [[processors.regex.tags]]
+    key = "resp_code"
+    pattern = "^(\\d)\\d\\d$"
+    replacement = "${1}xx"
+  [[processors.regex.fields]]
+    key = "request"
+    pattern = "^/api(?P<method>/[\\w/]+)\\S*"
+    replacement = "${method}"
+    result_key = "method"
+`,
+				&Converter{
+					Tags: ConverterTypes{
+						Integer: []string{"port"},
+					},
+					Fields: ConverterTypes{
+						Tag:   []string{"scboard_*"},
+						Float: []string{"load*"},
+					},
+				}: `[[processors.converter]]
+  ## Tags to convert
+  ##
+  ## The table key determines the target type, and the array of key-values
+  ## select the keys to convert.  The array may contain globs.
+  [processors.converter.tags]
+    string = []
+    integer = ["port"]
+    unsigned = []
+    boolean = []
+    float = []
+
+  ## Fields to convert
+  [processors.converter.fields]
+    tag = ["scboard_*"]
+    string = []
+    integer = []
+    unsigned = []
+    boolean = []
+    float = ["load*"]
+`,
+				&Enum{
+					Mappings: []EnumMapping{
+						{
+							Field: "status",
+							Dest:  "status_code",
+							ValueMappings: map[string]int64{
+								"green":  1,
+								"amber":  2,
+								"red":    3,
+								"is ok?": 4,
+							},
+						},
+					},
+				}: `[[processors.enum]]
+  [[processors.enum.mapping]]
+    ## Name of the field to map
+    field = "status"
+
+    ## Destination field to be used for the mapped value.  By default the
+    ## source field is used, overwriting the original value.
+    dest = "status_code"
+
+    ## Table of mappings
+    [processors.enum.mapping.value_mappings]
+      "amber" = 2
+      "green" = 1
+      "is ok?" = 4
+      "red" = 3
+`,
+				&Strings{
+					Lowercase:  []StringsConverter{{Tag: "method"}},
+					TrimPrefix: []StringsConverter{{Field: "uri", Prefix: "/api", Dest: "path"}},
+				}: `[[processors.strings]]
+  ## Each function applies to the measurement, a tag or a field, and writes
+  ## the result to dest, or in place if dest is not set.
+  [[processors.strings.lowercase]]
+    tag = "method"
+  [[processors.strings.trim_prefix]]
+    field = "uri"
+    dest = "path"
+    prefix = "/api"
+`,
+			},
+		},
+	}
+	for _, c := range cases {
+		for processor, toml := range c.plugins {
+			if toml != processor.TOML() {
+				t.Fatalf("%s failed want %s, got %v", c.name, toml, processor.TOML())
+			}
+		}
+	}
+}
+
+func TestDecodeTOML(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    telegrafPluginConfig
+		wantErr error
+		output  telegrafPluginConfig
+		data    interface{}
+	}{
+		{
+			name:    "rename empty",
+			want:    &Rename{},
+			wantErr: errors.New("bad replace for rename processor plugin"),
+			output:  &Rename{},
+		},
+		{
+			name:    "rename bad replace",
+			want:    &Rename{},
+			wantErr: errors.New("replace is not an array of tables for rename processor plugin"),
+			output:  &Rename{},
+			data: map[string]interface{}{
+				"replace": "",
+			},
+		},
+		{
+			name:    "rename missing dest",
+			want:    &Rename{},
+			wantErr: errors.New("dest is missing for rename processor plugin"),
+			output:  &Rename{},
+			data: map[string]interface{}{
+				"replace": []map[string]interface{}{
+					{"tag": "hostname"},
+				},
+			},
+		},
+		{
+			name: "rename",
+			want: &Rename{
+				Replaces: []RenameReplace{
+					{Tag: "hostname", Dest: "host"},
+				},
+			},
+			output: &Rename{},
+			data: map[string]interface{}{
+				"replace": []map[string]interface{}{
+					{"tag": "hostname", "dest": "host"},
+				},
+			},
+		},
+		{
+			name:    "regex missing pattern",
+			want:    &Regex{},
+			wantErr: errors.New("pattern is missing for regex processor plugin"),
+			output:  &Regex{},
+			data: map[string]interface{}{
+				"tags": []map[string]interface{}{
+					{"key": "resp_code"},
+				},
+			},
+		},
+		{
+			name: "regex",
+			want: &Regex{
+				Fields: []RegexConverter{
+					{Key: "request", Pattern: "^/api", Replacement: "", ResultKey: "api"},
+				},
+			},
+			output: &Regex{},
+			data: map[string]interface{}{
+				"fields": []map[string]interface{}{
+					{"key": "request", "pattern": "^/api", "result_key": "api"},
+				},
+			},
+		},
+		{
+			name:    "converter bad tags",
+			want:    &Converter{},
+			wantErr: errors.New("tags is not a table for converter processor plugin"),
+			output:  &Converter{},
+			data: map[string]interface{}{
+				"tags": []interface{}{},
+			},
+		},
+		{
+			name: "converter",
+			want: &Converter{
+				Tags: ConverterTypes{
+					String: []string{"port"},
+				},
+				Fields: ConverterTypes{
+					Tag:     []string{"host"},
+					Boolean: []string{"ok"},
+				},
+			},
+			output: &Converter{},
+			data: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"tag":    []interface{}{"ignored"},
+					"string": []interface{}{"port"},
+				},
+				"fields": map[string]interface{}{
+					"tag":     []interface{}{"host"},
+					"boolean": []interface{}{"ok"},
+					"float":   []interface{}{},
+				},
+			},
+		},
+		{
+			name:    "enum missing value mappings",
+			want:    &Enum{},
+			wantErr: errors.New("value_mappings is missing for enum processor plugin"),
+			output:  &Enum{},
+			data: map[string]interface{}{
+				"mapping": []map[string]interface{}{
+					{"field": "status"},
+				},
+			},
+		},
+		{
+			name: "enum",
+			want: &Enum{
+				Mappings: []EnumMapping{
+					{Field: "status", ValueMappings: map[string]int64{"green": 1}},
+				},
+			},
+			output: &Enum{},
+			data: map[string]interface{}{
+				"mapping": []map[string]interface{}{
+					{
+						"field":          "status",
+						"value_mappings": map[string]interface{}{"green": int64(1)},
+					},
+				},
+			},
+		},
+		{
+			name: "strings",
+			want: &Strings{
+				Uppercase: []StringsConverter{{Measurement: "*"}},
+				Trim:      []StringsConverter{{Field: "message", Cutset: " "}},
+			},
+			output: &Strings{},
+			data: map[string]interface{}{
+				"uppercase": []map[string]interface{}{
+					{"measurement": "*"},
+				},
+				"trim": []map[string]interface{}{
+					{"field": "message", "cutset": " "},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		err := c.output.UnmarshalTOML(c.data)
+		if c.wantErr != nil && (err == nil || err.Error() != c.wantErr.Error()) {
+			t.Fatalf("%s failed want err %s, got %v", c.name, c.wantErr.Error(), err)
+		}
+		if c.wantErr == nil && err != nil {
+			t.Fatalf("%s failed want err nil, got %v", c.name, err)
+		}
+		if !reflect.DeepEqual(c.output, c.want) {
+			t.Fatalf("%s failed want %v, got %v", c.name, c.want, c.output)
+		}
+	}
+}
+
+func TestTOMLRoundTrip(t *testing.T) {
+	cases := []struct {
+		plugin telegrafPluginConfig
+		output telegrafPluginConfig
+	}{
+		{
+			plugin: &Rename{Replaces: []RenameReplace{{Field: "lower", Dest: "min"}}},
+			output: &Rename{},
+		},
+		{
+			plugin: &Regex{Tags: []RegexConverter{{Key: "resp_code", Pattern: `^(\d)\d\d$`, Replacement: "${1}xx"}}},
+			output: &Regex{},
+		},
+		{
+			plugin: &Converter{Fields: ConverterTypes{Unsigned: []string{"a", "b"}}},
+			output: &Converter{},
+		},
+		{
+			plugin: &Enum{Mappings: []EnumMapping{{Field: "status", Dest: "code", ValueMappings: map[string]int64{"a b": 1, "c": -2}}}},
+			output: &Enum{},
+		},
+		{
+			plugin: &Strings{TrimSuffix: []StringsConverter{{Tag: "host", Suffix: ".local"}}},
+			output: &Strings{},
+		},
+	}
+	for _, c := range cases {
+		var data map[string]map[string][]map[string]interface{}
+		if _, err := toml.Decode(c.plugin.TOML(), &data); err != nil {
+			t.Fatalf("%s failed to decode toml: %v", c.plugin.PluginName(), err)
+		}
+		if err := c.output.UnmarshalTOML(data["processors"][c.plugin.PluginName()][0]); err != nil {
+			t.Fatalf("%s failed want err nil, got %v", c.plugin.PluginName(), err)
+		}
+		if !reflect.DeepEqual(c.output, c.plugin) {
+			t.Fatalf("%s failed want %v, got %v", c.plugin.PluginName(), c.plugin, c.output)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Regex is based on telegraf regex processor plugin.
+type Regex struct {
+	baseProcessor
+	Tags   []RegexConverter `json:"tags"`
+	Fields []RegexConverter `json:"fields"`
+}
+
+// RegexConverter replaces the matches of Pattern in the value of the tag
+// or field Key with Replacement.
+type RegexConverter struct {
+	Key         string `json:"key"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+	// ResultKey is the key of the replaced value; the value of Key is
+	// overwritten if it is empty.
+	ResultKey string `json:"resultKey,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (r *Regex) PluginName() string {
+	return "regex"
+}
+
+// TOML encodes to toml string.
+func (r *Regex) TOML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `[[processors.%s]]
+  ## Tag and field conversions are defined in separate sub-tables.
+`, r.PluginName())
+	for _, section := range []struct {
+		name       string
+		converters []RegexConverter
+	}{
+		{"tags", r.Tags},
+		{"fields", r.Fields},
+	} {
+		for _, c := range section.converters {
+			fmt.Fprintf(&b, `  [[processors.%s.%s]]
+    key = %q
+    pattern = %q
+    replacement = %q
+`, r.PluginName(), section.name, c.Key, c.Pattern, c.Replacement)
+			if c.ResultKey != "" {
+				fmt.Fprintf(&b, "    result_key = %q\n", c.ResultKey)
+			}
+		}
+	}
+	return b.String()
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (r *Regex) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad tags or fields for regex processor plugin")
+	}
+	var err error
+	if r.Tags, err = r.decodeConverters(dataOK, "tags"); err != nil {
+		return err
+	}
+	if r.Fields, err = r.decodeConverters(dataOK, "fields"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Regex) decodeConverters(data map[string]interface{}, key string) ([]RegexConverter, error) {
+	tables, err := decodeTables(data, key, r.PluginName())
+	if err != nil {
+		return nil, err
+	}
+	var converters []RegexConverter
+	for _, t := range tables {
+		c := RegexConverter{}
+		var ok bool
+		if c.Key, ok = t["key"].(string); !ok {
+			return nil, errors.New("key is missing for regex processor plugin")
+		}
+		if c.Pattern, ok = t["pattern"].(string); !ok {
+			return nil, errors.New("pattern is missing for regex processor plugin")
+		}
+		c.Replacement, _ = t["replacement"].(string)
+		c.ResultKey, _ = t["result_key"].(string)
+		converters = append(converters, c)
+	}
+	return converters, nil
+}
@@ -0,0 +1,70 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Rename is based on telegraf rename processor plugin.
+type Rename struct {
+	baseProcessor
+	Replaces []RenameReplace `json:"replace"`
+}
+
+// RenameReplace renames one of a measurement, tag or field to Dest.
+type RenameReplace struct {
+	Measurement string `json:"measurement,omitempty"`
+	Tag         string `json:"tag,omitempty"`
+	Field       string `json:"field,omitempty"`
+	Dest        string `json:"dest"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (r *Rename) PluginName() string {
+	return "rename"
+}
+
+// TOML encodes to toml string.
+func (r *Rename) TOML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `[[processors.%s]]
+  ## Each replace renames one measurement, tag or field to dest.
+`, r.PluginName())
+	for _, v := range r.Replaces {
+		fmt.Fprintf(&b, "  [[processors.%s.replace]]\n", r.PluginName())
+		switch {
+		case v.Measurement != "":
+			fmt.Fprintf(&b, "    measurement = %q\n", v.Measurement)
+		case v.Tag != "":
+			fmt.Fprintf(&b, "    tag = %q\n", v.Tag)
+		case v.Field != "":
+			fmt.Fprintf(&b, "    field = %q\n", v.Field)
+		}
+		fmt.Fprintf(&b, "    dest = %q\n", v.Dest)
+	}
+	return b.String()
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (r *Rename) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad replace for rename processor plugin")
+	}
+	tables, err := decodeTables(dataOK, "replace", r.PluginName())
+	if err != nil {
+		return err
+	}
+	for _, t := range tables {
+		v := RenameReplace{}
+		v.Measurement, _ = t["measurement"].(string)
+		v.Tag, _ = t["tag"].(string)
+		v.Field, _ = t["field"].(string)
+		if v.Dest, ok = t["dest"].(string); !ok {
+			return errors.New("dest is missing for rename processor plugin")
+		}
+		r.Replaces = append(r.Replaces, v)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Strings is based on telegraf strings processor plugin.
+type Strings struct {
+	baseProcessor
+	Lowercase  []StringsConverter `json:"lowercase,omitempty"`
+	Uppercase  []StringsConverter `json:"uppercase,omitempty"`
+	Trim       []StringsConverter `json:"trim,omitempty"`
+	TrimLeft   []StringsConverter `json:"trimLeft,omitempty"`
+	TrimRight  []StringsConverter `json:"trimRight,omitempty"`
+	TrimPrefix []StringsConverter `json:"trimPrefix,omitempty"`
+	TrimSuffix []StringsConverter `json:"trimSuffix,omitempty"`
+}
+
+// StringsConverter applies a string function to the measurement or the value
+// of a tag or field.
+type StringsConverter struct {
+	Measurement string `json:"measurement,omitempty"`
+	Tag         string `json:"tag,omitempty"`
+	Field       string `json:"field,omitempty"`
+	// Dest is the tag or field of the result; the value is replaced if it
+	// is empty.
+	Dest string `json:"dest,omitempty"`
+	// Cutset is the set of characters removed by the trim functions.
+	Cutset string `json:"cutset,omitempty"`
+	// Prefix is the prefix removed by trim_prefix.
+	Prefix string `json:"prefix,omitempty"`
+	// Suffix is the suffix removed by trim_suffix.
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (s *Strings) PluginName() string {
+	return "strings"
+}
+
+// functions returns the converters of s by the name of their function in
+// telegraf.
+func (s *Strings) functions() []struct {
+	name       string
+	converters *[]StringsConverter
+} {
+	return []struct {
+		name       string
+		converters *[]StringsConverter
+	}{
+		{"lowercase", &s.Lowercase},
+		{"uppercase", &s.Uppercase},
+		{"trim", &s.Trim},
+		{"trim_left", &s.TrimLeft},
+		{"trim_right", &s.TrimRight},
+		{"trim_prefix", &s.TrimPrefix},
+		{"trim_suffix", &s.TrimSuffix},
+	}
+}
+
+// TOML encodes to toml string.
+func (s *Strings) TOML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `[[processors.%s]]
+  ## Each function applies to the measurement, a tag or a field, and writes
+  ## the result to dest, or in place if dest is not set.
+`, s.PluginName())
+	for _, fn := range s.functions() {
+		for _, c := range *fn.converters {
+			fmt.Fprintf(&b, "  [[processors.%s.%s]]\n", s.PluginName(), fn.name)
+			for _, kv := range [][2]string{
+				{"measurement", c.Measurement},
+				{"tag", c.Tag},
+				{"field", c.Field},
+				{"dest", c.Dest},
+				{"cutset", c.Cutset},
+				{"prefix", c.Prefix},
+				{"suffix", c.Suffix},
+			} {
+				if kv[1] != "" {
+					fmt.Fprintf(&b, "    %s = %q\n", kv[0], kv[1])
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (s *Strings) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad functions for strings processor plugin")
+	}
+	for _, fn := range s.functions() {
+		tables, err := decodeTables(dataOK, fn.name, s.PluginName())
+		if err != nil {
+			return err
+		}
+		for _, t := range tables {
+			c := StringsConverter{}
+			c.Measurement, _ = t["measurement"].(string)
+			c.Tag, _ = t["tag"].(string)
+			c.Field, _ = t["field"].(string)
+			c.Dest, _ = t["dest"].(string)
+			c.Cutset, _ = t["cutset"].(string)
+			c.Prefix, _ = t["prefix"].(string)
+			c.Suffix, _ = t["suffix"].(string)
+			*fn.converters = append(*fn.converters, c)
+		}
+	}
+	return nil
+}
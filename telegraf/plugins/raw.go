@@ -0,0 +1,65 @@
+package plugins
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Raw is the config of a plugin that has no typed config; it keeps the toml
+// of the plugin as it was written.
+type Raw struct {
+	PluginType Type   `json:"-"`
+	Name       string `json:"-"`
+	// Text is the toml of the plugin, including its table header.
+	Text string `json:"toml"`
+}
+
+// NewRaw returns the config of the plugin of type typ and name with the toml
+// text.
+func NewRaw(typ Type, name, text string) *Raw {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	return &Raw{
+		PluginType: typ,
+		Name:       name,
+		Text:       text,
+	}
+}
+
+// TOML returns the toml of the plugin.
+func (r *Raw) TOML() string {
+	return r.Text
+}
+
+// UnmarshalTOML encodes the parsed data of the plugin back to toml; the
+// comments and layout of the original text are lost.
+func (r *Raw) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad config for raw plugin")
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{
+		r.PluginType.Section(): map[string]interface{}{
+			r.Name: []map[string]interface{}{dataOK},
+		},
+	}); err != nil {
+		return err
+	}
+	r.Text = buf.String()
+	return nil
+}
+
+// Type is the plugin type.
+func (r *Raw) Type() Type {
+	return r.PluginType
+}
+
+// PluginName is the name of the telegraf plugin.
+func (r *Raw) PluginName() string {
+	return r.Name
+}
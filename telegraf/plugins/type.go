@@ -22,3 +22,9 @@ type Config interface {
 	// PluginName is the string value of telegraf plugin package name.
 	PluginName() string
 }
+
+// Section returns the name of the toml table of the plugins of the type, such
+// as "inputs".
+func (t Type) Section() string {
+	return string(t) + "s"
+}
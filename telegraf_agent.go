@@ -0,0 +1,82 @@
+package influxdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+)
+
+// ErrTelegrafTokenNotFound is the error message for a telegraf config without a token.
+const ErrTelegrafTokenNotFound = "telegraf token not found"
+
+// ops for telegraf agents errors and op logs.
+var (
+	OpCreateTelegrafToken  = "CreateTelegrafToken"
+	OpFindTelegrafToken    = "FindTelegrafToken"
+	OpPutTelegrafAgent     = "PutTelegrafAgent"
+	OpFindTelegrafAgents   = "FindTelegrafAgents"
+	OpDeleteTelegrafAgents = "DeleteTelegrafAgents"
+)
+
+// TelegrafAgentService provisions the tokens the telegraf agents write with,
+// and tracks the agents that fetch the telegraf configs.
+//
+// The token of a telegraf config is a write only authorization on the
+// buckets of its influxdb_v2 outputs. It is not stored in the config; it is
+// set in the influxdb_v2 outputs without a token when an agent fetches the
+// config with an agent token, a token with read permission on the config
+// itself.
+type TelegrafAgentService interface {
+	// CreateTelegrafToken creates the token of the telegraf config, owned by
+	// userID. The previous token of the config is deleted, so the agents
+	// get the new token the next time they fetch the config.
+	CreateTelegrafToken(ctx context.Context, tc *TelegrafConfig, userID ID) (*Authorization, error)
+
+	// FindTelegrafToken returns the token of the telegraf config.
+	FindTelegrafToken(ctx context.Context, telegrafID ID) (*Authorization, error)
+
+	// PutTelegrafAgent records that an agent fetched a telegraf config.
+	PutTelegrafAgent(ctx context.Context, a *TelegrafAgent) error
+
+	// FindTelegrafAgents returns the agents that fetched the telegraf config.
+	FindTelegrafAgents(ctx context.Context, telegrafID ID) ([]*TelegrafAgent, error)
+
+	// DeleteTelegrafAgents removes the token and the agents of the telegraf config.
+	DeleteTelegrafAgents(ctx context.Context, telegrafID ID) error
+}
+
+// TelegrafAgent is the last fetch of a telegraf config by an agent.
+type TelegrafAgent struct {
+	TelegrafID ID `json:"telegrafID"`
+	// Hostname is the hostname the agent sent with the fetch, or else the
+	// host the agent fetched the config from.
+	Hostname string `json:"hostname"`
+	// Version is the version of the config fetched, see TelegrafConfig.Version.
+	Version   string    `json:"version"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// Version returns the version of the config, a hash of its toml.
+func (tc TelegrafConfig) Version() string {
+	sum := sha256.Sum256([]byte(tc.TOML()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// WithToken returns a copy of tc where the influxdb_v2 outputs without a
+// token have token.
+func (tc TelegrafConfig) WithToken(token string) TelegrafConfig {
+	ps := make([]TelegrafPlugin, len(tc.Plugins))
+	for i, p := range tc.Plugins {
+		if o, ok := p.Config.(*outputs.InfluxDBV2); ok && o.Token == "" {
+			cp := *o
+			cp.Token = token
+			p.Config = &cp
+		}
+		ps[i] = p
+	}
+	tc.Plugins = ps
+	return tc
+}
@@ -0,0 +1,41 @@
+package influxdb
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
+	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+)
+
+func TestTelegrafConfigWithToken(t *testing.T) {
+	tc := TelegrafConfig{
+		Agent: TelegrafAgentConfig{Interval: 10000},
+		Plugins: []TelegrafPlugin{
+			{Config: &inputs.CPUStats{}},
+			{Config: &outputs.InfluxDBV2{URLs: []string{"url1"}, Organization: "org1", Bucket: "bucket1"}},
+			{Config: &outputs.InfluxDBV2{URLs: []string{"url2"}, Token: "token1"}},
+		},
+	}
+	version := tc.Version()
+
+	got := tc.WithToken("token2")
+	want := []TelegrafPlugin{
+		{Config: &inputs.CPUStats{}},
+		{Config: &outputs.InfluxDBV2{URLs: []string{"url1"}, Token: "token2", Organization: "org1", Bucket: "bucket1"}},
+		{Config: &outputs.InfluxDBV2{URLs: []string{"url2"}, Token: "token1"}},
+	}
+	if diff := cmp.Diff(got.Plugins, want, telegrafCmpOptions...); diff != "" {
+		t.Fatalf("telegraf plugins are different -got/+want\ndiff %s", diff)
+	}
+	if token := tc.Plugins[1].Config.(*outputs.InfluxDBV2).Token; token != "" {
+		t.Fatalf("WithToken changed the config, got token %q", token)
+	}
+
+	if tc.Version() != version {
+		t.Fatalf("version of an unchanged config changed")
+	}
+	if got.Version() == version {
+		t.Fatalf("version of a changed config is unchanged")
+	}
+}
@@ -11,8 +11,10 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/influxdb/telegraf/plugins"
+	"github.com/influxdata/influxdb/telegraf/plugins/aggregators"
 	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
 	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+	"github.com/influxdata/influxdb/telegraf/plugins/processors"
 )
 
 var telegrafCmpOptions = cmp.Options{
@@ -22,6 +24,8 @@ var telegrafCmpOptions = cmp.Options{
 		inputs.File{},
 		outputs.File{},
 		outputs.InfluxDBV2{},
+		processors.Rename{},
+		aggregators.BasicStats{},
 		unsupportedPlugin{},
 	),
 	cmp.Transformer("Sort", func(in []*TelegrafConfig) []*TelegrafConfig {
@@ -46,7 +50,7 @@ func (u *unsupportedPluginType) PluginName() string {
 }
 
 func (u *unsupportedPluginType) Type() plugins.Type {
-	return plugins.Aggregator
+	return plugins.Type("serializer")
 }
 
 func (u *unsupportedPluginType) UnmarshalTOML(data interface{}) error {
@@ -187,6 +191,41 @@ func TestTelegrafConfigJSON(t *testing.T) {
 							Token: "tok1",
 						},
 					},
+					{
+						Comment: "comment5",
+						Config: &processors.Rename{
+							Replaces: []processors.RenameReplace{
+								{Tag: "hostname", Dest: "host"},
+							},
+						},
+					},
+					{
+						Comment: "comment6",
+						Config: &aggregators.BasicStats{
+							Period: "30s",
+							Stats:  []string{"mean"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "raw plugin",
+			cfg: &TelegrafConfig{
+				ID:             *id1,
+				OrganizationID: *id2,
+				Name:           "n1",
+				Agent: TelegrafAgentConfig{
+					Interval:      4000,
+					RoundInterval: new(bool),
+					FlushInterval: "30s",
+				},
+				GlobalTags: map[string]string{"dc": "us-east-1"},
+				Plugins: []TelegrafPlugin{
+					{
+						Comment: "comment1",
+						Config:  plugins.NewRaw(plugins.Output, "kafka", "[[outputs.kafka]]\n  # comment\n  brokers = [\"localhost:9092\"]\n"),
+					},
 				},
 			},
 		},
@@ -207,7 +246,7 @@ func TestTelegrafConfigJSON(t *testing.T) {
 			},
 			err: &Error{
 				Code: EInvalid,
-				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, "aggregator"),
+				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, "serializer"),
 				Op:   "unmarshal telegraf config raw plugin",
 			},
 		},
@@ -288,6 +327,20 @@ func TestTOML(t *testing.T) {
 					Bucket:       "bucket1",
 				},
 			},
+			{
+				Comment: "comment5",
+				Config: &processors.Rename{
+					Replaces: []processors.RenameReplace{
+						{Tag: "hostname", Dest: "host"},
+					},
+				},
+			},
+			{
+				Comment: "comment6",
+				Config: &aggregators.MinMax{
+					Period: "30s",
+				},
+			},
 		},
 	}
 	want := `# Configuration for telegraf agent
@@ -386,6 +439,17 @@ func TestTOML(t *testing.T) {
 
   ## Destination bucket to write into.
   bucket = "bucket1"
+[[processors.rename]]
+  ## Each replace renames one measurement, tag or field to dest.
+  [[processors.rename.replace]]
+    tag = "hostname"
+    dest = "host"
+[[aggregators.minmax]]
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
 `
 	if result := tc.TOML(); result != want {
 		t.Fatalf("telegraf config's toml is incorrect, got %s", result)
@@ -395,7 +459,158 @@ func TestTOML(t *testing.T) {
 	if err != nil {
 		t.Fatalf("telegraf toml parsing issue %s", err.Error())
 	}
+	if len(tcr.Plugins) != len(tc.Plugins) {
+		t.Fatalf("telegraf toml parsing issue, want %d plugins, got %d", len(tc.Plugins), len(tcr.Plugins))
+	}
 	if reflect.DeepEqual(tcr, tc) {
 		t.Fatalf("telegraf toml parsing issue, want %q, got %q", tc, tcr)
 	}
 }
+
+func TestDecodeTOML(t *testing.T) {
+	cases := []struct {
+		name string
+		toml string
+		want *TelegrafConfig
+		err  error
+	}{
+		{
+			name: "known and unknown plugins",
+			toml: `# A telegraf config
+[global_tags]
+  dc = "us-east-1"
+
+[agent]
+  interval = "10s"
+  round_interval = false
+  flush_interval = "30s"
+  hostname = "h1"
+
+[[inputs.cpu]]
+  percpu = false
+  totalcpu = true
+
+[[inputs.nginx]]
+  # Nginx status urls
+  urls = ["http://localhost/server_status"]
+  response_timeout = "5s"
+
+[[outputs.kafka]]
+  brokers = ["localhost:9092"]
+  topic = """
+telegraf
+"""
+  [outputs.kafka.topic_suffix]
+    method = "measurement"
+`,
+			want: &TelegrafConfig{
+				Agent: TelegrafAgentConfig{
+					Interval:      10000,
+					RoundInterval: new(bool),
+					FlushInterval: "30s",
+					Hostname:      "h1",
+				},
+				GlobalTags: map[string]string{"dc": "us-east-1"},
+				Plugins: []TelegrafPlugin{
+					{
+						Config: &inputs.CPUStats{},
+					},
+					{
+						Config: plugins.NewRaw(plugins.Input, "nginx", `[[inputs.nginx]]
+  # Nginx status urls
+  urls = ["http://localhost/server_status"]
+  response_timeout = "5s"
+
+`),
+					},
+					{
+						Config: plugins.NewRaw(plugins.Output, "kafka", `[[outputs.kafka]]
+  brokers = ["localhost:9092"]
+  topic = """
+telegraf
+"""
+  [outputs.kafka.topic_suffix]
+    method = "measurement"
+`),
+					},
+				},
+			},
+		},
+		{
+			name: "syntax error",
+			toml: "[agent]\n  interval = \"10s\n",
+			err: &Error{
+				Code: EInvalid,
+				Op:   OpDecodeTelegrafTOML,
+				Msg:  "Near line 2 (last key parsed 'agent.interval'): strings cannot contain newlines",
+			},
+		},
+		{
+			name: "missing agent",
+			toml: "[[inputs.cpu]]\n",
+			err: &Error{
+				Code: EInvalid,
+				Op:   OpDecodeTelegrafTOML,
+				Msg:  "agent is missing",
+			},
+		},
+		{
+			name: "unsupported agent option",
+			toml: "[agent]\n  interval = \"10s\"\n  flush_buffer_when_full = true\n",
+			err: &Error{
+				Code: EInvalid,
+				Op:   OpDecodeTelegrafTOML,
+				Msg:  `line 3: unsupported agent option "flush_buffer_when_full"`,
+			},
+		},
+		{
+			name: "bad agent duration",
+			toml: "[agent]\n  interval = \"10s\"\n\n  flush_jitter = \"5\"\n",
+			err: &Error{
+				Code: EInvalid,
+				Op:   OpDecodeTelegrafTOML,
+				Msg:  `line 4: agent flush_jitter: time: missing unit in duration "5"`,
+			},
+		},
+		{
+			name: "invalid known plugin",
+			toml: "[agent]\n  interval = \"10s\"\n\n[[inputs.cpu]]\n\n[[outputs.influxdb_v2]]\n  urls = [\"http://localhost:9999\"]\n",
+			err: &Error{
+				Code: EInvalid,
+				Op:   OpDecodeTelegrafTOML,
+				Msg:  "line 6: organization is missing for influxdb_v2 output plugin",
+			},
+		},
+		{
+			name: "unsupported table",
+			toml: "[agent]\n  interval = \"10s\"\n[serializers]\n  a = 1\n",
+			err: &Error{
+				Code: EInvalid,
+				Op:   OpDecodeTelegrafTOML,
+				Msg:  `line 3: unsupported table "serializers"`,
+			},
+		},
+	}
+	for _, c := range cases {
+		got := new(TelegrafConfig)
+		err := got.DecodeTOML(c.toml)
+		if diff := cmp.Diff(err, c.err); diff != "" {
+			t.Fatalf("%s decode failed, got err: %v, should be %v", c.name, err, c.err)
+		}
+		if c.err != nil {
+			continue
+		}
+		if diff := cmp.Diff(got, c.want, telegrafCmpOptions...); diff != "" {
+			t.Fatalf("failed %s, telegraf configs are different -got/+want\ndiff %s", c.name, diff)
+		}
+
+		// The toml of the decoded config decodes to the same config.
+		again := new(TelegrafConfig)
+		if err := again.DecodeTOML(got.TOML()); err != nil {
+			t.Fatalf("%s failed to decode its toml: %v", c.name, err)
+		}
+		if again.TOML() != got.TOML() {
+			t.Fatalf("%s toml is not stable, got %s, want %s", c.name, again.TOML(), got.TOML())
+		}
+	}
+}
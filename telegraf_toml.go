@@ -0,0 +1,322 @@
+package influxdb
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/influxdata/influxdb/telegraf/plugins"
+)
+
+// OpDecodeTelegrafTOML is the op of the errors of decoding a telegraf toml config.
+const OpDecodeTelegrafTOML = "DecodeTelegrafTOML"
+
+// DecodeTOML sets the agent, global tags and plugins of tc from a telegraf
+// toml config. The plugins that have a typed config in telegraf/plugins are
+// decoded into it; any other plugin is kept as its toml text, unchanged.
+//
+// The messages of the errors name the line of the config at fault.
+func (tc *TelegrafConfig) DecodeTOML(text string) error {
+	// Decode the whole config first, so that syntax errors are reported
+	// with the line numbers of the config.
+	var data map[string]interface{}
+	if _, err := toml.Decode(text, &data); err != nil {
+		return &Error{
+			Code: EInvalid,
+			Op:   OpDecodeTelegrafTOML,
+			Msg:  err.Error(),
+		}
+	}
+
+	blocks, err := splitTelegrafTOML(text)
+	if err != nil {
+		return err
+	}
+
+	var (
+		agent      *TelegrafAgentConfig
+		globalTags map[string]string
+		ps         []TelegrafPlugin
+	)
+	for _, b := range blocks {
+		switch b.section {
+		case "agent":
+			if agent, err = decodeTelegrafAgentTOML(b); err != nil {
+				return err
+			}
+		case "global_tags":
+			if globalTags, err = decodeTelegrafGlobalTagsTOML(b); err != nil {
+				return err
+			}
+		default:
+			p, err := decodeTelegrafPluginTOML(b)
+			if err != nil {
+				return err
+			}
+			ps = append(ps, TelegrafPlugin{Config: p})
+		}
+	}
+	if agent == nil {
+		return &Error{
+			Code: EInvalid,
+			Op:   OpDecodeTelegrafTOML,
+			Msg:  "agent is missing",
+		}
+	}
+
+	tc.Agent = *agent
+	tc.GlobalTags = globalTags
+	tc.Plugins = ps
+	return nil
+}
+
+// telegrafTOMLBlock is a top level table of a telegraf toml config, with the
+// tables nested in it.
+type telegrafTOMLBlock struct {
+	line    int    // line of the table header, starting at 1.
+	section string // name of the top level table, such as "agent" or "inputs".
+	name    string // name of the plugin, for plugin tables.
+	text    string
+}
+
+// errorf returns an invalid error at the line of the block.
+func (b telegrafTOMLBlock) errorf(format string, args ...interface{}) error {
+	return telegrafTOMLErrorf(b.line, format, args...)
+}
+
+func telegrafTOMLErrorf(line int, format string, args ...interface{}) error {
+	return &Error{
+		Code: EInvalid,
+		Op:   OpDecodeTelegrafTOML,
+		Msg:  fmt.Sprintf("line %d: %s", line, fmt.Sprintf(format, args...)),
+	}
+}
+
+// decode decodes the text of the block into v.
+func (b telegrafTOMLBlock) decode(v interface{}) (toml.MetaData, error) {
+	md, err := toml.Decode(b.text, v)
+	if err != nil {
+		return md, b.errorf("%v", err)
+	}
+	return md, nil
+}
+
+// keyLine returns the line of key in the block, or the line of the block if
+// it is not found.
+func (b telegrafTOMLBlock) keyLine(key string) int {
+	re := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=`)
+	for i, line := range strings.Split(b.text, "\n") {
+		if re.MatchString(line) {
+			return b.line + i
+		}
+	}
+	return b.line
+}
+
+// tomlTableHeader matches the header of a toml table, [a.b] or [[a.b]].
+var tomlTableHeader = regexp.MustCompile(`^\s*(\[\[?)\s*([^\[\]]+?)\s*\]\]?\s*(#.*)?$`)
+
+// splitTelegrafTOML splits a telegraf toml config into its top level tables.
+func splitTelegrafTOML(text string) ([]telegrafTOMLBlock, error) {
+	var (
+		blocks []telegrafTOMLBlock
+		cur    *telegrafTOMLBlock
+		lines  []string
+		quote  string // delimiter of the open multi-line string
+	)
+	flush := func() {
+		if cur != nil {
+			cur.text = strings.Join(lines, "")
+			blocks = append(blocks, *cur)
+		}
+		lines = lines[:0]
+	}
+
+	// The lines keep their newlines, so that the text of the blocks is the
+	// text of the config.
+	for i, line := range strings.SplitAfter(text, "\n") {
+		n := i + 1
+		if quote != "" {
+			if strings.Count(line, quote)%2 == 1 {
+				quote = ""
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		if m := tomlTableHeader.FindStringSubmatch(line); m != nil {
+			isArray := m[1] == "[["
+			keys := strings.Split(m[2], ".")
+			for k := range keys {
+				keys[k] = strings.TrimSpace(keys[k])
+			}
+
+			switch keys[0] {
+			case "inputs", "outputs", "processors", "aggregators":
+				switch {
+				case !isArray && len(keys) == 1:
+					// The parent table of the plugins, as written by toml
+					// encoders; it has no keys of its own.
+					flush()
+					cur = nil
+					continue
+				case isArray && len(keys) == 2:
+					flush()
+					cur = &telegrafTOMLBlock{line: n, section: keys[0], name: keys[1]}
+				case cur != nil && len(keys) > 2 && cur.section == keys[0] && cur.name == keys[1]:
+					// A table nested in the current plugin.
+				default:
+					return nil, telegrafTOMLErrorf(n, "unexpected table %q", m[2])
+				}
+			case "agent", "global_tags":
+				if isArray || len(keys) != 1 {
+					return nil, telegrafTOMLErrorf(n, "unexpected table %q", m[2])
+				}
+				flush()
+				cur = &telegrafTOMLBlock{line: n, section: keys[0]}
+			default:
+				return nil, telegrafTOMLErrorf(n, "unsupported table %q", m[2])
+			}
+		} else if cur == nil {
+			if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				return nil, telegrafTOMLErrorf(n, "key outside of a table")
+			}
+			continue
+		}
+
+		lines = append(lines, line)
+		for _, q := range []string{`"""`, `'''`} {
+			if strings.Count(line, q)%2 == 1 {
+				quote = q
+				break
+			}
+		}
+	}
+	flush()
+	return blocks, nil
+}
+
+// telegrafAgentTOML is the toml of the agent of a telegraf config.
+type telegrafAgentTOML struct {
+	Interval          string `toml:"interval"`
+	RoundInterval     *bool  `toml:"round_interval"`
+	MetricBatchSize   int64  `toml:"metric_batch_size"`
+	MetricBufferLimit int64  `toml:"metric_buffer_limit"`
+	CollectionJitter  string `toml:"collection_jitter"`
+	FlushInterval     string `toml:"flush_interval"`
+	FlushJitter       string `toml:"flush_jitter"`
+	Precision         string `toml:"precision"`
+	Debug             bool   `toml:"debug"`
+	Quiet             bool   `toml:"quiet"`
+	Logfile           string `toml:"logfile"`
+	Hostname          string `toml:"hostname"`
+	OmitHostname      bool   `toml:"omit_hostname"`
+}
+
+func decodeTelegrafAgentTOML(b telegrafTOMLBlock) (*TelegrafAgentConfig, error) {
+	var v struct {
+		Agent telegrafAgentTOML `toml:"agent"`
+	}
+	md, err := b.decode(&v)
+	if err != nil {
+		return nil, err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		key := undecoded[0][len(undecoded[0])-1]
+		return nil, telegrafTOMLErrorf(b.keyLine(key), "unsupported agent option %q", key)
+	}
+
+	a := v.Agent
+	if !md.IsDefined("agent", "interval") {
+		return nil, b.errorf("agent interval is missing")
+	}
+	interval, err := time.ParseDuration(a.Interval)
+	if err != nil {
+		return nil, telegrafTOMLErrorf(b.keyLine("interval"), "agent interval: %v", err)
+	}
+	for _, d := range []struct {
+		key   string
+		value string
+	}{
+		{"collection_jitter", a.CollectionJitter},
+		{"flush_interval", a.FlushInterval},
+		{"flush_jitter", a.FlushJitter},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(d.value); err != nil {
+			return nil, telegrafTOMLErrorf(b.keyLine(d.key), "agent %s: %v", d.key, err)
+		}
+	}
+
+	return &TelegrafAgentConfig{
+		Interval:          interval.Nanoseconds() / 1000000,
+		RoundInterval:     a.RoundInterval,
+		MetricBatchSize:   a.MetricBatchSize,
+		MetricBufferLimit: a.MetricBufferLimit,
+		CollectionJitter:  a.CollectionJitter,
+		FlushInterval:     a.FlushInterval,
+		FlushJitter:       a.FlushJitter,
+		Precision:         a.Precision,
+		Debug:             a.Debug,
+		Quiet:             a.Quiet,
+		Logfile:           a.Logfile,
+		Hostname:          a.Hostname,
+		OmitHostname:      a.OmitHostname,
+	}, nil
+}
+
+func decodeTelegrafGlobalTagsTOML(b telegrafTOMLBlock) (map[string]string, error) {
+	var v struct {
+		GlobalTags map[string]string `toml:"global_tags"`
+	}
+	if _, err := b.decode(&v); err != nil {
+		return nil, err
+	}
+	return v.GlobalTags, nil
+}
+
+func decodeTelegrafPluginTOML(b telegrafTOMLBlock) (plugins.Config, error) {
+	typ := plugins.Type(strings.TrimSuffix(b.section, "s"))
+	tpFn, ok := availablePlugins[typ][b.name]
+	if !ok {
+		return plugins.NewRaw(typ, b.name, b.text), nil
+	}
+
+	var data map[string]map[string][]map[string]interface{}
+	if _, err := b.decode(&data); err != nil {
+		return nil, err
+	}
+	p := tpFn()
+	if err := p.UnmarshalTOML(data[b.section][b.name][0]); err != nil {
+		return nil, b.errorf("%v", err)
+	}
+	return p, nil
+}
+
+// UnmarshalTOML implements toml.Unmarshaler interface. The parsed data is
+// encoded back to toml and decoded with DecodeTOML, so the comments of the
+// plugins kept as toml are lost.
+func (tc *TelegrafConfig) UnmarshalTOML(data interface{}) error {
+	dataOk, ok := data.(map[string]interface{})
+	if !ok {
+		return &Error{
+			Code: EInvalid,
+			Op:   OpDecodeTelegrafTOML,
+			Msg:  "blank string",
+		}
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(dataOk); err != nil {
+		return &Error{
+			Code: EInvalid,
+			Op:   OpDecodeTelegrafTOML,
+			Err:  err,
+		}
+	}
+	return tc.DecodeTOML(buf.String())
+}
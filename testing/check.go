@@ -0,0 +1,522 @@
+package testing
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	checkOneID   = "020f755c3c082000"
+	checkTwoID   = "020f755c3c082001"
+	checkThreeID = "020f755c3c082002"
+)
+
+var checkCmpOptions = cmp.Options{
+	cmp.Transformer("Sort", func(in []*platform.Check) []*platform.Check {
+		out := append([]*platform.Check(nil), in...)
+		sort.Slice(out, func(i, j int) bool {
+			return out[i].ID.String() > out[j].ID.String()
+		})
+		return out
+	}),
+}
+
+// CheckFields will include the IDGenerator, and checks
+type CheckFields struct {
+	IDGenerator platform.IDGenerator
+	Checks      []*platform.Check
+}
+
+func newThresholdCheck(id string, orgID platform.ID, name string) *platform.Check {
+	c := &platform.Check{
+		OrganizationID: orgID,
+		Name:           name,
+		Status:         platform.CheckStatusActive,
+		Type:           platform.ThresholdCheckType,
+		Query:          `from(bucket: "telegraf") |> range(start: -1m) |> filter(fn: (r) => r._measurement == "cpu") |> mean()`,
+		Every:          "1m",
+		Thresholds: []platform.Threshold{
+			{Level: platform.LevelCrit, Op: platform.ThresholdGreater, Value: 90},
+		},
+	}
+	if id != "" {
+		c.ID = MustIDBase16(id)
+	}
+	return c
+}
+
+// CheckService tests all the service functions.
+func CheckService(
+	init func(CheckFields, *testing.T) (platform.CheckService, string, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(CheckFields, *testing.T) (platform.CheckService, string, func()),
+			t *testing.T)
+	}{
+		{
+			name: "CreateCheck",
+			fn:   CreateCheck,
+		},
+		{
+			name: "FindCheckByID",
+			fn:   FindCheckByID,
+		},
+		{
+			name: "FindChecks",
+			fn:   FindChecks,
+		},
+		{
+			name: "UpdateCheck",
+			fn:   UpdateCheck,
+		},
+		{
+			name: "DeleteCheck",
+			fn:   DeleteCheck,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// CreateCheck testing
+func CreateCheck(
+	init func(CheckFields, *testing.T) (platform.CheckService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		check *platform.Check
+	}
+	type wants struct {
+		err    error
+		checks []*platform.Check
+	}
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "create check assigns an id",
+			fields: CheckFields{
+				IDGenerator: mock.NewIDGenerator(checkTwoID, t),
+				Checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "existing"),
+				},
+			},
+			args: args{
+				check: func() *platform.Check {
+					c := newThresholdCheck("", platform.ID(1), "cpu")
+					c.Status = ""
+					return c
+				}(),
+			},
+			wants: wants{
+				checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "existing"),
+					newThresholdCheck(checkTwoID, platform.ID(1), "cpu"),
+				},
+			},
+		},
+		{
+			name: "create check without org id",
+			fields: CheckFields{
+				IDGenerator: mock.NewIDGenerator(checkTwoID, t),
+				Checks:      []*platform.Check{},
+			},
+			args: args{
+				check: newThresholdCheck("", 0, "cpu"),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EInvalid,
+					Msg:  "org id is invalid",
+					Op:   platform.OpCreateCheck,
+				},
+				checks: []*platform.Check{},
+			},
+		},
+		{
+			name: "create invalid check",
+			fields: CheckFields{
+				IDGenerator: mock.NewIDGenerator(checkTwoID, t),
+				Checks:      []*platform.Check{},
+			},
+			args: args{
+				check: func() *platform.Check {
+					c := newThresholdCheck("", platform.ID(1), "cpu")
+					c.Thresholds = nil
+					return c
+				}(),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EInvalid,
+					Msg:  "threshold check requires at least one threshold",
+					Op:   platform.OpCreateCheck,
+				},
+				checks: []*platform.Check{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateCheck(ctx, tt.args.check)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			checks, _, err := s.FindChecks(ctx, platform.CheckFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve checks: %v", err)
+			}
+			if diff := cmp.Diff(checks, tt.wants.checks, checkCmpOptions...); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindCheckByID testing
+func FindCheckByID(
+	init func(CheckFields, *testing.T) (platform.CheckService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id platform.ID
+	}
+	type wants struct {
+		err   error
+		check *platform.Check
+	}
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "find check by id",
+			fields: CheckFields{
+				Checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "a"),
+					newThresholdCheck(checkTwoID, platform.ID(1), "b"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(checkTwoID),
+			},
+			wants: wants{
+				check: newThresholdCheck(checkTwoID, platform.ID(1), "b"),
+			},
+		},
+		{
+			name: "check not found",
+			fields: CheckFields{
+				Checks: []*platform.Check{},
+			},
+			args: args{
+				id: MustIDBase16(checkOneID),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpFindCheckByID,
+					Msg:  platform.ErrCheckNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			check, err := s.FindCheckByID(ctx, tt.args.id)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(check, tt.wants.check); diff != "" {
+				t.Errorf("check is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindChecks testing
+func FindChecks(
+	init func(CheckFields, *testing.T) (platform.CheckService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter platform.CheckFilter
+	}
+	type wants struct {
+		err    error
+		checks []*platform.Check
+	}
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "find all checks",
+			fields: CheckFields{
+				Checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "a"),
+					newThresholdCheck(checkTwoID, platform.ID(2), "b"),
+				},
+			},
+			wants: wants{
+				checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "a"),
+					newThresholdCheck(checkTwoID, platform.ID(2), "b"),
+				},
+			},
+		},
+		{
+			name: "find checks by organization id",
+			fields: CheckFields{
+				Checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "a"),
+					newThresholdCheck(checkTwoID, platform.ID(2), "b"),
+					newThresholdCheck(checkThreeID, platform.ID(1), "c"),
+				},
+			},
+			args: args{
+				filter: platform.CheckFilter{
+					OrganizationID: idPtr(platform.ID(1)),
+				},
+			},
+			wants: wants{
+				checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "a"),
+					newThresholdCheck(checkThreeID, platform.ID(1), "c"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			checks, n, err := s.FindChecks(ctx, tt.args.filter)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if n != len(tt.wants.checks) {
+				t.Errorf("expected %d checks, got %d", len(tt.wants.checks), n)
+			}
+			if diff := cmp.Diff(checks, tt.wants.checks, checkCmpOptions...); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateCheck testing
+func UpdateCheck(
+	init func(CheckFields, *testing.T) (platform.CheckService, string, func()),
+	t *testing.T,
+) {
+	name := "cpu high"
+	level := platform.LevelWarn
+	badEvery := "soon"
+
+	type args struct {
+		id  platform.ID
+		upd platform.CheckUpdate
+	}
+	type wants struct {
+		err   error
+		check *platform.Check
+	}
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "update name and thresholds",
+			fields: CheckFields{
+				Checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "cpu"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(checkOneID),
+				upd: platform.CheckUpdate{
+					Name: &name,
+					Thresholds: []platform.Threshold{
+						{Level: level, Op: platform.ThresholdGreater, Value: 80},
+					},
+				},
+			},
+			wants: wants{
+				check: func() *platform.Check {
+					c := newThresholdCheck(checkOneID, platform.ID(1), "cpu high")
+					c.Thresholds = []platform.Threshold{
+						{Level: level, Op: platform.ThresholdGreater, Value: 80},
+					}
+					return c
+				}(),
+			},
+		},
+		{
+			name: "update makes check invalid",
+			fields: CheckFields{
+				Checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "cpu"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(checkOneID),
+				upd: platform.CheckUpdate{
+					Every: &badEvery,
+				},
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EInvalid,
+					Op:   platform.OpUpdateCheck,
+					Msg:  "check every must be a positive duration",
+				},
+			},
+		},
+		{
+			name: "update missing check",
+			fields: CheckFields{
+				Checks: []*platform.Check{},
+			},
+			args: args{
+				id: MustIDBase16(checkOneID),
+				upd: platform.CheckUpdate{
+					Name: &name,
+				},
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpUpdateCheck,
+					Msg:  platform.ErrCheckNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			check, err := s.UpdateCheck(ctx, tt.args.id, tt.args.upd)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(check, tt.wants.check); diff != "" {
+				t.Errorf("check is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteCheck testing
+func DeleteCheck(
+	init func(CheckFields, *testing.T) (platform.CheckService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id platform.ID
+	}
+	type wants struct {
+		err    error
+		checks []*platform.Check
+	}
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "delete check",
+			fields: CheckFields{
+				Checks: []*platform.Check{
+					newThresholdCheck(checkOneID, platform.ID(1), "a"),
+					newThresholdCheck(checkTwoID, platform.ID(1), "b"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(checkOneID),
+			},
+			wants: wants{
+				checks: []*platform.Check{
+					newThresholdCheck(checkTwoID, platform.ID(1), "b"),
+				},
+			},
+		},
+		{
+			name: "delete missing check",
+			fields: CheckFields{
+				Checks: []*platform.Check{
+					newThresholdCheck(checkTwoID, platform.ID(1), "b"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(checkOneID),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpDeleteCheck,
+					Msg:  platform.ErrCheckNotFound,
+				},
+				checks: []*platform.Check{
+					newThresholdCheck(checkTwoID, platform.ID(1), "b"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteCheck(ctx, tt.args.id)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			checks, _, err := s.FindChecks(ctx, platform.CheckFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve checks: %v", err)
+			}
+			if diff := cmp.Diff(checks, tt.wants.checks, checkCmpOptions...); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -118,6 +118,25 @@ func KVGet(
 				err: kv.ErrKeyNotFound,
 			},
 		},
+		{
+			name: "get key with an empty value",
+			fields: KVStoreFields{
+				Bucket: []byte("bucket"),
+				Pairs: []kv.Pair{
+					{
+						Key:   []byte("hello"),
+						Value: []byte{},
+					},
+				},
+			},
+			args: args{
+				bucket: []byte("bucket"),
+				key:    []byte("hello"),
+			},
+			wants: wants{
+				val: []byte{},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -409,6 +428,56 @@ func KVCursor(
 				},
 			},
 		},
+		{
+			name: "cursor with empty values",
+			fields: KVStoreFields{
+				Bucket: []byte("bucket"),
+				Pairs: []kv.Pair{
+					{
+						Key:   []byte("a"),
+						Value: []byte{},
+					},
+					{
+						Key:   []byte("b"),
+						Value: []byte("2"),
+					},
+					{
+						Key:   []byte("c"),
+						Value: []byte{},
+					},
+					{
+						Key:   []byte("d"),
+						Value: []byte("4"),
+					},
+				},
+			},
+			args: args{
+				bucket: []byte("bucket"),
+				seek:   []byte("b"),
+			},
+			wants: wants{
+				first: kv.Pair{
+					Key:   []byte("a"),
+					Value: []byte{},
+				},
+				last: kv.Pair{
+					Key:   []byte("d"),
+					Value: []byte("4"),
+				},
+				seek: kv.Pair{
+					Key:   []byte("b"),
+					Value: []byte("2"),
+				},
+				next: kv.Pair{
+					Key:   []byte("c"),
+					Value: []byte{},
+				},
+				prev: kv.Pair{
+					Key:   []byte("b"),
+					Value: []byte("2"),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
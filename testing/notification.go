@@ -0,0 +1,544 @@
+package testing
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	endpointOneID = "020f755c3c082100"
+	endpointTwoID = "020f755c3c082101"
+	ruleOneID     = "020f755c3c082200"
+	ruleTwoID     = "020f755c3c082201"
+)
+
+var notificationCmpOptions = cmp.Options{
+	cmp.Transformer("SortRules", func(in []*platform.NotificationRule) []*platform.NotificationRule {
+		out := append([]*platform.NotificationRule(nil), in...)
+		sort.Slice(out, func(i, j int) bool {
+			return out[i].ID.String() > out[j].ID.String()
+		})
+		return out
+	}),
+	cmp.Transformer("SortEndpoints", func(in []*platform.NotificationEndpoint) []*platform.NotificationEndpoint {
+		out := append([]*platform.NotificationEndpoint(nil), in...)
+		sort.Slice(out, func(i, j int) bool {
+			return out[i].ID.String() > out[j].ID.String()
+		})
+		return out
+	}),
+}
+
+// NotificationService is the union of the notification rule and endpoint services.
+type NotificationService interface {
+	platform.NotificationRuleService
+	platform.NotificationEndpointService
+}
+
+// NotificationFields will include the IDGenerator, notification rules and endpoints.
+type NotificationFields struct {
+	IDGenerator platform.IDGenerator
+	Rules       []*platform.NotificationRule
+	Endpoints   []*platform.NotificationEndpoint
+}
+
+func newHTTPEndpoint(id string, orgID platform.ID, name string) *platform.NotificationEndpoint {
+	e := &platform.NotificationEndpoint{
+		OrganizationID: orgID,
+		Name:           name,
+		Type:           platform.HTTPNotificationEndpointType,
+		URL:            "http://localhost:8080/alerts",
+	}
+	if id != "" {
+		e.ID = MustIDBase16(id)
+	}
+	return e
+}
+
+func newCritRule(id string, orgID platform.ID, endpointID string) *platform.NotificationRule {
+	r := &platform.NotificationRule{
+		OrganizationID: orgID,
+		Name:           "crit to http",
+		Status:         platform.CheckStatusActive,
+		EndpointID:     MustIDBase16(endpointID),
+		StatusRules: []platform.StatusRule{
+			{CurrentLevel: platform.LevelCrit},
+		},
+	}
+	if id != "" {
+		r.ID = MustIDBase16(id)
+	}
+	return r
+}
+
+// NotificationServices tests all the notification rule and endpoint service functions.
+func NotificationServices(
+	init func(NotificationFields, *testing.T) (NotificationService, string, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(NotificationFields, *testing.T) (NotificationService, string, func()),
+			t *testing.T)
+	}{
+		{
+			name: "CreateNotificationEndpoint",
+			fn:   CreateNotificationEndpoint,
+		},
+		{
+			name: "UpdateNotificationEndpoint",
+			fn:   UpdateNotificationEndpoint,
+		},
+		{
+			name: "DeleteNotificationEndpoint",
+			fn:   DeleteNotificationEndpoint,
+		},
+		{
+			name: "CreateNotificationRule",
+			fn:   CreateNotificationRule,
+		},
+		{
+			name: "FindNotificationRules",
+			fn:   FindNotificationRules,
+		},
+		{
+			name: "DeleteNotificationRule",
+			fn:   DeleteNotificationRule,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// CreateNotificationEndpoint testing
+func CreateNotificationEndpoint(
+	init func(NotificationFields, *testing.T) (NotificationService, string, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		err       error
+		endpoints []*platform.NotificationEndpoint
+	}
+
+	tests := []struct {
+		name     string
+		fields   NotificationFields
+		endpoint *platform.NotificationEndpoint
+		wants    wants
+	}{
+		{
+			name: "create endpoint assigns an id",
+			fields: NotificationFields{
+				IDGenerator: mock.NewIDGenerator(endpointTwoID, t),
+				Endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+			},
+			endpoint: newHTTPEndpoint("", platform.ID(1), "b"),
+			wants: wants{
+				endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+					newHTTPEndpoint(endpointTwoID, platform.ID(1), "b"),
+				},
+			},
+		},
+		{
+			name: "create endpoint with invalid url",
+			fields: NotificationFields{
+				IDGenerator: mock.NewIDGenerator(endpointTwoID, t),
+			},
+			endpoint: func() *platform.NotificationEndpoint {
+				e := newHTTPEndpoint("", platform.ID(1), "b")
+				e.URL = "localhost"
+				return e
+			}(),
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EInvalid,
+					Op:   platform.OpCreateNotificationEndpoint,
+					Msg:  `invalid notification endpoint url "localhost"`,
+				},
+				endpoints: []*platform.NotificationEndpoint{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateNotificationEndpoint(ctx, tt.endpoint)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			endpoints, _, err := s.FindNotificationEndpoints(ctx, platform.NotificationEndpointFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve notification endpoints: %v", err)
+			}
+			if diff := cmp.Diff(endpoints, tt.wants.endpoints, notificationCmpOptions...); diff != "" {
+				t.Errorf("notification endpoints are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateNotificationEndpoint testing
+func UpdateNotificationEndpoint(
+	init func(NotificationFields, *testing.T) (NotificationService, string, func()),
+	t *testing.T,
+) {
+	u := "https://example.com/hook"
+
+	type wants struct {
+		err      error
+		endpoint *platform.NotificationEndpoint
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationFields
+		id     platform.ID
+		upd    platform.NotificationEndpointUpdate
+		wants  wants
+	}{
+		{
+			name: "update url and headers",
+			fields: NotificationFields{
+				Endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+			},
+			id: MustIDBase16(endpointOneID),
+			upd: platform.NotificationEndpointUpdate{
+				URL:     &u,
+				Headers: map[string]string{"Authorization": "Bearer abc"},
+			},
+			wants: wants{
+				endpoint: func() *platform.NotificationEndpoint {
+					e := newHTTPEndpoint(endpointOneID, platform.ID(1), "a")
+					e.URL = u
+					e.Headers = map[string]string{"Authorization": "Bearer abc"}
+					return e
+				}(),
+			},
+		},
+		{
+			name: "update missing endpoint",
+			id:   MustIDBase16(endpointOneID),
+			upd: platform.NotificationEndpointUpdate{
+				URL: &u,
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpUpdateNotificationEndpoint,
+					Msg:  platform.ErrNotificationEndpointNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			endpoint, err := s.UpdateNotificationEndpoint(ctx, tt.id, tt.upd)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(endpoint, tt.wants.endpoint); diff != "" {
+				t.Errorf("notification endpoint is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteNotificationEndpoint testing
+func DeleteNotificationEndpoint(
+	init func(NotificationFields, *testing.T) (NotificationService, string, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		err       error
+		endpoints []*platform.NotificationEndpoint
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationFields
+		id     platform.ID
+		wants  wants
+	}{
+		{
+			name: "delete unused endpoint",
+			fields: NotificationFields{
+				Endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+					newHTTPEndpoint(endpointTwoID, platform.ID(1), "b"),
+				},
+			},
+			id: MustIDBase16(endpointTwoID),
+			wants: wants{
+				endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+			},
+		},
+		{
+			name: "delete endpoint used by a rule",
+			fields: NotificationFields{
+				Endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+				Rules: []*platform.NotificationRule{
+					newCritRule(ruleOneID, platform.ID(1), endpointOneID),
+				},
+			},
+			id: MustIDBase16(endpointOneID),
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EConflict,
+					Op:   platform.OpDeleteNotificationEndpoint,
+					Msg:  "notification endpoint is used by notification rule " + ruleOneID,
+				},
+				endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteNotificationEndpoint(ctx, tt.id)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			endpoints, _, err := s.FindNotificationEndpoints(ctx, platform.NotificationEndpointFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve notification endpoints: %v", err)
+			}
+			if diff := cmp.Diff(endpoints, tt.wants.endpoints, notificationCmpOptions...); diff != "" {
+				t.Errorf("notification endpoints are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// CreateNotificationRule testing
+func CreateNotificationRule(
+	init func(NotificationFields, *testing.T) (NotificationService, string, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		err   error
+		rules []*platform.NotificationRule
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationFields
+		rule   *platform.NotificationRule
+		wants  wants
+	}{
+		{
+			name: "create rule assigns an id",
+			fields: NotificationFields{
+				IDGenerator: mock.NewIDGenerator(ruleOneID, t),
+				Endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+			},
+			rule: func() *platform.NotificationRule {
+				r := newCritRule("", platform.ID(1), endpointOneID)
+				r.Status = ""
+				return r
+			}(),
+			wants: wants{
+				rules: []*platform.NotificationRule{
+					newCritRule(ruleOneID, platform.ID(1), endpointOneID),
+				},
+			},
+		},
+		{
+			name: "create rule with missing endpoint",
+			fields: NotificationFields{
+				IDGenerator: mock.NewIDGenerator(ruleOneID, t),
+			},
+			rule: newCritRule("", platform.ID(1), endpointOneID),
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpCreateNotificationRule,
+					Msg:  platform.ErrNotificationEndpointNotFound,
+				},
+				rules: []*platform.NotificationRule{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateNotificationRule(ctx, tt.rule)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			rules, _, err := s.FindNotificationRules(ctx, platform.NotificationRuleFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve notification rules: %v", err)
+			}
+			if diff := cmp.Diff(rules, tt.wants.rules, notificationCmpOptions...); diff != "" {
+				t.Errorf("notification rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindNotificationRules testing
+func FindNotificationRules(
+	init func(NotificationFields, *testing.T) (NotificationService, string, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name   string
+		fields NotificationFields
+		filter platform.NotificationRuleFilter
+		rules  []*platform.NotificationRule
+	}{
+		{
+			name: "find rules by organization id",
+			fields: NotificationFields{
+				Endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+				Rules: []*platform.NotificationRule{
+					newCritRule(ruleOneID, platform.ID(1), endpointOneID),
+					newCritRule(ruleTwoID, platform.ID(2), endpointOneID),
+				},
+			},
+			filter: platform.NotificationRuleFilter{
+				OrganizationID: idPtr(platform.ID(2)),
+			},
+			rules: []*platform.NotificationRule{
+				newCritRule(ruleTwoID, platform.ID(2), endpointOneID),
+			},
+		},
+		{
+			name: "find rule by id",
+			fields: NotificationFields{
+				Endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+				Rules: []*platform.NotificationRule{
+					newCritRule(ruleOneID, platform.ID(1), endpointOneID),
+					newCritRule(ruleTwoID, platform.ID(2), endpointOneID),
+				},
+			},
+			filter: platform.NotificationRuleFilter{
+				ID: idPtr(MustIDBase16(ruleOneID)),
+			},
+			rules: []*platform.NotificationRule{
+				newCritRule(ruleOneID, platform.ID(1), endpointOneID),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			rules, n, err := s.FindNotificationRules(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("failed to retrieve notification rules: %v", err)
+			}
+			if n != len(tt.rules) {
+				t.Errorf("expected %d notification rules, got %d", len(tt.rules), n)
+			}
+			if diff := cmp.Diff(rules, tt.rules, notificationCmpOptions...); diff != "" {
+				t.Errorf("notification rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteNotificationRule testing
+func DeleteNotificationRule(
+	init func(NotificationFields, *testing.T) (NotificationService, string, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		err   error
+		rules []*platform.NotificationRule
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationFields
+		id     platform.ID
+		wants  wants
+	}{
+		{
+			name: "delete rule",
+			fields: NotificationFields{
+				Endpoints: []*platform.NotificationEndpoint{
+					newHTTPEndpoint(endpointOneID, platform.ID(1), "a"),
+				},
+				Rules: []*platform.NotificationRule{
+					newCritRule(ruleOneID, platform.ID(1), endpointOneID),
+				},
+			},
+			id: MustIDBase16(ruleOneID),
+			wants: wants{
+				rules: []*platform.NotificationRule{},
+			},
+		},
+		{
+			name: "delete missing rule",
+			id:   MustIDBase16(ruleOneID),
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpDeleteNotificationRule,
+					Msg:  platform.ErrNotificationRuleNotFound,
+				},
+				rules: []*platform.NotificationRule{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteNotificationRule(ctx, tt.id)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			rules, _, err := s.FindNotificationRules(ctx, platform.NotificationRuleFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve notification rules: %v", err)
+			}
+			if diff := cmp.Diff(rules, tt.wants.rules, notificationCmpOptions...); diff != "" {
+				t.Errorf("notification rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
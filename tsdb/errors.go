@@ -50,3 +50,13 @@ type PartialWriteError struct {
 func (e PartialWriteError) Error() string {
 	return fmt.Sprintf("partial write: %s dropped=%d", e.Reason, e.Dropped)
 }
+
+// InvalidPointsError indicates that some points of a batch cannot be written.
+// Reasons holds why the points of each rejected key are rejected.
+type InvalidPointsError struct {
+	Reasons map[string]string
+}
+
+func (e InvalidPointsError) Error() string {
+	return fmt.Sprintf("invalid points: dropped=%d", len(e.Reasons))
+}
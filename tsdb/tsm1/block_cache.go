@@ -0,0 +1,94 @@
+package tsm1
+
+import (
+	"container/list"
+	"sync"
+)
+
+// blockCache is a least recently used cache of the raw blocks read from cold
+// TSM files.  It is shared by all the cold files of a FileStore and bounded by
+// the total size of the cached blocks.
+type blockCache struct {
+	mu      sync.Mutex
+	maxSize uint64
+	size    uint64
+	list    *list.List
+	blocks  map[blockCacheKey]*list.Element
+}
+
+type blockCacheKey struct {
+	path   string
+	offset int64
+}
+
+type blockCacheEntry struct {
+	key blockCacheKey
+	b   []byte
+}
+
+// newBlockCache returns a cache that holds up to maxSize bytes of blocks.
+func newBlockCache(maxSize uint64) *blockCache {
+	return &blockCache{
+		maxSize: maxSize,
+		list:    list.New(),
+		blocks:  make(map[blockCacheKey]*list.Element),
+	}
+}
+
+// get returns the block of the file at path stored at offset, if it is cached.
+// The returned slice must not be modified.
+func (c *blockCache) get(path string, offset int64) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.blocks[blockCacheKey{path: path, offset: offset}]
+	if !ok {
+		return nil, false
+	}
+	c.list.MoveToFront(e)
+	return e.Value.(*blockCacheEntry).b, true
+}
+
+// put adds the block of the file at path stored at offset to the cache and
+// evicts the least recently used blocks to stay within the size limit.
+func (c *blockCache) put(path string, offset int64, b []byte) {
+	if uint64(len(b)) > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := blockCacheKey{path: path, offset: offset}
+	if e, ok := c.blocks[key]; ok {
+		c.list.MoveToFront(e)
+		return
+	}
+
+	c.blocks[key] = c.list.PushFront(&blockCacheEntry{key: key, b: b})
+	c.size += uint64(len(b))
+
+	for c.size > c.maxSize {
+		e := c.list.Back()
+		entry := e.Value.(*blockCacheEntry)
+		c.list.Remove(e)
+		delete(c.blocks, entry.key)
+		c.size -= uint64(len(entry.b))
+	}
+}
+
+// evict removes all the blocks of the file at path from the cache.
+func (c *blockCache) evict(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for e := c.list.Front(); e != nil; {
+		next := e.Next()
+		if entry := e.Value.(*blockCacheEntry); entry.key.path == path {
+			c.list.Remove(e)
+			delete(c.blocks, entry.key)
+			c.size -= uint64(len(entry.b))
+		}
+		e = next
+	}
+}
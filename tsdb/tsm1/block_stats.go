@@ -0,0 +1,274 @@
+package tsm1
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// BlockStats contains statistics about the values stored in a block.  They are
+// recorded in the index entry for the block in version 2 and later TSM files and
+// allow some aggregates to be answered without decoding the block.
+//
+// Min, Max, Sum, First and Last hold the bits of values of the block's type and
+// should be read with the accessor for that type.  Blocks of strings only record
+// the count.  A Count of zero means the statistics are not available, as is the
+// case for index entries read from version 1 files.
+type BlockStats struct {
+	// Count is the number of values in the block.
+	Count uint32
+
+	Min, Max, Sum, First, Last uint64
+}
+
+// HasStats returns true if the statistics were recorded for the block.
+func (s *BlockStats) HasStats() bool {
+	return s.Count > 0
+}
+
+// FloatStats returns the statistics of a block of float values.
+func (s *BlockStats) FloatStats() (min, max, sum, first, last float64) {
+	return math.Float64frombits(s.Min), math.Float64frombits(s.Max), math.Float64frombits(s.Sum),
+		math.Float64frombits(s.First), math.Float64frombits(s.Last)
+}
+
+// IntegerStats returns the statistics of a block of integer values.  The sum
+// wraps around on overflow.
+func (s *BlockStats) IntegerStats() (min, max, sum, first, last int64) {
+	return int64(s.Min), int64(s.Max), int64(s.Sum), int64(s.First), int64(s.Last)
+}
+
+// UnsignedStats returns the statistics of a block of unsigned values.  The sum
+// wraps around on overflow.
+func (s *BlockStats) UnsignedStats() (min, max, sum, first, last uint64) {
+	return s.Min, s.Max, s.Sum, s.First, s.Last
+}
+
+// BooleanStats returns the statistics of a block of boolean values.  The sum is
+// the number of true values in the block.
+func (s *BlockStats) BooleanStats() (min, max bool, sum uint64, first, last bool) {
+	return s.Min == 1, s.Max == 1, s.Sum, s.First == 1, s.Last == 1
+}
+
+func (s *BlockStats) unmarshalBinary(b []byte) {
+	s.Count = binary.BigEndian.Uint32(b[0:4])
+	s.Min = binary.BigEndian.Uint64(b[4:12])
+	s.Max = binary.BigEndian.Uint64(b[12:20])
+	s.Sum = binary.BigEndian.Uint64(b[20:28])
+	s.First = binary.BigEndian.Uint64(b[28:36])
+	s.Last = binary.BigEndian.Uint64(b[36:44])
+}
+
+func (s *BlockStats) appendTo(b []byte) {
+	binary.BigEndian.PutUint32(b[0:4], s.Count)
+	binary.BigEndian.PutUint64(b[4:12], s.Min)
+	binary.BigEndian.PutUint64(b[12:20], s.Max)
+	binary.BigEndian.PutUint64(b[20:28], s.Sum)
+	binary.BigEndian.PutUint64(b[28:36], s.First)
+	binary.BigEndian.PutUint64(b[36:44], s.Last)
+}
+
+func (s *BlockStats) addFloat(v float64) {
+	bits := math.Float64bits(v)
+	if s.Count == 0 {
+		s.Min, s.Max, s.Sum, s.First = bits, bits, bits, bits
+	} else {
+		if v < math.Float64frombits(s.Min) {
+			s.Min = bits
+		}
+		if v > math.Float64frombits(s.Max) {
+			s.Max = bits
+		}
+		s.Sum = math.Float64bits(math.Float64frombits(s.Sum) + v)
+	}
+	s.Last = bits
+	s.Count++
+}
+
+func (s *BlockStats) addInteger(v int64) {
+	bits := uint64(v)
+	if s.Count == 0 {
+		s.Min, s.Max, s.Sum, s.First = bits, bits, bits, bits
+	} else {
+		if v < int64(s.Min) {
+			s.Min = bits
+		}
+		if v > int64(s.Max) {
+			s.Max = bits
+		}
+		s.Sum += bits
+	}
+	s.Last = bits
+	s.Count++
+}
+
+func (s *BlockStats) addUnsigned(v uint64) {
+	if s.Count == 0 {
+		s.Min, s.Max, s.Sum, s.First = v, v, v, v
+	} else {
+		if v < s.Min {
+			s.Min = v
+		}
+		if v > s.Max {
+			s.Max = v
+		}
+		s.Sum += v
+	}
+	s.Last = v
+	s.Count++
+}
+
+func (s *BlockStats) addBoolean(v bool) {
+	var bits uint64
+	if v {
+		bits = 1
+	}
+	if s.Count == 0 {
+		s.Min, s.Max, s.First = bits, bits, bits
+	} else {
+		if bits < s.Min {
+			s.Min = bits
+		}
+		if bits > s.Max {
+			s.Max = bits
+		}
+	}
+	s.Sum += bits
+	s.Last = bits
+	s.Count++
+}
+
+// newValuesBlockStats returns the statistics of values, which must all be of
+// the same type.
+func newValuesBlockStats(values Values) BlockStats {
+	var s BlockStats
+	for _, v := range values {
+		switch v := v.(type) {
+		case FloatValue:
+			s.addFloat(v.value)
+		case IntegerValue:
+			s.addInteger(v.value)
+		case UnsignedValue:
+			s.addUnsigned(v.value)
+		case BooleanValue:
+			s.addBoolean(v.value)
+		default:
+			s.Count++
+		}
+	}
+	return s
+}
+
+func newFloatValuesBlockStats(values []FloatValue) BlockStats {
+	var s BlockStats
+	for _, v := range values {
+		s.addFloat(v.value)
+	}
+	return s
+}
+
+func newIntegerValuesBlockStats(values []IntegerValue) BlockStats {
+	var s BlockStats
+	for _, v := range values {
+		s.addInteger(v.value)
+	}
+	return s
+}
+
+func newUnsignedValuesBlockStats(values []UnsignedValue) BlockStats {
+	var s BlockStats
+	for _, v := range values {
+		s.addUnsigned(v.value)
+	}
+	return s
+}
+
+func newStringValuesBlockStats(values []StringValue) BlockStats {
+	return BlockStats{Count: uint32(len(values))}
+}
+
+func newBooleanValuesBlockStats(values []BooleanValue) BlockStats {
+	var s BlockStats
+	for _, v := range values {
+		s.addBoolean(v.value)
+	}
+	return s
+}
+
+func newFloatArrayBlockStats(a *tsdb.FloatArray) BlockStats {
+	var s BlockStats
+	for _, v := range a.Values {
+		s.addFloat(v)
+	}
+	return s
+}
+
+func newIntegerArrayBlockStats(a *tsdb.IntegerArray) BlockStats {
+	var s BlockStats
+	for _, v := range a.Values {
+		s.addInteger(v)
+	}
+	return s
+}
+
+func newUnsignedArrayBlockStats(a *tsdb.UnsignedArray) BlockStats {
+	var s BlockStats
+	for _, v := range a.Values {
+		s.addUnsigned(v)
+	}
+	return s
+}
+
+func newStringArrayBlockStats(a *tsdb.StringArray) BlockStats {
+	return BlockStats{Count: uint32(a.Len())}
+}
+
+func newBooleanArrayBlockStats(a *tsdb.BooleanArray) BlockStats {
+	var s BlockStats
+	for _, v := range a.Values {
+		s.addBoolean(v)
+	}
+	return s
+}
+
+// newBlockStats decodes block and returns the statistics of its values.
+func newBlockStats(block []byte) (BlockStats, error) {
+	typ, err := BlockType(block)
+	if err != nil {
+		return BlockStats{}, err
+	}
+
+	switch typ {
+	case BlockFloat64:
+		var a tsdb.FloatArray
+		if err := DecodeFloatArrayBlock(block, &a); err != nil {
+			return BlockStats{}, err
+		}
+		return newFloatArrayBlockStats(&a), nil
+	case BlockInteger:
+		var a tsdb.IntegerArray
+		if err := DecodeIntegerArrayBlock(block, &a); err != nil {
+			return BlockStats{}, err
+		}
+		return newIntegerArrayBlockStats(&a), nil
+	case BlockUnsigned:
+		var a tsdb.UnsignedArray
+		if err := DecodeUnsignedArrayBlock(block, &a); err != nil {
+			return BlockStats{}, err
+		}
+		return newUnsignedArrayBlockStats(&a), nil
+	case BlockString:
+		// Only the count is recorded for strings, which the timestamps provide.
+		return BlockStats{Count: uint32(BlockCount(block))}, nil
+	case BlockBoolean:
+		var a tsdb.BooleanArray
+		if err := DecodeBooleanArrayBlock(block, &a); err != nil {
+			return BlockStats{}, err
+		}
+		return newBooleanArrayBlockStats(&a), nil
+	default:
+		return BlockStats{}, fmt.Errorf("unknown block type: %d", typ)
+	}
+}
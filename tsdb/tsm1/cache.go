@@ -1,6 +1,7 @@
 package tsm1
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/bytesutil"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxql"
 	"github.com/prometheus/client_golang/prometheus"
@@ -252,6 +254,61 @@ func (c *Cache) Write(key []byte, values []Value) error {
 	return nil
 }
 
+// Validate returns the error WriteMulti would return for values, without
+// writing them. Keys whose values have a different type than the other values
+// or the values in the cache are reported by a tsdb.InvalidPointsError, unless
+// the cache does not have room for the values of the other keys.
+func (c *Cache) Validate(values map[string][]Value) error {
+	c.init()
+
+	c.mu.RLock()
+	store := c.store
+	c.mu.RUnlock()
+
+	var (
+		addedSize uint64
+		reasons   map[string]string
+	)
+	for k, v := range values {
+		if len(v) == 0 {
+			continue
+		}
+		if !validValueTypes(store, k, v) {
+			if reasons == nil {
+				reasons = make(map[string]string)
+			}
+			reasons[k] = tsdb.ErrFieldTypeConflict.Error()
+			continue
+		}
+		addedSize += uint64(Values(v).Size())
+	}
+
+	// The values of the batch must fit in the cache together.
+	limit := c.maxSize // maxSize is safe for reading without a lock.
+	n := c.Size() + addedSize
+	if limit > 0 && n > limit {
+		return ErrCacheMemorySizeLimitExceeded(n, limit)
+	}
+
+	if len(reasons) > 0 {
+		return tsdb.InvalidPointsError{Reasons: reasons}
+	}
+	return nil
+}
+
+// validValueTypes reports whether the values of key all have the type of the
+// values of key in the store.
+func validValueTypes(store storer, key string, values []Value) bool {
+	et := valueType(values[0])
+	for _, value := range values {
+		if valueType(value) != et {
+			return false
+		}
+	}
+	e := store.entry([]byte(key))
+	return e == nil || e.vtype == 0 || e.vtype == et
+}
+
 // WriteMulti writes the map of keys and associated values to the cache. This
 // function is goroutine-safe. It returns an error if the cache will exceeded
 // its max size by adding the new values.  The write attempts to write as many
@@ -592,6 +649,29 @@ func (c *Cache) DeleteRange(keys [][]byte, min, max int64) {
 	c.tracker.SetMemBytes(uint64(c.Size()))
 }
 
+// DeleteBucketRange removes the values of the keys starting with name between min
+// and max (inclusive) and returns the keys found.
+func (c *Cache) DeleteBucketRange(name []byte, min, max int64) [][]byte {
+	var keys [][]byte
+
+	// ApplySerialEntryFn cannot return an error in this invocation.
+	_ = c.ApplyEntryFn(func(k []byte, _ *entry) error {
+		if bytes.HasPrefix(k, name) {
+			if keys == nil {
+				keys = make([][]byte, 0, 10000)
+			}
+			keys = append(keys, k)
+		}
+		return nil
+	})
+
+	// Sort the series keys because ApplyEntryFn iterates over the keys randomly.
+	bytesutil.Sort(keys)
+
+	c.DeleteRange(keys, min, max)
+	return keys
+}
+
 // SetMaxSize updates the memory limit of the cache.
 func (c *Cache) SetMaxSize(size uint64) {
 	c.mu.Lock()
@@ -692,6 +772,8 @@ func (cl *CacheLoader) Load(cache *Cache) error {
 					}
 				case *DeleteRangeWALEntry:
 					cache.DeleteRange(t.Keys, t.Min, t.Max)
+				case *DeleteBucketRangeWALEntry:
+					cache.DeleteBucketRange(t.Name, t.Min, t.Max)
 				case *DeleteWALEntry:
 					cache.Delete(t.Keys)
 				}
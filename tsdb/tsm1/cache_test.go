@@ -16,6 +16,7 @@ import (
 	"testing"
 
 	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/tsdb"
 )
 
 func TestCache_NewCache(t *testing.T) {
@@ -167,6 +168,55 @@ func TestCache_CacheWriteMulti_TypeConflict(t *testing.T) {
 	}
 }
 
+func TestCache_Validate(t *testing.T) {
+	v0 := NewValue(1, 1.0)
+	v1 := NewValue(2, int64(2))
+	valuesSize := uint64(v0.Size() + v1.Size())
+
+	c := NewCache(3 * valuesSize)
+	if err := c.WriteMulti(map[string][]Value{"foo": {v0}}); err != nil {
+		t.Fatalf("failed to write key foo to cache: %s", err.Error())
+	}
+	size := c.Size()
+
+	if err := c.Validate(map[string][]Value{"foo": {v0}, "bar": {v1}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conflict := tsdb.ErrFieldTypeConflict.Error()
+	err := c.Validate(map[string][]Value{"foo": {v1}, "bar": {v1}})
+	if exp := (tsdb.InvalidPointsError{Reasons: map[string]string{"foo": conflict}}); !reflect.DeepEqual(err, exp) {
+		t.Fatalf("expected field type conflict for foo, got %v", err)
+	}
+	err = c.Validate(map[string][]Value{"bar": {v0, v1}})
+	if exp := (tsdb.InvalidPointsError{Reasons: map[string]string{"bar": conflict}}); !reflect.DeepEqual(err, exp) {
+		t.Fatalf("expected field type conflict for bar, got %v", err)
+	}
+	if err := c.Validate(map[string][]Value{"bar": {v0, v0, v0, v0, v0, v0}}); err == nil || !strings.Contains(err.Error(), "cache-max-memory-size") {
+		t.Fatalf("expected cache size limit error, got %v", err)
+	}
+
+	// The values of every key fit in the cache, but not together.
+	batch := make(map[string][]Value)
+	for i := 0; i < 6; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if err := c.Validate(map[string][]Value{key: {v0}}); err != nil {
+			t.Fatalf("unexpected error for %s: %v", key, err)
+		}
+		batch[key] = []Value{v0}
+	}
+	if err := c.Validate(batch); err == nil || !strings.Contains(err.Error(), "cache-max-memory-size") {
+		t.Fatalf("expected cache size limit error for the batch, got %v", err)
+	}
+
+	// Validating does not write.
+	if got := c.Size(); got != size {
+		t.Fatalf("cache size changed after validation, exp %d, got %d", size, got)
+	}
+	if exp, keys := [][]byte{[]byte("foo")}, c.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("cache keys incorrect after validation, exp %v, got %v", exp, keys)
+	}
+}
+
 func TestCache_Cache_DeleteRange(t *testing.T) {
 	v0 := NewValue(1, 1.0)
 	v1 := NewValue(2, 2.0)
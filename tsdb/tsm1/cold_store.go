@@ -0,0 +1,152 @@
+package tsm1
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/file"
+)
+
+// ColdStore stores TSM files that were moved out of the engine path to a cheaper
+// storage tier.  Objects are written once and never modified.
+type ColdStore interface {
+	// Put stores the contents of r as the object called name.
+	Put(name string, r io.Reader) error
+
+	// Open opens the object called name for reading.
+	Open(name string) (ColdObject, error)
+
+	// Remove removes the object called name.  Removing an object that does not
+	// exist is not an error.
+	Remove(name string) error
+}
+
+// ColdObject provides random access to an object of a ColdStore.
+type ColdObject interface {
+	io.ReaderAt
+	io.Closer
+
+	// Size returns the size of the object in bytes.
+	Size() int64
+}
+
+// DirColdStore is a ColdStore that keeps objects as files in a directory, such
+// as a mount point of a slower or cheaper disk.
+type DirColdStore struct {
+	dir string
+}
+
+// NewDirColdStore returns a ColdStore that keeps objects in dir.
+func NewDirColdStore(dir string) *DirColdStore {
+	return &DirColdStore{dir: dir}
+}
+
+// Put stores the contents of r in the file called name.  The file is written to a
+// temporary path and renamed once it is complete.
+func (s *DirColdStore) Put(name string, r io.Reader) error {
+	if err := os.MkdirAll(s.dir, 0777); err != nil {
+		return err
+	}
+
+	path := s.path(name)
+	tmpPath := path + "." + TmpTSMFileExtension
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := file.RenameFile(tmpPath, path); err != nil {
+		return err
+	}
+	return file.SyncDir(s.dir)
+}
+
+// Open opens the file called name.
+func (s *DirColdStore) Open(name string) (ColdObject, error) {
+	f, err := os.Open(s.path(name))
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &dirColdObject{File: f, size: stat.Size()}, nil
+}
+
+// Remove removes the file called name.
+func (s *DirColdStore) Remove(name string) error {
+	if err := os.Remove(s.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *DirColdStore) path(name string) string {
+	return filepath.Join(s.dir, filepath.Base(name))
+}
+
+type dirColdObject struct {
+	*os.File
+	size int64
+}
+
+func (o *dirColdObject) Size() int64 { return o.size }
+
+// coldPointerPath returns the path of the file in the engine path that records
+// that the TSM file at path was moved to the cold store.
+func coldPointerPath(path string) string {
+	return path + "." + ColdTSMFileExtension
+}
+
+// writeColdPointer records that the TSM file at path was moved to the cold store
+// as the object called name.  The pointer keeps the modification time of the
+// TSM file.
+func writeColdPointer(path, name string, modTime int64) error {
+	pointerPath := coldPointerPath(path)
+	tmpPath := pointerPath + "." + TmpTSMFileExtension
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, name); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	mtime := time.Unix(0, modTime)
+	if err := os.Chtimes(tmpPath, mtime, mtime); err != nil {
+		return err
+	}
+	if err := file.RenameFile(tmpPath, pointerPath); err != nil {
+		return fmt.Errorf("error renaming cold pointer file: %v", err)
+	}
+	return file.SyncDir(filepath.Dir(path))
+}
@@ -0,0 +1,283 @@
+package tsm1
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/file"
+	"go.uber.org/zap"
+)
+
+// coldTierMover moves the TSM generations of a FileStore that are fully compacted
+// and older than a configured age to the cold tier.  It observes the files added
+// to and removed from the FileStore to know which files are candidates for a move,
+// and passes the notifications on to the observer it wraps.
+type coldTierMover struct {
+	fs       *FileStore
+	age      time.Duration
+	interval time.Duration
+	obs      FileStoreObserver
+	logger   *zap.Logger
+
+	mu    sync.Mutex
+	files map[string]struct{} // TSM files in the engine path
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newColdTierMover(fs *FileStore, age, interval time.Duration, obs FileStoreObserver) *coldTierMover {
+	if obs == nil {
+		obs = noFileStoreObserver{}
+	}
+	return &coldTierMover{
+		fs:       fs,
+		age:      age,
+		interval: interval,
+		obs:      obs,
+		logger:   zap.NewNop(),
+		files:    make(map[string]struct{}),
+	}
+}
+
+// FileFinishing records that the TSM file at path is a candidate for a move.
+func (m *coldTierMover) FileFinishing(path string) error {
+	if err := m.obs.FileFinishing(path); err != nil {
+		return err
+	}
+
+	if path, ok := coldTierCandidatePath(path); ok {
+		m.mu.Lock()
+		m.files[path] = struct{}{}
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// FileUnlinking records that the TSM file at path is no longer a candidate for a move.
+func (m *coldTierMover) FileUnlinking(path string) error {
+	if err := m.obs.FileUnlinking(path); err != nil {
+		return err
+	}
+
+	if path, ok := coldTierCandidatePath(path); ok {
+		m.mu.Lock()
+		delete(m.files, path)
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// coldTierCandidatePath returns the path a TSM file has once it is live in the
+// FileStore.  New files are observed with their temporary extension.
+func coldTierCandidatePath(path string) (string, bool) {
+	path = strings.TrimSuffix(path, "."+TmpTSMFileExtension)
+	return path, strings.HasSuffix(path, "."+TSMFileExtension)
+}
+
+// open starts moving files to the cold tier.  The files already in the FileStore
+// are the initial candidates.
+func (m *coldTierMover) open() {
+	m.mu.Lock()
+	for _, f := range m.fs.Files() {
+		if r, ok := f.(*TSMReader); ok && !r.Cold() {
+			m.files[r.Path()] = struct{}{}
+		}
+	}
+	m.mu.Unlock()
+
+	m.done = make(chan struct{})
+	m.wg.Add(1)
+	go func(done chan struct{}) {
+		defer m.wg.Done()
+		m.run(done)
+	}(m.done)
+}
+
+// close stops moving files and waits for a running move to complete.
+func (m *coldTierMover) close() {
+	if m.done == nil {
+		return
+	}
+	close(m.done)
+	m.wg.Wait()
+	m.done = nil
+}
+
+func (m *coldTierMover) run(done chan struct{}) {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			if err := m.moveFiles(time.Now()); err != nil {
+				m.logger.Info("Error moving files to cold tier", zap.Error(err))
+			}
+		}
+	}
+}
+
+// moveFiles moves the generations whose files are all fully compacted, have no
+// tombstones and only contain points older than the configured age at now.
+func (m *coldTierMover) moveFiles(now time.Time) error {
+	m.mu.Lock()
+	generations := make(map[int][]string)
+	for path := range m.files {
+		generation, _, err := m.fs.parseFileName(path)
+		if err != nil {
+			continue
+		}
+		generations[generation] = append(generations[generation], path)
+	}
+	m.mu.Unlock()
+
+	stats := make(map[string]FileStat)
+	for _, stat := range m.fs.Stats() {
+		stats[stat.Path] = stat
+	}
+
+	var paths []string
+	minTime := now.Add(-m.age).UnixNano()
+	for _, files := range generations {
+		if m.eligible(files, stats, minTime) {
+			paths = append(paths, files...)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		select {
+		case <-m.done:
+			return nil
+		default:
+		}
+
+		start := time.Now()
+		if err := m.fs.moveToColdStore(path); err == ErrFileInUse {
+			m.logger.Info("Skipping file in use", zap.String("path", path))
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		m.mu.Lock()
+		delete(m.files, path)
+		m.mu.Unlock()
+
+		m.logger.Info("Moved file to cold tier",
+			zap.String("path", path),
+			zap.Duration("duration", time.Since(start)))
+	}
+	return nil
+}
+
+// eligible returns true if the generation made of files can be moved.
+func (m *coldTierMover) eligible(files []string, stats map[string]FileStat, minTime int64) bool {
+	for _, path := range files {
+		if _, seq, err := m.fs.parseFileName(path); err != nil || seq < 4 {
+			return false
+		}
+
+		stat, ok := stats[path]
+		if !ok || stat.HasTombstone || stat.MaxTime >= minTime {
+			return false
+		}
+	}
+	return true
+}
+
+// moveToColdStore moves the TSM file at path to the cold store and replaces its
+// reader with one that reads from the cold store.  The tombstones and statistics
+// of the file stay in the engine path.  ErrFileInUse is returned if the file is
+// used by a query or compaction, in which case the move should be retried later.
+func (f *FileStore) moveToColdStore(path string) error {
+	if f.coldStore == nil {
+		return fmt.Errorf("cannot move %s without a cold store", path)
+	}
+
+	r := f.TSMReader(path)
+	if r == nil {
+		return nil
+	}
+	r.Unref()
+	if r.Cold() {
+		return nil
+	}
+
+	// Copy the file while it is not locked so that queries are not blocked.
+	name := filepath.Base(path)
+	if err := func() error {
+		fd, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+
+		if err := f.coldStore.Put(name, fd); err != nil {
+			return err
+		}
+		return writeColdPointer(path, name, r.LastModified())
+	}(); err != nil {
+		return err
+	}
+
+	// abort undoes the copy if the file can not be swapped.
+	abort := func(err error) error {
+		if e := os.Remove(coldPointerPath(path)); e != nil && !os.IsNotExist(e) {
+			return e
+		}
+		if e := f.coldStore.Remove(name); e != nil {
+			return e
+		}
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	idx := -1
+	for i, file := range f.files {
+		if file == TSMFile(r) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		// The file was replaced by a compaction during the copy.
+		return abort(nil)
+	} else if r.InUse() {
+		return abort(ErrFileInUse)
+	}
+
+	// Wait for running deletes so that the tombstones read by the new reader
+	// are complete.
+	r.deleteMu.Lock()
+	defer r.deleteMu.Unlock()
+
+	cold, err := newColdTSMReader(path, name, f.coldStore, f.blockCache,
+		WithTSMReaderLogger(f.logger))
+	if err != nil {
+		return abort(err)
+	}
+	cold.WithObserver(f.obs)
+
+	if err := r.Close(); err != nil {
+		cold.Close()
+		return abort(err)
+	}
+	f.files[idx] = cold
+	f.lastFileStats = nil
+
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return file.SyncDir(f.dir)
+}
@@ -0,0 +1,190 @@
+package tsm1
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestColdTierMover_MoveFiles(t *testing.T) {
+	dir, coldDir := mustTempDir(), mustTempDir()
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(coldDir)
+
+	now := time.Now()
+	old := now.Add(-2 * time.Hour).UnixNano()
+	oldPath := mustWriteColdTierFile(t, dir, 1, 4, "cpu", []Value{NewValue(old, 1.0), NewValue(old+1, 2.0)})
+	newPath := mustWriteColdTierFile(t, dir, 2, 4, "cpu", []Value{NewValue(now.UnixNano(), 3.0)})
+	lowPath := mustWriteColdTierFile(t, dir, 3, 1, "mem", []Value{NewValue(old, 4.0)})
+
+	store := NewDirColdStore(coldDir)
+	fs := NewFileStore(dir)
+	fs.WithColdStore(store, 1024)
+	m := newColdTierMover(fs, time.Hour, time.Hour, nil)
+	fs.WithObserver(m)
+	if err := fs.Open(); err != nil {
+		t.Fatalf("unexpected error opening file store: %v", err)
+	}
+	m.open()
+	m.close()
+
+	if err := m.moveFiles(now); err != nil {
+		t.Fatalf("unexpected error moving files: %v", err)
+	}
+
+	// Only the fully compacted generation with old points is moved.
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed: %v", oldPath, err)
+	}
+	if _, err := os.Stat(coldPointerPath(oldPath)); err != nil {
+		t.Fatalf("expected cold pointer: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(coldDir, filepath.Base(oldPath))); err != nil {
+		t.Fatalf("expected file in cold store: %v", err)
+	}
+	for _, path := range []string{newPath, lowPath} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s to be kept: %v", path, err)
+		}
+	}
+
+	exp := []Value{NewValue(old, 1.0), NewValue(old+1, 2.0)}
+	for i := 0; i < 2; i++ {
+		values, err := fs.Read([]byte("cpu"), old)
+		if err != nil {
+			t.Fatalf("unexpected error reading values: %v", err)
+		}
+		if !reflect.DeepEqual(values, exp) {
+			t.Fatalf("unexpected values: got %v, exp %v", values, exp)
+		}
+	}
+
+	if err := fs.Close(); err != nil {
+		t.Fatalf("unexpected error closing file store: %v", err)
+	}
+
+	// The moved file is read from the cold store after a restart.
+	fs = NewFileStore(dir)
+	fs.WithColdStore(store, 1024)
+	if err := fs.Open(); err != nil {
+		t.Fatalf("unexpected error opening file store: %v", err)
+	}
+	defer fs.Close()
+
+	if got, exp := fs.Count(), 3; got != exp {
+		t.Fatalf("unexpected file count: got %d, exp %d", got, exp)
+	}
+	r := fs.TSMReader(oldPath)
+	if r == nil || !r.Cold() {
+		t.Fatalf("expected cold reader for %s", oldPath)
+	}
+	r.Unref()
+
+	values, err := fs.Read([]byte("cpu"), old+1)
+	if err != nil {
+		t.Fatalf("unexpected error reading values: %v", err)
+	}
+	if !reflect.DeepEqual(values, exp) {
+		t.Fatalf("unexpected values: got %v, exp %v", values, exp)
+	}
+
+	// Deletes are recorded in the engine path.
+	if err := fs.DeleteRange([][]byte{[]byte("cpu")}, old, old); err != nil {
+		t.Fatalf("unexpected error deleting values: %v", err)
+	}
+	r = fs.TSMReader(oldPath)
+	if len(r.TombstoneFiles()) == 0 {
+		t.Fatal("expected tombstone file")
+	}
+	values, err = r.ReadAll([]byte("cpu"))
+	r.Unref()
+	if err != nil {
+		t.Fatalf("unexpected error reading values: %v", err)
+	} else if exp := exp[1:]; !reflect.DeepEqual(values, exp) {
+		t.Fatalf("unexpected values: got %v, exp %v", values, exp)
+	}
+
+	// Removing the file also removes it from the cold store.
+	if err := fs.Replace([]string{oldPath}, nil); err != nil {
+		t.Fatalf("unexpected error replacing files: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(coldDir, filepath.Base(oldPath))); !os.IsNotExist(err) {
+		t.Fatalf("expected file to be removed from cold store: %v", err)
+	}
+	if _, err := os.Stat(coldPointerPath(oldPath)); !os.IsNotExist(err) {
+		t.Fatalf("expected cold pointer to be removed: %v", err)
+	}
+}
+
+func TestFileStore_Open_ColdPointerWithoutStore(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, DefaultFormatFileName(1, 4)+"."+TSMFileExtension)
+	if err := writeColdPointer(path, filepath.Base(path), time.Now().UnixNano()); err != nil {
+		t.Fatalf("unexpected error writing pointer: %v", err)
+	}
+
+	fs := NewFileStore(dir)
+	if err := fs.Open(); err == nil {
+		fs.Close()
+		t.Fatal("expected error opening cold file without a cold store")
+	}
+}
+
+func TestBlockCache_Evict(t *testing.T) {
+	c := newBlockCache(8)
+	c.put("a", 0, []byte("1234"))
+	c.put("a", 4, []byte("5678"))
+	if _, ok := c.get("a", 0); !ok {
+		t.Fatal("expected cached block")
+	}
+
+	// The least recently used block is evicted to make room.
+	c.put("b", 0, []byte("abcd"))
+	if _, ok := c.get("a", 4); ok {
+		t.Fatal("expected block to be evicted")
+	}
+	if b, ok := c.get("a", 0); !ok || string(b) != "1234" {
+		t.Fatalf("unexpected block: %q %v", b, ok)
+	}
+
+	// Blocks larger than the cache are not cached.
+	c.put("c", 0, []byte("123456789"))
+	if _, ok := c.get("c", 0); ok {
+		t.Fatal("expected block to not be cached")
+	}
+
+	c.evict("a")
+	if _, ok := c.get("a", 0); ok {
+		t.Fatal("expected blocks of file to be evicted")
+	}
+	if _, ok := c.get("b", 0); !ok {
+		t.Fatal("expected blocks of other file to be kept")
+	}
+}
+
+func mustWriteColdTierFile(t *testing.T, dir string, generation, sequence int, key string, values []Value) string {
+	path := filepath.Join(dir, DefaultFormatFileName(generation, sequence)+"."+TSMFileExtension)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("unexpected error creating file: %v", err)
+	}
+
+	w, err := NewTSMWriter(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+	if err := w.Write([]byte(key), values); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+	return path
+}
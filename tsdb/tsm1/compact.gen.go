@@ -176,6 +176,7 @@ func (k *tsmKeyIterator) combineFloat(dedup bool) blocks {
 func (k *tsmKeyIterator) chunkFloat(dst blocks) blocks {
 	if len(k.mergedFloatValues) > k.size {
 		values := k.mergedFloatValues[:k.size]
+		stats := newFloatValuesBlockStats(values)
 		cb, err := FloatValues(values).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -187,6 +188,7 @@ func (k *tsmKeyIterator) chunkFloat(dst blocks) blocks {
 			maxTime: values[len(values)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedFloatValues = k.mergedFloatValues[k.size:]
 		return dst
@@ -194,6 +196,7 @@ func (k *tsmKeyIterator) chunkFloat(dst blocks) blocks {
 
 	// Re-encode the remaining values into the last block
 	if len(k.mergedFloatValues) > 0 {
+		stats := newFloatValuesBlockStats(k.mergedFloatValues)
 		cb, err := FloatValues(k.mergedFloatValues).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -205,6 +208,7 @@ func (k *tsmKeyIterator) chunkFloat(dst blocks) blocks {
 			maxTime: k.mergedFloatValues[len(k.mergedFloatValues)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedFloatValues = k.mergedFloatValues[:0]
 	}
@@ -375,6 +379,7 @@ func (k *tsmKeyIterator) combineInteger(dedup bool) blocks {
 func (k *tsmKeyIterator) chunkInteger(dst blocks) blocks {
 	if len(k.mergedIntegerValues) > k.size {
 		values := k.mergedIntegerValues[:k.size]
+		stats := newIntegerValuesBlockStats(values)
 		cb, err := IntegerValues(values).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -386,6 +391,7 @@ func (k *tsmKeyIterator) chunkInteger(dst blocks) blocks {
 			maxTime: values[len(values)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedIntegerValues = k.mergedIntegerValues[k.size:]
 		return dst
@@ -393,6 +399,7 @@ func (k *tsmKeyIterator) chunkInteger(dst blocks) blocks {
 
 	// Re-encode the remaining values into the last block
 	if len(k.mergedIntegerValues) > 0 {
+		stats := newIntegerValuesBlockStats(k.mergedIntegerValues)
 		cb, err := IntegerValues(k.mergedIntegerValues).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -404,6 +411,7 @@ func (k *tsmKeyIterator) chunkInteger(dst blocks) blocks {
 			maxTime: k.mergedIntegerValues[len(k.mergedIntegerValues)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedIntegerValues = k.mergedIntegerValues[:0]
 	}
@@ -574,6 +582,7 @@ func (k *tsmKeyIterator) combineUnsigned(dedup bool) blocks {
 func (k *tsmKeyIterator) chunkUnsigned(dst blocks) blocks {
 	if len(k.mergedUnsignedValues) > k.size {
 		values := k.mergedUnsignedValues[:k.size]
+		stats := newUnsignedValuesBlockStats(values)
 		cb, err := UnsignedValues(values).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -585,6 +594,7 @@ func (k *tsmKeyIterator) chunkUnsigned(dst blocks) blocks {
 			maxTime: values[len(values)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedUnsignedValues = k.mergedUnsignedValues[k.size:]
 		return dst
@@ -592,6 +602,7 @@ func (k *tsmKeyIterator) chunkUnsigned(dst blocks) blocks {
 
 	// Re-encode the remaining values into the last block
 	if len(k.mergedUnsignedValues) > 0 {
+		stats := newUnsignedValuesBlockStats(k.mergedUnsignedValues)
 		cb, err := UnsignedValues(k.mergedUnsignedValues).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -603,6 +614,7 @@ func (k *tsmKeyIterator) chunkUnsigned(dst blocks) blocks {
 			maxTime: k.mergedUnsignedValues[len(k.mergedUnsignedValues)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedUnsignedValues = k.mergedUnsignedValues[:0]
 	}
@@ -773,6 +785,7 @@ func (k *tsmKeyIterator) combineString(dedup bool) blocks {
 func (k *tsmKeyIterator) chunkString(dst blocks) blocks {
 	if len(k.mergedStringValues) > k.size {
 		values := k.mergedStringValues[:k.size]
+		stats := newStringValuesBlockStats(values)
 		cb, err := StringValues(values).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -784,6 +797,7 @@ func (k *tsmKeyIterator) chunkString(dst blocks) blocks {
 			maxTime: values[len(values)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedStringValues = k.mergedStringValues[k.size:]
 		return dst
@@ -791,6 +805,7 @@ func (k *tsmKeyIterator) chunkString(dst blocks) blocks {
 
 	// Re-encode the remaining values into the last block
 	if len(k.mergedStringValues) > 0 {
+		stats := newStringValuesBlockStats(k.mergedStringValues)
 		cb, err := StringValues(k.mergedStringValues).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -802,6 +817,7 @@ func (k *tsmKeyIterator) chunkString(dst blocks) blocks {
 			maxTime: k.mergedStringValues[len(k.mergedStringValues)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedStringValues = k.mergedStringValues[:0]
 	}
@@ -972,6 +988,7 @@ func (k *tsmKeyIterator) combineBoolean(dedup bool) blocks {
 func (k *tsmKeyIterator) chunkBoolean(dst blocks) blocks {
 	if len(k.mergedBooleanValues) > k.size {
 		values := k.mergedBooleanValues[:k.size]
+		stats := newBooleanValuesBlockStats(values)
 		cb, err := BooleanValues(values).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -983,6 +1000,7 @@ func (k *tsmKeyIterator) chunkBoolean(dst blocks) blocks {
 			maxTime: values[len(values)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedBooleanValues = k.mergedBooleanValues[k.size:]
 		return dst
@@ -990,6 +1008,7 @@ func (k *tsmKeyIterator) chunkBoolean(dst blocks) blocks {
 
 	// Re-encode the remaining values into the last block
 	if len(k.mergedBooleanValues) > 0 {
+		stats := newBooleanValuesBlockStats(k.mergedBooleanValues)
 		cb, err := BooleanValues(k.mergedBooleanValues).Encode(nil)
 		if err != nil {
 			k.err = err
@@ -1001,6 +1020,7 @@ func (k *tsmKeyIterator) chunkBoolean(dst blocks) blocks {
 			maxTime: k.mergedBooleanValues[len(k.mergedBooleanValues)-1].UnixNano(),
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedBooleanValues = k.mergedBooleanValues[:0]
 	}
@@ -1187,6 +1207,7 @@ func (k *tsmBatchKeyIterator) chunkFloat(dst blocks) blocks {
 		values.Timestamps = k.mergedFloatValues.Timestamps[:k.size]
 		minTime, maxTime := values.Timestamps[0], values.Timestamps[len(values.Timestamps)-1]
 		values.Values = k.mergedFloatValues.Values[:k.size]
+		stats := newFloatArrayBlockStats(&values)
 
 		cb, err := EncodeFloatArrayBlock(&values, nil) // TODO(edd): pool this buffer
 		if err != nil {
@@ -1199,6 +1220,7 @@ func (k *tsmBatchKeyIterator) chunkFloat(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedFloatValues.Timestamps = k.mergedFloatValues.Timestamps[k.size:]
 		k.mergedFloatValues.Values = k.mergedFloatValues.Values[k.size:]
@@ -1208,6 +1230,7 @@ func (k *tsmBatchKeyIterator) chunkFloat(dst blocks) blocks {
 	// Re-encode the remaining values into the last block
 	if k.mergedFloatValues.Len() > 0 {
 		minTime, maxTime := k.mergedFloatValues.Timestamps[0], k.mergedFloatValues.Timestamps[len(k.mergedFloatValues.Timestamps)-1]
+		stats := newFloatArrayBlockStats(k.mergedFloatValues)
 		cb, err := EncodeFloatArrayBlock(k.mergedFloatValues, nil) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
@@ -1219,6 +1242,7 @@ func (k *tsmBatchKeyIterator) chunkFloat(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedFloatValues.Timestamps = k.mergedFloatValues.Timestamps[:0]
 		k.mergedFloatValues.Values = k.mergedFloatValues.Values[:0]
@@ -1406,6 +1430,7 @@ func (k *tsmBatchKeyIterator) chunkInteger(dst blocks) blocks {
 		values.Timestamps = k.mergedIntegerValues.Timestamps[:k.size]
 		minTime, maxTime := values.Timestamps[0], values.Timestamps[len(values.Timestamps)-1]
 		values.Values = k.mergedIntegerValues.Values[:k.size]
+		stats := newIntegerArrayBlockStats(&values)
 
 		cb, err := EncodeIntegerArrayBlock(&values, nil) // TODO(edd): pool this buffer
 		if err != nil {
@@ -1418,6 +1443,7 @@ func (k *tsmBatchKeyIterator) chunkInteger(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedIntegerValues.Timestamps = k.mergedIntegerValues.Timestamps[k.size:]
 		k.mergedIntegerValues.Values = k.mergedIntegerValues.Values[k.size:]
@@ -1427,6 +1453,7 @@ func (k *tsmBatchKeyIterator) chunkInteger(dst blocks) blocks {
 	// Re-encode the remaining values into the last block
 	if k.mergedIntegerValues.Len() > 0 {
 		minTime, maxTime := k.mergedIntegerValues.Timestamps[0], k.mergedIntegerValues.Timestamps[len(k.mergedIntegerValues.Timestamps)-1]
+		stats := newIntegerArrayBlockStats(k.mergedIntegerValues)
 		cb, err := EncodeIntegerArrayBlock(k.mergedIntegerValues, nil) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
@@ -1438,6 +1465,7 @@ func (k *tsmBatchKeyIterator) chunkInteger(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedIntegerValues.Timestamps = k.mergedIntegerValues.Timestamps[:0]
 		k.mergedIntegerValues.Values = k.mergedIntegerValues.Values[:0]
@@ -1625,6 +1653,7 @@ func (k *tsmBatchKeyIterator) chunkUnsigned(dst blocks) blocks {
 		values.Timestamps = k.mergedUnsignedValues.Timestamps[:k.size]
 		minTime, maxTime := values.Timestamps[0], values.Timestamps[len(values.Timestamps)-1]
 		values.Values = k.mergedUnsignedValues.Values[:k.size]
+		stats := newUnsignedArrayBlockStats(&values)
 
 		cb, err := EncodeUnsignedArrayBlock(&values, nil) // TODO(edd): pool this buffer
 		if err != nil {
@@ -1637,6 +1666,7 @@ func (k *tsmBatchKeyIterator) chunkUnsigned(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedUnsignedValues.Timestamps = k.mergedUnsignedValues.Timestamps[k.size:]
 		k.mergedUnsignedValues.Values = k.mergedUnsignedValues.Values[k.size:]
@@ -1646,6 +1676,7 @@ func (k *tsmBatchKeyIterator) chunkUnsigned(dst blocks) blocks {
 	// Re-encode the remaining values into the last block
 	if k.mergedUnsignedValues.Len() > 0 {
 		minTime, maxTime := k.mergedUnsignedValues.Timestamps[0], k.mergedUnsignedValues.Timestamps[len(k.mergedUnsignedValues.Timestamps)-1]
+		stats := newUnsignedArrayBlockStats(k.mergedUnsignedValues)
 		cb, err := EncodeUnsignedArrayBlock(k.mergedUnsignedValues, nil) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
@@ -1657,6 +1688,7 @@ func (k *tsmBatchKeyIterator) chunkUnsigned(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedUnsignedValues.Timestamps = k.mergedUnsignedValues.Timestamps[:0]
 		k.mergedUnsignedValues.Values = k.mergedUnsignedValues.Values[:0]
@@ -1844,6 +1876,7 @@ func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 		values.Timestamps = k.mergedStringValues.Timestamps[:k.size]
 		minTime, maxTime := values.Timestamps[0], values.Timestamps[len(values.Timestamps)-1]
 		values.Values = k.mergedStringValues.Values[:k.size]
+		stats := newStringArrayBlockStats(&values)
 
 		cb, err := EncodeStringArrayBlock(&values, nil) // TODO(edd): pool this buffer
 		if err != nil {
@@ -1856,6 +1889,7 @@ func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedStringValues.Timestamps = k.mergedStringValues.Timestamps[k.size:]
 		k.mergedStringValues.Values = k.mergedStringValues.Values[k.size:]
@@ -1865,6 +1899,7 @@ func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 	// Re-encode the remaining values into the last block
 	if k.mergedStringValues.Len() > 0 {
 		minTime, maxTime := k.mergedStringValues.Timestamps[0], k.mergedStringValues.Timestamps[len(k.mergedStringValues.Timestamps)-1]
+		stats := newStringArrayBlockStats(k.mergedStringValues)
 		cb, err := EncodeStringArrayBlock(k.mergedStringValues, nil) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
@@ -1876,6 +1911,7 @@ func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedStringValues.Timestamps = k.mergedStringValues.Timestamps[:0]
 		k.mergedStringValues.Values = k.mergedStringValues.Values[:0]
@@ -2063,6 +2099,7 @@ func (k *tsmBatchKeyIterator) chunkBoolean(dst blocks) blocks {
 		values.Timestamps = k.mergedBooleanValues.Timestamps[:k.size]
 		minTime, maxTime := values.Timestamps[0], values.Timestamps[len(values.Timestamps)-1]
 		values.Values = k.mergedBooleanValues.Values[:k.size]
+		stats := newBooleanArrayBlockStats(&values)
 
 		cb, err := EncodeBooleanArrayBlock(&values, nil) // TODO(edd): pool this buffer
 		if err != nil {
@@ -2075,6 +2112,7 @@ func (k *tsmBatchKeyIterator) chunkBoolean(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedBooleanValues.Timestamps = k.mergedBooleanValues.Timestamps[k.size:]
 		k.mergedBooleanValues.Values = k.mergedBooleanValues.Values[k.size:]
@@ -2084,6 +2122,7 @@ func (k *tsmBatchKeyIterator) chunkBoolean(dst blocks) blocks {
 	// Re-encode the remaining values into the last block
 	if k.mergedBooleanValues.Len() > 0 {
 		minTime, maxTime := k.mergedBooleanValues.Timestamps[0], k.mergedBooleanValues.Timestamps[len(k.mergedBooleanValues.Timestamps)-1]
+		stats := newBooleanArrayBlockStats(k.mergedBooleanValues)
 		cb, err := EncodeBooleanArrayBlock(k.mergedBooleanValues, nil) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
@@ -2095,6 +2134,7 @@ func (k *tsmBatchKeyIterator) chunkBoolean(dst blocks) blocks {
 			maxTime: maxTime,
 			key:     k.key,
 			b:       cb,
+			stats:   stats,
 		})
 		k.mergedBooleanValues.Timestamps = k.mergedBooleanValues.Timestamps[:0]
 		k.mergedBooleanValues.Values = k.mergedBooleanValues.Values[:0]
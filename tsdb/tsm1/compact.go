@@ -712,7 +712,9 @@ type Compactor struct {
 	// The channel to signal that any in progress level compactions should be aborted.
 	compactionsInterrupt chan struct{}
 
-	files map[string]struct{}
+	// files maps the files of the running compactions to the number of bytes of
+	// blocks written by the compaction they are part of.
+	files map[string]*int64
 }
 
 // NewCompactor returns a new instance of Compactor.
@@ -745,7 +747,7 @@ func (c *Compactor) Open() {
 	c.compactionsInterrupt = make(chan struct{})
 	c.snapshotLatencies = &latencies{values: make([]time.Duration, 4)}
 
-	c.files = make(map[string]struct{})
+	c.files = make(map[string]*int64)
 }
 
 // Close disables the Compactor.
@@ -1025,6 +1027,14 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 	// These are the new TSM files written
 	var files []string
 
+	// Track the progress of compactions of existing files.
+	var written *int64
+	if len(src) > 0 {
+		c.mu.RLock()
+		written = c.files[src[0]]
+		c.mu.RUnlock()
+	}
+
 	for {
 		sequence++
 
@@ -1033,7 +1043,7 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 		statsFileName := StatsFilename(fileName)
 
 		// Write as much as possible to this file
-		err := c.write(fileName, iter, throttle)
+		err := c.write(fileName, iter, throttle, written)
 
 		// We've hit the max file limit and there is more to write.  Create a new file
 		// and continue.
@@ -1078,7 +1088,7 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 	return files, nil
 }
 
-func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err error) {
+func (c *Compactor) write(path string, iter KeyIterator, throttle bool, written *int64) (err error) {
 	fd, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
 	if err != nil {
 		return errCompactionInProgress{err: err}
@@ -1155,8 +1165,17 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 			return fmt.Errorf("invalid index entry for block. min=%d, max=%d", minTime, maxTime)
 		}
 
-		// Write the key and value
-		if err := w.WriteBlock(key, minTime, maxTime, block); err == ErrMaxBlocksExceeded {
+		// Write the key and value, with the block statistics if the iterator knows them
+		var stats BlockStats
+		if sr, ok := iter.(blockStatsReader); ok {
+			stats = sr.ReadStats()
+		}
+		if stats.HasStats() {
+			err = w.WriteBlockWithStats(key, minTime, maxTime, block, stats)
+		} else {
+			err = w.WriteBlock(key, minTime, maxTime, block)
+		}
+		if err == ErrMaxBlocksExceeded {
 			if err := w.WriteIndex(); err != nil {
 				return err
 			}
@@ -1165,6 +1184,10 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 			return err
 		}
 
+		if written != nil {
+			atomic.AddInt64(written, int64(len(block)))
+		}
+
 		// If we have a max file size configured and we're over it, close out the file
 		// and return the error.
 		if w.Size() > maxTSMFileSize {
@@ -1200,12 +1223,30 @@ func (c *Compactor) add(files []string) bool {
 	}
 
 	// Mark all the new files in use
+	written := new(int64)
 	for _, f := range files {
-		c.files[f] = struct{}{}
+		c.files[f] = written
 	}
 	return true
 }
 
+// BytesWritten returns the number of bytes of blocks written so far by the running
+// compaction of tsmFiles.
+func (c *Compactor) BytesWritten(tsmFiles []string) int64 {
+	if len(tsmFiles) == 0 {
+		return 0
+	}
+
+	c.mu.RLock()
+	written := c.files[tsmFiles[0]]
+	c.mu.RUnlock()
+
+	if written == nil {
+		return 0
+	}
+	return atomic.LoadInt64(written)
+}
+
 func (c *Compactor) remove(files []string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -1234,6 +1275,15 @@ type KeyIterator interface {
 	EstimatedIndexSize() int
 }
 
+// blockStatsReader is implemented by KeyIterators that know the statistics of
+// the values in the block returned by Read.  It avoids decoding the block again
+// to compute the statistics when it is written.
+type blockStatsReader interface {
+	// ReadStats returns the statistics of the block returned by Read.  A zero
+	// Count means the statistics are not known.
+	ReadStats() BlockStats
+}
+
 // tsmKeyIterator implements the KeyIterator for set of TSMReaders.  Iteration produces
 // keys in sorted order and the values between the keys sorted and deduped.  If any of
 // the readers have associated tombstone entries, they are returned as part of iteration.
@@ -1292,6 +1342,9 @@ type block struct {
 	b                []byte
 	tombstones       []TimeRange
 
+	// stats are the statistics of the values in b, if known.
+	stats BlockStats
+
 	// readMin, readMax are the timestamps range of values have been
 	// read and encoded from this block.
 	readMin, readMax int64
@@ -1409,6 +1462,7 @@ RETRY:
 				if err != nil {
 					k.err = err
 				}
+				stats := iter.Stats()
 
 				var blk *block
 				if cap(k.buf[i]) > len(k.buf[i]) {
@@ -1427,6 +1481,7 @@ RETRY:
 				blk.key = key
 				blk.typ = typ
 				blk.b = b
+				blk.stats = stats
 				blk.readMin = math.MaxInt64
 				blk.readMax = math.MinInt64
 
@@ -1441,6 +1496,7 @@ RETRY:
 					if err != nil {
 						k.err = err
 					}
+					stats := iter.Stats()
 
 					var blk *block
 					if cap(k.buf[i]) > len(k.buf[i]) {
@@ -1460,6 +1516,7 @@ RETRY:
 					blk.key = key
 					blk.typ = typ
 					blk.b = b
+					blk.stats = stats
 					blk.readMin = math.MaxInt64
 					blk.readMax = math.MinInt64
 					blk.tombstones = iter.r.TombstoneRange(key, blk.tombstones[:0])
@@ -1550,6 +1607,14 @@ func (k *tsmKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return block.key, block.minTime, block.maxTime, block.b, k.err
 }
 
+// ReadStats returns the statistics of the block returned by Read.
+func (k *tsmKeyIterator) ReadStats() BlockStats {
+	if len(k.merged) == 0 {
+		return BlockStats{}
+	}
+	return k.merged[0].stats
+}
+
 func (k *tsmKeyIterator) Close() error {
 	k.values = nil
 	k.pos = nil
@@ -1698,6 +1763,7 @@ RETRY:
 			if err != nil {
 				k.err = err
 			}
+			stats := iter.Stats()
 
 			var blk *block
 			if cap(k.buf[i]) > len(k.buf[i]) {
@@ -1716,6 +1782,7 @@ RETRY:
 			blk.key = key
 			blk.typ = typ
 			blk.b = b
+			blk.stats = stats
 			blk.readMin = math.MaxInt64
 			blk.readMax = math.MinInt64
 
@@ -1730,6 +1797,7 @@ RETRY:
 				if err != nil {
 					k.err = err
 				}
+				stats := iter.Stats()
 
 				var blk *block
 				if cap(k.buf[i]) > len(k.buf[i]) {
@@ -1749,6 +1817,7 @@ RETRY:
 				blk.key = key
 				blk.typ = typ
 				blk.b = b
+				blk.stats = stats
 				blk.readMin = math.MaxInt64
 				blk.readMax = math.MinInt64
 				blk.tombstones = iter.r.TombstoneRange(key, blk.tombstones[:0])
@@ -1838,6 +1907,14 @@ func (k *tsmBatchKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return block.key, block.minTime, block.maxTime, block.b, k.err
 }
 
+// ReadStats returns the statistics of the block returned by Read.
+func (k *tsmBatchKeyIterator) ReadStats() BlockStats {
+	if len(k.merged) == 0 {
+		return BlockStats{}
+	}
+	return k.merged[0].stats
+}
+
 func (k *tsmBatchKeyIterator) Close() error {
 	k.values = nil
 	k.pos = nil
@@ -1871,6 +1948,7 @@ type cacheBlock struct {
 	k                []byte
 	minTime, maxTime int64
 	b                []byte
+	stats            BlockStats
 	err              error
 }
 
@@ -1947,6 +2025,7 @@ func (c *cacheKeyIterator) encode() {
 					}
 
 					minTime, maxTime := values[0].UnixNano(), values[end-1].UnixNano()
+					stats := newValuesBlockStats(values[:end])
 					var b []byte
 					var err error
 
@@ -1972,6 +2051,7 @@ func (c *cacheKeyIterator) encode() {
 						minTime: minTime,
 						maxTime: maxTime,
 						b:       b,
+						stats:   stats,
 						err:     err,
 					})
 
@@ -2016,6 +2096,11 @@ func (c *cacheKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return blk.k, blk.minTime, blk.maxTime, blk.b, blk.err
 }
 
+// ReadStats returns the statistics of the block returned by Read.
+func (c *cacheKeyIterator) ReadStats() BlockStats {
+	return c.blocks[c.i][0].stats
+}
+
 func (c *cacheKeyIterator) Close() error {
 	return nil
 }
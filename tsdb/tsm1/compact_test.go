@@ -343,6 +343,76 @@ func TestCompactor_Compact_OverlappingBlocks(t *testing.T) {
 }
 
 // Ensures that a compaction will properly merge multiple TSM files
+// Tests that compactions record the statistics of merged and copied blocks.
+func TestCompactor_Compact_BlockStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	// The first key has overlapping blocks that are merged and split, the
+	// second one is only in one file and its block is copied as is.
+	writes := map[string][]tsm1.Value{
+		"cpu,host=A#!~#value": {tsm1.NewValue(1, int64(5)), tsm1.NewValue(3, int64(-1)), tsm1.NewValue(5, int64(9))},
+		"cpu,host=B#!~#value": {tsm1.NewValue(1, 1.5), tsm1.NewValue(2, 0.5)},
+	}
+	f1 := MustWriteTSM(dir, 1, writes)
+
+	writes = map[string][]tsm1.Value{
+		"cpu,host=A#!~#value": {tsm1.NewValue(2, int64(4)), tsm1.NewValue(4, int64(2))},
+	}
+	f2 := MustWriteTSM(dir, 2, writes)
+
+	fs := &fakeFileStore{}
+	defer fs.Close()
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = fs
+	compactor.Size = 2
+	compactor.Open()
+
+	files, err := compactor.CompactFull([]string{f1, f2})
+	if err != nil {
+		t.Fatalf("unexpected error compacting: %v", err)
+	}
+	if got, exp := len(files), 1; got != exp {
+		t.Fatalf("files length mismatch: got %v, exp %v", got, exp)
+	}
+
+	r := MustOpenTSMReader(files[0])
+	defer r.Close()
+
+	type blockStats struct {
+		Count                      uint32
+		Min, Max, Sum, First, Last float64
+	}
+	var got []blockStats
+	iter := r.Iterator(nil)
+	for iter.Next() {
+		for _, e := range iter.Entries() {
+			s := e.Stats
+			if iter.Type() == tsm1.BlockInteger {
+				min, max, sum, first, last := s.IntegerStats()
+				got = append(got, blockStats{s.Count, float64(min), float64(max), float64(sum), float64(first), float64(last)})
+			} else {
+				min, max, sum, first, last := s.FloatStats()
+				got = append(got, blockStats{s.Count, min, max, sum, first, last})
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("unexpected error iterating: %v", err)
+	}
+
+	exp := []blockStats{
+		{2, 4, 5, 9, 5, 4},
+		{2, -1, 2, 1, -1, 2},
+		{1, 9, 9, 9, 9, 9},
+		{2, 0.5, 1.5, 2, 1.5, 0.5},
+	}
+	if diff := cmp.Diff(got, exp); diff != "" {
+		t.Fatalf("unexpected block stats -got/+exp\n%s", diff)
+	}
+}
+
 func TestCompactor_Compact_OverlappingBlocksMultiple(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
@@ -2977,6 +3047,86 @@ func MustOpenTSMReader(name string) *tsm1.TSMReader {
 	return r
 }
 
+// Ensure that the time window planner compacts the generations of a window once
+// a newer window has started.
+func TestTimeWindowPlanner_Plan(t *testing.T) {
+	hour := int64(time.Hour)
+	cp := tsm1.NewTimeWindowPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat {
+				return []tsm1.FileStat{
+					{Path: "01-04.tsm1", MinTime: 0, MaxTime: hour - 1},
+					{Path: "02-01.tsm1", MinTime: 10, MaxTime: 20},
+					{Path: "03-01.tsm1", MinTime: 30, MaxTime: 40},
+					{Path: "04-01.tsm1", MinTime: hour, MaxTime: hour + 10},
+					{Path: "05-01.tsm1", MinTime: hour + 20, MaxTime: hour + 30},
+				}
+			},
+		}, time.Hour,
+	)
+
+	exp := []tsm1.CompactionGroup{{"01-04.tsm1", "02-01.tsm1", "03-01.tsm1"}}
+	tsm := cp.Plan(time.Now())
+	if !cmp.Equal(tsm, exp) {
+		t.Fatalf("unexpected plan: %v", cmp.Diff(tsm, exp))
+	}
+
+	// The most recent window does not have enough generations.
+	if tsm := cp.PlanLevel(1); len(tsm) != 0 {
+		t.Fatalf("unexpected level plan: %v", tsm)
+	}
+
+	// Files in use are not planned again until released.
+	if tsm := cp.Plan(time.Now()); len(tsm) != 0 {
+		t.Fatalf("unexpected plan of files in use: %v", tsm)
+	}
+	cp.Release(exp)
+	if tsm := cp.Plan(time.Now()); !cmp.Equal(tsm, exp) {
+		t.Fatalf("unexpected plan after release: %v", cmp.Diff(tsm, exp))
+	}
+}
+
+// Ensure that the time window planner bounds the generations of the most recent
+// window and compacts it when a full compaction is forced.
+func TestTimeWindowPlanner_PlanLevel(t *testing.T) {
+	stats := []tsm1.FileStat{
+		{Path: "01-01.tsm1", MinTime: 0, MaxTime: 10},
+		{Path: "02-01.tsm1", MinTime: 20, MaxTime: 30},
+		{Path: "03-01.tsm1", MinTime: 40, MaxTime: 50},
+	}
+	cp := tsm1.NewTimeWindowPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat { return stats },
+		}, time.Hour,
+	)
+
+	if tsm := cp.PlanLevel(1); len(tsm) != 0 {
+		t.Fatalf("unexpected level plan: %v", tsm)
+	}
+	if tsm := cp.Plan(time.Now()); len(tsm) != 0 {
+		t.Fatalf("unexpected plan of incomplete window: %v", tsm)
+	}
+	if cp.FullyCompacted() {
+		t.Fatal("expected window with many generations to not be fully compacted")
+	}
+
+	stats = append(stats, tsm1.FileStat{Path: "04-01.tsm1", MinTime: 60, MaxTime: 70})
+	exp := []tsm1.CompactionGroup{{"01-01.tsm1", "02-01.tsm1", "03-01.tsm1", "04-01.tsm1"}}
+	tsm := cp.PlanLevel(1)
+	if !cmp.Equal(tsm, exp) {
+		t.Fatalf("unexpected level plan: %v", cmp.Diff(tsm, exp))
+	}
+	cp.Release(tsm)
+
+	cp.ForceFull()
+	if tsm := cp.PlanLevel(1); len(tsm) != 0 {
+		t.Fatalf("unexpected level plan while forcing a full compaction: %v", tsm)
+	}
+	if tsm := cp.Plan(time.Now()); !cmp.Equal(tsm, exp) {
+		t.Fatalf("unexpected full plan: %v", cmp.Diff(tsm, exp))
+	}
+}
+
 type fakeFileStore struct {
 	PathsFn      func() []tsm1.FileStat
 	lastModified time.Time
@@ -0,0 +1,277 @@
+package tsm1
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCompactTimeWindow is the default window of time used by the
+	// TimeWindowPlanner to group TSM files.
+	DefaultCompactTimeWindow = 24 * time.Hour
+
+	// timeWindowMinGenerations is the number of generations of the most recent
+	// window that are compacted together before the window is complete.
+	timeWindowMinGenerations = 4
+)
+
+// TimeWindowPlanner implements CompactionPlanner for append-only workloads, where
+// points are written in time order and rarely overwritten.  Adjacent generations
+// of TSM files whose first point falls in the same window of time are compacted
+// into a single generation once a newer window has started.  Unlike the
+// DefaultPlanner, generations of windows that are complete are never rewritten
+// again, unless they have tombstones or a full compaction is forced.
+type TimeWindowPlanner struct {
+	FileStore fileStore
+
+	window time.Duration
+
+	mu sync.Mutex
+
+	// forceFull causes the next full plan to compact every window with more
+	// than one generation.
+	forceFull bool
+
+	// filesInUse is the set of files that have been returned as part of a plan and might
+	// be being compacted.  Two plans should not return the same file at any given time.
+	filesInUse map[string]struct{}
+}
+
+// NewTimeWindowPlanner returns a TimeWindowPlanner that groups TSM files by
+// windows of the given duration.
+func NewTimeWindowPlanner(fs fileStore, window time.Duration) *TimeWindowPlanner {
+	if window <= 0 {
+		window = DefaultCompactTimeWindow
+	}
+	return &TimeWindowPlanner{
+		FileStore:  fs,
+		window:     window,
+		filesInUse: make(map[string]struct{}),
+	}
+}
+
+// timeWindowRun is a run of adjacent generations whose first points fall in the
+// same window.
+type timeWindowRun struct {
+	start         int64 // start of the window
+	generations   int
+	files         []string
+	hasTombstones bool
+	inUse         bool // some files are part of a plan
+}
+
+func (r *timeWindowRun) group() CompactionGroup {
+	group := make(CompactionGroup, len(r.files))
+	copy(group, r.files)
+	sort.Strings(group)
+	return group
+}
+
+// runs returns the runs of generations of the files in the FileStore and the time
+// of the most recent point.  If skipInUse is true, runs with files that are part of
+// an existing plan are not returned.
+func (p *TimeWindowPlanner) runs(skipInUse bool) ([]*timeWindowRun, int64) {
+	type generation struct {
+		id            int
+		minTime       int64
+		files         []string
+		hasTombstones bool
+		inUse         bool
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	maxTime := int64(-1 << 63)
+	generations := make(map[int]*generation)
+	for _, stat := range p.FileStore.Stats() {
+		if stat.MaxTime > maxTime {
+			maxTime = stat.MaxTime
+		}
+
+		id, _, err := p.FileStore.ParseFileName(stat.Path)
+		if err != nil {
+			continue
+		}
+
+		g := generations[id]
+		if g == nil {
+			g = &generation{id: id, minTime: stat.MinTime}
+			generations[id] = g
+		}
+		if stat.MinTime < g.minTime {
+			g.minTime = stat.MinTime
+		}
+		g.files = append(g.files, stat.Path)
+		g.hasTombstones = g.hasTombstones || stat.HasTombstone
+		if _, ok := p.filesInUse[stat.Path]; ok {
+			g.inUse = true
+		}
+	}
+
+	ordered := make([]*generation, 0, len(generations))
+	for _, g := range generations {
+		ordered = append(ordered, g)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].id < ordered[j].id })
+
+	var runs []*timeWindowRun
+	for _, g := range ordered {
+		start := p.windowStart(g.minTime)
+		if len(runs) == 0 || runs[len(runs)-1].start != start {
+			runs = append(runs, &timeWindowRun{start: start})
+		}
+
+		run := runs[len(runs)-1]
+		run.generations++
+		run.files = append(run.files, g.files...)
+		run.hasTombstones = run.hasTombstones || g.hasTombstones
+		run.inUse = run.inUse || g.inUse
+	}
+
+	if skipInUse {
+		var available []*timeWindowRun
+		for _, run := range runs {
+			if !run.inUse {
+				available = append(available, run)
+			}
+		}
+		runs = available
+	}
+	return runs, maxTime
+}
+
+// windowStart returns the start of the window t falls in.
+func (p *TimeWindowPlanner) windowStart(t int64) int64 {
+	w := int64(p.window)
+	start := t - t%w
+	if t%w < 0 {
+		start -= w
+	}
+	return start
+}
+
+func (p *TimeWindowPlanner) SetFileStore(fs *FileStore) {
+	p.FileStore = fs
+}
+
+// FullyCompacted returns true if every window is made of a single generation
+// without tombstones.
+func (p *TimeWindowPlanner) FullyCompacted() bool {
+	runs, _ := p.runs(false)
+	for _, run := range runs {
+		if run.generations > 1 || run.hasTombstones {
+			return false
+		}
+	}
+	return true
+}
+
+// ForceFull causes the planner to return a plan compacting every window made of
+// more than one generation the next time Plan is called.
+func (p *TimeWindowPlanner) ForceFull() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.forceFull = true
+}
+
+// PlanLevel returns groups of generations of the most recent window once enough
+// of them exist, to bound the number of files while the window is written to.
+// Only level 1 is planned.
+func (p *TimeWindowPlanner) PlanLevel(level int) []CompactionGroup {
+	if level != 1 {
+		return nil
+	}
+
+	p.mu.Lock()
+	forceFull := p.forceFull
+	p.mu.Unlock()
+	if forceFull {
+		return nil
+	}
+
+	runs, maxTime := p.runs(true)
+	var groups []CompactionGroup
+	for _, run := range runs {
+		if p.complete(run, maxTime) || run.generations < timeWindowMinGenerations {
+			continue
+		}
+		groups = append(groups, run.group())
+	}
+
+	if !p.acquire(groups) {
+		return nil
+	}
+	return groups
+}
+
+// PlanOptimize returns no plans since complete windows are compacted by Plan.
+func (p *TimeWindowPlanner) PlanOptimize() []CompactionGroup {
+	return nil
+}
+
+// Plan returns a group for each complete window made of more than one generation
+// or with tombstones.
+func (p *TimeWindowPlanner) Plan(lastWrite time.Time) []CompactionGroup {
+	p.mu.Lock()
+	forceFull := p.forceFull
+	p.forceFull = false
+	p.mu.Unlock()
+
+	runs, maxTime := p.runs(true)
+	var groups []CompactionGroup
+	for _, run := range runs {
+		if !forceFull && !p.complete(run, maxTime) {
+			continue
+		}
+		if run.generations <= 1 && !run.hasTombstones {
+			continue
+		}
+		groups = append(groups, run.group())
+	}
+
+	if !p.acquire(groups) {
+		return nil
+	}
+	return groups
+}
+
+// complete returns true if points newer than the window of run exist.
+func (p *TimeWindowPlanner) complete(run *timeWindowRun, maxTime int64) bool {
+	return maxTime >= run.start+int64(p.window)
+}
+
+func (p *TimeWindowPlanner) acquire(groups []CompactionGroup) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// See if the new files are already in use
+	for _, g := range groups {
+		for _, f := range g {
+			if _, ok := p.filesInUse[f]; ok {
+				return false
+			}
+		}
+	}
+
+	// Mark all the new files in use
+	for _, g := range groups {
+		for _, f := range g {
+			p.filesInUse[f] = struct{}{}
+		}
+	}
+	return true
+}
+
+// Release removes the files reference in each compaction group allowing new plans
+// to be able to use them.
+func (p *TimeWindowPlanner) Release(groups []CompactionGroup) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, g := range groups {
+		for _, f := range g {
+			delete(p.filesInUse, f)
+		}
+	}
+}
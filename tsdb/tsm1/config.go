@@ -27,6 +27,7 @@ type Config struct {
 
 	Compaction CompactionConfig `toml:"compaction"`
 	Cache      CacheConfig      `toml:"cache"`
+	ColdTier   ColdTierConfig   `toml:"cold-tier"`
 }
 
 // NewConfig constructs a Config with the default values.
@@ -45,6 +46,13 @@ func NewConfig() Config {
 			Throughput:            toml.Size(DefaultCompactThroughput),
 			ThroughputBurst:       toml.Size(DefaultCompactThroughputBurst),
 			MaxConcurrent:         DefaultCompactMaxConcurrent,
+			Planner:               DefaultCompactPlanner,
+			TimeWindow:            toml.Duration(DefaultCompactTimeWindow),
+		},
+		ColdTier: ColdTierConfig{
+			Age:            toml.Duration(DefaultColdTierAge),
+			CheckInterval:  toml.Duration(DefaultColdTierCheckInterval),
+			BlockCacheSize: toml.Size(DefaultColdTierBlockCacheSize),
 		},
 	}
 }
@@ -54,6 +62,8 @@ const (
 	DefaultCompactThroughput            = 48 * 1024 * 1024
 	DefaultCompactThroughputBurst       = 48 * 1024 * 1024
 	DefaultCompactMaxConcurrent         = 0
+	DefaultCompactPlanner               = "default"
+	TimeWindowCompactPlanner            = "time-window"
 )
 
 // CompactionConfing holds all of the configuration for compactions. Eventually we want
@@ -77,6 +87,14 @@ type CompactionConfig struct {
 	// MaxConcurrent is the maximum number of concurrent full and level compactions that can
 	// run at one time.  A value of 0 results in 50% of runtime.GOMAXPROCS(0) used at runtime.
 	MaxConcurrent int `toml:"max-concurrent"`
+
+	// Planner selects how TSM files are grouped into compactions.  The "default"
+	// planner rolls up files in levels and the "time-window" planner groups files
+	// by the time of their points, which suits append-only workloads.
+	Planner string `toml:"planner"`
+
+	// TimeWindow is the window of time used by the "time-window" planner.
+	TimeWindow toml.Duration `toml:"time-window"`
 }
 
 const (
@@ -102,6 +120,35 @@ type CacheConfig struct {
 	SnapshotWriteColdDuration toml.Duration `toml:"snapshot-write-cold-duration"`
 }
 
+const (
+	DefaultColdTierAge            = time.Duration(30 * 24 * time.Hour)
+	DefaultColdTierCheckInterval  = time.Duration(time.Hour)
+	DefaultColdTierBlockCacheSize = 64 * 1024 * 1024 // 64MB
+)
+
+// ColdTierConfig holds all of the configuration for moving TSM files that are no
+// longer written to a cheaper storage tier.
+type ColdTierConfig struct {
+	// Enabled controls if TSM files are moved to the cold tier.
+	Enabled bool `toml:"enabled"`
+
+	// Path is the directory the TSM files are moved to when no other cold store
+	// is provided to the engine.  It must not be shared with other engines.
+	Path string `toml:"path"`
+
+	// Age is the length of time after which a fully compacted TSM generation is
+	// moved to the cold tier, measured from the newest point it contains.
+	Age toml.Duration `toml:"age"`
+
+	// CheckInterval is the interval at which the engine looks for TSM generations
+	// to move to the cold tier.
+	CheckInterval toml.Duration `toml:"check-interval"`
+
+	// BlockCacheSize is the maximum size of the blocks read from the cold tier that
+	// are kept in memory.
+	BlockCacheSize toml.Size `toml:"block-cache-size"`
+}
+
 const (
 	DefaultWALEnabled    = true
 	DefaultWALFsyncDelay = time.Duration(0)
@@ -117,6 +164,16 @@ type WALConfig struct {
 	// useful for slower disks or when WAL write contention is seen.  A value of 0 fsyncs
 	// every write to the WAL.
 	FsyncDelay toml.Duration `toml:"fsync-delay"`
+
+	// RetentionSize is the total size of the WAL segments kept after their data has
+	// been written to TSM files, so that replicas falling behind can catch up without
+	// a snapshot of the TSM files.  A value of 0 does not keep any segment.
+	RetentionSize toml.Size `toml:"retention-size"`
+
+	// Replication enables the WAL entries replicas need to follow the engine.
+	// Versions of influxd without replication can not read the WAL segments
+	// written once it is enabled, until the segments are removed.
+	Replication bool `toml:"replication"`
 }
 
 func NewWALConfig() WALConfig {
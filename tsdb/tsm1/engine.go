@@ -162,6 +162,13 @@ type Engine struct {
 	compactionLimiter limiter.Fixed
 
 	scheduler *scheduler
+
+	compactions       compactionList // Running and queued compactions.
+	compactionPlanner string         // Name of the configured compaction planner.
+
+	coldTierConfig ColdTierConfig
+	coldStore      ColdStore
+	coldTier       *coldTierMover // Moves files to the cold tier while the engine is open.
 }
 
 // NewEngine returns a new instance of Engine.
@@ -221,6 +228,16 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 		formatFileName:                DefaultFormatFileName,
 		compactionLimiter:             limiter.NewFixed(maxCompactions),
 		scheduler:                     newScheduler(maxCompactions),
+		compactionPlanner:             config.Compaction.Planner,
+		coldTierConfig:                config.ColdTier,
+	}
+
+	if config.Compaction.Planner == TimeWindowCompactPlanner {
+		e.CompactionPlan = NewTimeWindowPlanner(fs, time.Duration(config.Compaction.TimeWindow))
+	}
+
+	if config.ColdTier.Enabled && config.ColdTier.Path != "" {
+		e.coldStore = NewDirColdStore(config.ColdTier.Path)
 	}
 
 	for _, option := range options {
@@ -244,6 +261,12 @@ func (e *Engine) WithFileStoreObserver(obs FileStoreObserver) {
 	e.FileStore.WithObserver(obs)
 }
 
+// WithColdStore sets the store TSM files are moved to when the cold tier is
+// enabled, in place of the directory set in the configuration.
+func (e *Engine) WithColdStore(store ColdStore) {
+	e.coldStore = store
+}
+
 func (e *Engine) WithCompactionPlanner(planner CompactionPlanner) {
 	planner.SetFileStore(e.FileStore)
 	e.CompactionPlan = planner
@@ -521,6 +544,12 @@ func (e *Engine) initTrackers() {
 
 // Open opens and initializes the engine.
 func (e *Engine) Open() error {
+	switch e.compactionPlanner {
+	case "", DefaultCompactPlanner, TimeWindowCompactPlanner:
+	default:
+		return fmt.Errorf("unknown compaction planner %q", e.compactionPlanner)
+	}
+
 	e.initTrackers()
 
 	if err := os.MkdirAll(e.path, 0777); err != nil {
@@ -535,6 +564,23 @@ func (e *Engine) Open() error {
 		return err
 	}
 
+	// Files already moved to the cold tier are opened even if moving files is
+	// no longer enabled.
+	if e.coldStore != nil {
+		e.FileStore.WithColdStore(e.coldStore, uint64(e.coldTierConfig.BlockCacheSize))
+	}
+	if e.coldTierConfig.Enabled {
+		if e.coldStore == nil {
+			return errors.New("cold tier enabled without a path or cold store")
+		}
+		e.coldTier = newColdTierMover(e.FileStore,
+			time.Duration(e.coldTierConfig.Age),
+			time.Duration(e.coldTierConfig.CheckInterval),
+			e.FileStore.obs)
+		e.coldTier.logger = e.logger
+		e.FileStore.WithObserver(e.coldTier)
+	}
+
 	if err := e.FileStore.Open(); err != nil {
 		return err
 	}
@@ -549,11 +595,21 @@ func (e *Engine) Open() error {
 		e.SetCompactionsEnabled(true)
 	}
 
+	if e.coldTier != nil {
+		e.coldTier.open()
+	}
+
 	return nil
 }
 
 // Close closes the engine. Subsequent calls to Close are a nop.
 func (e *Engine) Close() error {
+	if e.coldTier != nil {
+		e.coldTier.close()
+		e.FileStore.WithObserver(e.coldTier.obs)
+		e.coldTier = nil
+	}
+
 	e.SetCompactionsEnabled(false)
 
 	// Lock now and close everything else down.
@@ -598,6 +654,51 @@ func (e *Engine) Free() error {
 // WritePoints writes metadata and point data into the engine.
 // It returns an error if new points are added to an existing key.
 func (e *Engine) WritePoints(points []models.Point) error {
+	values, err := pointValues(points)
+	if err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	// first try to write to the cache
+	if err := e.Cache.WriteMulti(values); err != nil {
+		return err
+	}
+
+	// Then make the write durable in the cache.
+	if _, err := e.WAL.WriteMulti(values); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidatePoints returns the error WritePoints would return for points
+// because of the values of the cache, without writing them. Points with values
+// of the wrong type are reported by series key in a tsdb.InvalidPointsError.
+func (e *Engine) ValidatePoints(points []models.Point) error {
+	values, err := pointValues(points)
+	if err != nil {
+		return err
+	}
+
+	err = e.Cache.Validate(values)
+	invalid, ok := err.(tsdb.InvalidPointsError)
+	if !ok {
+		return err
+	}
+	reasons := make(map[string]string, len(invalid.Reasons))
+	for key, reason := range invalid.Reasons {
+		seriesKey, _ := SeriesAndFieldFromCompositeKey([]byte(key))
+		reasons[string(seriesKey)] = reason
+	}
+	return tsdb.InvalidPointsError{Reasons: reasons}
+}
+
+// pointValues returns the values of the fields of points by series key.
+func pointValues(points []models.Point) (map[string][]Value, error) {
 	values := make(map[string][]Value, len(points))
 	var (
 		keyBuf  []byte
@@ -618,19 +719,19 @@ func (e *Engine) WritePoints(points []models.Point) error {
 			case models.Float:
 				fv, err := iter.FloatValue()
 				if err != nil {
-					return err
+					return nil, err
 				}
 				v = NewFloatValue(t, fv)
 			case models.Integer:
 				iv, err := iter.IntegerValue()
 				if err != nil {
-					return err
+					return nil, err
 				}
 				v = NewIntegerValue(t, iv)
 			case models.Unsigned:
 				iv, err := iter.UnsignedValue()
 				if err != nil {
-					return err
+					return nil, err
 				}
 				v = NewUnsignedValue(t, iv)
 			case models.String:
@@ -638,30 +739,16 @@ func (e *Engine) WritePoints(points []models.Point) error {
 			case models.Boolean:
 				bv, err := iter.BooleanValue()
 				if err != nil {
-					return err
+					return nil, err
 				}
 				v = NewBooleanValue(t, bv)
 			default:
-				return fmt.Errorf("unknown field type for %s: %s", string(iter.FieldKey()), p.String())
+				return nil, fmt.Errorf("unknown field type for %s: %s", string(iter.FieldKey()), p.String())
 			}
 			values[string(keyBuf)] = append(values[string(keyBuf)], v)
 		}
 	}
-
-	e.mu.RLock()
-	defer e.mu.RUnlock()
-
-	// first try to write to the cache
-	if err := e.Cache.WriteMulti(values); err != nil {
-		return err
-	}
-
-	// Then make the write durable in the cache.
-	if _, err := e.WAL.WriteMulti(values); err != nil {
-		return err
-	}
-
-	return nil
+	return values, nil
 }
 
 // DeleteSeriesRange removes the values between min and max (inclusive) from all series
@@ -870,8 +957,12 @@ func (e *Engine) deleteSeriesRange(seriesKeys [][]byte, min, max int64) error {
 
 	e.Cache.DeleteRange(deleteKeys, min, max)
 
-	// delete from the WAL
-	if _, err := e.WAL.DeleteRange(deleteKeys, min, max); err != nil {
+	// delete from the WAL.  The series keys are recorded along with the cached keys
+	// so that replicas applying the entry delete the data of their TSM files as well.
+	walKeys := make([][]byte, 0, len(deleteKeys)+len(seriesKeys))
+	walKeys = append(walKeys, deleteKeys...)
+	walKeys = append(walKeys, seriesKeys...)
+	if _, err := e.WAL.DeleteRange(walKeys, min, max); err != nil {
 		return err
 	}
 
@@ -1313,6 +1404,7 @@ func (e *Engine) ShouldCompactCache(t time.Time) bool {
 func (e *Engine) compact(wg *sync.WaitGroup) {
 	t := time.NewTicker(time.Second)
 	defer t.Stop()
+	defer e.compactions.setQueued(nil)
 
 	for {
 		e.mu.RLock()
@@ -1371,6 +1463,18 @@ func (e *Engine) compact(wg *sync.WaitGroup) {
 				}
 			}
 
+			// Record the plans we didn't start as queued.
+			var queued []queuedCompaction
+			for level, groups := range [][]CompactionGroup{level1Groups, level2Groups, level3Groups} {
+				for _, group := range groups {
+					queued = append(queued, queuedCompaction{level: compactionLevel(level + 1), group: group})
+				}
+			}
+			for _, group := range level4Groups {
+				queued = append(queued, queuedCompaction{level: 5, group: group})
+			}
+			e.compactions.setQueued(queued)
+
 			// Release all the plans we didn't start.
 			e.CompactionPlan.Release(level1Groups)
 			e.CompactionPlan.Release(level2Groups)
@@ -1476,6 +1580,10 @@ type compactionStrategy struct {
 
 // Apply concurrently compacts all the groups in a compaction strategy.
 func (s *compactionStrategy) Apply() {
+	if s.engine != nil {
+		s.engine.compactions.start(s)
+		defer s.engine.compactions.finish(s)
+	}
 	s.compactGroup()
 }
 
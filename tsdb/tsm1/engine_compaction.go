@@ -0,0 +1,233 @@
+package tsm1
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// CompactionInfo describes a compaction that is running or queued in the engine.
+type CompactionInfo struct {
+	// Level is the level of the compaction: 1 to 3 for level compactions, 4 for
+	// optimize compactions and 5 for full compactions.
+	Level int
+
+	// Running is true if the compaction is running and false if it is queued.
+	Running bool
+
+	// StartedAt is the time a running compaction started.
+	StartedAt time.Time
+
+	// Files are the TSM files being compacted.
+	Files []CompactionFileInfo
+
+	// Size is the total size of the files being compacted.
+	Size int64
+
+	// BytesWritten is the number of bytes of blocks written so far by a running
+	// compaction.
+	BytesWritten int64
+}
+
+// CompactionFileInfo describes a TSM file that is part of a compaction.
+type CompactionFileInfo struct {
+	Path string
+	Size int64
+}
+
+// Progress returns an estimate of the fraction of a compaction that is complete.
+func (c CompactionInfo) Progress() float64 {
+	if c.Size == 0 {
+		return 0
+	}
+
+	p := float64(c.BytesWritten) / float64(c.Size)
+	if p > 1 {
+		p = 1
+	}
+	return p
+}
+
+// compactionList tracks the compactions running and queued in the engine.
+type compactionList struct {
+	mu      sync.Mutex
+	running map[*compactionStrategy]time.Time
+	queued  []queuedCompaction
+}
+
+type queuedCompaction struct {
+	level compactionLevel
+	group CompactionGroup
+}
+
+func (l *compactionList) start(s *compactionStrategy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running == nil {
+		l.running = make(map[*compactionStrategy]time.Time)
+	}
+	l.running[s] = time.Now()
+}
+
+func (l *compactionList) finish(s *compactionStrategy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.running, s)
+}
+
+// setQueued replaces the compactions that were planned but not started.
+func (l *compactionList) setQueued(queued []queuedCompaction) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queued = queued
+}
+
+// Compactions returns the compactions running in the engine, ordered by the time
+// they started, followed by the compactions planned but waiting for a compaction
+// slot.
+func (e *Engine) Compactions() []CompactionInfo {
+	sizes := make(map[string]int64)
+	for _, stat := range e.FileStore.Stats() {
+		sizes[stat.Path] = int64(stat.Size)
+	}
+
+	newInfo := func(level compactionLevel, group CompactionGroup) CompactionInfo {
+		info := CompactionInfo{
+			Level: int(level),
+			Files: make([]CompactionFileInfo, 0, len(group)),
+		}
+		for _, path := range group {
+			info.Files = append(info.Files, CompactionFileInfo{Path: path, Size: sizes[path]})
+			info.Size += sizes[path]
+		}
+		return info
+	}
+
+	e.compactions.mu.Lock()
+	defer e.compactions.mu.Unlock()
+
+	running := make([]CompactionInfo, 0, len(e.compactions.running))
+	for s, started := range e.compactions.running {
+		info := newInfo(s.level, s.group)
+		info.Running = true
+		info.StartedAt = started
+		info.BytesWritten = e.Compactor.BytesWritten(s.group)
+		running = append(running, info)
+	}
+	sort.Slice(running, func(i, j int) bool {
+		return running[i].StartedAt.Before(running[j].StartedAt)
+	})
+
+	for _, q := range e.compactions.queued {
+		running = append(running, newInfo(q.level, q.group))
+	}
+	return running
+}
+
+// CompactionsEnabled returns true if level compactions are enabled.
+func (e *Engine) CompactionsEnabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.done != nil
+}
+
+// ScheduleOptimizeCompaction starts compactions that rewrite each run of adjacent
+// fully compacted generations of TSM files into a single generation, without
+// waiting for the planner to consider them worth optimizing.  Any data in the
+// cache is snapshotted first.
+func (e *Engine) ScheduleOptimizeCompaction() error {
+	if err := e.WriteSnapshot(); err != nil {
+		return err
+	}
+
+	groups := e.optimizeGroups()
+
+	// Hold the lock while adding to the wait group so that compactions can not be
+	// disabled concurrently.
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.done == nil {
+		return errCompactionsDisabled
+	}
+	select {
+	case <-e.done:
+		return errCompactionsDisabled
+	default:
+	}
+
+	for _, group := range groups {
+		s := e.fullCompactionStrategy(group, true)
+
+		e.wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+
+			e.compactionLimiter.Take()
+			defer e.compactionLimiter.Release()
+
+			e.compactionTracker.IncActive(s.level)
+			defer e.compactionTracker.DecActive(s.level)
+			s.Apply()
+		}(e.wg)
+	}
+	return nil
+}
+
+// optimizeGroups returns the files of each run of adjacent level 4 generations
+// made of more than one generation.
+func (e *Engine) optimizeGroups() []CompactionGroup {
+	type generation struct {
+		id    int
+		level int
+		files []string
+	}
+
+	generations := make(map[int]*generation)
+	for _, stat := range e.FileStore.Stats() {
+		id, seq, err := e.FileStore.ParseFileName(stat.Path)
+		if err != nil {
+			continue
+		}
+
+		g := generations[id]
+		if g == nil {
+			g = &generation{id: id, level: 4}
+			generations[id] = g
+		}
+		if seq < 4 {
+			g.level = seq
+		}
+		g.files = append(g.files, stat.Path)
+	}
+
+	ordered := make([]*generation, 0, len(generations))
+	for _, g := range generations {
+		ordered = append(ordered, g)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].id < ordered[j].id })
+
+	var groups []CompactionGroup
+	var run []*generation
+	flush := func() {
+		if len(run) > 1 {
+			var group CompactionGroup
+			for _, g := range run {
+				group = append(group, g.files...)
+			}
+			sort.Strings(group)
+			groups = append(groups, group)
+		}
+		run = run[:0]
+	}
+	for _, g := range ordered {
+		if g.level < 4 {
+			flush()
+			continue
+		}
+		run = append(run, g)
+	}
+	flush()
+
+	return groups
+}
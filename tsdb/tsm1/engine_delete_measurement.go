@@ -6,7 +6,6 @@ import (
 	"sync"
 
 	"github.com/influxdata/influxdb/models"
-	"github.com/influxdata/influxdb/pkg/bytesutil"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxql"
 )
@@ -76,32 +75,16 @@ func (e *Engine) DeleteBucket(name []byte, min, max int64) error {
 		return err
 	}
 
-	// TODO(jeff): add a DeletePrefix to the Cache and WAL.
-	// TODO(jeff): add a Tombstone entry into the WAL for deletes.
-
-	var deleteKeys [][]byte
-
-	// ApplySerialEntryFn cannot return an error in this invocation.
-	_ = e.Cache.ApplyEntryFn(func(k []byte, _ *entry) error {
-		if bytes.HasPrefix(k, name) {
-			if deleteKeys == nil {
-				deleteKeys = make([][]byte, 0, 10000)
-			}
-			deleteKeys = append(deleteKeys, k)
-
-			// we have to double check every key in the cache because maybe
-			// it exists in the index but not yet on disk.
-			possiblyDead.keys[string(k)] = struct{}{}
-		}
-		return nil
-	})
-
-	// Sort the series keys because ApplyEntryFn iterates over the keys randomly.
-	bytesutil.Sort(deleteKeys)
-
-	// Delete from the cache and WAL.
-	e.Cache.DeleteRange(deleteKeys, min, max)
-	if _, err := e.WAL.DeleteRange(deleteKeys, min, max); err != nil {
+	// Delete from the cache and WAL. With replication, the WAL records the bucket rather
+	// than the cached keys so that replicas applying it delete the data of their TSM
+	// files as well.
+	deleteKeys := e.Cache.DeleteBucketRange(name, min, max)
+	for _, k := range deleteKeys {
+		// we have to double check every key in the cache because maybe
+		// it exists in the index but not yet on disk.
+		possiblyDead.keys[string(k)] = struct{}{}
+	}
+	if _, err := e.WAL.DeleteBucketRange(name, deleteKeys, min, max); err != nil {
 		return err
 	}
 
@@ -32,6 +32,9 @@ const (
 
 	// The extension used to describe corrupt snapshot files.
 	BadTSMFileExtension = "bad"
+
+	// The extension used to describe files pointing to TSM files in the cold store.
+	ColdTSMFileExtension = "cold"
 )
 
 type TSMIterator interface {
@@ -206,6 +209,9 @@ type FileStore struct {
 	parseFileName ParseFileNameFunc
 
 	obs FileStoreObserver
+
+	coldStore  ColdStore   // store of the TSM files moved to the cold tier
+	blockCache *blockCache // cache of the blocks read from cold TSM files
 }
 
 // FileStat holds information about a TSM file on disk.
@@ -262,6 +268,13 @@ func (f *FileStore) WithObserver(obs FileStoreObserver) {
 	f.obs = obs
 }
 
+// WithColdStore sets the store of the TSM files moved to the cold tier.  Blocks
+// read from those files are cached up to cacheSize bytes.
+func (f *FileStore) WithColdStore(store ColdStore, cacheSize uint64) {
+	f.coldStore = store
+	f.blockCache = newBlockCache(cacheSize)
+}
+
 func (f *FileStore) WithParseFileNameFunc(parseFileNameFunc ParseFileNameFunc) {
 	f.parseFileName = parseFileNameFunc
 }
@@ -555,6 +568,11 @@ func (f *FileStore) Open() error {
 		return err
 	}
 
+	coldFiles, err := f.coldFiles(files)
+	if err != nil {
+		return err
+	}
+
 	// struct to hold the result of opening each reader in a goroutine
 	type res struct {
 		r   *TSMReader
@@ -610,8 +628,45 @@ func (f *FileStore) Open() error {
 		}(i, file)
 	}
 
+	for i, fn := range coldFiles {
+		generation, _, err := f.parseFileName(fn)
+		if err != nil {
+			return err
+		}
+
+		if generation >= f.currentGeneration {
+			f.currentGeneration = generation + 1
+		}
+
+		go func(idx int, fn string) {
+			f.openLimiter.Take()
+			defer f.openLimiter.Release()
+
+			start := time.Now()
+			name, err := ioutil.ReadFile(coldPointerPath(fn))
+			if err != nil {
+				readerC <- &res{err: fmt.Errorf("error opening cold pointer file %s: %v", fn, err)}
+				return
+			}
+
+			df, err := newColdTSMReader(fn, string(name), f.coldStore, f.blockCache,
+				WithTSMReaderLogger(f.logger))
+			if err != nil {
+				readerC <- &res{err: fmt.Errorf("error opening cold file %s: %v", fn, err)}
+				return
+			}
+			f.logger.Info("Opened cold file",
+				zap.String("path", fn),
+				zap.Int("id", idx),
+				zap.Duration("duration", time.Since(start)))
+
+			df.WithObserver(f.obs)
+			readerC <- &res{r: df}
+		}(i, fn)
+	}
+
 	var lm int64
-	for range files {
+	for i := 0; i < len(files)+len(coldFiles); i++ {
 		res := <-readerC
 		if res.err != nil {
 			return res.err
@@ -641,6 +696,46 @@ func (f *FileStore) Open() error {
 	return nil
 }
 
+// coldFiles returns the paths of the TSM files of the cold tier.  The pointer of a
+// file that also exists in the engine path is removed, which happens when the
+// process stops before a move to the cold tier completes.
+func (f *FileStore) coldFiles(files []string) ([]string, error) {
+	pointers, err := filepath.Glob(filepath.Join(f.dir, fmt.Sprintf("*.%s.%s", TSMFileExtension, ColdTSMFileExtension)))
+	if err != nil {
+		return nil, err
+	}
+
+	hot := make(map[string]struct{}, len(files))
+	for _, fn := range files {
+		hot[fn] = struct{}{}
+	}
+
+	var cold []string
+	for _, pointer := range pointers {
+		fn := strings.TrimSuffix(pointer, "."+ColdTSMFileExtension)
+		if _, ok := hot[fn]; ok {
+			if f.coldStore != nil {
+				name, err := ioutil.ReadFile(pointer)
+				if err != nil {
+					return nil, err
+				} else if err := f.coldStore.Remove(string(name)); err != nil {
+					return nil, err
+				}
+			}
+			if err := os.Remove(pointer); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if f.coldStore == nil {
+			return nil, fmt.Errorf("cannot open cold file %s without a cold store", fn)
+		}
+		cold = append(cold, fn)
+	}
+	return cold, nil
+}
+
 // Close closes the file store.
 func (f *FileStore) Close() error {
 	// Make the object appear closed to other method calls.
@@ -1143,7 +1238,12 @@ func (f *FileStore) CreateSnapshot() (string, error) {
 	}
 	for _, tsmf := range files {
 		newpath := filepath.Join(tmpPath, filepath.Base(tsmf.Path()))
-		if err := os.Link(tsmf.Path(), newpath); err != nil {
+		if r, ok := tsmf.(*TSMReader); ok && r.Cold() {
+			// Files in the cold tier cannot be linked so they are copied from the cold store.
+			if err := r.accessor.(*coldAccessor).copyTo(newpath); err != nil {
+				return "", fmt.Errorf("error copying cold tsm file: %q", err)
+			}
+		} else if err := os.Link(tsmf.Path(), newpath); err != nil {
 			return "", fmt.Errorf("error creating tsm hard link: %q", err)
 		}
 		for _, tf := range tsmf.TombstoneFiles() {
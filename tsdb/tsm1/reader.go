@@ -250,6 +250,12 @@ func (t *TSMReader) InUse() bool {
 	return refs > 0
 }
 
+// Cold returns true if the file was moved to the cold tier.
+func (t *TSMReader) Cold() bool {
+	_, ok := t.accessor.(*coldAccessor)
+	return ok
+}
+
 // Remove removes any underlying files stored on disk for this reader.
 func (t *TSMReader) Remove() error {
 	t.mu.Lock()
@@ -280,6 +286,13 @@ func (t *TSMReader) remove() error {
 		}
 	}
 
+	// Files moved to the cold tier also remove the object in the cold store.
+	if c, ok := t.accessor.(*coldAccessor); ok {
+		if err := c.remove(); err != nil {
+			return err
+		}
+	}
+
 	if err := t.tombstoner.Delete(); err != nil {
 		return err
 	}
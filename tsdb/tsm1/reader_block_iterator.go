@@ -46,6 +46,12 @@ func (b *BlockIterator) Read() (key []byte, minTime int64, maxTime int64, typ by
 	return b.iter.Key(), b.entries[0].MinTime, b.entries[0].MaxTime, b.iter.Type(), checksum, buf, err
 }
 
+// Stats returns the statistics of the values in the next block to be iterated.
+// They are not available for blocks of version 1 files.
+func (b *BlockIterator) Stats() BlockStats {
+	return b.entries[0].Stats
+}
+
 // Err returns any errors encounter during iteration.
 func (b *BlockIterator) Err() error {
 	return b.iter.Err()
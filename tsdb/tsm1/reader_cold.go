@@ -0,0 +1,301 @@
+package tsm1
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/influxdata/influxdb/pkg/file"
+	"go.uber.org/zap"
+)
+
+// coldAccessor is a blockAccessor for TSM files that were moved to a ColdStore.
+// The index is held in memory and blocks are read from the object on demand
+// through a block cache shared by all the cold files of a FileStore.
+type coldAccessor struct {
+	logger *zap.Logger
+
+	mu    sync.RWMutex
+	obj   ColdObject
+	name  string // name of the object in the cold store
+	store ColdStore
+	cache *blockCache
+
+	// p is the path the TSM file had in the engine path.  The file itself no
+	// longer exists but the path identifies the file to the FileStore and is
+	// where its tombstones and statistics are kept.
+	p string
+
+	index *indirectIndex
+}
+
+// newColdTSMReader returns a TSMReader for the TSM file that was moved from path to
+// the object called name in store.
+func newColdTSMReader(path, name string, store ColdStore, cache *blockCache, options ...tsmReaderOption) (*TSMReader, error) {
+	t := &TSMReader{
+		logger: zap.NewNop(),
+	}
+	for _, option := range options {
+		option(t)
+	}
+
+	stat, err := os.Stat(coldPointerPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := store.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	t.size = obj.Size()
+	t.lastModified = stat.ModTime().UnixNano()
+	t.accessor = &coldAccessor{
+		logger: t.logger,
+		obj:    obj,
+		name:   name,
+		store:  store,
+		cache:  cache,
+		p:      path,
+	}
+
+	index, err := t.accessor.init()
+	if err != nil {
+		obj.Close()
+		return nil, err
+	}
+
+	t.index = index
+	t.tombstoner = NewTombstoner(t.Path(), index.MaybeContainsKey)
+
+	if err := t.applyTombstones(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (c *coldAccessor) init() (*indirectIndex, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	size := c.obj.Size()
+	version, err := verifyVersion(io.NewSectionReader(c.obj, 0, size))
+	if err != nil {
+		return nil, err
+	}
+	if size < 8 {
+		return nil, fmt.Errorf("coldAccessor: object too small for indirectIndex")
+	}
+
+	var footer [8]byte
+	if _, err := c.obj.ReadAt(footer[:], size-8); err != nil {
+		return nil, err
+	}
+	indexStart := int64(binary.BigEndian.Uint64(footer[:]))
+	if indexStart < 0 || indexStart >= size-8 {
+		return nil, fmt.Errorf("coldAccessor: invalid indexStart")
+	}
+
+	// The index is read into memory since it is accessed for every lookup.
+	b := make([]byte, size-8-indexStart)
+	if _, err := c.obj.ReadAt(b, indexStart); err != nil {
+		return nil, err
+	}
+
+	c.index = NewIndirectIndex()
+	c.index.version = version
+	if err := c.index.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	c.index.logger = c.logger
+
+	return c.index, nil
+}
+
+// block returns the checksum and data of the block of entry, reading it from the
+// object if it is not cached.
+func (c *coldAccessor) block(entry *IndexEntry) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.obj == nil || entry.Size < 4 {
+		return nil, ErrTSMClosed
+	}
+
+	if b, ok := c.cache.get(c.p, entry.Offset); ok {
+		return b, nil
+	}
+
+	b, err := c.readAt(entry)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.put(c.p, entry.Offset, b)
+	return b, nil
+}
+
+// readAt reads the checksum and data of the block of entry from the object.
+func (c *coldAccessor) readAt(entry *IndexEntry) ([]byte, error) {
+	b := make([]byte, entry.Size)
+	if _, err := c.obj.ReadAt(b, entry.Offset); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (c *coldAccessor) read(key []byte, timestamp int64) ([]Value, error) {
+	entry := c.index.Entry(key, timestamp)
+	if entry == nil {
+		return nil, nil
+	}
+
+	return c.readBlock(entry, nil)
+}
+
+func (c *coldAccessor) readBlock(entry *IndexEntry, values []Value) ([]Value, error) {
+	b, err := c.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBlock(b[4:], values)
+}
+
+// readBytes returns the checksum and data of the block of entry.  It is used to
+// copy blocks during compactions and bypasses the block cache so that they do
+// not evict the blocks of queries.
+func (c *coldAccessor) readBytes(entry *IndexEntry, b []byte) (uint32, []byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.obj == nil || entry.Size < 4 {
+		return 0, nil, ErrTSMClosed
+	}
+
+	buf, err := c.readAt(entry)
+	if err != nil {
+		return 0, nil, err
+	}
+	return binary.BigEndian.Uint32(buf[:4]), buf[4:], nil
+}
+
+// readAll returns all values for a key in all blocks.
+func (c *coldAccessor) readAll(key []byte) ([]Value, error) {
+	blocks, err := c.index.ReadEntries(key, nil)
+	if len(blocks) == 0 || err != nil {
+		return nil, err
+	}
+
+	tombstones := c.index.TombstoneRange(key, nil)
+
+	var temp []Value
+	var values []Value
+	for i := range blocks {
+		var skip bool
+		for _, t := range tombstones {
+			// Should we skip this block because it contains points that have been deleted
+			if t.Min <= blocks[i].MinTime && t.Max >= blocks[i].MaxTime {
+				skip = true
+				break
+			}
+		}
+
+		if skip {
+			continue
+		}
+
+		temp, err = c.readBlock(&blocks[i], temp[:0])
+		if err != nil {
+			return nil, err
+		}
+
+		// Filter out any values that were deleted
+		for _, t := range tombstones {
+			temp = Values(temp).Exclude(t.Min, t.Max)
+		}
+
+		values = append(values, temp...)
+	}
+
+	return values, nil
+}
+
+// rename changes the path of the file in the engine path.  The object in the
+// cold store keeps its name.
+func (c *coldAccessor) rename(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := file.RenameFile(coldPointerPath(c.p), coldPointerPath(path)); err != nil {
+		return err
+	}
+	c.cache.evict(c.p)
+	c.p = path
+	return nil
+}
+
+func (c *coldAccessor) path() string {
+	c.mu.RLock()
+	path := c.p
+	c.mu.RUnlock()
+	return path
+}
+
+func (c *coldAccessor) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.obj == nil {
+		return nil
+	}
+
+	c.cache.evict(c.p)
+	err := c.obj.Close()
+	c.obj = nil
+	return err
+}
+
+// remove removes the object from the cold store and the pointer to it.
+func (c *coldAccessor) remove() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.store.Remove(c.name); err != nil {
+		return err
+	}
+	if err := os.Remove(coldPointerPath(c.p)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// copyTo writes the contents of the object to a new file at path.
+func (c *coldAccessor) copyTo(path string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.obj == nil {
+		return ErrTSMClosed
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, io.NewSectionReader(c.obj, 0, c.obj.Size())); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// free is a no-op since the blocks are only held by the block cache.
+func (c *coldAccessor) free() error {
+	return nil
+}
@@ -118,10 +118,14 @@ type indirectIndex struct {
 
 	// When we have identified the correct position in the index for a given
 	// key, we could perform another binary search or a linear scan.  This
-	// should be fast as well since each index entry is 28 bytes and all
-	// contiguous in memory.  The current implementation uses a linear scan since the
+	// should be fast as well since each index entry is 72 bytes (28 bytes in
+	// version 1 files) and all contiguous in memory.  The current implementation uses a linear scan since the
 	// number of block entries is expected to be < 100 per key.
 
+	// version is the version of the TSM file the index belongs to.  It determines
+	// the size of the index entries.
+	version byte
+
 	// b is the underlying index byte slice.  This could be a copy on the heap or an MMAP
 	// slice reference
 	b faultBuffer
@@ -151,6 +155,7 @@ type indirectIndex struct {
 // NewIndirectIndex returns a new indirect index.
 func NewIndirectIndex() *indirectIndex {
 	return &indirectIndex{
+		version:          Version,
 		tombstones:       make(map[uint32][]TimeRange),
 		prefixTombstones: newPrefixTree(),
 	}
@@ -172,7 +177,7 @@ func (d *indirectIndex) ReadEntries(key []byte, entries []IndexEntry) ([]IndexEn
 		return nil, nil
 	}
 
-	entries, err := readEntries(d.b.access(iter.EntryOffset(&d.b), 0), entries)
+	entries, err := readEntries(d.b.access(iter.EntryOffset(&d.b), 0), entries, d.entrySize())
 	if err != nil {
 		return nil, err
 	}
@@ -340,7 +345,7 @@ func (d *indirectIndex) DeleteRange(keys [][]byte, minTime, maxTime int64) bool
 		}
 
 		entryOffset := iter.EntryOffset(&d.b)
-		entries, err = readEntriesTimes(d.b.access(entryOffset, 0), entries)
+		entries, err = readEntriesTimes(d.b.access(entryOffset, 0), entries, d.entrySize())
 		if err != nil {
 			// If we have an error reading the entries for a key, we should just pretend
 			// the whole key is deleted. Maybe a better idea is to report this up somehow
@@ -403,7 +408,7 @@ func (d *indirectIndex) DeleteRange(keys [][]byte, minTime, maxTime int64) bool
 		// rare and only during concurrent deletes to the same key. We could make
 		// a copy of the entries before getting here, but that penalizes the common
 		// no-concurrent case.
-		entries, err = readEntriesTimes(d.b.access(p.EntryOffset, 0), entries)
+		entries, err = readEntriesTimes(d.b.access(p.EntryOffset, 0), entries, d.entrySize())
 		if err != nil {
 			// If we have an error reading the entries for a key, we should just pretend
 			// the whole key is deleted. Maybe a better idea is to report this up somehow
@@ -488,7 +493,7 @@ func (d *indirectIndex) DeletePrefix(prefix []byte, minTime, maxTime int64, dead
 		}
 
 		entryOffset := iter.EntryOffset(&d.b)
-		entries, err = readEntriesTimes(d.b.access(entryOffset, 0), entries)
+		entries, err = readEntriesTimes(d.b.access(entryOffset, 0), entries, d.entrySize())
 		if err != nil {
 			// If we have an error reading the entries for a key, we should just pretend
 			// the whole key is deleted. Maybe a better idea is to report this up somehow
@@ -669,6 +674,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 	// field.
 	var i uint32
 	var ro readerOffsets
+	entrySize := uint32(d.entrySize())
 
 	iMax := uint32(len(b))
 	if iMax > math.MaxInt32 {
@@ -711,7 +717,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			minTime = minT
 		}
 
-		i += (count - 1) * indexEntrySize
+		i += (count - 1) * entrySize
 
 		// Find the max time for the block
 		if i+16 >= iMax {
@@ -722,7 +728,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			maxTime = maxT
 		}
 
-		i += indexEntrySize
+		i += entrySize
 	}
 
 	ro.Done()
@@ -759,7 +765,13 @@ func readKey(b []byte) (key []byte) {
 	return b[2 : 2+size]
 }
 
-func readEntries(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
+// entrySize returns the size in bytes of the index entries.
+func (d *indirectIndex) entrySize() int {
+	return indexEntrySizeForVersion(d.version)
+}
+
+// readEntries reads the entries of size entrySize at the provided buffer.
+func readEntries(b []byte, entries []IndexEntry, entrySize int) ([]IndexEntry, error) {
 	if len(b) < indexTypeSize+indexCountSize {
 		return entries[:0], errors.New("readEntries: data too short for headers")
 	}
@@ -773,10 +785,10 @@ func readEntries(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
 	b = b[indexTypeSize+indexCountSize:]
 
 	for i := range entries {
-		if err := entries[i].UnmarshalBinary(b); err != nil {
+		if err := entries[i].unmarshalBinary(b, entrySize); err != nil {
 			return entries[:0], err
 		}
-		b = b[indexEntrySize:]
+		b = b[entrySize:]
 	}
 
 	return entries, nil
@@ -784,7 +796,7 @@ func readEntries(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
 
 // readEntriesTimes is a helper function to read entries at the provided buffer but
 // only reading in the min and max times.
-func readEntriesTimes(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
+func readEntriesTimes(b []byte, entries []IndexEntry, entrySize int) ([]IndexEntry, error) {
 	if len(b) < indexTypeSize+indexCountSize {
 		return entries[:0], errors.New("readEntries: data too short for headers")
 	}
@@ -798,12 +810,12 @@ func readEntriesTimes(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
 	b = b[indexTypeSize+indexCountSize:]
 
 	for i := range entries {
-		if len(b) < indexEntrySize {
+		if len(b) < entrySize {
 			return entries[:0], errors.New("readEntries: stream too short for entry")
 		}
 		entries[i].MinTime = int64(binary.BigEndian.Uint64(b[0:8]))
 		entries[i].MaxTime = int64(binary.BigEndian.Uint64(b[8:16]))
-		b = b[indexEntrySize:]
+		b = b[entrySize:]
 	}
 
 	return entries, nil
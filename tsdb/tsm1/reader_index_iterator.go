@@ -96,11 +96,12 @@ func (t *TSMIndexIterator) Type() byte {
 	return t.typ
 }
 
-// Entries reports the current list of entries.
+// Entries reports the current list of entries.  The entries of version 2 files
+// include the statistics of the values in each block.
 func (t *TSMIndexIterator) Entries() []IndexEntry {
 	if len(t.entries) == 0 {
 		buf := t.b.access(t.eoffset, 0)
-		t.entries, t.err = readEntries(buf, t.entries)
+		t.entries, t.err = readEntries(buf, t.entries, t.d.entrySize())
 	}
 	if t.err != nil {
 		return nil
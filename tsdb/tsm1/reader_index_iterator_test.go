@@ -30,23 +30,23 @@ func TestIndirectIndexIterator(t *testing.T) {
 	checkEqual(t, iter.Key(), []byte("cpu1"))
 	checkEqual(t, iter.Type(), BlockInteger)
 	checkEqual(t, iter.Entries(), []IndexEntry{
-		{0, 10, 10, 20},
-		{10, 20, 10, 20},
+		{0, 10, 10, 20, BlockStats{}},
+		{10, 20, 10, 20, BlockStats{}},
 	})
 	checkEqual(t, iter.Next(), true)
 	checkEqual(t, iter.Peek(), []byte("mem"))
 	checkEqual(t, iter.Key(), []byte("cpu2"))
 	checkEqual(t, iter.Type(), BlockInteger)
 	checkEqual(t, iter.Entries(), []IndexEntry{
-		{0, 10, 10, 20},
-		{10, 20, 10, 20},
+		{0, 10, 10, 20, BlockStats{}},
+		{10, 20, 10, 20, BlockStats{}},
 	})
 	checkEqual(t, iter.Next(), true)
 	checkEqual(t, iter.Peek(), []byte(nil))
 	checkEqual(t, iter.Key(), []byte("mem"))
 	checkEqual(t, iter.Type(), BlockInteger)
 	checkEqual(t, iter.Entries(), []IndexEntry{
-		{0, 10, 10, 20},
+		{0, 10, 10, 20, BlockStats{}},
 	})
 	checkEqual(t, iter.Next(), false)
 	checkEqual(t, iter.Err(), error(nil))
@@ -59,15 +59,15 @@ func TestIndirectIndexIterator(t *testing.T) {
 	checkEqual(t, iter.Key(), []byte("cpu1"))
 	checkEqual(t, iter.Type(), BlockInteger)
 	checkEqual(t, iter.Entries(), []IndexEntry{
-		{0, 10, 10, 20},
-		{10, 20, 10, 20},
+		{0, 10, 10, 20, BlockStats{}},
+		{10, 20, 10, 20, BlockStats{}},
 	})
 	checkEqual(t, iter.Next(), true)
 	checkEqual(t, iter.Peek(), []byte(nil))
 	checkEqual(t, iter.Key(), []byte("mem"))
 	checkEqual(t, iter.Type(), BlockInteger)
 	checkEqual(t, iter.Entries(), []IndexEntry{
-		{0, 10, 10, 20},
+		{0, 10, 10, 20, BlockStats{}},
 	})
 	checkEqual(t, iter.Next(), false)
 	checkEqual(t, iter.Err(), error(nil))
@@ -79,7 +79,7 @@ func TestIndirectIndexIterator(t *testing.T) {
 	checkEqual(t, iter.Key(), []byte("mem"))
 	checkEqual(t, iter.Type(), BlockInteger)
 	checkEqual(t, iter.Entries(), []IndexEntry{
-		{0, 10, 10, 20},
+		{0, 10, 10, 20, BlockStats{}},
 	})
 	checkEqual(t, iter.Next(), false)
 	checkEqual(t, iter.Err(), error(nil))
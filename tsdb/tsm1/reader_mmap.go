@@ -31,12 +31,11 @@ func (m *mmapAccessor) init() (*indirectIndex, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if err := verifyVersion(m.f); err != nil {
+	version, err := verifyVersion(m.f)
+	if err != nil {
 		return nil, err
 	}
 
-	var err error
-
 	if _, err := m.f.Seek(0, 0); err != nil {
 		return nil, err
 	}
@@ -70,6 +69,7 @@ func (m *mmapAccessor) init() (*indirectIndex, error) {
 	}
 
 	m.index = NewIndirectIndex()
+	m.index.version = version
 	if err := m.index.UnmarshalBinary(m.b[indexStart:indexOfsPos]); err != nil {
 		return nil, err
 	}
@@ -1,6 +1,8 @@
 package tsm1
 
 import (
+	"encoding/binary"
+	"hash/crc32"
 	"io/ioutil"
 	"math"
 	"os"
@@ -21,6 +23,70 @@ func fatalIfErr(t testing.TB, msg string, err error) {
 	}
 }
 
+func TestTSMReader_Version1(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+	f := mustTempFile(dir)
+
+	// Write a version 1 file, whose index entries have no block statistics.
+	values := []Value{NewValue(1, 1.5), NewValue(2, 2.5)}
+	block, err := Values(values).Encode(nil)
+	fatalIfErr(t, "encoding block", err)
+
+	buf := []byte{0, 0, 0, 0, 1}
+	binary.BigEndian.PutUint32(buf, MagicNumber)
+	buf = append(buf, make([]byte, 4)...)
+	binary.BigEndian.PutUint32(buf[len(buf)-4:], crc32.ChecksumIEEE(block))
+	buf = append(buf, block...)
+
+	indexOfs := len(buf)
+	key := []byte("cpu")
+	buf = append(buf, 0, byte(len(key)))
+	buf = append(buf, key...)
+	buf = append(buf, BlockFloat64, 0, 1)
+	entry := make([]byte, indexEntrySizeV1)
+	binary.BigEndian.PutUint64(entry[0:8], 1)
+	binary.BigEndian.PutUint64(entry[8:16], 2)
+	binary.BigEndian.PutUint64(entry[16:24], 5)
+	binary.BigEndian.PutUint32(entry[24:28], uint32(4+len(block)))
+	buf = append(buf, entry...)
+	buf = append(buf, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(buf[len(buf)-8:], uint64(indexOfs))
+
+	_, err = f.Write(buf)
+	fatalIfErr(t, "writing file", err)
+
+	r, err := NewTSMReader(f)
+	fatalIfErr(t, "creating reader", err)
+	defer r.Close()
+
+	readValues, err := r.ReadAll(key)
+	fatalIfErr(t, "reading values", err)
+	if got, exp := len(readValues), len(values); got != exp {
+		t.Fatalf("value length mismatch: got %v, exp %v", got, exp)
+	}
+	for i, v := range values {
+		if got, exp := readValues[i].Value(), v.Value(); got != exp {
+			t.Fatalf("value %d mismatch: got %v, exp %v", i, got, exp)
+		}
+	}
+
+	iter := r.Iterator(nil)
+	if !iter.Next() {
+		t.Fatalf("expected a key: %v", iter.Err())
+	}
+	entries := iter.Entries()
+	if got, exp := len(entries), 1; got != exp {
+		t.Fatalf("entries length mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := entries[0], (IndexEntry{MinTime: 1, MaxTime: 2, Offset: 5, Size: uint32(4 + len(block))}); got != exp {
+		t.Fatalf("entry mismatch: got %v, exp %v", got, exp)
+	}
+	if entries[0].Stats.HasStats() {
+		t.Fatalf("unexpected stats for version 1 file: %+v", entries[0].Stats)
+	}
+}
+
 func TestTSMReader_Type(t *testing.T) {
 	dir := mustTempDir()
 	defer os.RemoveAll(dir)
@@ -35,6 +35,7 @@ type Log interface {
 
 	WriteMulti(values map[string][]Value) (int, error)
 	DeleteRange(keys [][]byte, min, max int64) (int, error)
+	DeleteBucketRange(name []byte, keys [][]byte, min, max int64) (int, error)
 
 	CloseSegment() error
 	ClosedSegments() ([]string, error)
@@ -73,6 +74,11 @@ const (
 
 	// DeleteRangeWALEntryType indicates a delete range entry.
 	DeleteRangeWALEntryType WalEntryType = 0x03
+
+	// DeleteBucketRangeWALEntryType indicates a delete bucket range entry.  Versions
+	// of influxd without replication can not read segments with these entries, so
+	// they are only written by a WAL with replication enabled.
+	DeleteBucketRangeWALEntryType WalEntryType = 0x04
 )
 
 var (
@@ -120,6 +126,13 @@ type WAL struct {
 	// SegmentSize is the file size at which a segment file will be rotated
 	SegmentSize int
 
+	// retentionSize is the total size of the removed segments kept in the archive
+	// directory for replicas.  A value of 0 disables the archive.
+	retentionSize int64
+
+	// replication enables the entries replicas need to apply the WAL.
+	replication bool
+
 	tracker *walTracker
 	limiter limiter.Fixed
 }
@@ -154,6 +167,25 @@ func (l *WAL) WithFsyncDelay(delay time.Duration) {
 	l.syncDelay = delay
 }
 
+// WithRetentionSize sets the total size of the removed segments kept for replicas
+// and should be called before the WAL is opened.
+func (l *WAL) WithRetentionSize(size int64) {
+	l.retentionSize = size
+}
+
+// WithReplication enables the entries replicas need to apply the WAL, such as
+// DeleteBucketRangeWALEntry, and should be called before the WAL is opened.
+// Versions of influxd without replication can not read the segments written
+// once it is enabled.
+func (l *WAL) WithReplication(enabled bool) {
+	l.replication = enabled
+}
+
+// Replication returns true if the entries replicas need are written.
+func (l *WAL) Replication() bool {
+	return l.replication
+}
+
 // WithLogger sets the WAL's logger.
 func (l *WAL) WithLogger(log *zap.Logger) {
 	l.logger = log.With(zap.String("service", "wal"))
@@ -236,6 +268,21 @@ func (l *WAL) Open() error {
 	}
 	l.tracker.SetOldSegmentSize(uint64(totalOldDiskSize))
 
+	// Never reuse the IDs of archived segments, replicas might have read them.
+	archived, err := segmentFileNames(l.archivePath())
+	if err != nil {
+		return err
+	}
+	if len(archived) > 0 {
+		id, err := idFromFileName(archived[len(archived)-1])
+		if err != nil {
+			return err
+		}
+		if id > l.currentSegmentID {
+			l.currentSegmentID = id
+		}
+	}
+
 	l.closing = make(chan struct{})
 
 	return nil
@@ -351,6 +398,11 @@ func (l *WAL) ClosedSegments() ([]string, error) {
 func (l *WAL) Remove(files []string) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	if l.retentionSize > 0 {
+		if err := l.archive(files); err != nil {
+			l.logger.Info("Error archiving WAL files", zap.Error(err))
+		}
+	}
 	for _, fn := range files {
 		l.traceLogger.Info("Removing WAL file", zap.String("path", fn))
 		os.RemoveAll(fn)
@@ -518,6 +570,28 @@ func (l *WAL) DeleteRange(keys [][]byte, min, max int64) (int, error) {
 	return id, nil
 }
 
+// DeleteBucketRange deletes the data of the keys starting with name within the
+// given time range, returning the segment ID for the operation.  keys are the
+// keys starting with name in the cache; the deletion is written as a delete
+// range of keys unless replication is enabled.
+func (l *WAL) DeleteBucketRange(name []byte, keys [][]byte, min, max int64) (int, error) {
+	if !l.replication {
+		return l.DeleteRange(keys, min, max)
+	}
+
+	entry := &DeleteBucketRangeWALEntry{
+		Name: name,
+		Min:  min,
+		Max:  max,
+	}
+
+	id, err := l.writeToLog(entry)
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
 // Close will finish any flush that is currently in progress and close file handles.
 func (l *WAL) Close() error {
 	l.mu.Lock()
@@ -1109,6 +1183,57 @@ func (w *DeleteRangeWALEntry) Type() WalEntryType {
 	return DeleteRangeWALEntryType
 }
 
+// DeleteBucketRangeWALEntry represents the deletion of the data of a bucket.
+type DeleteBucketRangeWALEntry struct {
+	Name     []byte
+	Min, Max int64
+}
+
+// MarshalBinary returns a binary representation of the entry in a new byte slice.
+func (w *DeleteBucketRangeWALEntry) MarshalBinary() ([]byte, error) {
+	b := make([]byte, w.MarshalSize())
+	return w.Encode(b)
+}
+
+// UnmarshalBinary deserializes the byte slice into w.
+func (w *DeleteBucketRangeWALEntry) UnmarshalBinary(b []byte) error {
+	if len(b) < 16 {
+		return ErrWALCorrupt
+	}
+
+	w.Min = int64(binary.BigEndian.Uint64(b[:8]))
+	w.Max = int64(binary.BigEndian.Uint64(b[8:16]))
+
+	// b originates from a pool. Copy what needs to be retained.
+	w.Name = make([]byte, len(b)-16)
+	copy(w.Name, b[16:])
+	return nil
+}
+
+func (w *DeleteBucketRangeWALEntry) MarshalSize() int {
+	return 16 + len(w.Name)
+}
+
+// Encode converts the DeleteBucketRangeWALEntry into a byte slice, appending to b.
+func (w *DeleteBucketRangeWALEntry) Encode(b []byte) ([]byte, error) {
+	sz := w.MarshalSize()
+
+	if len(b) < sz {
+		b = make([]byte, sz)
+	}
+
+	binary.BigEndian.PutUint64(b[:8], uint64(w.Min))
+	binary.BigEndian.PutUint64(b[8:16], uint64(w.Max))
+	copy(b[16:], w.Name)
+
+	return b[:sz], nil
+}
+
+// Type returns DeleteBucketRangeWALEntryType.
+func (w *DeleteBucketRangeWALEntry) Type() WalEntryType {
+	return DeleteBucketRangeWALEntryType
+}
+
 // WALSegmentWriter writes WAL segments.
 type WALSegmentWriter struct {
 	bw   *bufio.Writer
@@ -1256,6 +1381,8 @@ func (r *WALSegmentReader) Next() bool {
 		r.entry = &DeleteWALEntry{}
 	case DeleteRangeWALEntryType:
 		r.entry = &DeleteRangeWALEntry{}
+	case DeleteBucketRangeWALEntryType:
+		r.entry = &DeleteBucketRangeWALEntry{}
 	default:
 		r.err = fmt.Errorf("unknown wal entry type: %v", entryType)
 		return true
@@ -1323,6 +1450,9 @@ func (w NopWAL) DiskSizeBytes() int64     { return 0 }
 
 func (w NopWAL) WriteMulti(values map[string][]Value) (int, error)      { return 0, nil }
 func (w NopWAL) DeleteRange(keys [][]byte, min, max int64) (int, error) { return 0, nil }
+func (w NopWAL) DeleteBucketRange(name []byte, keys [][]byte, min, max int64) (int, error) {
+	return 0, nil
+}
 
 func (w NopWAL) CloseSegment() error               { return nil }
 func (w NopWAL) ClosedSegments() ([]string, error) { return nil, nil }
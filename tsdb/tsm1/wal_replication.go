@@ -0,0 +1,235 @@
+package tsm1
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// WALArchiveDirectoryName is the name of the directory of the WAL where removed
+// segments are kept for replicas.
+const WALArchiveDirectoryName = "archive"
+
+// ErrWALSegmentNotFound is returned when reading a segment that has been removed
+// from the WAL and its archive, or that has not been written yet.
+var ErrWALSegmentNotFound = errors.New("WAL segment not found")
+
+// WALSegment is a segment of the WAL opened to be read by a replica.
+type WALSegment struct {
+	// ID is the ID of the segment.
+	ID int
+
+	// Closed is true if no more entries will be written to the segment.
+	Closed bool
+
+	// Size is the size of the segment when it was opened.
+	Size int64
+
+	f *os.File
+}
+
+// NewReader returns a reader of the segment starting at offset.  Entries are only
+// flushed to the segment once they are synced, so the last entry read might be
+// incomplete if the segment is not closed.
+func (s *WALSegment) NewReader(offset int64) io.Reader {
+	if s.f == nil || offset >= s.Size {
+		return strings.NewReader("")
+	}
+	return io.NewSectionReader(s.f, offset, s.Size-offset)
+}
+
+// Close closes the segment.
+func (s *WALSegment) Close() error {
+	if s.f == nil {
+		return nil
+	}
+	return s.f.Close()
+}
+
+func (l *WAL) archivePath() string {
+	return filepath.Join(l.path, WALArchiveDirectoryName)
+}
+
+// archive links files in the archive directory and removes the oldest archived
+// segments above the retention size.  The most recent archived segment is always
+// kept so that segment IDs are not reused when the WAL is reopened.
+func (l *WAL) archive(files []string) error {
+	if err := os.MkdirAll(l.archivePath(), 0777); err != nil {
+		return err
+	}
+	for _, fn := range files {
+		dst := filepath.Join(l.archivePath(), filepath.Base(fn))
+		if err := os.Link(fn, dst); err != nil && !os.IsExist(err) {
+			return err
+		}
+	}
+
+	archived, err := segmentFileNames(l.archivePath())
+	if err != nil {
+		return err
+	}
+
+	sizes := make([]int64, len(archived))
+	var total int64
+	for i, fn := range archived {
+		stat, err := os.Stat(fn)
+		if err != nil {
+			return err
+		}
+		sizes[i] = stat.Size()
+		total += stat.Size()
+	}
+
+	for i := 0; i < len(archived)-1 && total > l.retentionSize; i++ {
+		l.traceLogger.Info("Removing archived WAL file", zap.String("path", archived[i]))
+		if err := os.Remove(archived[i]); err != nil {
+			return err
+		}
+		total -= sizes[i]
+	}
+	return nil
+}
+
+// segmentPaths returns the paths of the archived segments and of the segments of
+// the WAL indexed by ID.  Callers must hold a lock on the WAL.
+func (l *WAL) segmentPaths() (map[int]string, error) {
+	archived, err := segmentFileNames(l.archivePath())
+	if err != nil {
+		return nil, err
+	}
+	segments, err := segmentFileNames(l.path)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[int]string, len(archived)+len(segments))
+	for _, fn := range append(archived, segments...) {
+		id, err := idFromFileName(fn)
+		if err != nil {
+			return nil, err
+		}
+		paths[id] = fn
+	}
+	return paths, nil
+}
+
+// writingSegmentID returns the ID of the segment the next entry is written to.
+// Callers must hold a lock on the WAL.
+func (l *WAL) writingSegmentID() int {
+	if l.currentSegmentWriter == nil {
+		return l.currentSegmentID + 1
+	}
+	return l.currentSegmentID
+}
+
+// OpenSegment opens the segment with the given ID from the WAL or its archive.
+// ErrWALSegmentNotFound is returned if the segment has been removed or is newer
+// than the segment being written.  IDs of segments that were never written are
+// returned as empty segments.
+func (l *WAL) OpenSegment(id int) (*WALSegment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	writing := l.writingSegmentID()
+	if id > writing {
+		return nil, ErrWALSegmentNotFound
+	}
+
+	paths, err := l.segmentPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	segment := &WALSegment{ID: id, Closed: id < writing}
+	path, ok := paths[id]
+	if !ok {
+		// IDs between the oldest segment and the segment being written without a
+		// file were never written to.
+		oldest := writing
+		for other := range paths {
+			if other < oldest {
+				oldest = other
+			}
+		}
+		if id < oldest {
+			return nil, ErrWALSegmentNotFound
+		}
+		return segment, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrWALSegmentNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	segment.Size = stat.Size()
+	segment.f = f
+	return segment, nil
+}
+
+// FirstSegmentID returns the ID of the oldest segment of the WAL, not including the
+// archived segments.  All the entries of older segments have been written to TSM
+// files.
+func (l *WAL) FirstSegmentID() (int, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	segments, err := segmentFileNames(l.path)
+	if err != nil {
+		return 0, err
+	} else if len(segments) == 0 {
+		return l.writingSegmentID(), nil
+	}
+	return idFromFileName(segments[0])
+}
+
+// SizeFrom returns the number of bytes of the WAL and its archive written after
+// offset in the segment with the given ID.
+func (l *WAL) SizeFrom(id int, offset int64) (int64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	paths, err := l.segmentPaths()
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]int, 0, len(paths))
+	for other := range paths {
+		if other >= id {
+			ids = append(ids, other)
+		}
+	}
+	sort.Ints(ids)
+
+	var size int64
+	for _, other := range ids {
+		stat, err := os.Stat(paths[other])
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		size += stat.Size()
+		if other == id {
+			size -= offset
+		}
+	}
+
+	if size < 0 {
+		size = 0
+	}
+	return size, nil
+}
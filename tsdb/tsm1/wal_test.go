@@ -3,6 +3,7 @@ package tsm1_test
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
@@ -778,6 +779,191 @@ func TestWriteWALSegment_UnmarshalBinary_DeleteRangeWALCorrupt(t *testing.T) {
 	}
 }
 
+func TestDeleteBucketRangeWALEntry_UnmarshalBinary(t *testing.T) {
+	w := &tsm1.DeleteBucketRangeWALEntry{
+		Name: []byte("0000000000000001"),
+		Min:  -1,
+		Max:  2,
+	}
+
+	b, err := w.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if got, exp := len(b), w.MarshalSize(); got != exp {
+		t.Fatalf("size mismatch: got %v, exp %v", got, exp)
+	}
+
+	out := &tsm1.DeleteBucketRangeWALEntry{}
+	if err := out.UnmarshalBinary(b); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if !reflect.DeepEqual(w, out) {
+		t.Fatalf("entry mismatch: got %v, exp %v", out, w)
+	}
+
+	// Test every possible truncation of the entry
+	for i := 0; i < 16; i++ {
+		truncated := make([]byte, i)
+		copy(truncated, b[:i])
+		if err := out.UnmarshalBinary(truncated); err != tsm1.ErrWALCorrupt {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestWAL_DeleteBucketRange_NoReplication(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	w := tsm1.NewWAL(dir)
+	if err := w.Open(); err != nil {
+		t.Fatalf("error opening WAL: %v", err)
+	}
+	defer w.Close()
+
+	keys := [][]byte{[]byte("cpu,host=A#!~#value")}
+	if _, err := w.DeleteBucketRange([]byte("cpu"), keys, 0, 10); err != nil {
+		t.Fatalf("error writing delete: %v", err)
+	}
+	if err := w.CloseSegment(); err != nil {
+		t.Fatalf("error closing segment: %v", err)
+	}
+
+	files, err := w.ClosedSegments()
+	if err != nil {
+		t.Fatalf("error getting closed segments: %v", err)
+	} else if got, exp := len(files), 1; got != exp {
+		t.Fatalf("closed segment length mismatch: got %v, exp %v", got, exp)
+	}
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatalf("error opening segment: %v", err)
+	}
+	defer f.Close()
+
+	// Without replication the delete is written as an entry older versions
+	// can read.
+	r := tsm1.NewWALSegmentReader(f)
+	if !r.Next() {
+		t.Fatalf("expected next, got false")
+	}
+	we, err := r.Read()
+	if err != nil {
+		fatal(t, "read entry", err)
+	}
+	e, ok := we.(*tsm1.DeleteRangeWALEntry)
+	if !ok {
+		t.Fatalf("expected DeleteRangeWALEntry: got %#v", we)
+	}
+	if got, exp := len(e.Keys), 1; got != exp {
+		t.Fatalf("key length mismatch: got %v, exp %v", got, exp)
+	} else if got, exp := string(e.Keys[0]), string(keys[0]); got != exp {
+		t.Fatalf("key mismatch: got %v, exp %v", got, exp)
+	} else if e.Min != 0 || e.Max != 10 {
+		t.Fatalf("range mismatch: got %d-%d, exp 0-10", e.Min, e.Max)
+	}
+}
+
+func TestWAL_OpenSegment(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	w := tsm1.NewWAL(dir)
+	w.WithRetentionSize(1 << 20)
+	w.WithReplication(true)
+	if err := w.Open(); err != nil {
+		t.Fatalf("error opening WAL: %v", err)
+	}
+	defer w.Close()
+
+	values := map[string][]tsm1.Value{
+		"cpu,host=A#!~#value": []tsm1.Value{
+			tsm1.NewValue(1, 1.1),
+		},
+	}
+	if _, err := w.WriteMulti(values); err != nil {
+		t.Fatalf("error writing points: %v", err)
+	}
+	if _, err := w.DeleteBucketRange([]byte("cpu"), [][]byte{[]byte("cpu,host=A#!~#value")}, 0, 10); err != nil {
+		t.Fatalf("error writing delete: %v", err)
+	}
+	if err := w.CloseSegment(); err != nil {
+		t.Fatalf("error closing segment: %v", err)
+	}
+
+	files, err := w.ClosedSegments()
+	if err != nil {
+		t.Fatalf("error getting closed segments: %v", err)
+	} else if got, exp := len(files), 1; got != exp {
+		t.Fatalf("closed segment length mismatch: got %v, exp %v", got, exp)
+	}
+	if err := w.Remove(files); err != nil {
+		t.Fatalf("error removing segments: %v", err)
+	}
+
+	first, err := w.FirstSegmentID()
+	if err != nil {
+		t.Fatalf("error getting first segment: %v", err)
+	} else if got, exp := first, 2; got != exp {
+		t.Fatalf("first segment mismatch: got %v, exp %v", got, exp)
+	}
+
+	// The removed segment is read from the archive.
+	segment, err := w.OpenSegment(1)
+	if err != nil {
+		t.Fatalf("error opening segment: %v", err)
+	}
+	defer segment.Close()
+	if !segment.Closed {
+		t.Fatalf("expected segment to be closed")
+	}
+
+	size, err := w.SizeFrom(1, 0)
+	if err != nil {
+		t.Fatalf("error getting size: %v", err)
+	} else if got, exp := size, segment.Size; got != exp {
+		t.Fatalf("size mismatch: got %v, exp %v", got, exp)
+	}
+
+	r := tsm1.NewWALSegmentReader(ioutil.NopCloser(segment.NewReader(0)))
+	var entries []tsm1.WALEntry
+	for r.Next() {
+		entry, err := r.Read()
+		if err != nil {
+			t.Fatalf("error reading entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	if got, exp := len(entries), 2; got != exp {
+		t.Fatalf("entries length mismatch: got %v, exp %v", got, exp)
+	}
+	if e, ok := entries[0].(*tsm1.WriteWALEntry); !ok || !reflect.DeepEqual(e.Values, values) {
+		t.Fatalf("write entry mismatch: got %v", entries[0])
+	}
+	if e, ok := entries[1].(*tsm1.DeleteBucketRangeWALEntry); !ok || string(e.Name) != "cpu" || e.Min != 0 || e.Max != 10 {
+		t.Fatalf("delete entry mismatch: got %v", entries[1])
+	}
+
+	// The segment being written is empty and open.
+	segment, err = w.OpenSegment(2)
+	if err != nil {
+		t.Fatalf("error opening segment: %v", err)
+	}
+	defer segment.Close()
+	if segment.Closed || segment.Size != 0 {
+		t.Fatalf("unexpected segment: closed %v, size %v", segment.Closed, segment.Size)
+	}
+
+	if _, err := w.OpenSegment(3); err != tsm1.ErrWALSegmentNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.OpenSegment(0); err != tsm1.ErrWALSegmentNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func BenchmarkWALSegmentWriter(b *testing.B) {
 	points := map[string][]tsm1.Value{}
 	for i := 0; i < 5000; i++ {
@@ -36,7 +36,8 @@ composed of a sequence of index entries ordered lexicographically by key and
 then by time.  Each index entry starts with a key length and key followed by a
 count of the number of blocks in the file.  Each block entry is composed of
 the min and max time for the block, the offset into the file where the block
-is located and the the size of the block.
+is located, the size of the block and, since version 2, statistics of the
+values in the block.
 
 The index structure can provide efficient access to all blocks as well as the
 ability to determine the cost associated with acessing a given key.  Given a key
@@ -52,6 +53,17 @@ file, we can use the size to determine how much to read in a given IO.
 │ 2 bytes │ N bytes │1 byte│2 bytes│ 8 bytes │ 8 bytes │8 bytes │4 bytes │   │
 └─────────┴─────────┴──────┴───────┴─────────┴─────────┴────────┴────────┴───┘
 
+The block statistics follow the size of each block entry.  Min, max, sum, first
+and last hold the bits of values of the block's type.  Blocks of strings only
+record the number of values.
+
+┌──────────────────────────────────────────────────────┐
+│                     Block Stats                      │
+├───────┬─────────┬─────────┬─────────┬────────┬───────┤
+│ Count │   Min   │   Max   │   Sum   │ First  │ Last  │
+│4 bytes│ 8 bytes │ 8 bytes │ 8 bytes │8 bytes │8 bytes│
+└───────┴─────────┴─────────┴─────────┴────────┴───────┘
+
 The last section is the footer that stores the offset of the start of the index.
 
 ┌─────────┐
@@ -82,11 +94,18 @@ const (
 	// identify the file as a tsm1 formatted file
 	MagicNumber uint32 = 0x16D116D1
 
-	// Version indicates the version of the TSM file format.
-	Version byte = 1
+	// Version indicates the version of the TSM file format.  Version 2 adds
+	// block statistics to the index entries.
+	Version byte = 2
 
 	// Size in bytes of an index entry
-	indexEntrySize = 28
+	indexEntrySize = indexEntrySizeV1 + blockStatsSize
+
+	// Size in bytes of an index entry in a version 1 file
+	indexEntrySizeV1 = 28
+
+	// Size in bytes of the block statistics of an index entry
+	blockStatsSize = 44
 
 	// Size in bytes used to store the count of index entries for a key
 	indexCountSize = 2
@@ -137,6 +156,11 @@ type TSMWriter interface {
 	// timestamp values are used as the minimum and maximum values for the index entry.
 	WriteBlock(key []byte, minTime, maxTime int64, block []byte) error
 
+	// WriteBlockWithStats is like WriteBlock but records stats in the index entry
+	// instead of decoding block to compute them.  The caller is responsible for
+	// ensuring the stats describe the values in block.
+	WriteBlockWithStats(key []byte, minTime, maxTime int64, block []byte, stats BlockStats) error
+
 	// WriteIndex finishes the TSM write streams and writes the index.
 	WriteIndex() error
 
@@ -160,6 +184,9 @@ type IndexWriter interface {
 	// Add records a new block entry for a key in the index.
 	Add(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32)
 
+	// AddWithStats records a new block entry with the statistics of its values.
+	AddWithStats(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32, stats BlockStats)
+
 	// Entries returns all index entries for a key.
 	Entries(key []byte) []IndexEntry
 
@@ -190,17 +217,32 @@ type IndexEntry struct {
 
 	// The size in bytes of the block in the file.
 	Size uint32
+
+	// Stats contains statistics of the values in the block.  They are not
+	// available for blocks of version 1 files.
+	Stats BlockStats
 }
 
 // UnmarshalBinary decodes an IndexEntry from a byte slice.
 func (e *IndexEntry) UnmarshalBinary(b []byte) error {
-	if len(b) < indexEntrySize {
-		return fmt.Errorf("unmarshalBinary: short buf: %v < %v", len(b), indexEntrySize)
+	return e.unmarshalBinary(b, indexEntrySize)
+}
+
+// unmarshalBinary decodes an IndexEntry of the given encoded size, which
+// depends on the version of the file.
+func (e *IndexEntry) unmarshalBinary(b []byte, size int) error {
+	if len(b) < size {
+		return fmt.Errorf("unmarshalBinary: short buf: %v < %v", len(b), size)
 	}
 	e.MinTime = int64(binary.BigEndian.Uint64(b[:8]))
 	e.MaxTime = int64(binary.BigEndian.Uint64(b[8:16]))
 	e.Offset = int64(binary.BigEndian.Uint64(b[16:24]))
 	e.Size = binary.BigEndian.Uint32(b[24:28])
+	if size > indexEntrySizeV1 {
+		e.Stats.unmarshalBinary(b[indexEntrySizeV1:])
+	} else {
+		e.Stats = BlockStats{}
+	}
 	return nil
 }
 
@@ -219,6 +261,7 @@ func (e *IndexEntry) AppendTo(b []byte) []byte {
 	binary.BigEndian.PutUint64(b[8:16], uint64(e.MaxTime))
 	binary.BigEndian.PutUint64(b[16:24], uint64(e.Offset))
 	binary.BigEndian.PutUint32(b[24:28], uint32(e.Size))
+	e.Stats.appendTo(b[indexEntrySizeV1:])
 
 	return b
 }
@@ -313,6 +356,10 @@ func (a *indexEntries) WriteTo(w io.Writer) (total int64, err error) {
 }
 
 func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32) {
+	d.AddWithStats(key, blockType, minTime, maxTime, offset, size, BlockStats{})
+}
+
+func (d *directIndex) AddWithStats(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32, stats BlockStats) {
 	// Is this the first block being added?
 	if len(d.key) == 0 {
 		// size of the key stored in the index
@@ -330,6 +377,7 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 			MaxTime: maxTime,
 			Offset:  offset,
 			Size:    size,
+			Stats:   stats,
 		})
 
 		// size of the encoded index entry
@@ -347,6 +395,7 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 			MaxTime: maxTime,
 			Offset:  offset,
 			Size:    size,
+			Stats:   stats,
 		})
 
 		// size of the encoded index entry
@@ -369,6 +418,7 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 			MaxTime: maxTime,
 			Offset:  offset,
 			Size:    size,
+			Stats:   stats,
 		})
 
 		// size of the encoded index entry
@@ -688,7 +738,7 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 	n += len(checksum)
 
 	// Record this block in index
-	t.index.Add(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n))
+	t.index.AddWithStats(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n), newValuesBlockStats(values))
 
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
@@ -717,6 +767,25 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 		return nil
 	}
 
+	stats, err := newBlockStats(block)
+	if err != nil {
+		return err
+	}
+	return t.WriteBlockWithStats(key, minTime, maxTime, block, stats)
+}
+
+// WriteBlockWithStats writes block for the given key and time range to the TSM file
+// and records stats in its index entry.  It behaves like WriteBlock otherwise.
+func (t *tsmWriter) WriteBlockWithStats(key []byte, minTime, maxTime int64, block []byte, stats BlockStats) error {
+	if len(key) > maxKeyLength {
+		return ErrMaxKeyLengthExceeded
+	}
+
+	// Nothing to write
+	if len(block) == 0 {
+		return nil
+	}
+
 	blockType, err := BlockType(block)
 	if err != nil {
 		return err
@@ -744,7 +813,7 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 	n += len(checksum)
 
 	// Record this block in index
-	t.index.Add(key, blockType, minTime, maxTime, t.n, uint32(n))
+	t.index.AddWithStats(key, blockType, minTime, maxTime, t.n, uint32(n), stats)
 
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
@@ -892,27 +961,36 @@ func (t *tsmWriter) Size() uint32 {
 }
 
 // verifyVersion verifies that the reader's bytes are a TSM byte
-// stream of the correct version (1)
-func verifyVersion(r io.ReadSeeker) error {
+// stream of a supported version (1 or 2) and returns the version.
+func verifyVersion(r io.ReadSeeker) (byte, error) {
 	_, err := r.Seek(0, 0)
 	if err != nil {
-		return fmt.Errorf("init: failed to seek: %v", err)
+		return 0, fmt.Errorf("init: failed to seek: %v", err)
 	}
 	var b [4]byte
 	_, err = io.ReadFull(r, b[:])
 	if err != nil {
-		return fmt.Errorf("init: error reading magic number of file: %v", err)
+		return 0, fmt.Errorf("init: error reading magic number of file: %v", err)
 	}
 	if binary.BigEndian.Uint32(b[:]) != MagicNumber {
-		return fmt.Errorf("can only read from tsm file")
+		return 0, fmt.Errorf("can only read from tsm file")
 	}
 	_, err = io.ReadFull(r, b[:1])
 	if err != nil {
-		return fmt.Errorf("init: error reading version: %v", err)
+		return 0, fmt.Errorf("init: error reading version: %v", err)
 	}
-	if b[0] != Version {
-		return fmt.Errorf("init: file is version %b. expected %b", b[0], Version)
+	if b[0] < 1 || b[0] > Version {
+		return 0, fmt.Errorf("init: file is version %b. expected at most %b", b[0], Version)
 	}
 
-	return nil
+	return b[0], nil
+}
+
+// indexEntrySizeForVersion returns the size in bytes of an index entry in a
+// file of the given version.
+func indexEntrySizeForVersion(version byte) int {
+	if version < 2 {
+		return indexEntrySizeV1
+	}
+	return indexEntrySize
 }
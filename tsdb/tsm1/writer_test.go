@@ -546,6 +546,72 @@ func TestTSMWriter_WriteBlock_Multiple(t *testing.T) {
 	}
 }
 
+func TestTSMWriter_BlockStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	f := MustTempFile(dir)
+
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+
+	// Stats are computed from the values by Write and by decoding the block
+	// by WriteBlock.
+	floats := []tsm1.Value{tsm1.NewValue(0, 2.5), tsm1.NewValue(1, -1.0), tsm1.NewValue(2, 4.0)}
+	if err := w.Write([]byte("cpu"), floats); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	integers := []tsm1.Value{tsm1.NewValue(0, int64(3)), tsm1.NewValue(1, int64(7)), tsm1.NewValue(2, int64(-2))}
+	block, err := tsm1.Values(integers).Encode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+	if err := w.WriteBlock([]byte("mem"), 0, 2, block); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	booleans := []tsm1.Value{tsm1.NewValue(0, false), tsm1.NewValue(1, true), tsm1.NewValue(2, true)}
+	if err := w.Write([]byte("up"), booleans); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	r := MustOpenTSMReader(f.Name())
+	defer r.Close()
+
+	stats := make(map[string]tsm1.BlockStats)
+	iter := r.Iterator(nil)
+	for iter.Next() {
+		entries := iter.Entries()
+		if len(entries) != 1 {
+			t.Fatalf("unexpected entries for %s: %v", iter.Key(), entries)
+		}
+		stats[string(iter.Key())] = entries[0].Stats
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("unexpected error iterating: %v", err)
+	}
+
+	s := stats["cpu"]
+	if min, max, sum, first, last := s.FloatStats(); s.Count != 3 || min != -1 || max != 4 || sum != 5.5 || first != 2.5 || last != 4 {
+		t.Fatalf("unexpected float stats: count=%d min=%v max=%v sum=%v first=%v last=%v", s.Count, min, max, sum, first, last)
+	}
+	s = stats["mem"]
+	if min, max, sum, first, last := s.IntegerStats(); s.Count != 3 || min != -2 || max != 7 || sum != 8 || first != 3 || last != -2 {
+		t.Fatalf("unexpected integer stats: count=%d min=%v max=%v sum=%v first=%v last=%v", s.Count, min, max, sum, first, last)
+	}
+	s = stats["up"]
+	if min, max, sum, first, last := s.BooleanStats(); s.Count != 3 || min || !max || sum != 2 || first || !last {
+		t.Fatalf("unexpected boolean stats: count=%d min=%v max=%v sum=%v first=%v last=%v", s.Count, min, max, sum, first, last)
+	}
+}
+
 func TestTSMWriter_WriteBlock_MaxKey(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
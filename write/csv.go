@@ -0,0 +1,458 @@
+package write
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	fcsv "github.com/influxdata/flux/csv"
+	"github.com/influxdata/influxdb/models"
+)
+
+// Columns with special meaning in flux annotated CSV.
+const (
+	resultColumn      = "result"
+	tableColumn       = "table"
+	startColumn       = "_start"
+	stopColumn        = "_stop"
+	timeColumn        = "_time"
+	measurementColumn = "_measurement"
+	fieldColumn       = "_field"
+	valueColumn       = "_value"
+)
+
+// parseAnnotatedCSV converts flux annotated CSV into points.
+//
+// Tables that have _field and _value columns produce one field per row and
+// every other string column becomes a tag. Tables without a _field column
+// are treated as pivoted: group key columns become tags and all remaining
+// columns become fields.
+func parseAnnotatedCSV(data []byte) ([]models.Point, error) {
+	dec := fcsv.NewMultiResultDecoder(fcsv.ResultDecoderConfig{})
+	results, err := dec.Decode(ioutil.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	defer results.Release()
+
+	var points []models.Point
+	for results.More() {
+		res := results.Next()
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				pts, err := ColReaderPoints(cr)
+				if err != nil {
+					return err
+				}
+				points = append(points, pts...)
+				return nil
+			})
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// ColReaderPoints converts a buffer of flux table rows into points.
+// The columns are interpreted as described by parseAnnotatedCSV.
+func ColReaderPoints(cr flux.ColReader) ([]models.Point, error) {
+	cols := cr.Cols()
+	var (
+		timeIdx  = -1
+		measIdx  = -1
+		fieldIdx = -1
+		valueIdx = -1
+	)
+	for j, c := range cols {
+		switch c.Label {
+		case timeColumn:
+			timeIdx = j
+		case measurementColumn:
+			measIdx = j
+		case fieldColumn:
+			fieldIdx = j
+		case valueColumn:
+			valueIdx = j
+		}
+	}
+
+	if measIdx < 0 || cols[measIdx].Type != flux.TString {
+		return nil, fmt.Errorf("table requires a string %s column", measurementColumn)
+	}
+	if timeIdx < 0 || cols[timeIdx].Type != flux.TTime {
+		return nil, fmt.Errorf("table requires a time %s column", timeColumn)
+	}
+	if (fieldIdx < 0) != (valueIdx < 0) {
+		return nil, fmt.Errorf("table must have both %s and %s columns or neither", fieldColumn, valueColumn)
+	}
+	pivoted := fieldIdx < 0
+
+	var tagIdx, fieldIdxs []int
+	for j, c := range cols {
+		switch c.Label {
+		case resultColumn, tableColumn, startColumn, stopColumn, timeColumn, measurementColumn, fieldColumn, valueColumn:
+			continue
+		}
+		if pivoted {
+			if cr.Key().HasCol(c.Label) && c.Type == flux.TString {
+				tagIdx = append(tagIdx, j)
+			} else {
+				fieldIdxs = append(fieldIdxs, j)
+			}
+		} else if c.Type == flux.TString {
+			tagIdx = append(tagIdx, j)
+		}
+	}
+	if !pivoted {
+		fieldIdxs = []int{valueIdx}
+	}
+
+	points := make([]models.Point, 0, cr.Len())
+	for i := 0; i < cr.Len(); i++ {
+		times := cr.Times(timeIdx)
+		if times.IsNull(i) {
+			return nil, fmt.Errorf("row %d: %s must not be null", i, timeColumn)
+		}
+		measurements := cr.Strings(measIdx)
+		if measurements.IsNull(i) {
+			return nil, fmt.Errorf("row %d: %s must not be null", i, measurementColumn)
+		}
+
+		tags := make(map[string]string, len(tagIdx))
+		for _, j := range tagIdx {
+			if s := cr.Strings(j); !s.IsNull(i) && s.ValueString(i) != "" {
+				tags[cols[j].Label] = s.ValueString(i)
+			}
+		}
+
+		fields := make(models.Fields, len(fieldIdxs))
+		for _, j := range fieldIdxs {
+			name := cols[j].Label
+			if !pivoted {
+				keys := cr.Strings(fieldIdx)
+				if keys.IsNull(i) {
+					return nil, fmt.Errorf("row %d: %s must not be null", i, fieldColumn)
+				}
+				name = keys.ValueString(i)
+			}
+			if v, ok := colValue(cr, cols[j].Type, j, i); ok {
+				fields[name] = v
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		pt, err := models.NewPoint(measurements.ValueString(i), models.NewTags(tags), fields, time.Unix(0, times.Value(i)).UTC())
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", i, err)
+		}
+		points = append(points, pt)
+	}
+	return points, nil
+}
+
+// colValue returns the field value at row i of column j. It returns false
+// when the value is null or cannot be stored as a field.
+func colValue(cr flux.ColReader, typ flux.ColType, j, i int) (interface{}, bool) {
+	switch typ {
+	case flux.TFloat:
+		if a := cr.Floats(j); !a.IsNull(i) {
+			return a.Value(i), true
+		}
+	case flux.TInt:
+		if a := cr.Ints(j); !a.IsNull(i) {
+			return a.Value(i), true
+		}
+	case flux.TUInt:
+		if a := cr.UInts(j); !a.IsNull(i) {
+			return a.Value(i), true
+		}
+	case flux.TBool:
+		if a := cr.Bools(j); !a.IsNull(i) {
+			return a.Value(i), true
+		}
+	case flux.TString:
+		if a := cr.Strings(j); !a.IsNull(i) {
+			return a.ValueString(i), true
+		}
+	}
+	return nil, false
+}
+
+// CSVFieldType is the type a plain CSV column is converted to when used as a field.
+type CSVFieldType string
+
+// Field types supported by CSVMapping.
+const (
+	CSVFloat    CSVFieldType = "float"
+	CSVInteger  CSVFieldType = "int"
+	CSVUnsigned CSVFieldType = "uint"
+	CSVBoolean  CSVFieldType = "bool"
+	CSVString   CSVFieldType = "string"
+)
+
+// CSVTimeFormat describes how the time column of plain CSV is encoded.
+type CSVTimeFormat string
+
+// Time formats supported by CSVMapping.
+const (
+	// CSVTimeRFC3339 parses times as RFC3339 with optional nanoseconds.
+	CSVTimeRFC3339 CSVTimeFormat = "rfc3339"
+	// CSVTimeUnix parses times as integer unix timestamps in the write precision.
+	CSVTimeUnix CSVTimeFormat = "unix"
+)
+
+// CSVField is a plain CSV column written as a field.
+type CSVField struct {
+	Column string
+	Type   CSVFieldType
+}
+
+// CSVMapping names the columns of plain CSV that hold the measurement, tags, fields and time.
+// Columns that are not named are ignored. The first row of the CSV must be a header.
+type CSVMapping struct {
+	// Measurement is a fixed measurement name used when MeasurementColumn is empty.
+	Measurement string
+	// MeasurementColumn is the column holding the measurement name.
+	MeasurementColumn string
+	Tags              []string
+	Fields            []CSVField
+	// TimeColumn is the column holding the timestamp; points get the current time when empty.
+	TimeColumn string
+	TimeFormat CSVTimeFormat
+}
+
+// ParseCSVMapping parses a mapping spec of comma separated key=value pairs:
+//
+//	measurement=<name>            fixed measurement name
+//	measurement-column=<column>   column holding the measurement name
+//	tag=<column>                  column written as a tag; may be repeated
+//	field=<column>[:<type>]       column written as a field of type float (default), int, uint, bool or string; may be repeated
+//	time=<column>[:<format>]      column holding the time as rfc3339 (default) or unix
+func ParseCSVMapping(spec string) (*CSVMapping, error) {
+	m := &CSVMapping{TimeFormat: CSVTimeRFC3339}
+	for _, kv := range strings.Split(spec, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid csv mapping %q; expected key=value", kv)
+		}
+		key, value := parts[0], parts[1]
+		switch key {
+		case "measurement":
+			m.Measurement = value
+		case "measurement-column":
+			m.MeasurementColumn = value
+		case "tag":
+			m.Tags = append(m.Tags, value)
+		case "field":
+			f := CSVField{Column: value, Type: CSVFloat}
+			if i := strings.LastIndexByte(value, ':'); i >= 0 {
+				f.Column, f.Type = value[:i], CSVFieldType(value[i+1:])
+			}
+			switch f.Type {
+			case CSVFloat, CSVInteger, CSVUnsigned, CSVBoolean, CSVString:
+			default:
+				return nil, fmt.Errorf("invalid csv field type %q for column %q", f.Type, f.Column)
+			}
+			m.Fields = append(m.Fields, f)
+		case "time":
+			m.TimeColumn = value
+			if i := strings.LastIndexByte(value, ':'); i >= 0 {
+				m.TimeColumn, m.TimeFormat = value[:i], CSVTimeFormat(value[i+1:])
+			}
+			switch m.TimeFormat {
+			case CSVTimeRFC3339, CSVTimeUnix:
+			default:
+				return nil, fmt.Errorf("invalid csv time format %q", m.TimeFormat)
+			}
+		default:
+			return nil, fmt.Errorf("unknown csv mapping key %q", key)
+		}
+	}
+
+	if m.Measurement == "" && m.MeasurementColumn == "" {
+		return nil, fmt.Errorf("csv mapping requires a measurement or measurement-column")
+	}
+	if len(m.Fields) == 0 {
+		return nil, fmt.Errorf("csv mapping requires at least one field")
+	}
+	return m, nil
+}
+
+func (m *CSVMapping) parse(data []byte, opts ParseOptions) ([]models.Point, error) {
+	lr := &lineReader{data: data}
+	r := csv.NewReader(lr)
+	r.ReuseRecord = true
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(header))
+	for j, name := range header {
+		index[strings.TrimSpace(name)] = j
+	}
+	lookup := func(name string) (int, error) {
+		j, ok := index[name]
+		if !ok {
+			return 0, fmt.Errorf("csv header has no column %q", name)
+		}
+		return j, nil
+	}
+
+	measIdx, timeIdx := -1, -1
+	if m.MeasurementColumn != "" {
+		if measIdx, err = lookup(m.MeasurementColumn); err != nil {
+			return nil, err
+		}
+	}
+	if m.TimeColumn != "" {
+		if timeIdx, err = lookup(m.TimeColumn); err != nil {
+			return nil, err
+		}
+	}
+	tagIdx := make([]int, len(m.Tags))
+	for k, name := range m.Tags {
+		if tagIdx[k], err = lookup(name); err != nil {
+			return nil, err
+		}
+	}
+	fieldIdx := make([]int, len(m.Fields))
+	for k, f := range m.Fields {
+		if fieldIdx[k], err = lookup(f.Column); err != nil {
+			return nil, err
+		}
+	}
+
+	var points []models.Point
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		line := lr.line
+
+		value := func(j int) string {
+			if j >= len(record) {
+				return ""
+			}
+			return record[j]
+		}
+
+		name := m.Measurement
+		if measIdx >= 0 {
+			name = value(measIdx)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("line %d: missing measurement", line)
+		}
+
+		tags := make(map[string]string, len(tagIdx))
+		for k, j := range tagIdx {
+			if v := value(j); v != "" {
+				tags[m.Tags[k]] = v
+			}
+		}
+
+		fields := make(models.Fields, len(fieldIdx))
+		for k, j := range fieldIdx {
+			v := value(j)
+			if v == "" {
+				continue
+			}
+			fv, err := m.Fields[k].Type.parse(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: column %q: %v", line, m.Fields[k].Column, err)
+			}
+			fields[m.Fields[k].Column] = fv
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		ts := opts.Now
+		if timeIdx >= 0 {
+			if ts, err = m.TimeFormat.parse(value(timeIdx), opts.Precision); err != nil {
+				return nil, fmt.Errorf("line %d: column %q: %v", line, m.TimeColumn, err)
+			}
+		}
+
+		pt, err := models.NewPoint(name, models.NewTags(tags), fields, ts)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		points = append(points, pt)
+	}
+	return points, nil
+}
+
+// lineReader hands data to a csv.Reader at most one line per Read so that
+// line holds the number of the line the last record ended on.
+type lineReader struct {
+	data []byte
+	line int
+	mid  bool // the last Read stopped in the middle of a line
+}
+
+func (r *lineReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	if i := bytes.IndexByte(r.data, '\n'); i >= 0 && i+1 < len(p) {
+		p = p[:i+1]
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	if !r.mid {
+		r.line++
+	}
+	r.mid = p[n-1] != '\n'
+	return n, nil
+}
+
+func (t CSVFieldType) parse(s string) (interface{}, error) {
+	switch t {
+	case CSVInteger:
+		return strconv.ParseInt(s, 10, 64)
+	case CSVUnsigned:
+		return strconv.ParseUint(s, 10, 64)
+	case CSVBoolean:
+		return strconv.ParseBool(s)
+	case CSVString:
+		return s, nil
+	default:
+		return strconv.ParseFloat(s, 64)
+	}
+}
+
+func (f CSVTimeFormat) parse(s, precision string) (time.Time, error) {
+	if f == CSVTimeUnix {
+		ts, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return timeFromPrecision(ts, precision), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,121 @@
+package write
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Format is an encoding of points that can be ingested by a write.
+type Format string
+
+const (
+	// FormatLineProtocol is the influx line protocol.
+	FormatLineProtocol Format = "lp"
+	// FormatAnnotatedCSV is the flux annotated CSV returned by /api/v2/query.
+	FormatAnnotatedCSV Format = "annotated-csv"
+	// FormatCSV is plain CSV that requires a CSVMapping to describe its columns.
+	FormatCSV Format = "csv"
+	// FormatJSON is a JSON array of points.
+	FormatJSON Format = "json"
+)
+
+// Formats lists all supported write formats.
+var Formats = []Format{
+	FormatLineProtocol,
+	FormatAnnotatedCSV,
+	FormatCSV,
+	FormatJSON,
+}
+
+// ParseFormat returns the Format named by s.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatLineProtocol, FormatAnnotatedCSV, FormatCSV, FormatJSON:
+		return f, nil
+	case "":
+		return FormatLineProtocol, nil
+	}
+	return "", fmt.Errorf("unknown write format %q; valid formats are lp, annotated-csv, csv and json", s)
+}
+
+// FormatFromContentType returns the Format corresponding to a Content-Type header.
+// Plain CSV is never inferred from a content type; text/csv means annotated CSV.
+// Unrecognized content types are treated as line protocol, since many clients
+// send line protocol with a generic content type.
+func FormatFromContentType(contentType string) Format {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return FormatLineProtocol
+	}
+
+	switch mt {
+	case "text/csv", "application/csv":
+		return FormatAnnotatedCSV
+	case "application/json":
+		return FormatJSON
+	}
+	return FormatLineProtocol
+}
+
+// ParseOptions configure how non line protocol data is converted to points.
+type ParseOptions struct {
+	// Precision is the unit of integer timestamps; one of ns, us, ms or s.
+	Precision string
+	// CSVMapping describes the columns of plain CSV. It is required for FormatCSV.
+	CSVMapping *CSVMapping
+	// Now is the time given to points that do not have a timestamp.
+	Now time.Time
+}
+
+// ParsePoints converts data encoded in format f into points.
+func ParsePoints(f Format, data []byte, opts ParseOptions) ([]models.Point, error) {
+	if opts.Precision == "" {
+		opts.Precision = "ns"
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	switch f {
+	case FormatLineProtocol, "":
+		return models.ParsePointsWithPrecision(data, opts.Now, opts.Precision)
+	case FormatAnnotatedCSV:
+		return parseAnnotatedCSV(data)
+	case FormatCSV:
+		if opts.CSVMapping == nil {
+			return nil, fmt.Errorf("csv format requires a column mapping")
+		}
+		return opts.CSVMapping.parse(data, opts)
+	case FormatJSON:
+		return parseJSON(data, opts)
+	}
+	return nil, fmt.Errorf("unknown write format %q", f)
+}
+
+// ToLineProtocol converts data encoded in format f into line protocol.
+func ToLineProtocol(f Format, data []byte, opts ParseOptions) ([]byte, error) {
+	if f == FormatLineProtocol {
+		return data, nil
+	}
+
+	points, err := ParsePoints(f, data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, p := range points {
+		buf.WriteString(p.String())
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// timeFromPrecision converts a unix timestamp in precision units to a time.
+func timeFromPrecision(ts int64, precision string) time.Time {
+	return time.Unix(0, ts*models.GetPrecisionMultiplier(precision)).UTC()
+}
@@ -0,0 +1,248 @@
+package write
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePoints(t *testing.T) {
+	now := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		format  Format
+		mapping string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "line protocol",
+			format: FormatLineProtocol,
+			input:  "m,t1=v1 f1=2 1546398245000000000",
+			want:   []string{"m,t1=v1 f1=2 1546398245000000000"},
+		},
+		{
+			name:   "annotated csv",
+			format: FormatAnnotatedCSV,
+			input: `#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,double,string,string,string
+#group,false,false,true,true,false,false,true,true,true
+#default,_result,,,,,,,,
+,result,table,_start,_stop,_time,_value,_field,_measurement,host
+,,0,2019-01-01T00:00:00Z,2019-01-03T00:00:00Z,2019-01-02T03:04:05Z,1.5,usage,cpu,a
+,,0,2019-01-01T00:00:00Z,2019-01-03T00:00:00Z,2019-01-02T03:04:06Z,2.5,usage,cpu,a
+
+#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,long,string,string,string
+#group,false,false,true,true,false,false,true,true,true
+#default,_result,,,,,,,,
+,result,table,_start,_stop,_time,_value,_field,_measurement,host
+,,1,2019-01-01T00:00:00Z,2019-01-03T00:00:00Z,2019-01-02T03:04:05Z,7,count,cpu,b
+`,
+			want: []string{
+				"cpu,host=a usage=1.5 1546398245000000000",
+				"cpu,host=a usage=2.5 1546398246000000000",
+				"cpu,host=b count=7i 1546398245000000000",
+			},
+		},
+		{
+			name:   "pivoted annotated csv",
+			format: FormatAnnotatedCSV,
+			input: `#datatype,string,long,dateTime:RFC3339,string,string,double,boolean
+#group,false,false,false,true,true,false,false
+#default,_result,,,,,,
+,result,table,_time,_measurement,host,usage,up
+,,0,2019-01-02T03:04:05Z,cpu,a,1.5,true
+`,
+			want: []string{"cpu,host=a up=true,usage=1.5 1546398245000000000"},
+		},
+		{
+			name:   "annotated csv without measurement",
+			format: FormatAnnotatedCSV,
+			input: `#datatype,string,long,dateTime:RFC3339,double,string
+#group,false,false,false,false,true
+#default,_result,,,,
+,result,table,_time,_value,_field
+,,0,2019-01-02T03:04:05Z,1.5,usage
+`,
+			wantErr: true,
+		},
+		{
+			name:    "csv",
+			format:  FormatCSV,
+			mapping: "measurement=cpu,tag=host,field=usage,field=count:int,time=ts:unix",
+			input: `host,usage,count,ts,ignored
+a,1.5,3,1546398245,x
+b,,4,1546398246,y
+`,
+			want: []string{
+				"cpu,host=a count=3i,usage=1.5 1546398245000000000",
+				"cpu,host=b count=4i 1546398246000000000",
+			},
+		},
+		{
+			name:    "csv with measurement column and no time",
+			format:  FormatCSV,
+			mapping: "measurement-column=name,field=ok:bool",
+			input: `name,ok
+disk,true
+`,
+			want: []string{"disk ok=true 1546398245000000000"},
+		},
+		{
+			name:    "csv with invalid value",
+			format:  FormatCSV,
+			mapping: "measurement=cpu,field=usage:int",
+			input: `usage
+1.5
+`,
+			wantErr: true,
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			input: `[
+  {"measurement": "cpu", "tags": {"host": "a"}, "fields": {"usage": 1, "state": "ok"}, "time": "2019-01-02T03:04:05Z"},
+  {"measurement": "cpu", "fields": {"up": false}, "time": 1546398246},
+  {"measurement": "cpu", "fields": {"up": true}}
+]`,
+			want: []string{
+				`cpu,host=a state="ok",usage=1 1546398245000000000`,
+				"cpu up=false 1546398246000000000",
+				"cpu up=true 1546398245000000000",
+			},
+		},
+		{
+			name:    "json without measurement",
+			format:  FormatJSON,
+			input:   `[{"fields": {"usage": 1}}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := ParseOptions{Now: now}
+			if tt.format == FormatJSON || tt.format == FormatCSV {
+				opts.Precision = "s"
+			}
+			if tt.mapping != "" {
+				m, err := ParseCSVMapping(tt.mapping)
+				if err != nil {
+					t.Fatalf("ParseCSVMapping() error = %v", err)
+				}
+				opts.CSVMapping = m
+			}
+
+			points, err := ParsePoints(tt.format, []byte(tt.input), opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := make([]string, len(points))
+			for i, p := range points {
+				got[i] = p.String()
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("ParsePoints() unexpected points -got/+want\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestParseCSVMapping(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    *CSVMapping
+		wantErr string
+	}{
+		{
+			spec: "measurement=cpu, tag=host,tag=region,field=usage,field=up:bool,time=_time",
+			want: &CSVMapping{
+				Measurement: "cpu",
+				Tags:        []string{"host", "region"},
+				Fields:      []CSVField{{Column: "usage", Type: CSVFloat}, {Column: "up", Type: CSVBoolean}},
+				TimeColumn:  "_time",
+				TimeFormat:  CSVTimeRFC3339,
+			},
+		},
+		{
+			spec:    "field=usage",
+			wantErr: "measurement",
+		},
+		{
+			spec:    "measurement=cpu",
+			wantErr: "at least one field",
+		},
+		{
+			spec:    "measurement=cpu,field=usage:decimal",
+			wantErr: "invalid csv field type",
+		},
+		{
+			spec:    "measurement=cpu,field=usage,time=ts:epoch",
+			wantErr: "invalid csv time format",
+		},
+		{
+			spec:    "measurement=cpu,value=usage",
+			wantErr: "unknown csv mapping key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseCSVMapping(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseCSVMapping() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCSVMapping() error = %v", err)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("ParseCSVMapping() -got/+want\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestParsePoints_CSVErrorLine(t *testing.T) {
+	m, err := ParseCSVMapping("measurement=cpu,tag=host,field=usage:int")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := `host,usage
+a,1
+
+"b
+c",2
+d,1.5
+`
+	_, err = ParsePoints(FormatCSV, []byte(input), ParseOptions{CSVMapping: m})
+	if err == nil || !strings.HasPrefix(err.Error(), "line 6:") {
+		t.Fatalf("ParsePoints() error = %v, want error on line 6", err)
+	}
+}
+
+func TestFormatFromContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        Format
+	}{
+		{contentType: "", want: FormatLineProtocol},
+		{contentType: "text/plain; charset=utf-8", want: FormatLineProtocol},
+		{contentType: "text/csv", want: FormatAnnotatedCSV},
+		{contentType: "application/json", want: FormatJSON},
+		{contentType: "application/x-www-form-urlencoded", want: FormatLineProtocol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if got := FormatFromContentType(tt.contentType); got != tt.want {
+				t.Errorf("FormatFromContentType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package write
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// JSONPoint is a single point in the JSON write format.
+//
+// Time is either an RFC3339 string or an integer timestamp in the write precision.
+// As in line protocol without a type suffix, numeric fields are written as floats.
+type JSONPoint struct {
+	Measurement string                 `json:"measurement"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Fields      map[string]interface{} `json:"fields"`
+	Time        json.RawMessage        `json:"time,omitempty"`
+}
+
+// parseJSON converts a JSON array of JSONPoint into points.
+func parseJSON(data []byte, opts ParseOptions) ([]models.Point, error) {
+	var jps []JSONPoint
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&jps); err != nil {
+		return nil, fmt.Errorf("invalid json points: %v", err)
+	}
+
+	points := make([]models.Point, 0, len(jps))
+	for i, jp := range jps {
+		pt, err := jp.point(opts)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %v", i, err)
+		}
+		points = append(points, pt)
+	}
+	return points, nil
+}
+
+func (jp *JSONPoint) point(opts ParseOptions) (models.Point, error) {
+	if jp.Measurement == "" {
+		return nil, fmt.Errorf("missing measurement")
+	}
+
+	fields := make(models.Fields, len(jp.Fields))
+	for k, v := range jp.Fields {
+		fv, err := jsonFieldValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", k, err)
+		}
+		fields[k] = fv
+	}
+
+	ts, err := jp.time(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewPoint(jp.Measurement, models.NewTags(jp.Tags), fields, ts)
+}
+
+func (jp *JSONPoint) time(opts ParseOptions) (time.Time, error) {
+	if len(jp.Time) == 0 || string(jp.Time) == "null" {
+		return opts.Now, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(jp.Time, &s); err == nil {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time: %v", err)
+		}
+		return t, nil
+	}
+
+	var ts int64
+	if err := json.Unmarshal(jp.Time, &ts); err != nil {
+		return time.Time{}, fmt.Errorf("time must be an RFC3339 string or an integer timestamp")
+	}
+	return timeFromPrecision(ts, opts.Precision), nil
+}
+
+func jsonFieldValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Float64()
+	case bool:
+		return v, nil
+	case string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}